@@ -1,8 +1,6 @@
 package commander
 
 import "v2ray.com/core/common/errors"
-import "os"
-import "time"
 import "fmt"
 
 type errPathObjHolder struct{}
@@ -12,16 +10,7 @@ func newError(values ...interface{}) *errors.Error {
 }
 
 func newDebugMsg(msg string) {
-	f, err := os.OpenFile("/tmp/v2ray_debug.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		panic(err)
-	}
-	t := time.Now()
-	ts := t.Format("2006-01-02 15:04:05")
-	defer f.Close()
-	if _, err = f.WriteString(ts + ": " + msg + "\n"); err != nil {
-		panic(err)
-	}
+	newError(msg).AtDebug().WriteToLog()
 }
 
 func StructString(class interface{}) string {
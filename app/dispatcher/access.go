@@ -0,0 +1,83 @@
+// +build !confonly
+
+package dispatcher
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/log"
+)
+
+// accessRecorder records the access message of a session with its traffic and duration, when the session is closed.
+type accessRecorder struct {
+	// uplink and downlink are accessed atomically, and kept first for 64-bit alignment.
+	uplink   int64
+	downlink int64
+	msg      *log.AccessMessage
+	start    time.Time
+	once     sync.Once
+}
+
+func newAccessRecorder(msg *log.AccessMessage) *accessRecorder {
+	return &accessRecorder{
+		msg:   msg,
+		start: time.Now(),
+	}
+}
+
+func (r *accessRecorder) record(reason string) {
+	r.once.Do(func() {
+		msg := *r.msg
+		msg.Status = log.AccessClosed
+		msg.Reason = reason
+		msg.Uplink = atomic.LoadInt64(&r.uplink)
+		msg.Downlink = atomic.LoadInt64(&r.downlink)
+		msg.Duration = time.Since(r.start)
+		log.Record(&msg)
+	})
+}
+
+// accessUplinkWriter counts the traffic from inbound to outbound.
+type accessUplinkWriter struct {
+	recorder *accessRecorder
+	writer   buf.Writer
+}
+
+func (w *accessUplinkWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	atomic.AddInt64(&w.recorder.uplink, int64(mb.Len()))
+	return w.writer.WriteMultiBuffer(mb)
+}
+
+func (w *accessUplinkWriter) Close() error {
+	return common.Close(w.writer)
+}
+
+func (w *accessUplinkWriter) Interrupt() {
+	common.Interrupt(w.writer)
+}
+
+// accessDownlinkWriter counts the traffic from outbound to inbound. The session is closed when the outbound finishes
+// writing.
+type accessDownlinkWriter struct {
+	recorder *accessRecorder
+	writer   buf.Writer
+}
+
+func (w *accessDownlinkWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	atomic.AddInt64(&w.recorder.downlink, int64(mb.Len()))
+	return w.writer.WriteMultiBuffer(mb)
+}
+
+func (w *accessDownlinkWriter) Close() error {
+	w.recorder.record("closed")
+	return common.Close(w.writer)
+}
+
+func (w *accessDownlinkWriter) Interrupt() {
+	w.recorder.record("interrupted")
+	common.Interrupt(w.writer)
+}
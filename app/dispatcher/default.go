@@ -17,6 +17,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/dns"
 	"v2ray.com/core/features/outbound"
 	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
@@ -90,17 +91,18 @@ func (r *cachedReader) Interrupt() {
 
 // DefaultDispatcher is a default implementation of Dispatcher.
 type DefaultDispatcher struct {
-	ohm    outbound.Manager
-	router routing.Router
-	policy policy.Manager
-	stats  stats.Manager
+	ohm     outbound.Manager
+	router  routing.Router
+	policy  policy.Manager
+	stats   stats.Manager
+	fakeDNS dns.FakeDNSEngine
 }
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		d := new(DefaultDispatcher)
-		if err := core.RequireFeatures(ctx, func(om outbound.Manager, router routing.Router, pm policy.Manager, sm stats.Manager) error {
-			return d.Init(config.(*Config), om, router, pm, sm)
+		if err := core.RequireFeatures(ctx, func(om outbound.Manager, router routing.Router, pm policy.Manager, sm stats.Manager, dc dns.Client) error {
+			return d.Init(config.(*Config), om, router, pm, sm, dc)
 		}); err != nil {
 			return nil, err
 		}
@@ -109,11 +111,14 @@ func init() {
 }
 
 // Init initializes DefaultDispatcher.
-func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager, router routing.Router, pm policy.Manager, sm stats.Manager) error {
+func (d *DefaultDispatcher) Init(config *Config, om outbound.Manager, router routing.Router, pm policy.Manager, sm stats.Manager, dc dns.Client) error {
 	d.ohm = om
 	d.router = router
 	d.policy = pm
 	d.stats = sm
+	if e, ok := dc.(dns.FakeDNSEngine); ok && e.IsFakeDNSEnabled() {
+		d.fakeDNS = e
+	}
 	return nil
 }
 
@@ -130,7 +135,7 @@ func (*DefaultDispatcher) Start() error {
 // Close implements common.Closable.
 func (*DefaultDispatcher) Close() error { return nil }
 
-func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *transport.Link) {
+func (d *DefaultDispatcher) getLink(ctx context.Context, quota policy.QuotaCounter) (*transport.Link, *transport.Link) {
 	opt := pipe.OptionsFromContext(ctx)
 	uplinkReader, uplinkWriter := pipe.New(opt...)
 	downlinkReader, downlinkWriter := pipe.New(opt...)
@@ -171,6 +176,57 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 				}
 			}
 		}
+		if p.Stats.UserConnection {
+			name := "user>>>" + user.Email + ">>>connection>>>active"
+			if g, _ := stats.GetOrRegisterGauge(d.stats, name); g != nil {
+				g.Add(1)
+				outboundLink.Writer = &GaugeWriter{
+					Gauge:  g,
+					Writer: outboundLink.Writer,
+				}
+			}
+		}
+	}
+
+	if recorder, ok := d.router.(routing.TrafficRecorder); ok && user != nil {
+		if c := recorder.TrafficCounter(user.Email); c != nil {
+			inboundLink.Writer = &TrafficWriter{
+				Counter: c,
+				Writer:  inboundLink.Writer,
+			}
+			outboundLink.Writer = &TrafficWriter{
+				Counter: c,
+				Writer:  outboundLink.Writer,
+			}
+		}
+	}
+
+	if quota != nil {
+		reject := len(quota.Usage().Quota.ExceededTag) == 0
+		inboundLink.Writer = &QuotaWriter{
+			Counter: quota,
+			Reject:  reject,
+			Writer:  inboundLink.Writer,
+		}
+		outboundLink.Writer = &QuotaWriter{
+			Counter: quota,
+			Reject:  reject,
+			Writer:  outboundLink.Writer,
+		}
+	}
+
+	// The traffic of the session is only collected if the access message of its closing is used, as in JSON format or
+	// by log subscribers.
+	if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil && log.ClosedAccessHandled() {
+		recorder := newAccessRecorder(accessMessage)
+		inboundLink.Writer = &accessUplinkWriter{
+			recorder: recorder,
+			writer:   inboundLink.Writer,
+		}
+		outboundLink.Writer = &accessDownlinkWriter{
+			recorder: recorder,
+			writer:   outboundLink.Writer,
+		}
 	}
 
 	return inboundLink, outboundLink
@@ -190,12 +246,38 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
+	if d.fakeDNS != nil && destination.Address.Family().IsIP() {
+		ip := destination.Address.IP()
+		if domain := d.fakeDNS.GetDomainFromFakeIP(ip); len(domain) > 0 {
+			newError("restored domain ", domain, " from fake IP ", destination.Address).WriteToLog(session.ExportIDToError(ctx))
+			destination.Address = net.DomainAddress(domain)
+		} else if d.fakeDNS.IsFakeIP(ip) {
+			// The IP is recycled or handed out before restart. Forwarding to it reaches nowhere.
+			return nil, newError("no domain is mapped to fake IP ", destination.Address)
+		}
+	}
 	ob := &session.Outbound{
 		Target: destination,
 	}
 	ctx = session.ContextWithOutbound(ctx, ob)
 
-	inbound, outbound := d.getLink(ctx)
+	var quota policy.QuotaCounter
+	var detour string
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil {
+		quota = policy.QuotaForUser(d.policy, inbound.User)
+	}
+	if quota != nil {
+		if usage := quota.Usage(); usage.Exceeded() {
+			if len(usage.Quota.ExceededTag) == 0 {
+				return nil, newError("quota of user ", usage.Email, " is exceeded")
+			}
+			detour = usage.Quota.ExceededTag
+			// Traffic to the detour is not counted.
+			quota = nil
+		}
+	}
+
+	inbound, outbound := d.getLink(ctx, quota)
 	content := session.ContentFromContext(ctx)
 	if content == nil {
 		content = new(session.Content)
@@ -203,7 +285,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 	}
 	sniffingRequest := content.SniffingRequest
 	if destination.Network != net.Network_TCP || !sniffingRequest.Enabled {
-		go d.routedDispatch(ctx, outbound, destination)
+		go d.routedDispatch(ctx, outbound, destination, detour)
 	} else {
 		go func() {
 			cReader := &cachedReader{
@@ -213,6 +295,9 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 			result, err := sniffer(ctx, cReader)
 			if err == nil {
 				content.Protocol = result.Protocol()
+				if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+					accessMessage.Domain = result.Domain()
+				}
 			}
 			if err == nil && shouldOverride(result, sniffingRequest.OverrideDestinationForProtocol) {
 				domain := result.Domain()
@@ -220,7 +305,7 @@ func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destin
 				destination.Address = net.ParseAddress(domain)
 				ob.Target = destination
 			}
-			d.routedDispatch(ctx, outbound, destination)
+			d.routedDispatch(ctx, outbound, destination, detour)
 		}()
 	}
 	return inbound, nil
@@ -256,12 +341,25 @@ func sniffer(ctx context.Context, cReader *cachedReader) (SniffResult, error) {
 	}
 }
 
-func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.Link, destination net.Destination) {
+// routedDispatch dispatches the link to the outbound handler of detour, or the one picked by the router if detour is
+// empty.
+func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.Link, destination net.Destination, detour string) {
 	var handler outbound.Handler
 
-	skipRoutePick := false
+	if len(detour) > 0 {
+		handler = d.ohm.GetHandler(detour)
+		if handler == nil {
+			newError("non existing tag for exceeded quota: ", detour).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			common.Close(link.Writer)
+			common.Interrupt(link.Reader)
+			return
+		}
+		newError("taking detour [", detour, "] for [", destination, "] as quota is exceeded").WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	skipRoutePick := handler != nil
 	if content := session.ContentFromContext(ctx); content != nil {
-		skipRoutePick = content.SkipRoutePick
+		skipRoutePick = skipRoutePick || content.SkipRoutePick
 	}
 
 	if d.router != nil && !skipRoutePick {
@@ -292,6 +390,10 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		if tag := handler.Tag(); tag != "" {
 			accessMessage.Detour = tag
 		}
+		accessMessage.SessionID = uint32(session.IDFromContext(ctx))
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			accessMessage.InboundTag = inbound.Tag
+		}
 		log.Record(accessMessage)
 	}
 
@@ -3,11 +3,44 @@
 package dispatcher
 
 import (
+	"sync"
+	"time"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/routing"
 	"v2ray.com/core/features/stats"
 )
 
+// SizeStatReader counts the size of traffic read from the reader.
+type SizeStatReader struct {
+	Counter stats.Counter
+	Reader  buf.Reader
+}
+
+func (r *SizeStatReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+// ReadMultiBufferTimeout implements buf.TimeoutReader, if the underlying reader is a buf.TimeoutReader.
+func (r *SizeStatReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	reader, ok := r.Reader.(buf.TimeoutReader)
+	if !ok {
+		return nil, buf.ErrNotTimeoutReader
+	}
+	mb, err := reader.ReadMultiBufferTimeout(timeout)
+	r.Counter.Add(int64(mb.Len()))
+	return mb, err
+}
+
+func (r *SizeStatReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+// SizeStatWriter counts the size of traffic written into the writer.
 type SizeStatWriter struct {
 	Counter stats.Counter
 	Writer  buf.Writer
@@ -25,3 +58,73 @@ func (w *SizeStatWriter) Close() error {
 func (w *SizeStatWriter) Interrupt() {
 	common.Interrupt(w.Writer)
 }
+
+// GaugeWriter decreases the gauge by 1 when it is closed or interrupted, for a connection that is counted in the gauge.
+type GaugeWriter struct {
+	Gauge  stats.Gauge
+	Writer buf.Writer
+	once   sync.Once
+}
+
+func (w *GaugeWriter) done() {
+	w.once.Do(func() {
+		w.Gauge.Add(-1)
+	})
+}
+
+func (w *GaugeWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *GaugeWriter) Close() error {
+	w.done()
+	return common.Close(w.Writer)
+}
+
+func (w *GaugeWriter) Interrupt() {
+	w.done()
+	common.Interrupt(w.Writer)
+}
+
+// QuotaWriter counts traffic into the quota of a user. It fails writes once the quota is exceeded, if Reject is set.
+type QuotaWriter struct {
+	Counter policy.QuotaCounter
+	Reject  bool
+	Writer  buf.Writer
+}
+
+func (w *QuotaWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.Reject && w.Counter.Usage().Exceeded() {
+		buf.ReleaseMulti(mb)
+		return newError("quota exceeded")
+	}
+	w.Counter.Add(int64(mb.Len()))
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *QuotaWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *QuotaWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// TrafficWriter counts traffic of a user for routing.
+type TrafficWriter struct {
+	Counter routing.TrafficCounter
+	Writer  buf.Writer
+}
+
+func (w *TrafficWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	w.Counter.Add(int64(mb.Len()))
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *TrafficWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *TrafficWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
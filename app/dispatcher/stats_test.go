@@ -1,11 +1,13 @@
 package dispatcher_test
 
 import (
+	"bytes"
 	"testing"
 
 	. "v2ray.com/core/app/dispatcher"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
+	"v2ray.com/core/features/policy"
 )
 
 type TestCounter int64
@@ -42,3 +44,71 @@ func TestStatsWriter(t *testing.T) {
 		t.Fatal("unexpected counter value. want 7, but got ", c.Value())
 	}
 }
+
+func TestStatsReader(t *testing.T) {
+	var c TestCounter
+	reader := &SizeStatReader{
+		Counter: &c,
+		Reader:  buf.NewReader(bytes.NewReader([]byte("abcdefg"))),
+	}
+
+	mb, err := reader.ReadMultiBuffer()
+	common.Must(err)
+	buf.ReleaseMulti(mb)
+
+	if c.Value() != 7 {
+		t.Fatal("unexpected counter value. want 7, but got ", c.Value())
+	}
+}
+
+type testQuota struct {
+	used int64
+}
+
+func (q *testQuota) Add(n int64) {
+	q.used += n
+}
+
+func (q *testQuota) Usage() policy.QuotaUsage {
+	return policy.QuotaUsage{
+		Quota: policy.Quota{Bytes: 5},
+		Used:  q.used,
+	}
+}
+
+func TestQuotaWriter(t *testing.T) {
+	var q testQuota
+	writer := &QuotaWriter{
+		Counter: &q,
+		Reject:  true,
+		Writer:  buf.Discard,
+	}
+
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcdefg"))))
+	if q.used != 7 {
+		t.Fatal("unexpected usage. want 7, but got ", q.used)
+	}
+	if err := writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("h"))); err == nil {
+		t.Error("expect error when quota is exceeded")
+	}
+}
+
+type testTrafficCounter int64
+
+func (c *testTrafficCounter) Add(n int64) {
+	*c += testTrafficCounter(n)
+}
+
+func TestTrafficWriter(t *testing.T) {
+	var c testTrafficCounter
+	writer := &TrafficWriter{
+		Counter: &c,
+		Writer:  buf.Discard,
+	}
+
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("abcd"))))
+	common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("efg"))))
+	if c != 7 {
+		t.Fatal("unexpected traffic. want 7, but got ", c)
+	}
+}
@@ -0,0 +1,310 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/net"
+)
+
+// defaultCacheTTL is the TTL of answers from clients that don't report TTL, such as the local resolver.
+const defaultCacheTTL = time.Minute
+
+// cachedClient is implemented by Clients that keep answers with TTL.
+type cachedClient interface {
+	// expireTime returns the time when cached answers of the domain for queries in the context expire.
+	expireTime(ctx context.Context, domain string, option IPOption) (time.Time, bool)
+}
+
+type cacheBypassKey struct{}
+
+// contextWithCacheBypassed returns a context for queries that skip answers cached in Clients, such as prefetching.
+func contextWithCacheBypassed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, cacheBypassKey{}, true)
+}
+
+func isCacheBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(cacheBypassKey{}).(bool)
+	return bypassed
+}
+
+type cachedIPs struct {
+	ips    []net.IP
+	expire time.Time
+	ttl    time.Duration
+	hits   int32
+}
+
+func (c *cachedIPs) valid(now time.Time) bool {
+	return c != nil && c.expire.After(now)
+}
+
+// dueForPrefetch returns true if the answer is hit enough times, and is in the last tenth of its TTL.
+func (c *cachedIPs) dueForPrefetch(now time.Time, minHits int32) bool {
+	return minHits > 0 && atomic.LoadInt32(&c.hits) >= minHits && c.expire.Sub(now) < c.ttl/10
+}
+
+type ipCacheEntry struct {
+	ip4 *cachedIPs
+	ip6 *cachedIPs
+}
+
+// answers returns answers of the families enabled in option, or false if any of them is missing.
+func (e ipCacheEntry) answers(option IPOption) ([]*cachedIPs, bool) {
+	var answers []*cachedIPs
+	if option.IPv4Enable {
+		if e.ip4 == nil {
+			return nil, false
+		}
+		answers = append(answers, e.ip4)
+	}
+	if option.IPv6Enable {
+		if e.ip6 == nil {
+			return nil, false
+		}
+		answers = append(answers, e.ip6)
+	}
+	return answers, len(answers) > 0
+}
+
+// ipCache keeps answers of IP lookups as long as their TTL. It is shared by all users of the DNS server,
+// such as the router, which queries it instead of resolving domains again.
+type ipCache struct {
+	sync.RWMutex
+	entries map[string]ipCacheEntry
+	// maxStale is how long answers are kept after they expire, for serving stale.
+	maxStale time.Duration
+	// prefetchHits is the number of hits for answers to be prefetched. Zero disables prefetching.
+	prefetchHits int32
+	dirty        bool
+}
+
+func newIPCache() *ipCache {
+	return &ipCache{
+		entries: make(map[string]ipCacheEntry),
+	}
+}
+
+// update records IPs of the domain that are queried with option. Addresses of each family enabled in option are replaced.
+func (c *ipCache) update(domain string, option IPOption, ips []net.IP, expire time.Time) {
+	var ip4, ip6 []net.IP
+	for _, ip := range ips {
+		if len(ip) == net.IPv4len {
+			ip4 = append(ip4, ip)
+		} else {
+			ip6 = append(ip6, ip)
+		}
+	}
+	ttl := time.Until(expire)
+
+	c.Lock()
+	defer c.Unlock()
+
+	entry := c.entries[domain]
+	if option.IPv4Enable {
+		entry.ip4 = &cachedIPs{ips: ip4, expire: expire, ttl: ttl}
+	}
+	if option.IPv6Enable {
+		entry.ip6 = &cachedIPs{ips: ip6, expire: expire, ttl: ttl}
+	}
+	c.entries[domain] = entry
+	c.dirty = true
+}
+
+// lookup returns unexpired IPs of the domain. It returns false if there is no such IP.
+func (c *ipCache) lookup(domain string, option IPOption) ([]net.IP, bool) {
+	c.RLock()
+	entry, found := c.entries[domain]
+	c.RUnlock()
+
+	if !found {
+		return nil, false
+	}
+
+	now := time.Now()
+	var ips []net.IP
+	if option.IPv4Enable && entry.ip4.valid(now) {
+		ips = append(ips, entry.ip4.ips...)
+	}
+	if option.IPv6Enable && entry.ip6.valid(now) {
+		ips = append(ips, entry.ip6.ips...)
+	}
+	return ips, len(ips) > 0
+}
+
+// get returns IPs of the domain, only if unexpired answers of all families enabled in option are cached.
+// It counts a hit of the answers, and reports whether they are due for prefetching.
+func (c *ipCache) get(domain string, option IPOption) (ips []net.IP, prefetch bool, found bool) {
+	c.RLock()
+	entry := c.entries[domain]
+	c.RUnlock()
+
+	answers, found := entry.answers(option)
+	if !found {
+		return nil, false, false
+	}
+
+	now := time.Now()
+	for _, a := range answers {
+		if !a.valid(now) {
+			return nil, false, false
+		}
+	}
+	for _, a := range answers {
+		atomic.AddInt32(&a.hits, 1)
+		prefetch = prefetch || a.dueForPrefetch(now, c.prefetchHits)
+		ips = append(ips, a.ips...)
+	}
+	return ips, prefetch, len(ips) > 0
+}
+
+// getStale returns IPs of the domain in answers that expired no longer than maxStale ago.
+func (c *ipCache) getStale(domain string, option IPOption) ([]net.IP, bool) {
+	c.RLock()
+	entry := c.entries[domain]
+	c.RUnlock()
+
+	answers, found := entry.answers(option)
+	if !found {
+		return nil, false
+	}
+
+	staleLimit := time.Now().Add(-c.maxStale)
+	var ips []net.IP
+	for _, a := range answers {
+		if !a.valid(staleLimit) {
+			return nil, false
+		}
+		ips = append(ips, a.ips...)
+	}
+	return ips, len(ips) > 0
+}
+
+// Cleanup removes entries that expired longer than maxStale ago.
+func (c *ipCache) Cleanup() error {
+	c.Lock()
+	defer c.Unlock()
+
+	staleLimit := time.Now().Add(-c.maxStale)
+	for domain, entry := range c.entries {
+		if !entry.ip4.valid(staleLimit) {
+			entry.ip4 = nil
+		}
+		if !entry.ip6.valid(staleLimit) {
+			entry.ip6 = nil
+		}
+		if entry.ip4 == nil && entry.ip6 == nil {
+			delete(c.entries, domain)
+			c.dirty = true
+		} else {
+			c.entries[domain] = entry
+		}
+	}
+	return nil
+}
+
+func (c *cachedIPs) toSnapshot() *CacheSnapshot_Answer {
+	if c == nil {
+		return nil
+	}
+	answer := &CacheSnapshot_Answer{
+		Expire: c.expire.Unix(),
+		Ttl:    uint32(c.ttl / time.Second),
+	}
+	for _, ip := range c.ips {
+		answer.Ip = append(answer.Ip, []byte(ip))
+	}
+	return answer
+}
+
+func cachedIPsFromSnapshot(answer *CacheSnapshot_Answer, staleLimit time.Time) *cachedIPs {
+	if answer == nil {
+		return nil
+	}
+	a := &cachedIPs{
+		expire: time.Unix(answer.Expire, 0),
+		ttl:    time.Duration(answer.Ttl) * time.Second,
+	}
+	if !a.valid(staleLimit) {
+		return nil
+	}
+	for _, ip := range answer.Ip {
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return nil
+		}
+		a.ips = append(a.ips, net.IP(ip))
+	}
+	return a
+}
+
+// save writes the cache into the file, if it is changed since last save.
+func (c *ipCache) save(path string) error {
+	c.Lock()
+	if !c.dirty {
+		c.Unlock()
+		return nil
+	}
+	snapshot := new(CacheSnapshot)
+	for domain, entry := range c.entries {
+		snapshot.Entry = append(snapshot.Entry, &CacheSnapshot_Entry{
+			Domain: domain,
+			Ipv4:   entry.ip4.toSnapshot(),
+			Ipv6:   entry.ip6.toSnapshot(),
+		})
+	}
+	c.dirty = false
+	c.Unlock()
+
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return newError("failed to encode DNS cache").Base(err)
+	}
+	// Write to a temporary file first, so that the cache file is never left half-written.
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return newError("failed to write DNS cache to ", tmp).Base(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return newError("failed to write DNS cache to ", path).Base(err)
+	}
+	return nil
+}
+
+// load reads entries from the file into the cache. It does nothing if the file doesn't exist.
+func (c *ipCache) load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return newError("failed to read DNS cache from ", path).Base(err)
+	}
+
+	snapshot := new(CacheSnapshot)
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		return newError("failed to decode DNS cache from ", path).Base(err)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	staleLimit := time.Now().Add(-c.maxStale)
+	for _, e := range snapshot.Entry {
+		entry := ipCacheEntry{
+			ip4: cachedIPsFromSnapshot(e.Ipv4, staleLimit),
+			ip6: cachedIPsFromSnapshot(e.Ipv6, staleLimit),
+		}
+		if entry.ip4 != nil || entry.ip6 != nil {
+			c.entries[e.Domain] = entry
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package dns
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// CacheSnapshot is the file format of persistent DNS cache.
+type CacheSnapshot struct {
+	Entry                []*CacheSnapshot_Entry `protobuf:"bytes,1,rep,name=entry,proto3" json:"entry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *CacheSnapshot) Reset()         { *m = CacheSnapshot{} }
+func (m *CacheSnapshot) String() string { return proto.CompactTextString(m) }
+func (*CacheSnapshot) ProtoMessage()    {}
+func (*CacheSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_94c9fe96938f0ea5, []int{0}
+}
+
+func (m *CacheSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheSnapshot.Unmarshal(m, b)
+}
+func (m *CacheSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheSnapshot.Marshal(b, m, deterministic)
+}
+func (m *CacheSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheSnapshot.Merge(m, src)
+}
+func (m *CacheSnapshot) XXX_Size() int {
+	return xxx_messageInfo_CacheSnapshot.Size(m)
+}
+func (m *CacheSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheSnapshot proto.InternalMessageInfo
+
+func (m *CacheSnapshot) GetEntry() []*CacheSnapshot_Entry {
+	if m != nil {
+		return m.Entry
+	}
+	return nil
+}
+
+type CacheSnapshot_Answer struct {
+	Ip [][]byte `protobuf:"bytes,1,rep,name=ip,proto3" json:"ip,omitempty"`
+	// Unix time in seconds when the answer expires.
+	Expire int64 `protobuf:"varint,2,opt,name=expire,proto3" json:"expire,omitempty"`
+	// Original TTL of the answer in seconds.
+	Ttl                  uint32   `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheSnapshot_Answer) Reset()         { *m = CacheSnapshot_Answer{} }
+func (m *CacheSnapshot_Answer) String() string { return proto.CompactTextString(m) }
+func (*CacheSnapshot_Answer) ProtoMessage()    {}
+func (*CacheSnapshot_Answer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_94c9fe96938f0ea5, []int{0, 0}
+}
+
+func (m *CacheSnapshot_Answer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheSnapshot_Answer.Unmarshal(m, b)
+}
+func (m *CacheSnapshot_Answer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheSnapshot_Answer.Marshal(b, m, deterministic)
+}
+func (m *CacheSnapshot_Answer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheSnapshot_Answer.Merge(m, src)
+}
+func (m *CacheSnapshot_Answer) XXX_Size() int {
+	return xxx_messageInfo_CacheSnapshot_Answer.Size(m)
+}
+func (m *CacheSnapshot_Answer) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheSnapshot_Answer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheSnapshot_Answer proto.InternalMessageInfo
+
+func (m *CacheSnapshot_Answer) GetIp() [][]byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *CacheSnapshot_Answer) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
+func (m *CacheSnapshot_Answer) GetTtl() uint32 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type CacheSnapshot_Entry struct {
+	Domain               string                `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	Ipv4                 *CacheSnapshot_Answer `protobuf:"bytes,2,opt,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Ipv6                 *CacheSnapshot_Answer `protobuf:"bytes,3,opt,name=ipv6,proto3" json:"ipv6,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *CacheSnapshot_Entry) Reset()         { *m = CacheSnapshot_Entry{} }
+func (m *CacheSnapshot_Entry) String() string { return proto.CompactTextString(m) }
+func (*CacheSnapshot_Entry) ProtoMessage()    {}
+func (*CacheSnapshot_Entry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_94c9fe96938f0ea5, []int{0, 1}
+}
+
+func (m *CacheSnapshot_Entry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheSnapshot_Entry.Unmarshal(m, b)
+}
+func (m *CacheSnapshot_Entry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheSnapshot_Entry.Marshal(b, m, deterministic)
+}
+func (m *CacheSnapshot_Entry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheSnapshot_Entry.Merge(m, src)
+}
+func (m *CacheSnapshot_Entry) XXX_Size() int {
+	return xxx_messageInfo_CacheSnapshot_Entry.Size(m)
+}
+func (m *CacheSnapshot_Entry) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheSnapshot_Entry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheSnapshot_Entry proto.InternalMessageInfo
+
+func (m *CacheSnapshot_Entry) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *CacheSnapshot_Entry) GetIpv4() *CacheSnapshot_Answer {
+	if m != nil {
+		return m.Ipv4
+	}
+	return nil
+}
+
+func (m *CacheSnapshot_Entry) GetIpv6() *CacheSnapshot_Answer {
+	if m != nil {
+		return m.Ipv6
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*CacheSnapshot)(nil), "v2ray.core.app.dns.CacheSnapshot")
+	proto.RegisterType((*CacheSnapshot_Answer)(nil), "v2ray.core.app.dns.CacheSnapshot.Answer")
+	proto.RegisterType((*CacheSnapshot_Entry)(nil), "v2ray.core.app.dns.CacheSnapshot.Entry")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/dns/cache.proto", fileDescriptor_94c9fe96938f0ea5)
+}
+
+var fileDescriptor_94c9fe96938f0ea5 = []byte{
+	// 264 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0xd1, 0xc1, 0x4a, 0x33, 0x31,
+	0x10, 0x07, 0x70, 0xb2, 0xf9, 0xba, 0xf0, 0x4d, 0xad, 0x48, 0x0e, 0x65, 0xe9, 0x69, 0xe9, 0xc5,
+	0x3d, 0x65, 0x61, 0x2d, 0x3d, 0xe9, 0xa1, 0xad, 0xde, 0x25, 0x82, 0x07, 0x6f, 0x71, 0x37, 0xd0,
+	0x80, 0x9b, 0x0c, 0x49, 0xa8, 0xee, 0xb3, 0xf8, 0x06, 0x9e, 0x7c, 0x44, 0x49, 0xba, 0x1e, 0x44,
+	0x41, 0xf0, 0x96, 0x21, 0xff, 0xdf, 0x9f, 0x81, 0x81, 0xe5, 0xa1, 0x71, 0x72, 0xe0, 0xad, 0xed,
+	0xeb, 0xd6, 0x3a, 0x55, 0x4b, 0xc4, 0xba, 0x33, 0xbe, 0x6e, 0x65, 0xbb, 0x57, 0x1c, 0x9d, 0x0d,
+	0x96, 0xb1, 0xcf, 0x8c, 0x53, 0x5c, 0x22, 0xf2, 0xce, 0xf8, 0xe5, 0x7b, 0x06, 0xb3, 0x5d, 0xcc,
+	0xdc, 0x19, 0x89, 0x7e, 0x6f, 0x03, 0xbb, 0x82, 0x89, 0x32, 0xc1, 0x0d, 0x05, 0x29, 0x69, 0x35,
+	0x6d, 0xce, 0xf9, 0x77, 0xc5, 0xbf, 0x08, 0x7e, 0x13, 0xe3, 0xe2, 0xa8, 0x16, 0x5b, 0xc8, 0x37,
+	0xc6, 0x3f, 0x2b, 0xc7, 0x4e, 0x21, 0xd3, 0x98, 0x5a, 0x4e, 0x44, 0xa6, 0x91, 0xcd, 0x21, 0x57,
+	0x2f, 0xa8, 0x9d, 0x2a, 0xb2, 0x92, 0x54, 0x54, 0x8c, 0x13, 0x3b, 0x03, 0x1a, 0xc2, 0x53, 0x41,
+	0x4b, 0x52, 0xcd, 0x44, 0x7c, 0x2e, 0x5e, 0x09, 0x4c, 0x52, 0x69, 0x34, 0x9d, 0xed, 0xa5, 0x36,
+	0x05, 0x29, 0x49, 0xf5, 0x5f, 0x8c, 0x13, 0xbb, 0x84, 0x7f, 0x1a, 0x0f, 0xab, 0xd4, 0x34, 0x6d,
+	0xaa, 0xdf, 0x77, 0x3c, 0xee, 0x24, 0x92, 0x1a, 0xf5, 0xba, 0xa0, 0x7f, 0xd0, 0xeb, 0xed, 0x0a,
+	0xe6, 0xad, 0xed, 0x7f, 0x40, 0xb7, 0xe4, 0x81, 0x76, 0xc6, 0xbf, 0x65, 0xec, 0xbe, 0x11, 0x72,
+	0xe0, 0xbb, 0xf8, 0xb7, 0x41, 0xe4, 0xd7, 0xc6, 0x3f, 0xe6, 0xe9, 0x06, 0x17, 0x1f, 0x03, 0x00,
+	0x6e, 0x10, 0xdf, 0x72, 0xa9, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,205 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	dns_feature "v2ray.com/core/features/dns"
+)
+
+func TestIPCache(t *testing.T) {
+	c := newIPCache()
+	now := time.Now()
+
+	c.update("v2ray.com", IPOption{IPv4Enable: true, IPv6Enable: true}, []net.IP{{1, 1, 1, 1}, net.ParseIP("2001:db8::1")}, now.Add(time.Hour))
+	c.update("google.com", IPOption{IPv4Enable: true}, []net.IP{{8, 8, 8, 8}}, now.Add(-time.Second))
+
+	ips, found := c.lookup("v2ray.com", IPOption{IPv4Enable: true, IPv6Enable: true})
+	if !found {
+		t.Fatal("v2ray.com not found")
+	}
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}, net.ParseIP("2001:db8::1")}); r != "" {
+		t.Error(r)
+	}
+
+	ips, found = c.lookup("v2ray.com", IPOption{IPv4Enable: true})
+	if !found {
+		t.Fatal("v2ray.com not found")
+	}
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+
+	if ips, found := c.lookup("google.com", IPOption{IPv4Enable: true, IPv6Enable: true}); found {
+		t.Error("expect expired google.com, but got ", ips)
+	}
+
+	// IPv6 answers are replaced by an empty answer, while IPv4 answers are kept.
+	c.update("v2ray.com", IPOption{IPv6Enable: true}, nil, now.Add(time.Hour))
+	ips, _ = c.lookup("v2ray.com", IPOption{IPv4Enable: true, IPv6Enable: true})
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+
+	common.Must(c.Cleanup())
+	if _, found := c.entries["google.com"]; found {
+		t.Error("expired google.com not removed")
+	}
+	if _, found := c.entries["v2ray.com"]; !found {
+		t.Error("v2ray.com removed")
+	}
+}
+
+func TestIPCachePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dns.cache")
+	now := time.Now()
+
+	c := newIPCache()
+	c.maxStale = time.Hour
+	common.Must(c.load(path))
+	c.update("v2ray.com", IPOption{IPv4Enable: true, IPv6Enable: true}, []net.IP{{1, 1, 1, 1}, net.ParseIP("2001:db8::1")}, now.Add(time.Hour))
+	c.update("google.com", IPOption{IPv4Enable: true}, []net.IP{{8, 8, 8, 8}}, now.Add(-time.Minute))
+	c.update("v2fly.org", IPOption{IPv4Enable: true}, []net.IP{{9, 9, 9, 9}}, now.Add(-time.Hour*2))
+	common.Must(c.save(path))
+
+	c = newIPCache()
+	c.maxStale = time.Hour
+	common.Must(c.load(path))
+
+	ips, _, found := c.get("v2ray.com", IPOption{IPv4Enable: true, IPv6Enable: true})
+	if !found {
+		t.Fatal("v2ray.com not found")
+	}
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}, net.ParseIP("2001:db8::1")}); r != "" {
+		t.Error(r)
+	}
+	if _, _, found := c.get("google.com", IPOption{IPv4Enable: true}); found {
+		t.Error("expect expired google.com not found")
+	}
+	if ips, found := c.getStale("google.com", IPOption{IPv4Enable: true}); !found || !ips[0].Equal(net.IP{8, 8, 8, 8}) {
+		t.Error("expect stale google.com, but got ", ips)
+	}
+	if _, found := c.entries["v2fly.org"]; found {
+		t.Error("expect v2fly.org expired beyond max stale not loaded")
+	}
+}
+
+type testClient struct {
+	sync.Mutex
+	ips      []net.IP
+	err      error
+	delay    time.Duration
+	queries  int
+	bypassed int
+	subnets  []string
+}
+
+func (*testClient) Name() string {
+	return "test"
+}
+
+func (c *testClient) QueryIP(ctx context.Context, domain string, option IPOption) ([]net.IP, error) {
+	if c.delay > 0 {
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	c.queries++
+	if ip := clientIPFromContext(ctx); ip != nil {
+		subnet, _ := sourceSubnet(ip)
+		c.subnets = append(c.subnets, subnet.String())
+	}
+	if isCacheBypassed(ctx) {
+		c.bypassed++
+	}
+	return c.ips, c.err
+}
+
+func newTestCachedServer(client Client) *Server {
+	hosts, err := NewStaticHosts(nil, nil)
+	common.Must(err)
+
+	s := &Server{
+		hosts:       hosts,
+		clients:     []Client{client},
+		cache:       newIPCache(),
+		serveStale:  true,
+		prefetching: make(map[string]bool),
+	}
+	s.cache.maxStale = time.Hour
+	s.cache.prefetchHits = 2
+	return s
+}
+
+func TestServeStale(t *testing.T) {
+	client := &testClient{err: context.DeadlineExceeded}
+	s := newTestCachedServer(client)
+	option := IPOption{IPv4Enable: true}
+	s.cache.update("v2ray.com", option, []net.IP{{1, 1, 1, 1}}, time.Now().Add(-time.Minute))
+
+	ips, err := s.lookupIPInternal("v2ray.com", option)
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+
+	// Stale answers are not served if the domain doesn't exist.
+	client.err = dns_feature.RCodeError(dnsmessage.RCodeNameError)
+	if ips, err := s.lookupIPInternal("v2ray.com", option); err == nil {
+		t.Error("expect error for non-existent domain, but got ", ips)
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	client := &testClient{ips: []net.IP{{2, 2, 2, 2}}}
+	s := newTestCachedServer(client)
+	option := IPOption{IPv4Enable: true}
+
+	s.cache.update("v2ray.com", option, []net.IP{{1, 1, 1, 1}}, time.Now().Add(time.Second*5))
+	s.cache.entries["v2ray.com"].ip4.ttl = time.Minute
+
+	for i := 0; i < 2; i++ {
+		ips, err := s.lookupIPInternal("v2ray.com", option)
+		common.Must(err)
+		if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+			t.Error(r)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		client.Lock()
+		bypassed := client.bypassed
+		client.Unlock()
+		if bypassed > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	client.Lock()
+	if client.queries != 1 || client.bypassed != 1 {
+		t.Error("expect 1 prefetch query, but got ", client.queries, " queries and ", client.bypassed, " prefetch")
+	}
+	client.Unlock()
+
+	ips, _ := s.cache.lookup("v2ray.com", option)
+	if r := cmp.Diff(ips, []net.IP{{2, 2, 2, 2}}); r != "" {
+		t.Error(r)
+	}
+}
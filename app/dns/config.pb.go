@@ -50,13 +50,49 @@ func (DomainMatchingType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_ed5695198e3def8f, []int{0}
 }
 
+type ClientSubnet_Type int32
+
+const (
+	// No subnet is sent.
+	ClientSubnet_Off ClientSubnet_Type = 0
+	// The subnet ip/prefix is sent.
+	ClientSubnet_Fixed ClientSubnet_Type = 1
+	// The subnet of the client that sends the query, such as the source of
+	// the DNS inbound or outbound, is sent. It is /24 for IPv4 and /56 for
+	// IPv6. Answers are cached per subnet.
+	ClientSubnet_Source ClientSubnet_Type = 2
+)
+
+var ClientSubnet_Type_name = map[int32]string{
+	0: "Off",
+	1: "Fixed",
+	2: "Source",
+}
+
+var ClientSubnet_Type_value = map[string]int32{
+	"Off":    0,
+	"Fixed":  1,
+	"Source": 2,
+}
+
+func (x ClientSubnet_Type) String() string {
+	return proto.EnumName(ClientSubnet_Type_name, int32(x))
+}
+
+func (ClientSubnet_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ed5695198e3def8f, []int{1, 0}
+}
+
 type NameServer struct {
-	Address              *net.Endpoint                `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	PrioritizedDomain    []*NameServer_PriorityDomain `protobuf:"bytes,2,rep,name=prioritized_domain,json=prioritizedDomain,proto3" json:"prioritized_domain,omitempty"`
-	Geoip                []*router.GeoIP              `protobuf:"bytes,3,rep,name=geoip,proto3" json:"geoip,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
-	XXX_unrecognized     []byte                       `json:"-"`
-	XXX_sizecache        int32                        `json:"-"`
+	Address           *net.Endpoint                `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	PrioritizedDomain []*NameServer_PriorityDomain `protobuf:"bytes,2,rep,name=prioritized_domain,json=prioritizedDomain,proto3" json:"prioritized_domain,omitempty"`
+	Geoip             []*router.GeoIP              `protobuf:"bytes,3,rep,name=geoip,proto3" json:"geoip,omitempty"`
+	// EDNS Client Subnet of queries to this name server. Config.client_ip is
+	// used if it is not set.
+	ClientSubnet         *ClientSubnet `protobuf:"bytes,4,opt,name=client_subnet,json=clientSubnet,proto3" json:"client_subnet,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *NameServer) Reset()         { *m = NameServer{} }
@@ -105,6 +141,13 @@ func (m *NameServer) GetGeoip() []*router.GeoIP {
 	return nil
 }
 
+func (m *NameServer) GetClientSubnet() *ClientSubnet {
+	if m != nil {
+		return m.ClientSubnet
+	}
+	return nil
+}
+
 type NameServer_PriorityDomain struct {
 	Type                 DomainMatchingType `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
 	Domain               string             `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
@@ -152,6 +195,62 @@ func (m *NameServer_PriorityDomain) GetDomain() string {
 	return ""
 }
 
+type ClientSubnet struct {
+	Type ClientSubnet_Type `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.ClientSubnet_Type" json:"type,omitempty"`
+	Ip   []byte            `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	// Default 24 for IPv4 and 56 for IPv6.
+	Prefix               uint32   `protobuf:"varint,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientSubnet) Reset()         { *m = ClientSubnet{} }
+func (m *ClientSubnet) String() string { return proto.CompactTextString(m) }
+func (*ClientSubnet) ProtoMessage()    {}
+func (*ClientSubnet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed5695198e3def8f, []int{1}
+}
+
+func (m *ClientSubnet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientSubnet.Unmarshal(m, b)
+}
+func (m *ClientSubnet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientSubnet.Marshal(b, m, deterministic)
+}
+func (m *ClientSubnet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientSubnet.Merge(m, src)
+}
+func (m *ClientSubnet) XXX_Size() int {
+	return xxx_messageInfo_ClientSubnet.Size(m)
+}
+func (m *ClientSubnet) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientSubnet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientSubnet proto.InternalMessageInfo
+
+func (m *ClientSubnet) GetType() ClientSubnet_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ClientSubnet_Off
+}
+
+func (m *ClientSubnet) GetIp() []byte {
+	if m != nil {
+		return m.Ip
+	}
+	return nil
+}
+
+func (m *ClientSubnet) GetPrefix() uint32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
 type Config struct {
 	// Nameservers used by this DNS. Only traditional UDP servers are support at the moment.
 	// A special value 'localhost' as a domain address can be set to use DNS on local system.
@@ -162,20 +261,32 @@ type Config struct {
 	// Deprecated. Use static_hosts.
 	Hosts map[string]*net.IPOrDomain `protobuf:"bytes,2,rep,name=Hosts,proto3" json:"Hosts,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // Deprecated: Do not use.
 	// Client IP for EDNS client subnet. Must be 4 bytes (IPv4) or 16 bytes (IPv6).
+	// It is the default of NameServer.client_subnet.
 	ClientIp    []byte                `protobuf:"bytes,3,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
 	StaticHosts []*Config_HostMapping `protobuf:"bytes,4,rep,name=static_hosts,json=staticHosts,proto3" json:"static_hosts,omitempty"`
 	// Tag is the inbound tag of DNS client.
-	Tag                  string   `protobuf:"bytes,6,opt,name=tag,proto3" json:"tag,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Tag string `protobuf:"bytes,6,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Fake DNS hands out IPs in the given pools for domains queried through DNS
+	// outbound, and restores the domains when connections to these IPs are
+	// dispatched.
+	FakeDns *FakeDns `protobuf:"bytes,7,opt,name=fake_dns,json=fakeDns,proto3" json:"fake_dns,omitempty"`
+	// Cache of IP answers shared by all name servers.
+	Cache *CacheConfig `protobuf:"bytes,8,opt,name=cache,proto3" json:"cache,omitempty"`
+	// Query name servers of a group at the same time, and take the first valid
+	// answer, instead of querying them one after another.
+	ConcurrentQuery bool `protobuf:"varint,9,opt,name=concurrent_query,json=concurrentQuery,proto3" json:"concurrent_query,omitempty"`
+	// Rules to select name servers by domain. The first matched rule is used.
+	DomainRule           []*DomainRule `protobuf:"bytes,10,rep,name=domain_rule,json=domainRule,proto3" json:"domain_rule,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed5695198e3def8f, []int{1}
+	return fileDescriptor_ed5695198e3def8f, []int{2}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -240,6 +351,34 @@ func (m *Config) GetTag() string {
 	return ""
 }
 
+func (m *Config) GetFakeDns() *FakeDns {
+	if m != nil {
+		return m.FakeDns
+	}
+	return nil
+}
+
+func (m *Config) GetCache() *CacheConfig {
+	if m != nil {
+		return m.Cache
+	}
+	return nil
+}
+
+func (m *Config) GetConcurrentQuery() bool {
+	if m != nil {
+		return m.ConcurrentQuery
+	}
+	return false
+}
+
+func (m *Config) GetDomainRule() []*DomainRule {
+	if m != nil {
+		return m.DomainRule
+	}
+	return nil
+}
+
 type Config_HostMapping struct {
 	Type   DomainMatchingType `protobuf:"varint,1,opt,name=type,proto3,enum=v2ray.core.app.dns.DomainMatchingType" json:"type,omitempty"`
 	Domain string             `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
@@ -256,7 +395,7 @@ func (m *Config_HostMapping) Reset()         { *m = Config_HostMapping{} }
 func (m *Config_HostMapping) String() string { return proto.CompactTextString(m) }
 func (*Config_HostMapping) ProtoMessage()    {}
 func (*Config_HostMapping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ed5695198e3def8f, []int{1, 1}
+	return fileDescriptor_ed5695198e3def8f, []int{2, 1}
 }
 
 func (m *Config_HostMapping) XXX_Unmarshal(b []byte) error {
@@ -305,13 +444,214 @@ func (m *Config_HostMapping) GetProxiedDomain() string {
 	return ""
 }
 
+type DomainRule struct {
+	Domain []*NameServer_PriorityDomain `protobuf:"bytes,1,rep,name=domain,proto3" json:"domain,omitempty"`
+	// Indices of name servers in Config.name_server, queried in this order.
+	NameServer []uint32 `protobuf:"varint,2,rep,packed,name=name_server,json=nameServer,proto3" json:"name_server,omitempty"`
+	// Query the other name servers if none of the selected ones answers.
+	Fallback             bool     `protobuf:"varint,3,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DomainRule) Reset()         { *m = DomainRule{} }
+func (m *DomainRule) String() string { return proto.CompactTextString(m) }
+func (*DomainRule) ProtoMessage()    {}
+func (*DomainRule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed5695198e3def8f, []int{3}
+}
+
+func (m *DomainRule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DomainRule.Unmarshal(m, b)
+}
+func (m *DomainRule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DomainRule.Marshal(b, m, deterministic)
+}
+func (m *DomainRule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DomainRule.Merge(m, src)
+}
+func (m *DomainRule) XXX_Size() int {
+	return xxx_messageInfo_DomainRule.Size(m)
+}
+func (m *DomainRule) XXX_DiscardUnknown() {
+	xxx_messageInfo_DomainRule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DomainRule proto.InternalMessageInfo
+
+func (m *DomainRule) GetDomain() []*NameServer_PriorityDomain {
+	if m != nil {
+		return m.Domain
+	}
+	return nil
+}
+
+func (m *DomainRule) GetNameServer() []uint32 {
+	if m != nil {
+		return m.NameServer
+	}
+	return nil
+}
+
+func (m *DomainRule) GetFallback() bool {
+	if m != nil {
+		return m.Fallback
+	}
+	return false
+}
+
+type FakeDns struct {
+	// IPv4 CIDR of fake IPs, such as "198.18.0.0/15".
+	IpPool string `protobuf:"bytes,1,opt,name=ip_pool,json=ipPool,proto3" json:"ip_pool,omitempty"`
+	// IPv6 CIDR of fake IPs, such as "fc00::/18". AAAA queries get empty answers
+	// if it is not set.
+	Ipv6Pool string `protobuf:"bytes,2,opt,name=ipv6_pool,json=ipv6Pool,proto3" json:"ipv6_pool,omitempty"`
+	// Maximum number of domains kept in each pool. Least recently used domains
+	// are recycled when the pool is full. Default 65535.
+	LruSize              uint32   `protobuf:"varint,3,opt,name=lru_size,json=lruSize,proto3" json:"lru_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FakeDns) Reset()         { *m = FakeDns{} }
+func (m *FakeDns) String() string { return proto.CompactTextString(m) }
+func (*FakeDns) ProtoMessage()    {}
+func (*FakeDns) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed5695198e3def8f, []int{4}
+}
+
+func (m *FakeDns) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FakeDns.Unmarshal(m, b)
+}
+func (m *FakeDns) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FakeDns.Marshal(b, m, deterministic)
+}
+func (m *FakeDns) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FakeDns.Merge(m, src)
+}
+func (m *FakeDns) XXX_Size() int {
+	return xxx_messageInfo_FakeDns.Size(m)
+}
+func (m *FakeDns) XXX_DiscardUnknown() {
+	xxx_messageInfo_FakeDns.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FakeDns proto.InternalMessageInfo
+
+func (m *FakeDns) GetIpPool() string {
+	if m != nil {
+		return m.IpPool
+	}
+	return ""
+}
+
+func (m *FakeDns) GetIpv6Pool() string {
+	if m != nil {
+		return m.Ipv6Pool
+	}
+	return ""
+}
+
+func (m *FakeDns) GetLruSize() uint32 {
+	if m != nil {
+		return m.LruSize
+	}
+	return 0
+}
+
+type CacheConfig struct {
+	// File to keep the cache across restarts. The cache is only kept in memory
+	// if it is empty.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Serve expired answers when name servers fail to answer (RFC 8767).
+	ServeStale bool `protobuf:"varint,2,opt,name=serve_stale,json=serveStale,proto3" json:"serve_stale,omitempty"`
+	// Seconds that answers are kept after they expire, for serving stale.
+	// Default 86400.
+	MaxStale uint32 `protobuf:"varint,3,opt,name=max_stale,json=maxStale,proto3" json:"max_stale,omitempty"`
+	// Refresh popular answers in background shortly before they expire.
+	Prefetch bool `protobuf:"varint,4,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	// Answers are popular if they are hit at least this many times in their
+	// TTL. Default 2.
+	PrefetchHits         uint32   `protobuf:"varint,5,opt,name=prefetch_hits,json=prefetchHits,proto3" json:"prefetch_hits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CacheConfig) Reset()         { *m = CacheConfig{} }
+func (m *CacheConfig) String() string { return proto.CompactTextString(m) }
+func (*CacheConfig) ProtoMessage()    {}
+func (*CacheConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ed5695198e3def8f, []int{5}
+}
+
+func (m *CacheConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CacheConfig.Unmarshal(m, b)
+}
+func (m *CacheConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CacheConfig.Marshal(b, m, deterministic)
+}
+func (m *CacheConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CacheConfig.Merge(m, src)
+}
+func (m *CacheConfig) XXX_Size() int {
+	return xxx_messageInfo_CacheConfig.Size(m)
+}
+func (m *CacheConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_CacheConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CacheConfig proto.InternalMessageInfo
+
+func (m *CacheConfig) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *CacheConfig) GetServeStale() bool {
+	if m != nil {
+		return m.ServeStale
+	}
+	return false
+}
+
+func (m *CacheConfig) GetMaxStale() uint32 {
+	if m != nil {
+		return m.MaxStale
+	}
+	return 0
+}
+
+func (m *CacheConfig) GetPrefetch() bool {
+	if m != nil {
+		return m.Prefetch
+	}
+	return false
+}
+
+func (m *CacheConfig) GetPrefetchHits() uint32 {
+	if m != nil {
+		return m.PrefetchHits
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.app.dns.DomainMatchingType", DomainMatchingType_name, DomainMatchingType_value)
+	proto.RegisterEnum("v2ray.core.app.dns.ClientSubnet_Type", ClientSubnet_Type_name, ClientSubnet_Type_value)
 	proto.RegisterType((*NameServer)(nil), "v2ray.core.app.dns.NameServer")
 	proto.RegisterType((*NameServer_PriorityDomain)(nil), "v2ray.core.app.dns.NameServer.PriorityDomain")
+	proto.RegisterType((*ClientSubnet)(nil), "v2ray.core.app.dns.ClientSubnet")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.dns.Config")
 	proto.RegisterMapType((map[string]*net.IPOrDomain)(nil), "v2ray.core.app.dns.Config.HostsEntry")
 	proto.RegisterType((*Config_HostMapping)(nil), "v2ray.core.app.dns.Config.HostMapping")
+	proto.RegisterType((*DomainRule)(nil), "v2ray.core.app.dns.DomainRule")
+	proto.RegisterType((*FakeDns)(nil), "v2ray.core.app.dns.FakeDns")
+	proto.RegisterType((*CacheConfig)(nil), "v2ray.core.app.dns.CacheConfig")
 }
 
 func init() {
@@ -319,42 +659,64 @@ func init() {
 }
 
 var fileDescriptor_ed5695198e3def8f = []byte{
-	// 583 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x54, 0xdf, 0x6e, 0xd3, 0x3e,
-	0x18, 0xfd, 0x25, 0xfd, 0xb3, 0xf5, 0xcb, 0x56, 0xf5, 0xe7, 0x8b, 0x29, 0x2a, 0x08, 0xc6, 0xd0,
-	0x46, 0x05, 0xc2, 0x91, 0x02, 0x12, 0xb0, 0x9b, 0x89, 0x6d, 0x05, 0x2a, 0x34, 0xa8, 0x3c, 0xc4,
-	0x05, 0x20, 0x55, 0x5e, 0x62, 0x32, 0x8b, 0xc6, 0xb6, 0x1c, 0x77, 0x2c, 0x3c, 0x09, 0xcf, 0xc0,
-	0x4b, 0x70, 0xc1, 0x8b, 0xa1, 0xda, 0x19, 0xed, 0xb6, 0x0e, 0xb8, 0xe1, 0xce, 0x7f, 0xce, 0xf9,
-	0xce, 0xf9, 0x8e, 0xbf, 0x04, 0x6e, 0x9f, 0xc4, 0x9a, 0x96, 0x38, 0x91, 0x79, 0x94, 0x48, 0xcd,
-	0x22, 0xaa, 0x54, 0x94, 0x8a, 0x22, 0x4a, 0xa4, 0xf8, 0xc8, 0x33, 0xac, 0xb4, 0x34, 0x12, 0xa1,
-	0x33, 0x90, 0x66, 0x98, 0x2a, 0x85, 0x53, 0x51, 0x74, 0xef, 0x5c, 0x20, 0x26, 0x32, 0xcf, 0xa5,
-	0x88, 0x04, 0x33, 0x11, 0x4d, 0x53, 0xcd, 0x8a, 0xc2, 0x91, 0xbb, 0xf7, 0xae, 0x06, 0xa6, 0xac,
-	0x30, 0x5c, 0x50, 0xc3, 0xa5, 0xa8, 0xc0, 0x5b, 0x0b, 0xec, 0x68, 0x39, 0x31, 0x4c, 0x9f, 0x73,
-	0xb4, 0xf1, 0xdd, 0x07, 0x78, 0x45, 0x73, 0x76, 0xc8, 0xf4, 0x09, 0xd3, 0xe8, 0x09, 0x2c, 0x55,
-	0xa2, 0xa1, 0xb7, 0xee, 0xf5, 0x82, 0xf8, 0x26, 0x9e, 0xb3, 0xec, 0x14, 0xb1, 0x60, 0x06, 0xf7,
-	0x45, 0xaa, 0x24, 0x17, 0x86, 0x9c, 0xe1, 0xd1, 0x07, 0x40, 0x4a, 0x73, 0xa9, 0xb9, 0xe1, 0x5f,
-	0x58, 0x3a, 0x4a, 0x65, 0x4e, 0xb9, 0x08, 0xfd, 0xf5, 0x5a, 0x2f, 0x88, 0xef, 0xe3, 0xcb, 0x8d,
-	0xe3, 0x99, 0x2c, 0x1e, 0x3a, 0x62, 0xb9, 0x6f, 0x49, 0xe4, 0xff, 0xb9, 0x42, 0xee, 0x08, 0xc5,
-	0xd0, 0xc8, 0x98, 0xe4, 0x2a, 0xac, 0xd9, 0x82, 0xd7, 0x2f, 0x16, 0x74, 0xbd, 0xe1, 0xe7, 0x4c,
-	0x0e, 0x86, 0xc4, 0x41, 0xbb, 0x29, 0xb4, 0xcf, 0x17, 0x46, 0xdb, 0x50, 0x37, 0xa5, 0x62, 0xb6,
-	0xb7, 0x76, 0xbc, 0xb5, 0xc8, 0x95, 0x43, 0x1e, 0x50, 0x93, 0x1c, 0x73, 0x91, 0xbd, 0x29, 0x15,
-	0x23, 0x96, 0x83, 0xd6, 0xa0, 0xf9, 0xab, 0x27, 0xaf, 0xd7, 0x22, 0xd5, 0x6e, 0xe3, 0x47, 0x1d,
-	0x9a, 0x7b, 0x36, 0x52, 0xd4, 0x87, 0x60, 0xd6, 0xd4, 0x34, 0xc1, 0xda, 0x5f, 0x24, 0xb8, 0xeb,
-	0x87, 0x1e, 0x99, 0xe7, 0xa1, 0x1d, 0x08, 0x04, 0xcd, 0xd9, 0xa8, 0xb0, 0xfb, 0xb0, 0x61, 0xcb,
-	0xdc, 0xf8, 0x7d, 0x84, 0x04, 0xc4, 0xec, 0x15, 0x77, 0xa0, 0xf1, 0x42, 0x16, 0xa6, 0xa8, 0xd2,
-	0xdf, 0x5c, 0x44, 0x75, 0x96, 0xb1, 0xc5, 0xf5, 0x85, 0xd1, 0xa5, 0xf5, 0xe1, 0x78, 0xe8, 0x1a,
-	0xb4, 0x92, 0x31, 0x67, 0xc2, 0x8c, 0x6c, 0xe2, 0x5e, 0x6f, 0x85, 0x2c, 0xbb, 0x83, 0x81, 0x42,
-	0x03, 0x58, 0x29, 0x0c, 0x35, 0x3c, 0x19, 0x1d, 0x5b, 0x91, 0xba, 0x15, 0xd9, 0xfa, 0x83, 0xc8,
-	0x01, 0x55, 0x8a, 0x8b, 0x8c, 0x04, 0x8e, 0xeb, 0x74, 0x3a, 0x50, 0x33, 0x34, 0x0b, 0x9b, 0x36,
-	0xd0, 0xe9, 0xb2, 0xfb, 0x1e, 0x60, 0x66, 0x69, 0x7a, 0xff, 0x89, 0x95, 0xf6, 0xb9, 0x5a, 0x64,
-	0xba, 0x44, 0x8f, 0xa0, 0x71, 0x42, 0xc7, 0x13, 0x66, 0x1f, 0x21, 0x88, 0x6f, 0x5d, 0x11, 0xee,
-	0x60, 0xf8, 0x5a, 0x57, 0xc3, 0xe4, 0xf0, 0xdb, 0xfe, 0x63, 0xaf, 0xfb, 0xd5, 0x83, 0x60, 0xce,
-	0xcb, 0xbf, 0x18, 0x07, 0xd4, 0x06, 0xbf, 0x9a, 0xd2, 0x15, 0xe2, 0x73, 0x85, 0x36, 0xa1, 0xad,
-	0xb4, 0x3c, 0xe5, 0xb3, 0x4f, 0xa2, 0x6e, 0xf1, 0xab, 0xd5, 0xa9, 0x13, 0xb8, 0xdb, 0x07, 0x74,
-	0x59, 0x0a, 0x2d, 0x43, 0xfd, 0xd9, 0x64, 0x3c, 0xee, 0xfc, 0x87, 0x56, 0xa1, 0x75, 0x38, 0x39,
-	0x72, 0x15, 0x3a, 0x1e, 0x0a, 0x60, 0xe9, 0x25, 0x2b, 0x3f, 0x4b, 0x9d, 0x76, 0x7c, 0xd4, 0x82,
-	0x06, 0x61, 0x19, 0x3b, 0xed, 0xd4, 0x76, 0x1f, 0xc2, 0x5a, 0x22, 0xf3, 0x05, 0x8d, 0x0c, 0xbd,
-	0x77, 0xb5, 0x54, 0x14, 0xdf, 0x7c, 0xf4, 0x36, 0x26, 0xb4, 0xc4, 0x7b, 0xd3, 0xbb, 0xa7, 0x4a,
-	0xe1, 0x7d, 0x51, 0x1c, 0x35, 0xed, 0xbf, 0xe0, 0xc1, 0xcf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x4c,
-	0x2a, 0x66, 0x8a, 0xc4, 0x04, 0x00, 0x00,
+	// 933 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdf, 0x8f, 0x1b, 0x35,
+	0x10, 0xee, 0xe6, 0xe7, 0x66, 0x72, 0x39, 0x82, 0x1f, 0xca, 0x92, 0x22, 0x7a, 0xa4, 0xea, 0x11,
+	0x40, 0x6c, 0xa4, 0x00, 0x85, 0xf6, 0xa5, 0xa2, 0xbd, 0x1c, 0x3d, 0xa1, 0xd2, 0xe0, 0x20, 0x1e,
+	0x0a, 0xd2, 0xca, 0xb7, 0xeb, 0x24, 0xd6, 0x6d, 0x6c, 0xe3, 0xf5, 0x1e, 0xc9, 0xfd, 0x01, 0x3c,
+	0xf3, 0x08, 0x2f, 0x88, 0x67, 0xfe, 0x4a, 0x64, 0x7b, 0xf3, 0xab, 0x97, 0xa3, 0x08, 0x89, 0xb7,
+	0xf1, 0xec, 0xcc, 0x7c, 0x9f, 0x67, 0xbe, 0xf1, 0xc2, 0xbd, 0xcb, 0x81, 0x22, 0xcb, 0x30, 0x16,
+	0xf3, 0x7e, 0x2c, 0x14, 0xed, 0x13, 0x29, 0xfb, 0x09, 0xcf, 0xfa, 0xb1, 0xe0, 0x13, 0x36, 0x0d,
+	0xa5, 0x12, 0x5a, 0x20, 0xb4, 0x0a, 0x52, 0x34, 0x24, 0x52, 0x86, 0x09, 0xcf, 0x3a, 0xef, 0xbf,
+	0x92, 0x18, 0x8b, 0xf9, 0x5c, 0xf0, 0x3e, 0xa7, 0xba, 0x4f, 0x92, 0x44, 0xd1, 0x2c, 0x73, 0xc9,
+	0x9d, 0x8f, 0x6e, 0x0e, 0x4c, 0x68, 0xa6, 0x19, 0x27, 0x9a, 0x09, 0x5e, 0x04, 0x1f, 0xef, 0xa1,
+	0xa3, 0x44, 0xae, 0xa9, 0xda, 0x61, 0xd4, 0xfd, 0xa5, 0x0c, 0xf0, 0x0d, 0x99, 0xd3, 0x31, 0x55,
+	0x97, 0x54, 0xa1, 0x87, 0x50, 0x2f, 0x40, 0x03, 0xef, 0xc8, 0xeb, 0x35, 0x07, 0x77, 0xc3, 0x2d,
+	0xca, 0x0e, 0x31, 0xe4, 0x54, 0x87, 0x43, 0x9e, 0x48, 0xc1, 0xb8, 0xc6, 0xab, 0x78, 0xf4, 0x23,
+	0x20, 0xa9, 0x98, 0x50, 0x4c, 0xb3, 0x2b, 0x9a, 0x44, 0x89, 0x98, 0x13, 0xc6, 0x83, 0xd2, 0x51,
+	0xb9, 0xd7, 0x1c, 0x7c, 0x1c, 0x5e, 0xbf, 0x78, 0xb8, 0x81, 0x0d, 0x47, 0x2e, 0x71, 0x79, 0x62,
+	0x93, 0xf0, 0x9b, 0x5b, 0x85, 0x9c, 0x0b, 0x0d, 0xa0, 0x3a, 0xa5, 0x82, 0xc9, 0xa0, 0x6c, 0x0b,
+	0xbe, 0xf3, 0x6a, 0x41, 0x77, 0xb7, 0xf0, 0x2b, 0x2a, 0xce, 0x46, 0xd8, 0x85, 0xa2, 0x21, 0xb4,
+	0xe2, 0x94, 0x51, 0xae, 0xa3, 0x2c, 0x3f, 0xe7, 0x54, 0x07, 0x15, 0x7b, 0xa5, 0xa3, 0x7d, 0x64,
+	0x9e, 0xda, 0xc0, 0xb1, 0x8d, 0xc3, 0x07, 0xf1, 0xd6, 0xa9, 0x93, 0xc0, 0xe1, 0x2e, 0x3f, 0xf4,
+	0x08, 0x2a, 0x7a, 0x29, 0xa9, 0x6d, 0xd1, 0xe1, 0xe0, 0x78, 0x5f, 0x3d, 0x17, 0xf9, 0x9c, 0xe8,
+	0x78, 0xc6, 0xf8, 0xf4, 0xbb, 0xa5, 0xa4, 0xd8, 0xe6, 0xa0, 0xdb, 0x50, 0x5b, 0xb7, 0xc6, 0xeb,
+	0x35, 0x70, 0x71, 0xea, 0xfe, 0xee, 0xc1, 0xc1, 0x36, 0x09, 0xf4, 0x70, 0x07, 0xe4, 0xfe, 0xeb,
+	0x48, 0x87, 0x5b, 0x18, 0x87, 0x50, 0x62, 0xd2, 0xd6, 0x3f, 0xc0, 0x25, 0x26, 0x0d, 0xa6, 0x54,
+	0x74, 0xc2, 0x16, 0x41, 0xf9, 0xc8, 0xeb, 0xb5, 0x70, 0x71, 0xea, 0x1e, 0x43, 0xc5, 0x64, 0xa1,
+	0x3a, 0x94, 0x5f, 0x4c, 0x26, 0xed, 0x5b, 0xa8, 0x01, 0xd5, 0x53, 0xb6, 0xa0, 0x49, 0xdb, 0x43,
+	0x00, 0xb5, 0xb1, 0xc8, 0x55, 0x4c, 0xdb, 0xa5, 0xee, 0x1f, 0x35, 0xa8, 0x3d, 0xb5, 0xaa, 0x41,
+	0x43, 0x68, 0x6e, 0xe6, 0x66, 0x44, 0x52, 0xfe, 0x17, 0x22, 0x79, 0x52, 0x0a, 0x3c, 0xbc, 0x9d,
+	0x87, 0x1e, 0x43, 0x93, 0x93, 0x39, 0x8d, 0x32, 0x7b, 0x0e, 0xaa, 0xb6, 0xcc, 0xbb, 0xff, 0xac,
+	0x12, 0x0c, 0x7c, 0x6d, 0xa3, 0xc7, 0x50, 0x7d, 0x26, 0x32, 0x9d, 0x15, 0x02, 0xdb, 0xdf, 0x1e,
+	0x27, 0x74, 0x1b, 0x37, 0xe4, 0x5a, 0x2d, 0x2d, 0x0f, 0x97, 0x87, 0xee, 0x40, 0xa3, 0x10, 0x87,
+	0x15, 0x95, 0x69, 0x95, 0xef, 0x1c, 0x67, 0x12, 0x9d, 0xc1, 0x41, 0xa6, 0x89, 0x66, 0x71, 0x34,
+	0xb3, 0x20, 0x15, 0x0b, 0x72, 0xfc, 0x1a, 0x90, 0xe7, 0x44, 0x4a, 0xc6, 0xa7, 0xb8, 0xe9, 0x72,
+	0x1d, 0x4e, 0x1b, 0xca, 0x9a, 0x4c, 0x83, 0x9a, 0x1d, 0xb6, 0x31, 0xd1, 0x03, 0xf0, 0x27, 0xe4,
+	0x82, 0x46, 0x09, 0xcf, 0x82, 0xba, 0x55, 0xe4, 0x9d, 0x7d, 0x85, 0x4f, 0xc9, 0x05, 0x3d, 0xe1,
+	0x19, 0xae, 0x4f, 0x9c, 0x81, 0x3e, 0x83, 0x6a, 0x4c, 0xe2, 0x19, 0x0d, 0xfc, 0xeb, 0x9b, 0xb9,
+	0x66, 0x63, 0x02, 0x1c, 0x25, 0xec, 0xa2, 0xd1, 0x07, 0xd0, 0x8e, 0x05, 0x8f, 0x73, 0xa5, 0xcc,
+	0x65, 0x7f, 0xca, 0xa9, 0x5a, 0x06, 0x8d, 0x23, 0xaf, 0xe7, 0xe3, 0x37, 0x36, 0xfe, 0x6f, 0x8d,
+	0xdb, 0x4c, 0xc5, 0xa9, 0x31, 0x52, 0x79, 0x4a, 0x03, 0xb8, 0x79, 0x2a, 0xc5, 0xa2, 0xe6, 0x29,
+	0xc5, 0x90, 0xac, 0xed, 0xce, 0x0f, 0x00, 0x9b, 0x6e, 0x9b, 0xab, 0x5f, 0xd0, 0xa5, 0x15, 0x70,
+	0x03, 0x1b, 0x13, 0x7d, 0x0e, 0xd5, 0x4b, 0x92, 0xe6, 0xd4, 0x6a, 0xb3, 0x39, 0x78, 0xef, 0x06,
+	0xdd, 0x9c, 0x8d, 0x5e, 0xa8, 0x02, 0xc1, 0xc5, 0x3f, 0x2a, 0x7d, 0xe1, 0x75, 0x7e, 0xf3, 0xa0,
+	0xb9, 0xd5, 0xe6, 0xff, 0x63, 0x0b, 0x8b, 0xcd, 0x31, 0x6f, 0x8c, 0xdb, 0x9c, 0xfb, 0x70, 0x28,
+	0x95, 0x58, 0xb0, 0xcd, 0x83, 0x56, 0xb1, 0xf1, 0xad, 0xc2, 0xeb, 0x00, 0xba, 0xbf, 0x7a, 0x00,
+	0x9b, 0x96, 0xa0, 0xe1, 0xba, 0xba, 0xf7, 0x5f, 0x9e, 0xbf, 0x15, 0x99, 0xbb, 0xbb, 0x4b, 0x62,
+	0x94, 0xde, 0xda, 0x59, 0x82, 0x8e, 0x51, 0x52, 0x9a, 0x9e, 0x93, 0xf8, 0xc2, 0x4a, 0xd8, 0xc7,
+	0xeb, 0x73, 0xf7, 0x25, 0xd4, 0x0b, 0x05, 0xa1, 0xb7, 0xa0, 0xce, 0x64, 0x24, 0x85, 0x48, 0x8b,
+	0x59, 0xd4, 0x98, 0x1c, 0x09, 0x91, 0x9a, 0x1d, 0x60, 0xf2, 0xf2, 0x81, 0xfb, 0xe4, 0x1a, 0xe1,
+	0x1b, 0x87, 0xfd, 0xf8, 0x36, 0xf8, 0xa9, 0xca, 0xa3, 0x8c, 0x5d, 0xd1, 0xe2, 0xd9, 0xa8, 0xa7,
+	0x2a, 0x1f, 0xb3, 0x2b, 0xda, 0xfd, 0xd3, 0x83, 0xe6, 0x96, 0xd2, 0x10, 0x82, 0x8a, 0x24, 0x7a,
+	0x56, 0x54, 0xb7, 0xb6, 0x21, 0x6f, 0x79, 0x47, 0x99, 0x26, 0xa9, 0x1b, 0xb8, 0x8f, 0xc1, 0xba,
+	0xc6, 0xc6, 0x63, 0xc0, 0xe7, 0x64, 0x51, 0x7c, 0x76, 0x00, 0xfe, 0x9c, 0x2c, 0xdc, 0xc7, 0x0e,
+	0xf8, 0xe6, 0x8d, 0xa2, 0x3a, 0x9e, 0xd9, 0x8e, 0xfb, 0x78, 0x7d, 0x46, 0xf7, 0xa0, 0xb5, 0xb2,
+	0xa3, 0x19, 0xd3, 0x59, 0x50, 0xb5, 0xc9, 0x07, 0x2b, 0xe7, 0x33, 0xa6, 0xb3, 0x0f, 0x87, 0x80,
+	0xae, 0x0f, 0x1f, 0xf9, 0x50, 0x39, 0xcd, 0xd3, 0xb4, 0x7d, 0x0b, 0xb5, 0xa0, 0x31, 0xce, 0xcf,
+	0x5d, 0xa3, 0xdb, 0x1e, 0x6a, 0x42, 0xfd, 0x6b, 0xba, 0xfc, 0x59, 0xa8, 0xa4, 0x5d, 0x32, 0xaf,
+	0x20, 0xa6, 0x53, 0xba, 0x68, 0x97, 0x9f, 0x7c, 0x0a, 0xb7, 0x63, 0x31, 0xdf, 0x33, 0xbe, 0x91,
+	0xf7, 0xb2, 0x9c, 0xf0, 0xec, 0xaf, 0x12, 0xfa, 0x7e, 0x80, 0xc9, 0x32, 0x7c, 0x6a, 0xbe, 0x7d,
+	0x29, 0x65, 0x78, 0xc2, 0xb3, 0xf3, 0x9a, 0xfd, 0xb7, 0x7e, 0xf2, 0xf7, 0x00, 0xd1, 0x03, 0x23,
+	0x6c, 0x14, 0x08, 0x00, 0x00,
 }
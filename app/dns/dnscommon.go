@@ -1,8 +1,10 @@
+//go:build !confonly
 // +build !confonly
 
 package dns
 
 import (
+	"context"
 	"encoding/binary"
 	"time"
 
@@ -44,14 +46,23 @@ func (r *IPRecord) getIPs() ([]net.Address, error) {
 	return r.IP, nil
 }
 
-func isNewer(baseRec *IPRecord, newRec *IPRecord) bool {
-	if newRec == nil {
-		return false
-	}
-	if baseRec == nil {
-		return true
+// expire returns the earliest expire time of the records enabled in option. It returns false if no such record exists.
+func (r record) expire(option IPOption) (time.Time, bool) {
+	var expire time.Time
+	found := false
+	for _, rec := range []struct {
+		enabled bool
+		ip      *IPRecord
+	}{{option.IPv4Enable, r.A}, {option.IPv6Enable, r.AAAA}} {
+		if !rec.enabled || rec.ip == nil {
+			continue
+		}
+		if !found || rec.ip.Expire.Before(expire) {
+			expire = rec.ip.Expire
+			found = true
+		}
 	}
-	return baseRec.Expire.Before(newRec.Expire)
+	return expire, found
 }
 
 var (
@@ -61,25 +72,86 @@ var (
 type dnsRequest struct {
 	reqType dnsmessage.Type
 	domain  string
-	start   time.Time
-	expire  time.Time
-	msg     *dnsmessage.Message
+	// key is the key of answers of the request in the cache of the name server.
+	key    string
+	start  time.Time
+	expire time.Time
+	msg    *dnsmessage.Message
+}
+
+type clientIPKey struct{}
+
+// contextWithClientIP returns a context for queries on behalf of the client.
+func contextWithClientIP(ctx context.Context, ip net.IP) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func clientIPFromContext(ctx context.Context) net.IP {
+	ip, _ := ctx.Value(clientIPKey{}).(net.IP)
+	return ip
+}
+
+// sourceSubnet returns the subnet of the client IP sent to name servers, which is /24 for IPv4 and /56 for IPv6.
+func sourceSubnet(ip net.IP) (net.IP, int) {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4.Mask(net.CIDRMask(24, net.IPv4len*8)), 24
+	}
+	return ip.Mask(net.CIDRMask(56, net.IPv6len*8)), 56
+}
+
+// clientSubnet decides the subnet in EDNS Client Subnet of queries to a name server.
+type clientSubnet struct {
+	ip     net.IP
+	prefix int
+	// bySource sends the subnet of the client, if the query is on behalf of a client.
+	bySource bool
+}
+
+// isPerClient returns true if answers of queries in the context are only valid for the client.
+func (c clientSubnet) isPerClient(ctx context.Context) bool {
+	return c.bySource && clientIPFromContext(ctx) != nil
+}
+
+// cacheKey returns the key of answers of the domain in the cache of a name server. Answers on behalf of a client are
+// cached per subnet of the client, as in Server.cacheKey.
+func (c clientSubnet) cacheKey(ctx context.Context, domain string) string {
+	if !c.isPerClient(ctx) {
+		return domain
+	}
+	subnet, _ := sourceSubnet(clientIPFromContext(ctx))
+	return domain + "@" + subnet.String()
 }
 
-func genEDNS0Options(clientIP net.IP) *dnsmessage.Resource {
+func (c clientSubnet) options(ctx context.Context) *dnsmessage.Resource {
+	if c.bySource {
+		ip := clientIPFromContext(ctx)
+		if ip == nil {
+			return nil
+		}
+		return genEDNS0Options(sourceSubnet(ip))
+	}
+	return genEDNS0Options(c.ip, c.prefix)
+}
+
+// genEDNS0Options returns the OPT record with the subnet of the client IP. Netmask defaults to 24 for IPv4 and
+// 96 for IPv6 if it is zero.
+func genEDNS0Options(clientIP net.IP, netmask int) *dnsmessage.Resource {
 	if len(clientIP) == 0 {
 		return nil
 	}
 
-	var netmask int
 	var family uint16
 
 	if len(clientIP) == 4 {
 		family = 1
-		netmask = 24 // 24 for IPV4, 96 for IPv6
+		if netmask == 0 {
+			netmask = 24
+		}
 	} else {
 		family = 2
-		netmask = 96
+		if netmask == 0 {
+			netmask = 96
+		}
 	}
 
 	b := make([]byte, 4)
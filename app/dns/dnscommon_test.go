@@ -3,6 +3,7 @@
 package dns
 
 import (
+	"context"
 	"math/rand"
 	"testing"
 	"time"
@@ -137,13 +138,46 @@ func Test_genEDNS0Options(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := genEDNS0Options(tt.args.clientIP); got == nil {
+			if got := genEDNS0Options(tt.args.clientIP, 0); got == nil {
 				t.Errorf("genEDNS0Options() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestClientSubnetOptions(t *testing.T) {
+	clientCtx := contextWithClientIP(context.Background(), net.ParseIP("2001:db8:1234:5678::1"))
+
+	testCases := []struct {
+		subnet clientSubnet
+		ctx    context.Context
+		want   []byte
+	}{
+		{clientSubnet{}, clientCtx, nil},
+		{clientSubnet{ip: net.IP{1, 2, 3, 4}}, clientCtx, []byte{0, 1, 24, 0, 1, 2, 3}},
+		{clientSubnet{ip: net.IP{1, 2, 3, 4}, prefix: 16}, clientCtx, []byte{0, 1, 16, 0, 1, 2}},
+		{clientSubnet{bySource: true}, context.Background(), nil},
+		{clientSubnet{bySource: true}, clientCtx, []byte{0, 2, 56, 0, 0x20, 0x01, 0x0d, 0xb8, 0x12, 0x34, 0x56}},
+		{clientSubnet{bySource: true}, contextWithClientIP(context.Background(), net.ParseIP("4.3.2.1")), []byte{0, 1, 24, 0, 4, 3, 2}},
+	}
+	for _, tc := range testCases {
+		opt := tc.subnet.options(tc.ctx)
+		if tc.want == nil {
+			if opt != nil {
+				t.Error("expect no option for ", tc.subnet, ", but got ", opt)
+			}
+			continue
+		}
+		if opt == nil {
+			t.Error("expect option for ", tc.subnet, ", but nil")
+			continue
+		}
+		if r := cmp.Diff(opt.Body.(*dnsmessage.OPTResource).Options[0].Data, tc.want); r != "" {
+			t.Error(r)
+		}
+	}
+}
+
 func TestFqdn(t *testing.T) {
 	type args struct {
 		domain string
@@ -33,10 +33,11 @@ import (
 type DoHNameServer struct {
 	sync.RWMutex
 	ips        map[string]record
+	records    *recordCache
 	pub        *pubsub.Service
 	cleanup    *task.Periodic
 	reqID      uint32
-	clientIP   net.IP
+	subnet     clientSubnet
 	httpClient *http.Client
 	dohURL     string
 	name       string
@@ -113,11 +114,12 @@ func NewDoHLocalNameServer(url *url.URL, clientIP net.IP) *DoHNameServer {
 func baseDOHNameServer(url *url.URL, prefix string, clientIP net.IP) *DoHNameServer {
 
 	s := &DoHNameServer{
-		ips:      make(map[string]record),
-		clientIP: clientIP,
-		pub:      pubsub.NewService(),
-		name:     prefix + "//" + url.Host,
-		dohURL:   url.String(),
+		ips:     make(map[string]record),
+		records: newRecordCache(),
+		subnet:  clientSubnet{ip: clientIP},
+		pub:     pubsub.NewService(),
+		name:    prefix + "//" + url.Host,
+		dohURL:  url.String(),
 	}
 	s.cleanup = &task.Periodic{
 		Interval: time.Minute,
@@ -138,10 +140,12 @@ func (s *DoHNameServer) Cleanup() error {
 	s.Lock()
 	defer s.Unlock()
 
-	if len(s.ips) == 0 {
+	if len(s.ips) == 0 && s.records.len() == 0 {
 		return newError("nothing to do. stopping...")
 	}
 
+	s.records.cleanup(now)
+
 	for domain, record := range s.ips {
 		if record.A != nil && record.A.Expire.Before(now) {
 			record.A = nil
@@ -165,19 +169,17 @@ func (s *DoHNameServer) Cleanup() error {
 	return nil
 }
 
+// updateIP replaces cached answers under the key of the request with the new answers, and notifies the subscribers of
+// the key.
 func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
 	s.Lock()
-	rec := s.ips[req.domain]
-	updated := false
+	rec := s.ips[req.key]
 
 	switch req.reqType {
 	case dnsmessage.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
+		rec.A = ipRec
 	case dnsmessage.TypeAAAA:
 		addr := make([]net.Address, 0)
 		for _, ip := range ipRec.IP {
@@ -186,21 +188,16 @@ func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
+		rec.AAAA = ipRec
 	}
 	newError(s.name, " got answere: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.ips[req.key] = rec
 	switch req.reqType {
 	case dnsmessage.TypeA:
-		s.pub.Publish(req.domain+"4", nil)
+		s.pub.Publish(req.key+"4", nil)
 	case dnsmessage.TypeAAAA:
-		s.pub.Publish(req.domain+"6", nil)
+		s.pub.Publish(req.key+"6", nil)
 	}
 	s.Unlock()
 	common.Must(s.cleanup.Start())
@@ -213,37 +210,16 @@ func (s *DoHNameServer) newReqID() uint16 {
 func (s *DoHNameServer) sendQuery(ctx context.Context, domain string, option IPOption) {
 	newError(s.name, " querying: ", domain).AtInfo().WriteToLog(session.ExportIDToError(ctx))
 
-	reqs := buildReqMsgs(domain, option, s.newReqID, genEDNS0Options(s.clientIP))
+	key := s.subnet.cacheKey(ctx, domain)
+	reqs := buildReqMsgs(domain, option, s.newReqID, s.subnet.options(ctx))
 
-	var deadline time.Time
-	if d, ok := ctx.Deadline(); ok {
-		deadline = d
-	} else {
-		deadline = time.Now().Add(time.Second * 8)
-	}
+	deadline := queryDeadline(ctx)
 
 	for _, req := range reqs {
+		req.key = key
 
 		go func(r *dnsRequest) {
-
-			// generate new context for each req, using same context
-			// may cause reqs all aborted if any one encounter an error
-			dnsCtx := context.Background()
-
-			// reserve internal dns server requested Inbound
-			if inbound := session.InboundFromContext(ctx); inbound != nil {
-				dnsCtx = session.ContextWithInbound(dnsCtx, inbound)
-			}
-
-			dnsCtx = session.ContextWithContent(dnsCtx, &session.Content{
-				Protocol:      "https",
-				SkipRoutePick: true,
-			})
-
-			// forced to use mux for DOH
-			dnsCtx = session.ContextWithMuxPrefered(dnsCtx, true)
-
-			dnsCtx, cancel := context.WithDeadline(dnsCtx, deadline)
+			dnsCtx, cancel := s.newDoHContext(ctx, deadline)
 			defer cancel()
 
 			b, _ := dns.PackMessage(r.msg)
@@ -262,6 +238,34 @@ func (s *DoHNameServer) sendQuery(ctx context.Context, domain string, option IPO
 	}
 }
 
+func queryDeadline(ctx context.Context) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now().Add(time.Second * 8)
+}
+
+func (s *DoHNameServer) newDoHContext(ctx context.Context, deadline time.Time) (context.Context, context.CancelFunc) {
+	// generate new context for each req, using same context
+	// may cause reqs all aborted if any one encounter an error
+	dnsCtx := context.Background()
+
+	// reserve internal dns server requested Inbound
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		dnsCtx = session.ContextWithInbound(dnsCtx, inbound)
+	}
+
+	dnsCtx = session.ContextWithContent(dnsCtx, &session.Content{
+		Protocol:      "https",
+		SkipRoutePick: true,
+	})
+
+	// forced to use mux for DOH
+	dnsCtx = session.ContextWithMuxPrefered(dnsCtx, true)
+
+	return context.WithDeadline(dnsCtx, deadline)
+}
+
 func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte, error) {
 
 	body := bytes.NewBuffer(b)
@@ -287,6 +291,18 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return ioutil.ReadAll(resp.Body)
 }
 
+// expireTime implements cachedClient.
+func (s *DoHNameServer) expireTime(ctx context.Context, domain string, option IPOption) (time.Time, bool) {
+	s.RLock()
+	record, found := s.ips[s.subnet.cacheKey(ctx, Fqdn(domain))]
+	s.RUnlock()
+
+	if !found {
+		return time.Time{}, false
+	}
+	return record.expire(option)
+}
+
 func (s *DoHNameServer) findIPsForDomain(domain string, option IPOption) ([]net.IP, error) {
 	s.RLock()
 	record, found := s.ips[domain]
@@ -332,21 +348,26 @@ func (s *DoHNameServer) findIPsForDomain(domain string, option IPOption) ([]net.
 // QueryIP is called from dns.Server->queryIPTimeout
 func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option IPOption) ([]net.IP, error) {
 	fqdn := Fqdn(domain)
+	// Answers for a client are cached per subnet, and not shared with others.
+	key := s.subnet.cacheKey(ctx, fqdn)
 
-	ips, err := s.findIPsForDomain(fqdn, option)
-	if err != errRecordNotFound {
-		newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
-		return ips, err
+	bypassed := isCacheBypassed(ctx)
+	if !bypassed {
+		ips, err := s.findIPsForDomain(key, option)
+		if err != errRecordNotFound {
+			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			return ips, err
+		}
 	}
 
 	// ipv4 and ipv6 belong to different subscription groups
 	var sub4, sub6 *pubsub.Subscriber
 	if option.IPv4Enable {
-		sub4 = s.pub.Subscribe(fqdn + "4")
+		sub4 = s.pub.Subscribe(key + "4")
 		defer sub4.Close()
 	}
 	if option.IPv6Enable {
-		sub6 = s.pub.Subscribe(fqdn + "6")
+		sub6 = s.pub.Subscribe(key + "6")
 		defer sub6.Close()
 	}
 	done := make(chan interface{})
@@ -367,8 +388,17 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option IPOpt
 	}()
 	s.sendQuery(ctx, fqdn, option)
 
+	if bypassed {
+		// Cached answers are replaced by the new answers before the subscribers are notified.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+		}
+	}
+
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, err := s.findIPsForDomain(key, option)
 		if err != errRecordNotFound {
 			return ips, err
 		}
@@ -380,3 +410,36 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, option IPOpt
 		}
 	}
 }
+
+// QueryRecords implements recordClient.
+func (s *DoHNameServer) QueryRecords(ctx context.Context, domain string, recordType uint16) ([]dns_feature.Record, error) {
+	fqdn := Fqdn(domain)
+
+	return queryRecords(ctx, s.pub, s.records, fqdn, recordType, func() {
+		newError(s.name, " querying: ", fqdn, " ", dnsmessage.Type(recordType)).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+
+		req := buildRecordReqMsg(fqdn, recordType, s.newReqID(), s.subnet.options(ctx))
+		deadline := queryDeadline(ctx)
+		go func() {
+			dnsCtx, cancel := s.newDoHContext(ctx, deadline)
+			defer cancel()
+
+			b, _ := dns.PackMessage(req.msg)
+			resp, err := s.dohHTTPSContext(dnsCtx, b.Bytes())
+			if err != nil {
+				newError("failed to retrive response").Base(err).AtError().WriteToLog()
+				return
+			}
+			rs, err := parseRecordResponse(resp)
+			if err != nil {
+				newError("failed to handle DOH response").Base(err).AtError().WriteToLog()
+				return
+			}
+
+			newError(s.name, " got answere: ", fqdn, " ", req.reqType, " -> ", len(rs.Records), " records ", time.Since(req.start)).AtInfo().WriteToLog()
+			s.records.update(fqdn, recordType, rs)
+			s.pub.Publish(recordTopic(fqdn, recordType), nil)
+			common.Must(s.cleanup.Start())
+		}()
+	})
+}
@@ -0,0 +1,274 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	dns_proto "v2ray.com/core/common/protocol/dns"
+	udp_proto "v2ray.com/core/common/protocol/udp"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/session"
+	quic "v2ray.com/core/external/github.com/lucas-clemente/quic-go"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport"
+	"v2ray.com/core/transport/internet/udp"
+)
+
+// quicDispatcher sends DNS messages over one reused QUIC session (RFC 9250). Each query takes a stream of its own,
+// so queries are never blocked by each other.
+type quicDispatcher struct {
+	sync.Mutex
+	dial     func(ctx context.Context, dest net.Destination) (quic.Session, error)
+	callback udp.ResponseCallback
+	session  quic.Session
+	// connecting is closed when the ongoing dial completes. It is nil if no dial is in progress.
+	connecting chan struct{}
+}
+
+func isSessionActive(s quic.Session) bool {
+	select {
+	case <-s.Context().Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// getSession returns the current session, or dials a new one if there is none or it is closed. Dialing is done
+// without holding the lock, and concurrent queries wait for the same dial.
+func (d *quicDispatcher) getSession(ctx context.Context, destination net.Destination) (quic.Session, error) {
+	for {
+		d.Lock()
+		if d.session != nil && isSessionActive(d.session) {
+			s := d.session
+			d.Unlock()
+			return s, nil
+		}
+		if connecting := d.connecting; connecting != nil {
+			d.Unlock()
+			select {
+			case <-connecting:
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		connecting := make(chan struct{})
+		d.connecting = connecting
+		d.Unlock()
+
+		newError("establishing new QUIC session for ", destination).WriteToLog()
+		s, err := d.dial(ctx, destination)
+
+		d.Lock()
+		d.connecting = nil
+		if err == nil {
+			d.session = s
+		}
+		d.Unlock()
+		close(connecting)
+
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+}
+
+// Dispatch implements messageDispatcher.
+func (d *quicDispatcher) Dispatch(ctx context.Context, destination net.Destination, payload *buf.Buffer) {
+	s, err := d.getSession(ctx, destination)
+	if err != nil {
+		newError("failed to connect to ", destination).Base(err).WriteToLog(session.ExportIDToError(ctx))
+		payload.Release()
+		return
+	}
+
+	go func() {
+		if err := d.exchange(ctx, s, destination, payload); err != nil {
+			newError("failed to exchange DNS message with ", destination).Base(err).WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+}
+
+func (d *quicDispatcher) exchange(ctx context.Context, s quic.Session, destination net.Destination, payload *buf.Buffer) error {
+	if payload.Len() < 2 {
+		payload.Release()
+		return newError("invalid DNS message")
+	}
+
+	// Message ID must be 0 in DNS over QUIC, as streams already tell messages apart.
+	id := binary.BigEndian.Uint16(payload.BytesTo(2))
+	binary.BigEndian.PutUint16(payload.BytesTo(2), 0)
+
+	stream, err := s.OpenStream()
+	if err != nil {
+		payload.Release()
+		return err
+	}
+	defer stream.CancelRead(0)
+	common.Must(stream.SetDeadline(time.Now().Add(time.Second * 8)))
+
+	writer := &dns_proto.TCPWriter{
+		Writer: buf.NewWriter(stream),
+	}
+	if err := writer.WriteMessage(payload); err != nil {
+		return err
+	}
+	// Closing the stream only closes its sending direction, which tells the server that the query is complete.
+	if err := stream.Close(); err != nil {
+		return err
+	}
+
+	b, err := readStreamMessage(stream)
+	if err != nil {
+		return err
+	}
+	if b.Len() < 2 {
+		b.Release()
+		return newError("invalid DNS response")
+	}
+	binary.BigEndian.PutUint16(b.BytesTo(2), id)
+
+	d.callback(ctx, &udp_proto.Packet{
+		Payload: b,
+		Source:  destination,
+	})
+	return nil
+}
+
+// readStreamMessage reads a message with two-byte length prefix. Unlike dns_proto.TCPReader, it doesn't drop data
+// returned together with io.EOF, which QUIC streams do at the end of the stream.
+func readStreamMessage(reader io.Reader) (*buf.Buffer, error) {
+	size, err := serial.ReadUint16(reader)
+	if err != nil {
+		return nil, err
+	}
+	if size > buf.Size {
+		return nil, newError("message size too large: ", size)
+	}
+	b := buf.New()
+	if _, err := b.ReadFullFrom(reader, int32(size)); err != nil {
+		b.Release()
+		return nil, err
+	}
+	return b, nil
+}
+
+// linkPacketConn is a net.PacketConn over a dispatched UDP link, which always talks to the destination of the link.
+type linkPacketConn struct {
+	link   *transport.Link
+	remote net.Addr
+	cache  buf.MultiBuffer
+}
+
+func (c *linkPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for c.cache.IsEmpty() {
+		mb, err := c.link.Reader.ReadMultiBuffer()
+		if err != nil {
+			return 0, nil, err
+		}
+		c.cache = mb
+	}
+
+	var b *buf.Buffer
+	c.cache, b = buf.SplitFirst(c.cache)
+	n := copy(p, b.Bytes())
+	b.Release()
+	return n, c.remote, nil
+}
+
+func (c *linkPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	b := buf.New()
+	n, _ := b.Write(p)
+	if err := c.link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (c *linkPacketConn) Close() error {
+	common.Close(c.link.Writer)
+	common.Interrupt(c.link.Reader)
+	return nil
+}
+
+func (c *linkPacketConn) LocalAddr() net.Addr {
+	return &net.UDPAddr{
+		IP:   []byte{0, 0, 0, 0},
+		Port: 0,
+	}
+}
+
+func (c *linkPacketConn) SetDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *linkPacketConn) SetReadDeadline(t time.Time) error {
+	return nil
+}
+
+func (c *linkPacketConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// NewDoQNameServer creates DNS over QUIC client object for remote resolving.
+// Queries are sent through the dispatcher, so they can be routed to any outbound.
+// Only QUIC versions supported by the bundled quic-go are negotiated.
+func NewDoQNameServer(url *url.URL, dispatcher routing.Dispatcher, clientIP net.IP) (*ClassicNameServer, error) {
+	return newDoQNameServer(url, dispatcher, clientIP, &tls.Config{})
+}
+
+func newDoQNameServer(url *url.URL, dispatcher routing.Dispatcher, clientIP net.IP, tlsConfig *tls.Config) (*ClassicNameServer, error) {
+	dest, err := parseNameServerURL(url, net.Network_UDP, 853)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = url.Hostname()
+	}
+	tlsConfig.NextProtos = []string{"doq"}
+	quicConfig := &quic.Config{
+		HandshakeTimeout: handshakeTimeout,
+		IdleTimeout:      streamIdleTimeout,
+	}
+
+	s := baseClassicNameServer(dest, "DOQ//"+dest.NetAddr(), clientIP)
+	s.transport = &quicDispatcher{
+		callback: s.HandleResponse,
+		dial: func(ctx context.Context, dest net.Destination) (quic.Session, error) {
+			link, err := dispatcher.Dispatch(ctx, dest)
+			if err != nil {
+				return nil, err
+			}
+
+			remote := &net.UDPAddr{Port: int(dest.Port)}
+			if dest.Address.Family().IsIP() {
+				remote.IP = dest.Address.IP()
+			}
+			conn := &linkPacketConn{
+				link:   link,
+				remote: remote,
+			}
+			session, err := quic.DialContext(ctx, conn, remote, dest.NetAddr(), tlsConfig, quicConfig)
+			if err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return session, nil
+		},
+	}
+	newError("DNS: created DOQ client for ", url.String()).AtInfo().WriteToLog()
+	return s, nil
+}
@@ -0,0 +1,72 @@
+// +build !confonly
+
+package dns
+
+import (
+	"encoding/binary"
+	"net/url"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"v2ray.com/core/common"
+	quic "v2ray.com/core/external/github.com/lucas-clemente/quic-go"
+)
+
+func serveDoQStream(t *testing.T, stream quic.Stream) {
+	defer stream.Close()
+
+	b, err := readStreamMessage(stream)
+	if err != nil {
+		t.Error("failed to read query: ", err)
+		return
+	}
+	defer b.Release()
+
+	r := new(dns.Msg)
+	common.Must(r.Unpack(b.Bytes()))
+	if r.Id != 0 {
+		t.Error("expect message ID 0, but got ", r.Id)
+		return
+	}
+
+	ans := common.Must2(testAnswer(r).Pack()).([]byte)
+	response := make([]byte, 2, 2+len(ans))
+	binary.BigEndian.PutUint16(response, uint16(len(ans)))
+	if _, err := stream.Write(append(response, ans...)); err != nil {
+		t.Error("failed to write response: ", err)
+	}
+}
+
+func TestDoQNameServer(t *testing.T) {
+	serverConfig, clientConfig := newTestTLSConfigs()
+	serverConfig.NextProtos = []string{"doq"}
+	listener, err := quic.ListenAddr("127.0.0.1:0", serverConfig, nil)
+	common.Must(err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			session, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for {
+					stream, err := session.AcceptStream()
+					if err != nil {
+						return
+					}
+					go serveDoQStream(t, stream)
+				}
+			}()
+		}
+	}()
+
+	d := new(systemDispatcher)
+	u := common.Must2(url.Parse("quic://" + listener.Addr().String())).(*url.URL)
+	s, err := newDoQNameServer(u, d, nil, clientConfig)
+	common.Must(err)
+
+	testNameServer(t, s, d)
+}
@@ -0,0 +1,219 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	dns_proto "v2ray.com/core/common/protocol/dns"
+	udp_proto "v2ray.com/core/common/protocol/udp"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport/internet/udp"
+)
+
+const (
+	// streamIdleTimeout is how long an idle connection to a name server is kept for later queries.
+	streamIdleTimeout = time.Minute
+	handshakeTimeout  = time.Second * 4
+)
+
+// streamDispatcher sends DNS messages over one reused stream connection, with two-byte length prefixes (RFC 7766).
+// Queries are pipelined, and responses are matched to queries by message ID in ClassicNameServer.
+type streamDispatcher struct {
+	sync.Mutex
+	dial     func(ctx context.Context, dest net.Destination) (net.Conn, error)
+	callback udp.ResponseCallback
+	conn     net.Conn
+	timer    signal.ActivityUpdater
+	// connecting is closed when the ongoing dial completes. It is nil if no dial is in progress.
+	connecting chan struct{}
+
+	// write serializes writing of messages, each of which may take more than one write to the connection.
+	write sync.Mutex
+}
+
+// Dispatch implements messageDispatcher.
+func (d *streamDispatcher) Dispatch(ctx context.Context, destination net.Destination, payload *buf.Buffer) {
+	conn, timer, err := d.getConn(ctx, destination)
+	if err != nil {
+		newError("failed to connect to ", destination).Base(err).WriteToLog(session.ExportIDToError(ctx))
+		payload.Release()
+		return
+	}
+
+	writer := &dns_proto.TCPWriter{
+		Writer: buf.NewWriter(conn),
+	}
+	d.write.Lock()
+	err = writer.WriteMessage(payload)
+	d.write.Unlock()
+	if err != nil {
+		newError("failed to send DNS query to ", destination).Base(err).WriteToLog(session.ExportIDToError(ctx))
+		d.closeConn(conn)
+		return
+	}
+	timer.Update()
+}
+
+// getConn returns the current connection, or dials a new one if there is none. Dialing is done without holding the
+// lock, and concurrent queries wait for the same dial.
+func (d *streamDispatcher) getConn(ctx context.Context, destination net.Destination) (net.Conn, signal.ActivityUpdater, error) {
+	for {
+		d.Lock()
+		if d.conn != nil {
+			conn, timer := d.conn, d.timer
+			d.Unlock()
+			return conn, timer, nil
+		}
+		if connecting := d.connecting; connecting != nil {
+			d.Unlock()
+			select {
+			case <-connecting:
+				continue
+			case <-ctx.Done():
+				return nil, nil, ctx.Err()
+			}
+		}
+		connecting := make(chan struct{})
+		d.connecting = connecting
+		d.Unlock()
+
+		conn, timer, err := d.connect(ctx, destination)
+
+		d.Lock()
+		d.connecting = nil
+		if err == nil {
+			d.conn = conn
+			d.timer = timer
+		}
+		d.Unlock()
+		close(connecting)
+
+		if err != nil {
+			return nil, nil, err
+		}
+		go d.handleInput(ctx, conn, timer, destination)
+		return conn, timer, nil
+	}
+}
+
+func (d *streamDispatcher) connect(ctx context.Context, destination net.Destination) (net.Conn, signal.ActivityUpdater, error) {
+	newError("establishing new connection for ", destination).WriteToLog()
+
+	ctx, cancel := context.WithCancel(ctx)
+	conn, err := d.dial(ctx, destination)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	timer := signal.CancelAfterInactivity(ctx, func() {
+		cancel()
+		d.closeConn(conn)
+	}, streamIdleTimeout)
+	return conn, timer, nil
+}
+
+func (d *streamDispatcher) closeConn(conn net.Conn) {
+	d.Lock()
+	if d.conn == conn {
+		d.conn = nil
+	}
+	d.Unlock()
+	conn.Close()
+}
+
+func (d *streamDispatcher) handleInput(ctx context.Context, conn net.Conn, timer signal.ActivityUpdater, destination net.Destination) {
+	defer d.closeConn(conn)
+
+	reader := dns_proto.NewTCPReader(buf.NewReader(conn))
+	for {
+		b, err := reader.ReadMessage()
+		if err != nil {
+			if err != io.EOF {
+				newError("failed to read DNS response from ", destination).Base(err).WriteToLog()
+			}
+			return
+		}
+		timer.Update()
+		d.callback(ctx, &udp_proto.Packet{
+			Payload: b,
+			Source:  destination,
+		})
+	}
+}
+
+// NewDoTNameServer creates DNS over TLS (RFC 7858) client object for remote resolving.
+// Queries are sent through the dispatcher, so they can be routed to any outbound.
+func NewDoTNameServer(url *url.URL, dispatcher routing.Dispatcher, clientIP net.IP) (*ClassicNameServer, error) {
+	return newDoTNameServer(url, dispatcher, clientIP, &tls.Config{})
+}
+
+func newDoTNameServer(url *url.URL, dispatcher routing.Dispatcher, clientIP net.IP, tlsConfig *tls.Config) (*ClassicNameServer, error) {
+	dest, err := parseNameServerURL(url, net.Network_TCP, 853)
+	if err != nil {
+		return nil, err
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = url.Hostname()
+	}
+	s := baseClassicNameServer(dest, "DOT//"+dest.NetAddr(), clientIP)
+	s.transport = &streamDispatcher{
+		callback: s.HandleResponse,
+		dial: func(ctx context.Context, dest net.Destination) (net.Conn, error) {
+			link, err := dispatcher.Dispatch(ctx, dest)
+			if err != nil {
+				return nil, err
+			}
+			conn := tls.Client(net.NewConnection(
+				net.ConnectionInputMulti(link.Writer),
+				net.ConnectionOutputMulti(link.Reader),
+			), tlsConfig)
+
+			// Dispatched connections don't support deadlines.
+			timer := time.AfterFunc(handshakeTimeout, func() {
+				conn.Close()
+			})
+			err = conn.Handshake()
+			timer.Stop()
+			if err != nil {
+				conn.Close()
+				return nil, newError("failed to complete TLS handshake").Base(err)
+			}
+			return conn, nil
+		},
+	}
+	newError("DNS: created DOT client for ", url.String()).AtInfo().WriteToLog()
+	return s, nil
+}
+
+// parseNameServerURL returns the destination of name server URLs in the form of scheme://host[:port].
+func parseNameServerURL(url *url.URL, network net.Network, defaultPort net.Port) (net.Destination, error) {
+	if url.Hostname() == "" {
+		return net.Destination{}, newError("empty host in name server: ", url.String())
+	}
+
+	port := defaultPort
+	if p := url.Port(); p != "" {
+		var err error
+		if port, err = net.PortFromString(p); err != nil {
+			return net.Destination{}, newError("invalid port in name server: ", url.String()).Base(err)
+		}
+	}
+
+	return net.Destination{
+		Network: network,
+		Address: net.ParseAddress(url.Hostname()),
+		Port:    port,
+	}, nil
+}
@@ -0,0 +1,217 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol/tls/cert"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport"
+)
+
+// systemDispatcher dispatches all connections to the system network, and counts the dispatched connections.
+type systemDispatcher struct {
+	count int32
+}
+
+func (*systemDispatcher) Type() interface{} {
+	return routing.DispatcherType()
+}
+
+func (*systemDispatcher) Start() error {
+	return nil
+}
+
+func (*systemDispatcher) Close() error {
+	return nil
+}
+
+func (d *systemDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	atomic.AddInt32(&d.count, 1)
+
+	conn, err := net.Dial(dest.Network.SystemString(), dest.NetAddr())
+	if err != nil {
+		return nil, err
+	}
+	link := &transport.Link{
+		Reader: buf.NewReader(conn),
+		Writer: &connWriter{conn: conn},
+	}
+	if dest.Network == net.Network_UDP {
+		link.Reader = buf.NewPacketReader(conn)
+	}
+	return link, nil
+}
+
+// connWriter writes each buffer with a separate call, so that each UDP payload is sent in its own packet.
+type connWriter struct {
+	conn net.Conn
+}
+
+func (w *connWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	for _, b := range mb {
+		if _, err := w.conn.Write(b.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *connWriter) Close() error {
+	return w.conn.Close()
+}
+
+// newTestTLSConfigs returns the TLS config of a test name server for dns.v2fly.org, and the TLS config to verify it.
+func newTestTLSConfigs() (*tls.Config, *tls.Config) {
+	ca := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment|x509.KeyUsageCertSign))
+	leaf := cert.MustGenerate(ca, cert.DNSNames("dns.v2fly.org"))
+
+	certPEM, keyPEM := leaf.ToPEM()
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	common.Must(err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(common.Must2(x509.ParseCertificate(ca.Certificate)).(*x509.Certificate))
+
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+	}
+	clientConfig := &tls.Config{
+		ServerName: "dns.v2fly.org",
+		RootCAs:    roots,
+	}
+	return serverConfig, clientConfig
+}
+
+// testAnswer answers A queries of google.com and facebook.com, and MX queries of v2fly.org.
+func testAnswer(r *dns.Msg) *dns.Msg {
+	ans := new(dns.Msg)
+	ans.SetReply(r)
+	for _, q := range r.Question {
+		switch {
+		case q.Name == "google.com." && q.Qtype == dns.TypeA:
+			ans.Answer = append(ans.Answer, common.Must2(dns.NewRR("google.com. IN A 8.8.8.8")).(dns.RR))
+		case q.Name == "facebook.com." && q.Qtype == dns.TypeA:
+			ans.Answer = append(ans.Answer, common.Must2(dns.NewRR("facebook.com. IN A 9.9.9.9")).(dns.RR))
+		case q.Name == "v2fly.org." && q.Qtype == dns.TypeMX:
+			ans.Answer = append(ans.Answer, common.Must2(dns.NewRR("v2fly.org. IN MX 10 mail.v2fly.org.")).(dns.RR))
+		}
+	}
+	return ans
+}
+
+// testNameServer queries the name server concurrently, and expects all queries to share one connection.
+func testNameServer(t *testing.T, s *ClassicNameServer, d *systemDispatcher) {
+	testCases := []struct {
+		domain string
+		ip     string
+	}{
+		{"google.com", "8.8.8.8"},
+		{"facebook.com", "9.9.9.9"},
+	}
+
+	var wg sync.WaitGroup
+	for _, testCase := range testCases {
+		testCase := testCase
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+			defer cancel()
+			ips, err := s.QueryIP(ctx, testCase.domain, IPOption{IPv4Enable: true})
+			if err != nil {
+				t.Error("failed to query ", testCase.domain, ": ", err)
+				return
+			}
+			if len(ips) != 1 || ips[0].String() != testCase.ip {
+				t.Error("expect ", testCase.ip, " for ", testCase.domain, ", but got ", ips)
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+		defer cancel()
+		records, err := s.QueryRecords(ctx, "v2fly.org", uint16(dnsmessage.TypeMX))
+		if err != nil {
+			t.Error("failed to query MX records: ", err)
+			return
+		}
+		if len(records) != 1 || records[0].Type != uint16(dnsmessage.TypeMX) {
+			t.Error("expect 1 MX record, but got ", records)
+		}
+	}()
+	wg.Wait()
+
+	if c := atomic.LoadInt32(&d.count); c != 1 {
+		t.Error("expect 1 connection, but got ", c)
+	}
+}
+
+func TestDoTNameServer(t *testing.T) {
+	serverConfig, clientConfig := newTestTLSConfigs()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	common.Must(err)
+
+	dnsServer := &dns.Server{
+		Listener: listener,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			w.WriteMsg(testAnswer(r))
+		}),
+	}
+	go dnsServer.ActivateAndServe()
+	defer dnsServer.Shutdown()
+
+	d := new(systemDispatcher)
+	u := common.Must2(url.Parse("tls://" + listener.Addr().String())).(*url.URL)
+	s, err := newDoTNameServer(u, d, nil, clientConfig)
+	common.Must(err)
+
+	testNameServer(t, s, d)
+}
+
+func TestDoTNameServerHandshakeFailure(t *testing.T) {
+	serverConfig, _ := newTestTLSConfigs()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	common.Must(err)
+
+	dnsServer := &dns.Server{
+		Listener: listener,
+		Handler: dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+			w.WriteMsg(testAnswer(r))
+		}),
+	}
+	go dnsServer.ActivateAndServe()
+	defer dnsServer.Shutdown()
+
+	// The certificate of the server is not trusted.
+	u := common.Must2(url.Parse("tls://" + listener.Addr().String())).(*url.URL)
+	s, err := newDoTNameServer(u, new(systemDispatcher), nil, &tls.Config{ServerName: "dns.v2fly.org"})
+	common.Must(err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*2)
+	defer cancel()
+	if _, err := s.QueryIP(ctx, "google.com", IPOption{IPv4Enable: true}); err == nil {
+		t.Error("expect error for untrusted server, but nil")
+	}
+}
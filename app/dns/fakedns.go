@@ -0,0 +1,210 @@
+// +build !confonly
+
+package dns
+
+import (
+	"container/list"
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/net"
+)
+
+const defaultFakeDNSLRUSize = 65535
+
+type fakeIPEntry struct {
+	domain string
+	ip     net.IP
+}
+
+// fakeIPPool allocates IPs in a CIDR for domains. When the pool is full, the IP of the least recently used domain
+// is recycled.
+type fakeIPPool struct {
+	sync.Mutex
+	network  *net.IPNet
+	size     uint64
+	capacity int
+	cursor   uint64
+	lru      *list.List
+	byDomain map[string]*list.Element
+	byIP     map[string]*list.Element
+}
+
+func newFakeIPPool(cidr string, lruSize uint32) (*fakeIPPool, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, newError("invalid fake IP pool: ", cidr).Base(err)
+	}
+	if ip4 := network.IP.To4(); ip4 != nil {
+		network.IP = ip4
+	}
+
+	ones, bits := network.Mask.Size()
+	hostBits := uint(bits - ones)
+	if hostBits < 2 {
+		return nil, newError("fake IP pool too small: ", cidr)
+	}
+	size := uint64(1) << 63
+	if hostBits < 63 {
+		size = uint64(1) << hostBits
+	}
+
+	capacity := defaultFakeDNSLRUSize
+	if lruSize > 0 {
+		capacity = int(lruSize)
+	}
+	// Network address and broadcast address are never handed out.
+	if uint64(capacity) > size-2 {
+		capacity = int(size - 2)
+	}
+
+	return &fakeIPPool{
+		network:  network,
+		size:     size,
+		capacity: capacity,
+		lru:      list.New(),
+		byDomain: make(map[string]*list.Element),
+		byIP:     make(map[string]*list.Element),
+	}, nil
+}
+
+// ipAt returns the IP at offset of the pool.
+func (p *fakeIPPool) ipAt(offset uint64) net.IP {
+	ip := make(net.IP, len(p.network.IP))
+	copy(ip, p.network.IP)
+	if len(ip) == net.IPv4len {
+		binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(ip)+uint32(offset))
+		return ip
+	}
+	tail := ip[net.IPv6len-8:]
+	binary.BigEndian.PutUint64(tail, binary.BigEndian.Uint64(tail)+offset)
+	return ip
+}
+
+// nextIP returns the next IP that is not in use. It must be called with a free slot in the pool.
+func (p *fakeIPPool) nextIP() net.IP {
+	for {
+		p.cursor++
+		if p.cursor >= p.size-1 {
+			p.cursor = 1
+		}
+		ip := p.ipAt(p.cursor)
+		if _, used := p.byIP[string(ip)]; !used {
+			return ip
+		}
+	}
+}
+
+func (p *fakeIPPool) get(domain string) net.IP {
+	p.Lock()
+	defer p.Unlock()
+
+	if e, found := p.byDomain[domain]; found {
+		p.lru.MoveToFront(e)
+		return e.Value.(*fakeIPEntry).ip
+	}
+
+	var entry *fakeIPEntry
+	if p.lru.Len() >= p.capacity {
+		e := p.lru.Back()
+		entry = e.Value.(*fakeIPEntry)
+		p.lru.Remove(e)
+		delete(p.byDomain, entry.domain)
+		delete(p.byIP, string(entry.ip))
+		newError("fake IP ", entry.ip, " recycled from ", entry.domain, " to ", domain).AtDebug().WriteToLog()
+		entry.domain = domain
+	} else {
+		entry = &fakeIPEntry{
+			domain: domain,
+			ip:     p.nextIP(),
+		}
+	}
+
+	e := p.lru.PushFront(entry)
+	p.byDomain[domain] = e
+	p.byIP[string(entry.ip)] = e
+	return entry.ip
+}
+
+func (p *fakeIPPool) lookup(ip net.IP) string {
+	if !p.network.Contains(ip) {
+		return ""
+	}
+	if len(p.network.IP) == net.IPv4len {
+		ip = ip.To4()
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	if e, found := p.byIP[string(ip)]; found {
+		p.lru.MoveToFront(e)
+		return e.Value.(*fakeIPEntry).domain
+	}
+	return ""
+}
+
+// fakeDNS hands out fake IPs in an IPv4 pool and an IPv6 pool.
+type fakeDNS struct {
+	ip4 *fakeIPPool
+	ip6 *fakeIPPool
+}
+
+func newFakeDNS(config *FakeDns) (*fakeDNS, error) {
+	f := new(fakeDNS)
+	if len(config.IpPool) > 0 {
+		pool, err := newFakeIPPool(config.IpPool, config.LruSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(pool.network.IP) != net.IPv4len {
+			return nil, newError("not an IPv4 pool: ", config.IpPool)
+		}
+		f.ip4 = pool
+	}
+	if len(config.Ipv6Pool) > 0 {
+		pool, err := newFakeIPPool(config.Ipv6Pool, config.LruSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(pool.network.IP) != net.IPv6len {
+			return nil, newError("not an IPv6 pool: ", config.Ipv6Pool)
+		}
+		f.ip6 = pool
+	}
+	if f.ip4 == nil && f.ip6 == nil {
+		return nil, newError("no fake IP pool is configured")
+	}
+	return f, nil
+}
+
+func (f *fakeDNS) getFakeIP(domain string, ipv6 bool) net.IP {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	if ipv6 {
+		if f.ip6 == nil {
+			return nil
+		}
+		return f.ip6.get(domain)
+	}
+	if f.ip4 == nil {
+		return nil
+	}
+	return f.ip4.get(domain)
+}
+
+func (f *fakeDNS) getDomain(ip net.IP) string {
+	if f.ip4 != nil {
+		if domain := f.ip4.lookup(ip); len(domain) > 0 {
+			return domain
+		}
+	}
+	if f.ip6 != nil {
+		return f.ip6.lookup(ip)
+	}
+	return ""
+}
+
+func (f *fakeDNS) contains(ip net.IP) bool {
+	return (f.ip4 != nil && f.ip4.network.Contains(ip)) || (f.ip6 != nil && f.ip6.network.Contains(ip))
+}
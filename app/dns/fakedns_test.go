@@ -0,0 +1,82 @@
+// +build !confonly
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+)
+
+func TestFakeDNS(t *testing.T) {
+	f, err := newFakeDNS(&FakeDns{
+		IpPool:   "198.18.0.0/15",
+		Ipv6Pool: "fc00::/18",
+		LruSize:  2,
+	})
+	common.Must(err)
+
+	ip := f.getFakeIP("v2ray.com.", false)
+	if r := cmp.Diff(ip, net.IP{198, 18, 0, 1}); r != "" {
+		t.Error(r)
+	}
+	if r := cmp.Diff(f.getFakeIP("V2Ray.com", false), ip); r != "" {
+		t.Error("expect the same IP for the same domain: ", r)
+	}
+	if domain := f.getDomain(net.ParseIP("198.18.0.1")); domain != "v2ray.com" {
+		t.Error("expect v2ray.com, but got ", domain)
+	}
+
+	ip6 := f.getFakeIP("v2ray.com", true)
+	if r := cmp.Diff(ip6, net.ParseIP("fc00::1")); r != "" {
+		t.Error(r)
+	}
+	if domain := f.getDomain(ip6); domain != "v2ray.com" {
+		t.Error("expect v2ray.com, but got ", domain)
+	}
+
+	if r := cmp.Diff(f.getFakeIP("google.com", false), net.IP{198, 18, 0, 2}); r != "" {
+		t.Error(r)
+	}
+
+	// v2ray.com is used more recently than google.com, so IP of google.com is recycled.
+	f.getDomain(ip)
+	if r := cmp.Diff(f.getFakeIP("example.com", false), net.IP{198, 18, 0, 2}); r != "" {
+		t.Error(r)
+	}
+	if domain := f.getDomain(net.IP{198, 18, 0, 2}); domain != "example.com" {
+		t.Error("expect example.com, but got ", domain)
+	}
+	if domain := f.getDomain(net.IP{198, 18, 0, 1}); domain != "v2ray.com" {
+		t.Error("expect v2ray.com, but got ", domain)
+	}
+	if domain := f.getDomain(net.IP{8, 8, 8, 8}); domain != "" {
+		t.Error("expect no domain, but got ", domain)
+	}
+
+	if !f.contains(net.IP{198, 19, 0, 1}) || !f.contains(net.ParseIP("fc00::2")) {
+		t.Error("expect IP in fake IP pool")
+	}
+	if f.contains(net.IP{8, 8, 8, 8}) || f.contains(net.ParseIP("2001:db8::1")) {
+		t.Error("expect IP not in fake IP pool")
+	}
+}
+
+func TestFakeIPPoolWrap(t *testing.T) {
+	pool, err := newFakeIPPool("10.0.0.0/30", 0)
+	common.Must(err)
+	if pool.capacity != 2 {
+		t.Fatal("expect capacity 2, but got ", pool.capacity)
+	}
+
+	for i, domain := range []string{"a.com", "b.com", "c.com", "d.com"} {
+		ip := pool.get(domain)
+		expected := net.IP{10, 0, 0, byte(i%2 + 1)}
+		if r := cmp.Diff(ip, expected); r != "" {
+			t.Error(domain, ": ", r)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/strmatcher"
+)
+
+func newTestQueryServer(clients ...Client) *Server {
+	hosts, err := NewStaticHosts(nil, nil)
+	common.Must(err)
+
+	return &Server{
+		hosts:   hosts,
+		clients: clients,
+		cache:   newIPCache(),
+	}
+}
+
+func (c *testClient) queryCount() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.queries
+}
+
+func TestConcurrentQuery(t *testing.T) {
+	slow := &testClient{ips: []net.IP{{1, 1, 1, 1}}, delay: time.Second * 2}
+	fast := &testClient{ips: []net.IP{{2, 2, 2, 2}}}
+	s := newTestQueryServer(slow, fast)
+	s.concurrent = true
+
+	start := time.Now()
+	ips, err := s.lookupIPInternal("v2fly.org", IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{2, 2, 2, 2}}); r != "" {
+		t.Error(r)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Error("expect answer of the fast server, but took ", d)
+	}
+}
+
+func TestConcurrentQueryExpectedIPs(t *testing.T) {
+	var container router.GeoIPMatcherContainer
+	matcher, err := container.Add(&router.GeoIP{
+		Cidr: []*router.CIDR{{Ip: []byte{4, 0, 0, 0}, Prefix: 8}},
+	})
+	common.Must(err)
+
+	poisoned := &testClient{ips: []net.IP{{3, 3, 3, 3}}}
+	valid := &testClient{ips: []net.IP{{4, 4, 4, 4}}, delay: time.Millisecond * 100}
+	s := newTestQueryServer(poisoned, valid)
+	s.concurrent = true
+	s.ipIndexMap = map[uint32]*MultiGeoIPMatcher{
+		0: {matchers: []*router.GeoIPMatcher{matcher}},
+	}
+
+	ips, err := s.lookupIPInternal("v2fly.org", IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{4, 4, 4, 4}}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestDomainRule(t *testing.T) {
+	clients := []*testClient{
+		{ips: []net.IP{{1, 1, 1, 1}}},
+		{ips: []net.IP{{2, 2, 2, 2}}},
+		{err: context.DeadlineExceeded},
+	}
+	s := newTestQueryServer(clients[0], clients[1], clients[2])
+
+	ruleMatcher := &strmatcher.MatcherGroup{}
+	s.ruleMatcher = ruleMatcher
+	s.rules = map[uint32]*domainRule{
+		ruleMatcher.Add(common.Must2(toStrMatcher(DomainMatchingType_Subdomain, "v2fly.org")).(strmatcher.Matcher)): {
+			clients: []uint32{2, 1},
+		},
+		ruleMatcher.Add(common.Must2(toStrMatcher(DomainMatchingType_Subdomain, "v2ray.com")).(strmatcher.Matcher)): {
+			clients:  []uint32{2},
+			fallback: true,
+		},
+		ruleMatcher.Add(common.Must2(toStrMatcher(DomainMatchingType_Subdomain, "example.com")).(strmatcher.Matcher)): {
+			clients: []uint32{2},
+		},
+	}
+
+	ips, err := s.lookupIPInternal("www.v2fly.org", IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{2, 2, 2, 2}}); r != "" {
+		t.Error(r)
+	}
+
+	ips, err = s.lookupIPInternal("www.v2ray.com", IPOption{IPv4Enable: true})
+	common.Must(err)
+	if r := cmp.Diff(ips, []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+
+	if ips, err := s.lookupIPInternal("www.example.com", IPOption{IPv4Enable: true}); err == nil {
+		t.Error("expect error without fallback, but got ", ips)
+	}
+
+	if c := clients[0].queryCount(); c != 1 {
+		t.Error("expect 1 query at fallback server, but got ", c)
+	}
+	if c := clients[2].queryCount(); c != 3 {
+		t.Error("expect 3 queries at the first server of rules, but got ", c)
+	}
+}
+
+func TestSourceSubnetCache(t *testing.T) {
+	client := &testClient{ips: []net.IP{{1, 1, 1, 1}}}
+	s := newTestQueryServer(client)
+	_, err := s.newClientSubnet(&ClientSubnet{Type: ClientSubnet_Source})
+	common.Must(err)
+
+	for _, ip := range []net.IP{{10, 0, 0, 1}, {10, 0, 0, 2}, {10, 0, 1, 1}} {
+		common.Must2(s.LookupIPv4ForClient("v2fly.org", ip))
+	}
+	common.Must2(s.LookupIPv4("v2fly.org"))
+
+	client.Lock()
+	defer client.Unlock()
+	if r := cmp.Diff(client.subnets, []string{"10.0.0.0", "10.0.1.0"}); r != "" {
+		t.Error(r)
+	}
+	if client.queries != 3 {
+		t.Error("expect 3 queries, but got ", client.queries)
+	}
+}
+
+func TestNewClientSubnet(t *testing.T) {
+	s := &Server{clientIP: net.IP{1, 2, 3, 4}}
+
+	subnet, err := s.newClientSubnet(nil)
+	common.Must(err)
+	if !subnet.ip.Equal(s.clientIP) {
+		t.Error("expect default client IP, but got ", subnet)
+	}
+
+	subnet, err = s.newClientSubnet(&ClientSubnet{Type: ClientSubnet_Fixed, Ip: net.ParseIP("2001:db8::")})
+	common.Must(err)
+	if subnet.prefix != 56 {
+		t.Error("expect default prefix 56, but got ", subnet.prefix)
+	}
+
+	if _, err := s.newClientSubnet(&ClientSubnet{Type: ClientSubnet_Fixed, Ip: []byte{1, 2, 3, 4}, Prefix: 33}); err == nil {
+		t.Error("expect error for invalid prefix, but nil")
+	}
+}
@@ -0,0 +1,284 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"v2ray.com/core/common/errors"
+	dns_proto "v2ray.com/core/common/protocol/dns"
+	"v2ray.com/core/common/signal/pubsub"
+	dns_feature "v2ray.com/core/features/dns"
+)
+
+// recordClient is implemented by Clients that query records of any type.
+type recordClient interface {
+	// QueryRecords sends a query of the record type to its configured server.
+	QueryRecords(ctx context.Context, domain string, recordType uint16) ([]dns_feature.Record, error)
+}
+
+// recordSet is a cacheable answer of a query for records other than A and AAAA.
+type recordSet struct {
+	ReqID   uint16
+	Records []dns_feature.Record
+	Expire  time.Time
+	RCode   dnsmessage.RCode
+}
+
+func (r *recordSet) getRecords() ([]dns_feature.Record, error) {
+	if r == nil || r.Expire.Before(time.Now()) {
+		return nil, errRecordNotFound
+	}
+	if r.RCode != dnsmessage.RCodeSuccess {
+		return nil, dns_feature.RCodeError(r.RCode)
+	}
+	if len(r.Records) == 0 {
+		return nil, dns_feature.ErrEmptyResponse
+	}
+	return r.Records, nil
+}
+
+type recordKey struct {
+	domain     string
+	recordType uint16
+}
+
+func recordTopic(domain string, recordType uint16) string {
+	return domain + "#" + strconv.Itoa(int(recordType))
+}
+
+// recordCache keeps answers of record queries of a name server.
+type recordCache struct {
+	sync.RWMutex
+	sets map[recordKey]*recordSet
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{
+		sets: make(map[recordKey]*recordSet),
+	}
+}
+
+func (c *recordCache) get(domain string, recordType uint16) ([]dns_feature.Record, error) {
+	c.RLock()
+	rs := c.sets[recordKey{domain: domain, recordType: recordType}]
+	c.RUnlock()
+
+	return rs.getRecords()
+}
+
+func (c *recordCache) update(domain string, recordType uint16, rs *recordSet) {
+	c.Lock()
+	c.sets[recordKey{domain: domain, recordType: recordType}] = rs
+	c.Unlock()
+}
+
+func (c *recordCache) len() int {
+	c.RLock()
+	defer c.RUnlock()
+
+	return len(c.sets)
+}
+
+func (c *recordCache) cleanup(now time.Time) {
+	c.Lock()
+	defer c.Unlock()
+
+	for key, rs := range c.sets {
+		if rs.Expire.Before(now) {
+			delete(c.sets, key)
+		}
+	}
+}
+
+// queryRecords returns records from cache, or calls send and waits for the answer if they are not in cache.
+func queryRecords(ctx context.Context, pub *pubsub.Service, cache *recordCache, domain string, recordType uint16, send func()) ([]dns_feature.Record, error) {
+	records, err := cache.get(domain, recordType)
+	if err != errRecordNotFound {
+		return records, err
+	}
+
+	sub := pub.Subscribe(recordTopic(domain, recordType))
+	defer sub.Close()
+	send()
+
+	for {
+		records, err := cache.get(domain, recordType)
+		if err != errRecordNotFound {
+			return records, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-sub.Wait():
+		}
+	}
+}
+
+func buildRecordReqMsg(domain string, recordType uint16, id uint16, reqOpts *dnsmessage.Resource) *dnsRequest {
+	msg := new(dnsmessage.Message)
+	msg.Header.ID = id
+	msg.Header.RecursionDesired = true
+	msg.Questions = []dnsmessage.Question{{
+		Name:  dnsmessage.MustNewName(domain),
+		Type:  dnsmessage.Type(recordType),
+		Class: dnsmessage.ClassINET,
+	}}
+	if reqOpts != nil {
+		msg.Additionals = append(msg.Additionals, *reqOpts)
+	}
+	return &dnsRequest{
+		reqType: dnsmessage.Type(recordType),
+		domain:  domain,
+		start:   time.Now(),
+		msg:     msg,
+	}
+}
+
+var errInvalidMessage = errors.New("invalid DNS message")
+
+// readName reads a possibly compressed domain name at off of msg. It returns the name in fully qualified form,
+// and the offset after the name.
+func readName(msg []byte, off int) (string, int, error) {
+	var name []byte
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errInvalidMessage
+		}
+		l := int(msg[off])
+		switch l & 0xC0 {
+		case 0x00:
+			if l == 0 {
+				if end < 0 {
+					end = off + 1
+				}
+				if len(name) == 0 {
+					name = append(name, '.')
+				}
+				return string(name), end, nil
+			}
+			if off+1+l > len(msg) {
+				return "", 0, errInvalidMessage
+			}
+			name = append(name, msg[off+1:off+1+l]...)
+			name = append(name, '.')
+			off += 1 + l
+		case 0xC0:
+			if off+2 > len(msg) {
+				return "", 0, errInvalidMessage
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			jumps++
+			if jumps > 64 {
+				return "", 0, errInvalidMessage
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+		default:
+			return "", 0, errInvalidMessage
+		}
+	}
+}
+
+// decompressRData returns RDATA at [off, end) of msg, with domain names in it decompressed.
+// Only types defined in RFC 1035 may have compressed names, so RDATA of other types is returned as is.
+func decompressRData(msg []byte, recordType dnsmessage.Type, off int, end int) ([]byte, error) {
+	var prefix, suffix, names int
+	switch recordType {
+	case dnsmessage.TypeCNAME, dnsmessage.TypeNS, dnsmessage.TypePTR:
+		names = 1
+	case dnsmessage.TypeMX:
+		prefix, names = 2, 1
+	case dnsmessage.TypeSOA:
+		names, suffix = 2, 20
+	default:
+		return append([]byte(nil), msg[off:end]...), nil
+	}
+
+	if off+prefix > end {
+		return nil, errInvalidMessage
+	}
+	data := append([]byte(nil), msg[off:off+prefix]...)
+	off += prefix
+	for i := 0; i < names; i++ {
+		name, next, err := readName(msg[:end], off)
+		if err != nil {
+			return nil, err
+		}
+		data = dns_proto.AppendName(data, name)
+		off = next
+	}
+	if off+suffix != end {
+		return nil, errInvalidMessage
+	}
+	return append(data, msg[off:end]...), nil
+}
+
+// parseRecordResponse parses all records in the answer section of a DNS response.
+func parseRecordResponse(payload []byte) (*recordSet, error) {
+	if len(payload) < 12 {
+		return nil, newError("failed to parse DNS response").Base(errInvalidMessage).AtWarning()
+	}
+
+	now := time.Now()
+	rs := &recordSet{
+		ReqID:  binary.BigEndian.Uint16(payload[0:2]),
+		RCode:  dnsmessage.RCode(binary.BigEndian.Uint16(payload[2:4]) & 0xF),
+		Expire: now.Add(time.Second * 600),
+	}
+	questions := int(binary.BigEndian.Uint16(payload[4:6]))
+	answers := int(binary.BigEndian.Uint16(payload[6:8]))
+
+	off := 12
+	for i := 0; i < questions; i++ {
+		_, next, err := readName(payload, off)
+		if err != nil || next+4 > len(payload) {
+			return nil, newError("failed to parse questions in DNS response").Base(errInvalidMessage).AtWarning()
+		}
+		off = next + 4
+	}
+
+	for i := 0; i < answers; i++ {
+		name, next, err := readName(payload, off)
+		if err != nil || next+10 > len(payload) {
+			return nil, newError("failed to parse answers in DNS response").Base(errInvalidMessage).AtWarning()
+		}
+		recordType := dnsmessage.Type(binary.BigEndian.Uint16(payload[next:]))
+		ttl := binary.BigEndian.Uint32(payload[next+4:])
+		length := int(binary.BigEndian.Uint16(payload[next+8:]))
+		off = next + 10
+		if off+length > len(payload) {
+			return nil, newError("failed to parse answers in DNS response").Base(errInvalidMessage).AtWarning()
+		}
+		data, err := decompressRData(payload, recordType, off, off+length)
+		if err != nil {
+			return nil, newError("failed to parse ", recordType, " record for domain: ", name).Base(err).AtWarning()
+		}
+		off += length
+
+		expire := now.Add(time.Duration(ttl) * time.Second)
+		if ttl == 0 {
+			expire = now.Add(time.Second * 600)
+		}
+		if rs.Expire.After(expire) {
+			rs.Expire = expire
+		}
+		rs.Records = append(rs.Records, dns_feature.Record{
+			Name: name,
+			Type: uint16(recordType),
+			TTL:  ttl,
+			Data: data,
+		})
+	}
+
+	return rs, nil
+}
@@ -0,0 +1,106 @@
+// +build !confonly
+
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"v2ray.com/core/common"
+	dns_feature "v2ray.com/core/features/dns"
+)
+
+func uncompressedRData(rr dns.RR) []byte {
+	b := make([]byte, 512)
+	off := common.Must2(dns.PackRR(rr, b, 0, nil, false)).(int)
+	return b[off-int(rr.Header().Rdlength) : off]
+}
+
+func Test_parseRecordResponse(t *testing.T) {
+	rrs := []dns.RR{
+		common.Must2(dns.NewRR("v2ray.com. 300 IN CNAME www.v2ray.com.")).(dns.RR),
+		common.Must2(dns.NewRR("v2ray.com. 100 IN MX 10 mail.v2ray.com.")).(dns.RR),
+		common.Must2(dns.NewRR("v2ray.com. 200 IN TXT \"v=spf1 -all\"")).(dns.RR),
+		common.Must2(dns.NewRR("_sip._tcp.v2ray.com. 200 IN SRV 0 5 5060 sip.v2ray.com.")).(dns.RR),
+		common.Must2(dns.NewRR("v2ray.com. 200 IN SOA ns.v2ray.com. admin.v2ray.com. 1 2 3 4 5")).(dns.RR),
+	}
+
+	ans := new(dns.Msg)
+	ans.Id = 7
+	ans.Compress = true
+	ans.Question = []dns.Question{{Name: "v2ray.com.", Qtype: dns.TypeMX, Qclass: dns.ClassINET}}
+	ans.Answer = rrs
+	payload := common.Must2(ans.Pack()).([]byte)
+
+	rs, err := parseRecordResponse(payload)
+	common.Must(err)
+
+	if rs.ReqID != 7 || rs.RCode != dnsmessage.RCodeSuccess {
+		t.Error("unexpected header: ", rs.ReqID, " ", rs.RCode)
+	}
+	if d := time.Until(rs.Expire); d > 100*time.Second || d < 90*time.Second {
+		t.Error("expect expire in the minimum TTL, but got ", d)
+	}
+
+	var want []dns_feature.Record
+	for _, rr := range rrs {
+		want = append(want, dns_feature.Record{
+			Name: rr.Header().Name,
+			Type: rr.Header().Rrtype,
+			TTL:  rr.Header().Ttl,
+			Data: uncompressedRData(rr),
+		})
+	}
+	if r := cmp.Diff(rs.Records, want); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := parseRecordResponse(payload[:len(payload)-3]); err == nil {
+		t.Error("expect error for truncated response, but nil")
+	}
+}
+
+func TestRecordCache(t *testing.T) {
+	c := newRecordCache()
+
+	if _, err := c.get("v2ray.com.", uint16(dnsmessage.TypeMX)); err != errRecordNotFound {
+		t.Error("expect record not found, but got ", err)
+	}
+
+	c.update("v2ray.com.", uint16(dnsmessage.TypeMX), &recordSet{
+		Records: []dns_feature.Record{{Name: "v2ray.com.", Type: uint16(dnsmessage.TypeMX)}},
+		Expire:  time.Now().Add(time.Hour),
+	})
+	c.update("v2ray.com.", uint16(dnsmessage.TypeTXT), &recordSet{
+		Expire: time.Now().Add(time.Hour),
+	})
+	c.update("v2ray.com.", uint16(dnsmessage.TypeSRV), &recordSet{
+		RCode:  dnsmessage.RCodeNameError,
+		Expire: time.Now().Add(time.Hour),
+	})
+	c.update("v2fly.org.", uint16(dnsmessage.TypeMX), &recordSet{
+		Expire: time.Now().Add(-time.Second),
+	})
+
+	if records, err := c.get("v2ray.com.", uint16(dnsmessage.TypeMX)); err != nil || len(records) != 1 {
+		t.Error("expect 1 record, but got ", records, " ", err)
+	}
+	if _, err := c.get("v2ray.com.", uint16(dnsmessage.TypeTXT)); err != dns_feature.ErrEmptyResponse {
+		t.Error("expect empty response, but got ", err)
+	}
+	if _, err := c.get("v2ray.com.", uint16(dnsmessage.TypeSRV)); dns_feature.RCodeFromError(err) != uint16(dnsmessage.RCodeNameError) {
+		t.Error("expect name error, but got ", err)
+	}
+	if _, err := c.get("v2fly.org.", uint16(dnsmessage.TypeMX)); err != errRecordNotFound {
+		t.Error("expect expired record not found, but got ", err)
+	}
+
+	c.cleanup(time.Now())
+	if n := c.len(); n != 3 {
+		t.Error("expect 3 record sets after cleanup, but got ", n)
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"context"
 	"log"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"golang.org/x/net/dns/dnsmessage"
+
 	"v2ray.com/core"
 	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
@@ -19,10 +22,12 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/strmatcher"
+	"v2ray.com/core/common/task"
 	"v2ray.com/core/common/uuid"
 	"v2ray.com/core/features"
 	"v2ray.com/core/features/dns"
 	"v2ray.com/core/features/routing"
+	"v2ray.com/core/features/stats"
 )
 
 // Server is a DNS rely server.
@@ -34,7 +39,39 @@ type Server struct {
 	domainMatcher  strmatcher.IndexMatcher
 	domainIndexMap map[uint32]uint32
 	ipIndexMap     map[uint32]*MultiGeoIPMatcher
+	ruleMatcher    strmatcher.IndexMatcher
+	rules          map[uint32]*domainRule
+	concurrent     bool
+	sourceSubnet   bool
 	tag            string
+	cache          *ipCache
+	cachePath      string
+	serveStale     bool
+	prefetching    map[string]bool
+	cleanup        *task.Periodic
+	fakeDNS        *fakeDNS
+	counters       cacheCounters
+}
+
+// cacheCounters count lookups answered from the cache.
+type cacheCounters struct {
+	hit      stats.Counter
+	miss     stats.Counter
+	prefetch stats.Counter
+	stale    stats.Counter
+}
+
+func incCounter(c stats.Counter) {
+	if c != nil {
+		c.Add(1)
+	}
+}
+
+// domainRule selects name servers for matched domains.
+type domainRule struct {
+	// clients are indices of name servers in Server.clients, queried in order.
+	clients  []uint32
+	fallback bool
 }
 
 // MultiGeoIPMatcher for match
@@ -67,8 +104,14 @@ func generateRandomTag() string {
 // New creates a new DNS server with given configuration.
 func New(ctx context.Context, config *Config) (*Server, error) {
 	server := &Server{
-		clients: make([]Client, 0, len(config.NameServers)+len(config.NameServer)),
-		tag:     config.Tag,
+		clients:    make([]Client, 0, len(config.NameServers)+len(config.NameServer)),
+		tag:        config.Tag,
+		cache:      newIPCache(),
+		concurrent: config.ConcurrentQuery,
+	}
+	server.cleanup = &task.Periodic{
+		Interval: time.Minute,
+		Execute:  server.cleanupCache,
 	}
 	if server.tag == "" {
 		server.tag = generateRandomTag()
@@ -80,13 +123,51 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 		server.clientIP = net.IP(config.ClientIp)
 	}
 
+	if cc := config.Cache; cc != nil {
+		server.cachePath = cc.Path
+		server.serveStale = cc.ServeStale
+		if cc.ServeStale {
+			server.cache.maxStale = time.Duration(cc.MaxStale) * time.Second
+			if cc.MaxStale == 0 {
+				server.cache.maxStale = time.Hour * 24
+			}
+		}
+		if cc.Prefetch {
+			server.cache.prefetchHits = int32(cc.PrefetchHits)
+			if cc.PrefetchHits == 0 {
+				server.cache.prefetchHits = 2
+			}
+			server.prefetching = make(map[string]bool)
+		}
+		if len(cc.Path) > 0 {
+			if err := server.cache.load(cc.Path); err != nil {
+				newError("failed to load DNS cache").Base(err).AtWarning().WriteToLog()
+			}
+		}
+	}
+
+	common.Must(core.RequireFeatures(ctx, func(sm stats.Manager) {
+		server.counters.hit, _ = stats.GetOrRegisterCounter(sm, "dns>>>cache>>>hit")
+		server.counters.miss, _ = stats.GetOrRegisterCounter(sm, "dns>>>cache>>>miss")
+		server.counters.prefetch, _ = stats.GetOrRegisterCounter(sm, "dns>>>cache>>>prefetch")
+		server.counters.stale, _ = stats.GetOrRegisterCounter(sm, "dns>>>cache>>>stale")
+	}))
+
+	if config.FakeDns != nil {
+		fakeDNS, err := newFakeDNS(config.FakeDns)
+		if err != nil {
+			return nil, newError("failed to create fake DNS").Base(err)
+		}
+		server.fakeDNS = fakeDNS
+	}
+
 	hosts, err := NewStaticHosts(config.StaticHosts, config.Hosts)
 	if err != nil {
 		return nil, newError("failed to create hosts").Base(err)
 	}
 	server.hosts = hosts
 
-	addNameServer := func(endpoint *net.Endpoint) int {
+	addNameServer := func(endpoint *net.Endpoint, subnet clientSubnet) int {
 		address := endpoint.Address.AsAddress()
 		if address.Family().IsDomain() && address.Domain() == "localhost" {
 			server.clients = append(server.clients, NewLocalNameServer())
@@ -97,7 +178,9 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 			if err != nil {
 				log.Fatalln(newError("DNS config error").Base(err))
 			}
-			server.clients = append(server.clients, NewDoHLocalNameServer(u, server.clientIP))
+			c := NewDoHLocalNameServer(u, server.clientIP)
+			c.subnet = subnet
+			server.clients = append(server.clients, c)
 		} else if address.Family().IsDomain() &&
 			strings.HasPrefix(address.Domain(), "https://") {
 			// DOH Remote mode
@@ -114,6 +197,31 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 				if err != nil {
 					log.Fatalln(newError("DNS config error").Base(err))
 				}
+				c.subnet = subnet
+				server.clients[idx] = c
+			}))
+		} else if address.Family().IsDomain() &&
+			(strings.HasPrefix(address.Domain(), "tls://") || strings.HasPrefix(address.Domain(), "quic://")) {
+			// DOT and DOQ Remote mode
+			u, err := url.Parse(address.Domain())
+			if err != nil {
+				log.Fatalln(newError("DNS config error").Base(err))
+			}
+			idx := len(server.clients)
+			server.clients = append(server.clients, nil)
+
+			common.Must(core.RequireFeatures(ctx, func(d routing.Dispatcher) {
+				var c *ClassicNameServer
+				var err error
+				if u.Scheme == "tls" {
+					c, err = NewDoTNameServer(u, d, server.clientIP)
+				} else {
+					c, err = NewDoQNameServer(u, d, server.clientIP)
+				}
+				if err != nil {
+					log.Fatalln(newError("DNS config error").Base(err))
+				}
+				c.subnet = subnet
 				server.clients[idx] = c
 			}))
 		} else {
@@ -127,7 +235,9 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 				server.clients = append(server.clients, nil)
 
 				common.Must(core.RequireFeatures(ctx, func(d routing.Dispatcher) {
-					server.clients[idx] = NewClassicNameServer(dest, d, server.clientIP)
+					c := NewClassicNameServer(dest, d, server.clientIP)
+					c.subnet = subnet
+					server.clients[idx] = c
 				}))
 			}
 		}
@@ -137,7 +247,7 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 	if len(config.NameServers) > 0 {
 		features.PrintDeprecatedFeatureWarning("simple DNS server")
 		for _, destPB := range config.NameServers {
-			addNameServer(destPB)
+			addNameServer(destPB, clientSubnet{ip: server.clientIP})
 		}
 	}
 
@@ -146,9 +256,15 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 		domainIndexMap := make(map[uint32]uint32)
 		ipIndexMap := make(map[uint32]*MultiGeoIPMatcher)
 		var geoIPMatcherContainer router.GeoIPMatcherContainer
+		clientIndices := make([]uint32, 0, len(config.NameServer))
 
 		for _, ns := range config.NameServer {
-			idx := addNameServer(ns.Address)
+			subnet, err := server.newClientSubnet(ns.ClientSubnet)
+			if err != nil {
+				return nil, newError("invalid client subnet of name server ", ns.Address.Address.AsAddress()).Base(err)
+			}
+			idx := addNameServer(ns.Address, subnet)
+			clientIndices = append(clientIndices, uint32(idx))
 
 			for _, domain := range ns.PrioritizedDomain {
 				matcher, err := toStrMatcher(domain.Type, domain.Domain)
@@ -177,6 +293,29 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 		server.domainMatcher = domainMatcher
 		server.domainIndexMap = domainIndexMap
 		server.ipIndexMap = ipIndexMap
+
+		if len(config.DomainRule) > 0 {
+			ruleMatcher := &strmatcher.MatcherGroup{}
+			rules := make(map[uint32]*domainRule)
+			for _, r := range config.DomainRule {
+				rule := &domainRule{fallback: r.Fallback}
+				for _, i := range r.NameServer {
+					if int(i) >= len(clientIndices) {
+						return nil, newError("name server ", i, " in domain rule doesn't exist")
+					}
+					rule.clients = append(rule.clients, clientIndices[i])
+				}
+				for _, domain := range r.Domain {
+					matcher, err := toStrMatcher(domain.Type, domain.Domain)
+					if err != nil {
+						return nil, newError("failed to create domain rule").Base(err)
+					}
+					rules[ruleMatcher.Add(matcher)] = rule
+				}
+			}
+			server.ruleMatcher = ruleMatcher
+			server.rules = rules
+		}
 	}
 
 	if len(server.clients) == 0 {
@@ -186,6 +325,36 @@ func New(ctx context.Context, config *Config) (*Server, error) {
 	return server, nil
 }
 
+// newClientSubnet returns the EDNS Client Subnet of a name server. Config.client_ip is used if config is nil.
+func (s *Server) newClientSubnet(config *ClientSubnet) (clientSubnet, error) {
+	if config == nil {
+		return clientSubnet{ip: s.clientIP}, nil
+	}
+
+	switch config.Type {
+	case ClientSubnet_Off:
+		return clientSubnet{}, nil
+	case ClientSubnet_Fixed:
+		ip := net.IP(config.Ip)
+		if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+			return clientSubnet{}, newError("unexpected IP length ", len(ip))
+		}
+		prefix := int(config.Prefix)
+		if prefix == 0 {
+			_, prefix = sourceSubnet(ip)
+		}
+		if prefix > len(ip)*8 {
+			return clientSubnet{}, newError("invalid prefix ", prefix)
+		}
+		return clientSubnet{ip: ip, prefix: prefix}, nil
+	case ClientSubnet_Source:
+		s.sourceSubnet = true
+		return clientSubnet{bySource: true}, nil
+	default:
+		return clientSubnet{}, newError("unknown type ", config.Type)
+	}
+}
+
 // Type implements common.HasType.
 func (*Server) Type() interface{} {
 	return dns.ClientType()
@@ -193,11 +362,29 @@ func (*Server) Type() interface{} {
 
 // Start implements common.Runnable.
 func (s *Server) Start() error {
-	return nil
+	return s.cleanup.Start()
 }
 
 // Close implements common.Closable.
 func (s *Server) Close() error {
+	err := s.cleanup.Close()
+	if len(s.cachePath) > 0 {
+		if saveErr := s.cache.save(s.cachePath); saveErr != nil {
+			newError("failed to save DNS cache").Base(saveErr).AtWarning().WriteToLog()
+		}
+	}
+	return err
+}
+
+func (s *Server) cleanupCache() error {
+	if err := s.cache.Cleanup(); err != nil {
+		return err
+	}
+	if len(s.cachePath) > 0 {
+		if err := s.cache.save(s.cachePath); err != nil {
+			newError("failed to save DNS cache").Base(err).AtWarning().WriteToLog()
+		}
+	}
 	return nil
 }
 
@@ -231,8 +418,8 @@ func (s *Server) Match(idx uint32, client Client, domain string, ips []net.IP) (
 	return newIps, nil
 }
 
-func (s *Server) queryIPTimeout(idx uint32, client Client, domain string, option IPOption) ([]net.IP, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+func (s *Server) queryIPTimeout(ctx context.Context, idx uint32, client Client, domain string, option IPOption) ([]net.IP, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*4)
 	if len(s.tag) > 0 {
 		ctx = session.ContextWithInbound(ctx, &session.Inbound{
 			Tag: s.tag,
@@ -246,6 +433,15 @@ func (s *Server) queryIPTimeout(idx uint32, client Client, domain string, option
 	}
 
 	ips, err = s.Match(idx, client, domain, ips)
+	if err == nil && len(ips) > 0 {
+		expire := time.Now().Add(defaultCacheTTL)
+		if c, ok := client.(cachedClient); ok {
+			if t, found := c.expireTime(ctx, domain, option); found {
+				expire = t
+			}
+		}
+		s.cache.update(s.cacheKey(ctx, domain), option, ips, expire)
+	}
 	return ips, err
 }
 
@@ -276,6 +472,79 @@ func (s *Server) LookupIPv6(domain string) ([]net.IP, error) {
 	})
 }
 
+// LookupIPv4ForClient implements dns.ClientLookup.
+func (s *Server) LookupIPv4ForClient(domain string, clientIP net.IP) ([]net.IP, error) {
+	newDebugMsg("app: querying IPv4 for " + domain + " on behalf of " + clientIP.String())
+	return s.lookupIP(contextWithClientIP(context.Background(), clientIP), domain, IPOption{
+		IPv4Enable: true,
+		IPv6Enable: false,
+	})
+}
+
+// LookupIPv6ForClient implements dns.ClientLookup.
+func (s *Server) LookupIPv6ForClient(domain string, clientIP net.IP) ([]net.IP, error) {
+	newDebugMsg("app: querying IPv6 for " + domain + " on behalf of " + clientIP.String())
+	return s.lookupIP(contextWithClientIP(context.Background(), clientIP), domain, IPOption{
+		IPv4Enable: false,
+		IPv6Enable: true,
+	})
+}
+
+// cacheKey returns the key of answers in the cache. If any name server sends the subnet of clients, answers on behalf
+// of clients are cached per subnet.
+func (s *Server) cacheKey(ctx context.Context, domain string) string {
+	if ip := clientIPFromContext(ctx); ip != nil && s.sourceSubnet {
+		subnet, _ := sourceSubnet(ip)
+		return domain + "@" + subnet.String()
+	}
+	return domain
+}
+
+// IsFakeDNSEnabled implements dns.FakeDNSEngine.
+func (s *Server) IsFakeDNSEnabled() bool {
+	return s.fakeDNS != nil
+}
+
+// GetFakeIPForDomain implements dns.FakeDNSEngine.
+func (s *Server) GetFakeIPForDomain(domain string, ipv6 bool) net.IP {
+	if s.fakeDNS == nil {
+		return nil
+	}
+	return s.fakeDNS.getFakeIP(domain, ipv6)
+}
+
+// GetDomainFromFakeIP implements dns.FakeDNSEngine.
+func (s *Server) GetDomainFromFakeIP(ip net.IP) string {
+	if s.fakeDNS == nil {
+		return ""
+	}
+	return s.fakeDNS.getDomain(ip)
+}
+
+// IsFakeIP implements dns.FakeDNSEngine.
+func (s *Server) IsFakeIP(ip net.IP) bool {
+	return s.fakeDNS != nil && s.fakeDNS.contains(ip)
+}
+
+// LookupCachedIP implements dns.CachedLookup.
+func (s *Server) LookupCachedIP(domain string) ([]net.IP, bool) {
+	domain = strings.TrimSuffix(domain, ".")
+	option := IPOption{
+		IPv4Enable: true,
+		IPv6Enable: true,
+	}
+
+	ips := s.lookupStatic(domain, option, 0)
+	if ips != nil && ips[0].Family().IsIP() {
+		return toNetIP(ips), true
+	}
+	if ips != nil && ips[0].Family().IsDomain() {
+		domain = ips[0].Domain()
+	}
+
+	return s.cache.lookup(domain, option)
+}
+
 func (s *Server) lookupStatic(domain string, option IPOption, depth int32) []net.Address {
 	ips := s.hosts.LookupIP(domain, option)
 	if ips == nil {
@@ -300,7 +569,102 @@ func toNetIP(ips []net.Address) []net.IP {
 	return netips
 }
 
+// LookupRecords implements dns.RecordLookup.
+func (s *Server) LookupRecords(domain string, recordType uint16) ([]dns.Record, error) {
+	newDebugMsg("app: querying records of type " + strconv.Itoa(int(recordType)) + " for " + domain)
+	switch dnsmessage.Type(recordType) {
+	case dnsmessage.TypeA:
+		return s.lookupIPRecords(domain, IPOption{IPv4Enable: true}, recordType)
+	case dnsmessage.TypeAAAA:
+		return s.lookupIPRecords(domain, IPOption{IPv6Enable: true}, recordType)
+	}
+
+	if domain == "" {
+		return nil, newError("empty domain name")
+	}
+	domain = strings.TrimSuffix(domain, ".")
+
+	ips := s.lookupStatic(domain, IPOption{IPv4Enable: true, IPv6Enable: true}, 0)
+	if ips != nil && ips[0].Family().IsIP() {
+		return nil, dns.ErrEmptyResponse
+	}
+	if ips != nil && ips[0].Family().IsDomain() {
+		newdomain := ips[0].Domain()
+		newError("domain replaced: ", domain, " -> ", newdomain).WriteToLog()
+		domain = newdomain
+	}
+
+	var lastErr error
+	for _, group := range s.queryPlan(domain) {
+		for _, idx := range group {
+			c, ok := s.clients[idx].(recordClient)
+			if !ok {
+				continue
+			}
+
+			records, err := s.queryRecordsTimeout(c, domain, recordType)
+			if err == nil {
+				return records, nil
+			}
+
+			newError("failed to lookup records for domain ", domain, " at server ", s.clients[idx].Name()).Base(err).WriteToLog()
+			lastErr = err
+			if isFinalError(err) {
+				return nil, err
+			}
+		}
+	}
+
+	return nil, newError("returning nil for domain ", domain).Base(lastErr)
+}
+
+func (s *Server) queryRecordsTimeout(client recordClient, domain string, recordType uint16) ([]dns.Record, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*4)
+	defer cancel()
+
+	if len(s.tag) > 0 {
+		ctx = session.ContextWithInbound(ctx, &session.Inbound{
+			Tag: s.tag,
+		})
+	}
+	return client.QueryRecords(ctx, domain, recordType)
+}
+
+// lookupIPRecords returns A or AAAA records of the domain, resolved in the same way as LookupIP.
+func (s *Server) lookupIPRecords(domain string, option IPOption, recordType uint16) ([]dns.Record, error) {
+	ips, err := s.lookupIPInternal(domain, option)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]dns.Record, 0, len(ips))
+	for _, ip := range ips {
+		if option.IPv4Enable {
+			ip = ip.To4()
+		} else if len(ip) != net.IPv6len {
+			continue
+		}
+		if ip == nil {
+			continue
+		}
+		records = append(records, dns.Record{
+			Name: Fqdn(domain),
+			Type: recordType,
+			TTL:  600,
+			Data: []byte(ip),
+		})
+	}
+	if len(records) == 0 {
+		return nil, dns.ErrEmptyResponse
+	}
+	return records, nil
+}
+
 func (s *Server) lookupIPInternal(domain string, option IPOption) ([]net.IP, error) {
+	return s.lookupIP(context.Background(), domain, option)
+}
+
+func (s *Server) lookupIP(ctx context.Context, domain string, option IPOption) ([]net.IP, error) {
 	if domain == "" {
 		return nil, newError("empty domain name")
 	}
@@ -327,33 +691,146 @@ func (s *Server) lookupIPInternal(domain string, option IPOption) ([]net.IP, err
 		domain = newdomain
 	}
 
+	key := s.cacheKey(ctx, domain)
+	if ips, prefetch, found := s.cache.get(key, option); found {
+		incCounter(s.counters.hit)
+		if prefetch {
+			s.prefetchIP(ctx, domain, option)
+		}
+		return ips, nil
+	}
+	incCounter(s.counters.miss)
+
+	netips, err := s.queryIP(ctx, domain, option)
+	if err != nil && s.serveStale && canServeStale(err) {
+		if ips, found := s.cache.getStale(key, option); found {
+			incCounter(s.counters.stale)
+			newError("serving stale answer for domain ", domain).Base(err).AtInfo().WriteToLog()
+			return ips, nil
+		}
+	}
+	return netips, err
+}
+
+// canServeStale returns true if the error is a failure of resolving, rather than an answer that the domain has no IP.
+func canServeStale(err error) bool {
+	if errors.Cause(err) == dns.ErrEmptyResponse {
+		return false
+	}
+	return dns.RCodeFromError(err) != uint16(dnsmessage.RCodeNameError)
+}
+
+// prefetchIP refreshes answers of the domain in background, unless they are already being refreshed.
+func (s *Server) prefetchIP(ctx context.Context, domain string, option IPOption) {
+	key := s.cacheKey(ctx, domain)
+	if option.IPv4Enable {
+		key += "#4"
+	}
+	if option.IPv6Enable {
+		key += "#6"
+	}
+
+	s.Lock()
+	if s.prefetching[key] {
+		s.Unlock()
+		return
+	}
+	s.prefetching[key] = true
+	s.Unlock()
+
+	incCounter(s.counters.prefetch)
+	go func() {
+		if _, err := s.queryIP(contextWithCacheBypassed(ctx), domain, option); err != nil {
+			newError("failed to prefetch IP for domain ", domain).Base(err).AtDebug().WriteToLog()
+		}
+		s.Lock()
+		delete(s.prefetching, key)
+		s.Unlock()
+	}()
+}
+
+// queryIP queries name servers selected for the domain.
+func (s *Server) queryIP(ctx context.Context, domain string, option IPOption) ([]net.IP, error) {
 	var lastErr error
-	var matchedClient Client
-	if s.domainMatcher != nil {
-		idx := s.domainMatcher.Match(domain)
-		if idx > 0 {
-			matchedClient = s.clients[s.domainIndexMap[idx]]
-			ips, err := s.queryIPTimeout(s.domainIndexMap[idx], matchedClient, domain, option)
-			if len(ips) > 0 {
-				return ips, nil
-			}
-			if err == dns.ErrEmptyResponse {
-				return nil, err
-			}
-			if err != nil {
-				newError("failed to lookup ip for domain ", domain, " at server ", matchedClient.Name()).Base(err).WriteToLog()
-				lastErr = err
-			}
+	for _, group := range s.queryPlan(domain) {
+		var ips []net.IP
+		var err error
+		if s.concurrent {
+			ips, err = s.queryIPConcurrently(ctx, group, domain, option)
+		} else {
+			ips, err = s.queryIPSequentially(ctx, group, domain, option)
+		}
+		if len(ips) > 0 {
+			return ips, nil
+		}
+		if err != nil {
+			lastErr = err
+		}
+		if isFinalError(err) {
+			return nil, err
 		}
 	}
 
-	for idx, client := range s.clients {
-		if client == matchedClient {
-			newError("domain ", domain, " at server ", client.Name(), " idx:", idx, " already lookup failed, just ignore").AtDebug().WriteToLog()
-			continue
+	return nil, newError("returning nil for domain ", domain).Base(lastErr)
+}
+
+// queryPlan returns indices of clients to query for the domain, in groups. A group is only queried if
+// none of the clients in previous groups answers.
+func (s *Server) queryPlan(domain string) [][]uint32 {
+	var selected []uint32
+	fallback := true
+	if s.ruleMatcher != nil {
+		if idx := s.ruleMatcher.Match(domain); idx > 0 {
+			rule := s.rules[idx]
+			selected = rule.clients
+			fallback = rule.fallback
+		}
+	}
+	if selected == nil && s.domainMatcher != nil {
+		if idx := s.domainMatcher.Match(domain); idx > 0 {
+			selected = []uint32{s.domainIndexMap[idx]}
+		}
+	}
+
+	var plan [][]uint32
+	if len(selected) > 0 {
+		plan = append(plan, selected)
+	}
+	if !fallback {
+		return plan
+	}
+
+	others := make([]uint32, 0, len(s.clients))
+	for idx := range s.clients {
+		if !containsIndex(selected, uint32(idx)) {
+			others = append(others, uint32(idx))
+		}
+	}
+	if len(others) > 0 {
+		plan = append(plan, others)
+	}
+	return plan
+}
+
+func containsIndex(indices []uint32, idx uint32) bool {
+	for _, i := range indices {
+		if i == idx {
+			return true
 		}
+	}
+	return false
+}
+
+// isFinalError returns true if the error is an answer from name servers, so that other name servers are not queried.
+func isFinalError(err error) bool {
+	return err != nil && err != context.Canceled && err != context.DeadlineExceeded && err != errExpectedIPNonMatch
+}
 
-		ips, err := s.queryIPTimeout(uint32(idx), client, domain, option)
+func (s *Server) queryIPSequentially(ctx context.Context, group []uint32, domain string, option IPOption) ([]net.IP, error) {
+	var lastErr error
+	for _, idx := range group {
+		client := s.clients[idx]
+		ips, err := s.queryIPTimeout(ctx, idx, client, domain, option)
 		if len(ips) > 0 {
 			return ips, nil
 		}
@@ -362,12 +839,51 @@ func (s *Server) lookupIPInternal(domain string, option IPOption) ([]net.IP, err
 			newError("failed to lookup ip for domain ", domain, " at server ", client.Name()).Base(err).WriteToLog()
 			lastErr = err
 		}
-		if err != context.Canceled && err != context.DeadlineExceeded && err != errExpectedIPNonMatch {
+		if isFinalError(err) {
 			return nil, err
 		}
 	}
+	return nil, lastErr
+}
 
-	return nil, newError("returning nil for domain ", domain).Base(lastErr)
+// queryIPConcurrently queries all clients in the group at the same time, and returns the first valid answer.
+// It returns a final error only if none of the clients answers.
+func (s *Server) queryIPConcurrently(ctx context.Context, group []uint32, domain string, option IPOption) ([]net.IP, error) {
+	type result struct {
+		ips []net.IP
+		err error
+	}
+
+	results := make(chan result, len(group))
+	for _, idx := range group {
+		idx := idx
+		go func() {
+			client := s.clients[idx]
+			ips, err := s.queryIPTimeout(ctx, idx, client, domain, option)
+			if err != nil {
+				newError("failed to lookup ip for domain ", domain, " at server ", client.Name()).Base(err).WriteToLog()
+			}
+			results <- result{ips: ips, err: err}
+		}()
+	}
+
+	var lastErr, finalErr error
+	for range group {
+		r := <-results
+		if len(r.ips) > 0 {
+			return r.ips, nil
+		}
+		if r.err != nil {
+			lastErr = r.err
+		}
+		if isFinalError(r.err) && finalErr == nil {
+			finalErr = r.err
+		}
+	}
+	if finalErr != nil {
+		return nil, finalErr
+	}
+	return nil, lastErr
 }
 
 func init() {
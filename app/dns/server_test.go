@@ -167,6 +167,12 @@ func TestUDPServer(t *testing.T) {
 
 	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
 
+	{
+		if ips, found := client.(feature_dns.CachedLookup).LookupCachedIP("google.com"); found {
+			t.Fatal("unexpected cached ips: ", ips)
+		}
+	}
+
 	{
 		ips, err := client.LookupIP("google.com")
 		if err != nil {
@@ -178,6 +184,17 @@ func TestUDPServer(t *testing.T) {
 		}
 	}
 
+	{
+		ips, found := client.(feature_dns.CachedLookup).LookupCachedIP("google.com.")
+		if !found {
+			t.Fatal("ips of google.com not cached")
+		}
+
+		if r := cmp.Diff(ips, []net.IP{{8, 8, 8, 8}}); r != "" {
+			t.Fatal(r)
+		}
+	}
+
 	{
 		ips, err := client.LookupIP("facebook.com")
 		if err != nil {
@@ -11,6 +11,7 @@ import (
 
 	"golang.org/x/net/dns/dnsmessage"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol/dns"
 	udp_proto "v2ray.com/core/common/protocol/udp"
@@ -22,17 +23,23 @@ import (
 	"v2ray.com/core/transport/internet/udp"
 )
 
+// messageDispatcher sends DNS messages to name servers. Responses are handled by a callback.
+type messageDispatcher interface {
+	Dispatch(ctx context.Context, destination net.Destination, payload *buf.Buffer)
+}
+
 type ClassicNameServer struct {
 	sync.RWMutex
 	name      string
 	address   net.Destination
 	ips       map[string]record
+	records   *recordCache
 	requests  map[uint16]dnsRequest
 	pub       *pubsub.Service
-	udpServer *udp.Dispatcher
+	transport messageDispatcher
 	cleanup   *task.Periodic
 	reqID     uint32
-	clientIP  net.IP
+	subnet    clientSubnet
 }
 
 func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher, clientIP net.IP) *ClassicNameServer {
@@ -42,20 +49,26 @@ func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher
 		address.Port = net.Port(53)
 	}
 
+	s := baseClassicNameServer(address, strings.ToUpper(address.String()), clientIP)
+	s.transport = udp.NewDispatcher(dispatcher, s.HandleResponse)
+	newError("DNS: created udp client inited for ", address.NetAddr()).AtInfo().WriteToLog()
+	return s
+}
+
+func baseClassicNameServer(address net.Destination, name string, clientIP net.IP) *ClassicNameServer {
 	s := &ClassicNameServer{
 		address:  address,
 		ips:      make(map[string]record),
+		records:  newRecordCache(),
 		requests: make(map[uint16]dnsRequest),
-		clientIP: clientIP,
+		subnet:   clientSubnet{ip: clientIP},
 		pub:      pubsub.NewService(),
-		name:     strings.ToUpper(address.String()),
+		name:     name,
 	}
 	s.cleanup = &task.Periodic{
 		Interval: time.Minute,
 		Execute:  s.Cleanup,
 	}
-	s.udpServer = udp.NewDispatcher(dispatcher, s.HandleResponse)
-	newError("DNS: created udp client inited for ", address.NetAddr()).AtInfo().WriteToLog()
 	return s
 }
 
@@ -68,10 +81,12 @@ func (s *ClassicNameServer) Cleanup() error {
 	s.Lock()
 	defer s.Unlock()
 
-	if len(s.ips) == 0 && len(s.requests) == 0 {
+	if len(s.ips) == 0 && len(s.requests) == 0 && s.records.len() == 0 {
 		return newError(s.name, " nothing to do. stopping...")
 	}
 
+	s.records.cleanup(now)
+
 	for domain, record := range s.ips {
 		if record.A != nil && record.A.Expire.Before(now) {
 			record.A = nil
@@ -105,6 +120,10 @@ func (s *ClassicNameServer) Cleanup() error {
 }
 
 func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_proto.Packet) {
+	if req, ok := s.pendingRecordRequest(packet.Payload.Bytes()); ok {
+		s.handleRecordResponse(req, packet.Payload.Bytes())
+		return
+	}
 
 	ipRec, err := parseResponse(packet.Payload.Bytes())
 	if err != nil {
@@ -135,35 +154,31 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 
 	elapsed := time.Since(req.start)
 	newError(s.name, " got answere: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
-	if len(req.domain) > 0 && (rec.A != nil || rec.AAAA != nil) {
-		s.updateIP(req.domain, rec)
+	if len(req.key) > 0 && (rec.A != nil || rec.AAAA != nil) {
+		s.updateIP(req.key, rec)
 	}
 }
 
-func (s *ClassicNameServer) updateIP(domain string, newRec record) {
+// updateIP replaces cached answers under the key with the new answers, and notifies the subscribers of the key.
+func (s *ClassicNameServer) updateIP(key string, newRec record) {
 	s.Lock()
 
-	newError(s.name, " updating IP records for domain:", domain).AtDebug().WriteToLog()
-	rec := s.ips[domain]
+	newError(s.name, " updating IP records for domain:", key).AtDebug().WriteToLog()
+	rec := s.ips[key]
 
-	updated := false
-	if isNewer(rec.A, newRec.A) {
+	if newRec.A != nil {
 		rec.A = newRec.A
-		updated = true
 	}
-	if isNewer(rec.AAAA, newRec.AAAA) {
+	if newRec.AAAA != nil {
 		rec.AAAA = newRec.AAAA
-		updated = true
 	}
 
-	if updated {
-		s.ips[domain] = rec
-	}
+	s.ips[key] = rec
 	if newRec.A != nil {
-		s.pub.Publish(domain+"4", nil)
+		s.pub.Publish(key+"4", nil)
 	}
 	if newRec.AAAA != nil {
-		s.pub.Publish(domain+"6", nil)
+		s.pub.Publish(key+"6", nil)
 	}
 	s.Unlock()
 	common.Must(s.cleanup.Start())
@@ -185,9 +200,11 @@ func (s *ClassicNameServer) addPendingRequest(req *dnsRequest) {
 func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, option IPOption) {
 	newError(s.name, " querying DNS for: ", domain).AtDebug().WriteToLog(session.ExportIDToError(ctx))
 
-	reqs := buildReqMsgs(domain, option, s.newReqID, genEDNS0Options(s.clientIP))
+	key := s.subnet.cacheKey(ctx, domain)
+	reqs := buildReqMsgs(domain, option, s.newReqID, s.subnet.options(ctx))
 
 	for _, req := range reqs {
+		req.key = key
 		s.addPendingRequest(req)
 		b, _ := dns.PackMessage(req.msg)
 		udpCtx := context.Background()
@@ -197,8 +214,20 @@ func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, option
 		udpCtx = session.ContextWithContent(udpCtx, &session.Content{
 			Protocol: "dns",
 		})
-		s.udpServer.Dispatch(udpCtx, s.address, b)
+		s.transport.Dispatch(udpCtx, s.address, b)
+	}
+}
+
+// expireTime implements cachedClient.
+func (s *ClassicNameServer) expireTime(ctx context.Context, domain string, option IPOption) (time.Time, bool) {
+	s.RLock()
+	record, found := s.ips[s.subnet.cacheKey(ctx, Fqdn(domain))]
+	s.RUnlock()
+
+	if !found {
+		return time.Time{}, false
 	}
+	return record.expire(option)
 }
 
 func (s *ClassicNameServer) findIPsForDomain(domain string, option IPOption) ([]net.IP, error) {
@@ -242,21 +271,26 @@ func (s *ClassicNameServer) findIPsForDomain(domain string, option IPOption) ([]
 func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option IPOption) ([]net.IP, error) {
 
 	fqdn := Fqdn(domain)
+	// Answers for a client are cached per subnet, and not shared with others.
+	key := s.subnet.cacheKey(ctx, fqdn)
 
-	ips, err := s.findIPsForDomain(fqdn, option)
-	if err != errRecordNotFound {
-		newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
-		return ips, err
+	bypassed := isCacheBypassed(ctx)
+	if !bypassed {
+		ips, err := s.findIPsForDomain(key, option)
+		if err != errRecordNotFound {
+			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			return ips, err
+		}
 	}
 
 	// ipv4 and ipv6 belong to different subscription groups
 	var sub4, sub6 *pubsub.Subscriber
 	if option.IPv4Enable {
-		sub4 = s.pub.Subscribe(fqdn + "4")
+		sub4 = s.pub.Subscribe(key + "4")
 		defer sub4.Close()
 	}
 	if option.IPv6Enable {
-		sub6 = s.pub.Subscribe(fqdn + "6")
+		sub6 = s.pub.Subscribe(key + "6")
 		defer sub6.Close()
 	}
 	done := make(chan interface{})
@@ -277,8 +311,17 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option I
 	}()
 	s.sendQuery(ctx, fqdn, option)
 
+	if bypassed {
+		// Cached answers are replaced by the new answers before the subscribers are notified.
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-done:
+		}
+	}
+
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, err := s.findIPsForDomain(key, option)
 		if err != errRecordNotFound {
 			return ips, err
 		}
@@ -290,3 +333,55 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, option I
 		}
 	}
 }
+
+// pendingRecordRequest removes and returns the pending request of the response, if it is a query for records other than A and AAAA.
+func (s *ClassicNameServer) pendingRecordRequest(payload []byte) (dnsRequest, bool) {
+	if len(payload) < 2 {
+		return dnsRequest{}, false
+	}
+	id := uint16(payload[0])<<8 | uint16(payload[1])
+
+	s.Lock()
+	defer s.Unlock()
+
+	req, ok := s.requests[id]
+	if !ok || req.reqType == dnsmessage.TypeA || req.reqType == dnsmessage.TypeAAAA {
+		return dnsRequest{}, false
+	}
+	delete(s.requests, id)
+	return req, true
+}
+
+func (s *ClassicNameServer) handleRecordResponse(req dnsRequest, payload []byte) {
+	rs, err := parseRecordResponse(payload)
+	if err != nil {
+		newError(s.name, " fail to parse responsed DNS udp").Base(err).AtError().WriteToLog()
+		return
+	}
+
+	newError(s.name, " got answere: ", req.domain, " ", req.reqType, " -> ", len(rs.Records), " records ", time.Since(req.start)).AtInfo().WriteToLog()
+	s.records.update(req.domain, uint16(req.reqType), rs)
+	s.pub.Publish(recordTopic(req.domain, uint16(req.reqType)), nil)
+	common.Must(s.cleanup.Start())
+}
+
+// QueryRecords implements recordClient.
+func (s *ClassicNameServer) QueryRecords(ctx context.Context, domain string, recordType uint16) ([]dns_feature.Record, error) {
+	fqdn := Fqdn(domain)
+
+	return queryRecords(ctx, s.pub, s.records, fqdn, recordType, func() {
+		newError(s.name, " querying DNS for: ", fqdn, " ", dnsmessage.Type(recordType)).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+
+		req := buildRecordReqMsg(fqdn, recordType, s.newReqID(), s.subnet.options(ctx))
+		s.addPendingRequest(req)
+		b, _ := dns.PackMessage(req.msg)
+		udpCtx := context.Background()
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			udpCtx = session.ContextWithInbound(udpCtx, inbound)
+		}
+		udpCtx = session.ContextWithContent(udpCtx, &session.Content{
+			Protocol: "dns",
+		})
+		s.transport.Dispatch(udpCtx, s.address, b)
+	})
+}
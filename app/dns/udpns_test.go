@@ -0,0 +1,163 @@
+// +build !confonly
+
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	udp_proto "v2ray.com/core/common/protocol/udp"
+)
+
+// subnetDispatcher answers queries with an address and a TTL by the subnet in EDNS Client Subnet of the query. It
+// holds the first queries until wait queries arrive, and then answers them in descending order of TTL.
+type subnetDispatcher struct {
+	sync.Mutex
+	server  *ClassicNameServer
+	wait    int
+	queries int
+	pending [][]byte
+}
+
+type subnetAnswer struct {
+	ip  string
+	ttl uint32
+}
+
+var subnetAnswers = map[string]subnetAnswer{
+	"10.0.0.0": {ip: "1.1.1.1", ttl: 600},
+	"10.0.1.0": {ip: "2.2.2.2", ttl: 60},
+	"":         {ip: "3.3.3.3", ttl: 300},
+}
+
+func answerBySubnet(query []byte) ([]byte, uint32) {
+	m := new(dns.Msg)
+	common.Must(m.Unpack(query))
+
+	var subnet string
+	if opt := m.IsEdns0(); opt != nil {
+		for _, o := range opt.Option {
+			if e, ok := o.(*dns.EDNS0_SUBNET); ok {
+				subnet = e.Address.String()
+			}
+		}
+	}
+	answer := subnetAnswers[subnet]
+
+	r := new(dns.Msg)
+	r.SetReply(m)
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN A %s", m.Question[0].Name, answer.ttl, answer.ip))
+	common.Must(err)
+	r.Answer = append(r.Answer, rr)
+	b, err := r.Pack()
+	common.Must(err)
+	return b, answer.ttl
+}
+
+func (d *subnetDispatcher) Dispatch(ctx context.Context, destination net.Destination, payload *buf.Buffer) {
+	d.Lock()
+	defer d.Unlock()
+
+	d.queries++
+	d.pending = append(d.pending, append([]byte(nil), payload.Bytes()...))
+	payload.Release()
+	if len(d.pending) < d.wait {
+		return
+	}
+
+	type response struct {
+		payload []byte
+		ttl     uint32
+	}
+	var responses []response
+	for _, query := range d.pending {
+		b, ttl := answerBySubnet(query)
+		responses = append(responses, response{payload: b, ttl: ttl})
+	}
+	d.pending = nil
+	d.wait = 0
+	sort.Slice(responses, func(i, j int) bool {
+		return responses[i].ttl > responses[j].ttl
+	})
+
+	go func() {
+		for _, r := range responses {
+			b := buf.New()
+			common.Must2(b.Write(r.payload))
+			d.server.HandleResponse(ctx, &udp_proto.Packet{Payload: b})
+		}
+	}()
+}
+
+func (d *subnetDispatcher) queryCount() int {
+	d.Lock()
+	defer d.Unlock()
+	return d.queries
+}
+
+func TestClassicNameServerSourceSubnet(t *testing.T) {
+	s := baseClassicNameServer(net.UDPDestination(net.LocalHostIP, 53), "test", nil)
+	s.subnet = clientSubnet{bySource: true}
+	d := &subnetDispatcher{server: s, wait: 2}
+	s.transport = d
+	defer s.cleanup.Close()
+
+	option := IPOption{IPv4Enable: true}
+	query := func(clientIP net.IP) []net.IP {
+		ctx := context.Background()
+		if clientIP != nil {
+			ctx = contextWithClientIP(ctx, clientIP)
+		}
+		ctx, cancel := context.WithTimeout(ctx, time.Second*4)
+		defer cancel()
+
+		ips, err := s.QueryIP(ctx, "v2fly.org", option)
+		if err != nil {
+			t.Error(err)
+		}
+		return ips
+	}
+
+	// Clients of two subnets query at the same time, and the answer with longer TTL arrives first.
+	var wg sync.WaitGroup
+	results := make([][]net.IP, 2)
+	for i, ip := range []net.IP{{10, 0, 0, 1}, {10, 0, 1, 1}} {
+		wg.Add(1)
+		go func(i int, ip net.IP) {
+			defer wg.Done()
+			results[i] = query(ip)
+		}(i, ip)
+	}
+	wg.Wait()
+	if r := cmp.Diff(results, [][]net.IP{{{1, 1, 1, 1}}, {{2, 2, 2, 2}}}); r != "" {
+		t.Error(r)
+	}
+
+	expire0, found0 := s.expireTime(contextWithClientIP(context.Background(), net.IP{10, 0, 0, 2}), "v2fly.org", option)
+	expire1, found1 := s.expireTime(contextWithClientIP(context.Background(), net.IP{10, 0, 1, 2}), "v2fly.org", option)
+	if !found0 || !found1 || !expire0.After(expire1) {
+		t.Error("expect expire time of each subnet, but got ", expire0, " and ", expire1)
+	}
+
+	// Answers for clients are not served to queries not on behalf of clients.
+	if r := cmp.Diff(query(nil), []net.IP{{3, 3, 3, 3}}); r != "" {
+		t.Error(r)
+	}
+	// Answers for a client are served to other clients in the same subnet.
+	if r := cmp.Diff(query(net.IP{10, 0, 0, 3}), []net.IP{{1, 1, 1, 1}}); r != "" {
+		t.Error(r)
+	}
+	if c := d.queryCount(); c != 3 {
+		t.Error("expect 3 queries, but got ", c)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"v2ray.com/core"
 	"v2ray.com/core/app/log"
 	"v2ray.com/core/common"
+	clog "v2ray.com/core/common/log"
 )
 
 type LoggerServer struct {
@@ -33,6 +34,73 @@ func (s *LoggerServer) RestartLogger(ctx context.Context, request *RestartLogger
 	return &RestartLoggerResponse{}, nil
 }
 
+func (s *LoggerServer) getLogger() (*log.Instance, error) {
+	logger, ok := s.V.GetFeature((*log.Instance)(nil)).(*log.Instance)
+	if !ok {
+		return nil, newError("unable to get logger instance")
+	}
+	return logger, nil
+}
+
+// SetLogLevel implements LoggerService.
+func (s *LoggerServer) SetLogLevel(ctx context.Context, request *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	logger, err := s.getLogger()
+	if err != nil {
+		return nil, err
+	}
+	if request.Unset {
+		logger.ResetLevel(request.Path)
+	} else {
+		logger.SetLevel(request.Path, request.Level)
+	}
+	return &SetLogLevelResponse{}, nil
+}
+
+// GetLogLevels implements LoggerService.
+func (s *LoggerServer) GetLogLevels(ctx context.Context, request *GetLogLevelsRequest) (*GetLogLevelsResponse, error) {
+	logger, err := s.getLogger()
+	if err != nil {
+		return nil, err
+	}
+	level, overrides := logger.Levels()
+	return &GetLogLevelsResponse{
+		Level:     level,
+		Overrides: overrides,
+	}, nil
+}
+
+// FollowLog implements LoggerService.
+func (s *LoggerServer) FollowLog(request *FollowLogRequest, stream LoggerService_FollowLogServer) error {
+	if !request.Error && !request.Access {
+		return newError("neither error nor access log is followed")
+	}
+	logger, err := s.getLogger()
+	if err != nil {
+		return err
+	}
+
+	sub := logger.SubscribeMessages()
+	defer sub.Close()
+
+	filter := newLogFilter(request)
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-sub.Wait():
+			m, ok := msg.(clog.Message)
+			if !ok || !filter.match(m) {
+				continue
+			}
+			if err := stream.Send(&FollowLogResponse{
+				Message: formatMessage(m, request.Format),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 type service struct {
 	v *core.Instance
 }
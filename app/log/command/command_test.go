@@ -2,7 +2,12 @@ package command_test
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/dispatcher"
@@ -12,6 +17,8 @@ import (
 	_ "v2ray.com/core/app/proxyman/inbound"
 	_ "v2ray.com/core/app/proxyman/outbound"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+	clog "v2ray.com/core/common/log"
 	"v2ray.com/core/common/serial"
 )
 
@@ -32,3 +39,126 @@ func TestLoggerRestart(t *testing.T) {
 	}
 	common.Must2(server.RestartLogger(context.Background(), &RestartLoggerRequest{}))
 }
+
+func TestSetLogLevel(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&log.Config{
+				ErrorLogLevel: clog.Severity_Warning,
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+
+	server := &LoggerServer{
+		V: v,
+	}
+	common.Must2(server.SetLogLevel(context.Background(), &SetLogLevelRequest{Path: "proxy/dokodemo", Level: clog.Severity_Debug}))
+	common.Must2(server.SetLogLevel(context.Background(), &SetLogLevelRequest{Path: "v2ray.com/core/transport/", Level: clog.Severity_Error}))
+	common.Must2(server.SetLogLevel(context.Background(), &SetLogLevelRequest{Level: clog.Severity_Info}))
+	common.Must2(server.SetLogLevel(context.Background(), &SetLogLevelRequest{Path: "proxy/dokodemo", Unset: true}))
+
+	resp, err := server.GetLogLevels(context.Background(), &GetLogLevelsRequest{})
+	common.Must(err)
+	if resp.Level != clog.Severity_Info {
+		t.Error("unexpected level: ", resp.Level)
+	}
+	if r := cmp.Diff(resp.Overrides, map[string]clog.Severity{"transport": clog.Severity_Error}); r != "" {
+		t.Error(r)
+	}
+}
+
+type mockLogStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages chan string
+}
+
+func (s *mockLogStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockLogStream) Send(r *FollowLogResponse) error {
+	s.messages <- r.Message
+	return nil
+}
+
+func TestFollowLog(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&log.Config{}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+
+	server := &LoggerServer{
+		V: v,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockLogStream{
+		ctx:      ctx,
+		messages: make(chan string, 16),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.FollowLog(&FollowLogRequest{
+			Error:      true,
+			Access:     true,
+			InboundTag: []string{"in"},
+		}, stream)
+	}()
+
+	withSession := func(id uint32) errors.ExportOption {
+		return func(h *errors.ExportOptionHolder) {
+			h.SessionID = id
+		}
+	}
+
+	var msg string
+	for len(msg) == 0 {
+		clog.Record(&clog.AccessMessage{
+			From:       "127.0.0.1",
+			To:         "tcp:v2ray.com:443",
+			Status:     clog.AccessAccepted,
+			SessionID:  1,
+			InboundTag: "in",
+		})
+		select {
+		case msg = <-stream.messages:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	if !strings.Contains(msg, "127.0.0.1 accepted tcp:v2ray.com:443") {
+		t.Error("unexpected access log: ", msg)
+	}
+
+	clog.Record(&clog.AccessMessage{
+		From:       "127.0.0.1",
+		To:         "tcp:v2ray.com:443",
+		Status:     clog.AccessAccepted,
+		SessionID:  2,
+		InboundTag: "other",
+	})
+	errors.New("error of other session").AtWarning().WriteToLog(withSession(2))
+	errors.New("error of session").AtWarning().WriteToLog(withSession(1))
+
+	select {
+	case msg = <-stream.messages:
+		if !strings.HasSuffix(msg, "[Warning] [1] error of session") {
+			t.Error("unexpected error log: ", msg)
+		}
+	case <-time.After(time.Second):
+		t.Error("timeout waiting for error log")
+	}
+
+	cancel()
+	common.Must(<-done)
+}
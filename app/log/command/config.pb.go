@@ -5,7 +5,11 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	math "math"
+	log1 "v2ray.com/core/app/log"
+	log "v2ray.com/core/common/log"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -112,10 +116,314 @@ func (m *RestartLoggerResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_RestartLoggerResponse proto.InternalMessageInfo
 
+type SetLogLevelRequest struct {
+	// Path of packages relative to v2ray.com/core, such as "proxy/dokodemo".
+	// Empty for all packages without their own levels.
+	Path  string       `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Level log.Severity `protobuf:"varint,2,opt,name=level,proto3,enum=v2ray.core.common.log.Severity" json:"level,omitempty"`
+	// Remove the level of the path instead, so that packages under it use the
+	// level of their parent path. An empty path is reset to the level in config.
+	Unset                bool     `protobuf:"varint,3,opt,name=unset,proto3" json:"unset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLogLevelRequest) Reset()         { *m = SetLogLevelRequest{} }
+func (m *SetLogLevelRequest) String() string { return proto.CompactTextString(m) }
+func (*SetLogLevelRequest) ProtoMessage()    {}
+func (*SetLogLevelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{3}
+}
+
+func (m *SetLogLevelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogLevelRequest.Unmarshal(m, b)
+}
+func (m *SetLogLevelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogLevelRequest.Marshal(b, m, deterministic)
+}
+func (m *SetLogLevelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogLevelRequest.Merge(m, src)
+}
+func (m *SetLogLevelRequest) XXX_Size() int {
+	return xxx_messageInfo_SetLogLevelRequest.Size(m)
+}
+func (m *SetLogLevelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogLevelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogLevelRequest proto.InternalMessageInfo
+
+func (m *SetLogLevelRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SetLogLevelRequest) GetLevel() log.Severity {
+	if m != nil {
+		return m.Level
+	}
+	return log.Severity_Unknown
+}
+
+func (m *SetLogLevelRequest) GetUnset() bool {
+	if m != nil {
+		return m.Unset
+	}
+	return false
+}
+
+type SetLogLevelResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLogLevelResponse) Reset()         { *m = SetLogLevelResponse{} }
+func (m *SetLogLevelResponse) String() string { return proto.CompactTextString(m) }
+func (*SetLogLevelResponse) ProtoMessage()    {}
+func (*SetLogLevelResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{4}
+}
+
+func (m *SetLogLevelResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogLevelResponse.Unmarshal(m, b)
+}
+func (m *SetLogLevelResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogLevelResponse.Marshal(b, m, deterministic)
+}
+func (m *SetLogLevelResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogLevelResponse.Merge(m, src)
+}
+func (m *SetLogLevelResponse) XXX_Size() int {
+	return xxx_messageInfo_SetLogLevelResponse.Size(m)
+}
+func (m *SetLogLevelResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogLevelResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogLevelResponse proto.InternalMessageInfo
+
+type GetLogLevelsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLogLevelsRequest) Reset()         { *m = GetLogLevelsRequest{} }
+func (m *GetLogLevelsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLogLevelsRequest) ProtoMessage()    {}
+func (*GetLogLevelsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{5}
+}
+
+func (m *GetLogLevelsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLogLevelsRequest.Unmarshal(m, b)
+}
+func (m *GetLogLevelsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLogLevelsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLogLevelsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLogLevelsRequest.Merge(m, src)
+}
+func (m *GetLogLevelsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLogLevelsRequest.Size(m)
+}
+func (m *GetLogLevelsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLogLevelsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLogLevelsRequest proto.InternalMessageInfo
+
+type GetLogLevelsResponse struct {
+	// Level of all packages without their own levels.
+	Level log.Severity `protobuf:"varint,1,opt,name=level,proto3,enum=v2ray.core.common.log.Severity" json:"level,omitempty"`
+	// Levels of packages by path.
+	Overrides            map[string]log.Severity `protobuf:"bytes,2,rep,name=overrides,proto3" json:"overrides,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3,enum=v2ray.core.common.log.Severity"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
+}
+
+func (m *GetLogLevelsResponse) Reset()         { *m = GetLogLevelsResponse{} }
+func (m *GetLogLevelsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLogLevelsResponse) ProtoMessage()    {}
+func (*GetLogLevelsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{6}
+}
+
+func (m *GetLogLevelsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLogLevelsResponse.Unmarshal(m, b)
+}
+func (m *GetLogLevelsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLogLevelsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLogLevelsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLogLevelsResponse.Merge(m, src)
+}
+func (m *GetLogLevelsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLogLevelsResponse.Size(m)
+}
+func (m *GetLogLevelsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLogLevelsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLogLevelsResponse proto.InternalMessageInfo
+
+func (m *GetLogLevelsResponse) GetLevel() log.Severity {
+	if m != nil {
+		return m.Level
+	}
+	return log.Severity_Unknown
+}
+
+func (m *GetLogLevelsResponse) GetOverrides() map[string]log.Severity {
+	if m != nil {
+		return m.Overrides
+	}
+	return nil
+}
+
+type FollowLogRequest struct {
+	// Follow error logs.
+	Error bool `protobuf:"varint,1,opt,name=error,proto3" json:"error,omitempty"`
+	// Follow access logs.
+	Access bool `protobuf:"varint,2,opt,name=access,proto3" json:"access,omitempty"`
+	// Error logs at this level or more severe are sent. Default Info.
+	Level log.Severity `protobuf:"varint,3,opt,name=level,proto3,enum=v2ray.core.common.log.Severity" json:"level,omitempty"`
+	// Only send access logs of these inbounds, and error logs of their
+	// sessions. All inbounds if empty.
+	InboundTag []string `protobuf:"bytes,4,rep,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	// Only send access logs of these users, and error logs of their sessions.
+	// All users if empty.
+	Email []string `protobuf:"bytes,5,rep,name=email,proto3" json:"email,omitempty"`
+	// Format of messages.
+	Format               log1.LogFormat `protobuf:"varint,6,opt,name=format,proto3,enum=v2ray.core.app.log.LogFormat" json:"format,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *FollowLogRequest) Reset()         { *m = FollowLogRequest{} }
+func (m *FollowLogRequest) String() string { return proto.CompactTextString(m) }
+func (*FollowLogRequest) ProtoMessage()    {}
+func (*FollowLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{7}
+}
+
+func (m *FollowLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowLogRequest.Unmarshal(m, b)
+}
+func (m *FollowLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowLogRequest.Marshal(b, m, deterministic)
+}
+func (m *FollowLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowLogRequest.Merge(m, src)
+}
+func (m *FollowLogRequest) XXX_Size() int {
+	return xxx_messageInfo_FollowLogRequest.Size(m)
+}
+func (m *FollowLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowLogRequest proto.InternalMessageInfo
+
+func (m *FollowLogRequest) GetError() bool {
+	if m != nil {
+		return m.Error
+	}
+	return false
+}
+
+func (m *FollowLogRequest) GetAccess() bool {
+	if m != nil {
+		return m.Access
+	}
+	return false
+}
+
+func (m *FollowLogRequest) GetLevel() log.Severity {
+	if m != nil {
+		return m.Level
+	}
+	return log.Severity_Unknown
+}
+
+func (m *FollowLogRequest) GetInboundTag() []string {
+	if m != nil {
+		return m.InboundTag
+	}
+	return nil
+}
+
+func (m *FollowLogRequest) GetEmail() []string {
+	if m != nil {
+		return m.Email
+	}
+	return nil
+}
+
+func (m *FollowLogRequest) GetFormat() log1.LogFormat {
+	if m != nil {
+		return m.Format
+	}
+	return log1.LogFormat_Text
+}
+
+type FollowLogResponse struct {
+	// A line of log without line separator.
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FollowLogResponse) Reset()         { *m = FollowLogResponse{} }
+func (m *FollowLogResponse) String() string { return proto.CompactTextString(m) }
+func (*FollowLogResponse) ProtoMessage()    {}
+func (*FollowLogResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_46d95b63a682e4a6, []int{8}
+}
+
+func (m *FollowLogResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FollowLogResponse.Unmarshal(m, b)
+}
+func (m *FollowLogResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FollowLogResponse.Marshal(b, m, deterministic)
+}
+func (m *FollowLogResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FollowLogResponse.Merge(m, src)
+}
+func (m *FollowLogResponse) XXX_Size() int {
+	return xxx_messageInfo_FollowLogResponse.Size(m)
+}
+func (m *FollowLogResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FollowLogResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FollowLogResponse proto.InternalMessageInfo
+
+func (m *FollowLogResponse) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.log.command.Config")
 	proto.RegisterType((*RestartLoggerRequest)(nil), "v2ray.core.app.log.command.RestartLoggerRequest")
 	proto.RegisterType((*RestartLoggerResponse)(nil), "v2ray.core.app.log.command.RestartLoggerResponse")
+	proto.RegisterType((*SetLogLevelRequest)(nil), "v2ray.core.app.log.command.SetLogLevelRequest")
+	proto.RegisterType((*SetLogLevelResponse)(nil), "v2ray.core.app.log.command.SetLogLevelResponse")
+	proto.RegisterType((*GetLogLevelsRequest)(nil), "v2ray.core.app.log.command.GetLogLevelsRequest")
+	proto.RegisterType((*GetLogLevelsResponse)(nil), "v2ray.core.app.log.command.GetLogLevelsResponse")
+	proto.RegisterMapType((map[string]log.Severity)(nil), "v2ray.core.app.log.command.GetLogLevelsResponse.OverridesEntry")
+	proto.RegisterType((*FollowLogRequest)(nil), "v2ray.core.app.log.command.FollowLogRequest")
+	proto.RegisterType((*FollowLogResponse)(nil), "v2ray.core.app.log.command.FollowLogResponse")
 }
 
 func init() {
@@ -123,21 +431,43 @@ func init() {
 }
 
 var fileDescriptor_46d95b63a682e4a6 = []byte{
-	// 210 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xd2, 0x2e, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0x2c, 0x28, 0xd0, 0xcf,
-	0xc9, 0x4f, 0xd7, 0x4f, 0xce, 0xcf, 0xcd, 0x4d, 0xcc, 0x4b, 0xd1, 0x4f, 0xce, 0xcf, 0x4b, 0xcb,
-	0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x92, 0x82, 0x29, 0x2e, 0x4a, 0xd5, 0x4b, 0x2c,
-	0x28, 0xd0, 0xcb, 0xc9, 0x4f, 0xd7, 0x83, 0x2a, 0x54, 0xe2, 0xe0, 0x62, 0x73, 0x06, 0xab, 0x55,
-	0x12, 0xe3, 0x12, 0x09, 0x4a, 0x2d, 0x2e, 0x49, 0x2c, 0x2a, 0xf1, 0xc9, 0x4f, 0x4f, 0x4f, 0x2d,
-	0x0a, 0x4a, 0x2d, 0x2c, 0x4d, 0x2d, 0x2e, 0x51, 0x12, 0xe7, 0x12, 0x45, 0x13, 0x2f, 0x2e, 0xc8,
-	0xcf, 0x2b, 0x4e, 0x35, 0x6a, 0x67, 0xe4, 0xe2, 0x85, 0x08, 0x05, 0xa7, 0x16, 0x95, 0x65, 0x26,
-	0xa7, 0x0a, 0x95, 0x71, 0xf1, 0xa2, 0x28, 0x15, 0x32, 0xd0, 0xc3, 0x6d, 0xb5, 0x1e, 0x36, 0xdb,
-	0xa4, 0x0c, 0x49, 0xd0, 0x01, 0x71, 0x87, 0x12, 0x83, 0x93, 0x07, 0x97, 0x5c, 0x72, 0x7e, 0x2e,
-	0x1e, 0x9d, 0x01, 0x8c, 0x51, 0xec, 0x50, 0xe6, 0x2a, 0x26, 0xa9, 0x30, 0xa3, 0xa0, 0xc4, 0x4a,
-	0x3d, 0x67, 0x90, 0x3a, 0xc7, 0x82, 0x02, 0x3d, 0x9f, 0xfc, 0x74, 0x3d, 0x67, 0x88, 0x64, 0x12,
-	0x1b, 0x38, 0xc4, 0x8c, 0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x37, 0xc7, 0xfc, 0xda, 0x60, 0x01,
-	0x00, 0x00,
+	// 567 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0xc1, 0x6e, 0xd3, 0x40,
+	0x10, 0xad, 0xe3, 0x26, 0x4d, 0xa6, 0xb4, 0x2a, 0xdb, 0xb4, 0x58, 0x96, 0xa0, 0x91, 0xb9, 0x44,
+	0x82, 0xae, 0x4b, 0x50, 0x25, 0xc4, 0x05, 0x41, 0x45, 0xe1, 0x10, 0x09, 0xe4, 0x20, 0x0e, 0x48,
+	0x15, 0xda, 0x3a, 0x53, 0x63, 0x61, 0x7b, 0xcd, 0xae, 0x63, 0x94, 0x6f, 0xe0, 0x4f, 0xf8, 0x26,
+	0xae, 0xfc, 0x07, 0xf2, 0x7a, 0x5d, 0x27, 0x69, 0x88, 0x92, 0xdb, 0xce, 0xec, 0xf3, 0xbc, 0x37,
+	0x33, 0x6f, 0x0d, 0x4f, 0xf2, 0x81, 0x60, 0x53, 0xea, 0xf3, 0xd8, 0xf5, 0xb9, 0x40, 0x97, 0xa5,
+	0xa9, 0x1b, 0xf1, 0xc0, 0xf5, 0x79, 0x1c, 0xb3, 0x64, 0xec, 0xfa, 0x3c, 0xb9, 0x09, 0x03, 0x9a,
+	0x0a, 0x9e, 0x71, 0x62, 0x57, 0x60, 0x81, 0x94, 0xa5, 0x29, 0x8d, 0x78, 0x40, 0x35, 0xd0, 0x7e,
+	0xbc, 0x50, 0xa8, 0xc8, 0xf3, 0x44, 0xd5, 0x8a, 0xb8, 0x2e, 0x70, 0x07, 0x54, 0xb3, 0xd5, 0x2c,
+	0x4e, 0x1b, 0x5a, 0x17, 0x2a, 0x76, 0x8e, 0xa1, 0xeb, 0xa1, 0xcc, 0x98, 0xc8, 0x86, 0x3c, 0x08,
+	0x50, 0x78, 0xf8, 0x63, 0x82, 0x32, 0x73, 0x1e, 0xc0, 0xd1, 0x42, 0x5e, 0xa6, 0x3c, 0x91, 0xe8,
+	0x4c, 0x80, 0x8c, 0xb0, 0x48, 0x0e, 0x31, 0xc7, 0x48, 0xc3, 0x09, 0x81, 0xed, 0x94, 0x65, 0xdf,
+	0x2c, 0xa3, 0x67, 0xf4, 0x3b, 0x9e, 0x3a, 0x93, 0x73, 0x68, 0x46, 0x05, 0xc6, 0x6a, 0xf4, 0x8c,
+	0xfe, 0xfe, 0xe0, 0x84, 0xce, 0xb4, 0x56, 0x4a, 0x57, 0xdd, 0x8d, 0x30, 0x47, 0x11, 0x66, 0x53,
+	0xaf, 0x44, 0x93, 0x2e, 0x34, 0x27, 0x89, 0xc4, 0xcc, 0x32, 0x7b, 0x46, 0xbf, 0xed, 0x95, 0x81,
+	0x73, 0x04, 0x87, 0x73, 0xb4, 0x5a, 0xcd, 0x11, 0x1c, 0xbe, 0xab, 0xd3, 0xb2, 0x52, 0xff, 0xab,
+	0x01, 0xdd, 0xf9, 0x7c, 0x89, 0xaf, 0x35, 0x19, 0x1b, 0x69, 0xba, 0x82, 0x0e, 0xcf, 0x51, 0x88,
+	0x70, 0x8c, 0xd2, 0x6a, 0xf4, 0xcc, 0xfe, 0xee, 0xe0, 0x15, 0xfd, 0xff, 0xa6, 0xe8, 0x32, 0x6e,
+	0xfa, 0xa1, 0xaa, 0xf0, 0x36, 0xc9, 0xc4, 0xd4, 0xab, 0x2b, 0xda, 0x57, 0xb0, 0x3f, 0x7f, 0x49,
+	0x0e, 0xc0, 0xfc, 0x8e, 0x53, 0x3d, 0xce, 0xe2, 0x58, 0x28, 0xcf, 0x59, 0x34, 0xc1, 0xb5, 0xa7,
+	0xa9, 0xd0, 0x2f, 0x1b, 0x2f, 0x0c, 0xe7, 0xaf, 0x01, 0x07, 0x97, 0x3c, 0x8a, 0xf8, 0xcf, 0x21,
+	0x0f, 0xaa, 0x8d, 0x75, 0xa1, 0x89, 0x42, 0x70, 0xa1, 0x38, 0xda, 0x5e, 0x19, 0x90, 0x63, 0x68,
+	0x31, 0xdf, 0x47, 0x29, 0x15, 0x4d, 0xdb, 0xd3, 0x51, 0x3d, 0x37, 0x73, 0xa3, 0xb9, 0x9d, 0xc0,
+	0x6e, 0x98, 0x5c, 0xf3, 0x49, 0x32, 0xfe, 0x9a, 0xb1, 0xc0, 0xda, 0xee, 0x99, 0xfd, 0x8e, 0x07,
+	0x3a, 0xf5, 0x89, 0x05, 0x4a, 0x45, 0xcc, 0xc2, 0xc8, 0x6a, 0xaa, 0xab, 0x32, 0x20, 0xe7, 0xd0,
+	0xba, 0xe1, 0x22, 0x66, 0x99, 0xd5, 0x52, 0x74, 0x0f, 0x97, 0xcd, 0x7a, 0xc8, 0x83, 0x4b, 0x05,
+	0xf2, 0x34, 0xd8, 0x39, 0x85, 0xfb, 0x33, 0x6d, 0xea, 0x8d, 0x5b, 0xb0, 0x13, 0xa3, 0x94, 0x2c,
+	0x40, 0x3d, 0xcd, 0x2a, 0x1c, 0xfc, 0x31, 0x61, 0xaf, 0x34, 0xf7, 0x08, 0x45, 0x1e, 0xfa, 0x48,
+	0x72, 0xd8, 0x9b, 0x33, 0x3d, 0x39, 0x5b, 0xb5, 0xe4, 0x65, 0xef, 0xc6, 0x7e, 0xb6, 0xc1, 0x17,
+	0xda, 0xc3, 0x5b, 0x24, 0x85, 0xdd, 0x19, 0x73, 0x13, 0xba, 0xaa, 0xc6, 0xdd, 0xc7, 0x67, 0xbb,
+	0x6b, 0xe3, 0x6f, 0x19, 0x25, 0xdc, 0x9b, 0xf5, 0x28, 0x71, 0xd7, 0x77, 0x73, 0xc9, 0x79, 0xb6,
+	0xa9, 0xfd, 0x9d, 0x2d, 0x12, 0x41, 0xe7, 0x76, 0x3f, 0xe4, 0xe9, 0xaa, 0x02, 0x8b, 0x6e, 0xb5,
+	0x4f, 0xd7, 0x44, 0x57, 0x5c, 0x67, 0xc6, 0x9b, 0xf7, 0xf0, 0xc8, 0xe7, 0xf1, 0x8a, 0xef, 0x3e,
+	0x1a, 0x5f, 0x76, 0xf4, 0xf1, 0x77, 0xc3, 0xfe, 0x3c, 0xf0, 0xd8, 0x94, 0x5e, 0x14, 0xb8, 0xd7,
+	0x69, 0x5a, 0xb8, 0x8b, 0x5e, 0x94, 0x97, 0xd7, 0x2d, 0xf5, 0xd3, 0x7c, 0xfe, 0x6f, 0x00, 0x40,
+	0xb4, 0xcf, 0x6a, 0xc9, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -153,6 +483,13 @@ const _ = grpc.SupportPackageIsVersion4
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type LoggerServiceClient interface {
 	RestartLogger(ctx context.Context, in *RestartLoggerRequest, opts ...grpc.CallOption) (*RestartLoggerResponse, error)
+	// Sets the level of error logs from packages at runtime. Levels set are
+	// kept when the logger is restarted.
+	SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error)
+	GetLogLevels(ctx context.Context, in *GetLogLevelsRequest, opts ...grpc.CallOption) (*GetLogLevelsResponse, error)
+	// Sends log messages recorded after the call, until the client cancels.
+	// Messages are dropped if the client is not fast enough.
+	FollowLog(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (LoggerService_FollowLogClient, error)
 }
 
 type loggerServiceClient struct {
@@ -172,9 +509,83 @@ func (c *loggerServiceClient) RestartLogger(ctx context.Context, in *RestartLogg
 	return out, nil
 }
 
+func (c *loggerServiceClient) SetLogLevel(ctx context.Context, in *SetLogLevelRequest, opts ...grpc.CallOption) (*SetLogLevelResponse, error) {
+	out := new(SetLogLevelResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.log.command.LoggerService/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerServiceClient) GetLogLevels(ctx context.Context, in *GetLogLevelsRequest, opts ...grpc.CallOption) (*GetLogLevelsResponse, error) {
+	out := new(GetLogLevelsResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.log.command.LoggerService/GetLogLevels", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *loggerServiceClient) FollowLog(ctx context.Context, in *FollowLogRequest, opts ...grpc.CallOption) (LoggerService_FollowLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LoggerService_serviceDesc.Streams[0], "/v2ray.core.app.log.command.LoggerService/FollowLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &loggerServiceFollowLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LoggerService_FollowLogClient interface {
+	Recv() (*FollowLogResponse, error)
+	grpc.ClientStream
+}
+
+type loggerServiceFollowLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *loggerServiceFollowLogClient) Recv() (*FollowLogResponse, error) {
+	m := new(FollowLogResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // LoggerServiceServer is the server API for LoggerService service.
 type LoggerServiceServer interface {
 	RestartLogger(context.Context, *RestartLoggerRequest) (*RestartLoggerResponse, error)
+	// Sets the level of error logs from packages at runtime. Levels set are
+	// kept when the logger is restarted.
+	SetLogLevel(context.Context, *SetLogLevelRequest) (*SetLogLevelResponse, error)
+	GetLogLevels(context.Context, *GetLogLevelsRequest) (*GetLogLevelsResponse, error)
+	// Sends log messages recorded after the call, until the client cancels.
+	// Messages are dropped if the client is not fast enough.
+	FollowLog(*FollowLogRequest, LoggerService_FollowLogServer) error
+}
+
+// UnimplementedLoggerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedLoggerServiceServer struct {
+}
+
+func (*UnimplementedLoggerServiceServer) RestartLogger(ctx context.Context, req *RestartLoggerRequest) (*RestartLoggerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestartLogger not implemented")
+}
+func (*UnimplementedLoggerServiceServer) SetLogLevel(ctx context.Context, req *SetLogLevelRequest) (*SetLogLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
+func (*UnimplementedLoggerServiceServer) GetLogLevels(ctx context.Context, req *GetLogLevelsRequest) (*GetLogLevelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogLevels not implemented")
+}
+func (*UnimplementedLoggerServiceServer) FollowLog(req *FollowLogRequest, srv LoggerService_FollowLogServer) error {
+	return status.Errorf(codes.Unimplemented, "method FollowLog not implemented")
 }
 
 func RegisterLoggerServiceServer(s *grpc.Server, srv LoggerServiceServer) {
@@ -199,6 +610,63 @@ func _LoggerService_RestartLogger_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LoggerService_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServiceServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.log.command.LoggerService/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServiceServer).SetLogLevel(ctx, req.(*SetLogLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerService_GetLogLevels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLogLevelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LoggerServiceServer).GetLogLevels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.log.command.LoggerService/GetLogLevels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LoggerServiceServer).GetLogLevels(ctx, req.(*GetLogLevelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LoggerService_FollowLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(FollowLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LoggerServiceServer).FollowLog(m, &loggerServiceFollowLogServer{stream})
+}
+
+type LoggerService_FollowLogServer interface {
+	Send(*FollowLogResponse) error
+	grpc.ServerStream
+}
+
+type loggerServiceFollowLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *loggerServiceFollowLogServer) Send(m *FollowLogResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _LoggerService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v2ray.core.app.log.command.LoggerService",
 	HandlerType: (*LoggerServiceServer)(nil),
@@ -207,7 +675,21 @@ var _LoggerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "RestartLogger",
 			Handler:    _LoggerService_RestartLogger_Handler,
 		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _LoggerService_SetLogLevel_Handler,
+		},
+		{
+			MethodName: "GetLogLevels",
+			Handler:    _LoggerService_GetLogLevels_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "FollowLog",
+			Handler:       _LoggerService_FollowLog_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "v2ray.com/core/app/log/command/config.proto",
 }
@@ -0,0 +1,97 @@
+// +build !confonly
+
+package command
+
+import (
+	"time"
+
+	"v2ray.com/core/app/log"
+	clog "v2ray.com/core/common/log"
+)
+
+// logFilter selects messages for a FollowLog request.
+type logFilter struct {
+	request     *FollowLogRequest
+	level       clog.Severity
+	inboundTags map[string]bool
+	emails      map[string]bool
+	// sessions are IDs of sessions whose access logs are selected, for selecting their error logs.
+	sessions map[uint32]bool
+}
+
+func newLogFilter(request *FollowLogRequest) *logFilter {
+	f := &logFilter{
+		request:  request,
+		level:    request.Level,
+		sessions: make(map[uint32]bool),
+	}
+	if f.level == clog.Severity_Unknown {
+		f.level = clog.Severity_Info
+	}
+	if len(request.InboundTag) > 0 {
+		f.inboundTags = make(map[string]bool)
+		for _, tag := range request.InboundTag {
+			f.inboundTags[tag] = true
+		}
+	}
+	if len(request.Email) > 0 {
+		f.emails = make(map[string]bool)
+		for _, email := range request.Email {
+			f.emails[email] = true
+		}
+	}
+	return f
+}
+
+func (f *logFilter) hasSessionFilter() bool {
+	return f.inboundTags != nil || f.emails != nil
+}
+
+func (f *logFilter) matchAccess(msg *clog.AccessMessage) bool {
+	if f.inboundTags != nil && !f.inboundTags[msg.InboundTag] {
+		return false
+	}
+	if f.emails != nil && !f.emails[msg.Email] {
+		return false
+	}
+	if f.hasSessionFilter() && msg.SessionID > 0 {
+		if msg.Status == clog.AccessClosed {
+			delete(f.sessions, msg.SessionID)
+		} else {
+			f.sessions[msg.SessionID] = true
+		}
+	}
+	return f.request.Access
+}
+
+func (f *logFilter) matchGeneral(msg *clog.GeneralMessage) bool {
+	if !f.request.Error || msg.Severity > f.level {
+		return false
+	}
+	if !f.hasSessionFilter() {
+		return true
+	}
+	c, ok := msg.Content.(clog.StructuredContent)
+	return ok && f.sessions[c.LogSessionID()]
+}
+
+// match returns whether the message is selected. Access messages are always checked, so that sessions of selected
+// access logs are tracked.
+func (f *logFilter) match(msg clog.Message) bool {
+	switch msg := msg.(type) {
+	case *clog.AccessMessage:
+		return f.matchAccess(msg)
+	case *clog.GeneralMessage:
+		return f.matchGeneral(msg)
+	default:
+		return false
+	}
+}
+
+func formatMessage(msg clog.Message, format log.LogFormat) string {
+	now := time.Now()
+	if format == log.LogFormat_JSON {
+		return (&clog.JSONMessage{Time: now, Message: msg}).String()
+	}
+	return now.Format("2006/01/02 15:04:05 ") + msg.String()
+}
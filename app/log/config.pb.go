@@ -49,15 +49,50 @@ func (LogType) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_92dfeade43d9e989, []int{0}
 }
 
+type LogFormat int32
+
+const (
+	// Plain text, with a timestamp prefix in each line.
+	LogFormat_Text LogFormat = 0
+	// One JSON object in each line. Access records have the session, inbound,
+	// outbound, sniffed domain and traffic of the connection, and error records
+	// have the severity and package path of the error.
+	LogFormat_JSON LogFormat = 1
+)
+
+var LogFormat_name = map[int32]string{
+	0: "Text",
+	1: "JSON",
+}
+
+var LogFormat_value = map[string]int32{
+	"Text": 0,
+	"JSON": 1,
+}
+
+func (x LogFormat) String() string {
+	return proto.EnumName(LogFormat_name, int32(x))
+}
+
+func (LogFormat) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_92dfeade43d9e989, []int{1}
+}
+
 type Config struct {
-	ErrorLogType         LogType      `protobuf:"varint,1,opt,name=error_log_type,json=errorLogType,proto3,enum=v2ray.core.app.log.LogType" json:"error_log_type,omitempty"`
-	ErrorLogLevel        log.Severity `protobuf:"varint,2,opt,name=error_log_level,json=errorLogLevel,proto3,enum=v2ray.core.common.log.Severity" json:"error_log_level,omitempty"`
-	ErrorLogPath         string       `protobuf:"bytes,3,opt,name=error_log_path,json=errorLogPath,proto3" json:"error_log_path,omitempty"`
-	AccessLogType        LogType      `protobuf:"varint,4,opt,name=access_log_type,json=accessLogType,proto3,enum=v2ray.core.app.log.LogType" json:"access_log_type,omitempty"`
-	AccessLogPath        string       `protobuf:"bytes,5,opt,name=access_log_path,json=accessLogPath,proto3" json:"access_log_path,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	ErrorLogType  LogType      `protobuf:"varint,1,opt,name=error_log_type,json=errorLogType,proto3,enum=v2ray.core.app.log.LogType" json:"error_log_type,omitempty"`
+	ErrorLogLevel log.Severity `protobuf:"varint,2,opt,name=error_log_level,json=errorLogLevel,proto3,enum=v2ray.core.common.log.Severity" json:"error_log_level,omitempty"`
+	ErrorLogPath  string       `protobuf:"bytes,3,opt,name=error_log_path,json=errorLogPath,proto3" json:"error_log_path,omitempty"`
+	AccessLogType LogType      `protobuf:"varint,4,opt,name=access_log_type,json=accessLogType,proto3,enum=v2ray.core.app.log.LogType" json:"access_log_type,omitempty"`
+	AccessLogPath string       `protobuf:"bytes,5,opt,name=access_log_path,json=accessLogPath,proto3" json:"access_log_path,omitempty"`
+	// Format of both error and access logs.
+	LogFormat LogFormat `protobuf:"varint,6,opt,name=log_format,json=logFormat,proto3,enum=v2ray.core.app.log.LogFormat" json:"log_format,omitempty"`
+	// Rotation of log files. Files are not rotated if it is not set.
+	Rotation *LogRotation `protobuf:"bytes,7,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	// Levels of error logs from packages, that override error_log_level.
+	LevelOverride        []*LevelOverride `protobuf:"bytes,8,rep,name=level_override,json=levelOverride,proto3" json:"level_override,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -120,9 +155,159 @@ func (m *Config) GetAccessLogPath() string {
 	return ""
 }
 
+func (m *Config) GetLogFormat() LogFormat {
+	if m != nil {
+		return m.LogFormat
+	}
+	return LogFormat_Text
+}
+
+func (m *Config) GetRotation() *LogRotation {
+	if m != nil {
+		return m.Rotation
+	}
+	return nil
+}
+
+func (m *Config) GetLevelOverride() []*LevelOverride {
+	if m != nil {
+		return m.LevelOverride
+	}
+	return nil
+}
+
+type LogRotation struct {
+	// Size in bytes that a log file is rotated before exceeding. 0 for no limit.
+	MaxSize int64 `protobuf:"varint,1,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	// Seconds that a log file is rotated after, aligned to multiples of the interval in local time. 0 for no limit.
+	Interval int64 `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Number of rotated files to keep. 0 to keep all.
+	MaxBackups uint32 `protobuf:"varint,3,opt,name=max_backups,json=maxBackups,proto3" json:"max_backups,omitempty"`
+	// Seconds that rotated files are kept for. 0 to keep all.
+	MaxAge int64 `protobuf:"varint,4,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// Compress rotated files with gzip.
+	Compress             bool     `protobuf:"varint,5,opt,name=compress,proto3" json:"compress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogRotation) Reset()         { *m = LogRotation{} }
+func (m *LogRotation) String() string { return proto.CompactTextString(m) }
+func (*LogRotation) ProtoMessage()    {}
+func (*LogRotation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_92dfeade43d9e989, []int{1}
+}
+
+func (m *LogRotation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogRotation.Unmarshal(m, b)
+}
+func (m *LogRotation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogRotation.Marshal(b, m, deterministic)
+}
+func (m *LogRotation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogRotation.Merge(m, src)
+}
+func (m *LogRotation) XXX_Size() int {
+	return xxx_messageInfo_LogRotation.Size(m)
+}
+func (m *LogRotation) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogRotation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogRotation proto.InternalMessageInfo
+
+func (m *LogRotation) GetMaxSize() int64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *LogRotation) GetInterval() int64 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+func (m *LogRotation) GetMaxBackups() uint32 {
+	if m != nil {
+		return m.MaxBackups
+	}
+	return 0
+}
+
+func (m *LogRotation) GetMaxAge() int64 {
+	if m != nil {
+		return m.MaxAge
+	}
+	return 0
+}
+
+func (m *LogRotation) GetCompress() bool {
+	if m != nil {
+		return m.Compress
+	}
+	return false
+}
+
+type LevelOverride struct {
+	// Path of packages relative to v2ray.com/core, such as "proxy/dokodemo" or
+	// "transport". It applies to all packages under the path, and the longest
+	// matching path is used.
+	Path                 string       `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Level                log.Severity `protobuf:"varint,2,opt,name=level,proto3,enum=v2ray.core.common.log.Severity" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *LevelOverride) Reset()         { *m = LevelOverride{} }
+func (m *LevelOverride) String() string { return proto.CompactTextString(m) }
+func (*LevelOverride) ProtoMessage()    {}
+func (*LevelOverride) Descriptor() ([]byte, []int) {
+	return fileDescriptor_92dfeade43d9e989, []int{2}
+}
+
+func (m *LevelOverride) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LevelOverride.Unmarshal(m, b)
+}
+func (m *LevelOverride) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LevelOverride.Marshal(b, m, deterministic)
+}
+func (m *LevelOverride) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LevelOverride.Merge(m, src)
+}
+func (m *LevelOverride) XXX_Size() int {
+	return xxx_messageInfo_LevelOverride.Size(m)
+}
+func (m *LevelOverride) XXX_DiscardUnknown() {
+	xxx_messageInfo_LevelOverride.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LevelOverride proto.InternalMessageInfo
+
+func (m *LevelOverride) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *LevelOverride) GetLevel() log.Severity {
+	if m != nil {
+		return m.Level
+	}
+	return log.Severity_Unknown
+}
+
 func init() {
 	proto.RegisterEnum("v2ray.core.app.log.LogType", LogType_name, LogType_value)
+	proto.RegisterEnum("v2ray.core.app.log.LogFormat", LogFormat_name, LogFormat_value)
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.log.Config")
+	proto.RegisterType((*LogRotation)(nil), "v2ray.core.app.log.LogRotation")
+	proto.RegisterType((*LevelOverride)(nil), "v2ray.core.app.log.LevelOverride")
 }
 
 func init() {
@@ -130,25 +315,38 @@ func init() {
 }
 
 var fileDescriptor_92dfeade43d9e989 = []byte{
-	// 306 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xcd, 0x4a, 0xfb, 0x40,
-	0x14, 0x47, 0xff, 0x49, 0xbf, 0xfe, 0x1d, 0x6d, 0x1b, 0x66, 0x21, 0x45, 0x17, 0x16, 0x15, 0x29,
-	0x2e, 0x26, 0x50, 0xf5, 0x01, 0x6a, 0x50, 0x37, 0x45, 0x4a, 0x14, 0x17, 0x6e, 0xca, 0x38, 0x5c,
-	0xa7, 0x81, 0x49, 0xee, 0x30, 0x19, 0x02, 0x79, 0x22, 0xc1, 0xa7, 0x94, 0x4c, 0x1b, 0x52, 0x3f,
-	0xc0, 0x65, 0x92, 0x7b, 0xce, 0xef, 0x40, 0xc8, 0x69, 0x31, 0x33, 0xbc, 0x64, 0x02, 0xd3, 0x50,
-	0xa0, 0x81, 0x90, 0x6b, 0x1d, 0x2a, 0x94, 0xa1, 0xc0, 0xec, 0x2d, 0x91, 0x4c, 0x1b, 0xb4, 0x48,
-	0x69, 0x7d, 0x64, 0x80, 0x71, 0xad, 0x99, 0x42, 0x79, 0xf8, 0x1d, 0x14, 0x98, 0xa6, 0x98, 0x39,
-	0x56, 0xe1, 0x16, 0x3c, 0x79, 0xf7, 0x49, 0x37, 0x72, 0x26, 0x3a, 0x27, 0x43, 0x30, 0x06, 0xcd,
-	0x4a, 0xa1, 0x5c, 0xd9, 0x52, 0xc3, 0xd8, 0x9b, 0x78, 0xd3, 0xe1, 0xec, 0x88, 0xfd, 0x94, 0xb3,
-	0x05, 0xca, 0xa7, 0x52, 0x43, 0xbc, 0xef, 0x90, 0xed, 0x13, 0xbd, 0x27, 0xa3, 0x46, 0xa1, 0xa0,
-	0x00, 0x35, 0xf6, 0x9d, 0xe3, 0x78, 0xd7, 0xb1, 0x09, 0x71, 0x9a, 0x47, 0x28, 0xc0, 0x24, 0xb6,
-	0x8c, 0x07, 0xb5, 0x67, 0x51, 0x51, 0xf4, 0x6c, 0xb7, 0x45, 0x73, 0xbb, 0x1e, 0xb7, 0x26, 0xde,
-	0xb4, 0xdf, 0xcc, 0x2d, 0xb9, 0x5d, 0xd3, 0x88, 0x8c, 0xb8, 0x10, 0x90, 0xe7, 0x4d, 0x72, 0xfb,
-	0xef, 0xe4, 0xc1, 0x86, 0xa9, 0x9b, 0xcf, 0xbf, 0x48, 0xdc, 0x56, 0xc7, 0x6d, 0x35, 0x77, 0xd5,
-	0xd8, 0xc5, 0x35, 0xe9, 0xd5, 0xc8, 0x7f, 0xd2, 0x7e, 0xc0, 0x0c, 0x82, 0x7f, 0x74, 0x8f, 0xf4,
-	0x22, 0xcc, 0x72, 0x54, 0x10, 0x78, 0xd5, 0xeb, 0xbb, 0x44, 0x41, 0xe0, 0xd3, 0x3e, 0xe9, 0xdc,
-	0x16, 0x90, 0xd9, 0xa0, 0x75, 0x73, 0x45, 0x0e, 0x04, 0xa6, 0xbf, 0xf4, 0x2c, 0xbd, 0x97, 0x96,
-	0x42, 0xf9, 0xe1, 0xd3, 0xe7, 0x59, 0xcc, 0x4b, 0x16, 0x55, 0xdf, 0xe6, 0x5a, 0x57, 0x9d, 0xaf,
-	0x5d, 0xf7, 0x77, 0x2e, 0x3f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x33, 0xd5, 0x99, 0x3f, 0xfd, 0x01,
+	// 514 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x53, 0xdd, 0x6e, 0xd3, 0x30,
+	0x14, 0x5e, 0x96, 0xfe, 0xa4, 0xa7, 0xb4, 0x8b, 0x7c, 0x01, 0x61, 0x08, 0xb5, 0x14, 0x84, 0xaa,
+	0x5d, 0xa4, 0x52, 0x61, 0x57, 0x70, 0xd3, 0x55, 0x0c, 0x84, 0xa6, 0x6d, 0x72, 0x27, 0x2e, 0x76,
+	0x53, 0x79, 0xc1, 0xcb, 0x22, 0x9c, 0x1c, 0xcb, 0x31, 0x51, 0xbb, 0x47, 0xe1, 0x11, 0x78, 0x36,
+	0x1e, 0x02, 0xd9, 0x69, 0xda, 0x0e, 0x36, 0x21, 0xee, 0xce, 0x71, 0xbe, 0x3f, 0xf9, 0x8b, 0xe1,
+	0x65, 0x31, 0x56, 0x6c, 0x19, 0x46, 0x98, 0x8e, 0x22, 0x54, 0x7c, 0xc4, 0xa4, 0x1c, 0x09, 0x8c,
+	0x47, 0x11, 0x66, 0xd7, 0x49, 0x1c, 0x4a, 0x85, 0x1a, 0x09, 0xa9, 0x40, 0x8a, 0x87, 0x4c, 0xca,
+	0x50, 0x60, 0xbc, 0xff, 0x27, 0x31, 0xc2, 0x34, 0xc5, 0xcc, 0x72, 0x05, 0xae, 0x88, 0x83, 0x5f,
+	0x2e, 0x34, 0xa6, 0x56, 0x89, 0x4c, 0xa0, 0xcb, 0x95, 0x42, 0x35, 0x17, 0x18, 0xcf, 0xf5, 0x52,
+	0xf2, 0xc0, 0xe9, 0x3b, 0xc3, 0xee, 0xf8, 0x59, 0xf8, 0xb7, 0x78, 0x78, 0x82, 0xf1, 0xc5, 0x52,
+	0x72, 0xfa, 0xc8, 0x52, 0x56, 0x1b, 0xf9, 0x08, 0x7b, 0x1b, 0x09, 0xc1, 0x0b, 0x2e, 0x82, 0x5d,
+	0xab, 0xd1, 0xdb, 0xd6, 0x28, 0x83, 0x58, 0x99, 0x19, 0x2f, 0xb8, 0x4a, 0xf4, 0x92, 0x76, 0x2a,
+	0x9d, 0x13, 0xc3, 0x22, 0xaf, 0xb6, 0xb3, 0x48, 0xa6, 0x6f, 0x02, 0xb7, 0xef, 0x0c, 0x5b, 0x1b,
+	0xbb, 0x73, 0xa6, 0x6f, 0xc8, 0x14, 0xf6, 0x58, 0x14, 0xf1, 0x3c, 0xdf, 0x44, 0xae, 0xfd, 0x3b,
+	0x72, 0xa7, 0xe4, 0x54, 0x99, 0x5f, 0xdf, 0x11, 0xb1, 0x5e, 0x75, 0xeb, 0xb5, 0xc1, 0x59, 0xb3,
+	0xf7, 0x00, 0x06, 0x70, 0x8d, 0x2a, 0x65, 0x3a, 0x68, 0x58, 0x9f, 0xe7, 0x0f, 0xf8, 0x1c, 0x5b,
+	0x10, 0x6d, 0x89, 0x6a, 0x24, 0xef, 0xc0, 0x53, 0xa8, 0x99, 0x4e, 0x30, 0x0b, 0x9a, 0x7d, 0x67,
+	0xd8, 0x1e, 0xf7, 0x1e, 0xe0, 0xd2, 0x15, 0x8c, 0xae, 0x09, 0xe4, 0x13, 0x74, 0xed, 0x65, 0xce,
+	0xb1, 0xe0, 0x4a, 0x25, 0x5f, 0x79, 0xe0, 0xf5, 0xdd, 0x61, 0x7b, 0xfc, 0xe2, 0x5e, 0x09, 0x83,
+	0x3c, 0x5b, 0x01, 0x69, 0x47, 0x6c, 0xaf, 0x83, 0x1f, 0x0e, 0xb4, 0xb7, 0x3c, 0xc8, 0x53, 0xf0,
+	0x52, 0xb6, 0x98, 0xe7, 0xc9, 0x6d, 0xd9, 0xb6, 0x4b, 0x9b, 0x29, 0x5b, 0xcc, 0x92, 0x5b, 0x4e,
+	0xf6, 0xc1, 0x4b, 0x32, 0xcd, 0x55, 0xc1, 0xca, 0x12, 0x5d, 0xba, 0xde, 0x49, 0x0f, 0xda, 0x86,
+	0x76, 0xc5, 0xa2, 0x6f, 0xdf, 0x65, 0x6e, 0xbb, 0xe9, 0x50, 0x48, 0xd9, 0xe2, 0xa8, 0x3c, 0x21,
+	0x4f, 0xc0, 0xe8, 0xcc, 0x59, 0x5c, 0x36, 0xe2, 0xd2, 0x46, 0xca, 0x16, 0x93, 0xd8, 0xaa, 0x46,
+	0x98, 0x4a, 0xc5, 0xf3, 0xdc, 0x5e, 0xb3, 0x47, 0xd7, 0xfb, 0xe0, 0x12, 0x3a, 0x77, 0xc2, 0x13,
+	0x02, 0x35, 0xdb, 0x87, 0x63, 0xfb, 0xb0, 0x33, 0x39, 0x84, 0xfa, 0x7f, 0xfd, 0x58, 0x25, 0xfa,
+	0xe0, 0x10, 0x9a, 0x55, 0xe1, 0x1e, 0xd4, 0x4e, 0x31, 0xe3, 0xfe, 0x0e, 0x69, 0x43, 0x73, 0x8a,
+	0x59, 0x8e, 0x82, 0xfb, 0x8e, 0x39, 0x3e, 0x4e, 0x04, 0xf7, 0x77, 0x49, 0x0b, 0xea, 0x1f, 0x0a,
+	0x9e, 0x69, 0xdf, 0x3d, 0xe8, 0x41, 0x6b, 0x5d, 0xa7, 0x41, 0x5c, 0xf0, 0x85, 0xf6, 0x77, 0xcc,
+	0xf4, 0x79, 0x76, 0x76, 0xea, 0x3b, 0x47, 0x6f, 0xe1, 0x71, 0x84, 0xe9, 0x3d, 0x3d, 0x9c, 0x3b,
+	0x97, 0xae, 0xc0, 0xf8, 0xe7, 0x2e, 0xf9, 0x32, 0xa6, 0x6c, 0x19, 0x4e, 0xcd, 0xb7, 0x89, 0x94,
+	0xa6, 0xe2, 0xab, 0x86, 0x7d, 0x7c, 0x6f, 0x7e, 0x0f, 0x00, 0x81, 0x9c, 0xa7, 0xd3, 0xdc, 0x03,
 	0x00, 0x00,
 }
@@ -0,0 +1,48 @@
+// +build !confonly
+
+package log
+
+import (
+	"strings"
+
+	"v2ray.com/core/common/log"
+)
+
+const pathPrefix = "v2ray.com/core"
+
+// trimPath returns the path of a package relative to v2ray.com/core.
+func trimPath(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, pathPrefix), "/")
+}
+
+// levelOverrides are levels of packages, keyed by their paths relative to v2ray.com/core.
+type levelOverrides map[string]log.Severity
+
+func newLevelOverrides(config []*LevelOverride) levelOverrides {
+	o := make(levelOverrides)
+	for _, c := range config {
+		if path := trimPath(c.Path); len(path) > 0 {
+			o[path] = c.Level
+		}
+	}
+	return o
+}
+
+// match returns the level of the longest path that the package path is under.
+func (o levelOverrides) match(pkgPath string) (log.Severity, bool) {
+	if !strings.HasPrefix(pkgPath, pathPrefix) {
+		return log.Severity_Unknown, false
+	}
+	path := trimPath(pkgPath)
+	for len(path) > 0 {
+		if level, found := o[path]; found {
+			return level, true
+		}
+		idx := strings.LastIndexByte(path, '/')
+		if idx < 0 {
+			break
+		}
+		path = path[:idx]
+	}
+	return log.Severity_Unknown, false
+}
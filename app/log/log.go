@@ -10,8 +10,11 @@ import (
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/log"
+	"v2ray.com/core/common/signal/pubsub"
 )
 
+const messageTopic = "message"
+
 // Instance is a log.Handler that handles logs.
 type Instance struct {
 	sync.RWMutex
@@ -19,13 +22,19 @@ type Instance struct {
 	accessLogger log.Handler
 	errorLogger  log.Handler
 	active       bool
+	level        log.Severity
+	overrides    levelOverrides
+	messages     *pubsub.Service
 }
 
 // New creates a new log.Instance based on the given config.
 func New(ctx context.Context, config *Config) (*Instance, error) {
 	g := &Instance{
-		config: config,
-		active: false,
+		config:    config,
+		active:    false,
+		level:     config.ErrorLogLevel,
+		overrides: newLevelOverrides(config.LevelOverride),
+		messages:  pubsub.NewService(),
 	}
 	log.RegisterHandler(g)
 
@@ -41,7 +50,9 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 
 func (g *Instance) initAccessLogger() error {
 	handler, err := createHandler(g.config.AccessLogType, HandlerCreatorOptions{
-		Path: g.config.AccessLogPath,
+		Path:     g.config.AccessLogPath,
+		Format:   g.config.LogFormat,
+		Rotation: g.config.Rotation,
 	})
 	if err != nil {
 		return err
@@ -52,7 +63,9 @@ func (g *Instance) initAccessLogger() error {
 
 func (g *Instance) initErrorLogger() error {
 	handler, err := createHandler(g.config.ErrorLogType, HandlerCreatorOptions{
-		Path: g.config.ErrorLogPath,
+		Path:     g.config.ErrorLogPath,
+		Format:   g.config.LogFormat,
+		Rotation: g.config.Rotation,
 	})
 	if err != nil {
 		return err
@@ -91,8 +104,29 @@ func (g *Instance) Start() error {
 	return g.startInternal()
 }
 
+// SubscribeMessages returns a subscriber that receives all messages recorded, regardless of the levels of error logs.
+// Messages are dropped if the subscriber is not fast enough.
+func (g *Instance) SubscribeMessages() *pubsub.Subscriber {
+	return g.messages.Subscribe(messageTopic)
+}
+
+// HandlesClosedAccess implements log.ClosedAccessHandler. The access messages of closed sessions are only written in
+// JSON format, but subscribers receive them in any format.
+func (g *Instance) HandlesClosedAccess() bool {
+	if g.messages.HasSubscriber(messageTopic) {
+		return true
+	}
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.active && g.accessLogger != nil && g.config.LogFormat == LogFormat_JSON
+}
+
 // Handle implements log.Handler.
 func (g *Instance) Handle(msg log.Message) {
+	g.messages.Publish(messageTopic, msg)
+
 	g.RLock()
 	defer g.RUnlock()
 
@@ -102,11 +136,16 @@ func (g *Instance) Handle(msg log.Message) {
 
 	switch msg := msg.(type) {
 	case *log.AccessMessage:
+		// A closed session is already logged when it is accepted. The record with its traffic is only useful in
+		// structured output.
+		if msg.Status == log.AccessClosed && g.config.LogFormat != LogFormat_JSON {
+			return
+		}
 		if g.accessLogger != nil {
 			g.accessLogger.Handle(msg)
 		}
 	case *log.GeneralMessage:
-		if g.errorLogger != nil && msg.Severity <= g.config.ErrorLogLevel {
+		if g.errorLogger != nil && msg.Severity <= g.levelOf(msg) {
 			g.errorLogger.Handle(msg)
 		}
 	default:
@@ -114,6 +153,57 @@ func (g *Instance) Handle(msg log.Message) {
 	}
 }
 
+func (g *Instance) levelOf(msg *log.GeneralMessage) log.Severity {
+	if len(g.overrides) > 0 {
+		if c, ok := msg.Content.(log.StructuredContent); ok {
+			if level, found := g.overrides.match(c.LogPath()); found {
+				return level
+			}
+		}
+	}
+	return g.level
+}
+
+// SetLevel sets the level of error logs from packages under the path, such as "proxy/dokodemo". The path is relative
+// to v2ray.com/core, and an empty path sets the level of all other packages.
+func (g *Instance) SetLevel(path string, level log.Severity) {
+	g.Lock()
+	defer g.Unlock()
+
+	path = trimPath(path)
+	if len(path) == 0 {
+		g.level = level
+		return
+	}
+	g.overrides[path] = level
+}
+
+// ResetLevel removes the level set for the path, so that packages under it use the level of their parent path. An
+// empty path resets the level of all other packages to the one in config.
+func (g *Instance) ResetLevel(path string) {
+	g.Lock()
+	defer g.Unlock()
+
+	path = trimPath(path)
+	if len(path) == 0 {
+		g.level = g.config.ErrorLogLevel
+		return
+	}
+	delete(g.overrides, path)
+}
+
+// Levels returns the level of all other packages, and the levels set for paths.
+func (g *Instance) Levels() (log.Severity, map[string]log.Severity) {
+	g.RLock()
+	defer g.RUnlock()
+
+	overrides := make(map[string]log.Severity, len(g.overrides))
+	for path, level := range g.overrides {
+		overrides[path] = level
+	}
+	return g.level, overrides
+}
+
 // Close implements common.Closable.Close().
 func (g *Instance) Close() error {
 	newError("Logger closing").AtDebug().WriteToLog()
@@ -3,12 +3,59 @@
 package log
 
 import (
+	golog "log"
+	"time"
+
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/log"
 )
 
 type HandlerCreatorOptions struct {
-	Path string
+	Path     string
+	Format   LogFormat
+	Rotation *LogRotation
+}
+
+// jsonHandler writes messages as JSON objects into the underlying handler.
+type jsonHandler struct {
+	handler log.Handler
+}
+
+func (h *jsonHandler) Handle(msg log.Message) {
+	h.handler.Handle(&log.JSONMessage{
+		Time:    time.Now(),
+		Message: msg,
+	})
+}
+
+// Close implements common.Closable.
+func (h *jsonHandler) Close() error {
+	return common.Close(h.handler)
+}
+
+func (r *LogRotation) options() log.RotationOptions {
+	return log.RotationOptions{
+		MaxSize:    r.MaxSize,
+		Interval:   time.Duration(r.Interval) * time.Second,
+		MaxBackups: int(r.MaxBackups),
+		MaxAge:     time.Duration(r.MaxAge) * time.Second,
+		Compress:   r.Compress,
+	}
+}
+
+func (o HandlerCreatorOptions) flags() int {
+	if o.Format == LogFormat_JSON {
+		// JSON messages have their own timestamps.
+		return 0
+	}
+	return golog.Ldate | golog.Ltime
+}
+
+func (o HandlerCreatorOptions) wrap(handler log.Handler) log.Handler {
+	if o.Format == LogFormat_JSON {
+		return &jsonHandler{handler: handler}
+	}
+	return handler
 }
 
 type HandlerCreator func(LogType, HandlerCreatorOptions) (log.Handler, error)
@@ -36,15 +83,21 @@ func createHandler(logType LogType, options HandlerCreatorOptions) (log.Handler,
 
 func init() {
 	common.Must(RegisterHandlerCreator(LogType_Console, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
-		return log.NewLogger(log.CreateStdoutLogWriter()), nil
+		return options.wrap(log.NewLogger(log.CreateStdoutLogWriterWithFlags(options.flags()))), nil
 	}))
 
 	common.Must(RegisterHandlerCreator(LogType_File, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
-		creator, err := log.CreateFileLogWriter(options.Path)
+		var creator log.WriterCreator
+		var err error
+		if options.Rotation != nil {
+			creator, err = log.CreateRotatingFileLogWriter(options.Path, options.flags(), options.Rotation.options())
+		} else {
+			creator, err = log.CreateFileLogWriterWithFlags(options.Path, options.flags())
+		}
 		if err != nil {
 			return nil, err
 		}
-		return log.NewLogger(creator), nil
+		return options.wrap(log.NewLogger(creator)), nil
 	}))
 
 	common.Must(RegisterHandlerCreator(LogType_None, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
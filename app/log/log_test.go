@@ -5,9 +5,13 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	"v2ray.com/core/app/log"
+	"v2ray.com/core/app/router"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
 	clog "v2ray.com/core/common/log"
+	"v2ray.com/core/common/net"
 	"v2ray.com/core/testing/mocks"
 )
 
@@ -50,3 +54,120 @@ func TestCustomLogHandler(t *testing.T) {
 
 	common.Must(logger.Close())
 }
+
+func TestLevelOverride(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	var loggedValue []string
+
+	mockHandler := mocks.NewLogHandler(mockCtl)
+	mockHandler.EXPECT().Handle(gomock.Any()).AnyTimes().DoAndReturn(func(msg clog.Message) {
+		loggedValue = append(loggedValue, msg.String())
+	})
+
+	log.RegisterHandlerCreator(log.LogType_Console, func(lt log.LogType, options log.HandlerCreatorOptions) (clog.Handler, error) {
+		return mockHandler, nil
+	})
+
+	logger, err := log.New(context.Background(), &log.Config{
+		ErrorLogLevel: clog.Severity_Warning,
+		ErrorLogType:  log.LogType_Console,
+		AccessLogType: log.LogType_None,
+		LevelOverride: []*log.LevelOverride{
+			{Path: "app", Level: clog.Severity_Debug},
+			{Path: "app/router", Level: clog.Severity_Error},
+		},
+	})
+	common.Must(err)
+	common.Must(logger.Start())
+
+	record := func(pathObj interface{}, msg string) {
+		clog.Record(&clog.GeneralMessage{
+			Severity: clog.Severity_Info,
+			Content:  errors.New(msg).WithPathObj(pathObj),
+		})
+	}
+
+	loggedValue = nil
+	record(log.Config{}, "app/log")
+	record(router.Config{}, "app/router")
+	record(net.Destination{}, "common/net")
+	if r := cmp.Diff(loggedValue, []string{"[Info] v2ray.com/core/app/log: app/log"}); r != "" {
+		t.Error(r)
+	}
+
+	logger.SetLevel("common", clog.Severity_Info)
+	logger.SetLevel("app", clog.Severity_Warning)
+	loggedValue = nil
+	record(log.Config{}, "app/log")
+	record(net.Destination{}, "common/net")
+	if r := cmp.Diff(loggedValue, []string{"[Info] v2ray.com/core/common/net: common/net"}); r != "" {
+		t.Error(r)
+	}
+
+	logger.ResetLevel("common")
+	logger.SetLevel("", clog.Severity_Info)
+	loggedValue = nil
+	record(net.Destination{}, "common/net")
+	if len(loggedValue) != 1 {
+		t.Error("expect message logged at the global level, but got ", loggedValue)
+	}
+
+	common.Must(logger.Close())
+}
+
+func TestClosedAccessMessage(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	var loggedValue []string
+
+	mockHandler := mocks.NewLogHandler(mockCtl)
+	mockHandler.EXPECT().Handle(gomock.Any()).AnyTimes().DoAndReturn(func(msg clog.Message) {
+		loggedValue = append(loggedValue, msg.String())
+	})
+
+	log.RegisterHandlerCreator(log.LogType_Console, func(lt log.LogType, options log.HandlerCreatorOptions) (clog.Handler, error) {
+		return mockHandler, nil
+	})
+
+	for _, format := range []log.LogFormat{log.LogFormat_Text, log.LogFormat_JSON} {
+		logger, err := log.New(context.Background(), &log.Config{
+			ErrorLogType:  log.LogType_None,
+			AccessLogType: log.LogType_Console,
+			LogFormat:     format,
+		})
+		common.Must(err)
+		common.Must(logger.Start())
+
+		loggedValue = nil
+		clog.Record(&clog.AccessMessage{
+			From:   "127.0.0.1:1234",
+			To:     "tcp:v2ray.com:443",
+			Status: clog.AccessAccepted,
+		})
+		clog.Record(&clog.AccessMessage{
+			From:   "127.0.0.1:1234",
+			To:     "tcp:v2ray.com:443",
+			Status: clog.AccessClosed,
+		})
+
+		expected := 1
+		if format == log.LogFormat_JSON {
+			expected = 2
+		}
+		if len(loggedValue) != expected {
+			t.Error("expect ", expected, " access messages in format ", format, ", but got ", loggedValue)
+		}
+		if handled := clog.ClosedAccessHandled(); handled != (format == log.LogFormat_JSON) {
+			t.Error("expect closed access handled to be ", !handled, " in format ", format)
+		}
+		sub := logger.SubscribeMessages()
+		if !clog.ClosedAccessHandled() {
+			t.Error("expect closed access handled with subscriber in format ", format)
+		}
+		common.Must(sub.Close())
+		common.Must(logger.Close())
+	}
+}
@@ -0,0 +1,92 @@
+// +build !confonly
+
+package command
+
+//go:generate errorgen
+
+import (
+	"context"
+	"strings"
+
+	grpc "google.golang.org/grpc"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/features/policy"
+)
+
+// policyServer is an implementation of PolicyService.
+type policyServer struct {
+	quota policy.QuotaManager
+}
+
+func NewPolicyServer(manager policy.Manager) PolicyServiceServer {
+	s := new(policyServer)
+	if qm, ok := manager.(policy.QuotaManager); ok {
+		s.quota = qm
+	}
+	return s
+}
+
+func quotaUsage(u policy.QuotaUsage) *QuotaUsage {
+	return &QuotaUsage{
+		Email:       u.Email,
+		Quota:       u.Quota.Bytes,
+		Period:      u.Quota.Period.String(),
+		ExceededTag: u.Quota.ExceededTag,
+		Used:        u.Used,
+		ResetTime:   u.Reset.Unix(),
+		Exceeded:    u.Exceeded(),
+	}
+}
+
+func (s *policyServer) GetQuotaUsage(ctx context.Context, request *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	if s.quota == nil {
+		return nil, newError("quotas are not supported by policy manager")
+	}
+
+	response := new(GetQuotaUsageResponse)
+	for _, u := range s.quota.QuotaUsages() {
+		if strings.Contains(u.Email, request.Pattern) {
+			response.Usage = append(response.Usage, quotaUsage(u))
+		}
+	}
+	return response, nil
+}
+
+func (s *policyServer) ResetQuotaUsage(ctx context.Context, request *ResetQuotaUsageRequest) (*ResetQuotaUsageResponse, error) {
+	if s.quota == nil {
+		return nil, newError("quotas are not supported by policy manager")
+	}
+
+	if err := s.quota.ResetQuota(request.Email); err != nil {
+		return nil, err
+	}
+	response := new(ResetQuotaUsageResponse)
+	for _, u := range s.quota.QuotaUsages() {
+		if u.Email == request.Email {
+			response.Usage = quotaUsage(u)
+		}
+	}
+	return response, nil
+}
+
+type service struct {
+	policyManager policy.Manager
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterPolicyServiceServer(server, NewPolicyServer(s.policyManager))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(pm policy.Manager) {
+			s.policyManager = pm
+		})
+
+		return s, nil
+	}))
+}
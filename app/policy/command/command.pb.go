@@ -0,0 +1,463 @@
+package command
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type QuotaUsage struct {
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Traffic in bytes allowed in each period.
+	Quota int64 `protobuf:"varint,2,opt,name=quota,proto3" json:"quota,omitempty"`
+	// Period of the quota, "day", "week" or "month".
+	Period string `protobuf:"bytes,3,opt,name=period,proto3" json:"period,omitempty"`
+	// Tag of the outbound that connections are routed to when the quota is
+	// exceeded. Connections are rejected if empty.
+	ExceededTag string `protobuf:"bytes,4,opt,name=exceeded_tag,json=exceededTag,proto3" json:"exceeded_tag,omitempty"`
+	// Traffic in bytes used in the current period.
+	Used int64 `protobuf:"varint,5,opt,name=used,proto3" json:"used,omitempty"`
+	// Unix time in seconds when the usage is reset.
+	ResetTime            int64    `protobuf:"varint,6,opt,name=reset_time,json=resetTime,proto3" json:"reset_time,omitempty"`
+	Exceeded             bool     `protobuf:"varint,7,opt,name=exceeded,proto3" json:"exceeded,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaUsage) Reset()         { *m = QuotaUsage{} }
+func (m *QuotaUsage) String() string { return proto.CompactTextString(m) }
+func (*QuotaUsage) ProtoMessage()    {}
+func (*QuotaUsage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{0}
+}
+
+func (m *QuotaUsage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaUsage.Unmarshal(m, b)
+}
+func (m *QuotaUsage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaUsage.Marshal(b, m, deterministic)
+}
+func (m *QuotaUsage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaUsage.Merge(m, src)
+}
+func (m *QuotaUsage) XXX_Size() int {
+	return xxx_messageInfo_QuotaUsage.Size(m)
+}
+func (m *QuotaUsage) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaUsage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaUsage proto.InternalMessageInfo
+
+func (m *QuotaUsage) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetQuota() int64 {
+	if m != nil {
+		return m.Quota
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetPeriod() string {
+	if m != nil {
+		return m.Period
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetExceededTag() string {
+	if m != nil {
+		return m.ExceededTag
+	}
+	return ""
+}
+
+func (m *QuotaUsage) GetUsed() int64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetResetTime() int64 {
+	if m != nil {
+		return m.ResetTime
+	}
+	return 0
+}
+
+func (m *QuotaUsage) GetExceeded() bool {
+	if m != nil {
+		return m.Exceeded
+	}
+	return false
+}
+
+type GetQuotaUsageRequest struct {
+	// Part of emails of users to get usages of. Usages of all users are
+	// returned if empty.
+	Pattern              string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQuotaUsageRequest) Reset()         { *m = GetQuotaUsageRequest{} }
+func (m *GetQuotaUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*GetQuotaUsageRequest) ProtoMessage()    {}
+func (*GetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{1}
+}
+
+func (m *GetQuotaUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuotaUsageRequest.Unmarshal(m, b)
+}
+func (m *GetQuotaUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuotaUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *GetQuotaUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuotaUsageRequest.Merge(m, src)
+}
+func (m *GetQuotaUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_GetQuotaUsageRequest.Size(m)
+}
+func (m *GetQuotaUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuotaUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuotaUsageRequest proto.InternalMessageInfo
+
+func (m *GetQuotaUsageRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+type GetQuotaUsageResponse struct {
+	Usage                []*QuotaUsage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetQuotaUsageResponse) Reset()         { *m = GetQuotaUsageResponse{} }
+func (m *GetQuotaUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*GetQuotaUsageResponse) ProtoMessage()    {}
+func (*GetQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{2}
+}
+
+func (m *GetQuotaUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQuotaUsageResponse.Unmarshal(m, b)
+}
+func (m *GetQuotaUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQuotaUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *GetQuotaUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQuotaUsageResponse.Merge(m, src)
+}
+func (m *GetQuotaUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_GetQuotaUsageResponse.Size(m)
+}
+func (m *GetQuotaUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQuotaUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQuotaUsageResponse proto.InternalMessageInfo
+
+func (m *GetQuotaUsageResponse) GetUsage() []*QuotaUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+type ResetQuotaUsageRequest struct {
+	Email                string   `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResetQuotaUsageRequest) Reset()         { *m = ResetQuotaUsageRequest{} }
+func (m *ResetQuotaUsageRequest) String() string { return proto.CompactTextString(m) }
+func (*ResetQuotaUsageRequest) ProtoMessage()    {}
+func (*ResetQuotaUsageRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{3}
+}
+
+func (m *ResetQuotaUsageRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetQuotaUsageRequest.Unmarshal(m, b)
+}
+func (m *ResetQuotaUsageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetQuotaUsageRequest.Marshal(b, m, deterministic)
+}
+func (m *ResetQuotaUsageRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetQuotaUsageRequest.Merge(m, src)
+}
+func (m *ResetQuotaUsageRequest) XXX_Size() int {
+	return xxx_messageInfo_ResetQuotaUsageRequest.Size(m)
+}
+func (m *ResetQuotaUsageRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetQuotaUsageRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetQuotaUsageRequest proto.InternalMessageInfo
+
+func (m *ResetQuotaUsageRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type ResetQuotaUsageResponse struct {
+	Usage                *QuotaUsage `protobuf:"bytes,1,opt,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ResetQuotaUsageResponse) Reset()         { *m = ResetQuotaUsageResponse{} }
+func (m *ResetQuotaUsageResponse) String() string { return proto.CompactTextString(m) }
+func (*ResetQuotaUsageResponse) ProtoMessage()    {}
+func (*ResetQuotaUsageResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{4}
+}
+
+func (m *ResetQuotaUsageResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResetQuotaUsageResponse.Unmarshal(m, b)
+}
+func (m *ResetQuotaUsageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResetQuotaUsageResponse.Marshal(b, m, deterministic)
+}
+func (m *ResetQuotaUsageResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResetQuotaUsageResponse.Merge(m, src)
+}
+func (m *ResetQuotaUsageResponse) XXX_Size() int {
+	return xxx_messageInfo_ResetQuotaUsageResponse.Size(m)
+}
+func (m *ResetQuotaUsageResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResetQuotaUsageResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResetQuotaUsageResponse proto.InternalMessageInfo
+
+func (m *ResetQuotaUsageResponse) GetUsage() *QuotaUsage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+type Config struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_234074e3b0806ec7, []int{5}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*QuotaUsage)(nil), "v2ray.core.app.policy.command.QuotaUsage")
+	proto.RegisterType((*GetQuotaUsageRequest)(nil), "v2ray.core.app.policy.command.GetQuotaUsageRequest")
+	proto.RegisterType((*GetQuotaUsageResponse)(nil), "v2ray.core.app.policy.command.GetQuotaUsageResponse")
+	proto.RegisterType((*ResetQuotaUsageRequest)(nil), "v2ray.core.app.policy.command.ResetQuotaUsageRequest")
+	proto.RegisterType((*ResetQuotaUsageResponse)(nil), "v2ray.core.app.policy.command.ResetQuotaUsageResponse")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.command.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/policy/command/command.proto", fileDescriptor_234074e3b0806ec7)
+}
+
+var fileDescriptor_234074e3b0806ec7 = []byte{
+	// 403 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x53, 0x5d, 0x8b, 0xd3, 0x40,
+	0x14, 0x75, 0xda, 0x6d, 0xda, 0xbd, 0xeb, 0x22, 0x0c, 0xeb, 0x3a, 0x14, 0x0a, 0xd9, 0x3c, 0xc5,
+	0x97, 0x89, 0x64, 0xd5, 0x57, 0xd1, 0x3e, 0xf8, 0x26, 0x6b, 0xac, 0x22, 0x7d, 0x29, 0x63, 0x72,
+	0x0d, 0x81, 0x26, 0x33, 0x9d, 0x4c, 0x8a, 0x05, 0x9f, 0xfc, 0x09, 0xfe, 0x0c, 0x7f, 0x86, 0xbf,
+	0x4c, 0x32, 0x49, 0xfc, 0x68, 0x4b, 0x4b, 0x7d, 0xca, 0x9c, 0x7b, 0xcf, 0x39, 0x9c, 0x7b, 0x20,
+	0x10, 0xac, 0x43, 0x2d, 0x36, 0x3c, 0x96, 0x79, 0x10, 0x4b, 0x8d, 0x81, 0x50, 0x2a, 0x50, 0x72,
+	0x99, 0xc5, 0x9b, 0x20, 0x96, 0x79, 0x2e, 0x8a, 0xa4, 0xfb, 0x72, 0xa5, 0xa5, 0x91, 0x74, 0xd2,
+	0x09, 0x34, 0x72, 0xa1, 0x14, 0x6f, 0xc8, 0xbc, 0x25, 0x79, 0x3f, 0x09, 0xc0, 0xdb, 0x4a, 0x1a,
+	0xf1, 0xbe, 0x14, 0x29, 0xd2, 0x2b, 0x18, 0x60, 0x2e, 0xb2, 0x25, 0x23, 0x2e, 0xf1, 0xcf, 0xa3,
+	0x06, 0xd4, 0xd3, 0x55, 0xcd, 0x61, 0x3d, 0x97, 0xf8, 0xfd, 0xa8, 0x01, 0xf4, 0x1a, 0x1c, 0x85,
+	0x3a, 0x93, 0x09, 0xeb, 0x5b, 0x72, 0x8b, 0xe8, 0x0d, 0xdc, 0xc7, 0x2f, 0x31, 0x62, 0x82, 0xc9,
+	0xc2, 0x88, 0x94, 0x9d, 0xd9, 0xed, 0x45, 0x37, 0x9b, 0x89, 0x94, 0x52, 0x38, 0xab, 0x4a, 0x4c,
+	0xd8, 0xc0, 0xfa, 0xd9, 0x37, 0x9d, 0x00, 0x68, 0x2c, 0xd1, 0x2c, 0x4c, 0x96, 0x23, 0x73, 0xec,
+	0xe6, 0xdc, 0x4e, 0x66, 0x59, 0x8e, 0x74, 0x0c, 0xa3, 0xce, 0x81, 0x0d, 0x5d, 0xe2, 0x8f, 0xa2,
+	0xdf, 0xd8, 0x7b, 0x02, 0x57, 0xaf, 0xd1, 0xfc, 0x39, 0x23, 0xc2, 0x55, 0x85, 0xa5, 0xa1, 0x0c,
+	0x86, 0x4a, 0x18, 0x83, 0xba, 0x68, 0xef, 0xe9, 0xa0, 0xf7, 0x11, 0x1e, 0x6e, 0x29, 0x4a, 0x25,
+	0x8b, 0x12, 0xe9, 0x0b, 0x18, 0x54, 0xf5, 0x80, 0x11, 0xb7, 0xef, 0x5f, 0x84, 0x8f, 0xf9, 0xc1,
+	0xfa, 0xf8, 0x5f, 0x0e, 0x8d, 0xce, 0xe3, 0x70, 0x1d, 0xd5, 0xa1, 0x77, 0xd3, 0xec, 0xed, 0xd6,
+	0x9b, 0xc3, 0xa3, 0x1d, 0xfe, 0x6e, 0x16, 0xf2, 0x5f, 0x59, 0x46, 0xe0, 0x4c, 0x65, 0xf1, 0x39,
+	0x4b, 0xc3, 0xef, 0x3d, 0xb8, 0xbc, 0xb3, 0xf4, 0x77, 0xa8, 0xd7, 0x59, 0x8c, 0xf4, 0x2b, 0x5c,
+	0xfe, 0xd3, 0x00, 0xbd, 0x3d, 0x62, 0xbf, 0xaf, 0xe1, 0xf1, 0xd3, 0xd3, 0x44, 0xcd, 0x61, 0xde,
+	0x3d, 0xfa, 0x8d, 0xc0, 0x83, 0xad, 0xb3, 0xe9, 0xb3, 0x23, 0x5e, 0xfb, 0x6b, 0x1d, 0x3f, 0x3f,
+	0x55, 0xd6, 0x85, 0x78, 0xf5, 0x06, 0x6e, 0x62, 0x99, 0x1f, 0x96, 0xdf, 0x91, 0xf9, 0xb0, 0x7d,
+	0xfe, 0xe8, 0x4d, 0x3e, 0x84, 0x91, 0xd8, 0xf0, 0x69, 0x4d, 0x7d, 0xa9, 0x14, 0x6f, 0x1a, 0xe5,
+	0xd3, 0x66, 0xff, 0xc9, 0xb1, 0x7f, 0xdc, 0xed, 0xaf, 0x01, 0x00, 0x94, 0x79, 0xae, 0xc8, 0xa4,
+	0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PolicyServiceClient is the client API for PolicyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PolicyServiceClient interface {
+	GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error)
+	ResetQuotaUsage(ctx context.Context, in *ResetQuotaUsageRequest, opts ...grpc.CallOption) (*ResetQuotaUsageResponse, error)
+}
+
+type policyServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPolicyServiceClient(cc *grpc.ClientConn) PolicyServiceClient {
+	return &policyServiceClient{cc}
+}
+
+func (c *policyServiceClient) GetQuotaUsage(ctx context.Context, in *GetQuotaUsageRequest, opts ...grpc.CallOption) (*GetQuotaUsageResponse, error) {
+	out := new(GetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.policy.command.PolicyService/GetQuotaUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyServiceClient) ResetQuotaUsage(ctx context.Context, in *ResetQuotaUsageRequest, opts ...grpc.CallOption) (*ResetQuotaUsageResponse, error) {
+	out := new(ResetQuotaUsageResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.policy.command.PolicyService/ResetQuotaUsage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PolicyServiceServer is the server API for PolicyService service.
+type PolicyServiceServer interface {
+	GetQuotaUsage(context.Context, *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error)
+	ResetQuotaUsage(context.Context, *ResetQuotaUsageRequest) (*ResetQuotaUsageResponse, error)
+}
+
+// UnimplementedPolicyServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedPolicyServiceServer struct {
+}
+
+func (*UnimplementedPolicyServiceServer) GetQuotaUsage(ctx context.Context, req *GetQuotaUsageRequest) (*GetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotaUsage not implemented")
+}
+func (*UnimplementedPolicyServiceServer) ResetQuotaUsage(ctx context.Context, req *ResetQuotaUsageRequest) (*ResetQuotaUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetQuotaUsage not implemented")
+}
+
+func RegisterPolicyServiceServer(s *grpc.Server, srv PolicyServiceServer) {
+	s.RegisterService(&_PolicyService_serviceDesc, srv)
+}
+
+func _PolicyService_GetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyServiceServer).GetQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.policy.command.PolicyService/GetQuotaUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyServiceServer).GetQuotaUsage(ctx, req.(*GetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PolicyService_ResetQuotaUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetQuotaUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PolicyServiceServer).ResetQuotaUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.policy.command.PolicyService/ResetQuotaUsage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PolicyServiceServer).ResetQuotaUsage(ctx, req.(*ResetQuotaUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _PolicyService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.policy.command.PolicyService",
+	HandlerType: (*PolicyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetQuotaUsage",
+			Handler:    _PolicyService_GetQuotaUsage_Handler,
+		},
+		{
+			MethodName: "ResetQuotaUsage",
+			Handler:    _PolicyService_ResetQuotaUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "v2ray.com/core/app/policy/command/command.proto",
+}
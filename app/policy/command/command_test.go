@@ -0,0 +1,49 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/app/policy"
+	. "v2ray.com/core/app/policy/command"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
+	feature_policy "v2ray.com/core/features/policy"
+)
+
+func TestGetQuotaUsage(t *testing.T) {
+	m, err := policy.New(context.Background(), &policy.Config{
+		UserQuota: map[string]*policy.Quota{
+			"test@v2ray.com": {
+				Bytes:  100,
+				Period: policy.Quota_Month,
+			},
+			"love@v2ray.org": {
+				Bytes: 100,
+			},
+		},
+	})
+	common.Must(err)
+
+	feature_policy.QuotaForUser(m, &protocol.MemoryUser{Email: "test@v2ray.com"}).Add(120)
+
+	s := NewPolicyServer(m)
+	resp, err := s.GetQuotaUsage(context.Background(), &GetQuotaUsageRequest{
+		Pattern: "@v2ray.com",
+	})
+	common.Must(err)
+	if len(resp.Usage) != 1 {
+		t.Fatal("unexpected usages: ", resp.Usage)
+	}
+	if u := resp.Usage[0]; u.Email != "test@v2ray.com" || u.Used != 120 || !u.Exceeded || u.Period != "month" || u.ResetTime == 0 {
+		t.Error("unexpected usage: ", u)
+	}
+
+	reset, err := s.ResetQuotaUsage(context.Background(), &ResetQuotaUsageRequest{
+		Email: "test@v2ray.com",
+	})
+	common.Must(err)
+	if reset.Usage.Used != 0 || reset.Usage.Exceeded {
+		t.Error("expect usage reset, but got ", reset.Usage)
+	}
+}
@@ -58,6 +58,38 @@ func (p *Policy) overrideWith(another *Policy) {
 			Connection: another.Buffer.Connection,
 		}
 	}
+	if another.Limit != nil {
+		p.Limit = new(Limit)
+		*p.Limit = *another.Limit
+	}
+	if another.Quota != nil {
+		p.Quota = new(Quota)
+		*p.Quota = *another.Quota
+	}
+}
+
+// ToCoreQuota converts this Quota to policy.Quota.
+func (q *Quota) ToCoreQuota() policy.Quota {
+	if q == nil {
+		return policy.Quota{}
+	}
+	return policy.Quota{
+		Bytes:       int64(q.Bytes),
+		Period:      policy.QuotaPeriod(q.Period),
+		ExceededTag: q.ExceededTag,
+	}
+}
+
+// ToCoreLimit converts this Limit to policy.Limit.
+func (l *Limit) ToCoreLimit() policy.Limit {
+	if l == nil {
+		return policy.Limit{}
+	}
+	return policy.Limit{
+		UplinkRate:   int64(l.UplinkRate),
+		DownlinkRate: int64(l.DownlinkRate),
+		Connections:  l.Connection,
+	}
 }
 
 // ToCorePolicy converts this Policy to policy.Session.
@@ -73,10 +105,13 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Stats != nil {
 		cp.Stats.UserUplink = p.Stats.UserUplink
 		cp.Stats.UserDownlink = p.Stats.UserDownlink
+		cp.Stats.UserConnection = p.Stats.UserConnection
+		cp.Stats.UserThrottle = p.Stats.UserThrottle
 	}
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
 	}
+	cp.Limit = p.Limit.ToCoreLimit()
 	return cp
 }
 
@@ -84,8 +119,14 @@ func (p *Policy) ToCorePolicy() policy.Session {
 func (p *SystemPolicy) ToCorePolicy() policy.System {
 	return policy.System{
 		Stats: policy.SystemStats{
-			InboundUplink:   p.Stats.InboundUplink,
-			InboundDownlink: p.Stats.InboundDownlink,
+			InboundUplink:      p.Stats.InboundUplink,
+			InboundDownlink:    p.Stats.InboundDownlink,
+			InboundConnection:  p.Stats.InboundConnection,
+			OutboundConnection: p.Stats.OutboundConnection,
+			OutboundUplink:     p.Stats.OutboundUplink,
+			OutboundDownlink:   p.Stats.OutboundDownlink,
+			InboundThrottle:    p.Stats.InboundThrottle,
 		},
+		InboundLimit: p.InboundLimit.ToCoreLimit(),
 	}
 }
@@ -17,6 +17,34 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type Quota_Period int32
+
+const (
+	Quota_Day   Quota_Period = 0
+	Quota_Week  Quota_Period = 1
+	Quota_Month Quota_Period = 2
+)
+
+var Quota_Period_name = map[int32]string{
+	0: "Day",
+	1: "Week",
+	2: "Month",
+}
+
+var Quota_Period_value = map[string]int32{
+	"Day":   0,
+	"Week":  1,
+	"Month": 2,
+}
+
+func (x Quota_Period) String() string {
+	return proto.EnumName(Quota_Period_name, int32(x))
+}
+
+func (Quota_Period) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{2, 0}
+}
+
 type Second struct {
 	Value                uint32   `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -56,20 +84,145 @@ func (m *Second) GetValue() uint32 {
 	return 0
 }
 
+// Limit is limits of bandwidth and connections.
+type Limit struct {
+	// Rate of uplink traffic, in bytes per second. 0 for unlimited.
+	UplinkRate uint64 `protobuf:"varint,1,opt,name=uplink_rate,json=uplinkRate,proto3" json:"uplink_rate,omitempty"`
+	// Rate of downlink traffic, in bytes per second. 0 for unlimited.
+	DownlinkRate uint64 `protobuf:"varint,2,opt,name=downlink_rate,json=downlinkRate,proto3" json:"downlink_rate,omitempty"`
+	// Max number of concurrent connections. 0 for unlimited.
+	Connection           uint32   `protobuf:"varint,3,opt,name=connection,proto3" json:"connection,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Limit) Reset()         { *m = Limit{} }
+func (m *Limit) String() string { return proto.CompactTextString(m) }
+func (*Limit) ProtoMessage()    {}
+func (*Limit) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{1}
+}
+
+func (m *Limit) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Limit.Unmarshal(m, b)
+}
+func (m *Limit) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Limit.Marshal(b, m, deterministic)
+}
+func (m *Limit) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Limit.Merge(m, src)
+}
+func (m *Limit) XXX_Size() int {
+	return xxx_messageInfo_Limit.Size(m)
+}
+func (m *Limit) XXX_DiscardUnknown() {
+	xxx_messageInfo_Limit.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Limit proto.InternalMessageInfo
+
+func (m *Limit) GetUplinkRate() uint64 {
+	if m != nil {
+		return m.UplinkRate
+	}
+	return 0
+}
+
+func (m *Limit) GetDownlinkRate() uint64 {
+	if m != nil {
+		return m.DownlinkRate
+	}
+	return 0
+}
+
+func (m *Limit) GetConnection() uint32 {
+	if m != nil {
+		return m.Connection
+	}
+	return 0
+}
+
+// Quota is a traffic quota of a user.
+type Quota struct {
+	// Traffic in bytes allowed in each period, uplink and downlink combined.
+	// The quota is disabled if 0.
+	Bytes uint64 `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	// Period that usages are reset after, in local time.
+	Period Quota_Period `protobuf:"varint,2,opt,name=period,proto3,enum=v2ray.core.app.policy.Quota_Period" json:"period,omitempty"`
+	// Tag of outbound that connections are routed to when the quota is
+	// exceeded. Connections are rejected if empty.
+	ExceededTag          string   `protobuf:"bytes,3,opt,name=exceeded_tag,json=exceededTag,proto3" json:"exceeded_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Quota) Reset()         { *m = Quota{} }
+func (m *Quota) String() string { return proto.CompactTextString(m) }
+func (*Quota) ProtoMessage()    {}
+func (*Quota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{2}
+}
+
+func (m *Quota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Quota.Unmarshal(m, b)
+}
+func (m *Quota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Quota.Marshal(b, m, deterministic)
+}
+func (m *Quota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Quota.Merge(m, src)
+}
+func (m *Quota) XXX_Size() int {
+	return xxx_messageInfo_Quota.Size(m)
+}
+func (m *Quota) XXX_DiscardUnknown() {
+	xxx_messageInfo_Quota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Quota proto.InternalMessageInfo
+
+func (m *Quota) GetBytes() uint64 {
+	if m != nil {
+		return m.Bytes
+	}
+	return 0
+}
+
+func (m *Quota) GetPeriod() Quota_Period {
+	if m != nil {
+		return m.Period
+	}
+	return Quota_Day
+}
+
+func (m *Quota) GetExceededTag() string {
+	if m != nil {
+		return m.ExceededTag
+	}
+	return ""
+}
+
 type Policy struct {
-	Timeout              *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Stats                *Policy_Stats   `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
-	Buffer               *Policy_Buffer  `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Timeout *Policy_Timeout `protobuf:"bytes,1,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Stats   *Policy_Stats   `protobuf:"bytes,2,opt,name=stats,proto3" json:"stats,omitempty"`
+	Buffer  *Policy_Buffer  `protobuf:"bytes,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
+	// Limits of each user. Users without email are limited per source IP in
+	// each inbound, and not limited if the source is unknown.
+	Limit *Limit `protobuf:"bytes,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Quota of each user. Users without email are not limited.
+	Quota                *Quota   `protobuf:"bytes,5,opt,name=quota,proto3" json:"quota,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Policy) Reset()         { *m = Policy{} }
 func (m *Policy) String() string { return proto.CompactTextString(m) }
 func (*Policy) ProtoMessage()    {}
 func (*Policy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{1}
+	return fileDescriptor_48f54a345c1316d1, []int{3}
 }
 
 func (m *Policy) XXX_Unmarshal(b []byte) error {
@@ -111,6 +264,20 @@ func (m *Policy) GetBuffer() *Policy_Buffer {
 	return nil
 }
 
+func (m *Policy) GetLimit() *Limit {
+	if m != nil {
+		return m.Limit
+	}
+	return nil
+}
+
+func (m *Policy) GetQuota() *Quota {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
 // Timeout is a message for timeout settings in various stages, in seconds.
 type Policy_Timeout struct {
 	Handshake            *Second  `protobuf:"bytes,1,opt,name=handshake,proto3" json:"handshake,omitempty"`
@@ -126,7 +293,7 @@ func (m *Policy_Timeout) Reset()         { *m = Policy_Timeout{} }
 func (m *Policy_Timeout) String() string { return proto.CompactTextString(m) }
 func (*Policy_Timeout) ProtoMessage()    {}
 func (*Policy_Timeout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{1, 0}
+	return fileDescriptor_48f54a345c1316d1, []int{3, 0}
 }
 
 func (m *Policy_Timeout) XXX_Unmarshal(b []byte) error {
@@ -176,8 +343,13 @@ func (m *Policy_Timeout) GetDownlinkOnly() *Second {
 }
 
 type Policy_Stats struct {
-	UserUplink           bool     `protobuf:"varint,1,opt,name=user_uplink,json=userUplink,proto3" json:"user_uplink,omitempty"`
-	UserDownlink         bool     `protobuf:"varint,2,opt,name=user_downlink,json=userDownlink,proto3" json:"user_downlink,omitempty"`
+	UserUplink   bool `protobuf:"varint,1,opt,name=user_uplink,json=userUplink,proto3" json:"user_uplink,omitempty"`
+	UserDownlink bool `protobuf:"varint,2,opt,name=user_downlink,json=userDownlink,proto3" json:"user_downlink,omitempty"`
+	// Gauge of active connections of each user.
+	UserConnection bool `protobuf:"varint,3,opt,name=user_connection,json=userConnection,proto3" json:"user_connection,omitempty"`
+	// Counters of time throttled by rate limits of each user, in
+	// milliseconds.
+	UserThrottle         bool     `protobuf:"varint,4,opt,name=user_throttle,json=userThrottle,proto3" json:"user_throttle,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -187,7 +359,7 @@ func (m *Policy_Stats) Reset()         { *m = Policy_Stats{} }
 func (m *Policy_Stats) String() string { return proto.CompactTextString(m) }
 func (*Policy_Stats) ProtoMessage()    {}
 func (*Policy_Stats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{1, 1}
+	return fileDescriptor_48f54a345c1316d1, []int{3, 1}
 }
 
 func (m *Policy_Stats) XXX_Unmarshal(b []byte) error {
@@ -222,6 +394,20 @@ func (m *Policy_Stats) GetUserDownlink() bool {
 	return false
 }
 
+func (m *Policy_Stats) GetUserConnection() bool {
+	if m != nil {
+		return m.UserConnection
+	}
+	return false
+}
+
+func (m *Policy_Stats) GetUserThrottle() bool {
+	if m != nil {
+		return m.UserThrottle
+	}
+	return false
+}
+
 type Policy_Buffer struct {
 	// Buffer size per connection, in bytes. -1 for unlimited buffer.
 	Connection           int32    `protobuf:"varint,1,opt,name=connection,proto3" json:"connection,omitempty"`
@@ -234,7 +420,7 @@ func (m *Policy_Buffer) Reset()         { *m = Policy_Buffer{} }
 func (m *Policy_Buffer) String() string { return proto.CompactTextString(m) }
 func (*Policy_Buffer) ProtoMessage()    {}
 func (*Policy_Buffer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{1, 2}
+	return fileDescriptor_48f54a345c1316d1, []int{3, 2}
 }
 
 func (m *Policy_Buffer) XXX_Unmarshal(b []byte) error {
@@ -263,17 +449,19 @@ func (m *Policy_Buffer) GetConnection() int32 {
 }
 
 type SystemPolicy struct {
-	Stats                *SystemPolicy_Stats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Stats *SystemPolicy_Stats `protobuf:"bytes,1,opt,name=stats,proto3" json:"stats,omitempty"`
+	// Limits of each inbound.
+	InboundLimit         *Limit   `protobuf:"bytes,2,opt,name=inbound_limit,json=inboundLimit,proto3" json:"inbound_limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *SystemPolicy) Reset()         { *m = SystemPolicy{} }
 func (m *SystemPolicy) String() string { return proto.CompactTextString(m) }
 func (*SystemPolicy) ProtoMessage()    {}
 func (*SystemPolicy) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{2}
+	return fileDescriptor_48f54a345c1316d1, []int{4}
 }
 
 func (m *SystemPolicy) XXX_Unmarshal(b []byte) error {
@@ -301,9 +489,27 @@ func (m *SystemPolicy) GetStats() *SystemPolicy_Stats {
 	return nil
 }
 
+func (m *SystemPolicy) GetInboundLimit() *Limit {
+	if m != nil {
+		return m.InboundLimit
+	}
+	return nil
+}
+
 type SystemPolicy_Stats struct {
-	InboundUplink        bool     `protobuf:"varint,1,opt,name=inbound_uplink,json=inboundUplink,proto3" json:"inbound_uplink,omitempty"`
-	InboundDownlink      bool     `protobuf:"varint,2,opt,name=inbound_downlink,json=inboundDownlink,proto3" json:"inbound_downlink,omitempty"`
+	InboundUplink   bool `protobuf:"varint,1,opt,name=inbound_uplink,json=inboundUplink,proto3" json:"inbound_uplink,omitempty"`
+	InboundDownlink bool `protobuf:"varint,2,opt,name=inbound_downlink,json=inboundDownlink,proto3" json:"inbound_downlink,omitempty"`
+	// Gauge of active connections of each inbound.
+	InboundConnection bool `protobuf:"varint,3,opt,name=inbound_connection,json=inboundConnection,proto3" json:"inbound_connection,omitempty"`
+	// Gauge of active connections, histograms of connection durations and
+	// handshake latencies, and counters of dial failures by reason, of each
+	// outbound.
+	OutboundConnection bool `protobuf:"varint,4,opt,name=outbound_connection,json=outboundConnection,proto3" json:"outbound_connection,omitempty"`
+	OutboundUplink     bool `protobuf:"varint,5,opt,name=outbound_uplink,json=outboundUplink,proto3" json:"outbound_uplink,omitempty"`
+	OutboundDownlink   bool `protobuf:"varint,6,opt,name=outbound_downlink,json=outboundDownlink,proto3" json:"outbound_downlink,omitempty"`
+	// Counters of time throttled by rate limits of each inbound, in
+	// milliseconds.
+	InboundThrottle      bool     `protobuf:"varint,7,opt,name=inbound_throttle,json=inboundThrottle,proto3" json:"inbound_throttle,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -313,7 +519,7 @@ func (m *SystemPolicy_Stats) Reset()         { *m = SystemPolicy_Stats{} }
 func (m *SystemPolicy_Stats) String() string { return proto.CompactTextString(m) }
 func (*SystemPolicy_Stats) ProtoMessage()    {}
 func (*SystemPolicy_Stats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{2, 0}
+	return fileDescriptor_48f54a345c1316d1, []int{4, 0}
 }
 
 func (m *SystemPolicy_Stats) XXX_Unmarshal(b []byte) error {
@@ -348,19 +554,59 @@ func (m *SystemPolicy_Stats) GetInboundDownlink() bool {
 	return false
 }
 
+func (m *SystemPolicy_Stats) GetInboundConnection() bool {
+	if m != nil {
+		return m.InboundConnection
+	}
+	return false
+}
+
+func (m *SystemPolicy_Stats) GetOutboundConnection() bool {
+	if m != nil {
+		return m.OutboundConnection
+	}
+	return false
+}
+
+func (m *SystemPolicy_Stats) GetOutboundUplink() bool {
+	if m != nil {
+		return m.OutboundUplink
+	}
+	return false
+}
+
+func (m *SystemPolicy_Stats) GetOutboundDownlink() bool {
+	if m != nil {
+		return m.OutboundDownlink
+	}
+	return false
+}
+
+func (m *SystemPolicy_Stats) GetInboundThrottle() bool {
+	if m != nil {
+		return m.InboundThrottle
+	}
+	return false
+}
+
 type Config struct {
-	Level                map[uint32]*Policy `protobuf:"bytes,1,rep,name=level,proto3" json:"level,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	System               *SystemPolicy      `protobuf:"bytes,2,opt,name=system,proto3" json:"system,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Level  map[uint32]*Policy `protobuf:"bytes,1,rep,name=level,proto3" json:"level,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	System *SystemPolicy      `protobuf:"bytes,2,opt,name=system,proto3" json:"system,omitempty"`
+	// Quotas of users by email, which override quotas of their levels.
+	UserQuota map[string]*Quota `protobuf:"bytes,3,rep,name=user_quota,json=userQuota,proto3" json:"user_quota,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Path of the file that usages of quotas are saved in. Usages are kept in
+	// memory only if empty.
+	QuotaPath            string   `protobuf:"bytes,4,opt,name=quota_path,json=quotaPath,proto3" json:"quota_path,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_48f54a345c1316d1, []int{3}
+	return fileDescriptor_48f54a345c1316d1, []int{5}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -395,8 +641,131 @@ func (m *Config) GetSystem() *SystemPolicy {
 	return nil
 }
 
+func (m *Config) GetUserQuota() map[string]*Quota {
+	if m != nil {
+		return m.UserQuota
+	}
+	return nil
+}
+
+func (m *Config) GetQuotaPath() string {
+	if m != nil {
+		return m.QuotaPath
+	}
+	return ""
+}
+
+// QuotaSnapshot is the content of the file of quota usages.
+type QuotaSnapshot struct {
+	Usage                []*QuotaSnapshot_Usage `protobuf:"bytes,1,rep,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *QuotaSnapshot) Reset()         { *m = QuotaSnapshot{} }
+func (m *QuotaSnapshot) String() string { return proto.CompactTextString(m) }
+func (*QuotaSnapshot) ProtoMessage()    {}
+func (*QuotaSnapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{6}
+}
+
+func (m *QuotaSnapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaSnapshot.Unmarshal(m, b)
+}
+func (m *QuotaSnapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaSnapshot.Marshal(b, m, deterministic)
+}
+func (m *QuotaSnapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaSnapshot.Merge(m, src)
+}
+func (m *QuotaSnapshot) XXX_Size() int {
+	return xxx_messageInfo_QuotaSnapshot.Size(m)
+}
+func (m *QuotaSnapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaSnapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaSnapshot proto.InternalMessageInfo
+
+func (m *QuotaSnapshot) GetUsage() []*QuotaSnapshot_Usage {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
+type QuotaSnapshot_Usage struct {
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Traffic in bytes used in the period.
+	Used int64 `protobuf:"varint,2,opt,name=used,proto3" json:"used,omitempty"`
+	// Start of the period, in Unix seconds.
+	PeriodStart int64 `protobuf:"varint,3,opt,name=period_start,json=periodStart,proto3" json:"period_start,omitempty"`
+	// Level of the user, which the quota may come from.
+	Level                uint32   `protobuf:"varint,4,opt,name=level,proto3" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QuotaSnapshot_Usage) Reset()         { *m = QuotaSnapshot_Usage{} }
+func (m *QuotaSnapshot_Usage) String() string { return proto.CompactTextString(m) }
+func (*QuotaSnapshot_Usage) ProtoMessage()    {}
+func (*QuotaSnapshot_Usage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_48f54a345c1316d1, []int{6, 0}
+}
+
+func (m *QuotaSnapshot_Usage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QuotaSnapshot_Usage.Unmarshal(m, b)
+}
+func (m *QuotaSnapshot_Usage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QuotaSnapshot_Usage.Marshal(b, m, deterministic)
+}
+func (m *QuotaSnapshot_Usage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QuotaSnapshot_Usage.Merge(m, src)
+}
+func (m *QuotaSnapshot_Usage) XXX_Size() int {
+	return xxx_messageInfo_QuotaSnapshot_Usage.Size(m)
+}
+func (m *QuotaSnapshot_Usage) XXX_DiscardUnknown() {
+	xxx_messageInfo_QuotaSnapshot_Usage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QuotaSnapshot_Usage proto.InternalMessageInfo
+
+func (m *QuotaSnapshot_Usage) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *QuotaSnapshot_Usage) GetUsed() int64 {
+	if m != nil {
+		return m.Used
+	}
+	return 0
+}
+
+func (m *QuotaSnapshot_Usage) GetPeriodStart() int64 {
+	if m != nil {
+		return m.PeriodStart
+	}
+	return 0
+}
+
+func (m *QuotaSnapshot_Usage) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
 func init() {
+	proto.RegisterEnum("v2ray.core.app.policy.Quota_Period", Quota_Period_name, Quota_Period_value)
 	proto.RegisterType((*Second)(nil), "v2ray.core.app.policy.Second")
+	proto.RegisterType((*Limit)(nil), "v2ray.core.app.policy.Limit")
+	proto.RegisterType((*Quota)(nil), "v2ray.core.app.policy.Quota")
 	proto.RegisterType((*Policy)(nil), "v2ray.core.app.policy.Policy")
 	proto.RegisterType((*Policy_Timeout)(nil), "v2ray.core.app.policy.Policy.Timeout")
 	proto.RegisterType((*Policy_Stats)(nil), "v2ray.core.app.policy.Policy.Stats")
@@ -405,6 +774,9 @@ func init() {
 	proto.RegisterType((*SystemPolicy_Stats)(nil), "v2ray.core.app.policy.SystemPolicy.Stats")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.policy.Config")
 	proto.RegisterMapType((map[uint32]*Policy)(nil), "v2ray.core.app.policy.Config.LevelEntry")
+	proto.RegisterMapType((map[string]*Quota)(nil), "v2ray.core.app.policy.Config.UserQuotaEntry")
+	proto.RegisterType((*QuotaSnapshot)(nil), "v2ray.core.app.policy.QuotaSnapshot")
+	proto.RegisterType((*QuotaSnapshot_Usage)(nil), "v2ray.core.app.policy.QuotaSnapshot.Usage")
 }
 
 func init() {
@@ -412,38 +784,64 @@ func init() {
 }
 
 var fileDescriptor_48f54a345c1316d1 = []byte{
-	// 513 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdb, 0x6a, 0x13, 0x41,
-	0x1c, 0xc6, 0xd9, 0xa4, 0xbb, 0xad, 0xff, 0x24, 0x6d, 0x19, 0x2c, 0xc4, 0x05, 0x6b, 0x49, 0xad,
-	0xa4, 0x37, 0x13, 0x48, 0x6f, 0xd4, 0x6a, 0xc5, 0x78, 0x00, 0x41, 0xb1, 0x4c, 0x3c, 0xa0, 0x37,
-	0x61, 0xb3, 0x3b, 0xb1, 0x4b, 0x26, 0x33, 0xc3, 0x1e, 0x22, 0xfb, 0x1a, 0x3e, 0x46, 0x9f, 0xc6,
-	0x27, 0xf0, 0x59, 0x64, 0xe7, 0xe0, 0xa6, 0x92, 0x43, 0xef, 0x26, 0x1f, 0xbf, 0xef, 0x63, 0xbe,
-	0x7f, 0xfe, 0xb3, 0xf0, 0x68, 0xde, 0x4f, 0x82, 0x02, 0x87, 0x62, 0xd6, 0x0b, 0x45, 0x42, 0x7b,
-	0x81, 0x94, 0x3d, 0x29, 0x58, 0x1c, 0x16, 0xbd, 0x50, 0xf0, 0x49, 0xfc, 0x03, 0xcb, 0x44, 0x64,
-	0x02, 0x1d, 0x58, 0x2e, 0xa1, 0x38, 0x90, 0x12, 0x6b, 0xa6, 0x73, 0x08, 0xde, 0x90, 0x86, 0x82,
-	0x47, 0xe8, 0x2e, 0xb8, 0xf3, 0x80, 0xe5, 0xb4, 0xed, 0x1c, 0x39, 0xdd, 0x16, 0xd1, 0x3f, 0x3a,
-	0xbf, 0xb7, 0xc0, 0xbb, 0x54, 0x28, 0x7a, 0x01, 0xdb, 0x59, 0x3c, 0xa3, 0x22, 0xcf, 0x14, 0xd2,
-	0xe8, 0x9f, 0xe0, 0xa5, 0x99, 0x58, 0xf3, 0xf8, 0x93, 0x86, 0x89, 0x75, 0xa1, 0x27, 0xe0, 0xa6,
-	0x59, 0x90, 0xa5, 0xed, 0x9a, 0xb2, 0x1f, 0xaf, 0xb7, 0x0f, 0x4b, 0x94, 0x68, 0x07, 0x7a, 0x06,
-	0xde, 0x38, 0x9f, 0x4c, 0x68, 0xd2, 0xae, 0x2b, 0xef, 0xc3, 0xf5, 0xde, 0x81, 0x62, 0x89, 0xf1,
-	0xf8, 0xbf, 0x6a, 0xb0, 0x6d, 0x6e, 0x83, 0xce, 0xe1, 0xce, 0x55, 0xc0, 0xa3, 0xf4, 0x2a, 0x98,
-	0x52, 0xd3, 0xe3, 0xfe, 0x8a, 0x30, 0x3d, 0x18, 0x52, 0xf1, 0xe8, 0x2d, 0xec, 0x85, 0x82, 0x73,
-	0x1a, 0x66, 0xb1, 0xe0, 0xa3, 0x38, 0x62, 0xd4, 0x74, 0xd9, 0x10, 0xb1, 0x5b, 0xb9, 0xde, 0x45,
-	0x8c, 0xa2, 0x0b, 0x68, 0xe4, 0x92, 0xc5, 0x7c, 0x3a, 0x12, 0x9c, 0x15, 0xa6, 0xd3, 0x86, 0x0c,
-	0xd0, 0x8e, 0x8f, 0x9c, 0x15, 0x68, 0x00, 0xad, 0x48, 0xfc, 0xe4, 0x55, 0xc2, 0xd6, 0x6d, 0x12,
-	0x9a, 0xd6, 0x53, 0x66, 0xf8, 0x1f, 0xc0, 0x55, 0x23, 0x46, 0x0f, 0xa0, 0x91, 0xa7, 0x34, 0x19,
-	0xe9, 0x7c, 0x35, 0x93, 0x1d, 0x02, 0xa5, 0xf4, 0x59, 0x29, 0xe8, 0x18, 0x5a, 0x0a, 0xb0, 0x76,
-	0xd5, 0x79, 0x87, 0x34, 0x4b, 0xf1, 0xb5, 0xd1, 0xfc, 0x2e, 0x78, 0x7a, 0xea, 0xe8, 0x10, 0xa0,
-	0xaa, 0xab, 0xe2, 0x5c, 0xb2, 0xa0, 0x74, 0xae, 0x1d, 0x68, 0x0e, 0x8b, 0x34, 0xa3, 0xb3, 0x7f,
-	0x8b, 0x65, 0xf6, 0x42, 0xff, 0x1d, 0xa7, 0xab, 0x5a, 0x2c, 0x78, 0x6e, 0x6c, 0x87, 0xff, 0xcd,
-	0x56, 0x39, 0x81, 0xdd, 0x98, 0x8f, 0x45, 0xce, 0xa3, 0x9b, 0x6d, 0x5a, 0x46, 0x35, 0x85, 0x4e,
-	0x61, 0xdf, 0x62, 0xff, 0x75, 0xda, 0x33, 0xba, 0xad, 0xd5, 0xf9, 0xe3, 0x80, 0xf7, 0x4a, 0xbd,
-	0x23, 0x74, 0x01, 0x2e, 0xa3, 0x73, 0xca, 0xda, 0xce, 0x51, 0xbd, 0xdb, 0xe8, 0x77, 0x57, 0x5c,
-	0x53, 0xd3, 0xf8, 0x7d, 0x89, 0xbe, 0xe1, 0x59, 0x52, 0x10, 0x6d, 0x43, 0xe7, 0xe0, 0xa5, 0xaa,
-	0xc2, 0x86, 0xfd, 0x5f, 0xec, 0x49, 0x8c, 0xc5, 0xff, 0x0a, 0x50, 0x25, 0xa2, 0x7d, 0xa8, 0x4f,
-	0x69, 0x61, 0x5e, 0x6a, 0x79, 0x44, 0x67, 0xf6, 0xf5, 0xae, 0xdf, 0x47, 0x93, 0xaa, 0xd9, 0xa7,
-	0xb5, 0xc7, 0xce, 0xe0, 0x39, 0xdc, 0x0b, 0xc5, 0x6c, 0x39, 0x7e, 0xe9, 0x7c, 0xf7, 0xf4, 0xe9,
-	0xba, 0x76, 0xf0, 0xa5, 0x4f, 0x82, 0xb2, 0x5d, 0x42, 0xf1, 0x4b, 0x29, 0x4d, 0xd2, 0xd8, 0x53,
-	0x5f, 0x97, 0xb3, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe9, 0xde, 0xee, 0x20, 0x87, 0x04, 0x00,
+	// 929 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x56, 0xdd, 0x72, 0xdb, 0x44,
+	0x14, 0xc6, 0x96, 0xa5, 0xc4, 0xc7, 0x8e, 0xe3, 0x2e, 0x74, 0xc6, 0x78, 0x68, 0x29, 0x0e, 0x6d,
+	0x53, 0x7e, 0xe4, 0x19, 0xf5, 0x06, 0x28, 0x14, 0x9a, 0x16, 0x66, 0x18, 0xca, 0x10, 0xd6, 0x09,
+	0x9d, 0xe9, 0x8d, 0x67, 0x23, 0x6d, 0x62, 0x11, 0x59, 0x2b, 0xa4, 0x55, 0x40, 0x4f, 0xc0, 0x3d,
+	0xb7, 0x5c, 0xc3, 0x05, 0x8f, 0xc0, 0x0c, 0xcf, 0xc0, 0x2b, 0x75, 0xf6, 0xec, 0xae, 0x6c, 0xa7,
+	0xb5, 0x9b, 0x3b, 0xef, 0xa7, 0xef, 0xfb, 0xf6, 0xec, 0xf9, 0xd9, 0x35, 0xdc, 0xb9, 0x08, 0x72,
+	0x56, 0xf9, 0xa1, 0x98, 0x8f, 0x43, 0x91, 0xf3, 0x31, 0xcb, 0xb2, 0x71, 0x26, 0x92, 0x38, 0xac,
+	0xc6, 0xa1, 0x48, 0x4f, 0xe3, 0x33, 0x3f, 0xcb, 0x85, 0x14, 0xe4, 0xba, 0xe5, 0xe5, 0xdc, 0x67,
+	0x59, 0xe6, 0x6b, 0xce, 0xe8, 0x26, 0x78, 0x13, 0x1e, 0x8a, 0x34, 0x22, 0x6f, 0x81, 0x7b, 0xc1,
+	0x92, 0x92, 0x0f, 0x1a, 0xb7, 0x1a, 0xfb, 0x3b, 0x54, 0x2f, 0x46, 0x73, 0x70, 0x9f, 0xc6, 0xf3,
+	0x58, 0x92, 0x77, 0xa1, 0x53, 0x66, 0x49, 0x9c, 0x9e, 0x4f, 0x73, 0x26, 0x35, 0xa9, 0x45, 0x41,
+	0x43, 0x94, 0x49, 0x4e, 0xf6, 0x60, 0x27, 0x12, 0xbf, 0xa6, 0x0b, 0x4a, 0x13, 0x29, 0x5d, 0x0b,
+	0x22, 0xe9, 0x26, 0x40, 0x28, 0xd2, 0x94, 0x87, 0x32, 0x16, 0xe9, 0xc0, 0xc1, 0x9d, 0x96, 0x90,
+	0xd1, 0x5f, 0x0d, 0x70, 0x7f, 0x2c, 0x85, 0x64, 0x2a, 0x9c, 0x93, 0x4a, 0xf2, 0xc2, 0xec, 0xa4,
+	0x17, 0xe4, 0x01, 0x78, 0x19, 0xcf, 0x63, 0x11, 0xa1, 0x7b, 0x2f, 0xd8, 0xf3, 0x5f, 0x79, 0x2c,
+	0x1f, 0x3d, 0xfc, 0x43, 0xa4, 0x52, 0x23, 0x21, 0xef, 0x41, 0x97, 0xff, 0x16, 0x72, 0x1e, 0xf1,
+	0x68, 0x2a, 0xd9, 0x19, 0x6e, 0xdf, 0xa6, 0x1d, 0x8b, 0x1d, 0xb1, 0xb3, 0xd1, 0x1d, 0xf0, 0xb4,
+	0x88, 0x6c, 0x81, 0xf3, 0x84, 0x55, 0xfd, 0x37, 0xc8, 0x36, 0xb4, 0x9e, 0x71, 0x7e, 0xde, 0x6f,
+	0x90, 0x36, 0xb8, 0xdf, 0x8b, 0x54, 0xce, 0xfa, 0xcd, 0xd1, 0xef, 0x1e, 0x78, 0x87, 0xb8, 0x15,
+	0xf9, 0x12, 0xb6, 0x64, 0x3c, 0xe7, 0xa2, 0x94, 0x18, 0x6a, 0x27, 0xb8, 0xbd, 0x26, 0x26, 0xcd,
+	0xf7, 0x8f, 0x34, 0x99, 0x5a, 0x15, 0xf9, 0x14, 0xdc, 0x42, 0x32, 0x59, 0xe0, 0x91, 0x3a, 0xc1,
+	0xde, 0x66, 0xf9, 0x44, 0x51, 0xa9, 0x56, 0x90, 0xcf, 0xc1, 0x3b, 0x29, 0x4f, 0x4f, 0x79, 0x8e,
+	0x67, 0xe9, 0x04, 0xef, 0x6f, 0xd6, 0x1e, 0x20, 0x97, 0x1a, 0x0d, 0x09, 0xc0, 0x4d, 0x54, 0x6d,
+	0x07, 0x2d, 0x14, 0xbf, 0xb3, 0x46, 0x8c, 0xf5, 0xa7, 0x9a, 0xaa, 0x34, 0xbf, 0xa8, 0xdc, 0x0e,
+	0xdc, 0x8d, 0x1a, 0xcc, 0x3f, 0xd5, 0xd4, 0xe1, 0x1f, 0x4d, 0xd8, 0x32, 0xa7, 0x26, 0x0f, 0xa0,
+	0x3d, 0x63, 0x69, 0x54, 0xcc, 0xd8, 0x39, 0x37, 0xf9, 0xba, 0xb1, 0xc6, 0x43, 0xf7, 0x25, 0x5d,
+	0xf0, 0xc9, 0x37, 0xb0, 0xbb, 0xe8, 0x95, 0x69, 0x1c, 0x25, 0x7c, 0xd0, 0xbc, 0x8a, 0x45, 0x6f,
+	0xa1, 0xfa, 0x36, 0x4a, 0x38, 0x79, 0x58, 0xf7, 0xb2, 0x48, 0x93, 0x6a, 0xe0, 0x5c, 0xc5, 0xc3,
+	0xb4, 0xfa, 0x0f, 0x69, 0x52, 0x91, 0x83, 0xa5, 0x56, 0x47, 0x87, 0xd6, 0x55, 0x1c, 0xea, 0x49,
+	0x50, 0x1e, 0xc3, 0x3f, 0x1b, 0xe0, 0x62, 0x2d, 0x71, 0xb2, 0x0a, 0x9e, 0x4f, 0xf5, 0x06, 0x98,
+	0x94, 0x6d, 0x0a, 0x0a, 0x3a, 0x46, 0x44, 0x4d, 0x16, 0x12, 0xac, 0x1e, 0x0f, 0xbd, 0x4d, 0xbb,
+	0x0a, 0x7c, 0x62, 0x30, 0x72, 0x17, 0x76, 0x91, 0x74, 0x69, 0xbc, 0xb6, 0x69, 0x4f, 0xc1, 0x8f,
+	0x6b, 0xb4, 0x76, 0x93, 0xb3, 0x5c, 0x48, 0x99, 0xf0, 0x41, 0x6b, 0xe1, 0x76, 0x64, 0xb0, 0xe1,
+	0x3e, 0x78, 0xba, 0x59, 0x2e, 0x4d, 0xac, 0x0a, 0xce, 0x5d, 0x99, 0xd8, 0xff, 0x1d, 0xe8, 0x4e,
+	0xaa, 0x42, 0xf2, 0x79, 0x3d, 0x0f, 0xa6, 0x9d, 0x75, 0x75, 0xef, 0xad, 0x4b, 0xca, 0x92, 0x66,
+	0xb5, 0xa9, 0x1f, 0xc1, 0x4e, 0x9c, 0x9e, 0x88, 0x32, 0x8d, 0xa6, 0xba, 0x3d, 0x9b, 0x57, 0x68,
+	0xcf, 0xae, 0x91, 0xe0, 0x6a, 0xf8, 0x6f, 0xd3, 0x26, 0xf7, 0x36, 0xf4, 0xac, 0xd9, 0x4a, 0x7e,
+	0xed, 0x16, 0x26, 0xc5, 0xf7, 0xa0, 0x6f, 0x69, 0x97, 0xb2, 0xbc, 0x6b, 0xf0, 0x3a, 0xd1, 0x1f,
+	0x03, 0xb1, 0xd4, 0x97, 0x72, 0x7d, 0xcd, 0x7c, 0x59, 0x4a, 0xf7, 0x18, 0xde, 0x14, 0xa5, 0x7c,
+	0x89, 0xaf, 0x93, 0x4e, 0xec, 0xa7, 0x25, 0xc1, 0x5d, 0xd8, 0xad, 0x05, 0x26, 0x64, 0x57, 0x17,
+	0xd2, 0xc2, 0x26, 0xe6, 0x0f, 0xe1, 0x5a, 0x4d, 0xac, 0x83, 0xf6, 0x90, 0xda, 0xb7, 0x1f, 0xea,
+	0xa8, 0x97, 0x0e, 0x58, 0x17, 0x7e, 0x6b, 0xe5, 0x80, 0xb6, 0xf6, 0xa3, 0xbf, 0x1d, 0xf0, 0x1e,
+	0xe3, 0xd3, 0x41, 0x1e, 0x82, 0x9b, 0xf0, 0x0b, 0x9e, 0x0c, 0x1a, 0xb7, 0x9c, 0xfd, 0x4e, 0xb0,
+	0xbf, 0xa6, 0x04, 0x9a, 0xed, 0x3f, 0x55, 0xd4, 0xaf, 0x53, 0x99, 0x57, 0x54, 0xcb, 0xd4, 0x75,
+	0x5d, 0x60, 0x9d, 0x5f, 0x73, 0xb7, 0x2d, 0x37, 0x03, 0x35, 0x12, 0xf2, 0x1d, 0xe0, 0x10, 0x4c,
+	0xf5, 0x7d, 0xe3, 0x60, 0x04, 0x1f, 0x6d, 0x8e, 0xe0, 0xb8, 0xe0, 0x39, 0x5e, 0x3d, 0x3a, 0x8a,
+	0x76, 0x69, 0xd7, 0xe4, 0x06, 0x00, 0xfa, 0x4c, 0x33, 0x26, 0x67, 0x98, 0xfd, 0x36, 0x6d, 0x23,
+	0x72, 0xc8, 0xe4, 0x6c, 0xf8, 0x0c, 0x60, 0x11, 0x3d, 0xe9, 0x83, 0x73, 0xce, 0x2b, 0xf3, 0x10,
+	0xaa, 0x9f, 0xe4, 0xbe, 0x7d, 0x1c, 0x37, 0xdf, 0x37, 0xe6, 0x04, 0x9a, 0xfb, 0x59, 0xf3, 0x93,
+	0xc6, 0xf0, 0x39, 0xf4, 0x56, 0x83, 0x5a, 0x36, 0x6f, 0x6b, 0xf3, 0x60, 0xd5, 0xfc, 0x35, 0x77,
+	0x6a, 0xed, 0x3d, 0xfa, 0xaf, 0x01, 0x3b, 0x08, 0x4e, 0x52, 0x96, 0x15, 0x33, 0x21, 0xc9, 0x57,
+	0xe0, 0x96, 0x05, 0x3b, 0xe3, 0xa6, 0x5e, 0x1f, 0x6c, 0x72, 0xb2, 0x22, 0xff, 0x58, 0x29, 0xa8,
+	0x16, 0x0e, 0x7f, 0x06, 0x17, 0xd7, 0xea, 0xfd, 0xe5, 0x73, 0x16, 0x27, 0x26, 0x50, 0xbd, 0x20,
+	0x04, 0x5a, 0x65, 0xc1, 0xf5, 0xeb, 0xeb, 0x50, 0xfc, 0xad, 0x9e, 0x55, 0xfd, 0xc0, 0x4e, 0x0b,
+	0xc9, 0x72, 0x89, 0xa3, 0xe0, 0xd0, 0x8e, 0xc6, 0x26, 0x0a, 0x52, 0x66, 0xba, 0x8f, 0x5a, 0xfa,
+	0xbf, 0x05, 0x2e, 0x0e, 0xbe, 0x80, 0xb7, 0x43, 0x31, 0x7f, 0x75, 0x8c, 0x87, 0x8d, 0xe7, 0x9e,
+	0xfe, 0xf5, 0x4f, 0xf3, 0xfa, 0x4f, 0x01, 0x65, 0xaa, 0xc6, 0x39, 0xf7, 0x1f, 0x65, 0x99, 0xc9,
+	0xf2, 0x89, 0x87, 0x7f, 0x6c, 0xee, 0xbf, 0x18, 0x00, 0x83, 0xd3, 0xa8, 0x7e, 0x02, 0x09, 0x00,
 	0x00,
 }
@@ -0,0 +1,269 @@
+// +build !confonly
+
+package policy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/ratelimit"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/stats"
+)
+
+func isLimited(limit policy.Limit) bool {
+	return limit.UplinkRate > 0 || limit.DownlinkRate > 0 || limit.Connections > 0
+}
+
+// limitEntry is the state of limits of a user or an inbound, shared by all its sessions. It is guarded by the lock
+// of limitEntries.
+type limitEntry struct {
+	// refs is the number of sessions holding the entry. The entry is removed when it drops to zero.
+	refs             int
+	connections      uint32
+	limit            policy.Limit
+	uplink           *ratelimit.Bucket
+	downlink         *ratelimit.Bucket
+	uplinkThrottle   stats.Counter
+	downlinkThrottle stats.Counter
+}
+
+// newLimitEntry creates a limitEntry. Time throttled is counted with the name prefix, if the stats manager is not nil.
+func newLimitEntry(manager stats.Manager, prefix string) *limitEntry {
+	e := new(limitEntry)
+	if manager != nil {
+		e.uplinkThrottle, _ = stats.GetOrRegisterCounter(manager, prefix+"throttle>>>uplink")
+		e.downlinkThrottle, _ = stats.GetOrRegisterCounter(manager, prefix+"throttle>>>downlink")
+	}
+	return e
+}
+
+// setLimit updates the limit of the entry. Rates of existing sessions are changed as well.
+func (e *limitEntry) setLimit(limit policy.Limit) {
+	e.limit = limit
+	e.uplink = updateBucket(e.uplink, limit.UplinkRate)
+	e.downlink = updateBucket(e.downlink, limit.DownlinkRate)
+}
+
+func updateBucket(b *ratelimit.Bucket, rate int64) *ratelimit.Bucket {
+	switch {
+	case b != nil:
+		b.SetRate(rate)
+	case rate > 0:
+		b = ratelimit.NewBucket(rate)
+	}
+	return b
+}
+
+func (e *limitEntry) acquire() bool {
+	if e.limit.Connections > 0 && e.connections >= e.limit.Connections {
+		return false
+	}
+	e.connections++
+	return true
+}
+
+// limitKey is the key of limitEntry of users. Users without email are keyed by their source IP and inbound tag, so
+// that anonymous clients don't share limits with each other.
+type limitKey struct {
+	email   string
+	source  string
+	inbound string
+}
+
+// userLimitKey returns the key of limitEntry of the user in the inbound session, or false if the user can't be told
+// from others, in which case only limits of the inbound apply.
+func userLimitKey(inbound *session.Inbound) (limitKey, bool) {
+	if inbound == nil {
+		return limitKey{}, false
+	}
+	if inbound.User != nil && len(inbound.User.Email) > 0 {
+		return limitKey{email: inbound.User.Email}, true
+	}
+	if !inbound.Source.IsValid() {
+		return limitKey{}, false
+	}
+	return limitKey{
+		source:  inbound.Source.Address.String(),
+		inbound: inbound.Tag,
+	}, true
+}
+
+func (k limitKey) String() string {
+	if len(k.email) > 0 {
+		return "user " + k.email
+	}
+	return "client " + k.source + " of inbound " + k.inbound
+}
+
+// limitEntries are limitEntry of users and inbounds, keyed by user and tag.
+type limitEntries struct {
+	sync.Mutex
+	users    map[limitKey]*limitEntry
+	inbounds map[string]*limitEntry
+}
+
+// limitRef is a reference to a limitEntry, with the map holding it.
+type limitRef struct {
+	entry *limitEntry
+	// remove deletes the entry from its map.
+	remove func()
+}
+
+// userEntry returns the entry of the user, with its limit updated. It must be called with the lock of m.limits held.
+func (m *Instance) userEntry(key limitKey, p policy.Session) limitRef {
+	e, found := m.limits.users[key]
+	if !found {
+		var manager stats.Manager
+		if p.Stats.UserThrottle && len(key.email) > 0 {
+			manager = m.stats
+		}
+		e = newLimitEntry(manager, "user>>>"+key.email+">>>")
+		m.limits.users[key] = e
+	}
+	e.setLimit(p.Limit)
+	return limitRef{entry: e, remove: func() { delete(m.limits.users, key) }}
+}
+
+// inboundEntry returns the entry of the inbound, with its limit updated. It must be called with the lock of m.limits
+// held.
+func (m *Instance) inboundEntry(tag string, p policy.System) limitRef {
+	e, found := m.limits.inbounds[tag]
+	if !found {
+		var manager stats.Manager
+		if p.Stats.InboundThrottle {
+			manager = m.stats
+		}
+		e = newLimitEntry(manager, "inbound>>>"+tag+">>>")
+		m.limits.inbounds[tag] = e
+	}
+	e.setLimit(p.InboundLimit)
+	return limitRef{entry: e, remove: func() { delete(m.limits.inbounds, tag) }}
+}
+
+// Limit implements policy.LimitManager.
+func (m *Instance) Limit(ctx context.Context, level uint32) (policy.Limiter, error) {
+	inbound := session.InboundFromContext(ctx)
+
+	m.limits.Lock()
+	defer m.limits.Unlock()
+
+	var refs []limitRef
+	// owners are descriptions of the owners of entries, for error messages.
+	var owners []string
+	if key, ok := userLimitKey(inbound); ok {
+		if p := m.ForLevel(level); isLimited(p.Limit) {
+			refs = append(refs, m.userEntry(key, p))
+			owners = append(owners, key.String())
+		}
+	}
+	if inbound != nil && len(inbound.Tag) > 0 {
+		if p := m.ForSystem(); isLimited(p.InboundLimit) {
+			refs = append(refs, m.inboundEntry(inbound.Tag, p))
+			owners = append(owners, "inbound "+inbound.Tag)
+		}
+	}
+
+	l := &sessionLimiter{
+		limits: &m.limits,
+		refs:   refs,
+	}
+	for _, r := range refs {
+		r.entry.refs++
+	}
+	for i, r := range refs {
+		if !r.entry.acquire() {
+			for _, acquired := range refs[:i] {
+				acquired.entry.connections--
+			}
+			l.unref()
+			return nil, newError("too many connections of ", owners[i])
+		}
+	}
+	for _, r := range refs {
+		e := r.entry
+		if e.uplink != nil {
+			l.uplink.buckets = append(l.uplink.buckets, e.uplink)
+			l.uplink.throttles = append(l.uplink.throttles, e.uplinkThrottle)
+		}
+		if e.downlink != nil {
+			l.downlink.buckets = append(l.downlink.buckets, e.downlink)
+			l.downlink.throttles = append(l.downlink.throttles, e.downlinkThrottle)
+		}
+	}
+	return l, nil
+}
+
+// sessionLimiter is an implementation of policy.Limiter.
+type sessionLimiter struct {
+	limits   *limitEntries
+	refs     []limitRef
+	uplink   rateLimiter
+	downlink rateLimiter
+	once     sync.Once
+}
+
+// unref drops the references to entries, and removes those no longer referenced. It must be called with the lock of
+// limits held.
+func (l *sessionLimiter) unref() {
+	for _, r := range l.refs {
+		r.entry.refs--
+		if r.entry.refs == 0 {
+			r.remove()
+		}
+	}
+}
+
+// Uplink implements policy.Limiter.
+func (l *sessionLimiter) Uplink() buf.RateLimiter {
+	if len(l.uplink.buckets) == 0 {
+		return nil
+	}
+	return &l.uplink
+}
+
+// Downlink implements policy.Limiter.
+func (l *sessionLimiter) Downlink() buf.RateLimiter {
+	if len(l.downlink.buckets) == 0 {
+		return nil
+	}
+	return &l.downlink
+}
+
+// Close implements policy.Limiter.
+func (l *sessionLimiter) Close() error {
+	l.once.Do(func() {
+		l.limits.Lock()
+		defer l.limits.Unlock()
+
+		for _, r := range l.refs {
+			r.entry.connections--
+		}
+		l.unref()
+	})
+	return nil
+}
+
+// rateLimiter is a buf.RateLimiter that takes from all its buckets, and counts time throttled in milliseconds.
+type rateLimiter struct {
+	buckets   []*ratelimit.Bucket
+	throttles []stats.Counter
+}
+
+// Reserve implements buf.RateLimiter.
+func (r *rateLimiter) Reserve(n int64) time.Duration {
+	var wait time.Duration
+	for i, b := range r.buckets {
+		d := b.Reserve(n)
+		if d > 0 && r.throttles[i] != nil {
+			r.throttles[i].Add(int64(d / time.Millisecond))
+		}
+		if d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
@@ -1,16 +1,26 @@
+// +build !confonly
+
 package policy
 
 import (
 	"context"
 
+	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/stats"
 )
 
 // Instance is an instance of Policy manager.
 type Instance struct {
 	levels map[uint32]*Policy
 	system *SystemPolicy
+	stats  stats.Manager
+	limits limitEntries
+	// userQuotas are quotas of users by email.
+	userQuotas map[string]*Quota
+	quotas     *quotas
 }
 
 // New creates new Policy manager instance.
@@ -18,6 +28,12 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 	m := &Instance{
 		levels: make(map[uint32]*Policy),
 		system: config.System,
+		limits: limitEntries{
+			users:    make(map[limitKey]*limitEntry),
+			inbounds: make(map[string]*limitEntry),
+		},
+		userQuotas: config.UserQuota,
+		quotas:     newQuotas(config.QuotaPath),
 	}
 	if len(config.Level) > 0 {
 		for lv, p := range config.Level {
@@ -27,6 +43,23 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 		}
 	}
 
+	if len(config.QuotaPath) > 0 {
+		if err := m.quotas.load(); err != nil {
+			newError("failed to load quota usages").Base(err).AtWarning().WriteToLog()
+		}
+	}
+	for email, q := range m.userQuotas {
+		if q.Bytes > 0 {
+			m.quotas.counter(email, 0, q.ToCoreQuota())
+		}
+	}
+
+	if v := core.FromContext(ctx); v != nil {
+		common.Must(v.RequireFeatures(func(sm stats.Manager) {
+			m.stats = sm
+		}))
+	}
+
 	return m, nil
 }
 
@@ -53,11 +86,27 @@ func (m *Instance) ForSystem() policy.System {
 
 // Start implements common.Runnable.Start().
 func (m *Instance) Start() error {
+	if m.quotas.task != nil {
+		return m.quotas.task.Start()
+	}
 	return nil
 }
 
 // Close implements common.Closable.Close().
 func (m *Instance) Close() error {
+	if m.quotas.task == nil {
+		return nil
+	}
+	var errs []error
+	if err := m.quotas.task.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := m.quotas.save(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return errors.Combine(errs...)
+	}
 	return nil
 }
 
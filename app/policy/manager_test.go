@@ -2,11 +2,16 @@ package policy_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	. "v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/policy"
 )
 
@@ -43,3 +48,182 @@ func TestPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestLimit(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			1: {
+				Limit: &Limit{
+					UplinkRate: 1024,
+					Connection: 2,
+				},
+			},
+			2: {
+				Limit: &Limit{
+					Connection: 1,
+				},
+			},
+		},
+		System: &SystemPolicy{
+			Stats: &SystemPolicy_Stats{},
+			InboundLimit: &Limit{
+				Connection: 3,
+			},
+		},
+	})
+	common.Must(err)
+
+	newContext := func(tag string, email string) context.Context {
+		return session.ContextWithInbound(context.Background(), &session.Inbound{
+			Tag: tag,
+			User: &protocol.MemoryUser{
+				Level: 1,
+				Email: email,
+			},
+		})
+	}
+
+	l1, err := policy.LimitSession(newContext("in", "a"), manager, 1)
+	common.Must(err)
+	if l1.Uplink() == nil {
+		t.Error("expect uplink limited")
+	}
+	if l1.Downlink() != nil {
+		t.Error("expect downlink not limited")
+	}
+	l2, err := policy.LimitSession(newContext("in", "a"), manager, 1)
+	common.Must(err)
+	if _, err := policy.LimitSession(newContext("in", "a"), manager, 1); err == nil {
+		t.Error("expect error on exceeding connection limit of user, but nil")
+	}
+
+	l3, err := policy.LimitSession(newContext("in", "b"), manager, 1)
+	common.Must(err)
+	if _, err := policy.LimitSession(newContext("in", "c"), manager, 1); err == nil {
+		t.Error("expect error on exceeding connection limit of inbound, but nil")
+	}
+	common.Must(l3.Close())
+	common.Must(l3.Close())
+
+	l4, err := policy.LimitSession(newContext("in", "c"), manager, 1)
+	common.Must(err)
+
+	for _, l := range []policy.Limiter{l1, l2, l4} {
+		common.Must(l.Close())
+	}
+
+	l5, err := policy.LimitSession(newContext("in", ""), manager, 0)
+	common.Must(err)
+	if l5.Uplink() != nil || l5.Downlink() != nil {
+		t.Error("expect level 0 not rate limited")
+	}
+	common.Must(l5.Close())
+
+	// Users without email are limited per source IP in each inbound.
+	newAnonymousContext := func(tag string, ip net.IP) context.Context {
+		inbound := &session.Inbound{
+			Tag: tag,
+		}
+		if ip != nil {
+			inbound.Source = net.TCPDestination(net.IPAddress(ip), 12345)
+		}
+		return session.ContextWithInbound(context.Background(), inbound)
+	}
+	l6, err := policy.LimitSession(newAnonymousContext("x", net.IP{10, 0, 0, 1}), manager, 1)
+	common.Must(err)
+	l7, err := policy.LimitSession(newAnonymousContext("x", net.IP{10, 0, 0, 1}), manager, 1)
+	common.Must(err)
+	if _, err := policy.LimitSession(newAnonymousContext("x", net.IP{10, 0, 0, 1}), manager, 1); err == nil {
+		t.Error("expect error on exceeding connection limit of anonymous client, but nil")
+	}
+	l9, err := policy.LimitSession(newAnonymousContext("x", net.IP{10, 0, 0, 2}), manager, 1)
+	common.Must(err)
+	l10, err := policy.LimitSession(newAnonymousContext("y", net.IP{10, 0, 0, 1}), manager, 1)
+	common.Must(err)
+	l11, err := policy.LimitSession(newAnonymousContext("", nil), manager, 1)
+	common.Must(err)
+	if l11.Uplink() != nil {
+		t.Error("expect anonymous client of unknown source not limited")
+	}
+	for _, l := range []policy.Limiter{l6, l7, l9, l10, l11} {
+		common.Must(l.Close())
+	}
+
+	// The limit follows the current level of the user.
+	l8, err := policy.LimitSession(newContext("", "a"), manager, 2)
+	common.Must(err)
+	if l8.Uplink() != nil {
+		t.Error("expect uplink of level 2 not limited")
+	}
+	if _, err := policy.LimitSession(newContext("", "a"), manager, 2); err == nil {
+		t.Error("expect error on exceeding connection limit of level 2, but nil")
+	}
+	common.Must(l8.Close())
+}
+
+func TestQuota(t *testing.T) {
+	path := filepath.Join(os.TempDir(), "v2ray_test_quota")
+	os.Remove(path)
+	defer os.Remove(path)
+
+	config := &Config{
+		Level: map[uint32]*Policy{
+			0: {
+				Quota: &Quota{
+					Bytes:  100,
+					Period: Quota_Week,
+				},
+			},
+		},
+		UserQuota: map[string]*Quota{
+			"test@v2ray.com": {
+				Bytes:       10,
+				ExceededTag: "blocked",
+			},
+		},
+		QuotaPath: path,
+	}
+	manager, err := New(context.Background(), config)
+	common.Must(err)
+
+	if c := policy.QuotaForUser(manager, &protocol.MemoryUser{Level: 0}); c != nil {
+		t.Error("expect no quota for user without email")
+	}
+
+	c := policy.QuotaForUser(manager, &protocol.MemoryUser{Email: "level@v2ray.com", Level: 0})
+	c.Add(60)
+	if u := c.Usage(); u.Used != 60 || u.Exceeded() || u.Quota.Period != policy.QuotaWeek || u.Reset.Weekday() != time.Monday {
+		t.Error("unexpected usage: ", u)
+	}
+
+	c = policy.QuotaForUser(manager, &protocol.MemoryUser{Email: "test@v2ray.com", Level: 0})
+	c.Add(10)
+	if u := c.Usage(); !u.Exceeded() || u.Quota.ExceededTag != "blocked" {
+		t.Error("expect quota exceeded, but got ", u)
+	}
+
+	usages := manager.QuotaUsages()
+	if len(usages) != 2 || usages[0].Email != "level@v2ray.com" || usages[1].Email != "test@v2ray.com" {
+		t.Error("unexpected usages: ", usages)
+	}
+
+	common.Must(manager.Close())
+
+	manager, err = New(context.Background(), config)
+	common.Must(err)
+	if u := manager.QuotaUsages(); len(u) != 2 || u[0].Used != 60 || u[0].Quota.Bytes != 100 || u[1].Used != 10 {
+		t.Error("expect usages restored before users connect, but got ", u)
+	}
+	c = policy.QuotaForUser(manager, &protocol.MemoryUser{Email: "level@v2ray.com", Level: 0})
+	if u := c.Usage(); u.Used != 60 {
+		t.Error("expect usage of level@v2ray.com restored, but got ", u)
+	}
+
+	common.Must(manager.ResetQuota("test@v2ray.com"))
+	if u := manager.QuotaUsages(); u[1].Used != 0 {
+		t.Error("expect usage reset, but got ", u[1])
+	}
+	if err := manager.ResetQuota("unknown@v2ray.com"); err == nil {
+		t.Error("expect error for unknown user")
+	}
+}
@@ -0,0 +1,260 @@
+// +build !confonly
+
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/features/policy"
+)
+
+// quotaCounter is an implementation of policy.QuotaCounter.
+type quotaCounter struct {
+	sync.Mutex
+	email string
+	level uint32
+	quota policy.Quota
+	used  int64
+	// start is the start of the current period.
+	start time.Time
+}
+
+// refresh resets the usage if the current period is over. It must be called with the lock held.
+func (c *quotaCounter) refresh(now time.Time) {
+	if start := c.quota.Period.Start(now); !start.Equal(c.start) {
+		c.start = start
+		c.used = 0
+	}
+}
+
+// Add implements policy.QuotaCounter.
+func (c *quotaCounter) Add(n int64) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.refresh(time.Now())
+	c.used += n
+}
+
+// Usage implements policy.QuotaCounter.
+func (c *quotaCounter) Usage() policy.QuotaUsage {
+	c.Lock()
+	defer c.Unlock()
+
+	c.refresh(time.Now())
+	return policy.QuotaUsage{
+		Email: c.email,
+		Quota: c.quota,
+		Used:  c.used,
+		Reset: c.quota.Period.Next(c.start),
+	}
+}
+
+func (c *quotaCounter) reset() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.refresh(time.Now())
+	c.used = 0
+}
+
+// quotas are quota counters of users, keyed by email.
+type quotas struct {
+	sync.Mutex
+	counters map[string]*quotaCounter
+	// saved are usages in the file, of users that haven't connected since started.
+	saved map[string]*QuotaSnapshot_Usage
+	path  string
+	task  *task.Periodic
+	// saving serializes writing of the file.
+	saving sync.Mutex
+}
+
+func newQuotas(path string) *quotas {
+	q := &quotas{
+		counters: make(map[string]*quotaCounter),
+		saved:    make(map[string]*QuotaSnapshot_Usage),
+		path:     path,
+	}
+	if len(path) > 0 {
+		q.task = &task.Periodic{
+			Interval: time.Minute,
+			Execute: func() error {
+				if err := q.save(); err != nil {
+					newError("failed to save quota usages").Base(err).AtWarning().WriteToLog()
+				}
+				return nil
+			},
+		}
+	}
+	return q
+}
+
+// counter returns the counter of the user with the quota, creating it if not exist.
+func (q *quotas) counter(email string, level uint32, quota policy.Quota) *quotaCounter {
+	q.Lock()
+	c, found := q.counters[email]
+	if !found {
+		c = &quotaCounter{
+			email: email,
+		}
+		if u, found := q.saved[email]; found {
+			c.used = u.Used
+			c.start = time.Unix(u.PeriodStart, 0)
+			delete(q.saved, email)
+		}
+		q.counters[email] = c
+	}
+	q.Unlock()
+
+	c.Lock()
+	c.level = level
+	c.quota = quota
+	c.Unlock()
+	return c
+}
+
+// load reads usages from the file. It does nothing if the file doesn't exist.
+func (q *quotas) load() error {
+	data, err := ioutil.ReadFile(q.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return newError("failed to read quota usages from ", q.path).Base(err)
+	}
+
+	snapshot := new(QuotaSnapshot)
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		return newError("failed to decode quota usages from ", q.path).Base(err)
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	for _, u := range snapshot.Usage {
+		q.saved[u.Email] = u
+	}
+	return nil
+}
+
+// save writes usages into the file.
+func (q *quotas) save() error {
+	q.saving.Lock()
+	defer q.saving.Unlock()
+
+	snapshot := new(QuotaSnapshot)
+	q.Lock()
+	for email, c := range q.counters {
+		c.Lock()
+		snapshot.Usage = append(snapshot.Usage, &QuotaSnapshot_Usage{
+			Email:       email,
+			Used:        c.used,
+			PeriodStart: c.start.Unix(),
+			Level:       c.level,
+		})
+		c.Unlock()
+	}
+	for _, u := range q.saved {
+		snapshot.Usage = append(snapshot.Usage, u)
+	}
+	q.Unlock()
+
+	data, err := proto.Marshal(snapshot)
+	if err != nil {
+		return newError("failed to encode quota usages").Base(err)
+	}
+	// Write to a temporary file first, so that the file is never left half-written.
+	tmp := q.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return newError("failed to write quota usages to ", tmp).Base(err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return newError("failed to write quota usages to ", q.path).Base(err)
+	}
+	return nil
+}
+
+// quotaFor returns the quota of the user, by its email or its level.
+func (m *Instance) quotaFor(email string, level uint32) policy.Quota {
+	if q, found := m.userQuotas[email]; found {
+		return q.ToCoreQuota()
+	}
+	if p, found := m.levels[level]; found {
+		return p.Quota.ToCoreQuota()
+	}
+	return policy.Quota{}
+}
+
+// QuotaForUser implements policy.QuotaManager.
+func (m *Instance) QuotaForUser(user *protocol.MemoryUser) policy.QuotaCounter {
+	if user == nil || len(user.Email) == 0 {
+		return nil
+	}
+	quota := m.quotaFor(user.Email, user.Level)
+	if quota.Bytes <= 0 {
+		return nil
+	}
+	return m.quotas.counter(user.Email, user.Level, quota)
+}
+
+// QuotaUsages implements policy.QuotaManager. Saved usages of users that haven't connected since started are included
+// by the levels that they had when saved.
+func (m *Instance) QuotaUsages() []policy.QuotaUsage {
+	m.quotas.Lock()
+	counters := make([]*quotaCounter, 0, len(m.quotas.counters)+len(m.quotas.saved))
+	for _, c := range m.quotas.counters {
+		counters = append(counters, c)
+	}
+	for _, u := range m.quotas.saved {
+		quota := m.quotaFor(u.Email, u.Level)
+		if quota.Bytes <= 0 {
+			continue
+		}
+		counters = append(counters, &quotaCounter{
+			email: u.Email,
+			level: u.Level,
+			quota: quota,
+			used:  u.Used,
+			start: time.Unix(u.PeriodStart, 0),
+		})
+	}
+	m.quotas.Unlock()
+
+	usages := make([]policy.QuotaUsage, 0, len(counters))
+	for _, c := range counters {
+		usages = append(usages, c.Usage())
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].Email < usages[j].Email
+	})
+	return usages
+}
+
+// ResetQuota implements policy.QuotaManager.
+func (m *Instance) ResetQuota(email string) error {
+	m.quotas.Lock()
+	c, found := m.quotas.counters[email]
+	if !found {
+		_, found = m.quotas.saved[email]
+		delete(m.quotas.saved, email)
+	}
+	m.quotas.Unlock()
+
+	if c != nil {
+		c.reset()
+		return nil
+	}
+	if !found {
+		return newError("no quota usage of user ", email)
+	}
+	return nil
+}
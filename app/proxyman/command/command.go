@@ -4,13 +4,17 @@ package command
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	grpc "google.golang.org/grpc"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/features/inbound"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
 )
 
@@ -34,15 +38,23 @@ func getInbound(handler inbound.Handler) (proxy.Inbound, error) {
 	return gi.GetInbound(), nil
 }
 
-// ApplyInbound implements InboundOperation.
-func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+func getUserManager(handler inbound.Handler) (proxy.UserManager, error) {
 	p, err := getInbound(handler)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	um, ok := p.(proxy.UserManager)
 	if !ok {
-		return newError("proxy is not a UserManager")
+		return nil, newError("proxy is not a UserManager")
+	}
+	return um, nil
+}
+
+// ApplyInbound implements InboundOperation.
+func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
+	um, err := getUserManager(handler)
+	if err != nil {
+		return err
 	}
 	mUser, err := op.User.ToMemoryUser()
 	if err != nil {
@@ -53,21 +65,18 @@ func (op *AddUserOperation) ApplyInbound(ctx context.Context, handler inbound.Ha
 
 // ApplyInbound implements InboundOperation.
 func (op *RemoveUserOperation) ApplyInbound(ctx context.Context, handler inbound.Handler) error {
-	p, err := getInbound(handler)
+	um, err := getUserManager(handler)
 	if err != nil {
 		return err
 	}
-	um, ok := p.(proxy.UserManager)
-	if !ok {
-		return newError("proxy is not a UserManager")
-	}
 	return um.RemoveUser(ctx, op.Email)
 }
 
 type handlerServer struct {
-	s   *core.Instance
-	ihm inbound.Manager
-	ohm outbound.Manager
+	s     *core.Instance
+	ihm   inbound.Manager
+	ohm   outbound.Manager
+	stats stats.Manager
 }
 
 func (s *handlerServer) AddInbound(ctx context.Context, request *AddInboundRequest) (*AddInboundResponse, error) {
@@ -100,6 +109,73 @@ func (s *handlerServer) AlterInbound(ctx context.Context, request *AlterInboundR
 	return &AlterInboundResponse{}, operation.ApplyInbound(ctx, handler)
 }
 
+func (s *handlerServer) userInfo(user *protocol.MemoryUser) *UserInfo {
+	info := &UserInfo{
+		Email: user.Email,
+		Level: user.Level,
+	}
+	if len(user.Email) > 0 && s.stats != nil {
+		if c := s.stats.GetCounter("user>>>" + user.Email + ">>>traffic>>>uplink"); c != nil {
+			info.Uplink = c.Value()
+		}
+		if c := s.stats.GetCounter("user>>>" + user.Email + ">>>traffic>>>downlink"); c != nil {
+			info.Downlink = c.Value()
+		}
+	}
+	return info
+}
+
+func (s *handlerServer) getUsers(ctx context.Context, tag string) ([]*protocol.MemoryUser, error) {
+	handler, err := s.ihm.GetHandler(ctx, tag)
+	if err != nil {
+		return nil, newError("failed to get handler: ", tag).Base(err)
+	}
+	um, err := getUserManager(handler)
+	if err != nil {
+		return nil, err
+	}
+	lister, ok := um.(proxy.UserLister)
+	if !ok {
+		return nil, newError("listing users not supported by handler: ", tag)
+	}
+	return lister.GetUsers(ctx), nil
+}
+
+func (s *handlerServer) ListUsers(ctx context.Context, request *ListUsersRequest) (*ListUsersResponse, error) {
+	users, err := s.getUsers(ctx, request.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListUsersResponse{
+		User: make([]*UserInfo, 0, len(users)),
+	}
+	for _, user := range users {
+		response.User = append(response.User, s.userInfo(user))
+	}
+	sort.Slice(response.User, func(i, j int) bool {
+		return response.User[i].Email < response.User[j].Email
+	})
+	return response, nil
+}
+
+func (s *handlerServer) GetUser(ctx context.Context, request *GetUserRequest) (*GetUserResponse, error) {
+	if request.Email == "" {
+		return nil, newError("Email must not be empty.")
+	}
+	users, err := s.getUsers(ctx, request.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if strings.EqualFold(user.Email, request.Email) {
+			return &GetUserResponse{User: s.userInfo(user)}, nil
+		}
+	}
+	return nil, newError("User ", request.Email, " not found.")
+}
+
 func (s *handlerServer) AddOutbound(ctx context.Context, request *AddOutboundRequest) (*AddOutboundResponse, error) {
 	if err := core.AddOutboundHandler(s.s, request.Outbound); err != nil {
 		return nil, err
@@ -133,9 +209,10 @@ func (s *service) Register(server *grpc.Server) {
 	hs := &handlerServer{
 		s: s.v,
 	}
-	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager) {
+	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager, sm stats.Manager) {
 		hs.ihm = im
 		hs.ohm = om
+		hs.stats = sm
 	}))
 	RegisterHandlerServiceServer(server, hs)
 }
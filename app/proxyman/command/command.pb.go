@@ -5,6 +5,8 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	math "math"
 	core "v2ray.com/core"
 	protocol "v2ray.com/core/common/protocol"
@@ -536,6 +538,237 @@ func (m *AlterOutboundResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AlterOutboundResponse proto.InternalMessageInfo
 
+type ListUsersRequest struct {
+	// Tag of the inbound handler.
+	Tag                  string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListUsersRequest) Reset()         { *m = ListUsersRequest{} }
+func (m *ListUsersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListUsersRequest) ProtoMessage()    {}
+func (*ListUsersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{14}
+}
+
+func (m *ListUsersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUsersRequest.Unmarshal(m, b)
+}
+func (m *ListUsersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUsersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListUsersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUsersRequest.Merge(m, src)
+}
+func (m *ListUsersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListUsersRequest.Size(m)
+}
+func (m *ListUsersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUsersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUsersRequest proto.InternalMessageInfo
+
+func (m *ListUsersRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+type UserInfo struct {
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Level uint32 `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	// Traffic in bytes of the user, if user traffic stats are enabled for its
+	// level.
+	Uplink               int64    `protobuf:"varint,3,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink             int64    `protobuf:"varint,4,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UserInfo) Reset()         { *m = UserInfo{} }
+func (m *UserInfo) String() string { return proto.CompactTextString(m) }
+func (*UserInfo) ProtoMessage()    {}
+func (*UserInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{15}
+}
+
+func (m *UserInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UserInfo.Unmarshal(m, b)
+}
+func (m *UserInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UserInfo.Marshal(b, m, deterministic)
+}
+func (m *UserInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UserInfo.Merge(m, src)
+}
+func (m *UserInfo) XXX_Size() int {
+	return xxx_messageInfo_UserInfo.Size(m)
+}
+func (m *UserInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_UserInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UserInfo proto.InternalMessageInfo
+
+func (m *UserInfo) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UserInfo) GetLevel() uint32 {
+	if m != nil {
+		return m.Level
+	}
+	return 0
+}
+
+func (m *UserInfo) GetUplink() int64 {
+	if m != nil {
+		return m.Uplink
+	}
+	return 0
+}
+
+func (m *UserInfo) GetDownlink() int64 {
+	if m != nil {
+		return m.Downlink
+	}
+	return 0
+}
+
+type ListUsersResponse struct {
+	User                 []*UserInfo `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListUsersResponse) Reset()         { *m = ListUsersResponse{} }
+func (m *ListUsersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListUsersResponse) ProtoMessage()    {}
+func (*ListUsersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{16}
+}
+
+func (m *ListUsersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListUsersResponse.Unmarshal(m, b)
+}
+func (m *ListUsersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListUsersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListUsersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListUsersResponse.Merge(m, src)
+}
+func (m *ListUsersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListUsersResponse.Size(m)
+}
+func (m *ListUsersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListUsersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListUsersResponse proto.InternalMessageInfo
+
+func (m *ListUsersResponse) GetUser() []*UserInfo {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	// Tag of the inbound handler.
+	Tag                  string   `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Email                string   `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetUserRequest) Reset()         { *m = GetUserRequest{} }
+func (m *GetUserRequest) String() string { return proto.CompactTextString(m) }
+func (*GetUserRequest) ProtoMessage()    {}
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{17}
+}
+
+func (m *GetUserRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserRequest.Unmarshal(m, b)
+}
+func (m *GetUserRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserRequest.Marshal(b, m, deterministic)
+}
+func (m *GetUserRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserRequest.Merge(m, src)
+}
+func (m *GetUserRequest) XXX_Size() int {
+	return xxx_messageInfo_GetUserRequest.Size(m)
+}
+func (m *GetUserRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserRequest proto.InternalMessageInfo
+
+func (m *GetUserRequest) GetTag() string {
+	if m != nil {
+		return m.Tag
+	}
+	return ""
+}
+
+func (m *GetUserRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type GetUserResponse struct {
+	User                 *UserInfo `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetUserResponse) Reset()         { *m = GetUserResponse{} }
+func (m *GetUserResponse) String() string { return proto.CompactTextString(m) }
+func (*GetUserResponse) ProtoMessage()    {}
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e2c30a70a48636a0, []int{18}
+}
+
+func (m *GetUserResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetUserResponse.Unmarshal(m, b)
+}
+func (m *GetUserResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetUserResponse.Marshal(b, m, deterministic)
+}
+func (m *GetUserResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetUserResponse.Merge(m, src)
+}
+func (m *GetUserResponse) XXX_Size() int {
+	return xxx_messageInfo_GetUserResponse.Size(m)
+}
+func (m *GetUserResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetUserResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetUserResponse proto.InternalMessageInfo
+
+func (m *GetUserResponse) GetUser() *UserInfo {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
 type Config struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -546,7 +779,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e2c30a70a48636a0, []int{14}
+	return fileDescriptor_e2c30a70a48636a0, []int{19}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -582,6 +815,11 @@ func init() {
 	proto.RegisterType((*RemoveOutboundResponse)(nil), "v2ray.core.app.proxyman.command.RemoveOutboundResponse")
 	proto.RegisterType((*AlterOutboundRequest)(nil), "v2ray.core.app.proxyman.command.AlterOutboundRequest")
 	proto.RegisterType((*AlterOutboundResponse)(nil), "v2ray.core.app.proxyman.command.AlterOutboundResponse")
+	proto.RegisterType((*ListUsersRequest)(nil), "v2ray.core.app.proxyman.command.ListUsersRequest")
+	proto.RegisterType((*UserInfo)(nil), "v2ray.core.app.proxyman.command.UserInfo")
+	proto.RegisterType((*ListUsersResponse)(nil), "v2ray.core.app.proxyman.command.ListUsersResponse")
+	proto.RegisterType((*GetUserRequest)(nil), "v2ray.core.app.proxyman.command.GetUserRequest")
+	proto.RegisterType((*GetUserResponse)(nil), "v2ray.core.app.proxyman.command.GetUserResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.proxyman.command.Config")
 }
 
@@ -590,42 +828,51 @@ func init() {
 }
 
 var fileDescriptor_e2c30a70a48636a0 = []byte{
-	// 557 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0xdf, 0x6b, 0xd3, 0x40,
-	0x1c, 0xb7, 0x53, 0xbb, 0xed, 0x3b, 0x1d, 0xf3, 0xda, 0x6e, 0x25, 0x3e, 0x6c, 0x46, 0x90, 0x0d,
-	0xe1, 0xa2, 0x59, 0x37, 0x41, 0xf0, 0xa1, 0xd6, 0x87, 0xf9, 0x20, 0x1d, 0x99, 0xfa, 0xe0, 0x8b,
-	0xdc, 0x92, 0xb3, 0x04, 0x92, 0xbb, 0xf3, 0x92, 0x56, 0x2b, 0x08, 0x82, 0xff, 0x80, 0x7f, 0x87,
-	0x7f, 0xa5, 0x24, 0x77, 0xd7, 0x25, 0x69, 0x21, 0x0d, 0xf8, 0xd4, 0xf4, 0xfa, 0xf9, 0xf5, 0xfd,
-	0xde, 0x27, 0x14, 0x9e, 0xcf, 0x5c, 0x49, 0xe6, 0xd8, 0xe7, 0xb1, 0xe3, 0x73, 0x49, 0x1d, 0x22,
-	0x84, 0x23, 0x24, 0xff, 0x3e, 0x8f, 0x09, 0x73, 0x7c, 0x1e, 0xc7, 0x84, 0x05, 0xe6, 0x13, 0x0b,
-	0xc9, 0x53, 0x8e, 0x0e, 0x0d, 0x45, 0x52, 0x4c, 0x84, 0xc0, 0x06, 0x8e, 0x35, 0xcc, 0x3a, 0xa9,
-	0x68, 0x66, 0xe7, 0x9c, 0x39, 0x39, 0xdb, 0xe7, 0x91, 0x33, 0x4d, 0xa8, 0x54, 0x5a, 0xd6, 0xb3,
-	0xd5, 0xd0, 0x84, 0xca, 0x90, 0x44, 0x4e, 0x3a, 0x17, 0x34, 0xf8, 0x1c, 0xd3, 0x24, 0x21, 0x13,
-	0xaa, 0x19, 0x0f, 0x97, 0x18, 0xec, 0x4b, 0x38, 0x51, 0x3f, 0xda, 0x17, 0xb0, 0x37, 0x0c, 0x82,
-	0x0f, 0x09, 0x95, 0x63, 0x41, 0x25, 0x49, 0x43, 0xce, 0xd0, 0x00, 0xee, 0x64, 0x86, 0xfd, 0xd6,
-	0x51, 0xeb, 0x78, 0xc7, 0x3d, 0xc2, 0x85, 0xf4, 0xca, 0x0d, 0x9b, 0x60, 0x38, 0x23, 0x7a, 0x39,
-	0xda, 0x7e, 0x0a, 0x1d, 0x8f, 0xc6, 0x7c, 0x46, 0xcb, 0x62, 0x5d, 0xb8, 0x4b, 0x63, 0x12, 0x46,
-	0xb9, 0xda, 0xb6, 0xa7, 0xbe, 0xd8, 0x63, 0x78, 0x30, 0x0c, 0x82, 0xb7, 0xec, 0x9a, 0x4f, 0x59,
-	0xe0, 0xd1, 0xaf, 0x53, 0x9a, 0xa4, 0xe8, 0x25, 0x6c, 0x86, 0xea, 0x64, 0x95, 0xb5, 0x06, 0x5f,
-	0x10, 0x16, 0x44, 0x54, 0x8e, 0xf2, 0x21, 0x3c, 0x43, 0xb0, 0xbb, 0x80, 0x8a, 0x82, 0x89, 0xe0,
-	0x2c, 0xa1, 0xf6, 0x31, 0x74, 0x55, 0xa6, 0x8a, 0xd3, 0x1e, 0xdc, 0x4e, 0xc9, 0x44, 0x47, 0xca,
-	0x1e, 0xed, 0x03, 0xe8, 0x55, 0x90, 0x5a, 0x22, 0x86, 0xce, 0x30, 0x4a, 0xa9, 0xac, 0x53, 0x40,
-	0x6f, 0x60, 0x9b, 0x9b, 0xa9, 0xfb, 0x1b, 0x79, 0xfe, 0x27, 0x2b, 0x56, 0xa7, 0x2e, 0x0a, 0xbf,
-	0xcf, 0x2e, 0xea, 0x9d, 0xba, 0x27, 0xef, 0x86, 0x68, 0xef, 0x43, 0xb7, 0x6c, 0xa7, 0x63, 0x5c,
-	0xe5, 0xf3, 0x8d, 0xa7, 0x69, 0x29, 0xc5, 0x2b, 0xd8, 0xe2, 0xfa, 0x48, 0xaf, 0xec, 0x51, 0xd1,
-	0xd2, 0xc0, 0xcb, 0x3b, 0x5b, 0x50, 0xec, 0x1e, 0x74, 0x4a, 0xa2, 0xda, 0xeb, 0xc4, 0xec, 0xa2,
-	0x6a, 0xb7, 0xbc, 0xb6, 0x3e, 0xec, 0x57, 0xa1, 0x5a, 0x84, 0xe9, 0x41, 0x6a, 0x35, 0xfe, 0xd3,
-	0xe2, 0x0e, 0xa0, 0x57, 0xf1, 0xd3, 0x41, 0xb6, 0xa0, 0xad, 0x06, 0x77, 0xff, 0xb4, 0x61, 0x57,
-	0xaf, 0xe2, 0x8a, 0xca, 0x59, 0xe8, 0x53, 0xf4, 0x0d, 0xe0, 0xa6, 0x36, 0xc8, 0xc5, 0x35, 0x2f,
-	0x2a, 0x5e, 0x2a, 0xad, 0x75, 0xda, 0x88, 0xa3, 0x33, 0xdd, 0x42, 0xbf, 0x5a, 0x70, 0xbf, 0x54,
-	0x38, 0x74, 0x56, 0x2b, 0xb4, 0xaa, 0xca, 0xd6, 0x79, 0x53, 0xda, 0x22, 0xc2, 0x4f, 0xb8, 0x57,
-	0xac, 0x1a, 0x1a, 0xd4, 0x4f, 0xb2, 0xfc, 0x22, 0x58, 0x67, 0x0d, 0x59, 0x0b, 0xfb, 0x1f, 0xb0,
-	0x53, 0x28, 0x1f, 0x5a, 0x6b, 0x8f, 0x95, 0x32, 0x59, 0x83, 0x66, 0xa4, 0x85, 0xf7, 0xef, 0x16,
-	0xec, 0x96, 0x7b, 0x8b, 0xd6, 0xdd, 0x63, 0x35, 0xc2, 0x8b, 0xc6, 0xbc, 0x52, 0x07, 0x4a, 0x9d,
-	0x45, 0x6b, 0x2e, 0xb3, 0x9a, 0xe1, 0xbc, 0x29, 0xcd, 0x44, 0x78, 0xed, 0xc1, 0x63, 0x9f, 0xc7,
-	0x75, 0xf4, 0xcb, 0xd6, 0xa7, 0x4d, 0xfd, 0xf8, 0x77, 0xe3, 0xf0, 0xa3, 0xeb, 0x91, 0x39, 0x1e,
-	0x65, 0xe0, 0xa1, 0x10, 0xf8, 0xd2, 0x80, 0x47, 0x0a, 0x71, 0xdd, 0xce, 0xff, 0x1d, 0x4e, 0xff,
-	0x05, 0x00, 0x00, 0xff, 0xff, 0x2f, 0x05, 0xaa, 0x44, 0x29, 0x07, 0x00, 0x00,
+	// 698 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x4f, 0xd4, 0x40,
+	0x10, 0xb7, 0x1c, 0x72, 0xc7, 0x20, 0x08, 0xcb, 0x01, 0x97, 0xfa, 0x00, 0x56, 0x63, 0x8e, 0x98,
+	0x6c, 0xa1, 0xfc, 0xd1, 0x98, 0xf0, 0x70, 0x62, 0x22, 0x24, 0x1a, 0x48, 0x51, 0x1f, 0x7c, 0x31,
+	0xa5, 0x5d, 0x2e, 0xd5, 0x76, 0xb7, 0x6e, 0x7b, 0x87, 0x67, 0x62, 0x62, 0xe2, 0xb7, 0xf1, 0x03,
+	0xfa, 0x6c, 0xda, 0xdd, 0x96, 0xb6, 0x77, 0xa4, 0x57, 0xe3, 0xd3, 0x75, 0xb7, 0xf3, 0xfb, 0x33,
+	0xd3, 0x99, 0xc9, 0xc1, 0xee, 0xd0, 0xe0, 0xd6, 0x08, 0xdb, 0xcc, 0xd7, 0x6d, 0xc6, 0x89, 0x6e,
+	0x05, 0x81, 0x1e, 0x70, 0xf6, 0x6d, 0xe4, 0x5b, 0x54, 0xb7, 0x99, 0xef, 0x5b, 0xd4, 0x49, 0x7f,
+	0x71, 0xc0, 0x59, 0xc4, 0xd0, 0x66, 0x0a, 0xe1, 0x04, 0x5b, 0x41, 0x80, 0xd3, 0x70, 0x2c, 0xc3,
+	0xd4, 0xed, 0x12, 0x67, 0x7c, 0xcf, 0xa8, 0x9e, 0xa0, 0x6d, 0xe6, 0xe9, 0x83, 0x90, 0x70, 0xc1,
+	0xa5, 0xee, 0x4c, 0x0e, 0x0d, 0x09, 0x77, 0x2d, 0x4f, 0x8f, 0x46, 0x01, 0x71, 0x3e, 0xf9, 0x24,
+	0x0c, 0xad, 0x3e, 0x91, 0x88, 0x07, 0x63, 0x08, 0x7a, 0xe5, 0xf6, 0xc5, 0x4b, 0xed, 0x04, 0x96,
+	0x7b, 0x8e, 0xf3, 0x3e, 0x24, 0xfc, 0x2c, 0x20, 0xdc, 0x8a, 0x5c, 0x46, 0xd1, 0x3e, 0xcc, 0xc6,
+	0x82, 0x1d, 0x65, 0x4b, 0xe9, 0x2e, 0x18, 0x5b, 0x38, 0xe7, 0x5e, 0xa8, 0xe1, 0xd4, 0x18, 0x8e,
+	0x81, 0x66, 0x12, 0xad, 0x3d, 0x85, 0x55, 0x93, 0xf8, 0x6c, 0x48, 0x8a, 0x64, 0x6d, 0xb8, 0x4b,
+	0x7c, 0xcb, 0xf5, 0x12, 0xb6, 0x79, 0x53, 0x1c, 0xb4, 0x33, 0x58, 0xe9, 0x39, 0xce, 0x29, 0xbd,
+	0x64, 0x03, 0xea, 0x98, 0xe4, 0xeb, 0x80, 0x84, 0x11, 0x7a, 0x01, 0x4d, 0x57, 0xdc, 0x4c, 0x92,
+	0x96, 0xc1, 0x27, 0x16, 0x75, 0x3c, 0xc2, 0x8f, 0x93, 0x24, 0xcc, 0x14, 0xa0, 0xb5, 0x01, 0xe5,
+	0x09, 0xc3, 0x80, 0xd1, 0x90, 0x68, 0x5d, 0x68, 0x0b, 0x4f, 0x25, 0xa5, 0x65, 0x68, 0x44, 0x56,
+	0x5f, 0x5a, 0x8a, 0x1f, 0xb5, 0x0d, 0x58, 0x2b, 0x45, 0x4a, 0x0a, 0x1f, 0x56, 0x7b, 0x5e, 0x44,
+	0x78, 0x15, 0x03, 0x7a, 0x05, 0xf3, 0x2c, 0xcd, 0xba, 0x33, 0x93, 0xf8, 0x7f, 0x32, 0xa1, 0x74,
+	0xe2, 0x43, 0xe1, 0x77, 0xf1, 0x87, 0x7a, 0x2b, 0xbe, 0x93, 0x79, 0x03, 0xd4, 0xd6, 0xa1, 0x5d,
+	0x94, 0x93, 0x36, 0x2e, 0x92, 0xfc, 0xce, 0x06, 0x51, 0xc1, 0xc5, 0x11, 0xb4, 0x98, 0xbc, 0x92,
+	0x25, 0x7b, 0x98, 0x97, 0x4c, 0xc3, 0x8b, 0x35, 0xcb, 0x20, 0xda, 0x1a, 0xac, 0x16, 0x48, 0xa5,
+	0xd6, 0x76, 0x5a, 0x8b, 0xb2, 0xdc, 0x78, 0xd9, 0x3a, 0xb0, 0x5e, 0x0e, 0x95, 0x24, 0x54, 0x26,
+	0x52, 0xc9, 0xf1, 0x9f, 0x0a, 0xb7, 0x01, 0x6b, 0x25, 0x3d, 0x69, 0xe4, 0x31, 0x2c, 0xbf, 0x71,
+	0xc3, 0x28, 0xee, 0xca, 0xf0, 0xf6, 0x44, 0x3e, 0x43, 0x2b, 0x8e, 0x38, 0xa5, 0x57, 0x6c, 0x72,
+	0xcb, 0xc6, 0xb7, 0x1e, 0x19, 0x12, 0x2f, 0xb1, 0xb8, 0x68, 0x8a, 0x03, 0x5a, 0x87, 0xb9, 0x41,
+	0xe0, 0xb9, 0xf4, 0x4b, 0xa7, 0xb1, 0xa5, 0x74, 0x1b, 0xa6, 0x3c, 0x21, 0x15, 0x5a, 0x0e, 0xbb,
+	0xa6, 0xc9, 0x9b, 0xd9, 0xe4, 0x4d, 0x76, 0xd6, 0x4c, 0x58, 0xc9, 0x39, 0x12, 0x36, 0xd1, 0x51,
+	0x36, 0x74, 0x8d, 0xee, 0x82, 0xb1, 0x8d, 0x2b, 0x56, 0x06, 0x4e, 0xdd, 0xca, 0xe9, 0x7b, 0x0e,
+	0x4b, 0xaf, 0x49, 0x42, 0x79, 0x7b, 0xa1, 0xb3, 0xbc, 0x66, 0xf2, 0xa3, 0x78, 0x0e, 0xf7, 0x33,
+	0xe4, 0x98, 0x17, 0xe5, 0x5f, 0xbc, 0xb4, 0x60, 0x4e, 0xb4, 0x9a, 0xf1, 0xa7, 0x09, 0x4b, 0xb2,
+	0xf9, 0x2e, 0x08, 0x1f, 0xba, 0x36, 0x41, 0xd7, 0x00, 0x37, 0x83, 0x8a, 0x8c, 0x4a, 0xee, 0xb1,
+	0x35, 0xa1, 0xee, 0xd5, 0xc2, 0xc8, 0x2e, 0xb8, 0x83, 0x7e, 0x2a, 0xb0, 0x58, 0x18, 0x71, 0x74,
+	0x50, 0x49, 0x34, 0x69, 0x79, 0xa8, 0x87, 0x75, 0x61, 0x99, 0x85, 0x1f, 0x70, 0x2f, 0x3f, 0xdc,
+	0x68, 0xbf, 0x3a, 0x93, 0xf1, 0xd5, 0xa3, 0x1e, 0xd4, 0x44, 0x65, 0xf2, 0x11, 0xcc, 0x67, 0x7d,
+	0x87, 0x76, 0x2b, 0x59, 0xca, 0x53, 0xa3, 0x1a, 0x75, 0x20, 0x99, 0x2a, 0x85, 0xa6, 0xec, 0x2f,
+	0xa4, 0x57, 0x12, 0x14, 0x7b, 0x58, 0xdd, 0x99, 0x1e, 0x90, 0xe9, 0x7d, 0x87, 0x85, 0xdc, 0x52,
+	0x43, 0x53, 0x75, 0x4b, 0x69, 0x49, 0xa9, 0xfb, 0xf5, 0x40, 0x99, 0xf6, 0x2f, 0x05, 0x96, 0x8a,
+	0xfb, 0x10, 0x4d, 0xdb, 0x2d, 0x65, 0x0b, 0xcf, 0x6a, 0xe3, 0x0a, 0x9d, 0x5e, 0xd8, 0x85, 0x68,
+	0xca, 0x96, 0x29, 0x7b, 0x38, 0xac, 0x0b, 0x4b, 0x2d, 0xbc, 0x34, 0xe1, 0x91, 0xcd, 0xfc, 0x2a,
+	0xf8, 0xb9, 0xf2, 0xb1, 0x29, 0x1f, 0x7f, 0xcf, 0x6c, 0x7e, 0x30, 0x4c, 0x6b, 0x84, 0x8f, 0xe3,
+	0xe0, 0x5e, 0x10, 0xe0, 0xf3, 0x34, 0xf8, 0x58, 0x44, 0x5c, 0xce, 0x25, 0xff, 0x3a, 0xf6, 0xfe,
+	0x0e, 0x00, 0xc9, 0x69, 0xc6, 0x15, 0x81, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -643,6 +890,8 @@ type HandlerServiceClient interface {
 	AddInbound(ctx context.Context, in *AddInboundRequest, opts ...grpc.CallOption) (*AddInboundResponse, error)
 	RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*RemoveInboundResponse, error)
 	AlterInbound(ctx context.Context, in *AlterInboundRequest, opts ...grpc.CallOption) (*AlterInboundResponse, error)
+	ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
 	AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*AddOutboundResponse, error)
 	RemoveOutbound(ctx context.Context, in *RemoveOutboundRequest, opts ...grpc.CallOption) (*RemoveOutboundResponse, error)
 	AlterOutbound(ctx context.Context, in *AlterOutboundRequest, opts ...grpc.CallOption) (*AlterOutboundResponse, error)
@@ -683,6 +932,24 @@ func (c *handlerServiceClient) AlterInbound(ctx context.Context, in *AlterInboun
 	return out, nil
 }
 
+func (c *handlerServiceClient) ListUsers(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
+	out := new(ListUsersResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/ListUsers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error) {
+	out := new(GetUserResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/GetUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *handlerServiceClient) AddOutbound(ctx context.Context, in *AddOutboundRequest, opts ...grpc.CallOption) (*AddOutboundResponse, error) {
 	out := new(AddOutboundResponse)
 	err := c.cc.Invoke(ctx, "/v2ray.core.app.proxyman.command.HandlerService/AddOutbound", in, out, opts...)
@@ -715,11 +982,42 @@ type HandlerServiceServer interface {
 	AddInbound(context.Context, *AddInboundRequest) (*AddInboundResponse, error)
 	RemoveInbound(context.Context, *RemoveInboundRequest) (*RemoveInboundResponse, error)
 	AlterInbound(context.Context, *AlterInboundRequest) (*AlterInboundResponse, error)
+	ListUsers(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
 	AddOutbound(context.Context, *AddOutboundRequest) (*AddOutboundResponse, error)
 	RemoveOutbound(context.Context, *RemoveOutboundRequest) (*RemoveOutboundResponse, error)
 	AlterOutbound(context.Context, *AlterOutboundRequest) (*AlterOutboundResponse, error)
 }
 
+// UnimplementedHandlerServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedHandlerServiceServer struct {
+}
+
+func (*UnimplementedHandlerServiceServer) AddInbound(ctx context.Context, req *AddInboundRequest) (*AddInboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInbound not implemented")
+}
+func (*UnimplementedHandlerServiceServer) RemoveInbound(ctx context.Context, req *RemoveInboundRequest) (*RemoveInboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveInbound not implemented")
+}
+func (*UnimplementedHandlerServiceServer) AlterInbound(ctx context.Context, req *AlterInboundRequest) (*AlterInboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AlterInbound not implemented")
+}
+func (*UnimplementedHandlerServiceServer) ListUsers(ctx context.Context, req *ListUsersRequest) (*ListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (*UnimplementedHandlerServiceServer) GetUser(ctx context.Context, req *GetUserRequest) (*GetUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (*UnimplementedHandlerServiceServer) AddOutbound(ctx context.Context, req *AddOutboundRequest) (*AddOutboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddOutbound not implemented")
+}
+func (*UnimplementedHandlerServiceServer) RemoveOutbound(ctx context.Context, req *RemoveOutboundRequest) (*RemoveOutboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveOutbound not implemented")
+}
+func (*UnimplementedHandlerServiceServer) AlterOutbound(ctx context.Context, req *AlterOutboundRequest) (*AlterOutboundResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AlterOutbound not implemented")
+}
+
 func RegisterHandlerServiceServer(s *grpc.Server, srv HandlerServiceServer) {
 	s.RegisterService(&_HandlerService_serviceDesc, srv)
 }
@@ -778,6 +1076,42 @@ func _HandlerService_AlterInbound_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HandlerService_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/ListUsers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).ListUsers(ctx, req.(*ListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandlerService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandlerServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.proxyman.command.HandlerService/GetUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandlerServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HandlerService_AddOutbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddOutboundRequest)
 	if err := dec(in); err != nil {
@@ -848,6 +1182,14 @@ var _HandlerService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "AlterInbound",
 			Handler:    _HandlerService_AlterInbound_Handler,
 		},
+		{
+			MethodName: "ListUsers",
+			Handler:    _HandlerService_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _HandlerService_GetUser_Handler,
+		},
 		{
 			MethodName: "AddOutbound",
 			Handler:    _HandlerService_AddOutbound_Handler,
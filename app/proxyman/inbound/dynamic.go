@@ -112,6 +112,7 @@ func (h *DynamicInboundHandler) refresh() error {
 	}
 
 	uplinkCounter, downlinkCounter := getStatCounter(h.v, h.tag)
+	connGauge := getConnectionGauge(h.v, h.tag)
 
 	for i := uint32(0); i < concurrency; i++ {
 		port := h.allocatePort()
@@ -134,6 +135,7 @@ func (h *DynamicInboundHandler) refresh() error {
 				sniffingConfig:  h.receiverConfig.GetEffectiveSniffingSettings(),
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				connGauge:       connGauge,
 			}
 			if err := worker.Start(); err != nil {
 				newError("failed to create TCP worker").Base(err).AtWarning().WriteToLog()
@@ -151,6 +153,7 @@ func (h *DynamicInboundHandler) refresh() error {
 				dispatcher:      h.mux,
 				uplinkCounter:   uplinkCounter,
 				downlinkCounter: downlinkCounter,
+				connGauge:       connGauge,
 				stream:          h.streamSettings,
 			}
 			if err := worker.Start(); err != nil {
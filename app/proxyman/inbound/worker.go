@@ -41,6 +41,7 @@ type tcpWorker struct {
 	sniffingConfig  *proxyman.SniffingConfig
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	connGauge       stats.Gauge
 
 	hub internet.Listener
 }
@@ -57,6 +58,11 @@ func (w *tcpWorker) callback(conn internet.Connection) {
 	sid := session.NewID()
 	ctx = session.ContextWithID(ctx, sid)
 
+	if w.connGauge != nil {
+		w.connGauge.Add(1)
+		defer w.connGauge.Add(-1)
+	}
+
 	if w.recvOrigDest {
 		var dest net.Destination
 		switch getTProxyType(w.stream) {
@@ -230,6 +236,7 @@ type udpWorker struct {
 	dispatcher      routing.Dispatcher
 	uplinkCounter   stats.Counter
 	downlinkCounter stats.Counter
+	connGauge       stats.Gauge
 
 	checker    *task.Periodic
 	activeConn map[connID]*udpConn
@@ -284,6 +291,11 @@ func (w *udpWorker) callback(b *buf.Buffer, source net.Destination, originalDest
 		common.Must(w.checker.Start())
 
 		go func() {
+			if w.connGauge != nil {
+				w.connGauge.Add(1)
+				defer w.connGauge.Add(-1)
+			}
+
 			ctx := context.Background()
 			sid := session.NewID()
 			ctx = session.ContextWithID(ctx, sid)
@@ -2,6 +2,7 @@ package outbound
 
 import (
 	"context"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/app/proxyman"
@@ -10,6 +11,8 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/features/policy"
+	"v2ray.com/core/features/stats"
 	"v2ray.com/core/proxy"
 	"v2ray.com/core/transport"
 	"v2ray.com/core/transport/internet"
@@ -25,6 +28,9 @@ type Handler struct {
 	proxy           proxy.Outbound
 	outboundManager outbound.Manager
 	mux             *mux.ClientManager
+	stats           *connectionStats
+	uplinkCounter   stats.Counter
+	downlinkCounter stats.Counter
 }
 
 // NewHandler create a new Handler based on the given configuration.
@@ -35,6 +41,20 @@ func NewHandler(ctx context.Context, config *core.OutboundHandlerConfig) (outbou
 		outboundManager: v.GetFeature(outbound.ManagerType()).(outbound.Manager),
 	}
 
+	if len(config.Tag) > 0 {
+		systemStats := v.GetFeature(policy.ManagerType()).(policy.Manager).ForSystem().Stats
+		statsManager := v.GetFeature(stats.ManagerType()).(stats.Manager)
+		if systemStats.OutboundConnection {
+			h.stats = newConnectionStats(statsManager, config.Tag)
+		}
+		if systemStats.OutboundUplink {
+			h.uplinkCounter, _ = stats.GetOrRegisterCounter(statsManager, "outbound>>>"+config.Tag+">>>traffic>>>uplink")
+		}
+		if systemStats.OutboundDownlink {
+			h.downlinkCounter, _ = stats.GetOrRegisterCounter(statsManager, "outbound>>>"+config.Tag+">>>traffic>>>downlink")
+		}
+	}
+
 	if config.SenderSettings != nil {
 		senderSettings, err := config.SenderSettings.GetInstance()
 		if err != nil {
@@ -99,6 +119,7 @@ func (h *Handler) Tag() string {
 
 // Dispatch implements proxy.Outbound.Dispatch.
 func (h *Handler) Dispatch(ctx context.Context, link *transport.Link) {
+	link = h.statLink(link)
 	if h.mux != nil && (h.mux.Enabled || session.MuxPreferedFromContext(ctx)) {
 		if err := h.mux.Dispatch(ctx, link); err != nil {
 			newError("failed to process mux outbound traffic").Base(err).WriteToLog(session.ExportIDToError(ctx))
@@ -164,7 +185,12 @@ func (h *Handler) Dial(ctx context.Context, dest net.Destination) (internet.Conn
 		}
 	}
 
-	return internet.Dial(ctx, dest, h.streamSettings)
+	start := time.Now()
+	conn, err := internet.Dial(ctx, dest, h.streamSettings)
+	if h.stats != nil {
+		h.stats.dialed(start, err)
+	}
+	return conn, err
 }
 
 // GetOutbound implements proxy.GetOutbound.
@@ -1,13 +1,146 @@
 package outbound_test
 
 import (
+	"context"
+	"io"
 	"testing"
 
+	"v2ray.com/core"
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/app/proxyman"
 	. "v2ray.com/core/app/proxyman/outbound"
+	"v2ray.com/core/app/stats"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/outbound"
+	feature_stats "v2ray.com/core/features/stats"
+	"v2ray.com/core/proxy/freedom"
+	"v2ray.com/core/transport"
+	_ "v2ray.com/core/transport/internet/tcp"
+	"v2ray.com/core/transport/pipe"
 )
 
 func TestInterfaces(t *testing.T) {
 	_ = (outbound.Handler)(new(Handler))
 	_ = (outbound.Manager)(new(Manager))
 }
+
+func newStatsInstance(systemStats *policy.SystemPolicy_Stats) *core.Instance {
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&policy.Config{
+				System: &policy.SystemPolicy{
+					Stats: systemStats,
+				},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	}
+	v, err := core.New(config)
+	common.Must(err)
+	common.Must(v.Start())
+	return v
+}
+
+func TestOutboundConnectionStats(t *testing.T) {
+	v := newStatsInstance(&policy.SystemPolicy_Stats{
+		OutboundConnection: true,
+	})
+	defer v.Close()
+
+	// Dial a port that is not listened.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	dest := net.DestinationFromAddr(listener.Addr())
+	common.Must(listener.Close())
+
+	h := v.GetFeature(outbound.ManagerType()).(outbound.Manager).GetHandler("direct").(*Handler)
+	if _, err := h.Dial(context.Background(), dest); err == nil {
+		t.Fatal("expect dial error, but nil")
+	}
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: dest})
+	common.Must(uplinkWriter.Close())
+	h.Dispatch(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+	downlinkReader.Interrupt()
+
+	sm := v.GetFeature(feature_stats.ManagerType()).(*stats.Manager)
+	if c := sm.GetCounter("outbound>>>direct>>>dial>>>failure>>>refused"); c == nil || c.Value() < 1 {
+		t.Error("expect refused dial counted, but got ", c)
+	}
+	if g := sm.GetGauge("outbound>>>direct>>>connection>>>active"); g == nil || g.Value() != 0 {
+		t.Error("expect no active connection, but got ", g)
+	}
+	if h := sm.GetHistogram("outbound>>>direct>>>connection>>>duration"); h == nil || h.Snapshot().Count != 1 {
+		t.Error("expect 1 connection duration observed, but got ", h)
+	}
+}
+
+func TestOutboundTrafficStats(t *testing.T) {
+	v := newStatsInstance(&policy.SystemPolicy_Stats{
+		OutboundUplink:   true,
+		OutboundDownlink: true,
+	})
+	defer v.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		b := make([]byte, 4)
+		if _, err := io.ReadFull(conn, b); err == nil {
+			conn.Write([]byte("pong"))
+		}
+	}()
+
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	common.Must(uplinkWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("ping"))))
+	common.Must(uplinkWriter.Close())
+
+	dest := net.DestinationFromAddr(listener.Addr())
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: dest})
+	h := v.GetFeature(outbound.ManagerType()).(outbound.Manager).GetHandler("direct")
+	h.Dispatch(ctx, &transport.Link{Reader: uplinkReader, Writer: downlinkWriter})
+
+	var mb buf.MultiBuffer
+	for {
+		b, err := downlinkReader.ReadMultiBuffer()
+		mb = append(mb, b...)
+		if err != nil {
+			break
+		}
+	}
+	if mb.String() != "pong" {
+		t.Error("unexpected response: ", mb.String())
+	}
+
+	sm := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+	for name, value := range map[string]int64{
+		"outbound>>>direct>>>traffic>>>uplink":   4,
+		"outbound>>>direct>>>traffic>>>downlink": 4,
+	} {
+		if c := sm.GetCounter(name); c == nil || c.Value() != value {
+			t.Error("unexpected ", name, ": ", c)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package outbound
+
+import (
+	"context"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+
+	"v2ray.com/core/app/dispatcher"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/features/stats"
+	"v2ray.com/core/transport"
+)
+
+// connectionStats are stats of connections of an outbound handler.
+type connectionStats struct {
+	manager   stats.Manager
+	prefix    string
+	active    stats.Gauge
+	durations stats.Histogram
+	// dials are latencies of dialing connections by the transport.
+	dials stats.Histogram
+}
+
+func newConnectionStats(manager stats.Manager, tag string) *connectionStats {
+	prefix := "outbound>>>" + tag + ">>>"
+	s := &connectionStats{
+		manager: manager,
+		prefix:  prefix,
+	}
+	s.active, _ = stats.GetOrRegisterGauge(manager, prefix+"connection>>>active")
+	s.durations, _ = stats.GetOrRegisterHistogram(manager, prefix+"connection>>>duration", stats.DurationBounds)
+	s.dials, _ = stats.GetOrRegisterHistogram(manager, prefix+"dial>>>latency", stats.LatencyBounds)
+	return s
+}
+
+// open counts a new connection, and returns a writer that counts the connection as closed when it is closed.
+func (s *connectionStats) open(writer buf.Writer) buf.Writer {
+	if s.active != nil {
+		s.active.Add(1)
+	}
+	return &connectionStatsWriter{
+		stats:  s,
+		writer: writer,
+		start:  time.Now(),
+	}
+}
+
+func (s *connectionStats) dialed(start time.Time, err error) {
+	if err != nil {
+		if c, _ := stats.GetOrRegisterCounter(s.manager, s.prefix+"dial>>>failure>>>"+dialFailureReason(err)); c != nil {
+			c.Add(1)
+		}
+		return
+	}
+	if s.dials != nil {
+		s.dials.Observe(time.Since(start).Seconds())
+	}
+}
+
+// dialFailureReason returns a short reason of the dial error, such as "timeout" or "refused".
+func dialFailureReason(err error) string {
+	cause := errors.Cause(err)
+	if opErr, ok := cause.(*net.OpError); ok {
+		cause = errors.Cause(opErr.Err)
+	}
+
+	switch cause {
+	case context.Canceled:
+		return "canceled"
+	case context.DeadlineExceeded:
+		return "timeout"
+	case syscall.ECONNREFUSED:
+		return "refused"
+	case syscall.ECONNRESET:
+		return "reset"
+	case syscall.EHOSTUNREACH, syscall.ENETUNREACH:
+		return "unreachable"
+	}
+	if _, ok := cause.(*net.DNSError); ok {
+		return "dns"
+	}
+	if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+		return "timeout"
+	}
+	return "other"
+}
+
+// statLink returns a link that counts traffic and connections of this handler.
+func (h *Handler) statLink(link *transport.Link) *transport.Link {
+	if h.stats == nil && h.uplinkCounter == nil && h.downlinkCounter == nil {
+		return link
+	}
+	reader, writer := link.Reader, link.Writer
+	if h.uplinkCounter != nil {
+		reader = &dispatcher.SizeStatReader{
+			Counter: h.uplinkCounter,
+			Reader:  reader,
+		}
+	}
+	if h.downlinkCounter != nil {
+		writer = &dispatcher.SizeStatWriter{
+			Counter: h.downlinkCounter,
+			Writer:  writer,
+		}
+	}
+	if h.stats != nil {
+		writer = h.stats.open(writer)
+	}
+	return &transport.Link{
+		Reader: reader,
+		Writer: writer,
+	}
+}
+
+// connectionStatsWriter records the duration of a connection when it is closed or interrupted.
+type connectionStatsWriter struct {
+	stats  *connectionStats
+	writer buf.Writer
+	start  time.Time
+	once   sync.Once
+}
+
+func (w *connectionStatsWriter) done() {
+	w.once.Do(func() {
+		if w.stats.active != nil {
+			w.stats.active.Add(-1)
+		}
+		if w.stats.durations != nil {
+			w.stats.durations.Observe(time.Since(w.start).Seconds())
+		}
+	})
+}
+
+func (w *connectionStatsWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	return w.writer.WriteMultiBuffer(mb)
+}
+
+func (w *connectionStatsWriter) Close() error {
+	w.done()
+	return common.Close(w.writer)
+}
+
+func (w *connectionStatsWriter) Interrupt() {
+	w.done()
+	common.Interrupt(w.writer)
+}
@@ -24,6 +24,7 @@ func (s *RandomStrategy) PickOutbound(tags []string) string {
 }
 
 type Balancer struct {
+	tag       string
 	selectors []string
 	strategy  BalancingStrategy
 	ohm       outbound.Manager
@@ -44,3 +45,8 @@ func (b *Balancer) PickOutbound() (string, error) {
 	}
 	return tag, nil
 }
+
+// Tag returns the tag of the balancing rule.
+func (b *Balancer) Tag() string {
+	return b.tag
+}
@@ -0,0 +1,254 @@
+// +build !confonly
+
+package command
+
+//go:generate errorgen
+
+import (
+	"context"
+
+	grpc "google.golang.org/grpc"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/routing"
+)
+
+// routingServer is an implementation of RoutingService.
+type routingServer struct {
+	router routing.Router
+}
+
+func NewRoutingServer(r routing.Router) RoutingServiceServer {
+	return &routingServer{
+		router: r,
+	}
+}
+
+func (s *routingServer) getRouter() (*router.Router, error) {
+	r, ok := s.router.(*router.Router)
+	if !ok {
+		return nil, newError("RoutingService only works with its own router.Router.")
+	}
+	return r, nil
+}
+
+// contextFromRoutingContext builds a session context for routing from the given RoutingContext. Network is TCP if not specified.
+func contextFromRoutingContext(rc *RoutingContext) context.Context {
+	ctx := context.Background()
+	if rc == nil {
+		return ctx
+	}
+
+	network := rc.Network
+	if network == net.Network_Unknown {
+		network = net.Network_TCP
+	}
+
+	inbound := &session.Inbound{
+		Tag: rc.InboundTag,
+	}
+	if len(rc.UserEmail) > 0 {
+		inbound.User = &protocol.MemoryUser{Email: rc.UserEmail}
+	}
+	if len(rc.SourceIp) > 0 {
+		inbound.Source = net.Destination{
+			Network: network,
+			Address: net.ParseAddress(rc.SourceIp),
+			Port:    net.Port(rc.SourcePort),
+		}
+	}
+	ctx = session.ContextWithInbound(ctx, inbound)
+
+	if len(rc.Target) > 0 {
+		ctx = session.ContextWithOutbound(ctx, &session.Outbound{
+			Target: net.Destination{
+				Network: network,
+				Address: net.ParseAddress(rc.Target),
+				Port:    net.Port(rc.TargetPort),
+			},
+		})
+	}
+
+	if len(rc.Protocol) > 0 || len(rc.Attributes) > 0 {
+		content := &session.Content{
+			Protocol: rc.Protocol,
+		}
+		for k, v := range rc.Attributes {
+			content.SetAttribute(k, v)
+		}
+		ctx = session.ContextWithContent(ctx, content)
+	}
+
+	return ctx
+}
+
+func (s *routingServer) TestRoute(ctx context.Context, request *TestRouteRequest) (*TestRouteResponse, error) {
+	r, err := s.getRouter()
+	if err != nil {
+		return nil, err
+	}
+
+	trace, err := r.TraceRoute(contextFromRoutingContext(request.RoutingContext))
+	if err != nil {
+		return nil, newError("failed to route").Base(err)
+	}
+
+	response := &TestRouteResponse{
+		Matched:     trace.Rule != nil,
+		RuleIndex:   int32(trace.Index),
+		OutboundTag: trace.OutboundTag,
+	}
+	if trace.Rule != nil {
+		response.RuleTag = trace.Rule.RuleTag
+		if trace.Rule.Balancer != nil {
+			response.BalancingTag = trace.Rule.Balancer.Tag()
+		}
+	}
+	for _, rule := range trace.Rules {
+		result := &RuleResult{
+			Index:   int32(rule.Index),
+			RuleTag: rule.RuleTag,
+			Matched: rule.Matched,
+		}
+		for _, cond := range rule.Conditions {
+			result.Condition = append(result.Condition, &ConditionResult{
+				Name:      cond.Name,
+				Evaluated: cond.Evaluated,
+				Matched:   cond.Matched,
+			})
+		}
+		response.Rule = append(response.Rule, result)
+	}
+	for _, ip := range trace.ResolvedIPs {
+		response.ResolvedIp = append(response.ResolvedIp, ip.String())
+	}
+
+	return response, nil
+}
+
+func (s *routingServer) SubscribeRoutingDecisions(request *SubscribeRoutingDecisionsRequest, stream RoutingService_SubscribeRoutingDecisionsServer) error {
+	r, err := s.getRouter()
+	if err != nil {
+		return err
+	}
+
+	sub := r.SubscribeDecisions()
+	defer sub.Close()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg := <-sub.Wait():
+			decision, ok := msg.(*router.Decision)
+			if !ok {
+				continue
+			}
+			if err := stream.Send(toRoutingDecision(decision)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *routingServer) AddRule(ctx context.Context, request *AddRuleRequest) (*AddRuleResponse, error) {
+	r, err := s.getRouter()
+	if err != nil {
+		return nil, err
+	}
+	if request.Rule == nil {
+		return nil, newError("empty rule")
+	}
+	if err := r.AddRule(request.Rule, int(request.Index)); err != nil {
+		return nil, newError("failed to add rule").Base(err)
+	}
+	return &AddRuleResponse{}, nil
+}
+
+func (s *routingServer) RemoveRule(ctx context.Context, request *RemoveRuleRequest) (*RemoveRuleResponse, error) {
+	r, err := s.getRouter()
+	if err != nil {
+		return nil, err
+	}
+	return &RemoveRuleResponse{}, r.RemoveRule(request.RuleTag)
+}
+
+func (s *routingServer) ReplaceRules(ctx context.Context, request *ReplaceRulesRequest) (*ReplaceRulesResponse, error) {
+	r, err := s.getRouter()
+	if err != nil {
+		return nil, err
+	}
+	if request.Config == nil {
+		return nil, newError("empty config")
+	}
+	if err := r.ReplaceRules(request.Config); err != nil {
+		return nil, newError("failed to replace rules").Base(err)
+	}
+	return &ReplaceRulesResponse{}, nil
+}
+
+func (s *routingServer) ReloadGeoData(ctx context.Context, request *ReloadGeoDataRequest) (*ReloadGeoDataResponse, error) {
+	r, err := s.getRouter()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ReloadGeoData(); err != nil {
+		return nil, newError("failed to reload geodata").Base(err)
+	}
+	return &ReloadGeoDataResponse{}, nil
+}
+
+func toRoutingDecision(d *router.Decision) *RoutingDecision {
+	rc := &RoutingContext{
+		InboundTag: d.InboundTag,
+		UserEmail:  d.Email,
+		Protocol:   d.Protocol,
+	}
+	if d.Source.IsValid() {
+		rc.Network = d.Source.Network
+		rc.SourceIp = d.Source.Address.String()
+		rc.SourcePort = uint32(d.Source.Port)
+	}
+	if d.Target.IsValid() {
+		rc.Network = d.Target.Network
+		rc.Target = d.Target.Address.String()
+		rc.TargetPort = uint32(d.Target.Port)
+	}
+
+	decision := &RoutingDecision{
+		RoutingContext: rc,
+		RuleIndex:      int32(d.Index),
+		RuleTag:        d.RuleTag,
+		OutboundTag:    d.OutboundTag,
+		Timestamp:      d.Time.Unix(),
+	}
+	if d.Err != nil {
+		decision.Error = d.Err.Error()
+	}
+	return decision
+}
+
+type service struct {
+	router routing.Router
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterRoutingServiceServer(server, NewRoutingServer(s.router))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(r routing.Router) {
+			s.router = r
+		})
+
+		return s, nil
+	}))
+}
@@ -0,0 +1,1184 @@
+package command
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+	router "v2ray.com/core/app/router"
+	net "v2ray.com/core/common/net"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// RoutingContext describes a connection to be routed.
+type RoutingContext struct {
+	InboundTag string `protobuf:"bytes,1,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	UserEmail  string `protobuf:"bytes,2,opt,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	// Network of the connection. TCP is used if not specified.
+	Network net.Network `protobuf:"varint,3,opt,name=network,proto3,enum=v2ray.core.common.net.Network" json:"network,omitempty"`
+	// Source IP address of the connection.
+	SourceIp   string `protobuf:"bytes,4,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	SourcePort uint32 `protobuf:"varint,5,opt,name=source_port,json=sourcePort,proto3" json:"source_port,omitempty"`
+	// Target domain or IP address of the connection.
+	Target     string `protobuf:"bytes,6,opt,name=target,proto3" json:"target,omitempty"`
+	TargetPort uint32 `protobuf:"varint,7,opt,name=target_port,json=targetPort,proto3" json:"target_port,omitempty"`
+	// Sniffed protocol, such as "http1" or "tls".
+	Protocol             string            `protobuf:"bytes,8,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	Attributes           map[string]string `protobuf:"bytes,9,rep,name=attributes,proto3" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *RoutingContext) Reset()         { *m = RoutingContext{} }
+func (m *RoutingContext) String() string { return proto.CompactTextString(m) }
+func (*RoutingContext) ProtoMessage()    {}
+func (*RoutingContext) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{0}
+}
+
+func (m *RoutingContext) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutingContext.Unmarshal(m, b)
+}
+func (m *RoutingContext) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutingContext.Marshal(b, m, deterministic)
+}
+func (m *RoutingContext) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutingContext.Merge(m, src)
+}
+func (m *RoutingContext) XXX_Size() int {
+	return xxx_messageInfo_RoutingContext.Size(m)
+}
+func (m *RoutingContext) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutingContext.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutingContext proto.InternalMessageInfo
+
+func (m *RoutingContext) GetInboundTag() string {
+	if m != nil {
+		return m.InboundTag
+	}
+	return ""
+}
+
+func (m *RoutingContext) GetUserEmail() string {
+	if m != nil {
+		return m.UserEmail
+	}
+	return ""
+}
+
+func (m *RoutingContext) GetNetwork() net.Network {
+	if m != nil {
+		return m.Network
+	}
+	return net.Network_Unknown
+}
+
+func (m *RoutingContext) GetSourceIp() string {
+	if m != nil {
+		return m.SourceIp
+	}
+	return ""
+}
+
+func (m *RoutingContext) GetSourcePort() uint32 {
+	if m != nil {
+		return m.SourcePort
+	}
+	return 0
+}
+
+func (m *RoutingContext) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *RoutingContext) GetTargetPort() uint32 {
+	if m != nil {
+		return m.TargetPort
+	}
+	return 0
+}
+
+func (m *RoutingContext) GetProtocol() string {
+	if m != nil {
+		return m.Protocol
+	}
+	return ""
+}
+
+func (m *RoutingContext) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+type ConditionResult struct {
+	// Name of the condition, same as the field name in JSON config, such as "domain" or "inboundTag".
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Matched bool   `protobuf:"varint,2,opt,name=matched,proto3" json:"matched,omitempty"`
+	// Whether the condition is evaluated. Conditions after the first unmatched one in a rule are not evaluated.
+	Evaluated            bool     `protobuf:"varint,3,opt,name=evaluated,proto3" json:"evaluated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConditionResult) Reset()         { *m = ConditionResult{} }
+func (m *ConditionResult) String() string { return proto.CompactTextString(m) }
+func (*ConditionResult) ProtoMessage()    {}
+func (*ConditionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{1}
+}
+
+func (m *ConditionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConditionResult.Unmarshal(m, b)
+}
+func (m *ConditionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConditionResult.Marshal(b, m, deterministic)
+}
+func (m *ConditionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConditionResult.Merge(m, src)
+}
+func (m *ConditionResult) XXX_Size() int {
+	return xxx_messageInfo_ConditionResult.Size(m)
+}
+func (m *ConditionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConditionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConditionResult proto.InternalMessageInfo
+
+func (m *ConditionResult) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ConditionResult) GetMatched() bool {
+	if m != nil {
+		return m.Matched
+	}
+	return false
+}
+
+func (m *ConditionResult) GetEvaluated() bool {
+	if m != nil {
+		return m.Evaluated
+	}
+	return false
+}
+
+type RuleResult struct {
+	Index                int32              `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	RuleTag              string             `protobuf:"bytes,2,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	Condition            []*ConditionResult `protobuf:"bytes,3,rep,name=condition,proto3" json:"condition,omitempty"`
+	Matched              bool               `protobuf:"varint,4,opt,name=matched,proto3" json:"matched,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RuleResult) Reset()         { *m = RuleResult{} }
+func (m *RuleResult) String() string { return proto.CompactTextString(m) }
+func (*RuleResult) ProtoMessage()    {}
+func (*RuleResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{2}
+}
+
+func (m *RuleResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RuleResult.Unmarshal(m, b)
+}
+func (m *RuleResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RuleResult.Marshal(b, m, deterministic)
+}
+func (m *RuleResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RuleResult.Merge(m, src)
+}
+func (m *RuleResult) XXX_Size() int {
+	return xxx_messageInfo_RuleResult.Size(m)
+}
+func (m *RuleResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_RuleResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RuleResult proto.InternalMessageInfo
+
+func (m *RuleResult) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *RuleResult) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+func (m *RuleResult) GetCondition() []*ConditionResult {
+	if m != nil {
+		return m.Condition
+	}
+	return nil
+}
+
+func (m *RuleResult) GetMatched() bool {
+	if m != nil {
+		return m.Matched
+	}
+	return false
+}
+
+type TestRouteRequest struct {
+	RoutingContext       *RoutingContext `protobuf:"bytes,1,opt,name=routing_context,json=routingContext,proto3" json:"routing_context,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *TestRouteRequest) Reset()         { *m = TestRouteRequest{} }
+func (m *TestRouteRequest) String() string { return proto.CompactTextString(m) }
+func (*TestRouteRequest) ProtoMessage()    {}
+func (*TestRouteRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{3}
+}
+
+func (m *TestRouteRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestRouteRequest.Unmarshal(m, b)
+}
+func (m *TestRouteRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestRouteRequest.Marshal(b, m, deterministic)
+}
+func (m *TestRouteRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestRouteRequest.Merge(m, src)
+}
+func (m *TestRouteRequest) XXX_Size() int {
+	return xxx_messageInfo_TestRouteRequest.Size(m)
+}
+func (m *TestRouteRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestRouteRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestRouteRequest proto.InternalMessageInfo
+
+func (m *TestRouteRequest) GetRoutingContext() *RoutingContext {
+	if m != nil {
+		return m.RoutingContext
+	}
+	return nil
+}
+
+type TestRouteResponse struct {
+	Matched bool `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	// Index of the matched rule, or -1 if no rule matches.
+	RuleIndex int32  `protobuf:"varint,2,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	RuleTag   string `protobuf:"bytes,3,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	// Tag of the balancer of the matched rule, if any.
+	BalancingTag string `protobuf:"bytes,4,opt,name=balancing_tag,json=balancingTag,proto3" json:"balancing_tag,omitempty"`
+	// Tag of the outbound picked by the matched rule or its balancer.
+	OutboundTag string `protobuf:"bytes,5,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	// Rules evaluated in order. Rules may be evaluated twice when domain strategy is IpIfNonMatch.
+	Rule []*RuleResult `protobuf:"bytes,6,rep,name=rule,proto3" json:"rule,omitempty"`
+	// IPs of target domain resolved during routing.
+	ResolvedIp           []string `protobuf:"bytes,7,rep,name=resolved_ip,json=resolvedIp,proto3" json:"resolved_ip,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TestRouteResponse) Reset()         { *m = TestRouteResponse{} }
+func (m *TestRouteResponse) String() string { return proto.CompactTextString(m) }
+func (*TestRouteResponse) ProtoMessage()    {}
+func (*TestRouteResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{4}
+}
+
+func (m *TestRouteResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TestRouteResponse.Unmarshal(m, b)
+}
+func (m *TestRouteResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TestRouteResponse.Marshal(b, m, deterministic)
+}
+func (m *TestRouteResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TestRouteResponse.Merge(m, src)
+}
+func (m *TestRouteResponse) XXX_Size() int {
+	return xxx_messageInfo_TestRouteResponse.Size(m)
+}
+func (m *TestRouteResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TestRouteResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TestRouteResponse proto.InternalMessageInfo
+
+func (m *TestRouteResponse) GetMatched() bool {
+	if m != nil {
+		return m.Matched
+	}
+	return false
+}
+
+func (m *TestRouteResponse) GetRuleIndex() int32 {
+	if m != nil {
+		return m.RuleIndex
+	}
+	return 0
+}
+
+func (m *TestRouteResponse) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+func (m *TestRouteResponse) GetBalancingTag() string {
+	if m != nil {
+		return m.BalancingTag
+	}
+	return ""
+}
+
+func (m *TestRouteResponse) GetOutboundTag() string {
+	if m != nil {
+		return m.OutboundTag
+	}
+	return ""
+}
+
+func (m *TestRouteResponse) GetRule() []*RuleResult {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *TestRouteResponse) GetResolvedIp() []string {
+	if m != nil {
+		return m.ResolvedIp
+	}
+	return nil
+}
+
+type SubscribeRoutingDecisionsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRoutingDecisionsRequest) Reset()         { *m = SubscribeRoutingDecisionsRequest{} }
+func (m *SubscribeRoutingDecisionsRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRoutingDecisionsRequest) ProtoMessage()    {}
+func (*SubscribeRoutingDecisionsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{5}
+}
+
+func (m *SubscribeRoutingDecisionsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRoutingDecisionsRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRoutingDecisionsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRoutingDecisionsRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRoutingDecisionsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRoutingDecisionsRequest.Merge(m, src)
+}
+func (m *SubscribeRoutingDecisionsRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRoutingDecisionsRequest.Size(m)
+}
+func (m *SubscribeRoutingDecisionsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRoutingDecisionsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRoutingDecisionsRequest proto.InternalMessageInfo
+
+type RoutingDecision struct {
+	RoutingContext *RoutingContext `protobuf:"bytes,1,opt,name=routing_context,json=routingContext,proto3" json:"routing_context,omitempty"`
+	RuleIndex      int32           `protobuf:"varint,2,opt,name=rule_index,json=ruleIndex,proto3" json:"rule_index,omitempty"`
+	RuleTag        string          `protobuf:"bytes,3,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	OutboundTag    string          `protobuf:"bytes,4,opt,name=outbound_tag,json=outboundTag,proto3" json:"outbound_tag,omitempty"`
+	Error          string          `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// Unix time in seconds.
+	Timestamp            int64    `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoutingDecision) Reset()         { *m = RoutingDecision{} }
+func (m *RoutingDecision) String() string { return proto.CompactTextString(m) }
+func (*RoutingDecision) ProtoMessage()    {}
+func (*RoutingDecision) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{6}
+}
+
+func (m *RoutingDecision) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutingDecision.Unmarshal(m, b)
+}
+func (m *RoutingDecision) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutingDecision.Marshal(b, m, deterministic)
+}
+func (m *RoutingDecision) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutingDecision.Merge(m, src)
+}
+func (m *RoutingDecision) XXX_Size() int {
+	return xxx_messageInfo_RoutingDecision.Size(m)
+}
+func (m *RoutingDecision) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutingDecision.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutingDecision proto.InternalMessageInfo
+
+func (m *RoutingDecision) GetRoutingContext() *RoutingContext {
+	if m != nil {
+		return m.RoutingContext
+	}
+	return nil
+}
+
+func (m *RoutingDecision) GetRuleIndex() int32 {
+	if m != nil {
+		return m.RuleIndex
+	}
+	return 0
+}
+
+func (m *RoutingDecision) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+func (m *RoutingDecision) GetOutboundTag() string {
+	if m != nil {
+		return m.OutboundTag
+	}
+	return ""
+}
+
+func (m *RoutingDecision) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *RoutingDecision) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type AddRuleRequest struct {
+	Rule *router.RoutingRule `protobuf:"bytes,1,opt,name=rule,proto3" json:"rule,omitempty"`
+	// Position of the new rule. The rule is appended to the end if it is negative or out of range.
+	Index                int32    `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRuleRequest) Reset()         { *m = AddRuleRequest{} }
+func (m *AddRuleRequest) String() string { return proto.CompactTextString(m) }
+func (*AddRuleRequest) ProtoMessage()    {}
+func (*AddRuleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{7}
+}
+
+func (m *AddRuleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRuleRequest.Unmarshal(m, b)
+}
+func (m *AddRuleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRuleRequest.Marshal(b, m, deterministic)
+}
+func (m *AddRuleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRuleRequest.Merge(m, src)
+}
+func (m *AddRuleRequest) XXX_Size() int {
+	return xxx_messageInfo_AddRuleRequest.Size(m)
+}
+func (m *AddRuleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRuleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRuleRequest proto.InternalMessageInfo
+
+func (m *AddRuleRequest) GetRule() *router.RoutingRule {
+	if m != nil {
+		return m.Rule
+	}
+	return nil
+}
+
+func (m *AddRuleRequest) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+type AddRuleResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddRuleResponse) Reset()         { *m = AddRuleResponse{} }
+func (m *AddRuleResponse) String() string { return proto.CompactTextString(m) }
+func (*AddRuleResponse) ProtoMessage()    {}
+func (*AddRuleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{8}
+}
+
+func (m *AddRuleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddRuleResponse.Unmarshal(m, b)
+}
+func (m *AddRuleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddRuleResponse.Marshal(b, m, deterministic)
+}
+func (m *AddRuleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddRuleResponse.Merge(m, src)
+}
+func (m *AddRuleResponse) XXX_Size() int {
+	return xxx_messageInfo_AddRuleResponse.Size(m)
+}
+func (m *AddRuleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddRuleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddRuleResponse proto.InternalMessageInfo
+
+type RemoveRuleRequest struct {
+	// All rules with this rule tag are removed.
+	RuleTag              string   `protobuf:"bytes,1,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRuleRequest) Reset()         { *m = RemoveRuleRequest{} }
+func (m *RemoveRuleRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRuleRequest) ProtoMessage()    {}
+func (*RemoveRuleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{9}
+}
+
+func (m *RemoveRuleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRuleRequest.Unmarshal(m, b)
+}
+func (m *RemoveRuleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRuleRequest.Marshal(b, m, deterministic)
+}
+func (m *RemoveRuleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRuleRequest.Merge(m, src)
+}
+func (m *RemoveRuleRequest) XXX_Size() int {
+	return xxx_messageInfo_RemoveRuleRequest.Size(m)
+}
+func (m *RemoveRuleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRuleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRuleRequest proto.InternalMessageInfo
+
+func (m *RemoveRuleRequest) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+type RemoveRuleResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RemoveRuleResponse) Reset()         { *m = RemoveRuleResponse{} }
+func (m *RemoveRuleResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveRuleResponse) ProtoMessage()    {}
+func (*RemoveRuleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{10}
+}
+
+func (m *RemoveRuleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RemoveRuleResponse.Unmarshal(m, b)
+}
+func (m *RemoveRuleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RemoveRuleResponse.Marshal(b, m, deterministic)
+}
+func (m *RemoveRuleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RemoveRuleResponse.Merge(m, src)
+}
+func (m *RemoveRuleResponse) XXX_Size() int {
+	return xxx_messageInfo_RemoveRuleResponse.Size(m)
+}
+func (m *RemoveRuleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RemoveRuleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RemoveRuleResponse proto.InternalMessageInfo
+
+type ReplaceRulesRequest struct {
+	// Domain strategy, rules and balancers in this config replace the current ones.
+	Config               *router.Config `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ReplaceRulesRequest) Reset()         { *m = ReplaceRulesRequest{} }
+func (m *ReplaceRulesRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplaceRulesRequest) ProtoMessage()    {}
+func (*ReplaceRulesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{11}
+}
+
+func (m *ReplaceRulesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceRulesRequest.Unmarshal(m, b)
+}
+func (m *ReplaceRulesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceRulesRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplaceRulesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceRulesRequest.Merge(m, src)
+}
+func (m *ReplaceRulesRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplaceRulesRequest.Size(m)
+}
+func (m *ReplaceRulesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceRulesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceRulesRequest proto.InternalMessageInfo
+
+func (m *ReplaceRulesRequest) GetConfig() *router.Config {
+	if m != nil {
+		return m.Config
+	}
+	return nil
+}
+
+type ReplaceRulesResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplaceRulesResponse) Reset()         { *m = ReplaceRulesResponse{} }
+func (m *ReplaceRulesResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplaceRulesResponse) ProtoMessage()    {}
+func (*ReplaceRulesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{12}
+}
+
+func (m *ReplaceRulesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplaceRulesResponse.Unmarshal(m, b)
+}
+func (m *ReplaceRulesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplaceRulesResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplaceRulesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplaceRulesResponse.Merge(m, src)
+}
+func (m *ReplaceRulesResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplaceRulesResponse.Size(m)
+}
+func (m *ReplaceRulesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplaceRulesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplaceRulesResponse proto.InternalMessageInfo
+
+type ReloadGeoDataRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReloadGeoDataRequest) Reset()         { *m = ReloadGeoDataRequest{} }
+func (m *ReloadGeoDataRequest) String() string { return proto.CompactTextString(m) }
+func (*ReloadGeoDataRequest) ProtoMessage()    {}
+func (*ReloadGeoDataRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{13}
+}
+
+func (m *ReloadGeoDataRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReloadGeoDataRequest.Unmarshal(m, b)
+}
+func (m *ReloadGeoDataRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReloadGeoDataRequest.Marshal(b, m, deterministic)
+}
+func (m *ReloadGeoDataRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadGeoDataRequest.Merge(m, src)
+}
+func (m *ReloadGeoDataRequest) XXX_Size() int {
+	return xxx_messageInfo_ReloadGeoDataRequest.Size(m)
+}
+func (m *ReloadGeoDataRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadGeoDataRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReloadGeoDataRequest proto.InternalMessageInfo
+
+type ReloadGeoDataResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReloadGeoDataResponse) Reset()         { *m = ReloadGeoDataResponse{} }
+func (m *ReloadGeoDataResponse) String() string { return proto.CompactTextString(m) }
+func (*ReloadGeoDataResponse) ProtoMessage()    {}
+func (*ReloadGeoDataResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{14}
+}
+
+func (m *ReloadGeoDataResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReloadGeoDataResponse.Unmarshal(m, b)
+}
+func (m *ReloadGeoDataResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReloadGeoDataResponse.Marshal(b, m, deterministic)
+}
+func (m *ReloadGeoDataResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReloadGeoDataResponse.Merge(m, src)
+}
+func (m *ReloadGeoDataResponse) XXX_Size() int {
+	return xxx_messageInfo_ReloadGeoDataResponse.Size(m)
+}
+func (m *ReloadGeoDataResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReloadGeoDataResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReloadGeoDataResponse proto.InternalMessageInfo
+
+type Config struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Config) Reset()         { *m = Config{} }
+func (m *Config) String() string { return proto.CompactTextString(m) }
+func (*Config) ProtoMessage()    {}
+func (*Config) Descriptor() ([]byte, []int) {
+	return fileDescriptor_59607e80b1106a93, []int{15}
+}
+
+func (m *Config) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Config.Unmarshal(m, b)
+}
+func (m *Config) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Config.Marshal(b, m, deterministic)
+}
+func (m *Config) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Config.Merge(m, src)
+}
+func (m *Config) XXX_Size() int {
+	return xxx_messageInfo_Config.Size(m)
+}
+func (m *Config) XXX_DiscardUnknown() {
+	xxx_messageInfo_Config.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Config proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*RoutingContext)(nil), "v2ray.core.app.router.command.RoutingContext")
+	proto.RegisterMapType((map[string]string)(nil), "v2ray.core.app.router.command.RoutingContext.AttributesEntry")
+	proto.RegisterType((*ConditionResult)(nil), "v2ray.core.app.router.command.ConditionResult")
+	proto.RegisterType((*RuleResult)(nil), "v2ray.core.app.router.command.RuleResult")
+	proto.RegisterType((*TestRouteRequest)(nil), "v2ray.core.app.router.command.TestRouteRequest")
+	proto.RegisterType((*TestRouteResponse)(nil), "v2ray.core.app.router.command.TestRouteResponse")
+	proto.RegisterType((*SubscribeRoutingDecisionsRequest)(nil), "v2ray.core.app.router.command.SubscribeRoutingDecisionsRequest")
+	proto.RegisterType((*RoutingDecision)(nil), "v2ray.core.app.router.command.RoutingDecision")
+	proto.RegisterType((*AddRuleRequest)(nil), "v2ray.core.app.router.command.AddRuleRequest")
+	proto.RegisterType((*AddRuleResponse)(nil), "v2ray.core.app.router.command.AddRuleResponse")
+	proto.RegisterType((*RemoveRuleRequest)(nil), "v2ray.core.app.router.command.RemoveRuleRequest")
+	proto.RegisterType((*RemoveRuleResponse)(nil), "v2ray.core.app.router.command.RemoveRuleResponse")
+	proto.RegisterType((*ReplaceRulesRequest)(nil), "v2ray.core.app.router.command.ReplaceRulesRequest")
+	proto.RegisterType((*ReplaceRulesResponse)(nil), "v2ray.core.app.router.command.ReplaceRulesResponse")
+	proto.RegisterType((*ReloadGeoDataRequest)(nil), "v2ray.core.app.router.command.ReloadGeoDataRequest")
+	proto.RegisterType((*ReloadGeoDataResponse)(nil), "v2ray.core.app.router.command.ReloadGeoDataResponse")
+	proto.RegisterType((*Config)(nil), "v2ray.core.app.router.command.Config")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/router/command/command.proto", fileDescriptor_59607e80b1106a93)
+}
+
+var fileDescriptor_59607e80b1106a93 = []byte{
+	// 949 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5d, 0x8f, 0xdb, 0x44,
+	0x17, 0xae, 0xf3, 0x9d, 0xb3, 0x1f, 0xe9, 0xce, 0x9b, 0xb7, 0xb8, 0x86, 0x40, 0x6a, 0x24, 0x58,
+	0x2e, 0x70, 0x4a, 0x0a, 0xa8, 0x42, 0x5a, 0xa1, 0x65, 0x5b, 0xa1, 0x95, 0xaa, 0xaa, 0x9a, 0x56,
+	0xbd, 0x40, 0x2a, 0xab, 0x89, 0x3d, 0x04, 0xb7, 0xb6, 0xc7, 0x8c, 0xc7, 0xa1, 0x91, 0xf8, 0x0f,
+	0xf0, 0x27, 0xb8, 0xe1, 0x2f, 0xf0, 0xa3, 0xb8, 0xe7, 0x0a, 0xcd, 0x87, 0x1d, 0x3b, 0xd1, 0xae,
+	0x77, 0x85, 0xb8, 0x58, 0xad, 0xe7, 0xcc, 0x79, 0x9e, 0x73, 0xce, 0x73, 0xce, 0xb1, 0x03, 0xb3,
+	0xd5, 0x9c, 0x93, 0xb5, 0xe7, 0xb3, 0x78, 0xe6, 0x33, 0x4e, 0x67, 0x24, 0x4d, 0x67, 0x9c, 0xe5,
+	0x82, 0xf2, 0x99, 0xcf, 0xe2, 0x98, 0x24, 0x41, 0xf1, 0xdf, 0x4b, 0x39, 0x13, 0x0c, 0x4d, 0x0a,
+	0x00, 0xa7, 0x1e, 0x49, 0x53, 0x4f, 0x3b, 0x7b, 0xc6, 0xc9, 0xf9, 0x78, 0x8b, 0x4f, 0xda, 0x59,
+	0x32, 0x4b, 0xa8, 0x90, 0x7f, 0x3f, 0x33, 0xfe, 0x46, 0xf3, 0x38, 0x1f, 0x5d, 0x15, 0x38, 0xf9,
+	0x21, 0x5c, 0x6a, 0x3f, 0xf7, 0xcf, 0x36, 0x1c, 0x62, 0x96, 0x8b, 0x30, 0x59, 0x9e, 0xb1, 0x44,
+	0xd0, 0xb7, 0x02, 0x7d, 0x00, 0x7b, 0x61, 0xb2, 0x60, 0x79, 0x12, 0x5c, 0x08, 0xb2, 0xb4, 0xad,
+	0xa9, 0x75, 0x3c, 0xc4, 0x60, 0x4c, 0x2f, 0xc8, 0x12, 0x4d, 0x00, 0xf2, 0x8c, 0xf2, 0x0b, 0x1a,
+	0x93, 0x30, 0xb2, 0x5b, 0xea, 0x7e, 0x28, 0x2d, 0x8f, 0xa5, 0x01, 0x3d, 0x84, 0xbe, 0xc9, 0xc5,
+	0x6e, 0x4f, 0xad, 0xe3, 0xc3, 0xf9, 0xfb, 0x5e, 0xa5, 0x28, 0x9d, 0xb1, 0x97, 0x50, 0xe1, 0x3d,
+	0xd5, 0x5e, 0xb8, 0x70, 0x47, 0xef, 0xc2, 0x30, 0x63, 0x39, 0xf7, 0xe9, 0x45, 0x98, 0xda, 0x1d,
+	0xc5, 0x3b, 0xd0, 0x86, 0xf3, 0x54, 0xa6, 0x65, 0x2e, 0x53, 0xc6, 0x85, 0xdd, 0x9d, 0x5a, 0xc7,
+	0x07, 0x18, 0xb4, 0xe9, 0x19, 0xe3, 0x02, 0xdd, 0x81, 0x9e, 0x20, 0x7c, 0x49, 0x85, 0xdd, 0x53,
+	0x50, 0x73, 0x92, 0x40, 0xfd, 0xa4, 0x81, 0x7d, 0x0d, 0xd4, 0x26, 0x05, 0x74, 0x60, 0xa0, 0xc4,
+	0xf0, 0x59, 0x64, 0x0f, 0x74, 0xd4, 0xe2, 0x8c, 0x5e, 0x01, 0x10, 0x21, 0x78, 0xb8, 0xc8, 0x05,
+	0xcd, 0xec, 0xe1, 0xb4, 0x7d, 0xbc, 0x37, 0x3f, 0xf1, 0xae, 0x6c, 0x92, 0x57, 0xd7, 0xd3, 0x3b,
+	0x2d, 0xf1, 0x8f, 0x13, 0xc1, 0xd7, 0xb8, 0x42, 0xe8, 0x9c, 0xc0, 0x68, 0xeb, 0x1a, 0xdd, 0x86,
+	0xf6, 0x1b, 0xba, 0x36, 0xb2, 0xcb, 0x47, 0x34, 0x86, 0xee, 0x8a, 0x44, 0x39, 0x35, 0x52, 0xeb,
+	0xc3, 0x57, 0xad, 0x87, 0x96, 0xfb, 0x0a, 0x46, 0x67, 0x2c, 0x09, 0x42, 0x11, 0xb2, 0x04, 0xd3,
+	0x2c, 0x8f, 0x04, 0x42, 0xd0, 0x49, 0x48, 0x4c, 0x0d, 0x5e, 0x3d, 0x23, 0x1b, 0xfa, 0x31, 0x11,
+	0xfe, 0x8f, 0x34, 0x50, 0x14, 0x03, 0x5c, 0x1c, 0xd1, 0x7b, 0x30, 0xa4, 0x92, 0x8e, 0x08, 0x1a,
+	0xa8, 0x6e, 0x0d, 0xf0, 0xc6, 0xe0, 0xfe, 0x6e, 0x01, 0xe0, 0x3c, 0xa2, 0x86, 0x7a, 0x0c, 0xdd,
+	0x30, 0x09, 0xe8, 0x5b, 0xc5, 0xdd, 0xc5, 0xfa, 0x80, 0xee, 0xc2, 0x80, 0xe7, 0x11, 0x55, 0xb3,
+	0xa2, 0x13, 0xec, 0xcb, 0xb3, 0x1c, 0x94, 0x27, 0x30, 0xf4, 0x8b, 0xf4, 0xec, 0xb6, 0xd2, 0xce,
+	0x6b, 0xd0, 0x6e, 0xab, 0x1c, 0xbc, 0x21, 0xa8, 0x56, 0xd1, 0xa9, 0x55, 0xe1, 0xbe, 0x86, 0xdb,
+	0x2f, 0x68, 0x26, 0xa4, 0xee, 0x14, 0xd3, 0x9f, 0x72, 0x9a, 0x09, 0xf4, 0x12, 0x46, 0x5c, 0xf7,
+	0xe1, 0xc2, 0xd7, 0x8d, 0x50, 0x69, 0xef, 0xcd, 0x3f, 0xbd, 0x51, 0xf7, 0xf0, 0x21, 0xaf, 0x9d,
+	0xdd, 0x5f, 0x5b, 0x70, 0x54, 0x09, 0x96, 0xa5, 0x2c, 0xc9, 0x6a, 0x0a, 0x5b, 0x75, 0x85, 0x27,
+	0x00, 0x4a, 0x1e, 0xad, 0x5c, 0x4b, 0x29, 0x37, 0x94, 0x96, 0xf3, 0x1d, 0xf5, 0xda, 0x75, 0xf5,
+	0x3e, 0x84, 0x83, 0x05, 0x89, 0x48, 0xe2, 0xcb, 0x1a, 0xe4, 0xbd, 0xde, 0x88, 0xfd, 0xd2, 0x28,
+	0x9d, 0xee, 0xc1, 0x3e, 0xcb, 0xc5, 0x66, 0x5b, 0xbb, 0xca, 0x67, 0xaf, 0xb0, 0x49, 0x97, 0x13,
+	0xe8, 0x48, 0x4a, 0xbb, 0xa7, 0x1a, 0xf0, 0x49, 0x53, 0xf9, 0x65, 0xbf, 0xb1, 0x82, 0xc9, 0xf5,
+	0xe1, 0x34, 0x63, 0xd1, 0x8a, 0x06, 0x72, 0x2d, 0xfb, 0xd3, 0xb6, 0x7c, 0x1d, 0x14, 0xa6, 0xf3,
+	0xd4, 0x75, 0x61, 0xfa, 0x3c, 0x5f, 0x64, 0x3e, 0x0f, 0x17, 0xd4, 0x88, 0xf7, 0x88, 0xfa, 0x61,
+	0x16, 0xb2, 0x24, 0x33, 0xdd, 0x70, 0xff, 0xb6, 0x60, 0xb4, 0x75, 0xf7, 0x5f, 0x75, 0xe8, 0x5f,
+	0x28, 0xbe, 0x2d, 0x66, 0x67, 0x57, 0xcc, 0x31, 0x74, 0x29, 0xe7, 0x8c, 0x1b, 0xa1, 0xf5, 0x41,
+	0xae, 0x91, 0x08, 0x63, 0x9a, 0x09, 0x12, 0xa7, 0xea, 0xed, 0xd3, 0xc6, 0x1b, 0x83, 0xfb, 0x3d,
+	0x1c, 0x9e, 0x06, 0x81, 0x16, 0x56, 0x0f, 0xe7, 0x97, 0xa6, 0x25, 0xba, 0x5e, 0xf7, 0x92, 0x7a,
+	0x4d, 0x9d, 0x0a, 0xa8, 0x7b, 0x51, 0x6e, 0x60, 0xab, 0xb2, 0x81, 0xee, 0x11, 0x8c, 0x4a, 0x7e,
+	0x3d, 0x8f, 0xae, 0x07, 0x47, 0x98, 0xc6, 0x6c, 0x45, 0xab, 0x51, 0xab, 0x95, 0x5b, 0xb5, 0xca,
+	0xdd, 0x31, 0xa0, 0xaa, 0xbf, 0x61, 0x79, 0x02, 0xff, 0xc3, 0x34, 0x8d, 0x88, 0xaf, 0xcc, 0x45,
+	0x33, 0xd1, 0x17, 0xd0, 0xd3, 0xdf, 0x10, 0x93, 0xff, 0xe4, 0x92, 0xfc, 0xcf, 0x94, 0x13, 0x36,
+	0xce, 0xee, 0x1d, 0x18, 0xd7, 0xd9, 0x4c, 0x14, 0x65, 0x8f, 0x18, 0x09, 0xbe, 0xa5, 0xec, 0x11,
+	0x11, 0xa4, 0x98, 0x99, 0x77, 0xe0, 0xff, 0x5b, 0x76, 0x03, 0x18, 0x40, 0x4f, 0x53, 0xcf, 0xff,
+	0xea, 0x96, 0x5f, 0xaf, 0xe7, 0x94, 0xaf, 0x42, 0x9f, 0xa2, 0x14, 0x86, 0xe5, 0x7a, 0xa2, 0x59,
+	0xc3, 0x24, 0x6d, 0xbf, 0x35, 0x9c, 0xfb, 0xd7, 0x07, 0x98, 0x64, 0x6e, 0xa1, 0xdf, 0x2c, 0xb8,
+	0x7b, 0xe9, 0x02, 0xa0, 0xaf, 0x1b, 0x18, 0x9b, 0x56, 0xc7, 0xf1, 0xae, 0xb7, 0x0d, 0x05, 0xce,
+	0xbd, 0x75, 0xdf, 0x42, 0xaf, 0xa1, 0x6f, 0x26, 0x02, 0x35, 0x2d, 0x53, 0x7d, 0x32, 0x1d, 0xef,
+	0xba, 0xee, 0x65, 0xf9, 0x19, 0xc0, 0x66, 0x74, 0x50, 0x93, 0x80, 0x3b, 0x53, 0xe9, 0x7c, 0x76,
+	0x03, 0x44, 0x19, 0x74, 0x0d, 0xfb, 0xd5, 0x59, 0x42, 0xf3, 0x46, 0x92, 0x9d, 0x31, 0x76, 0x1e,
+	0xdc, 0x08, 0x53, 0x86, 0xfe, 0x05, 0x0e, 0x6a, 0x63, 0x89, 0x9a, 0x79, 0x76, 0x87, 0xdb, 0xf9,
+	0xfc, 0x66, 0xa0, 0x22, 0xfa, 0x37, 0x4f, 0xe1, 0x9e, 0xcf, 0xe2, 0xab, 0xc1, 0xcf, 0xac, 0xef,
+	0xfa, 0xe6, 0xf1, 0x8f, 0xd6, 0xe4, 0xe5, 0x1c, 0x93, 0xb5, 0x77, 0x26, 0x5d, 0x4f, 0xd3, 0xd4,
+	0xc3, 0xc5, 0x6e, 0xaa, 0xfb, 0x45, 0x4f, 0xfd, 0xd2, 0x79, 0xf0, 0xcf, 0x00, 0xc0, 0x4e, 0x98,
+	0x8a, 0xa9, 0x0a, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// RoutingServiceClient is the client API for RoutingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type RoutingServiceClient interface {
+	TestRoute(ctx context.Context, in *TestRouteRequest, opts ...grpc.CallOption) (*TestRouteResponse, error)
+	SubscribeRoutingDecisions(ctx context.Context, in *SubscribeRoutingDecisionsRequest, opts ...grpc.CallOption) (RoutingService_SubscribeRoutingDecisionsClient, error)
+	AddRule(ctx context.Context, in *AddRuleRequest, opts ...grpc.CallOption) (*AddRuleResponse, error)
+	RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*RemoveRuleResponse, error)
+	ReplaceRules(ctx context.Context, in *ReplaceRulesRequest, opts ...grpc.CallOption) (*ReplaceRulesResponse, error)
+	ReloadGeoData(ctx context.Context, in *ReloadGeoDataRequest, opts ...grpc.CallOption) (*ReloadGeoDataResponse, error)
+}
+
+type routingServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewRoutingServiceClient(cc *grpc.ClientConn) RoutingServiceClient {
+	return &routingServiceClient{cc}
+}
+
+func (c *routingServiceClient) TestRoute(ctx context.Context, in *TestRouteRequest, opts ...grpc.CallOption) (*TestRouteResponse, error) {
+	out := new(TestRouteResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.router.command.RoutingService/TestRoute", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) SubscribeRoutingDecisions(ctx context.Context, in *SubscribeRoutingDecisionsRequest, opts ...grpc.CallOption) (RoutingService_SubscribeRoutingDecisionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_RoutingService_serviceDesc.Streams[0], "/v2ray.core.app.router.command.RoutingService/SubscribeRoutingDecisions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routingServiceSubscribeRoutingDecisionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type RoutingService_SubscribeRoutingDecisionsClient interface {
+	Recv() (*RoutingDecision, error)
+	grpc.ClientStream
+}
+
+type routingServiceSubscribeRoutingDecisionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *routingServiceSubscribeRoutingDecisionsClient) Recv() (*RoutingDecision, error) {
+	m := new(RoutingDecision)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routingServiceClient) AddRule(ctx context.Context, in *AddRuleRequest, opts ...grpc.CallOption) (*AddRuleResponse, error) {
+	out := new(AddRuleResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.router.command.RoutingService/AddRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) RemoveRule(ctx context.Context, in *RemoveRuleRequest, opts ...grpc.CallOption) (*RemoveRuleResponse, error) {
+	out := new(RemoveRuleResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.router.command.RoutingService/RemoveRule", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) ReplaceRules(ctx context.Context, in *ReplaceRulesRequest, opts ...grpc.CallOption) (*ReplaceRulesResponse, error) {
+	out := new(ReplaceRulesResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.router.command.RoutingService/ReplaceRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routingServiceClient) ReloadGeoData(ctx context.Context, in *ReloadGeoDataRequest, opts ...grpc.CallOption) (*ReloadGeoDataResponse, error) {
+	out := new(ReloadGeoDataResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.router.command.RoutingService/ReloadGeoData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RoutingServiceServer is the server API for RoutingService service.
+type RoutingServiceServer interface {
+	TestRoute(context.Context, *TestRouteRequest) (*TestRouteResponse, error)
+	SubscribeRoutingDecisions(*SubscribeRoutingDecisionsRequest, RoutingService_SubscribeRoutingDecisionsServer) error
+	AddRule(context.Context, *AddRuleRequest) (*AddRuleResponse, error)
+	RemoveRule(context.Context, *RemoveRuleRequest) (*RemoveRuleResponse, error)
+	ReplaceRules(context.Context, *ReplaceRulesRequest) (*ReplaceRulesResponse, error)
+	ReloadGeoData(context.Context, *ReloadGeoDataRequest) (*ReloadGeoDataResponse, error)
+}
+
+// UnimplementedRoutingServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedRoutingServiceServer struct {
+}
+
+func (*UnimplementedRoutingServiceServer) TestRoute(ctx context.Context, req *TestRouteRequest) (*TestRouteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TestRoute not implemented")
+}
+func (*UnimplementedRoutingServiceServer) SubscribeRoutingDecisions(req *SubscribeRoutingDecisionsRequest, srv RoutingService_SubscribeRoutingDecisionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeRoutingDecisions not implemented")
+}
+func (*UnimplementedRoutingServiceServer) AddRule(ctx context.Context, req *AddRuleRequest) (*AddRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRule not implemented")
+}
+func (*UnimplementedRoutingServiceServer) RemoveRule(ctx context.Context, req *RemoveRuleRequest) (*RemoveRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveRule not implemented")
+}
+func (*UnimplementedRoutingServiceServer) ReplaceRules(ctx context.Context, req *ReplaceRulesRequest) (*ReplaceRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceRules not implemented")
+}
+func (*UnimplementedRoutingServiceServer) ReloadGeoData(ctx context.Context, req *ReloadGeoDataRequest) (*ReloadGeoDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReloadGeoData not implemented")
+}
+
+func RegisterRoutingServiceServer(s *grpc.Server, srv RoutingServiceServer) {
+	s.RegisterService(&_RoutingService_serviceDesc, srv)
+}
+
+func _RoutingService_TestRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TestRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).TestRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.router.command.RoutingService/TestRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).TestRoute(ctx, req.(*TestRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_SubscribeRoutingDecisions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRoutingDecisionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RoutingServiceServer).SubscribeRoutingDecisions(m, &routingServiceSubscribeRoutingDecisionsServer{stream})
+}
+
+type RoutingService_SubscribeRoutingDecisionsServer interface {
+	Send(*RoutingDecision) error
+	grpc.ServerStream
+}
+
+type routingServiceSubscribeRoutingDecisionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *routingServiceSubscribeRoutingDecisionsServer) Send(m *RoutingDecision) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _RoutingService_AddRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).AddRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.router.command.RoutingService/AddRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).AddRule(ctx, req.(*AddRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_RemoveRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).RemoveRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.router.command.RoutingService/RemoveRule",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).RemoveRule(ctx, req.(*RemoveRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_ReplaceRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).ReplaceRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.router.command.RoutingService/ReplaceRules",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).ReplaceRules(ctx, req.(*ReplaceRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoutingService_ReloadGeoData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadGeoDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoutingServiceServer).ReloadGeoData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.router.command.RoutingService/ReloadGeoData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoutingServiceServer).ReloadGeoData(ctx, req.(*ReloadGeoDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _RoutingService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "v2ray.core.app.router.command.RoutingService",
+	HandlerType: (*RoutingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "TestRoute",
+			Handler:    _RoutingService_TestRoute_Handler,
+		},
+		{
+			MethodName: "AddRule",
+			Handler:    _RoutingService_AddRule_Handler,
+		},
+		{
+			MethodName: "RemoveRule",
+			Handler:    _RoutingService_RemoveRule_Handler,
+		},
+		{
+			MethodName: "ReplaceRules",
+			Handler:    _RoutingService_ReplaceRules_Handler,
+		},
+		{
+			MethodName: "ReloadGeoData",
+			Handler:    _RoutingService_ReloadGeoData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeRoutingDecisions",
+			Handler:       _RoutingService_SubscribeRoutingDecisions_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v2ray.com/core/app/router/command/command.proto",
+}
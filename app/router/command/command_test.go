@@ -0,0 +1,218 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app/router"
+	. "v2ray.com/core/app/router/command"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+)
+
+func newRouter() *router.Router {
+	config := &router.Config{
+		Rule: []*router.RoutingRule{
+			{
+				TargetTag: &router.RoutingRule_Tag{
+					Tag: "blocked",
+				},
+				RuleTag: "block-ads",
+				Domain: []*router.Domain{
+					{
+						Type:  router.Domain_Domain,
+						Value: "ads.example.com",
+					},
+				},
+				InboundTag: []string{"socks"},
+			},
+			{
+				TargetTag: &router.RoutingRule_Tag{
+					Tag: "direct",
+				},
+				RuleTag: "admin",
+				UserEmail: []string{
+					"admin@v2ray.com",
+				},
+			},
+		},
+	}
+
+	r := new(router.Router)
+	common.Must(r.Init(config, nil, nil, nil))
+	return r
+}
+
+func TestTestRoute(t *testing.T) {
+	s := NewRoutingServer(newRouter())
+
+	resp, err := s.TestRoute(context.Background(), &TestRouteRequest{
+		RoutingContext: &RoutingContext{
+			InboundTag: "http",
+			UserEmail:  "admin@v2ray.com",
+			Network:    net.Network_TCP,
+			Target:     "ads.example.com",
+			TargetPort: 443,
+		},
+	})
+	common.Must(err)
+
+	expected := &TestRouteResponse{
+		Matched:     true,
+		RuleIndex:   1,
+		RuleTag:     "admin",
+		OutboundTag: "direct",
+		Rule: []*RuleResult{
+			{
+				Index:   0,
+				RuleTag: "block-ads",
+				Condition: []*ConditionResult{
+					{Name: "domain", Evaluated: true, Matched: true},
+					{Name: "inboundTag", Evaluated: true, Matched: false},
+				},
+			},
+			{
+				Index:   1,
+				RuleTag: "admin",
+				Condition: []*ConditionResult{
+					{Name: "user", Evaluated: true, Matched: true},
+				},
+				Matched: true,
+			},
+		},
+	}
+	if r := cmp.Diff(resp, expected); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = s.TestRoute(context.Background(), &TestRouteRequest{
+		RoutingContext: &RoutingContext{
+			Target:     "v2ray.com",
+			TargetPort: 443,
+		},
+	})
+	common.Must(err)
+	if resp.Matched || resp.RuleIndex != -1 || len(resp.Rule) != 2 {
+		t.Error("unexpected response: ", resp)
+	}
+	if r := cmp.Diff(resp.Rule[0].Condition, []*ConditionResult{
+		{Name: "domain", Evaluated: true, Matched: false},
+		{Name: "inboundTag", Evaluated: false, Matched: false},
+	}); r != "" {
+		t.Error(r)
+	}
+}
+
+type mockDecisionStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	decisions chan *RoutingDecision
+}
+
+func (s *mockDecisionStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *mockDecisionStream) Send(d *RoutingDecision) error {
+	s.decisions <- d
+	return nil
+}
+
+func TestSubscribeRoutingDecisions(t *testing.T) {
+	r := newRouter()
+	s := NewRoutingServer(r)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockDecisionStream{
+		ctx:       ctx,
+		decisions: make(chan *RoutingDecision, 1),
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.SubscribeRoutingDecisions(&SubscribeRoutingDecisionsRequest{}, stream)
+	}()
+
+	routeCtx := session.ContextWithInbound(context.Background(), &session.Inbound{Tag: "socks"})
+	routeCtx = session.ContextWithOutbound(routeCtx, &session.Outbound{Target: net.TCPDestination(net.DomainAddress("ads.example.com"), 80)})
+
+	var decision *RoutingDecision
+	for decision == nil {
+		tag, err := r.PickRoute(routeCtx)
+		common.Must(err)
+		if tag != "blocked" {
+			t.Fatal("expect tag 'blocked', but actually ", tag)
+		}
+		select {
+		case decision = <-stream.decisions:
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	if decision.RuleTag != "block-ads" || decision.OutboundTag != "blocked" || decision.RoutingContext.Target != "ads.example.com" || decision.RoutingContext.InboundTag != "socks" {
+		t.Error("unexpected decision: ", decision)
+	}
+
+	cancel()
+	common.Must(<-done)
+}
+
+func TestUpdateRules(t *testing.T) {
+	s := NewRoutingServer(newRouter())
+
+	testRoute := func() *TestRouteResponse {
+		resp, err := s.TestRoute(context.Background(), &TestRouteRequest{
+			RoutingContext: &RoutingContext{
+				Target:     "v2ray.com",
+				TargetPort: 443,
+			},
+		})
+		common.Must(err)
+		return resp
+	}
+
+	_, err := s.AddRule(context.Background(), &AddRuleRequest{
+		Rule: &router.RoutingRule{
+			TargetTag: &router.RoutingRule_Tag{
+				Tag: "proxy",
+			},
+			RuleTag:  "https",
+			PortList: &net.PortList{Range: []*net.PortRange{{From: 443, To: 443}}},
+		},
+		Index: -1,
+	})
+	common.Must(err)
+
+	if resp := testRoute(); resp.RuleIndex != 2 || resp.OutboundTag != "proxy" {
+		t.Error("unexpected response: ", resp)
+	}
+
+	_, err = s.RemoveRule(context.Background(), &RemoveRuleRequest{RuleTag: "https"})
+	common.Must(err)
+
+	if resp := testRoute(); resp.Matched {
+		t.Error("unexpected response: ", resp)
+	}
+
+	_, err = s.ReplaceRules(context.Background(), &ReplaceRulesRequest{
+		Config: &router.Config{
+			Rule: []*router.RoutingRule{
+				{
+					TargetTag: &router.RoutingRule_Tag{
+						Tag: "direct",
+					},
+					Domain: []*router.Domain{{Type: router.Domain_Full, Value: "v2ray.com"}},
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	if resp := testRoute(); resp.RuleIndex != 0 || resp.OutboundTag != "direct" || len(resp.Rule) != 1 {
+		t.Error("unexpected response: ", resp)
+	}
+}
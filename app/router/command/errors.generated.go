@@ -0,0 +1,18 @@
+package command
+
+import "v2ray.com/core/common/errors"
+import "fmt"
+
+type errPathObjHolder struct{}
+
+func newError(values ...interface{}) *errors.Error {
+	return errors.New(values...).WithPathObj(errPathObjHolder{})
+}
+
+func newDebugMsg(msg string) {
+	newError(msg).AtDebug().WriteToLog()
+}
+
+func StructString(class interface{}) string {
+	return fmt.Sprintf("%+v", class)
+}
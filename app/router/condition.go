@@ -4,12 +4,15 @@ package router
 
 import (
 	"strings"
+	"sync"
+	"time"
 
 	"go.starlark.net/starlark"
 	"go.starlark.net/syntax"
 
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/strmatcher"
+	"v2ray.com/core/features/policy"
 )
 
 type Condition interface {
@@ -64,6 +67,8 @@ func domainToMatcher(domain *Domain) (strmatcher.Matcher, error) {
 
 type DomainMatcher struct {
 	matchers strmatcher.IndexMatcher
+	// sets are domain sets queried in place, such as entries of compact geodata.
+	sets []strmatcher.Matcher
 }
 
 func NewDomainMatcher(domains []*Domain) (*DomainMatcher, error) {
@@ -82,7 +87,15 @@ func NewDomainMatcher(domains []*Domain) (*DomainMatcher, error) {
 }
 
 func (m *DomainMatcher) ApplyDomain(domain string) bool {
-	return m.matchers.Match(domain) > 0
+	if m.matchers.Match(domain) > 0 {
+		return true
+	}
+	for _, set := range m.sets {
+		if set.Match(domain) {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *DomainMatcher) Apply(ctx *Context) bool {
@@ -112,23 +125,37 @@ func getIPsFromTarget(ctx *Context) []net.IP {
 	return ctx.GetTargetIPs()
 }
 
+// ipMatcher is implemented by GeoIPMatcher and CompactGeoIPMatcher.
+type ipMatcher interface {
+	Match(ip net.IP) bool
+}
+
 type MultiGeoIPMatcher struct {
-	matchers []*GeoIPMatcher
+	matchers []ipMatcher
+	onSource bool
 	ipFunc   func(*Context) []net.IP
 }
 
 func NewMultiGeoIPMatcher(geoips []*GeoIP, onSource bool) (*MultiGeoIPMatcher, error) {
-	var matchers []*GeoIPMatcher
+	return newMultiGeoIPMatcher(&globalGeoIPContainer, geoips, nil, onSource)
+}
+
+func newMultiGeoIPMatcher(container *GeoIPMatcherContainer, geoips []*GeoIP, compact []*CompactGeoIPMatcher, onSource bool) (*MultiGeoIPMatcher, error) {
+	var matchers []ipMatcher
 	for _, geoip := range geoips {
-		matcher, err := globalGeoIPContainer.Add(geoip)
+		matcher, err := container.Add(geoip)
 		if err != nil {
 			return nil, err
 		}
 		matchers = append(matchers, matcher)
 	}
+	for _, matcher := range compact {
+		matchers = append(matchers, matcher)
+	}
 
 	matcher := &MultiGeoIPMatcher{
 		matchers: matchers,
+		onSource: onSource,
 	}
 
 	if onSource {
@@ -154,16 +181,24 @@ func (m *MultiGeoIPMatcher) Apply(ctx *Context) bool {
 }
 
 type PortMatcher struct {
-	port net.MemoryPortList
+	port     net.MemoryPortList
+	onSource bool
 }
 
-func NewPortMatcher(list *net.PortList) *PortMatcher {
+func NewPortMatcher(list *net.PortList, onSource bool) *PortMatcher {
 	return &PortMatcher{
-		port: net.PortListFromProto(list),
+		port:     net.PortListFromProto(list),
+		onSource: onSource,
 	}
 }
 
 func (v *PortMatcher) Apply(ctx *Context) bool {
+	if v.onSource {
+		if ctx.Inbound == nil || !ctx.Inbound.Source.IsValid() {
+			return false
+		}
+		return v.port.Contains(ctx.Inbound.Source.Port)
+	}
 	if ctx.Outbound == nil || !ctx.Outbound.Target.IsValid() {
 		return false
 	}
@@ -284,6 +319,303 @@ func (m *ProtocolMatcher) Apply(ctx *Context) bool {
 	return false
 }
 
+func loadLocation(name string) (*time.Location, error) {
+	if len(name) == 0 {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, newError("unknown time zone ", name).Base(err)
+	}
+	return loc, nil
+}
+
+type timeWindow struct {
+	weekdays [7]bool
+	start    uint32
+	end      uint32
+}
+
+// contains returns true if t is in the window. A window ending before its start crosses midnight, so the part after
+// midnight belongs to the weekday before.
+func (w *timeWindow) contains(t time.Time) bool {
+	seconds := uint32(t.Hour()*3600 + t.Minute()*60 + t.Second())
+	if w.start < w.end {
+		return w.weekdays[t.Weekday()] && seconds >= w.start && seconds < w.end
+	}
+	if seconds >= w.start {
+		return w.weekdays[t.Weekday()]
+	}
+	return seconds < w.end && w.weekdays[(t.Weekday()+6)%7]
+}
+
+// TimeMatcher matches current time against a list of time windows.
+type TimeMatcher struct {
+	location *time.Location
+	windows  []timeWindow
+}
+
+func NewTimeMatcher(schedule *Schedule) (*TimeMatcher, error) {
+	loc, err := loadLocation(schedule.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := make([]timeWindow, 0, len(schedule.Window))
+	for _, w := range schedule.Window {
+		if w.Start >= 86400 || w.End > 86400 {
+			return nil, newError("invalid time window ", w.Start, "-", w.End)
+		}
+		tw := timeWindow{
+			start: w.Start,
+			end:   w.End,
+		}
+		if len(w.Weekday) == 0 {
+			for i := range tw.weekdays {
+				tw.weekdays[i] = true
+			}
+		}
+		for _, d := range w.Weekday {
+			if d > 6 {
+				return nil, newError("invalid weekday ", d)
+			}
+			tw.weekdays[d] = true
+		}
+		windows = append(windows, tw)
+	}
+
+	return &TimeMatcher{
+		location: loc,
+		windows:  windows,
+	}, nil
+}
+
+func (m *TimeMatcher) ApplyTime(t time.Time) bool {
+	t = t.In(m.location)
+	for i := range m.windows {
+		if m.windows[i].contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TimeMatcher) Apply(ctx *Context) bool {
+	return m.ApplyTime(time.Now())
+}
+
+// trafficPeriod is the period of traffic quota rules, with the time zone of period boundaries.
+type trafficPeriod struct {
+	period   TrafficQuota_Period
+	location *time.Location
+}
+
+// start returns the start of the period that t is in, or zero time for the period that never ends.
+func (p trafficPeriod) start(t time.Time) time.Time {
+	t = t.In(p.location)
+	year, month, day := t.Date()
+	switch p.period {
+	case TrafficQuota_Daily:
+		return time.Date(year, month, day, 0, 0, 0, 0, p.location)
+	case TrafficQuota_Weekly:
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, p.location)
+	case TrafficQuota_Monthly:
+		return time.Date(year, month, 1, 0, 0, 0, 0, p.location)
+	default:
+		return time.Time{}
+	}
+}
+
+type trafficPeriodKey struct {
+	period   TrafficQuota_Period
+	location string
+}
+
+func (p trafficPeriod) key() trafficPeriodKey {
+	return trafficPeriodKey{
+		period:   p.period,
+		location: p.location.String(),
+	}
+}
+
+// trafficUsage is the traffic of a user in the current period of traffic quota rules.
+type trafficUsage struct {
+	periodStart time.Time
+	used        int64
+}
+
+type trafficUsageKey struct {
+	period trafficPeriodKey
+	email  string
+}
+
+// trafficUsages keeps traffic usages of users in periods of traffic quota rules. Traffic is counted by the dispatcher
+// through Router, so that every byte is counted into the period when it happens. Router keeps one for all its rules,
+// so that usages are not lost when rules are rebuilt.
+type trafficUsages struct {
+	access sync.Mutex
+	// periods are periods of the current traffic quota rules. Traffic is only counted in these periods.
+	periods map[trafficPeriodKey]trafficPeriod
+	usages  map[trafficUsageKey]*trafficUsage
+}
+
+func newTrafficUsages() *trafficUsages {
+	return &trafficUsages{
+		periods: make(map[trafficPeriodKey]trafficPeriod),
+		usages:  make(map[trafficUsageKey]*trafficUsage),
+	}
+}
+
+// setPeriods sets periods of the current traffic quota rules. Usages in other periods are dropped.
+func (u *trafficUsages) setPeriods(periods []trafficPeriod) {
+	u.access.Lock()
+	defer u.access.Unlock()
+
+	u.periods = make(map[trafficPeriodKey]trafficPeriod, len(periods))
+	for _, p := range periods {
+		u.periods[p.key()] = p
+	}
+	for key := range u.usages {
+		if _, found := u.periods[key.period]; !found {
+			delete(u.usages, key)
+		}
+	}
+}
+
+// hasPeriods returns whether there is any traffic quota rule that needs traffic of users.
+func (u *trafficUsages) hasPeriods() bool {
+	u.access.Lock()
+	defer u.access.Unlock()
+
+	return len(u.periods) > 0
+}
+
+// add counts n bytes of traffic of the user at time t into all periods.
+func (u *trafficUsages) add(email string, n int64, t time.Time) {
+	u.access.Lock()
+	defer u.access.Unlock()
+
+	for key, p := range u.periods {
+		start := p.start(t)
+		usageKey := trafficUsageKey{period: key, email: email}
+		usage, found := u.usages[usageKey]
+		if !found {
+			usage = &trafficUsage{periodStart: start}
+			u.usages[usageKey] = usage
+		}
+		if usage.periodStart.Before(start) {
+			usage.periodStart = start
+			usage.used = 0
+		}
+		usage.used += n
+	}
+}
+
+// used returns the traffic of the user in the period that t is in.
+func (u *trafficUsages) used(p trafficPeriod, email string, t time.Time) int64 {
+	u.access.Lock()
+	defer u.access.Unlock()
+
+	usage, found := u.usages[trafficUsageKey{period: p.key(), email: email}]
+	if !found || usage.periodStart.Before(p.start(t)) {
+		return 0
+	}
+	return usage.used
+}
+
+// trafficCounter counts traffic of a user into trafficUsages. It implements routing.TrafficCounter.
+type trafficCounter struct {
+	usages *trafficUsages
+	email  string
+}
+
+func (c *trafficCounter) Add(n int64) {
+	c.usages.add(c.email, n, time.Now())
+}
+
+// TrafficQuotaMatcher matches users who have passed a traffic threshold in the current period.
+//
+// With period Policy, the usage of the quota of the user in policy is used, so that it is shared with quota
+// enforcement. Otherwise traffic of the user is counted by the dispatcher into the current period, since the rule is
+// built.
+type TrafficQuotaMatcher struct {
+	threshold int64
+	period    trafficPeriod
+	// usages is used when the Context doesn't come from a Router.
+	usages *trafficUsages
+}
+
+func NewTrafficQuotaMatcher(quota *TrafficQuota) (*TrafficQuotaMatcher, error) {
+	loc, err := loadLocation(quota.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	m := &TrafficQuotaMatcher{
+		threshold: int64(quota.Threshold),
+		period: trafficPeriod{
+			period:   quota.Period,
+			location: loc,
+		},
+		usages: newTrafficUsages(),
+	}
+	if m.countsTraffic() {
+		if m.threshold <= 0 {
+			return nil, newError("threshold of traffic quota is required unless period is Policy")
+		}
+		m.usages.setPeriods([]trafficPeriod{m.period})
+	}
+	return m, nil
+}
+
+// countsTraffic returns whether the matcher needs traffic of users counted by the dispatcher.
+func (m *TrafficQuotaMatcher) countsTraffic() bool {
+	return m.period.period != TrafficQuota_Policy
+}
+
+// AddTraffic counts n bytes of traffic of the user at time t, for matching Contexts that don't come from a Router.
+func (m *TrafficQuotaMatcher) AddTraffic(email string, n int64, t time.Time) {
+	m.usages.add(email, n, t)
+}
+
+// ApplyTraffic returns true if the user has passed the threshold at time t, with traffic added by AddTraffic.
+func (m *TrafficQuotaMatcher) ApplyTraffic(email string, t time.Time) bool {
+	return m.applyTraffic(m.usages, email, t)
+}
+
+func (m *TrafficQuotaMatcher) applyTraffic(usages *trafficUsages, email string, t time.Time) bool {
+	return m.threshold > 0 && usages.used(m.period, email, t) >= m.threshold
+}
+
+// ApplyUsage returns true if the usage has passed the threshold, or the quota is used up if there is no threshold.
+func (m *TrafficQuotaMatcher) ApplyUsage(usage policy.QuotaUsage) bool {
+	if m.threshold <= 0 {
+		return usage.Exceeded()
+	}
+	return usage.Used >= m.threshold
+}
+
+func (m *TrafficQuotaMatcher) Apply(ctx *Context) bool {
+	if ctx.Inbound == nil || ctx.Inbound.User == nil {
+		return false
+	}
+
+	if !m.countsTraffic() {
+		usage, found := ctx.quotaUsage(ctx.Inbound.User)
+		return found && m.ApplyUsage(usage)
+	}
+
+	email := ctx.Inbound.User.Email
+	if len(email) == 0 {
+		return false
+	}
+	usages := ctx.trafficUsages
+	if usages == nil {
+		usages = m.usages
+	}
+	return m.applyTraffic(usages, email, time.Now())
+}
+
 type AttributeMatcher struct {
 	program *starlark.Program
 }
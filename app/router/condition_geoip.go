@@ -5,6 +5,7 @@ package router
 import (
 	"encoding/binary"
 	"sort"
+	"sync"
 
 	"v2ray.com/core/common/net"
 )
@@ -162,12 +163,16 @@ func (m *GeoIPMatcher) Match(ip net.IP) bool {
 
 // GeoIPMatcherContainer is a container for GeoIPMatchers. It keeps unique copies of GeoIPMatcher by country code.
 type GeoIPMatcherContainer struct {
+	access   sync.Mutex
 	matchers []*GeoIPMatcher
 }
 
 // Add adds a new GeoIP set into the container.
 // If the country code of GeoIP is not empty, GeoIPMatcherContainer will try to find an existing one, instead of adding a new one.
 func (c *GeoIPMatcherContainer) Add(geoip *GeoIP) (*GeoIPMatcher, error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
 	if len(geoip.CountryCode) > 0 {
 		for _, m := range c.matchers {
 			if m.countryCode == geoip.CountryCode {
@@ -188,6 +193,18 @@ func (c *GeoIPMatcherContainer) Add(geoip *GeoIP) (*GeoIPMatcher, error) {
 	return m, nil
 }
 
+// replace replaces GeoIPMatchers in the container with the ones in other. GeoIPMatchers in use are not affected.
+func (c *GeoIPMatcherContainer) replace(other *GeoIPMatcherContainer) {
+	other.access.Lock()
+	matchers := other.matchers
+	other.access.Unlock()
+
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	c.matchers = matchers
+}
+
 var (
 	globalGeoIPContainer GeoIPMatcherContainer
 )
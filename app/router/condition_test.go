@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	proto "github.com/golang/protobuf/proto"
 
@@ -17,6 +18,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/protocol/http"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/policy"
 )
 
 func init() {
@@ -264,6 +266,29 @@ func TestRoutingRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			rule: &RoutingRule{
+				SourcePortList: &net.PortList{
+					Range: []*net.PortRange{
+						{From: 10000, To: 20000},
+					},
+				},
+			},
+			test: []ruleTest{
+				{
+					input:  withInbound(&session.Inbound{Source: net.TCPDestination(net.LocalHostIP, 12345)}),
+					output: true,
+				},
+				{
+					input:  withInbound(&session.Inbound{Source: net.TCPDestination(net.LocalHostIP, 443)}),
+					output: false,
+				},
+				{
+					input:  withOutbound(&session.Outbound{Target: net.TCPDestination(net.LocalHostIP, 12345)}),
+					output: false,
+				},
+			},
+		},
 		{
 			rule: &RoutingRule{
 				Protocol:   []string{"http"},
@@ -291,6 +316,179 @@ func TestRoutingRule(t *testing.T) {
 	}
 }
 
+func TestTimeMatcher(t *testing.T) {
+	matcher, err := NewTimeMatcher(&Schedule{
+		Timezone: "UTC",
+		Window: []*TimeWindow{
+			{
+				Weekday: []uint32{1, 2, 3, 4, 5},
+				Start:   8 * 3600,
+				End:     18 * 3600,
+			},
+			{
+				Weekday: []uint32{6},
+				Start:   22 * 3600,
+				End:     2 * 3600,
+			},
+		},
+	})
+	common.Must(err)
+
+	testCases := []struct {
+		Time   time.Time
+		Output bool
+	}{
+		{
+			Time:   time.Date(2020, 5, 4, 8, 0, 0, 0, time.UTC), // Monday
+			Output: true,
+		},
+		{
+			Time:   time.Date(2020, 5, 4, 18, 0, 0, 0, time.UTC),
+			Output: false,
+		},
+		{
+			Time:   time.Date(2020, 5, 3, 12, 0, 0, 0, time.UTC), // Sunday
+			Output: false,
+		},
+		{
+			Time:   time.Date(2020, 5, 9, 23, 30, 0, 0, time.UTC), // Saturday
+			Output: true,
+		},
+		{
+			Time:   time.Date(2020, 5, 9, 1, 30, 0, 0, time.UTC),
+			Output: false,
+		},
+		{
+			Time:   time.Date(2020, 5, 10, 1, 30, 0, 0, time.UTC), // Sunday, after the window of Saturday night
+			Output: true,
+		},
+		{
+			Time:   time.Date(2020, 5, 10, 2, 0, 0, 0, time.UTC),
+			Output: false,
+		},
+		{
+			Time:   time.Date(2020, 5, 4, 7, 0, 0, 0, time.FixedZone("UTC+8", 8*3600)),
+			Output: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		r := matcher.ApplyTime(testCase.Time)
+		if r != testCase.Output {
+			t.Error("expected output ", testCase.Output, " for time ", testCase.Time, " but got ", r)
+		}
+	}
+
+	if _, err := NewTimeMatcher(&Schedule{Window: []*TimeWindow{{Weekday: []uint32{7}}}}); err == nil {
+		t.Error("expected error for invalid weekday")
+	}
+}
+
+func TestTrafficQuotaMatcher(t *testing.T) {
+	matcher, err := NewTrafficQuotaMatcher(&TrafficQuota{
+		Threshold: 1000,
+		Period:    TrafficQuota_Monthly,
+		Timezone:  "UTC",
+	})
+	common.Must(err)
+
+	trafficCases := []struct {
+		Email   string
+		Traffic int64
+		Time    time.Time
+		Output  bool
+	}{
+		{
+			Email:   "a@v2ray.com",
+			Traffic: 500,
+			Time:    time.Date(2020, 5, 4, 0, 0, 0, 0, time.UTC),
+			Output:  false,
+		},
+		{
+			Email:   "a@v2ray.com",
+			Traffic: 600,
+			Time:    time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC),
+			Output:  true,
+		},
+		{
+			Email:   "b@v2ray.com",
+			Traffic: 800,
+			Time:    time.Date(2020, 5, 20, 0, 0, 0, 0, time.UTC),
+			Output:  false,
+		},
+		{
+			Email:  "a@v2ray.com",
+			Time:   time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), // new period starts
+			Output: false,
+		},
+		{
+			Email:   "a@v2ray.com",
+			Traffic: 1000,
+			Time:    time.Date(2020, 6, 2, 0, 0, 0, 0, time.UTC),
+			Output:  true,
+		},
+		{
+			Email:   "c@v2ray.com",
+			Traffic: 900,
+			Time:    time.Date(2020, 6, 30, 12, 0, 0, 0, time.UTC),
+			Output:  false,
+		},
+		{
+			Email:   "c@v2ray.com",
+			Traffic: 200, // only this is in July
+			Time:    time.Date(2020, 7, 1, 12, 0, 0, 0, time.UTC),
+			Output:  false,
+		},
+	}
+
+	for _, testCase := range trafficCases {
+		matcher.AddTraffic(testCase.Email, testCase.Traffic, testCase.Time)
+		if r := matcher.ApplyTraffic(testCase.Email, testCase.Time); r != testCase.Output {
+			t.Error("expected output ", testCase.Output, " for ", testCase.Email, " with ", testCase.Traffic, " at ", testCase.Time, " but got ", r)
+		}
+	}
+
+	if _, err := NewTrafficQuotaMatcher(&TrafficQuota{Period: TrafficQuota_Monthly}); err == nil {
+		t.Error("expected error for no threshold without period Policy")
+	}
+
+	testCases := []struct {
+		Threshold uint64
+		Usage     policy.QuotaUsage
+		Output    bool
+	}{
+		{
+			Threshold: 1000,
+			Usage:     policy.QuotaUsage{Quota: policy.Quota{Bytes: 4000}, Used: 500},
+			Output:    false,
+		},
+		{
+			Threshold: 1000,
+			Usage:     policy.QuotaUsage{Quota: policy.Quota{Bytes: 4000}, Used: 1500},
+			Output:    true,
+		},
+		{
+			Usage:  policy.QuotaUsage{Quota: policy.Quota{Bytes: 4000}, Used: 1500},
+			Output: false,
+		},
+		{
+			Usage:  policy.QuotaUsage{Quota: policy.Quota{Bytes: 4000}, Used: 4000},
+			Output: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		matcher, err := NewTrafficQuotaMatcher(&TrafficQuota{
+			Threshold: testCase.Threshold,
+			Period:    TrafficQuota_Policy,
+		})
+		common.Must(err)
+		if r := matcher.ApplyUsage(testCase.Usage); r != testCase.Output {
+			t.Error("expected output ", testCase.Output, " for ", testCase.Usage, " but got ", r)
+		}
+	}
+}
+
 func loadGeoSite(country string) ([]*Domain, error) {
 	geositeBytes, err := filesystem.ReadAsset("geosite.dat")
 	if err != nil {
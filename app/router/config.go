@@ -3,7 +3,10 @@
 package router
 
 import (
+	"strings"
+
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/strmatcher"
 	"v2ray.com/core/features/outbound"
 )
 
@@ -48,8 +51,12 @@ func (l *CIDRList) Swap(i int, j int) {
 
 type Rule struct {
 	Tag       string
+	RuleTag   string
 	Balancer  *Balancer
 	Condition Condition
+
+	// config is the rule config that this rule is built from.
+	config *RoutingRule
 }
 
 func (r *Rule) GetTag() (string, error) {
@@ -64,13 +71,36 @@ func (r *Rule) Apply(ctx *Context) bool {
 }
 
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
+	return rr.buildCondition(newGeoDataLoader())
+}
+
+func (rr *RoutingRule) buildCondition(loader *geoDataLoader) (Condition, error) {
 	conds := NewConditionChan()
 
-	if len(rr.Domain) > 0 {
-		matcher, err := NewDomainMatcher(rr.Domain)
+	domains := rr.Domain
+	var domainSets []strmatcher.Matcher
+	for _, ref := range rr.GeositeRef {
+		set, err := loader.loadCompactGeoSiteMatcher(ref)
+		if err != nil {
+			return nil, newError("failed to load geosite: ", ref.Code).Base(err)
+		}
+		if set != nil {
+			domainSets = append(domainSets, set)
+			continue
+		}
+		d, err := loader.LoadGeoSite(ref)
+		if err != nil {
+			return nil, newError("failed to load geosite: ", ref.Code).Base(err)
+		}
+		domains = append(domains[:len(domains):len(domains)], d...)
+	}
+
+	if len(domains) > 0 || len(domainSets) > 0 {
+		matcher, err := NewDomainMatcher(domains)
 		if err != nil {
 			return nil, newError("failed to build domain condition").Base(err)
 		}
+		matcher.sets = domainSets
 		conds.Add(matcher)
 	}
 
@@ -83,9 +113,13 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	}
 
 	if rr.PortList != nil {
-		conds.Add(NewPortMatcher(rr.PortList))
+		conds.Add(NewPortMatcher(rr.PortList, false))
 	} else if rr.PortRange != nil {
-		conds.Add(NewPortMatcher(&net.PortList{Range: []*net.PortRange{rr.PortRange}}))
+		conds.Add(NewPortMatcher(&net.PortList{Range: []*net.PortRange{rr.PortRange}}, false))
+	}
+
+	if rr.SourcePortList != nil {
+		conds.Add(NewPortMatcher(rr.SourcePortList, true))
 	}
 
 	if len(rr.Networks) > 0 {
@@ -94,8 +128,12 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(NewNetworkMatcher(rr.NetworkList.Network))
 	}
 
-	if len(rr.Geoip) > 0 {
-		cond, err := NewMultiGeoIPMatcher(rr.Geoip, false)
+	geoips, compactGeoips, err := loadGeoIPs(loader, rr.Geoip, rr.GeoipRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(geoips) > 0 || len(compactGeoips) > 0 {
+		cond, err := newMultiGeoIPMatcher(loader.geoIPMatchers, geoips, compactGeoips, false)
 		if err != nil {
 			return nil, err
 		}
@@ -108,8 +146,12 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(cond)
 	}
 
-	if len(rr.SourceGeoip) > 0 {
-		cond, err := NewMultiGeoIPMatcher(rr.SourceGeoip, true)
+	sourceGeoips, compactSourceGeoips, err := loadGeoIPs(loader, rr.SourceGeoip, rr.SourceGeoipRef)
+	if err != nil {
+		return nil, err
+	}
+	if len(sourceGeoips) > 0 || len(compactSourceGeoips) > 0 {
+		cond, err := newMultiGeoIPMatcher(loader.geoIPMatchers, sourceGeoips, compactSourceGeoips, true)
 		if err != nil {
 			return nil, err
 		}
@@ -134,6 +176,22 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(cond)
 	}
 
+	if rr.Schedule != nil {
+		cond, err := NewTimeMatcher(rr.Schedule)
+		if err != nil {
+			return nil, newError("failed to build schedule condition").Base(err)
+		}
+		conds.Add(cond)
+	}
+
+	if rr.TrafficQuota != nil {
+		cond, err := NewTrafficQuotaMatcher(rr.TrafficQuota)
+		if err != nil {
+			return nil, newError("failed to build traffic quota condition").Base(err)
+		}
+		conds.Add(cond)
+	}
+
 	if conds.Len() == 0 {
 		return nil, newError("this rule has no effective fields").AtWarning()
 	}
@@ -141,8 +199,49 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	return conds, nil
 }
 
+// loadGeoIPs loads GeoIP references. References in compact geodata are returned as shared matchers,
+// others are appended to geoips.
+func loadGeoIPs(loader *geoDataLoader, geoips []*GeoIP, refs []*GeoDataReference) ([]*GeoIP, []*CompactGeoIPMatcher, error) {
+	var compact []*CompactGeoIPMatcher
+	for _, ref := range refs {
+		geoip, m, err := loader.loadGeoIPMatcher(ref)
+		if err != nil {
+			return nil, nil, newError("failed to load geoip: ", ref.Code).Base(err)
+		}
+		if m != nil {
+			compact = append(compact, m)
+			continue
+		}
+		geoips = append(geoips[:len(geoips):len(geoips)], geoip)
+	}
+	return geoips, compact, nil
+}
+
+// loadCompactGeoSiteMatcher returns the shared matcher of a GeoSite reference in compact geodata,
+// or nil if the reference is not in compact geodata or has more than one attribute.
+func (l *geoDataLoader) loadCompactGeoSiteMatcher(ref *GeoDataReference) (strmatcher.Matcher, error) {
+	gd, err := l.compact.Load(ref.File)
+	if err != nil || gd == nil {
+		return nil, err
+	}
+	if strings.Count(ref.Code, "@") > 1 {
+		return nil, nil
+	}
+	if parts := strings.Split(ref.Code, "@"); len(parts) == 2 {
+		if _, found := gd.entry(compactKindGeoSite, ref.Code); !found {
+			return nil, newAttributeNotFoundError(parts[1], parts[0])
+		}
+	}
+	m, err := gd.geoSiteMatcher(ref.Code)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (br *BalancingRule) Build(ohm outbound.Manager) (*Balancer, error) {
 	return &Balancer{
+		tag:       br.Tag,
 		selectors: br.OutboundSelector,
 		strategy:  &RandomStrategy{},
 		ohm:       ohm,
@@ -54,6 +54,44 @@ func (Domain_Type) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_6b1608360690c5fc, []int{0, 0}
 }
 
+type TrafficQuota_Period int32
+
+const (
+	// Count all traffic since any rule uses this period.
+	TrafficQuota_Total TrafficQuota_Period = 0
+	TrafficQuota_Daily TrafficQuota_Period = 1
+	// Weeks start on Monday.
+	TrafficQuota_Weekly  TrafficQuota_Period = 2
+	TrafficQuota_Monthly TrafficQuota_Period = 3
+	// Use the usage of the quota of the user in policy, in the period of the
+	// quota. Users without quotas in policy never match.
+	TrafficQuota_Policy TrafficQuota_Period = 4
+)
+
+var TrafficQuota_Period_name = map[int32]string{
+	0: "Total",
+	1: "Daily",
+	2: "Weekly",
+	3: "Monthly",
+	4: "Policy",
+}
+
+var TrafficQuota_Period_value = map[string]int32{
+	"Total":   0,
+	"Daily":   1,
+	"Weekly":  2,
+	"Monthly": 3,
+	"Policy":  4,
+}
+
+func (x TrafficQuota_Period) String() string {
+	return proto.EnumName(TrafficQuota_Period_name, int32(x))
+}
+
+func (TrafficQuota_Period) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_6b1608360690c5fc, []int{8, 0}
+}
+
 type Config_DomainStrategy int32
 
 const (
@@ -65,6 +103,9 @@ const (
 	Config_IpIfNonMatch Config_DomainStrategy = 2
 	// Resolve to IP if any rule requires IP matching.
 	Config_IpOnDemand Config_DomainStrategy = 3
+	// Use IPs in DNS cache if any rule requires IP matching. Domains are never
+	// resolved during routing.
+	Config_IpIfCached Config_DomainStrategy = 4
 )
 
 var Config_DomainStrategy_name = map[int32]string{
@@ -72,6 +113,7 @@ var Config_DomainStrategy_name = map[int32]string{
 	1: "UseIp",
 	2: "IpIfNonMatch",
 	3: "IpOnDemand",
+	4: "IpIfCached",
 }
 
 var Config_DomainStrategy_value = map[string]int32{
@@ -79,6 +121,7 @@ var Config_DomainStrategy_value = map[string]int32{
 	"UseIp":        1,
 	"IpIfNonMatch": 2,
 	"IpOnDemand":   3,
+	"IpIfCached":   4,
 }
 
 func (x Config_DomainStrategy) String() string {
@@ -86,7 +129,7 @@ func (x Config_DomainStrategy) String() string {
 }
 
 func (Config_DomainStrategy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_6b1608360690c5fc, []int{8, 0}
+	return fileDescriptor_6b1608360690c5fc, []int{12, 0}
 }
 
 // Domain for routing decision.
@@ -458,6 +501,225 @@ func (m *GeoSiteList) GetEntry() []*GeoSite {
 	return nil
 }
 
+// Time window in a day.
+type TimeWindow struct {
+	// Days of week, 0 for Sunday. Empty for every day.
+	Weekday []uint32 `protobuf:"varint,1,rep,packed,name=weekday,proto3" json:"weekday,omitempty"`
+	// Start of the window, in seconds since midnight.
+	Start uint32 `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	// End of the window, in seconds since midnight. If it is not greater than start, the window spans midnight.
+	End                  uint32   `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TimeWindow) Reset()         { *m = TimeWindow{} }
+func (m *TimeWindow) String() string { return proto.CompactTextString(m) }
+func (*TimeWindow) ProtoMessage()    {}
+func (*TimeWindow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b1608360690c5fc, []int{6}
+}
+
+func (m *TimeWindow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimeWindow.Unmarshal(m, b)
+}
+func (m *TimeWindow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimeWindow.Marshal(b, m, deterministic)
+}
+func (m *TimeWindow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimeWindow.Merge(m, src)
+}
+func (m *TimeWindow) XXX_Size() int {
+	return xxx_messageInfo_TimeWindow.Size(m)
+}
+func (m *TimeWindow) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimeWindow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimeWindow proto.InternalMessageInfo
+
+func (m *TimeWindow) GetWeekday() []uint32 {
+	if m != nil {
+		return m.Weekday
+	}
+	return nil
+}
+
+func (m *TimeWindow) GetStart() uint32 {
+	if m != nil {
+		return m.Start
+	}
+	return 0
+}
+
+func (m *TimeWindow) GetEnd() uint32 {
+	if m != nil {
+		return m.End
+	}
+	return 0
+}
+
+type Schedule struct {
+	// Name of time zone in IANA database, such as "Asia/Shanghai". Local time zone is used if empty.
+	Timezone             string        `protobuf:"bytes,1,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Window               []*TimeWindow `protobuf:"bytes,2,rep,name=window,proto3" json:"window,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Schedule) Reset()         { *m = Schedule{} }
+func (m *Schedule) String() string { return proto.CompactTextString(m) }
+func (*Schedule) ProtoMessage()    {}
+func (*Schedule) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b1608360690c5fc, []int{7}
+}
+
+func (m *Schedule) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Schedule.Unmarshal(m, b)
+}
+func (m *Schedule) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Schedule.Marshal(b, m, deterministic)
+}
+func (m *Schedule) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Schedule.Merge(m, src)
+}
+func (m *Schedule) XXX_Size() int {
+	return xxx_messageInfo_Schedule.Size(m)
+}
+func (m *Schedule) XXX_DiscardUnknown() {
+	xxx_messageInfo_Schedule.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Schedule proto.InternalMessageInfo
+
+func (m *Schedule) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+func (m *Schedule) GetWindow() []*TimeWindow {
+	if m != nil {
+		return m.Window
+	}
+	return nil
+}
+
+// TrafficQuota matches users who have passed a traffic threshold in the
+// current period. Traffic of users is counted by the dispatcher into the
+// period, unless the period is Policy.
+type TrafficQuota struct {
+	// Number of bytes, uplink and downlink combined. If 0, the rule takes effect
+	// when the quota of the user in policy is used up, which is only allowed
+	// with period Policy.
+	Threshold uint64              `protobuf:"varint,1,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Period    TrafficQuota_Period `protobuf:"varint,2,opt,name=period,proto3,enum=v2ray.core.app.router.TrafficQuota_Period" json:"period,omitempty"`
+	// Name of time zone for determining period boundaries. Local time zone is used if empty.
+	Timezone             string   `protobuf:"bytes,3,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TrafficQuota) Reset()         { *m = TrafficQuota{} }
+func (m *TrafficQuota) String() string { return proto.CompactTextString(m) }
+func (*TrafficQuota) ProtoMessage()    {}
+func (*TrafficQuota) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b1608360690c5fc, []int{8}
+}
+
+func (m *TrafficQuota) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TrafficQuota.Unmarshal(m, b)
+}
+func (m *TrafficQuota) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TrafficQuota.Marshal(b, m, deterministic)
+}
+func (m *TrafficQuota) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TrafficQuota.Merge(m, src)
+}
+func (m *TrafficQuota) XXX_Size() int {
+	return xxx_messageInfo_TrafficQuota.Size(m)
+}
+func (m *TrafficQuota) XXX_DiscardUnknown() {
+	xxx_messageInfo_TrafficQuota.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TrafficQuota proto.InternalMessageInfo
+
+func (m *TrafficQuota) GetThreshold() uint64 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *TrafficQuota) GetPeriod() TrafficQuota_Period {
+	if m != nil {
+		return m.Period
+	}
+	return TrafficQuota_Total
+}
+
+func (m *TrafficQuota) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+// Reference to an entry in a geodata file in asset location. The entry is loaded when the rule is built.
+type GeoDataReference struct {
+	// File name, such as "geoip.dat" or "geosite.dat".
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// Country code of the entry, such as "CN". For geosite entries, attributes may follow, such as "CN@ads".
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GeoDataReference) Reset()         { *m = GeoDataReference{} }
+func (m *GeoDataReference) String() string { return proto.CompactTextString(m) }
+func (*GeoDataReference) ProtoMessage()    {}
+func (*GeoDataReference) Descriptor() ([]byte, []int) {
+	return fileDescriptor_6b1608360690c5fc, []int{9}
+}
+
+func (m *GeoDataReference) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GeoDataReference.Unmarshal(m, b)
+}
+func (m *GeoDataReference) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GeoDataReference.Marshal(b, m, deterministic)
+}
+func (m *GeoDataReference) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GeoDataReference.Merge(m, src)
+}
+func (m *GeoDataReference) XXX_Size() int {
+	return xxx_messageInfo_GeoDataReference.Size(m)
+}
+func (m *GeoDataReference) XXX_DiscardUnknown() {
+	xxx_messageInfo_GeoDataReference.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GeoDataReference proto.InternalMessageInfo
+
+func (m *GeoDataReference) GetFile() string {
+	if m != nil {
+		return m.File
+	}
+	return ""
+}
+
+func (m *GeoDataReference) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
 type RoutingRule struct {
 	// Types that are valid to be assigned to TargetTag:
 	//	*RoutingRule_Tag
@@ -484,21 +746,35 @@ type RoutingRule struct {
 	// List of CIDRs for source IP address matching.
 	SourceCidr []*CIDR `protobuf:"bytes,6,rep,name=source_cidr,json=sourceCidr,proto3" json:"source_cidr,omitempty"` // Deprecated: Do not use.
 	// List of GeoIPs for source IP address matching. If this entry exists, the source_cidr above will have no effect.
-	SourceGeoip          []*GeoIP `protobuf:"bytes,11,rep,name=source_geoip,json=sourceGeoip,proto3" json:"source_geoip,omitempty"`
-	UserEmail            []string `protobuf:"bytes,7,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
-	InboundTag           []string `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
-	Protocol             []string `protobuf:"bytes,9,rep,name=protocol,proto3" json:"protocol,omitempty"`
-	Attributes           string   `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	SourceGeoip []*GeoIP `protobuf:"bytes,11,rep,name=source_geoip,json=sourceGeoip,proto3" json:"source_geoip,omitempty"`
+	UserEmail   []string `protobuf:"bytes,7,rep,name=user_email,json=userEmail,proto3" json:"user_email,omitempty"`
+	InboundTag  []string `protobuf:"bytes,8,rep,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	Protocol    []string `protobuf:"bytes,9,rep,name=protocol,proto3" json:"protocol,omitempty"`
+	Attributes  string   `protobuf:"bytes,15,opt,name=attributes,proto3" json:"attributes,omitempty"`
+	// List of ports for source port matching.
+	SourcePortList *net.PortList `protobuf:"bytes,16,opt,name=source_port_list,json=sourcePortList,proto3" json:"source_port_list,omitempty"`
+	// Time windows in which this rule takes effect.
+	Schedule *Schedule `protobuf:"bytes,17,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// Traffic threshold of the user in the session. The rule takes effect when the user has passed it.
+	TrafficQuota *TrafficQuota `protobuf:"bytes,18,opt,name=traffic_quota,json=trafficQuota,proto3" json:"traffic_quota,omitempty"`
+	// Name of this rule, for diagnostic only.
+	RuleTag string `protobuf:"bytes,19,opt,name=rule_tag,json=ruleTag,proto3" json:"rule_tag,omitempty"`
+	// Geosite entries for target domain matching, in addition to the domain list above.
+	GeositeRef []*GeoDataReference `protobuf:"bytes,20,rep,name=geosite_ref,json=geositeRef,proto3" json:"geosite_ref,omitempty"`
+	// GeoIP entries for target IP address matching, in addition to the geoip list above.
+	GeoipRef []*GeoDataReference `protobuf:"bytes,21,rep,name=geoip_ref,json=geoipRef,proto3" json:"geoip_ref,omitempty"`
+	// GeoIP entries for source IP address matching, in addition to the source_geoip list above.
+	SourceGeoipRef       []*GeoDataReference `protobuf:"bytes,22,rep,name=source_geoip_ref,json=sourceGeoipRef,proto3" json:"source_geoip_ref,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
 }
 
 func (m *RoutingRule) Reset()         { *m = RoutingRule{} }
 func (m *RoutingRule) String() string { return proto.CompactTextString(m) }
 func (*RoutingRule) ProtoMessage()    {}
 func (*RoutingRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6b1608360690c5fc, []int{6}
+	return fileDescriptor_6b1608360690c5fc, []int{10}
 }
 
 func (m *RoutingRule) XXX_Unmarshal(b []byte) error {
@@ -651,6 +927,55 @@ func (m *RoutingRule) GetAttributes() string {
 	return ""
 }
 
+func (m *RoutingRule) GetSourcePortList() *net.PortList {
+	if m != nil {
+		return m.SourcePortList
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetSchedule() *Schedule {
+	if m != nil {
+		return m.Schedule
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetTrafficQuota() *TrafficQuota {
+	if m != nil {
+		return m.TrafficQuota
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetRuleTag() string {
+	if m != nil {
+		return m.RuleTag
+	}
+	return ""
+}
+
+func (m *RoutingRule) GetGeositeRef() []*GeoDataReference {
+	if m != nil {
+		return m.GeositeRef
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetGeoipRef() []*GeoDataReference {
+	if m != nil {
+		return m.GeoipRef
+	}
+	return nil
+}
+
+func (m *RoutingRule) GetSourceGeoipRef() []*GeoDataReference {
+	if m != nil {
+		return m.SourceGeoipRef
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*RoutingRule) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -671,7 +996,7 @@ func (m *BalancingRule) Reset()         { *m = BalancingRule{} }
 func (m *BalancingRule) String() string { return proto.CompactTextString(m) }
 func (*BalancingRule) ProtoMessage()    {}
 func (*BalancingRule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6b1608360690c5fc, []int{7}
+	return fileDescriptor_6b1608360690c5fc, []int{11}
 }
 
 func (m *BalancingRule) XXX_Unmarshal(b []byte) error {
@@ -719,7 +1044,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6b1608360690c5fc, []int{8}
+	return fileDescriptor_6b1608360690c5fc, []int{12}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -763,6 +1088,7 @@ func (m *Config) GetBalancingRule() []*BalancingRule {
 
 func init() {
 	proto.RegisterEnum("v2ray.core.app.router.Domain_Type", Domain_Type_name, Domain_Type_value)
+	proto.RegisterEnum("v2ray.core.app.router.TrafficQuota_Period", TrafficQuota_Period_name, TrafficQuota_Period_value)
 	proto.RegisterEnum("v2ray.core.app.router.Config_DomainStrategy", Config_DomainStrategy_name, Config_DomainStrategy_value)
 	proto.RegisterType((*Domain)(nil), "v2ray.core.app.router.Domain")
 	proto.RegisterType((*Domain_Attribute)(nil), "v2ray.core.app.router.Domain.Attribute")
@@ -771,6 +1097,10 @@ func init() {
 	proto.RegisterType((*GeoIPList)(nil), "v2ray.core.app.router.GeoIPList")
 	proto.RegisterType((*GeoSite)(nil), "v2ray.core.app.router.GeoSite")
 	proto.RegisterType((*GeoSiteList)(nil), "v2ray.core.app.router.GeoSiteList")
+	proto.RegisterType((*TimeWindow)(nil), "v2ray.core.app.router.TimeWindow")
+	proto.RegisterType((*Schedule)(nil), "v2ray.core.app.router.Schedule")
+	proto.RegisterType((*TrafficQuota)(nil), "v2ray.core.app.router.TrafficQuota")
+	proto.RegisterType((*GeoDataReference)(nil), "v2ray.core.app.router.GeoDataReference")
 	proto.RegisterType((*RoutingRule)(nil), "v2ray.core.app.router.RoutingRule")
 	proto.RegisterType((*BalancingRule)(nil), "v2ray.core.app.router.BalancingRule")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.router.Config")
@@ -781,62 +1111,84 @@ func init() {
 }
 
 var fileDescriptor_6b1608360690c5fc = []byte{
-	// 910 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x5f, 0x8f, 0xdb, 0x44,
-	0x10, 0x3f, 0xdb, 0x49, 0x1a, 0x8f, 0x93, 0xd4, 0xac, 0x28, 0x32, 0x07, 0xd7, 0x0b, 0x56, 0xa1,
-	0x91, 0x40, 0x8e, 0x94, 0x02, 0x0f, 0x08, 0x54, 0x2e, 0xb9, 0x72, 0x17, 0x01, 0xc7, 0x69, 0xaf,
-	0xed, 0x03, 0x3c, 0x44, 0x8e, 0xb3, 0x67, 0xac, 0x3a, 0xbb, 0xab, 0xf5, 0xba, 0x34, 0x5f, 0x09,
-	0x89, 0xcf, 0xc0, 0x47, 0x03, 0xed, 0x9f, 0x24, 0x17, 0x68, 0x8e, 0x13, 0x6f, 0x3b, 0xb3, 0xbf,
-	0xf9, 0xed, 0x6f, 0x67, 0x76, 0x66, 0xe1, 0x93, 0xd7, 0x23, 0x91, 0xae, 0x92, 0x8c, 0x2d, 0x87,
-	0x19, 0x13, 0x64, 0x98, 0x72, 0x3e, 0x14, 0xac, 0x96, 0x44, 0x0c, 0x33, 0x46, 0xaf, 0x8b, 0x3c,
-	0xe1, 0x82, 0x49, 0x86, 0x1e, 0xac, 0x71, 0x82, 0x24, 0x29, 0xe7, 0x89, 0xc1, 0x1c, 0x3e, 0xfa,
-	0x47, 0x78, 0xc6, 0x96, 0x4b, 0x46, 0x87, 0x94, 0xc8, 0x21, 0x67, 0x42, 0x9a, 0xe0, 0xc3, 0xc7,
-	0xfb, 0x51, 0x94, 0xc8, 0xdf, 0x98, 0x78, 0x65, 0x80, 0xf1, 0x9f, 0x2e, 0xb4, 0x4e, 0xd9, 0x32,
-	0x2d, 0x28, 0xfa, 0x12, 0x1a, 0x72, 0xc5, 0x49, 0xe4, 0xf4, 0x9d, 0x41, 0x6f, 0x14, 0x27, 0x6f,
-	0x3d, 0x3f, 0x31, 0xe0, 0xe4, 0xf9, 0x8a, 0x13, 0xac, 0xf1, 0xe8, 0x5d, 0x68, 0xbe, 0x4e, 0xcb,
-	0x9a, 0x44, 0x6e, 0xdf, 0x19, 0xf8, 0xd8, 0x18, 0xe8, 0x19, 0xf8, 0xa9, 0x94, 0xa2, 0x98, 0xd7,
-	0x92, 0x44, 0x5e, 0xdf, 0x1b, 0x04, 0xa3, 0xc7, 0xb7, 0x53, 0x9e, 0xac, 0xe1, 0x78, 0x1b, 0x79,
-	0x58, 0x82, 0xbf, 0xf1, 0xa3, 0x10, 0xbc, 0x57, 0x64, 0xa5, 0x05, 0xfa, 0x58, 0x2d, 0xd1, 0x31,
-	0xc0, 0x9c, 0xb1, 0x72, 0xb6, 0x15, 0xd0, 0x3e, 0x3f, 0xc0, 0xbe, 0xf2, 0xbd, 0xd4, 0x32, 0x8e,
-	0xc0, 0x2f, 0xa8, 0xb4, 0xfb, 0x5e, 0xdf, 0x19, 0x78, 0xe7, 0x07, 0xb8, 0x5d, 0x50, 0xa9, 0xb7,
-	0xc7, 0x5d, 0x08, 0xd4, 0x1d, 0x16, 0x06, 0x10, 0x8f, 0xa0, 0xa1, 0x2e, 0x86, 0x7c, 0x68, 0x5e,
-	0x96, 0x69, 0x41, 0xc3, 0x03, 0xb5, 0xc4, 0x24, 0x27, 0x6f, 0x42, 0x07, 0xc1, 0x3a, 0x55, 0xa1,
-	0x8b, 0xda, 0xd0, 0xf8, 0xae, 0x2e, 0xcb, 0xd0, 0x8b, 0x13, 0x68, 0x4c, 0xa6, 0xa7, 0x18, 0xf5,
-	0xc0, 0x2d, 0xb8, 0xd6, 0xd6, 0xc1, 0x6e, 0xc1, 0xd1, 0x7b, 0xd0, 0xe2, 0x82, 0x5c, 0x17, 0x6f,
-	0xb4, 0xac, 0x2e, 0xb6, 0x56, 0xfc, 0x0b, 0x34, 0xcf, 0x08, 0x9b, 0x5e, 0xa2, 0x8f, 0xa0, 0x93,
-	0xb1, 0x9a, 0x4a, 0xb1, 0x9a, 0x65, 0x6c, 0x41, 0xec, 0xb5, 0x02, 0xeb, 0x9b, 0xb0, 0x05, 0x41,
-	0x43, 0x68, 0x64, 0xc5, 0x42, 0x44, 0xae, 0xce, 0xdf, 0x07, 0x7b, 0xf2, 0xa7, 0x8e, 0xc7, 0x1a,
-	0x18, 0x3f, 0x05, 0x5f, 0x93, 0xff, 0x50, 0x54, 0x12, 0x8d, 0xa0, 0x49, 0x14, 0x55, 0xe4, 0xe8,
-	0xf0, 0x0f, 0xf7, 0x84, 0xeb, 0x00, 0x6c, 0xa0, 0x71, 0x06, 0xf7, 0xce, 0x08, 0xbb, 0x2a, 0x24,
-	0xb9, 0x8b, 0xbe, 0x2f, 0xa0, 0xb5, 0xd0, 0x19, 0xb1, 0x0a, 0x8f, 0x6e, 0xad, 0x30, 0xb6, 0xe0,
-	0x78, 0x02, 0x81, 0x3d, 0x44, 0xeb, 0xfc, 0x7c, 0x57, 0xe7, 0xc3, 0xfd, 0x3a, 0x55, 0xc8, 0x5a,
-	0xe9, 0x5f, 0x4d, 0x08, 0x30, 0xab, 0x65, 0x41, 0x73, 0x5c, 0x97, 0x04, 0x21, 0xf0, 0x64, 0x9a,
-	0x1b, 0x95, 0xe7, 0x07, 0x58, 0x19, 0xe8, 0x63, 0xe8, 0xce, 0xd3, 0x32, 0xa5, 0x59, 0x41, 0xf3,
-	0x99, 0xda, 0xed, 0xd8, 0xdd, 0xce, 0xc6, 0xfd, 0x3c, 0xcd, 0xff, 0xe7, 0x35, 0xd0, 0x13, 0x5b,
-	0x1d, 0xef, 0x3f, 0xab, 0x33, 0x76, 0x23, 0xc7, 0x54, 0x48, 0x15, 0x25, 0x27, 0xac, 0xe0, 0x11,
-	0xdc, 0xa5, 0x28, 0x1a, 0x8a, 0x26, 0x00, 0xaa, 0xb7, 0x67, 0x22, 0xa5, 0x39, 0x89, 0x1a, 0x7d,
-	0x67, 0x10, 0x8c, 0xfa, 0x37, 0x03, 0x4d, 0x7b, 0x27, 0x94, 0xc8, 0xe4, 0x92, 0x09, 0x89, 0x15,
-	0x4e, 0x9f, 0xe9, 0xf3, 0xb5, 0x89, 0xbe, 0x06, 0x6d, 0xcc, 0xca, 0xa2, 0x92, 0x51, 0x4f, 0x73,
-	0x1c, 0xdf, 0xc2, 0xa1, 0x2a, 0x83, 0xdb, 0xdc, 0xae, 0xd0, 0x14, 0x3a, 0x76, 0x70, 0x18, 0x82,
-	0xa6, 0x26, 0x88, 0xf7, 0x10, 0x5c, 0x18, 0xa8, 0x8a, 0xd4, 0x32, 0x02, 0xba, 0x75, 0xa0, 0xaf,
-	0xa0, 0x6d, 0xcd, 0x2a, 0xea, 0xf6, 0xbd, 0x41, 0x6f, 0xb7, 0xe2, 0xff, 0xa6, 0xc1, 0x1b, 0x3c,
-	0xfa, 0x16, 0x82, 0x8a, 0xd5, 0x22, 0x23, 0x33, 0x9d, 0xf9, 0xd6, 0xdd, 0x32, 0x0f, 0x26, 0x66,
-	0xa2, 0xf2, 0xff, 0x14, 0x3a, 0x96, 0xc1, 0x94, 0x21, 0xb8, 0x43, 0x19, 0xec, 0x99, 0x67, 0xba,
-	0x18, 0x47, 0x00, 0x75, 0x45, 0xc4, 0x8c, 0x2c, 0xd3, 0xa2, 0x8c, 0xee, 0xf5, 0xbd, 0x81, 0x8f,
-	0x7d, 0xe5, 0x79, 0xa6, 0x1c, 0xe8, 0x18, 0x82, 0x82, 0xce, 0x59, 0x4d, 0x17, 0xfa, 0xc1, 0xb5,
-	0xf5, 0x3e, 0x58, 0x97, 0x7a, 0x6c, 0x87, 0xd0, 0xd6, 0xa3, 0x37, 0x63, 0x65, 0xe4, 0xeb, 0xdd,
-	0x8d, 0x8d, 0x1e, 0x02, 0x6c, 0x46, 0x5f, 0x15, 0xdd, 0xd7, 0x0d, 0x77, 0xc3, 0x33, 0xee, 0x00,
-	0xc8, 0x54, 0xe4, 0x44, 0x2a, 0xee, 0xf8, 0x02, 0xba, 0xe3, 0xf5, 0x33, 0xd6, 0x2d, 0x10, 0xde,
-	0x68, 0x01, 0xd3, 0x00, 0x9f, 0xc2, 0x3b, 0xac, 0x96, 0x46, 0x4e, 0x45, 0x4a, 0x92, 0x49, 0x66,
-	0xa6, 0x89, 0x8f, 0xc3, 0xf5, 0xc6, 0x95, 0xf5, 0xc7, 0x7f, 0xb8, 0xd0, 0x9a, 0xe8, 0x2f, 0x08,
-	0xbd, 0x80, 0xfb, 0xe6, 0x91, 0xcf, 0x2a, 0x29, 0x52, 0x49, 0xf2, 0x95, 0xfd, 0x16, 0x3e, 0xdb,
-	0x97, 0x6b, 0xf3, 0x75, 0x99, 0x0e, 0xb9, 0xb2, 0x31, 0xb8, 0xb7, 0xd8, 0xb1, 0xd5, 0x17, 0x23,
-	0xea, 0x92, 0xd8, 0x36, 0xdb, 0xf7, 0xc5, 0xdc, 0xe8, 0x6a, 0xac, 0xf1, 0xe8, 0x7b, 0xe8, 0x6d,
-	0xfb, 0x58, 0x33, 0x98, 0x9e, 0x7b, 0xb4, 0x87, 0x61, 0x27, 0x2d, 0x78, 0x3b, 0x03, 0x94, 0x19,
-	0x9f, 0x41, 0x6f, 0x57, 0xa6, 0x1a, 0xe6, 0x27, 0xd5, 0xb4, 0x32, 0xd3, 0xfe, 0x45, 0x45, 0xa6,
-	0x3c, 0x74, 0x50, 0x08, 0x9d, 0x29, 0x9f, 0x5e, 0x5f, 0x30, 0xfa, 0x63, 0x2a, 0xb3, 0x5f, 0x43,
-	0x17, 0xf5, 0x00, 0xa6, 0xfc, 0x27, 0x7a, 0x4a, 0x96, 0x29, 0x5d, 0x84, 0xde, 0xf8, 0x1b, 0x78,
-	0x3f, 0x63, 0xcb, 0xb7, 0x4b, 0xb8, 0x74, 0x7e, 0x6e, 0x99, 0xd5, 0xef, 0xee, 0x83, 0x97, 0x23,
-	0x9c, 0xae, 0x92, 0x89, 0x42, 0x9c, 0x70, 0xae, 0xef, 0x47, 0xc4, 0xbc, 0xa5, 0xcb, 0xfe, 0xe4,
-	0xef, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7b, 0x3e, 0x38, 0x1b, 0x11, 0x08, 0x00, 0x00,
+	// 1253 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x7d, 0x6f, 0x1b, 0xc5,
+	0x13, 0x8e, 0x7d, 0x8e, 0xe3, 0x1b, 0xbf, 0xf4, 0xba, 0xbf, 0xb6, 0xba, 0xe6, 0xd7, 0x17, 0xf7,
+	0x28, 0x34, 0x02, 0x64, 0x4b, 0x2e, 0x20, 0x51, 0x40, 0xa5, 0x71, 0x4a, 0x62, 0x41, 0x43, 0xba,
+	0x49, 0x5b, 0x09, 0xfe, 0xb0, 0x36, 0x77, 0x63, 0x67, 0x95, 0xf3, 0xed, 0x71, 0xb7, 0xd7, 0xd4,
+	0x7c, 0x24, 0xbe, 0x03, 0xe2, 0x23, 0x20, 0xbe, 0x11, 0xda, 0x17, 0xbf, 0xa4, 0xd4, 0x21, 0xe5,
+	0xbf, 0x9d, 0xd9, 0x79, 0x9e, 0x9d, 0x99, 0x9d, 0x7d, 0xee, 0xe0, 0xa3, 0xd7, 0xbd, 0x8c, 0x4d,
+	0x3b, 0xa1, 0x98, 0x74, 0x43, 0x91, 0x61, 0x97, 0xa5, 0x69, 0x37, 0x13, 0x85, 0xc4, 0xac, 0x1b,
+	0x8a, 0x64, 0xc4, 0xc7, 0x9d, 0x34, 0x13, 0x52, 0x90, 0xeb, 0xb3, 0xb8, 0x0c, 0x3b, 0x2c, 0x4d,
+	0x3b, 0x26, 0x66, 0xf3, 0xfe, 0x5b, 0xf0, 0x50, 0x4c, 0x26, 0x22, 0xe9, 0x26, 0x28, 0xbb, 0xa9,
+	0xc8, 0xa4, 0x01, 0x6f, 0x3e, 0x58, 0x1d, 0x95, 0xa0, 0x3c, 0x13, 0xd9, 0xa9, 0x09, 0x0c, 0xfe,
+	0x28, 0x43, 0x75, 0x47, 0x4c, 0x18, 0x4f, 0xc8, 0x17, 0x50, 0x91, 0xd3, 0x14, 0xfd, 0x52, 0xbb,
+	0xb4, 0xd5, 0xea, 0x05, 0x9d, 0x77, 0x9e, 0xdf, 0x31, 0xc1, 0x9d, 0xa3, 0x69, 0x8a, 0x54, 0xc7,
+	0x93, 0x6b, 0xb0, 0xfe, 0x9a, 0xc5, 0x05, 0xfa, 0xe5, 0x76, 0x69, 0xcb, 0xa5, 0xc6, 0x20, 0x4f,
+	0xc1, 0x65, 0x52, 0x66, 0xfc, 0xb8, 0x90, 0xe8, 0x3b, 0x6d, 0x67, 0xab, 0xde, 0x7b, 0x70, 0x31,
+	0xe5, 0x93, 0x59, 0x38, 0x5d, 0x20, 0x37, 0x63, 0x70, 0xe7, 0x7e, 0xe2, 0x81, 0x73, 0x8a, 0x53,
+	0x9d, 0xa0, 0x4b, 0xd5, 0x92, 0xdc, 0x05, 0x38, 0x16, 0x22, 0x1e, 0x2e, 0x12, 0xa8, 0xed, 0xad,
+	0x51, 0x57, 0xf9, 0x5e, 0xea, 0x34, 0x6e, 0x83, 0xcb, 0x13, 0x69, 0xf7, 0x9d, 0x76, 0x69, 0xcb,
+	0xd9, 0x5b, 0xa3, 0x35, 0x9e, 0x48, 0xbd, 0xbd, 0xdd, 0x84, 0xba, 0xaa, 0x21, 0x32, 0x01, 0x41,
+	0x0f, 0x2a, 0xaa, 0x30, 0xe2, 0xc2, 0xfa, 0x41, 0xcc, 0x78, 0xe2, 0xad, 0xa9, 0x25, 0xc5, 0x31,
+	0xbe, 0xf1, 0x4a, 0x04, 0x66, 0xad, 0xf2, 0xca, 0xa4, 0x06, 0x95, 0xef, 0x8a, 0x38, 0xf6, 0x9c,
+	0xa0, 0x03, 0x95, 0xfe, 0x60, 0x87, 0x92, 0x16, 0x94, 0x79, 0xaa, 0x73, 0x6b, 0xd0, 0x32, 0x4f,
+	0xc9, 0x0d, 0xa8, 0xa6, 0x19, 0x8e, 0xf8, 0x1b, 0x9d, 0x56, 0x93, 0x5a, 0x2b, 0xf8, 0x19, 0xd6,
+	0x77, 0x51, 0x0c, 0x0e, 0xc8, 0x3d, 0x68, 0x84, 0xa2, 0x48, 0x64, 0x36, 0x1d, 0x86, 0x22, 0x42,
+	0x5b, 0x56, 0xdd, 0xfa, 0xfa, 0x22, 0x42, 0xd2, 0x85, 0x4a, 0xc8, 0xa3, 0xcc, 0x2f, 0xeb, 0xfe,
+	0xfd, 0x7f, 0x45, 0xff, 0xd4, 0xf1, 0x54, 0x07, 0x06, 0x8f, 0xc1, 0xd5, 0xe4, 0x3f, 0xf0, 0x5c,
+	0x92, 0x1e, 0xac, 0xa3, 0xa2, 0xf2, 0x4b, 0x1a, 0x7e, 0x6b, 0x05, 0x5c, 0x03, 0xa8, 0x09, 0x0d,
+	0x42, 0xd8, 0xd8, 0x45, 0x71, 0xc8, 0x25, 0x5e, 0x26, 0xbf, 0xcf, 0xa1, 0x1a, 0xe9, 0x8e, 0xd8,
+	0x0c, 0x6f, 0x5f, 0x78, 0xc3, 0xd4, 0x06, 0x07, 0x7d, 0xa8, 0xdb, 0x43, 0x74, 0x9e, 0x9f, 0x9d,
+	0xcf, 0xf3, 0xce, 0xea, 0x3c, 0x15, 0x64, 0x96, 0xe9, 0x3e, 0xc0, 0x11, 0x9f, 0xe0, 0x2b, 0x9e,
+	0x44, 0xe2, 0x8c, 0xf8, 0xb0, 0x71, 0x86, 0x78, 0x1a, 0x31, 0xc3, 0xd2, 0xa4, 0x33, 0x53, 0x8d,
+	0x67, 0x2e, 0x59, 0x26, 0xed, 0x35, 0x18, 0x43, 0x8d, 0x12, 0x26, 0x91, 0x9e, 0x88, 0x26, 0x55,
+	0xcb, 0x80, 0x41, 0xed, 0x30, 0x3c, 0xc1, 0xa8, 0x88, 0x91, 0x6c, 0x42, 0x4d, 0xf2, 0x09, 0xfe,
+	0x2a, 0x92, 0x59, 0xd9, 0x73, 0x9b, 0x7c, 0x09, 0xd5, 0x33, 0x7d, 0xa6, 0xad, 0xf9, 0xde, 0x8a,
+	0x74, 0x17, 0xc9, 0x51, 0x0b, 0x08, 0xfe, 0x2a, 0x41, 0xe3, 0x28, 0x63, 0xa3, 0x11, 0x0f, 0x9f,
+	0x17, 0x42, 0x32, 0x72, 0x0b, 0x5c, 0x79, 0x92, 0x61, 0x7e, 0x22, 0xe2, 0x48, 0x1f, 0x54, 0xa1,
+	0x0b, 0x07, 0xd9, 0x86, 0x6a, 0x8a, 0x19, 0x17, 0x91, 0x4e, 0xbd, 0xd5, 0xfb, 0x78, 0xd5, 0x49,
+	0x4b, 0x94, 0x9d, 0x03, 0x8d, 0xa0, 0x16, 0x79, 0xae, 0x12, 0xe7, 0x7c, 0x25, 0x41, 0x1f, 0xaa,
+	0x26, 0x5a, 0x0d, 0xf9, 0x91, 0x90, 0x2c, 0x36, 0xf3, 0xbe, 0xc3, 0x78, 0x3c, 0x35, 0xf3, 0xfe,
+	0x0a, 0xf1, 0x34, 0x9e, 0x7a, 0x65, 0x52, 0x87, 0x8d, 0x67, 0x22, 0x91, 0x27, 0xf1, 0xd4, 0x73,
+	0xd4, 0xc6, 0x81, 0x88, 0x79, 0x38, 0xf5, 0x2a, 0xc1, 0x23, 0xf0, 0x76, 0x51, 0xec, 0x30, 0xc9,
+	0x28, 0x8e, 0x30, 0xc3, 0x24, 0x44, 0x42, 0xa0, 0x32, 0xe2, 0xf1, 0xac, 0x75, 0x7a, 0xad, 0x7c,
+	0x7a, 0x8a, 0x8c, 0x48, 0xe8, 0x75, 0xf0, 0xa7, 0x0b, 0x75, 0x2a, 0x0a, 0xc9, 0x93, 0x31, 0x2d,
+	0x74, 0x8c, 0x23, 0xd9, 0xd8, 0xc0, 0xf6, 0xd6, 0xa8, 0x32, 0xc8, 0x87, 0xd0, 0x3c, 0x66, 0x31,
+	0x4b, 0x42, 0x9e, 0x8c, 0x87, 0x6a, 0xb7, 0x61, 0x77, 0x1b, 0x73, 0xf7, 0x11, 0x1b, 0xff, 0xc7,
+	0x49, 0x24, 0x0f, 0xed, 0x03, 0x73, 0xfe, 0xf5, 0x81, 0x6d, 0x97, 0xfd, 0x92, 0x79, 0x64, 0xea,
+	0x5d, 0x8d, 0x51, 0xf0, 0xd4, 0x87, 0xcb, 0xbc, 0x2b, 0x1d, 0x4a, 0xfa, 0x00, 0x4a, 0x9e, 0x87,
+	0x19, 0x4b, 0xc6, 0xe8, 0x57, 0xda, 0xa5, 0xad, 0x7a, 0xaf, 0xbd, 0x0c, 0x34, 0x0a, 0xdd, 0x49,
+	0x50, 0x76, 0x0e, 0x44, 0x26, 0xa9, 0x8a, 0xd3, 0x67, 0xba, 0xe9, 0xcc, 0x24, 0x5f, 0x83, 0x36,
+	0x86, 0x31, 0xcf, 0xa5, 0xdf, 0xd2, 0x1c, 0x77, 0x2f, 0xe0, 0x50, 0x8f, 0x8b, 0xd6, 0x52, 0xbb,
+	0x22, 0x03, 0x68, 0x58, 0xed, 0x37, 0x04, 0xeb, 0x9a, 0x20, 0x58, 0x41, 0xb0, 0x6f, 0x42, 0x15,
+	0x52, 0xa7, 0x51, 0x4f, 0x16, 0x0e, 0xf2, 0x08, 0x6a, 0xd6, 0xcc, 0xfd, 0x66, 0xdb, 0xd9, 0x6a,
+	0xf5, 0xee, 0x5c, 0x4c, 0x43, 0xe7, 0xf1, 0xe4, 0x5b, 0xa8, 0xe7, 0xa2, 0xc8, 0x42, 0x1c, 0xea,
+	0xce, 0x57, 0x2f, 0xd7, 0x79, 0x30, 0x98, 0xbe, 0xea, 0xff, 0x63, 0x68, 0x58, 0x06, 0x73, 0x0d,
+	0xf5, 0x4b, 0x5c, 0x83, 0x3d, 0x73, 0x57, 0x5f, 0xc6, 0x6d, 0x80, 0x22, 0xc7, 0x6c, 0x88, 0x13,
+	0xc6, 0x63, 0x7f, 0xa3, 0xed, 0x6c, 0xb9, 0xd4, 0x55, 0x9e, 0xa7, 0xca, 0x41, 0xee, 0x42, 0x9d,
+	0x27, 0xc7, 0xa2, 0x48, 0x22, 0x3d, 0x70, 0x35, 0xbd, 0x0f, 0xd6, 0xa5, 0x86, 0x6d, 0x13, 0x6a,
+	0xfa, 0xeb, 0x19, 0x8a, 0xd8, 0x77, 0xf5, 0xee, 0xdc, 0x26, 0x77, 0x00, 0xe6, 0x5f, 0xaf, 0xdc,
+	0xbf, 0xa2, 0xa7, 0x7d, 0xc9, 0x43, 0x06, 0xe0, 0xd9, 0xe4, 0x17, 0x57, 0xe9, 0x5d, 0xee, 0x2a,
+	0x5b, 0x06, 0x38, 0xb3, 0xc9, 0x57, 0x50, 0xcb, 0xad, 0x62, 0xf9, 0x57, 0xff, 0x49, 0xb1, 0xd4,
+	0x83, 0x99, 0xb0, 0xd1, 0x39, 0x80, 0xec, 0x41, 0x53, 0x1a, 0xdd, 0x18, 0xfe, 0xa2, 0x84, 0xc3,
+	0x27, 0x9a, 0xe1, 0x83, 0x4b, 0x68, 0x0c, 0x6d, 0xc8, 0x25, 0x8b, 0xdc, 0x84, 0x5a, 0x56, 0xc4,
+	0xa8, 0x7b, 0xf5, 0x3f, 0x5d, 0xef, 0x86, 0xb2, 0x55, 0xa3, 0xf6, 0xa0, 0x3e, 0x46, 0x91, 0x73,
+	0x89, 0xc3, 0x0c, 0x47, 0xfe, 0xb5, 0x0b, 0x7f, 0x03, 0xde, 0x96, 0x11, 0x0a, 0x16, 0x4b, 0x71,
+	0x44, 0x76, 0xc0, 0xd5, 0x97, 0xad, 0x79, 0xae, 0xbf, 0x1f, 0x4f, 0x4d, 0x23, 0x15, 0xcb, 0xf3,
+	0x79, 0xf3, 0x17, 0x64, 0x37, 0xde, 0x8f, 0xac, 0xb5, 0x34, 0x48, 0x14, 0x47, 0xdb, 0x0d, 0x00,
+	0xc9, 0xb2, 0x31, 0x4a, 0x55, 0x7f, 0xb0, 0x0f, 0xcd, 0xed, 0x99, 0x2c, 0x69, 0x49, 0xf3, 0x96,
+	0x24, 0xcd, 0x08, 0xda, 0x27, 0x70, 0x55, 0x14, 0xd2, 0x8c, 0x57, 0x8e, 0x31, 0x86, 0x52, 0x98,
+	0x0f, 0xbc, 0x4b, 0xbd, 0xd9, 0xc6, 0xa1, 0xf5, 0x07, 0xbf, 0x97, 0xa1, 0xda, 0xd7, 0x7f, 0x85,
+	0xe4, 0x05, 0x5c, 0x31, 0xa2, 0x35, 0xcc, 0x65, 0xc6, 0x24, 0x8e, 0xa7, 0xf6, 0x4f, 0xed, 0xd3,
+	0x55, 0x6f, 0x47, 0xe3, 0xac, 0xe2, 0x1d, 0x5a, 0x0c, 0x6d, 0x45, 0xe7, 0x6c, 0xf5, 0xd7, 0xa7,
+	0x6e, 0xcb, 0xca, 0xe6, 0xaa, 0xbf, 0xbe, 0x25, 0x95, 0xa6, 0x3a, 0x9e, 0x7c, 0x0f, 0xad, 0x85,
+	0x2e, 0x6b, 0x06, 0xa3, 0xa1, 0xf7, 0x57, 0x30, 0x9c, 0x6b, 0x0b, 0x6d, 0x1e, 0x2f, 0x9b, 0xc1,
+	0x2b, 0x68, 0x9d, 0x4f, 0x53, 0xfd, 0x5f, 0x3d, 0xc9, 0x07, 0xb9, 0xf9, 0x20, 0xbd, 0xc8, 0x71,
+	0x90, 0x7a, 0x25, 0xe2, 0x41, 0x63, 0x90, 0x0e, 0x46, 0xfb, 0x22, 0x79, 0xc6, 0x64, 0x78, 0xe2,
+	0x95, 0x49, 0x0b, 0x60, 0x90, 0xfe, 0x98, 0xec, 0xe0, 0x84, 0x25, 0x91, 0xe7, 0x18, 0x7b, 0x30,
+	0xea, 0x33, 0x35, 0xe6, 0x5e, 0x65, 0xfb, 0x1b, 0xb8, 0x19, 0x8a, 0xc9, 0xbb, 0x53, 0x3a, 0x28,
+	0xfd, 0x54, 0x35, 0xab, 0xdf, 0xca, 0xd7, 0x5f, 0xf6, 0x28, 0x9b, 0x76, 0xfa, 0x2a, 0xe2, 0x49,
+	0x9a, 0xea, 0x7a, 0x31, 0x3b, 0xae, 0xea, 0x67, 0xfd, 0xf0, 0xef, 0x01, 0x00, 0x6c, 0x0e, 0x9a,
+	0x6c, 0xb4, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,293 @@
+package router
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/platform/filesystem"
+)
+
+// geoDataLoader loads entries from geodata files in asset location. Each file is read at most once per loader.
+// Files in compact format are memory-mapped and kept in compact, and GeoIP matchers are kept in geoIPMatchers, which
+// are shared by all loaders unless the loader is isolated.
+type geoDataLoader struct {
+	geoip   map[string]*GeoIPList
+	geosite map[string]*GeoSiteList
+
+	compact       *compactGeoDataContainer
+	geoIPMatchers *GeoIPMatcherContainer
+}
+
+func newGeoDataLoader() *geoDataLoader {
+	return &geoDataLoader{
+		geoip:         make(map[string]*GeoIPList),
+		geosite:       make(map[string]*GeoSiteList),
+		compact:       &globalCompactGeoData,
+		geoIPMatchers: &globalGeoIPContainer,
+	}
+}
+
+// newIsolatedGeoDataLoader returns a loader that reads all files again, and doesn't share matchers with other loaders.
+func newIsolatedGeoDataLoader() *geoDataLoader {
+	return &geoDataLoader{
+		geoip:         make(map[string]*GeoIPList),
+		geosite:       make(map[string]*GeoSiteList),
+		compact:       new(compactGeoDataContainer),
+		geoIPMatchers: new(GeoIPMatcherContainer),
+	}
+}
+
+// share makes files and matchers of the isolated loader shared by loaders created later.
+func (l *geoDataLoader) share() {
+	globalCompactGeoData.replace(l.compact)
+	globalGeoIPContainer.replace(l.geoIPMatchers)
+}
+
+func (l *geoDataLoader) loadGeoIPList(file string) (*GeoIPList, error) {
+	if list, found := l.geoip[file]; found {
+		return list, nil
+	}
+	geoipBytes, err := filesystem.ReadAsset(file)
+	if err != nil {
+		return nil, newError("failed to open file: ", file).Base(err)
+	}
+	list := new(GeoIPList)
+	if err := proto.Unmarshal(geoipBytes, list); err != nil {
+		return nil, newError("failed to parse file: ", file).Base(err)
+	}
+	l.geoip[file] = list
+	return list, nil
+}
+
+func (l *geoDataLoader) loadGeoSiteList(file string) (*GeoSiteList, error) {
+	if list, found := l.geosite[file]; found {
+		return list, nil
+	}
+	geositeBytes, err := filesystem.ReadAsset(file)
+	if err != nil {
+		return nil, newError("failed to open file: ", file).Base(err)
+	}
+	list := new(GeoSiteList)
+	if err := proto.Unmarshal(geositeBytes, list); err != nil {
+		return nil, newError("failed to parse file: ", file).Base(err)
+	}
+	l.geosite[file] = list
+	return list, nil
+}
+
+// LoadGeoIP returns the GeoIP entry of the given reference. The country code of the returned GeoIP is the code in reference,
+// prefixed by file name if the file is not geoip.dat. Entries in compact geodata are expanded into CIDRs, so callers that
+// only match IPs should use loadGeoIPMatcher instead.
+func (l *geoDataLoader) LoadGeoIP(ref *GeoDataReference) (*GeoIP, error) {
+	geoip, compact, err := l.loadGeoIPMatcher(ref)
+	if err != nil {
+		return nil, err
+	}
+	if compact != nil {
+		return &GeoIP{
+			CountryCode: geoIPCode(ref),
+			Cidr:        compact.CIDRs(),
+		}, nil
+	}
+	return geoip, nil
+}
+
+// loadGeoIPMatcher returns the shared matcher of the given reference if it is in compact geodata, which matches IPs
+// in the memory-mapped ranges directly. Otherwise it returns the GeoIP entry in the same form as LoadGeoIP.
+func (l *geoDataLoader) loadGeoIPMatcher(ref *GeoDataReference) (*GeoIP, *CompactGeoIPMatcher, error) {
+	code := strings.ToUpper(ref.Code)
+	gd, err := l.compact.Load(ref.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	if gd != nil {
+		m, err := gd.geoIPMatcher(code)
+		if err != nil {
+			return nil, nil, newError("failed to load ", ref.File).Base(err)
+		}
+		return nil, m, nil
+	}
+
+	list, err := l.loadGeoIPList(ref.File)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, geoip := range list.Entry {
+		if geoip.CountryCode == code {
+			return &GeoIP{
+				CountryCode: geoIPCode(ref),
+				Cidr:        geoip.Cidr,
+			}, nil, nil
+		}
+	}
+	return nil, nil, newError("country not found in ", ref.File, ": ", code)
+}
+
+func geoIPCode(ref *GeoDataReference) string {
+	if ref.File != "geoip.dat" {
+		return strings.ToUpper(ref.File + "_" + ref.Code)
+	}
+	return strings.ToUpper(ref.Code)
+}
+
+// LoadGeoSite returns domains of the given reference. The code may be followed by attributes, such as "CN@ads",
+// in which case only domains with all the attributes are returned.
+func (l *geoDataLoader) LoadGeoSite(ref *GeoDataReference) ([]*Domain, error) {
+	parts := strings.Split(ref.Code, "@")
+	country := strings.ToUpper(parts[0])
+	attrs := parseDomainAttrs(parts[1:])
+
+	gd, err := l.compact.Load(ref.File)
+	if err != nil {
+		return nil, err
+	}
+	if gd != nil {
+		return loadCompactGeoSite(gd, country, attrs)
+	}
+
+	list, err := l.loadGeoSiteList(ref.File)
+	if err != nil {
+		return nil, err
+	}
+
+	var domains []*Domain
+	found := false
+	for _, site := range list.Entry {
+		if site.CountryCode == country {
+			domains = site.Domain
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, newError("country not found in ", ref.File, ": ", country)
+	}
+
+	if len(attrs) == 0 {
+		return domains, nil
+	}
+
+	for _, attr := range attrs {
+		if !domainAttrs([]string{attr}).MatchAny(domains) {
+			return nil, newAttributeNotFoundError(attr, country)
+		}
+	}
+
+	filteredDomains := make([]*Domain, 0, len(domains))
+	for _, domain := range domains {
+		if attrs.Match(domain) {
+			filteredDomains = append(filteredDomains, domain)
+		}
+	}
+	return filteredDomains, nil
+}
+
+// loadCompactGeoSite returns domains of the country with all the attributes in compact geodata.
+// Compact geodata has entries of single attribute, so the entries of each attribute are intersected.
+func loadCompactGeoSite(gd *compactGeoData, country string, attrs domainAttrs) ([]*Domain, error) {
+	m, err := gd.geoSiteMatcher(country)
+	if err != nil {
+		return nil, err
+	}
+	domains := m.Domains()
+
+	for _, attr := range attrs {
+		code := country + "@" + strings.ToUpper(attr)
+		if _, found := gd.entry(compactKindGeoSite, code); !found {
+			return nil, newAttributeNotFoundError(attr, country)
+		}
+		m, err := gd.geoSiteMatcher(code)
+		if err != nil {
+			return nil, err
+		}
+		included := make(map[Domain_Type]map[string]bool)
+		for _, d := range m.Domains() {
+			if included[d.Type] == nil {
+				included[d.Type] = make(map[string]bool)
+			}
+			included[d.Type][d.Value] = true
+		}
+		filtered := domains[:0]
+		for _, d := range domains {
+			if included[d.Type][d.Value] {
+				filtered = append(filtered, d)
+			}
+		}
+		domains = filtered
+	}
+	return domains, nil
+}
+
+// domainAttrs is a list of attribute keys that a domain must all have.
+type domainAttrs []string
+
+func parseDomainAttrs(attrs []string) domainAttrs {
+	al := make(domainAttrs, 0, len(attrs))
+	for _, attr := range attrs {
+		al = append(al, strings.ToLower(attr))
+	}
+	return al
+}
+
+func (al domainAttrs) Match(domain *Domain) bool {
+	for _, key := range al {
+		found := false
+		for _, attr := range domain.Attribute {
+			if attr.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchAny returns true if any of the domains has all the attributes.
+func (al domainAttrs) MatchAny(domains []*Domain) bool {
+	for _, domain := range domains {
+		if al.Match(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAttributeNotFoundError returns the error for an attribute that no domain in the geosite has.
+func newAttributeNotFoundError(attr string, code string) error {
+	return newError("attribute ", strings.ToLower(attr), " not found in geosite ", strings.ToUpper(code))
+}
+
+// LoadGeoIP loads the GeoIP entry of the given code from a geoip file in asset location.
+func LoadGeoIP(file, code string) (*GeoIP, error) {
+	return newGeoDataLoader().LoadGeoIP(&GeoDataReference{File: file, Code: code})
+}
+
+// LoadGeoSite loads domains of the given code from a geosite file in asset location. See GeoDataReference for the format of code.
+func LoadGeoSite(file, code string) ([]*Domain, error) {
+	return newGeoDataLoader().LoadGeoSite(&GeoDataReference{File: file, Code: code})
+}
+
+// CheckGeoDataReferences returns an error if any geodata reference of the rules can't be resolved,
+// i.e., the file doesn't exist or the code is not found in the file.
+func CheckGeoDataReferences(rules []*RoutingRule) error {
+	loader := newGeoDataLoader()
+	for _, rule := range rules {
+		for _, ref := range rule.GeositeRef {
+			if _, err := loader.LoadGeoSite(ref); err != nil {
+				return newError("failed to load sites: ", ref.Code, " from ", ref.File).Base(err)
+			}
+		}
+		for _, refs := range [][]*GeoDataReference{rule.GeoipRef, rule.SourceGeoipRef} {
+			for _, ref := range refs {
+				if _, _, err := loader.loadGeoIPMatcher(ref); err != nil {
+					return newError("failed to load IPs: ", ref.Code, " from ", ref.File).Base(err)
+				}
+			}
+		}
+	}
+	return nil
+}
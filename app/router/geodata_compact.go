@@ -0,0 +1,683 @@
+package router
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/bits"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/platform/filesystem"
+	"v2ray.com/core/common/strmatcher"
+)
+
+// The compact geodata format stores GeoIP entries as sorted, merged IP ranges, and GeoSite entries as sets in a
+// shared domain graph (see strmatcher.DomainGraph), so that entries can be queried in place from a memory-mapped file.
+//
+// The layout, in little endian, is:
+//
+//	8 bytes magic, uint32 version
+//	uint32 graph offset, uint32 graph length
+//	uint32 entry count, followed by entries:
+//	  uint8 kind, uint8 code length, code, uint32 payload offset, uint32 payload length
+//	payloads
+//
+// GeoIP payload:
+//
+//	uint32 IPv4 range count, followed by ranges: uint32 start, uint32 end
+//	uint32 IPv6 range count, followed by ranges: uint64 start high, uint64 start low, uint64 end high, uint64 end low
+//
+// GeoSite payload:
+//
+//	uint32 root of domain set in graph
+//	uint32 pattern count, followed by Plain and Regex patterns: uint8 domain type, uint16 length, pattern
+//
+// A GeoSite entry with code "CN" also has entries "CN@ATTR" for each attribute in its domains.
+const (
+	compactGeoDataMagic   = "V2GEODAT"
+	compactGeoDataVersion = 1
+
+	compactKindGeoIP   = 1
+	compactKindGeoSite = 2
+)
+
+// IsCompactGeoData returns true if the given data is in compact geodata format.
+func IsCompactGeoData(data []byte) bool {
+	return len(data) >= len(compactGeoDataMagic) && string(data[:len(compactGeoDataMagic)]) == compactGeoDataMagic
+}
+
+type uint128 struct {
+	hi uint64
+	lo uint64
+}
+
+func (a uint128) less(b uint128) bool {
+	return a.hi < b.hi || (a.hi == b.hi && a.lo < b.lo)
+}
+
+func (a uint128) add(b uint128) (uint128, bool) {
+	lo, carry := bits.Add64(a.lo, b.lo, 0)
+	hi, carry := bits.Add64(a.hi, b.hi, carry)
+	return uint128{hi: hi, lo: lo}, carry != 0
+}
+
+func (a uint128) trailingZeros() int {
+	if a.lo != 0 {
+		return bits.TrailingZeros64(a.lo)
+	}
+	return 64 + bits.TrailingZeros64(a.hi)
+}
+
+// mask128 returns a uint128 with the lowest n bits set.
+func mask128(n int) uint128 {
+	switch {
+	case n <= 0:
+		return uint128{}
+	case n < 64:
+		return uint128{lo: 1<<uint(n) - 1}
+	case n < 128:
+		return uint128{hi: 1<<uint(n-64) - 1, lo: ^uint64(0)}
+	default:
+		return uint128{hi: ^uint64(0), lo: ^uint64(0)}
+	}
+}
+
+func ipToUint128(ip []byte) uint128 {
+	if len(ip) == 4 {
+		return uint128{lo: uint64(binary.BigEndian.Uint32(ip))}
+	}
+	return uint128{hi: binary.BigEndian.Uint64(ip[0:8]), lo: binary.BigEndian.Uint64(ip[8:16])}
+}
+
+func uint128ToIP(v uint128, size int) []byte {
+	ip := make([]byte, size)
+	if size == 4 {
+		binary.BigEndian.PutUint32(ip, uint32(v.lo))
+	} else {
+		binary.BigEndian.PutUint64(ip[0:8], v.hi)
+		binary.BigEndian.PutUint64(ip[8:16], v.lo)
+	}
+	return ip
+}
+
+type ipRange struct {
+	start uint128
+	end   uint128
+}
+
+// cidrsToRanges converts CIDRs of one IP size into sorted, non-overlapping and non-adjacent ranges.
+func cidrsToRanges(cidrs []*CIDR, size int) []ipRange {
+	var ranges []ipRange
+	for _, cidr := range cidrs {
+		if len(cidr.Ip) != size {
+			continue
+		}
+		hostBits := size*8 - int(cidr.Prefix)
+		if hostBits < 0 {
+			hostBits = 0
+		}
+		m := mask128(hostBits)
+		ip := ipToUint128(cidr.Ip)
+		start := uint128{hi: ip.hi &^ m.hi, lo: ip.lo &^ m.lo}
+		ranges = append(ranges, ipRange{
+			start: start,
+			end:   uint128{hi: start.hi | m.hi, lo: start.lo | m.lo},
+		})
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.less(ranges[j].start)
+	})
+
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			next, overflow := last.end.add(uint128{lo: 1})
+			if overflow || !next.less(r.start) {
+				if last.end.less(r.end) {
+					last.end = r.end
+				}
+				continue
+			}
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// rangeToCIDRs splits a range into the minimum list of CIDRs.
+func rangeToCIDRs(r ipRange, size int) []*CIDR {
+	var cidrs []*CIDR
+	width := size * 8
+	start := r.start
+	for {
+		hostBits := width
+		if start != (uint128{}) {
+			hostBits = start.trailingZeros()
+			if hostBits > width {
+				hostBits = width
+			}
+		}
+		for hostBits > 0 {
+			last, overflow := start.add(mask128(hostBits))
+			if !overflow && !r.end.less(last) {
+				break
+			}
+			hostBits--
+		}
+		cidrs = append(cidrs, &CIDR{
+			Ip:     uint128ToIP(start, size),
+			Prefix: uint32(width - hostBits),
+		})
+
+		last, _ := start.add(mask128(hostBits))
+		if !last.less(r.end) {
+			return cidrs
+		}
+		start, _ = last.add(uint128{lo: 1})
+	}
+}
+
+type compactGeoDataWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *compactGeoDataWriter) writeUint16(v uint16) {
+	var b [2]byte
+	binary.LittleEndian.PutUint16(b[:], v)
+	w.buf.Write(b[:])
+}
+
+func (w *compactGeoDataWriter) writeUint32(v uint32) {
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], v)
+	w.buf.Write(b[:])
+}
+
+func (w *compactGeoDataWriter) writeUint64(v uint64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	w.buf.Write(b[:])
+}
+
+type compactEntryHeader struct {
+	kind    byte
+	code    string
+	payload []byte
+}
+
+func compileGeoIP(geoip *GeoIP) []byte {
+	w := new(compactGeoDataWriter)
+	ranges4 := cidrsToRanges(geoip.Cidr, 4)
+	w.writeUint32(uint32(len(ranges4)))
+	for _, r := range ranges4 {
+		w.writeUint32(uint32(r.start.lo))
+		w.writeUint32(uint32(r.end.lo))
+	}
+	ranges6 := cidrsToRanges(geoip.Cidr, 16)
+	w.writeUint32(uint32(len(ranges6)))
+	for _, r := range ranges6 {
+		w.writeUint64(r.start.hi)
+		w.writeUint64(r.start.lo)
+		w.writeUint64(r.end.hi)
+		w.writeUint64(r.end.lo)
+	}
+	return w.buf.Bytes()
+}
+
+type compactGeoSiteSet struct {
+	code     string
+	set      int
+	patterns []*Domain
+}
+
+// CompileGeoData converts the given GeoIP and GeoSite lists into compact geodata format. Either list may be nil.
+func CompileGeoData(geoips *GeoIPList, geosites *GeoSiteList) ([]byte, error) {
+	var entries []compactEntryHeader
+
+	for _, geoip := range geoips.GetEntry() {
+		entries = append(entries, compactEntryHeader{
+			kind:    compactKindGeoIP,
+			code:    strings.ToUpper(geoip.CountryCode),
+			payload: compileGeoIP(geoip),
+		})
+	}
+
+	builder := new(strmatcher.DomainGraphBuilder)
+	var sets []*compactGeoSiteSet
+	for _, site := range geosites.GetEntry() {
+		code := strings.ToUpper(site.CountryCode)
+		all := &compactGeoSiteSet{code: code, set: builder.NewSet()}
+		sets = append(sets, all)
+		byAttr := make(map[string]*compactGeoSiteSet)
+		var attrs []string
+
+		for _, domain := range site.Domain {
+			targets := []*compactGeoSiteSet{all}
+			for _, attr := range domain.Attribute {
+				key := strings.ToLower(attr.Key)
+				s, found := byAttr[key]
+				if !found {
+					s = &compactGeoSiteSet{code: code + "@" + strings.ToUpper(key), set: builder.NewSet()}
+					byAttr[key] = s
+					attrs = append(attrs, key)
+				}
+				targets = append(targets, s)
+			}
+
+			for _, s := range targets {
+				switch domain.Type {
+				case Domain_Domain:
+					if err := builder.Add(s.set, strmatcher.Domain, domain.Value); err != nil {
+						return nil, err
+					}
+				case Domain_Full:
+					if err := builder.Add(s.set, strmatcher.Full, domain.Value); err != nil {
+						return nil, err
+					}
+				case Domain_Plain, Domain_Regex:
+					s.patterns = append(s.patterns, domain)
+				default:
+					return nil, newError("unsupported domain type ", domain.Type, " in ", code)
+				}
+			}
+		}
+
+		sort.Strings(attrs)
+		for _, attr := range attrs {
+			sets = append(sets, byAttr[attr])
+		}
+	}
+
+	graph, roots := builder.Build()
+	for _, s := range sets {
+		w := new(compactGeoDataWriter)
+		w.writeUint32(roots[s.set])
+		w.writeUint32(uint32(len(s.patterns)))
+		for _, p := range s.patterns {
+			if len(p.Value) > 0xffff {
+				return nil, newError("pattern too long in ", s.code)
+			}
+			w.buf.WriteByte(byte(p.Type))
+			w.writeUint16(uint16(len(p.Value)))
+			w.buf.WriteString(p.Value)
+		}
+		entries = append(entries, compactEntryHeader{
+			kind:    compactKindGeoSite,
+			code:    s.code,
+			payload: w.buf.Bytes(),
+		})
+	}
+
+	headerSize := len(compactGeoDataMagic) + 16
+	for _, e := range entries {
+		if len(e.code) > 0xff {
+			return nil, newError("code too long: ", e.code)
+		}
+		headerSize += 10 + len(e.code)
+	}
+
+	w := new(compactGeoDataWriter)
+	w.buf.WriteString(compactGeoDataMagic)
+	w.writeUint32(compactGeoDataVersion)
+	w.writeUint32(uint32(headerSize))
+	w.writeUint32(uint32(len(graph)))
+	w.writeUint32(uint32(len(entries)))
+	offset := headerSize + len(graph)
+	for _, e := range entries {
+		w.buf.WriteByte(e.kind)
+		w.buf.WriteByte(byte(len(e.code)))
+		w.buf.WriteString(e.code)
+		w.writeUint32(uint32(offset))
+		w.writeUint32(uint32(len(e.payload)))
+		offset += len(e.payload)
+	}
+	w.buf.Write(graph)
+	for _, e := range entries {
+		w.buf.Write(e.payload)
+	}
+	return w.buf.Bytes(), nil
+}
+
+type compactEntryKey struct {
+	kind byte
+	code string
+}
+
+// compactGeoData is a parsed compact geodata file. Matchers of its entries are created once and shared.
+type compactGeoData struct {
+	// file holds the mapping that data, graph and entries refer to. It is unmapped when compactGeoData is unreachable.
+	file    *filesystem.MappedFile
+	data    []byte
+	graph   *strmatcher.DomainGraph
+	entries map[compactEntryKey][]byte
+
+	access       sync.Mutex
+	ipMatchers   map[string]*CompactGeoIPMatcher
+	siteMatchers map[string]*CompactGeoSiteMatcher
+}
+
+func parseCompactGeoData(data []byte) (*compactGeoData, error) {
+	errInvalid := newError("invalid compact geodata")
+	if !IsCompactGeoData(data) || len(data) < len(compactGeoDataMagic)+16 {
+		return nil, errInvalid
+	}
+	p := data[len(compactGeoDataMagic):]
+	if version := binary.LittleEndian.Uint32(p[0:4]); version != compactGeoDataVersion {
+		return nil, newError("unsupported compact geodata version: ", version)
+	}
+	graphOffset := uint64(binary.LittleEndian.Uint32(p[4:8]))
+	graphLength := uint64(binary.LittleEndian.Uint32(p[8:12]))
+	count := binary.LittleEndian.Uint32(p[12:16])
+	p = p[16:]
+
+	if graphOffset+graphLength > uint64(len(data)) {
+		return nil, errInvalid
+	}
+	graph, err := strmatcher.NewDomainGraph(data[graphOffset : graphOffset+graphLength])
+	if err != nil {
+		return nil, errInvalid.Base(err)
+	}
+
+	gd := &compactGeoData{
+		data:         data,
+		graph:        graph,
+		entries:      make(map[compactEntryKey][]byte, count),
+		ipMatchers:   make(map[string]*CompactGeoIPMatcher),
+		siteMatchers: make(map[string]*CompactGeoSiteMatcher),
+	}
+	for i := uint32(0); i < count; i++ {
+		if len(p) < 2 || len(p) < 2+int(p[1])+8 {
+			return nil, errInvalid
+		}
+		kind := p[0]
+		codeLen := int(p[1])
+		code := string(p[2 : 2+codeLen])
+		offset := uint64(binary.LittleEndian.Uint32(p[2+codeLen:]))
+		length := uint64(binary.LittleEndian.Uint32(p[6+codeLen:]))
+		if offset+length > uint64(len(data)) {
+			return nil, errInvalid
+		}
+		gd.entries[compactEntryKey{kind: kind, code: code}] = data[offset : offset+length]
+		p = p[10+codeLen:]
+	}
+	return gd, nil
+}
+
+func (gd *compactGeoData) entry(kind byte, code string) ([]byte, bool) {
+	payload, found := gd.entries[compactEntryKey{kind: kind, code: strings.ToUpper(code)}]
+	return payload, found
+}
+
+// geoIPMatcher returns the shared matcher of the GeoIP entry of the given code.
+func (gd *compactGeoData) geoIPMatcher(code string) (*CompactGeoIPMatcher, error) {
+	code = strings.ToUpper(code)
+
+	gd.access.Lock()
+	defer gd.access.Unlock()
+
+	if m, found := gd.ipMatchers[code]; found {
+		return m, nil
+	}
+	payload, found := gd.entry(compactKindGeoIP, code)
+	if !found {
+		return nil, newError("country not found: ", code)
+	}
+	m, err := newCompactGeoIPMatcher(gd, payload)
+	if err != nil {
+		return nil, newError("invalid geoip entry: ", code).Base(err)
+	}
+	gd.ipMatchers[code] = m
+	return m, nil
+}
+
+// geoSiteMatcher returns the shared matcher of the GeoSite entry of the given code, such as "CN" or "CN@ADS".
+func (gd *compactGeoData) geoSiteMatcher(code string) (*CompactGeoSiteMatcher, error) {
+	code = strings.ToUpper(code)
+
+	gd.access.Lock()
+	defer gd.access.Unlock()
+
+	if m, found := gd.siteMatchers[code]; found {
+		return m, nil
+	}
+	payload, found := gd.entry(compactKindGeoSite, code)
+	if !found {
+		return nil, newError("country not found: ", code)
+	}
+	m, err := newCompactGeoSiteMatcher(gd, payload)
+	if err != nil {
+		return nil, newError("invalid geosite entry: ", code).Base(err)
+	}
+	gd.siteMatchers[code] = m
+	return m, nil
+}
+
+// CompactGeoIPMatcher matches IPs against a GeoIP entry in compact geodata.
+type CompactGeoIPMatcher struct {
+	gd     *compactGeoData
+	count4 int
+	ip4    []byte
+	count6 int
+	ip6    []byte
+}
+
+func newCompactGeoIPMatcher(gd *compactGeoData, payload []byte) (*CompactGeoIPMatcher, error) {
+	m := &CompactGeoIPMatcher{gd: gd}
+	if len(payload) < 4 {
+		return nil, newError("truncated payload")
+	}
+	m.count4 = int(binary.LittleEndian.Uint32(payload))
+	payload = payload[4:]
+	if uint64(len(payload)) < uint64(m.count4)*8+4 {
+		return nil, newError("truncated payload")
+	}
+	m.ip4, payload = payload[:m.count4*8], payload[m.count4*8:]
+	m.count6 = int(binary.LittleEndian.Uint32(payload))
+	payload = payload[4:]
+	if uint64(len(payload)) != uint64(m.count6)*32 {
+		return nil, newError("truncated payload")
+	}
+	m.ip6 = payload
+	return m, nil
+}
+
+func (m *CompactGeoIPMatcher) range4(i int) (uint32, uint32) {
+	b := m.ip4[i*8:]
+	return binary.LittleEndian.Uint32(b[0:4]), binary.LittleEndian.Uint32(b[4:8])
+}
+
+func (m *CompactGeoIPMatcher) range6(i int) ipRange {
+	b := m.ip6[i*32:]
+	return ipRange{
+		start: uint128{hi: binary.LittleEndian.Uint64(b[0:8]), lo: binary.LittleEndian.Uint64(b[8:16])},
+		end:   uint128{hi: binary.LittleEndian.Uint64(b[16:24]), lo: binary.LittleEndian.Uint64(b[24:32])},
+	}
+}
+
+// Match returns true if the given ip is included by the GeoIP entry.
+func (m *CompactGeoIPMatcher) Match(ip net.IP) bool {
+	defer runtime.KeepAlive(m.gd)
+
+	switch len(ip) {
+	case 4:
+		v := binary.BigEndian.Uint32(ip)
+		// Index of the first range that starts after v.
+		idx := sort.Search(m.count4, func(i int) bool {
+			start, _ := m.range4(i)
+			return start > v
+		})
+		if idx == 0 {
+			return false
+		}
+		_, end := m.range4(idx - 1)
+		return v <= end
+	case 16:
+		v := ipToUint128(ip)
+		idx := sort.Search(m.count6, func(i int) bool {
+			return v.less(m.range6(i).start)
+		})
+		if idx == 0 {
+			return false
+		}
+		return !m.range6(idx - 1).end.less(v)
+	default:
+		return false
+	}
+}
+
+// CIDRs returns the GeoIP entry as a list of CIDRs.
+func (m *CompactGeoIPMatcher) CIDRs() []*CIDR {
+	defer runtime.KeepAlive(m.gd)
+
+	var cidrs []*CIDR
+	for i := 0; i < m.count4; i++ {
+		start, end := m.range4(i)
+		cidrs = append(cidrs, rangeToCIDRs(ipRange{start: uint128{lo: uint64(start)}, end: uint128{lo: uint64(end)}}, 4)...)
+	}
+	for i := 0; i < m.count6; i++ {
+		cidrs = append(cidrs, rangeToCIDRs(m.range6(i), 16)...)
+	}
+	return cidrs
+}
+
+// CompactGeoSiteMatcher matches domains against a GeoSite entry in compact geodata.
+type CompactGeoSiteMatcher struct {
+	gd       *compactGeoData
+	root     uint32
+	patterns []*Domain
+	matchers []strmatcher.Matcher
+}
+
+func newCompactGeoSiteMatcher(gd *compactGeoData, payload []byte) (*CompactGeoSiteMatcher, error) {
+	if len(payload) < 8 {
+		return nil, newError("truncated payload")
+	}
+	m := &CompactGeoSiteMatcher{
+		gd:   gd,
+		root: binary.LittleEndian.Uint32(payload[0:4]),
+	}
+	count := binary.LittleEndian.Uint32(payload[4:8])
+	payload = payload[8:]
+	for i := uint32(0); i < count; i++ {
+		if len(payload) < 3 {
+			return nil, newError("truncated payload")
+		}
+		t := Domain_Type(payload[0])
+		l := int(binary.LittleEndian.Uint16(payload[1:3]))
+		if len(payload) < 3+l {
+			return nil, newError("truncated payload")
+		}
+		domain := &Domain{Type: t, Value: string(payload[3 : 3+l])}
+		payload = payload[3+l:]
+
+		var matcherType strmatcher.Type
+		switch t {
+		case Domain_Plain:
+			matcherType = strmatcher.Substr
+		case Domain_Regex:
+			matcherType = strmatcher.Regex
+		default:
+			return nil, newError("unexpected domain type ", t)
+		}
+		matcher, err := matcherType.New(domain.Value)
+		if err != nil {
+			return nil, err
+		}
+		m.patterns = append(m.patterns, domain)
+		m.matchers = append(m.matchers, matcher)
+	}
+	return m, nil
+}
+
+// Match implements strmatcher.Matcher.
+func (m *CompactGeoSiteMatcher) Match(domain string) bool {
+	defer runtime.KeepAlive(m.gd)
+
+	if m.gd.graph.Match(m.root, domain) {
+		return true
+	}
+	for _, matcher := range m.matchers {
+		if matcher.Match(domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// Domains returns the GeoSite entry as a list of domains. Attributes of domains are not kept.
+func (m *CompactGeoSiteMatcher) Domains() []*Domain {
+	defer runtime.KeepAlive(m.gd)
+
+	var domains []*Domain
+	m.gd.graph.Walk(m.root, func(t strmatcher.Type, pattern string) bool {
+		domainType := Domain_Full
+		if t == strmatcher.Domain {
+			domainType = Domain_Domain
+		}
+		domains = append(domains, &Domain{Type: domainType, Value: pattern})
+		return true
+	})
+	return append(domains, m.patterns...)
+}
+
+// compactGeoDataContainer keeps parsed compact geodata files in asset location by file name, so that all rules
+// share one mapping of each file.
+type compactGeoDataContainer struct {
+	access sync.Mutex
+	files  map[string]*compactGeoData
+}
+
+// Load returns the parsed compact geodata of the given file, or nil if the file is not in compact format.
+// Files are memory-mapped, so they must be updated by atomic rename. See filesystem.MapFile.
+func (c *compactGeoDataContainer) Load(file string) (*compactGeoData, error) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if gd, found := c.files[file]; found {
+		return gd, nil
+	}
+
+	mapped, err := filesystem.MapAsset(file)
+	if err != nil {
+		return nil, newError("failed to open file: ", file).Base(err)
+	}
+	var gd *compactGeoData
+	if IsCompactGeoData(mapped.Bytes()) {
+		gd, err = parseCompactGeoData(mapped.Bytes())
+		if err != nil {
+			mapped.Close()
+			return nil, newError("failed to parse file: ", file).Base(err)
+		}
+		gd.file = mapped
+	} else {
+		mapped.Close()
+	}
+
+	if c.files == nil {
+		c.files = make(map[string]*compactGeoData)
+	}
+	c.files[file] = gd
+	return gd, nil
+}
+
+// replace replaces files in the container with the ones in other. Mappings are not released by replace. Each matcher
+// references its compactGeoData, which holds the mapping, so a mapping is released only after routing tables using its
+// matchers are gone.
+func (c *compactGeoDataContainer) replace(other *compactGeoDataContainer) {
+	other.access.Lock()
+	files := other.files
+	other.access.Unlock()
+
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	c.files = files
+}
+
+var (
+	globalCompactGeoData compactGeoDataContainer
+)
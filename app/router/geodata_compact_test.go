@@ -0,0 +1,151 @@
+package router_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "v2ray.com/core/app/router"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/platform"
+	"v2ray.com/core/common/session"
+)
+
+func writeCompactGeoData(t *testing.T, file string) {
+	data, err := CompileGeoData(&GeoIPList{
+		Entry: []*GeoIP{
+			{
+				CountryCode: "test",
+				Cidr: []*CIDR{
+					{Ip: []byte{10, 0, 0, 0}, Prefix: 9},
+					{Ip: []byte{10, 128, 0, 0}, Prefix: 9},
+					{Ip: []byte{192, 168, 1, 0}, Prefix: 24},
+					{Ip: []byte{192, 168, 1, 128}, Prefix: 25},
+					{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 32},
+				},
+			},
+		},
+	}, &GeoSiteList{
+		Entry: []*GeoSite{
+			{
+				CountryCode: "test",
+				Domain: []*Domain{
+					{Type: Domain_Domain, Value: "v2ray.com"},
+					{Type: Domain_Full, Value: "www.google.com", Attribute: []*Domain_Attribute{{Key: "ads"}}},
+					{Type: Domain_Plain, Value: "baidu", Attribute: []*Domain_Attribute{{Key: "ads"}, {Key: "cn"}}},
+					{Type: Domain_Regex, Value: "^example[0-9]\\.org$", Attribute: []*Domain_Attribute{{Key: "cn"}}},
+				},
+			},
+		},
+	})
+	common.Must(err)
+	if !IsCompactGeoData(data) {
+		t.Fatal("expect compact geodata")
+	}
+	common.Must(ioutil.WriteFile(platform.GetAssetLocation(file), data, 0644))
+}
+
+func TestCompactGeoDataCondition(t *testing.T) {
+	writeCompactGeoData(t, "router_test_compact.dat")
+
+	rule := &RoutingRule{
+		GeoipRef: []*GeoDataReference{
+			{File: "router_test_compact.dat", Code: "TEST"},
+		},
+	}
+	cond, err := rule.BuildCondition()
+	common.Must(err)
+
+	ipCases := []struct {
+		ip     string
+		output bool
+	}{
+		{"10.0.0.1", true},
+		{"10.255.255.255", true},
+		{"11.0.0.0", false},
+		{"192.168.1.200", true},
+		{"192.168.2.1", false},
+		{"2001:db8::1", true},
+		{"2001:db9::1", false},
+	}
+	for _, test := range ipCases {
+		ctx := withOutbound(&session.Outbound{Target: net.TCPDestination(net.ParseAddress(test.ip), 80)})
+		if r := cond.Apply(ctx); r != test.output {
+			t.Error("for ", test.ip, ", expect ", test.output, " but got ", r)
+		}
+	}
+
+	domainCases := []struct {
+		code   string
+		domain string
+		output bool
+	}{
+		{"TEST", "www.v2ray.com", true},
+		{"TEST", "www.google.com", true},
+		{"TEST", "mail.google.com", false},
+		{"TEST", "www.baidu.com", true},
+		{"TEST", "example1.org", true},
+		{"TEST@ads", "www.google.com", true},
+		{"TEST@ads", "v2ray.com", false},
+		{"TEST@ads", "baidu.com", true},
+		{"TEST@ads@cn", "baidu.com", true},
+		{"TEST@ads@cn", "www.google.com", false},
+		{"TEST@cn", "example2.org", true},
+	}
+	for _, test := range domainCases {
+		rule := &RoutingRule{
+			GeositeRef: []*GeoDataReference{
+				{File: "router_test_compact.dat", Code: test.code},
+			},
+		}
+		cond, err := rule.BuildCondition()
+		common.Must(err)
+		ctx := withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress(test.domain), 80)})
+		if r := cond.Apply(ctx); r != test.output {
+			t.Error("for ", test.code, " ", test.domain, ", expect ", test.output, " but got ", r)
+		}
+	}
+
+	for _, code := range []string{"TEST@unknown", "TEST@ads@unknown"} {
+		rule := &RoutingRule{
+			GeositeRef: []*GeoDataReference{
+				{File: "router_test_compact.dat", Code: code},
+			},
+		}
+		if _, err := rule.BuildCondition(); err == nil {
+			t.Error("expect error for unknown attribute in ", code, ", but nil")
+		}
+	}
+}
+
+func TestCompactGeoDataLoad(t *testing.T) {
+	writeCompactGeoData(t, "router_test_compact_load.dat")
+
+	geoip, err := LoadGeoIP("router_test_compact_load.dat", "test")
+	common.Must(err)
+	if r := cmp.Diff(geoip.Cidr, []*CIDR{
+		{Ip: []byte{10, 0, 0, 0}, Prefix: 8},
+		{Ip: []byte{192, 168, 1, 0}, Prefix: 24},
+		{Ip: net.ParseAddress("2001:db8::").IP(), Prefix: 32},
+	}); r != "" {
+		t.Error(r)
+	}
+
+	domains, err := LoadGeoSite("router_test_compact_load.dat", "test@ads")
+	common.Must(err)
+	if r := cmp.Diff(domains, []*Domain{
+		{Type: Domain_Full, Value: "www.google.com"},
+		{Type: Domain_Plain, Value: "baidu"},
+	}); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := LoadGeoSite("router_test_compact_load.dat", "unknown"); err == nil {
+		t.Error("expect error for unknown code, but nil")
+	}
+	if _, err := LoadGeoSite("router_test_compact_load.dat", "test@unknown"); err == nil {
+		t.Error("expect error for unknown attribute, but nil")
+	}
+}
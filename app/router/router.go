@@ -6,21 +6,26 @@ package router
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal/pubsub"
 	"v2ray.com/core/features/dns"
 	"v2ray.com/core/features/outbound"
+	"v2ray.com/core/features/policy"
 	"v2ray.com/core/features/routing"
 )
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
-		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager) error {
-			return r.Init(config.(*Config), d, ohm)
+		if err := core.RequireFeatures(ctx, func(d dns.Client, ohm outbound.Manager, pm policy.Manager) error {
+			return r.Init(config.(*Config), d, ohm, pm)
 		}); err != nil {
 			return nil, err
 		}
@@ -30,94 +35,366 @@ func init() {
 
 // Router is an implementation of routing.Router.
 type Router struct {
+	access sync.RWMutex
+	table  *routingTable
+
+	// update serializes modifications of routing table.
+	update sync.Mutex
+
+	ohm       outbound.Manager
+	dns       dns.Client
+	policy    policy.Manager
+	decisions *pubsub.Service
+	// usages is shared by traffic quota rules of all routing tables.
+	usages *trafficUsages
+}
+
+// routingTable holds rules and balancers of Router. It is never modified once built, but replaced as a whole,
+// so that routing in progress is not affected by rule changes.
+type routingTable struct {
 	domainStrategy Config_DomainStrategy
 	rules          []*Rule
 	balancers      map[string]*Balancer
-	dns            dns.Client
 }
 
-// Init initializes the Router.
-func (r *Router) Init(config *Config, d dns.Client, ohm outbound.Manager) error {
-	r.domainStrategy = config.DomainStrategy
-	r.dns = d
+func buildRule(config *RoutingRule, balancers map[string]*Balancer, loader *geoDataLoader) (*Rule, error) {
+	cond, err := config.buildCondition(loader)
+	if err != nil {
+		return nil, err
+	}
+	rr := &Rule{
+		Condition: cond,
+		Tag:       config.GetTag(),
+		RuleTag:   config.RuleTag,
+		config:    config,
+	}
+	btag := config.GetBalancingTag()
+	if len(btag) > 0 {
+		brule, found := balancers[btag]
+		if !found {
+			return nil, newError("balancer ", btag, " not found")
+		}
+		rr.Balancer = brule
+	}
+	return rr, nil
+}
+
+func buildRoutingTable(config *Config, ohm outbound.Manager) (*routingTable, error) {
+	t := &routingTable{
+		domainStrategy: config.DomainStrategy,
+		balancers:      make(map[string]*Balancer, len(config.BalancingRule)),
+		rules:          make([]*Rule, 0, len(config.Rule)),
+	}
 
-	r.balancers = make(map[string]*Balancer, len(config.BalancingRule))
 	for _, rule := range config.BalancingRule {
 		balancer, err := rule.Build(ohm)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		r.balancers[rule.Tag] = balancer
+		t.balancers[rule.Tag] = balancer
 	}
 
-	r.rules = make([]*Rule, 0, len(config.Rule))
+	loader := newGeoDataLoader()
 	for _, rule := range config.Rule {
-		cond, err := rule.BuildCondition()
+		rr, err := buildRule(rule, t.balancers, loader)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		rr := &Rule{
-			Condition: cond,
-			Tag:       rule.GetTag(),
+		t.rules = append(t.rules, rr)
+	}
+
+	return t, nil
+}
+
+// Init initializes the Router.
+func (r *Router) Init(config *Config, d dns.Client, ohm outbound.Manager, pm policy.Manager) error {
+	r.ohm = ohm
+	r.dns = d
+	r.policy = pm
+	r.decisions = pubsub.NewService()
+	r.usages = newTrafficUsages()
+
+	t, err := buildRoutingTable(config, ohm)
+	if err != nil {
+		return err
+	}
+	r.setTable(t)
+
+	return nil
+}
+
+func (r *Router) getTable() *routingTable {
+	r.access.RLock()
+	defer r.access.RUnlock()
+
+	return r.table
+}
+
+func (r *Router) setTable(t *routingTable) {
+	r.usages.setPeriods(t.trafficPeriods())
+
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	r.table = t
+}
+
+// trafficPeriods returns periods of traffic quota rules that count traffic of users.
+func (t *routingTable) trafficPeriods() []trafficPeriod {
+	var periods []trafficPeriod
+	for _, rule := range t.rules {
+		conds := []Condition{rule.Condition}
+		if chain, ok := rule.Condition.(*ConditionChan); ok {
+			conds = *chain
 		}
-		btag := rule.GetBalancingTag()
-		if len(btag) > 0 {
-			brule, found := r.balancers[btag]
-			if !found {
-				return newError("balancer ", btag, " not found")
+		for _, cond := range conds {
+			if m, ok := cond.(*TrafficQuotaMatcher); ok && m.countsTraffic() {
+				periods = append(periods, m.period)
 			}
-			rr.Balancer = brule
 		}
-		r.rules = append(r.rules, rr)
 	}
+	return periods
+}
 
+// TrafficCounter implements routing.TrafficRecorder.
+func (r *Router) TrafficCounter(email string) routing.TrafficCounter {
+	if len(email) == 0 || !r.usages.hasPeriods() {
+		return nil
+	}
+	return &trafficCounter{
+		usages: r.usages,
+		email:  email,
+	}
+}
+
+// AddRule builds a rule from the given config and inserts it at the given index.
+// The rule is appended to the end if index is negative or out of range.
+func (r *Router) AddRule(config *RoutingRule, index int) error {
+	r.update.Lock()
+	defer r.update.Unlock()
+
+	t := r.getTable()
+	rule, err := buildRule(config, t.balancers, newGeoDataLoader())
+	if err != nil {
+		return err
+	}
+
+	if index < 0 || index > len(t.rules) {
+		index = len(t.rules)
+	}
+	rules := make([]*Rule, 0, len(t.rules)+1)
+	rules = append(rules, t.rules[:index]...)
+	rules = append(rules, rule)
+	rules = append(rules, t.rules[index:]...)
+
+	r.setTable(&routingTable{
+		domainStrategy: t.domainStrategy,
+		rules:          rules,
+		balancers:      t.balancers,
+	})
+	return nil
+}
+
+// RemoveRule removes all rules with the given rule tag.
+func (r *Router) RemoveRule(ruleTag string) error {
+	if len(ruleTag) == 0 {
+		return newError("empty rule tag")
+	}
+
+	r.update.Lock()
+	defer r.update.Unlock()
+
+	t := r.getTable()
+	rules := make([]*Rule, 0, len(t.rules))
+	for _, rule := range t.rules {
+		if rule.RuleTag != ruleTag {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == len(t.rules) {
+		return newError("rule ", ruleTag, " not found")
+	}
+
+	r.setTable(&routingTable{
+		domainStrategy: t.domainStrategy,
+		rules:          rules,
+		balancers:      t.balancers,
+	})
 	return nil
 }
 
+// ReplaceRules replaces domain strategy, all rules and balancers with the ones in the given config.
+func (r *Router) ReplaceRules(config *Config) error {
+	r.update.Lock()
+	defer r.update.Unlock()
+
+	t, err := buildRoutingTable(config, r.ohm)
+	if err != nil {
+		return err
+	}
+	r.setTable(t)
+	return nil
+}
+
+// ReloadGeoData reads geodata files again, and rebuilds all rules that reference them. Nothing is changed if any rule
+// fails to rebuild.
+func (r *Router) ReloadGeoData() error {
+	r.update.Lock()
+	defer r.update.Unlock()
+
+	t := r.getTable()
+	loader := newIsolatedGeoDataLoader()
+	rules := make([]*Rule, 0, len(t.rules))
+	for _, rule := range t.rules {
+		config := rule.config
+		if config == nil || len(config.GeositeRef)+len(config.GeoipRef)+len(config.SourceGeoipRef) == 0 {
+			rules = append(rules, rule)
+			continue
+		}
+		rr, err := buildRule(config, t.balancers, loader)
+		if err != nil {
+			return newError("failed to rebuild rule ", rule.RuleTag).Base(err)
+		}
+		rules = append(rules, rr)
+	}
+
+	loader.share()
+	r.setTable(&routingTable{
+		domainStrategy: t.domainStrategy,
+		rules:          rules,
+		balancers:      t.balancers,
+	})
+	return nil
+}
+
+// PickRoute implements routing.Router.
 func (r *Router) PickRoute(ctx context.Context) (string, error) {
-	rule, err := r.pickRouteInternal(ctx)
+	index, rule, err := r.pickRouteInternal(ctx, nil)
+	tag := ""
+	if err == nil {
+		tag, err = rule.GetTag()
+	}
+	r.publishDecision(ctx, index, rule, tag, err)
 	if err != nil {
 		return "", err
 	}
-	return rule.GetTag()
+	return tag, nil
+}
+
+// TraceRoute picks a route for the given context as PickRoute does, and returns how each rule is evaluated.
+func (r *Router) TraceRoute(ctx context.Context) (*Trace, error) {
+	trace := &Trace{
+		Index: -1,
+	}
+	index, rule, err := r.pickRouteInternal(ctx, trace)
+	if err == common.ErrNoClue {
+		return trace, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	trace.Index = index
+	trace.Rule = rule
+	tag, err := rule.GetTag()
+	if err != nil {
+		return nil, err
+	}
+	trace.OutboundTag = tag
+	return trace, nil
+}
+
+// SubscribeDecisions returns a subscriber that receives a *Decision for every routed connection.
+func (r *Router) SubscribeDecisions() *pubsub.Subscriber {
+	return r.decisions.Subscribe(decisionTopic)
+}
+
+func (r *Router) publishDecision(ctx context.Context, index int, rule *Rule, tag string, err error) {
+	if !r.decisions.HasSubscriber(decisionTopic) {
+		return
+	}
+	decision := &Decision{
+		Index:       index,
+		OutboundTag: tag,
+		Time:        time.Now(),
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		decision.InboundTag = inbound.Tag
+		decision.Source = inbound.Source
+		if inbound.User != nil {
+			decision.Email = inbound.User.Email
+		}
+	}
+	if outbound := session.OutboundFromContext(ctx); outbound != nil {
+		decision.Target = outbound.Target
+	}
+	if content := session.ContentFromContext(ctx); content != nil {
+		decision.Protocol = content.Protocol
+	}
+	if rule != nil {
+		decision.RuleTag = rule.RuleTag
+	}
+	if err != nil && err != common.ErrNoClue {
+		decision.Err = err
+	}
+	r.decisions.Publish(decisionTopic, decision)
 }
 
 func isDomainOutbound(outbound *session.Outbound) bool {
 	return outbound != nil && outbound.Target.IsValid() && outbound.Target.Address.Family().IsDomain()
 }
 
-// PickRoute implements routing.Router.
-func (r *Router) pickRouteInternal(ctx context.Context) (*Rule, error) {
+func applyRules(rules []*Rule, ctx *Context, trace *Trace) (int, *Rule) {
+	for idx, rule := range rules {
+		var matched bool
+		if trace != nil {
+			matched = trace.apply(idx, rule, ctx)
+		} else {
+			matched = rule.Apply(ctx)
+		}
+		if matched {
+			return idx, rule
+		}
+	}
+	return -1, nil
+}
+
+// pickRouteInternal returns the index of the matched rule and the rule itself. Evaluation of rules is recorded in trace if it is not nil.
+func (r *Router) pickRouteInternal(ctx context.Context, trace *Trace) (int, *Rule, error) {
+	t := r.getTable()
 	sessionContext := &Context{
 		Inbound:  session.InboundFromContext(ctx),
 		Outbound: session.OutboundFromContext(ctx),
 		Content:  session.ContentFromContext(ctx),
+
+		policyManager: r.policy,
+		trafficUsages: r.usages,
+		readOnly:      trace != nil,
 	}
 
-	if r.domainStrategy == Config_IpOnDemand {
+	switch t.domainStrategy {
+	case Config_IpOnDemand:
+		sessionContext.dnsClient = r.dns
+	case Config_IpIfCached:
 		sessionContext.dnsClient = r.dns
+		sessionContext.cachedOnly = true
 	}
 
-	for _, rule := range r.rules {
-		if rule.Apply(sessionContext) {
-			return rule, nil
-		}
+	if idx, rule := applyRules(t.rules, sessionContext, trace); rule != nil {
+		return idx, rule, nil
 	}
 
-	if r.domainStrategy != Config_IpIfNonMatch || !isDomainOutbound(sessionContext.Outbound) {
-		return nil, common.ErrNoClue
+	if t.domainStrategy != Config_IpIfNonMatch || !isDomainOutbound(sessionContext.Outbound) {
+		return -1, nil, common.ErrNoClue
 	}
 
 	sessionContext.dnsClient = r.dns
 
 	// Try applying rules again if we have IPs.
-	for _, rule := range r.rules {
-		if rule.Apply(sessionContext) {
-			return rule, nil
-		}
+	if idx, rule := applyRules(t.rules, sessionContext, trace); rule != nil {
+		return idx, rule, nil
 	}
 
-	return nil, common.ErrNoClue
+	return -1, nil, common.ErrNoClue
 }
 
 // Start implements common.Runnable.
@@ -140,7 +417,35 @@ type Context struct {
 	Outbound *session.Outbound
 	Content  *session.Content
 
-	dnsClient dns.Client
+	dnsClient     dns.Client
+	cachedOnly    bool
+	policyManager policy.Manager
+	trafficUsages *trafficUsages
+	// readOnly is set when tracing, so that evaluating rules doesn't change any state, such as quota counters of users.
+	readOnly bool
+}
+
+// quotaUsage returns the usage of the quota of the user in policy. A read-only Context looks it up in usages of all
+// users, instead of creating a QuotaCounter for the user.
+func (c *Context) quotaUsage(user *protocol.MemoryUser) (policy.QuotaUsage, bool) {
+	if c.policyManager == nil {
+		return policy.QuotaUsage{}, false
+	}
+	if !c.readOnly {
+		counter := policy.QuotaForUser(c.policyManager, user)
+		if counter == nil {
+			return policy.QuotaUsage{}, false
+		}
+		return counter.Usage(), true
+	}
+	if qm, ok := c.policyManager.(policy.QuotaManager); ok {
+		for _, usage := range qm.QuotaUsages() {
+			if usage.Email == user.Email {
+				return usage, true
+			}
+		}
+	}
+	return policy.QuotaUsage{}, false
 }
 
 func (c *Context) GetTargetIPs() []net.IP {
@@ -158,6 +463,16 @@ func (c *Context) GetTargetIPs() []net.IP {
 
 	if c.dnsClient != nil {
 		domain := c.Outbound.Target.Address.Domain()
+		if cache, ok := c.dnsClient.(dns.CachedLookup); ok {
+			if ips, found := cache.LookupCachedIP(domain); found {
+				c.Outbound.ResolvedIPs = ips
+				return ips
+			}
+		}
+		if c.cachedOnly {
+			return nil
+		}
+
 		ips, err := c.dnsClient.LookupIP(domain)
 		if err == nil {
 			c.Outbound.ResolvedIPs = ips
@@ -2,12 +2,17 @@ package router_test
 
 import (
 	"context"
+	"io/ioutil"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/policy"
 	. "v2ray.com/core/app/router"
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/platform"
+	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/features/outbound"
 	"v2ray.com/core/testing/mocks"
@@ -41,7 +46,7 @@ func TestSimpleRouter(t *testing.T) {
 	common.Must(r.Init(config, mockDns, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}))
+	}, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
 	tag, err := r.PickRoute(ctx)
@@ -82,7 +87,7 @@ func TestSimpleBalancer(t *testing.T) {
 	common.Must(r.Init(config, mockDns, &mockOutboundManager{
 		Manager:         mockOhm,
 		HandlerSelector: mockHs,
-	}))
+	}, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
 	tag, err := r.PickRoute(ctx)
@@ -117,7 +122,7 @@ func TestIPOnDemand(t *testing.T) {
 	mockDns.EXPECT().LookupIP(gomock.Eq("v2ray.com")).Return([]net.IP{{192, 168, 0, 1}}, nil).AnyTimes()
 
 	r := new(Router)
-	common.Must(r.Init(config, mockDns, nil))
+	common.Must(r.Init(config, mockDns, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
 	tag, err := r.PickRoute(ctx)
@@ -127,6 +132,61 @@ func TestIPOnDemand(t *testing.T) {
 	}
 }
 
+type cachedDNSClient struct {
+	*mocks.DNSClient
+	cache map[string][]net.IP
+}
+
+func (c *cachedDNSClient) LookupCachedIP(domain string) ([]net.IP, bool) {
+	ips, found := c.cache[domain]
+	return ips, found
+}
+
+func TestIPIfCached(t *testing.T) {
+	config := &Config{
+		DomainStrategy: Config_IpIfCached,
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Cidr: []*CIDR{
+					{
+						Ip:     []byte{192, 168, 0, 0},
+						Prefix: 16,
+					},
+				},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	// LookupIP is not expected to be called.
+	mockDns := &cachedDNSClient{
+		DNSClient: mocks.NewDNSClient(mockCtl),
+		cache: map[string][]net.IP{
+			"v2ray.com": {{192, 168, 0, 1}},
+		},
+	}
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
+	tag, err := r.PickRoute(ctx)
+	common.Must(err)
+	if tag != "test" {
+		t.Error("expect tag 'test', bug actually ", tag)
+	}
+
+	ctx = session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("google.com"), 80)})
+	if tag, err := r.PickRoute(ctx); err == nil {
+		t.Error("expect no route, but actually ", tag)
+	}
+}
+
 func TestIPIfNonMatchDomain(t *testing.T) {
 	config := &Config{
 		DomainStrategy: Config_IpIfNonMatch,
@@ -152,7 +212,7 @@ func TestIPIfNonMatchDomain(t *testing.T) {
 	mockDns.EXPECT().LookupIP(gomock.Eq("v2ray.com")).Return([]net.IP{{192, 168, 0, 1}}, nil).AnyTimes()
 
 	r := new(Router)
-	common.Must(r.Init(config, mockDns, nil))
+	common.Must(r.Init(config, mockDns, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
 	tag, err := r.PickRoute(ctx)
@@ -186,7 +246,7 @@ func TestIPIfNonMatchIP(t *testing.T) {
 	mockDns := mocks.NewDNSClient(mockCtl)
 
 	r := new(Router)
-	common.Must(r.Init(config, mockDns, nil))
+	common.Must(r.Init(config, mockDns, nil, nil))
 
 	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.LocalHostIP, 80)})
 	tag, err := r.PickRoute(ctx)
@@ -195,3 +255,311 @@ func TestIPIfNonMatchIP(t *testing.T) {
 		t.Error("expect tag 'test', bug actually ", tag)
 	}
 }
+
+func TestTrafficQuota(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "cheap",
+				},
+				TrafficQuota: &TrafficQuota{
+					Threshold: 1024,
+					Period:    TrafficQuota_Policy,
+				},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDns := mocks.NewDNSClient(mockCtl)
+
+	pm, err := policy.New(context.Background(), &policy.Config{
+		UserQuota: map[string]*policy.Quota{
+			"test@v2ray.com": {Bytes: 4096},
+		},
+	})
+	common.Must(err)
+	user := &protocol.MemoryUser{Email: "test@v2ray.com"}
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil, pm))
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{User: user})
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
+
+	tag, err := r.PickRoute(ctx)
+	common.Must(err)
+	if tag != "test" {
+		t.Error("expect tag 'test', bug actually ", tag)
+	}
+
+	pm.QuotaForUser(user).Add(2048)
+
+	tag, err = r.PickRoute(ctx)
+	common.Must(err)
+	if tag != "cheap" {
+		t.Error("expect tag 'cheap', bug actually ", tag)
+	}
+}
+
+func TestTraceRouteTrafficQuota(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "cheap",
+				},
+				TrafficQuota: &TrafficQuota{
+					Threshold: 1024,
+					Period:    TrafficQuota_Policy,
+				},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDns := mocks.NewDNSClient(mockCtl)
+
+	pm, err := policy.New(context.Background(), &policy.Config{
+		Level: map[uint32]*policy.Policy{
+			0: {Quota: &policy.Quota{Bytes: 4096}},
+		},
+	})
+	common.Must(err)
+	user := &protocol.MemoryUser{Email: "test@v2ray.com"}
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil, pm))
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{User: user})
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
+
+	trace, err := r.TraceRoute(ctx)
+	common.Must(err)
+	if trace.OutboundTag != "test" {
+		t.Error("expect tag 'test', bug actually ", trace.OutboundTag)
+	}
+	// Tracing doesn't create the quota counter of the user.
+	if usages := pm.QuotaUsages(); len(usages) != 0 {
+		t.Error("expect no quota usages after tracing, but got ", usages)
+	}
+
+	pm.QuotaForUser(user).Add(2048)
+
+	trace, err = r.TraceRoute(ctx)
+	common.Must(err)
+	if trace.OutboundTag != "cheap" {
+		t.Error("expect tag 'cheap', bug actually ", trace.OutboundTag)
+	}
+}
+
+func TestTrafficQuotaTraffic(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "cheap",
+				},
+				TrafficQuota: &TrafficQuota{
+					Threshold: 1024,
+					Period:    TrafficQuota_Monthly,
+				},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDns := mocks.NewDNSClient(mockCtl)
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil, nil))
+	if c := r.TrafficCounter(""); c != nil {
+		t.Error("expect no traffic counter for users without email")
+	}
+	counter := r.TrafficCounter("test@v2ray.com")
+
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{User: &protocol.MemoryUser{Email: "test@v2ray.com"}})
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
+
+	expectTag := func(expected string) {
+		t.Helper()
+		tag, err := r.PickRoute(ctx)
+		common.Must(err)
+		if tag != expected {
+			t.Error("expect tag '", expected, "', bug actually ", tag)
+		}
+	}
+
+	// Traffic before the first routing of the user is counted.
+	counter.Add(512)
+	expectTag("test")
+
+	// Usages are kept when rules are rebuilt.
+	common.Must(r.ReplaceRules(config))
+	counter.Add(512)
+	expectTag("cheap")
+
+	// Usages are dropped when no rule counts traffic of users.
+	common.Must(r.ReplaceRules(&Config{Rule: config.Rule[1:]}))
+	if c := r.TrafficCounter("test@v2ray.com"); c != nil {
+		t.Error("expect no traffic counter without traffic quota rules")
+	}
+	common.Must(r.ReplaceRules(config))
+	expectTag("test")
+}
+
+func TestUpdateRules(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				RuleTag:  "tcp",
+				Networks: []net.Network{net.Network_TCP},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	mockDns := mocks.NewDNSClient(mockCtl)
+
+	r := new(Router)
+	common.Must(r.Init(config, mockDns, nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("v2ray.com"), 80)})
+
+	expectTag := func(expected string) {
+		t.Helper()
+		tag, err := r.PickRoute(ctx)
+		if len(expected) == 0 {
+			if err == nil {
+				t.Error("expect no route, but actually ", tag)
+			}
+			return
+		}
+		common.Must(err)
+		if tag != expected {
+			t.Error("expect tag '", expected, "', but actually ", tag)
+		}
+	}
+
+	expectTag("test")
+
+	common.Must(r.AddRule(&RoutingRule{
+		TargetTag: &RoutingRule_Tag{
+			Tag: "direct",
+		},
+		RuleTag: "v2ray",
+		Domain: []*Domain{
+			{
+				Type:  Domain_Domain,
+				Value: "v2ray.com",
+			},
+		},
+	}, 0))
+	expectTag("direct")
+
+	common.Must(r.RemoveRule("v2ray"))
+	expectTag("test")
+
+	if err := r.RemoveRule("v2ray"); err == nil {
+		t.Error("expect error when removing non-exist rule")
+	}
+
+	common.Must(r.ReplaceRules(&Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "udp",
+				},
+				Networks: []net.Network{net.Network_UDP},
+			},
+		},
+	}))
+	expectTag("")
+}
+
+func TestReloadGeoData(t *testing.T) {
+	writeGeoIP := func(ip []byte) {
+		geoipBytes, err := proto.Marshal(&GeoIPList{
+			Entry: []*GeoIP{
+				{
+					CountryCode: "TEST",
+					Cidr:        []*CIDR{{Ip: ip, Prefix: 32}},
+				},
+			},
+		})
+		common.Must(err)
+		common.Must(ioutil.WriteFile(platform.GetAssetLocation("router_test_geoip.dat"), geoipBytes, 0644))
+	}
+	writeGeoIP([]byte{1, 1, 1, 1})
+
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				GeoipRef: []*GeoDataReference{
+					{File: "router_test_geoip.dat", Code: "TEST"},
+				},
+			},
+		},
+	}
+
+	r := new(Router)
+	common.Must(r.Init(config, nil, nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.ParseAddress("2.2.2.2"), 80)})
+	if tag, err := r.PickRoute(ctx); err == nil {
+		t.Error("expect no route, but actually ", tag)
+	}
+
+	writeGeoIP([]byte{2, 2, 2, 2})
+	common.Must(r.ReloadGeoData())
+
+	tag, err := r.PickRoute(ctx)
+	common.Must(err)
+	if tag != "test" {
+		t.Error("expect tag 'test', but actually ", tag)
+	}
+
+	// The rule can't be rebuilt without TEST, so the loaded rule is kept.
+	common.Must(ioutil.WriteFile(platform.GetAssetLocation("router_test_geoip.dat"), nil, 0644))
+	if err := r.ReloadGeoData(); err == nil {
+		t.Error("expect error for missing geoip code")
+	}
+	tag, err = r.PickRoute(ctx)
+	common.Must(err)
+	if tag != "test" {
+		t.Error("expect tag 'test', but actually ", tag)
+	}
+}
@@ -0,0 +1,127 @@
+// +build !confonly
+
+package router
+
+import (
+	"time"
+
+	"v2ray.com/core/common/net"
+)
+
+const decisionTopic = "decision"
+
+// ConditionResult is the evaluation result of a single condition in a rule.
+type ConditionResult struct {
+	Name string
+	// Evaluated is false if the condition is skipped because an earlier condition of the same rule does not match.
+	Evaluated bool
+	Matched   bool
+}
+
+// RuleResult is the evaluation result of a rule.
+type RuleResult struct {
+	Index      int
+	RuleTag    string
+	Conditions []ConditionResult
+	Matched    bool
+}
+
+// Trace records how a routing decision is made.
+type Trace struct {
+	// Rules evaluated in order. Rules may be evaluated twice when domain strategy is IpIfNonMatch.
+	Rules []RuleResult
+	// Index of the matched rule, or -1 if no rule matches.
+	Index int
+	// Rule is the matched rule, or nil if no rule matches.
+	Rule *Rule
+	// OutboundTag is the outbound picked by the rule or its balancer.
+	OutboundTag string
+	// ResolvedIPs are IPs of target domain resolved during routing.
+	ResolvedIPs []net.IP
+}
+
+// apply evaluates conditions of the rule in order as Rule.Apply does, and records the results.
+// Conditions after the first unmatched one are recorded as not evaluated.
+func (t *Trace) apply(index int, rule *Rule, ctx *Context) bool {
+	result := RuleResult{
+		Index:   index,
+		RuleTag: rule.RuleTag,
+		Matched: true,
+	}
+
+	conds := []Condition{rule.Condition}
+	if chain, ok := rule.Condition.(*ConditionChan); ok {
+		conds = *chain
+	}
+	for _, cond := range conds {
+		if !result.Matched {
+			result.Conditions = append(result.Conditions, ConditionResult{
+				Name: conditionName(cond),
+			})
+			continue
+		}
+		matched := cond.Apply(ctx)
+		result.Conditions = append(result.Conditions, ConditionResult{
+			Name:      conditionName(cond),
+			Evaluated: true,
+			Matched:   matched,
+		})
+		result.Matched = matched
+	}
+
+	t.Rules = append(t.Rules, result)
+	if ctx.Outbound != nil {
+		t.ResolvedIPs = ctx.Outbound.ResolvedIPs
+	}
+	return result.Matched
+}
+
+// conditionName returns the name of the condition, same as the field name in JSON config.
+func conditionName(cond Condition) string {
+	switch c := cond.(type) {
+	case *DomainMatcher:
+		return "domain"
+	case *MultiGeoIPMatcher:
+		if c.onSource {
+			return "source"
+		}
+		return "ip"
+	case *PortMatcher:
+		if c.onSource {
+			return "sourcePort"
+		}
+		return "port"
+	case NetworkMatcher:
+		return "network"
+	case *UserMatcher:
+		return "user"
+	case *InboundTagMatcher:
+		return "inboundTag"
+	case *ProtocolMatcher:
+		return "protocol"
+	case *AttributeMatcher:
+		return "attrs"
+	case *TimeMatcher:
+		return "schedule"
+	case *TrafficQuotaMatcher:
+		return "trafficQuota"
+	default:
+		return "unknown"
+	}
+}
+
+// Decision is a routing decision published to subscribers of Router.
+type Decision struct {
+	InboundTag string
+	Email      string
+	Source     net.Destination
+	Target     net.Destination
+	Protocol   string
+
+	// Index of the matched rule, or -1 if no rule matches.
+	Index       int
+	RuleTag     string
+	OutboundTag string
+	Err         error
+	Time        time.Time
+}
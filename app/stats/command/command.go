@@ -7,6 +7,7 @@ package command
 import (
 	"context"
 	"runtime"
+	"sort"
 	"time"
 
 	grpc "google.golang.org/grpc"
@@ -31,9 +32,52 @@ func NewStatsServer(manager feature_stats.Manager) StatsServiceServer {
 	}
 }
 
+func gaugeStat(name string, g feature_stats.Gauge) *Stat {
+	return &Stat{
+		Name:  name,
+		Value: g.Value(),
+		Type:  Stat_Gauge,
+	}
+}
+
+func histogramStat(name string, h feature_stats.Histogram) *Stat {
+	snapshot := h.Snapshot()
+	histogram := &Histogram{
+		Count: snapshot.Count,
+		Sum:   snapshot.Sum,
+	}
+	for i, count := range snapshot.Counts {
+		bucket := &Histogram_Bucket{
+			Count: count,
+		}
+		if i < len(snapshot.Bounds) {
+			bucket.UpperBound = snapshot.Bounds[i]
+		} else {
+			bucket.Unbounded = true
+		}
+		histogram.Bucket = append(histogram.Bucket, bucket)
+	}
+	return &Stat{
+		Name:      name,
+		Value:     int64(snapshot.Count),
+		Type:      Stat_Histogram,
+		Histogram: histogram,
+	}
+}
+
 func (s *statsServer) GetStats(ctx context.Context, request *GetStatsRequest) (*GetStatsResponse, error) {
 	c := s.stats.GetCounter(request.Name)
 	if c == nil {
+		if gm, ok := s.stats.(feature_stats.GaugeManager); ok {
+			if g := gm.GetGauge(request.Name); g != nil {
+				return &GetStatsResponse{Stat: gaugeStat(request.Name, g)}, nil
+			}
+		}
+		if hm, ok := s.stats.(feature_stats.HistogramManager); ok {
+			if h := hm.GetHistogram(request.Name); h != nil {
+				return &GetStatsResponse{Stat: histogramStat(request.Name, h)}, nil
+			}
+		}
 		return nil, newError(request.Name, " not found.")
 	}
 	var value int64
@@ -63,6 +107,34 @@ func (s *statsServer) QueryStats(ctx context.Context, request *QueryStatsRequest
 		return nil, newError("QueryStats only works its own stats.Manager.")
 	}
 
+	types := make(map[Stat_Type]bool)
+	for _, t := range request.Type {
+		types[t] = true
+	}
+	queried := func(t Stat_Type) bool {
+		return len(types) == 0 || types[t]
+	}
+
+	if queried(Stat_Gauge) {
+		manager.VisitGauges(func(name string, g feature_stats.Gauge) bool {
+			if matcher.Match(name) {
+				response.Stat = append(response.Stat, gaugeStat(name, g))
+			}
+			return true
+		})
+	}
+	if queried(Stat_Histogram) {
+		manager.VisitHistograms(func(name string, h feature_stats.Histogram) bool {
+			if matcher.Match(name) {
+				response.Stat = append(response.Stat, histogramStat(name, h))
+			}
+			return true
+		})
+	}
+	if !queried(Stat_Counter) {
+		return response, nil
+	}
+
 	manager.Visit(func(name string, c feature_stats.Counter) bool {
 		if matcher.Match(name) {
 			var value int64
@@ -104,6 +176,45 @@ func (s *statsServer) GetSysStats(ctx context.Context, request *SysStatsRequest)
 	return response, nil
 }
 
+func (s *statsServer) GetDailyStats(ctx context.Context, request *GetDailyStatsRequest) (*GetDailyStatsResponse, error) {
+	matcher, err := strmatcher.Substr.New(request.Pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	manager, ok := s.stats.(*stats.Manager)
+	if !ok {
+		return nil, newError("GetDailyStats only works its own stats.Manager.")
+	}
+	buckets := manager.DailyStats()
+	if buckets == nil {
+		return nil, newError("daily stats are not enabled")
+	}
+	if request.Days > 0 && int(request.Days) < len(buckets) {
+		buckets = buckets[len(buckets)-int(request.Days):]
+	}
+
+	response := &GetDailyStatsResponse{}
+	for _, bucket := range buckets {
+		daily := &DailyStats{
+			Date: bucket.Date,
+		}
+		for name, value := range bucket.Counter {
+			if matcher.Match(name) {
+				daily.Stat = append(daily.Stat, &Stat{
+					Name:  name,
+					Value: value,
+				})
+			}
+		}
+		sort.Slice(daily.Stat, func(i, j int) bool {
+			return daily.Stat[i].Name < daily.Stat[j].Name
+		})
+		response.Daily = append(response.Daily, daily)
+	}
+	return response, nil
+}
+
 type service struct {
 	statsManager feature_stats.Manager
 }
@@ -1,6 +1,3 @@
-// Code generated by protoc-gen-go. DO NOT EDIT.
-// source: v2ray.com/core/app/stats/command/command.proto
-
 package command
 
 import (
@@ -24,6 +21,37 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type Stat_Type int32
+
+const (
+	// Counter of a total, such as traffic in bytes. It can be reset.
+	Stat_Counter Stat_Type = 0
+	// Current value that goes up and down, such as active connections.
+	Stat_Gauge Stat_Type = 1
+	// Observed values in buckets, such as connection durations in seconds.
+	Stat_Histogram Stat_Type = 2
+)
+
+var Stat_Type_name = map[int32]string{
+	0: "Counter",
+	1: "Gauge",
+	2: "Histogram",
+}
+
+var Stat_Type_value = map[string]int32{
+	"Counter":   0,
+	"Gauge":     1,
+	"Histogram": 2,
+}
+
+func (x Stat_Type) String() string {
+	return proto.EnumName(Stat_Type_name, int32(x))
+}
+
+func (Stat_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{1, 0}
+}
+
 type GetStatsRequest struct {
 	// Name of the stat counter.
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -74,11 +102,15 @@ func (m *GetStatsRequest) GetReset_() bool {
 }
 
 type Stat struct {
-	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value                int64    `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Value of counters and gauges, or the number of observed values of
+	// histograms.
+	Value                int64      `protobuf:"varint,2,opt,name=value,proto3" json:"value,omitempty"`
+	Type                 Stat_Type  `protobuf:"varint,3,opt,name=type,proto3,enum=v2ray.core.app.stats.command.Stat_Type" json:"type,omitempty"`
+	Histogram            *Histogram `protobuf:"bytes,4,opt,name=histogram,proto3" json:"histogram,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *Stat) Reset()         { *m = Stat{} }
@@ -120,6 +152,133 @@ func (m *Stat) GetValue() int64 {
 	return 0
 }
 
+func (m *Stat) GetType() Stat_Type {
+	if m != nil {
+		return m.Type
+	}
+	return Stat_Counter
+}
+
+func (m *Stat) GetHistogram() *Histogram {
+	if m != nil {
+		return m.Histogram
+	}
+	return nil
+}
+
+type Histogram struct {
+	Bucket               []*Histogram_Bucket `protobuf:"bytes,1,rep,name=bucket,proto3" json:"bucket,omitempty"`
+	Count                uint64              `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Sum                  float64             `protobuf:"fixed64,3,opt,name=sum,proto3" json:"sum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Histogram) Reset()         { *m = Histogram{} }
+func (m *Histogram) String() string { return proto.CompactTextString(m) }
+func (*Histogram) ProtoMessage()    {}
+func (*Histogram) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{2}
+}
+
+func (m *Histogram) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Histogram.Unmarshal(m, b)
+}
+func (m *Histogram) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Histogram.Marshal(b, m, deterministic)
+}
+func (m *Histogram) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Histogram.Merge(m, src)
+}
+func (m *Histogram) XXX_Size() int {
+	return xxx_messageInfo_Histogram.Size(m)
+}
+func (m *Histogram) XXX_DiscardUnknown() {
+	xxx_messageInfo_Histogram.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Histogram proto.InternalMessageInfo
+
+func (m *Histogram) GetBucket() []*Histogram_Bucket {
+	if m != nil {
+		return m.Bucket
+	}
+	return nil
+}
+
+func (m *Histogram) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *Histogram) GetSum() float64 {
+	if m != nil {
+		return m.Sum
+	}
+	return 0
+}
+
+type Histogram_Bucket struct {
+	// Inclusive upper bound of the bucket. The last bucket has no bound.
+	UpperBound float64 `protobuf:"fixed64,1,opt,name=upper_bound,json=upperBound,proto3" json:"upper_bound,omitempty"`
+	Unbounded  bool    `protobuf:"varint,2,opt,name=unbounded,proto3" json:"unbounded,omitempty"`
+	// Number of values in this bucket, not including those in the previous
+	// ones.
+	Count                uint64   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Histogram_Bucket) Reset()         { *m = Histogram_Bucket{} }
+func (m *Histogram_Bucket) String() string { return proto.CompactTextString(m) }
+func (*Histogram_Bucket) ProtoMessage()    {}
+func (*Histogram_Bucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{2, 0}
+}
+
+func (m *Histogram_Bucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Histogram_Bucket.Unmarshal(m, b)
+}
+func (m *Histogram_Bucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Histogram_Bucket.Marshal(b, m, deterministic)
+}
+func (m *Histogram_Bucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Histogram_Bucket.Merge(m, src)
+}
+func (m *Histogram_Bucket) XXX_Size() int {
+	return xxx_messageInfo_Histogram_Bucket.Size(m)
+}
+func (m *Histogram_Bucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_Histogram_Bucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Histogram_Bucket proto.InternalMessageInfo
+
+func (m *Histogram_Bucket) GetUpperBound() float64 {
+	if m != nil {
+		return m.UpperBound
+	}
+	return 0
+}
+
+func (m *Histogram_Bucket) GetUnbounded() bool {
+	if m != nil {
+		return m.Unbounded
+	}
+	return false
+}
+
+func (m *Histogram_Bucket) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
 type GetStatsResponse struct {
 	Stat                 *Stat    `protobuf:"bytes,1,opt,name=stat,proto3" json:"stat,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -131,7 +290,7 @@ func (m *GetStatsResponse) Reset()         { *m = GetStatsResponse{} }
 func (m *GetStatsResponse) String() string { return proto.CompactTextString(m) }
 func (*GetStatsResponse) ProtoMessage()    {}
 func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{2}
+	return fileDescriptor_c902411c4948f26b, []int{3}
 }
 
 func (m *GetStatsResponse) XXX_Unmarshal(b []byte) error {
@@ -160,18 +319,22 @@ func (m *GetStatsResponse) GetStat() *Stat {
 }
 
 type QueryStatsRequest struct {
-	Pattern              string   `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
-	Reset_               bool     `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// Reset counters to 0 after fetching their values. Gauges and histograms
+	// are not reset.
+	Reset_ bool `protobuf:"varint,2,opt,name=reset,proto3" json:"reset,omitempty"`
+	// Only query stats of these types. All types if empty.
+	Type                 []Stat_Type `protobuf:"varint,3,rep,packed,name=type,proto3,enum=v2ray.core.app.stats.command.Stat_Type" json:"type,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *QueryStatsRequest) Reset()         { *m = QueryStatsRequest{} }
 func (m *QueryStatsRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryStatsRequest) ProtoMessage()    {}
 func (*QueryStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{3}
+	return fileDescriptor_c902411c4948f26b, []int{4}
 }
 
 func (m *QueryStatsRequest) XXX_Unmarshal(b []byte) error {
@@ -206,6 +369,13 @@ func (m *QueryStatsRequest) GetReset_() bool {
 	return false
 }
 
+func (m *QueryStatsRequest) GetType() []Stat_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
 type QueryStatsResponse struct {
 	Stat                 []*Stat  `protobuf:"bytes,1,rep,name=stat,proto3" json:"stat,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -217,7 +387,7 @@ func (m *QueryStatsResponse) Reset()         { *m = QueryStatsResponse{} }
 func (m *QueryStatsResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryStatsResponse) ProtoMessage()    {}
 func (*QueryStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{4}
+	return fileDescriptor_c902411c4948f26b, []int{5}
 }
 
 func (m *QueryStatsResponse) XXX_Unmarshal(b []byte) error {
@@ -255,7 +425,7 @@ func (m *SysStatsRequest) Reset()         { *m = SysStatsRequest{} }
 func (m *SysStatsRequest) String() string { return proto.CompactTextString(m) }
 func (*SysStatsRequest) ProtoMessage()    {}
 func (*SysStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{5}
+	return fileDescriptor_c902411c4948f26b, []int{6}
 }
 
 func (m *SysStatsRequest) XXX_Unmarshal(b []byte) error {
@@ -296,7 +466,7 @@ func (m *SysStatsResponse) Reset()         { *m = SysStatsResponse{} }
 func (m *SysStatsResponse) String() string { return proto.CompactTextString(m) }
 func (*SysStatsResponse) ProtoMessage()    {}
 func (*SysStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{6}
+	return fileDescriptor_c902411c4948f26b, []int{7}
 }
 
 func (m *SysStatsResponse) XXX_Unmarshal(b []byte) error {
@@ -387,6 +557,143 @@ func (m *SysStatsResponse) GetUptime() uint32 {
 	return 0
 }
 
+type GetDailyStatsRequest struct {
+	Pattern string `protobuf:"bytes,1,opt,name=pattern,proto3" json:"pattern,omitempty"`
+	// Only return the latest days. All days if 0.
+	Days                 uint32   `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetDailyStatsRequest) Reset()         { *m = GetDailyStatsRequest{} }
+func (m *GetDailyStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*GetDailyStatsRequest) ProtoMessage()    {}
+func (*GetDailyStatsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{8}
+}
+
+func (m *GetDailyStatsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDailyStatsRequest.Unmarshal(m, b)
+}
+func (m *GetDailyStatsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDailyStatsRequest.Marshal(b, m, deterministic)
+}
+func (m *GetDailyStatsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDailyStatsRequest.Merge(m, src)
+}
+func (m *GetDailyStatsRequest) XXX_Size() int {
+	return xxx_messageInfo_GetDailyStatsRequest.Size(m)
+}
+func (m *GetDailyStatsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDailyStatsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDailyStatsRequest proto.InternalMessageInfo
+
+func (m *GetDailyStatsRequest) GetPattern() string {
+	if m != nil {
+		return m.Pattern
+	}
+	return ""
+}
+
+func (m *GetDailyStatsRequest) GetDays() uint32 {
+	if m != nil {
+		return m.Days
+	}
+	return 0
+}
+
+type DailyStats struct {
+	// Date in local time, in the format of "2006-01-02".
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Increments of counters in the day.
+	Stat                 []*Stat  `protobuf:"bytes,2,rep,name=stat,proto3" json:"stat,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DailyStats) Reset()         { *m = DailyStats{} }
+func (m *DailyStats) String() string { return proto.CompactTextString(m) }
+func (*DailyStats) ProtoMessage()    {}
+func (*DailyStats) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{9}
+}
+
+func (m *DailyStats) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyStats.Unmarshal(m, b)
+}
+func (m *DailyStats) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyStats.Marshal(b, m, deterministic)
+}
+func (m *DailyStats) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyStats.Merge(m, src)
+}
+func (m *DailyStats) XXX_Size() int {
+	return xxx_messageInfo_DailyStats.Size(m)
+}
+func (m *DailyStats) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyStats.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyStats proto.InternalMessageInfo
+
+func (m *DailyStats) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyStats) GetStat() []*Stat {
+	if m != nil {
+		return m.Stat
+	}
+	return nil
+}
+
+type GetDailyStatsResponse struct {
+	// Daily stats, from the oldest to the newest.
+	Daily                []*DailyStats `protobuf:"bytes,1,rep,name=daily,proto3" json:"daily,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *GetDailyStatsResponse) Reset()         { *m = GetDailyStatsResponse{} }
+func (m *GetDailyStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*GetDailyStatsResponse) ProtoMessage()    {}
+func (*GetDailyStatsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c902411c4948f26b, []int{10}
+}
+
+func (m *GetDailyStatsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetDailyStatsResponse.Unmarshal(m, b)
+}
+func (m *GetDailyStatsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetDailyStatsResponse.Marshal(b, m, deterministic)
+}
+func (m *GetDailyStatsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetDailyStatsResponse.Merge(m, src)
+}
+func (m *GetDailyStatsResponse) XXX_Size() int {
+	return xxx_messageInfo_GetDailyStatsResponse.Size(m)
+}
+func (m *GetDailyStatsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetDailyStatsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetDailyStatsResponse proto.InternalMessageInfo
+
+func (m *GetDailyStatsResponse) GetDaily() []*DailyStats {
+	if m != nil {
+		return m.Daily
+	}
+	return nil
+}
+
 type Config struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -397,7 +704,7 @@ func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c902411c4948f26b, []int{7}
+	return fileDescriptor_c902411c4948f26b, []int{11}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -419,13 +726,19 @@ func (m *Config) XXX_DiscardUnknown() {
 var xxx_messageInfo_Config proto.InternalMessageInfo
 
 func init() {
+	proto.RegisterEnum("v2ray.core.app.stats.command.Stat_Type", Stat_Type_name, Stat_Type_value)
 	proto.RegisterType((*GetStatsRequest)(nil), "v2ray.core.app.stats.command.GetStatsRequest")
 	proto.RegisterType((*Stat)(nil), "v2ray.core.app.stats.command.Stat")
+	proto.RegisterType((*Histogram)(nil), "v2ray.core.app.stats.command.Histogram")
+	proto.RegisterType((*Histogram_Bucket)(nil), "v2ray.core.app.stats.command.Histogram.Bucket")
 	proto.RegisterType((*GetStatsResponse)(nil), "v2ray.core.app.stats.command.GetStatsResponse")
 	proto.RegisterType((*QueryStatsRequest)(nil), "v2ray.core.app.stats.command.QueryStatsRequest")
 	proto.RegisterType((*QueryStatsResponse)(nil), "v2ray.core.app.stats.command.QueryStatsResponse")
 	proto.RegisterType((*SysStatsRequest)(nil), "v2ray.core.app.stats.command.SysStatsRequest")
 	proto.RegisterType((*SysStatsResponse)(nil), "v2ray.core.app.stats.command.SysStatsResponse")
+	proto.RegisterType((*GetDailyStatsRequest)(nil), "v2ray.core.app.stats.command.GetDailyStatsRequest")
+	proto.RegisterType((*DailyStats)(nil), "v2ray.core.app.stats.command.DailyStats")
+	proto.RegisterType((*GetDailyStatsResponse)(nil), "v2ray.core.app.stats.command.GetDailyStatsResponse")
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.stats.command.Config")
 }
 
@@ -434,38 +747,53 @@ func init() {
 }
 
 var fileDescriptor_c902411c4948f26b = []byte{
-	// 488 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x6f, 0xd3, 0x30,
-	0x14, 0x5d, 0xdb, 0xac, 0xed, 0x6e, 0x37, 0xad, 0xb3, 0x10, 0xb2, 0xa6, 0x09, 0x45, 0x7e, 0xea,
-	0x0b, 0xce, 0x54, 0x24, 0x5e, 0x78, 0x1a, 0x91, 0x98, 0x84, 0xca, 0x18, 0x29, 0xf0, 0xc0, 0x9b,
-	0x17, 0x2e, 0x28, 0xd0, 0xc4, 0x9e, 0xed, 0x54, 0xca, 0xdf, 0xe1, 0x91, 0x1f, 0xc5, 0x6f, 0x41,
-	0x76, 0x12, 0xfa, 0x31, 0xad, 0x1b, 0x4f, 0xf5, 0x39, 0xf7, 0x9e, 0xdc, 0x73, 0xed, 0xa3, 0x02,
-	0x5f, 0x4e, 0xb5, 0xa8, 0x78, 0x2a, 0xf3, 0x28, 0x95, 0x1a, 0x23, 0xa1, 0x54, 0x64, 0xac, 0xb0,
-	0x26, 0x4a, 0x65, 0x9e, 0x8b, 0xe2, 0x6b, 0xfb, 0xcb, 0x95, 0x96, 0x56, 0x92, 0xb3, 0xb6, 0x5f,
-	0x23, 0x17, 0x4a, 0x71, 0xdf, 0xcb, 0x9b, 0x1e, 0xf6, 0x0a, 0x8e, 0x2f, 0xd1, 0xce, 0x1d, 0x97,
-	0xe0, 0x6d, 0x89, 0xc6, 0x12, 0x02, 0x41, 0x21, 0x72, 0xa4, 0x9d, 0xb0, 0x33, 0x39, 0x48, 0xfc,
-	0x99, 0x3c, 0x81, 0x7d, 0x8d, 0x06, 0x2d, 0xed, 0x86, 0x9d, 0xc9, 0x30, 0xa9, 0x01, 0x3b, 0x87,
-	0xc0, 0x29, 0xef, 0x53, 0x2c, 0xc5, 0xa2, 0x44, 0xaf, 0xe8, 0x25, 0x35, 0x60, 0x6f, 0x61, 0xbc,
-	0x1a, 0x67, 0x94, 0x2c, 0x0c, 0x92, 0x97, 0x10, 0x38, 0x4f, 0x5e, 0x3d, 0x9a, 0x32, 0xbe, 0xcb,
-	0x2f, 0x77, 0xd2, 0xc4, 0xf7, 0xb3, 0x18, 0x4e, 0x3e, 0x94, 0xa8, 0xab, 0x0d, 0xf3, 0x14, 0x06,
-	0x4a, 0x58, 0x8b, 0xba, 0x68, 0xdc, 0xb4, 0xf0, 0x9e, 0x15, 0x66, 0x40, 0xd6, 0x3f, 0x72, 0xc7,
-	0x52, 0xef, 0xbf, 0x2c, 0x9d, 0xc0, 0xf1, 0xbc, 0x32, 0xeb, 0x86, 0xd8, 0xaf, 0x2e, 0x8c, 0x57,
-	0x5c, 0xf3, 0x7d, 0x06, 0x87, 0x57, 0x65, 0x7e, 0x29, 0xb5, 0x2c, 0x6d, 0x56, 0xd4, 0x17, 0x77,
-	0x94, 0x6c, 0x70, 0xce, 0xaf, 0xc3, 0xb1, 0xf7, 0x7b, 0x94, 0xd4, 0xc0, 0xb1, 0x17, 0x8b, 0x85,
-	0x4c, 0x69, 0x2f, 0xec, 0x4c, 0x82, 0xa4, 0x06, 0xe4, 0x19, 0xc0, 0x47, 0x69, 0xc5, 0xa2, 0x2e,
-	0x05, 0xbe, 0xb4, 0xc6, 0x90, 0x31, 0xf4, 0xe6, 0x95, 0xa1, 0xfb, 0xbe, 0xe0, 0x8e, 0xee, 0x9e,
-	0xde, 0x09, 0x57, 0x33, 0xb4, 0xef, 0xd9, 0x16, 0xba, 0x09, 0x6f, 0x34, 0xa2, 0xa1, 0x83, 0x7a,
-	0x82, 0x07, 0x24, 0x84, 0xd1, 0x2c, 0x5b, 0xe2, 0xfb, 0x9b, 0x1f, 0x98, 0x5a, 0x43, 0x87, 0xbe,
-	0xb6, 0x4e, 0xb9, 0x9d, 0xae, 0x45, 0x69, 0xd0, 0x8f, 0xbd, 0x32, 0xf4, 0xc0, 0xb7, 0x6c, 0x70,
-	0xe4, 0x29, 0xf4, 0x3f, 0x29, 0x9b, 0xe5, 0x48, 0xc1, 0x2f, 0xd5, 0x20, 0x36, 0x84, 0x7e, 0x2c,
-	0x8b, 0x6f, 0xd9, 0xf7, 0xe9, 0x9f, 0x2e, 0x1c, 0xfa, 0xbb, 0x9a, 0xa3, 0x5e, 0x66, 0x29, 0x92,
-	0x9f, 0x30, 0x6c, 0x13, 0x43, 0x9e, 0xef, 0x7e, 0x88, 0xad, 0x20, 0x9f, 0xf2, 0xc7, 0xb6, 0xd7,
-	0xaf, 0xc2, 0xf6, 0xc8, 0x2d, 0xc0, 0x2a, 0x0d, 0x24, 0xda, 0xad, 0xbf, 0x13, 0xbe, 0xd3, 0xf3,
-	0xc7, 0x0b, 0xfe, 0x8d, 0x2c, 0x60, 0xe4, 0x8c, 0x34, 0x09, 0x79, 0x68, 0xc5, 0xad, 0x74, 0x3d,
-	0xb4, 0xe2, 0x76, 0xf0, 0xd8, 0xde, 0xeb, 0x19, 0x84, 0xa9, 0xcc, 0x77, 0xca, 0xae, 0x3b, 0x5f,
-	0x06, 0xcd, 0xf1, 0x77, 0xf7, 0xec, 0xf3, 0x34, 0x11, 0x15, 0x8f, 0x5d, 0xe7, 0x85, 0x52, 0x3e,
-	0xed, 0x86, 0xc7, 0x75, 0xf9, 0xa6, 0xef, 0xff, 0x63, 0x5e, 0xfc, 0x0d, 0x00, 0x00, 0xff, 0xff,
-	0x25, 0xa8, 0x5c, 0x1b, 0x95, 0x04, 0x00, 0x00,
+	// 736 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x55, 0x5d, 0x6f, 0xd3, 0x3c,
+	0x14, 0x5e, 0x9a, 0xf4, 0xeb, 0x74, 0x7d, 0xd7, 0x59, 0x7b, 0x51, 0x54, 0x4d, 0x50, 0xe5, 0x86,
+	0xde, 0xcc, 0x45, 0x9d, 0xc4, 0xcd, 0x24, 0xa4, 0xad, 0x63, 0x45, 0x68, 0x8c, 0x91, 0x8e, 0x0f,
+	0x21, 0x21, 0xe4, 0xa6, 0x66, 0x94, 0x35, 0xb1, 0x17, 0x3b, 0x95, 0xc2, 0x15, 0xbf, 0x85, 0x4b,
+	0x7e, 0xd2, 0x6e, 0xf9, 0x23, 0xc8, 0x4e, 0xd2, 0xa6, 0x1b, 0x74, 0xed, 0x55, 0xfd, 0x3c, 0xe7,
+	0x3c, 0xe7, 0xf8, 0x7c, 0x38, 0x05, 0x3c, 0xed, 0x86, 0x24, 0xc6, 0x1e, 0xf3, 0x3b, 0x1e, 0x0b,
+	0x69, 0x87, 0x70, 0xde, 0x11, 0x92, 0x48, 0xd1, 0xf1, 0x98, 0xef, 0x93, 0x60, 0x94, 0xfd, 0x62,
+	0x1e, 0x32, 0xc9, 0xd0, 0x6e, 0xe6, 0x1f, 0x52, 0x4c, 0x38, 0xc7, 0xda, 0x17, 0xa7, 0x3e, 0xce,
+	0x01, 0x6c, 0xf5, 0xa9, 0x1c, 0x28, 0xce, 0xa5, 0xd7, 0x11, 0x15, 0x12, 0x21, 0xb0, 0x02, 0xe2,
+	0x53, 0xdb, 0x68, 0x19, 0xed, 0xaa, 0xab, 0xcf, 0x68, 0x07, 0x8a, 0x21, 0x15, 0x54, 0xda, 0x85,
+	0x96, 0xd1, 0xae, 0xb8, 0x09, 0x70, 0x7e, 0x1b, 0x60, 0x29, 0xe9, 0xbf, 0x24, 0x53, 0x32, 0x89,
+	0xa8, 0x96, 0x98, 0x6e, 0x02, 0xd0, 0x01, 0x58, 0x32, 0xe6, 0xd4, 0x36, 0x5b, 0x46, 0xfb, 0xbf,
+	0xee, 0x63, 0xbc, 0xec, 0x72, 0x58, 0xc5, 0xc6, 0x17, 0x31, 0xa7, 0xae, 0x16, 0xa1, 0xe7, 0x50,
+	0xfd, 0x3a, 0x16, 0x92, 0x5d, 0x86, 0xc4, 0xb7, 0xad, 0x96, 0xd1, 0xae, 0xdd, 0x17, 0xe1, 0x45,
+	0xe6, 0xee, 0xce, 0x95, 0xce, 0x1e, 0x58, 0x2a, 0x28, 0xaa, 0x41, 0xb9, 0xc7, 0xa2, 0x40, 0xd2,
+	0xb0, 0xb1, 0x81, 0xaa, 0x50, 0xec, 0x93, 0xe8, 0x92, 0x36, 0x0c, 0x54, 0x87, 0xea, 0x4c, 0xd7,
+	0x28, 0x38, 0x37, 0x46, 0x0e, 0xa3, 0x13, 0x28, 0x0d, 0x23, 0xef, 0x8a, 0x4a, 0xdb, 0x68, 0x99,
+	0xed, 0x5a, 0x17, 0xaf, 0x78, 0x01, 0x7c, 0xa4, 0x55, 0x6e, 0xaa, 0x56, 0xed, 0xf1, 0x54, 0x72,
+	0xdd, 0x1e, 0xcb, 0x4d, 0x00, 0x6a, 0x80, 0x29, 0x22, 0x5f, 0x77, 0xc7, 0x70, 0xd5, 0xb1, 0xf9,
+	0x09, 0x4a, 0x89, 0x12, 0x3d, 0x82, 0x5a, 0xc4, 0x39, 0x0d, 0x3f, 0x0f, 0x59, 0x14, 0x8c, 0x74,
+	0xaf, 0x0d, 0x17, 0x34, 0x75, 0xa4, 0x18, 0xb4, 0x0b, 0xd5, 0x28, 0xd0, 0x46, 0x3a, 0x4a, 0x07,
+	0x35, 0x27, 0xe6, 0x09, 0xcd, 0x5c, 0x42, 0xe7, 0x25, 0x34, 0xe6, 0xf3, 0x17, 0x9c, 0x05, 0x82,
+	0xa2, 0xa7, 0x60, 0xa9, 0x22, 0x74, 0x86, 0x5a, 0xd7, 0xb9, 0x7f, 0x46, 0xae, 0xf6, 0x77, 0x7e,
+	0x18, 0xb0, 0xfd, 0x26, 0xa2, 0x61, 0xbc, 0xb0, 0x4e, 0x36, 0x94, 0x39, 0x91, 0x92, 0x86, 0x41,
+	0xba, 0x1e, 0x19, 0xfc, 0xfb, 0x52, 0xe5, 0x36, 0xc4, 0x5c, 0x7b, 0x43, 0x9c, 0x53, 0x40, 0xf9,
+	0x1b, 0xdc, 0x29, 0xc8, 0x5c, 0xab, 0xa0, 0x6d, 0xd8, 0x1a, 0xc4, 0x22, 0x5f, 0x8d, 0xf3, 0xb3,
+	0x00, 0x8d, 0x39, 0x97, 0xc6, 0x77, 0x60, 0xf3, 0x2c, 0xf2, 0xfb, 0x2c, 0x64, 0x91, 0x1c, 0x07,
+	0xc9, 0x33, 0xa8, 0xbb, 0x0b, 0x9c, 0x2a, 0x56, 0xe1, 0x9e, 0x2e, 0xb6, 0xee, 0x26, 0x40, 0xb1,
+	0x87, 0x93, 0x09, 0xf3, 0xb2, 0xa1, 0x68, 0x80, 0x1e, 0x02, 0x5c, 0x30, 0x49, 0x26, 0x89, 0xc9,
+	0xd2, 0xa6, 0x1c, 0xa3, 0xb6, 0x64, 0x10, 0x0b, 0xbb, 0xa8, 0x0d, 0xea, 0xa8, 0x9a, 0xfc, 0x8a,
+	0x28, 0x9b, 0xb0, 0x4b, 0x9a, 0xcd, 0xa0, 0xca, 0x70, 0x12, 0x52, 0x2a, 0xec, 0x72, 0x92, 0x41,
+	0x03, 0xd4, 0x82, 0xda, 0xe9, 0x78, 0x4a, 0x5f, 0x0f, 0xbf, 0x51, 0x4f, 0x0a, 0xbb, 0xa2, 0x6d,
+	0x79, 0x4a, 0xd5, 0x74, 0x4e, 0x22, 0x41, 0x75, 0xda, 0x33, 0x61, 0x57, 0xb5, 0xcb, 0x02, 0x87,
+	0x1e, 0x40, 0xe9, 0x2d, 0x97, 0x63, 0x9f, 0xda, 0xa0, 0x8b, 0x4a, 0x91, 0x73, 0x0c, 0x3b, 0x7d,
+	0x2a, 0x8f, 0xc9, 0x78, 0xb2, 0xea, 0x2a, 0x20, 0xb0, 0x46, 0x24, 0x16, 0x69, 0x73, 0xf4, 0xd9,
+	0xf9, 0x00, 0x30, 0x0f, 0x91, 0x78, 0xc8, 0xd9, 0x27, 0x46, 0x9d, 0x67, 0x73, 0x2d, 0xac, 0x39,
+	0xd7, 0xf7, 0xf0, 0xff, 0xad, 0xfb, 0xa5, 0x83, 0x7c, 0x06, 0xc5, 0x91, 0x62, 0xd3, 0x4d, 0x69,
+	0x2f, 0x8f, 0x98, 0x0b, 0x90, 0xc8, 0x9c, 0x0a, 0x94, 0x7a, 0x2c, 0xf8, 0x32, 0xbe, 0xec, 0xde,
+	0x98, 0xb0, 0xa9, 0x4d, 0x03, 0x1a, 0x4e, 0xc7, 0x1e, 0x45, 0x57, 0x50, 0xc9, 0x1e, 0x1a, 0xda,
+	0x5b, 0x1e, 0xf7, 0xd6, 0x07, 0xb9, 0x89, 0x57, 0x75, 0x4f, 0xaa, 0x70, 0x36, 0xd0, 0x35, 0xc0,
+	0xfc, 0x19, 0xa0, 0xce, 0x72, 0xfd, 0x9d, 0x27, 0xdb, 0x7c, 0xb2, 0xba, 0x60, 0x96, 0x32, 0x80,
+	0x9a, 0xba, 0x48, 0x2c, 0x56, 0x2a, 0xf1, 0xd6, 0xb3, 0x6a, 0xe2, 0x55, 0xdd, 0x67, 0xf9, 0xbe,
+	0x43, 0x7d, 0x61, 0x86, 0xa8, 0x7b, 0x6f, 0x97, 0xee, 0x2c, 0x64, 0x73, 0x7f, 0x2d, 0x4d, 0x96,
+	0xfb, 0xe8, 0x14, 0x5a, 0x1e, 0xf3, 0x97, 0x6a, 0xcf, 0x8d, 0x8f, 0xe5, 0xf4, 0xf8, 0xab, 0xb0,
+	0xfb, 0xae, 0xeb, 0x92, 0x18, 0xf7, 0x94, 0xe7, 0x21, 0xe7, 0x7a, 0x15, 0x05, 0xee, 0x25, 0xe6,
+	0x61, 0x49, 0xff, 0x4f, 0xef, 0xff, 0x19, 0x00, 0x5c, 0x3a, 0xc4, 0x0f, 0xd9, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -483,6 +811,7 @@ type StatsServiceClient interface {
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
 	QueryStats(ctx context.Context, in *QueryStatsRequest, opts ...grpc.CallOption) (*QueryStatsResponse, error)
 	GetSysStats(ctx context.Context, in *SysStatsRequest, opts ...grpc.CallOption) (*SysStatsResponse, error)
+	GetDailyStats(ctx context.Context, in *GetDailyStatsRequest, opts ...grpc.CallOption) (*GetDailyStatsResponse, error)
 }
 
 type statsServiceClient struct {
@@ -520,11 +849,21 @@ func (c *statsServiceClient) GetSysStats(ctx context.Context, in *SysStatsReques
 	return out, nil
 }
 
+func (c *statsServiceClient) GetDailyStats(ctx context.Context, in *GetDailyStatsRequest, opts ...grpc.CallOption) (*GetDailyStatsResponse, error) {
+	out := new(GetDailyStatsResponse)
+	err := c.cc.Invoke(ctx, "/v2ray.core.app.stats.command.StatsService/GetDailyStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatsServiceServer is the server API for StatsService service.
 type StatsServiceServer interface {
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
 	QueryStats(context.Context, *QueryStatsRequest) (*QueryStatsResponse, error)
 	GetSysStats(context.Context, *SysStatsRequest) (*SysStatsResponse, error)
+	GetDailyStats(context.Context, *GetDailyStatsRequest) (*GetDailyStatsResponse, error)
 }
 
 // UnimplementedStatsServiceServer can be embedded to have forward compatible implementations.
@@ -540,6 +879,9 @@ func (*UnimplementedStatsServiceServer) QueryStats(ctx context.Context, req *Que
 func (*UnimplementedStatsServiceServer) GetSysStats(ctx context.Context, req *SysStatsRequest) (*SysStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSysStats not implemented")
 }
+func (*UnimplementedStatsServiceServer) GetDailyStats(ctx context.Context, req *GetDailyStatsRequest) (*GetDailyStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailyStats not implemented")
+}
 
 func RegisterStatsServiceServer(s *grpc.Server, srv StatsServiceServer) {
 	s.RegisterService(&_StatsService_serviceDesc, srv)
@@ -599,6 +941,24 @@ func _StatsService_GetSysStats_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatsService_GetDailyStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDailyStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetDailyStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/v2ray.core.app.stats.command.StatsService/GetDailyStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetDailyStats(ctx, req.(*GetDailyStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _StatsService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "v2ray.core.app.stats.command.StatsService",
 	HandlerType: (*StatsServiceServer)(nil),
@@ -615,6 +975,10 @@ var _StatsService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetSysStats",
 			Handler:    _StatsService_GetSysStats_Handler,
 		},
+		{
+			MethodName: "GetDailyStats",
+			Handler:    _StatsService_GetDailyStats_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v2ray.com/core/app/stats/command/command.proto",
@@ -2,7 +2,11 @@ package command_test
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -89,3 +93,108 @@ func TestQueryStats(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestQueryTypedStats(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	c, err := m.RegisterCounter("outbound>>>direct>>>dial>>>failure>>>refused")
+	common.Must(err)
+	c.Set(3)
+
+	g, err := m.RegisterGauge("outbound>>>direct>>>connection>>>active")
+	common.Must(err)
+	g.Add(2)
+
+	h, err := m.RegisterHistogram("outbound>>>direct>>>connection>>>duration", []float64{1})
+	common.Must(err)
+	h.Observe(0.5)
+	h.Observe(2)
+
+	s := NewStatsServer(m)
+	resp, err := s.QueryStats(context.Background(), &QueryStatsRequest{
+		Pattern: "outbound>>>direct>>>",
+		Reset_:  true,
+	})
+	common.Must(err)
+	if r := cmp.Diff(resp.Stat, []*Stat{
+		{Name: "outbound>>>direct>>>connection>>>active", Value: 2, Type: Stat_Gauge},
+		{Name: "outbound>>>direct>>>connection>>>duration", Value: 2, Type: Stat_Histogram, Histogram: &Histogram{
+			Bucket: []*Histogram_Bucket{
+				{UpperBound: 1, Count: 1},
+				{Unbounded: true, Count: 1},
+			},
+			Count: 2,
+			Sum:   2.5,
+		}},
+		{Name: "outbound>>>direct>>>dial>>>failure>>>refused", Value: 3},
+	}, cmpopts.SortSlices(func(s1, s2 *Stat) bool { return s1.Name < s2.Name })); r != "" {
+		t.Error(r)
+	}
+
+	resp, err = s.QueryStats(context.Background(), &QueryStatsRequest{
+		Pattern: "outbound>>>direct>>>",
+		Type:    []Stat_Type{Stat_Gauge},
+	})
+	common.Must(err)
+	if len(resp.Stat) != 1 || resp.Stat[0].Value != 2 {
+		t.Error("unexpected gauges: ", resp.Stat)
+	}
+
+	getResp, err := s.GetStats(context.Background(), &GetStatsRequest{
+		Name: "outbound>>>direct>>>dial>>>failure>>>refused",
+	})
+	common.Must(err)
+	if getResp.Stat.Value != 0 {
+		t.Error("expect counter reset, but got ", getResp.Stat.Value)
+	}
+}
+
+func TestGetDailyStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	m, err := stats.NewManager(context.Background(), &stats.Config{
+		Persistence: &stats.PersistenceConfig{
+			Path:         filepath.Join(dir, "stats.dat"),
+			DailyBuckets: 7,
+		},
+	})
+	common.Must(err)
+
+	for name, value := range map[string]int64{
+		"user>>>a>>>traffic>>>uplink":     1,
+		"user>>>b>>>traffic>>>uplink":     2,
+		"inbound>>>in>>>traffic>>>uplink": 3,
+	} {
+		c, err := m.RegisterCounter(name)
+		common.Must(err)
+		c.Add(value)
+	}
+
+	s := NewStatsServer(m)
+	resp, err := s.GetDailyStats(context.Background(), &GetDailyStatsRequest{
+		Pattern: "user>>>",
+	})
+	common.Must(err)
+	if r := cmp.Diff(resp, &GetDailyStatsResponse{
+		Daily: []*DailyStats{
+			{
+				Date: time.Now().Format("2006-01-02"),
+				Stat: []*Stat{
+					{Name: "user>>>a>>>traffic>>>uplink", Value: 1},
+					{Name: "user>>>b>>>traffic>>>uplink", Value: 2},
+				},
+			},
+		},
+	}); r != "" {
+		t.Error(r)
+	}
+
+	m, err = stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+	if _, err := NewStatsServer(m).GetDailyStats(context.Background(), &GetDailyStatsRequest{}); err == nil {
+		t.Error("expect error when daily stats are disabled, but nil")
+	}
+}
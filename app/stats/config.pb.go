@@ -18,9 +18,14 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
 type Config struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	// HTTP endpoint of stats in Prometheus text format. It is disabled if not
+	// set.
+	Metrics *MetricsConfig `protobuf:"bytes,1,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Persistence of counters. Counters are kept in memory only if not set.
+	Persistence          *PersistenceConfig `protobuf:"bytes,2,opt,name=persistence,proto3" json:"persistence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
@@ -48,8 +53,149 @@ func (m *Config) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Config proto.InternalMessageInfo
 
+func (m *Config) GetMetrics() *MetricsConfig {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+func (m *Config) GetPersistence() *PersistenceConfig {
+	if m != nil {
+		return m.Persistence
+	}
+	return nil
+}
+
+type MetricsConfig struct {
+	// Address to listen on, such as "127.0.0.1:9100".
+	Listen string `protobuf:"bytes,1,opt,name=listen,proto3" json:"listen,omitempty"`
+	// Path of the endpoint. Default "/metrics".
+	Path string `protobuf:"bytes,2,opt,name=path,proto3" json:"path,omitempty"`
+	// Credentials of HTTP basic auth. Auth is disabled if username is empty.
+	Username             string   `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Password             string   `protobuf:"bytes,4,opt,name=password,proto3" json:"password,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetricsConfig) Reset()         { *m = MetricsConfig{} }
+func (m *MetricsConfig) String() string { return proto.CompactTextString(m) }
+func (*MetricsConfig) ProtoMessage()    {}
+func (*MetricsConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d494ded44ceaa50d, []int{1}
+}
+
+func (m *MetricsConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetricsConfig.Unmarshal(m, b)
+}
+func (m *MetricsConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetricsConfig.Marshal(b, m, deterministic)
+}
+func (m *MetricsConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetricsConfig.Merge(m, src)
+}
+func (m *MetricsConfig) XXX_Size() int {
+	return xxx_messageInfo_MetricsConfig.Size(m)
+}
+func (m *MetricsConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetricsConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetricsConfig proto.InternalMessageInfo
+
+func (m *MetricsConfig) GetListen() string {
+	if m != nil {
+		return m.Listen
+	}
+	return ""
+}
+
+func (m *MetricsConfig) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *MetricsConfig) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *MetricsConfig) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type PersistenceConfig struct {
+	// Path of the snapshot file.
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Interval in seconds between snapshots. Default 60.
+	Interval uint32 `protobuf:"varint,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	// Number of days that daily buckets are kept for. Daily buckets are
+	// disabled if 0.
+	DailyBuckets         uint32   `protobuf:"varint,3,opt,name=daily_buckets,json=dailyBuckets,proto3" json:"daily_buckets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PersistenceConfig) Reset()         { *m = PersistenceConfig{} }
+func (m *PersistenceConfig) String() string { return proto.CompactTextString(m) }
+func (*PersistenceConfig) ProtoMessage()    {}
+func (*PersistenceConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d494ded44ceaa50d, []int{2}
+}
+
+func (m *PersistenceConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PersistenceConfig.Unmarshal(m, b)
+}
+func (m *PersistenceConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PersistenceConfig.Marshal(b, m, deterministic)
+}
+func (m *PersistenceConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PersistenceConfig.Merge(m, src)
+}
+func (m *PersistenceConfig) XXX_Size() int {
+	return xxx_messageInfo_PersistenceConfig.Size(m)
+}
+func (m *PersistenceConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_PersistenceConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PersistenceConfig proto.InternalMessageInfo
+
+func (m *PersistenceConfig) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *PersistenceConfig) GetInterval() uint32 {
+	if m != nil {
+		return m.Interval
+	}
+	return 0
+}
+
+func (m *PersistenceConfig) GetDailyBuckets() uint32 {
+	if m != nil {
+		return m.DailyBuckets
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.app.stats.Config")
+	proto.RegisterType((*MetricsConfig)(nil), "v2ray.core.app.stats.MetricsConfig")
+	proto.RegisterType((*PersistenceConfig)(nil), "v2ray.core.app.stats.PersistenceConfig")
 }
 
 func init() {
@@ -57,13 +203,23 @@ func init() {
 }
 
 var fileDescriptor_d494ded44ceaa50d = []byte{
-	// 123 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2d, 0x33, 0x2a, 0x4a,
-	0xac, 0xd4, 0x4b, 0xce, 0xcf, 0xd5, 0x4f, 0xce, 0x2f, 0x4a, 0xd5, 0x4f, 0x2c, 0x28, 0xd0, 0x2f,
-	0x2e, 0x49, 0x2c, 0x29, 0xd6, 0x4f, 0xce, 0xcf, 0x4b, 0xcb, 0x4c, 0xd7, 0x2b, 0x28, 0xca, 0x2f,
-	0xc9, 0x17, 0x12, 0x81, 0x29, 0x2b, 0x4a, 0xd5, 0x4b, 0x2c, 0x28, 0xd0, 0x03, 0x2b, 0x51, 0xe2,
-	0xe0, 0x62, 0x73, 0x06, 0xab, 0x72, 0xb2, 0xe2, 0x92, 0x48, 0xce, 0xcf, 0xd5, 0xc3, 0xa6, 0x2a,
-	0x80, 0x31, 0x8a, 0x15, 0xcc, 0x58, 0xc5, 0x24, 0x12, 0x66, 0x14, 0x94, 0x58, 0xa9, 0xe7, 0x0c,
-	0x92, 0x77, 0x2c, 0x28, 0xd0, 0x0b, 0x06, 0x09, 0x27, 0xb1, 0x81, 0xad, 0x30, 0x06, 0x04, 0x00,
-	0x00, 0xff, 0xff, 0x88, 0x24, 0xc6, 0x41, 0x8b, 0x00, 0x00, 0x00,
+	// 288 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0x4f, 0x4b, 0xc3, 0x30,
+	0x14, 0xa7, 0x73, 0x56, 0xf7, 0x66, 0x0f, 0x86, 0x21, 0xc5, 0x93, 0x74, 0x88, 0x9e, 0x52, 0xa8,
+	0x37, 0xc1, 0x83, 0xdb, 0xc9, 0x83, 0x30, 0x22, 0x78, 0xf0, 0x22, 0x59, 0x16, 0x5d, 0xb0, 0x6d,
+	0x42, 0x92, 0x4d, 0xfa, 0x35, 0xfc, 0x18, 0x7e, 0x4a, 0xe9, 0xeb, 0x5a, 0x1d, 0xf6, 0xf6, 0x7e,
+	0xff, 0xde, 0xef, 0x91, 0xc0, 0xe5, 0x36, 0xb3, 0xbc, 0xa2, 0x42, 0x17, 0xa9, 0xd0, 0x56, 0xa6,
+	0xdc, 0x98, 0xd4, 0x79, 0xee, 0x5d, 0x2a, 0x74, 0xf9, 0xa6, 0xde, 0xa9, 0xb1, 0xda, 0x6b, 0x32,
+	0x69, 0x6d, 0x56, 0x52, 0x6e, 0x0c, 0x45, 0x4b, 0xf2, 0x15, 0x40, 0x38, 0x47, 0x1b, 0xb9, 0x83,
+	0xa3, 0x42, 0x7a, 0xab, 0x84, 0x8b, 0x83, 0x8b, 0xe0, 0x7a, 0x9c, 0x4d, 0x69, 0x5f, 0x84, 0x3e,
+	0x36, 0xa6, 0x26, 0xc5, 0xda, 0x0c, 0x79, 0x80, 0xb1, 0x91, 0xd6, 0x29, 0xe7, 0x65, 0x29, 0x64,
+	0x3c, 0xc0, 0x15, 0x57, 0xfd, 0x2b, 0x16, 0xbf, 0xc6, 0xdd, 0x9a, 0xbf, 0xd9, 0xc4, 0x41, 0xb4,
+	0x57, 0x42, 0xce, 0x20, 0xcc, 0x51, 0xc4, 0xcb, 0x46, 0x6c, 0x87, 0x08, 0x81, 0xa1, 0xe1, 0x7e,
+	0x8d, 0x65, 0x23, 0x86, 0x33, 0x39, 0x87, 0xe3, 0x8d, 0x93, 0xb6, 0xe4, 0x85, 0x8c, 0x0f, 0x90,
+	0xef, 0x70, 0xad, 0x19, 0xee, 0xdc, 0xa7, 0xb6, 0xab, 0x78, 0xd8, 0x68, 0x2d, 0x4e, 0xd6, 0x70,
+	0xfa, 0xef, 0xac, 0xae, 0x20, 0xd8, 0x2f, 0x50, 0xa5, 0x97, 0x76, 0xcb, 0x73, 0x2c, 0x8e, 0x58,
+	0x87, 0xc9, 0x14, 0xa2, 0x15, 0x57, 0x79, 0xf5, 0xba, 0xdc, 0x88, 0x0f, 0xe9, 0x1d, 0x5e, 0x10,
+	0xb1, 0x13, 0x24, 0x67, 0x0d, 0x37, 0xbb, 0x85, 0x58, 0xe8, 0xa2, 0xf7, 0x65, 0x16, 0xc1, 0xcb,
+	0x21, 0x0e, 0xdf, 0x83, 0xc9, 0x73, 0xc6, 0x78, 0x45, 0xe7, 0xb5, 0x7e, 0x6f, 0x0c, 0x7d, 0xaa,
+	0xe9, 0x65, 0x88, 0x9f, 0x79, 0xf3, 0x33, 0x00, 0x00, 0xf6, 0xb7, 0xe6, 0xf5, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,283 @@
+// +build !confonly
+
+package stats
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"v2ray.com/core/features/stats"
+)
+
+const metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricsServer serves stats of a Manager over HTTP, in Prometheus text format.
+type metricsServer struct {
+	manager   *Manager
+	config    *MetricsConfig
+	startTime time.Time
+	server    *http.Server
+}
+
+func newMetricsServer(manager *Manager, config *MetricsConfig) *metricsServer {
+	s := &metricsServer{
+		manager:   manager,
+		config:    config,
+		startTime: time.Now(),
+	}
+	path := config.Path
+	if path == "" {
+		path = "/metrics"
+	}
+	mux := http.NewServeMux()
+	mux.Handle(path, s)
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+func (s *metricsServer) start() error {
+	listener, err := net.Listen("tcp", s.config.Listen)
+	if err != nil {
+		return newError("failed to listen metrics on ", s.config.Listen).Base(err)
+	}
+	newError("metrics listening on ", listener.Addr()).AtInfo().WriteToLog()
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			newError("metrics server stopped").Base(err).AtWarning().WriteToLog()
+		}
+	}()
+	return nil
+}
+
+func (s *metricsServer) close() error {
+	return s.server.Close()
+}
+
+func (s *metricsServer) authorized(r *http.Request) bool {
+	if s.config.Username == "" {
+		return true
+	}
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(s.config.Username)) == 1
+	passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(s.config.Password)) == 1
+	return usernameMatch && passwordMatch
+}
+
+// ServeHTTP implements http.Handler.
+func (s *metricsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+	w.Header().Set("Content-Type", metricsContentType)
+	w.Write(s.collect().bytes()) // nolint: errcheck
+}
+
+func (s *metricsServer) collect() *metricSet {
+	set := newMetricSet()
+	s.manager.Visit(func(name string, c stats.Counter) bool {
+		set.add(name, "counter", c.Value())
+		return true
+	})
+	s.manager.VisitGauges(func(name string, g stats.Gauge) bool {
+		set.add(name, "gauge", g.Value())
+		return true
+	})
+	s.manager.VisitHistograms(func(name string, h stats.Histogram) bool {
+		set.addHistogram(name, h.Snapshot())
+		return true
+	})
+
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+	set.addRaw("v2ray_uptime_seconds", "gauge", time.Since(s.startTime).Seconds())
+	set.addRaw("go_goroutines", "gauge", float64(runtime.NumGoroutine()))
+	set.addRaw("go_memstats_alloc_bytes", "gauge", float64(rtm.Alloc))
+	set.addRaw("go_memstats_alloc_bytes_total", "counter", float64(rtm.TotalAlloc))
+	set.addRaw("go_memstats_sys_bytes", "gauge", float64(rtm.Sys))
+	set.addRaw("go_memstats_mallocs_total", "counter", float64(rtm.Mallocs))
+	set.addRaw("go_memstats_frees_total", "counter", float64(rtm.Frees))
+	set.addRaw("go_memstats_live_objects", "gauge", float64(rtm.Mallocs-rtm.Frees))
+	set.addRaw("go_gc_cycles_total", "counter", float64(rtm.NumGC))
+	set.addRaw("go_gc_pause_seconds_total", "counter", float64(rtm.PauseTotalNs)/float64(time.Second))
+	return set
+}
+
+type metricLabel struct {
+	name  string
+	value string
+}
+
+type metricSample struct {
+	suffix string
+	labels []metricLabel
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	typ     string
+	samples []metricSample
+}
+
+// metricSet is a set of metric families, keyed by family name.
+type metricSet struct {
+	families map[string]*metricFamily
+}
+
+func newMetricSet() *metricSet {
+	return &metricSet{
+		families: make(map[string]*metricFamily),
+	}
+}
+
+func (s *metricSet) family(name string, typ string) *metricFamily {
+	f, found := s.families[name]
+	if !found {
+		f = &metricFamily{name: name, typ: typ}
+		s.families[name] = f
+	}
+	if f.typ != typ {
+		return nil
+	}
+	return f
+}
+
+func (s *metricSet) addRaw(name string, typ string, value float64) {
+	if f := s.family(name, typ); f != nil {
+		f.samples = append(f.samples, metricSample{value: value})
+	}
+}
+
+func (s *metricSet) add(statName string, typ string, value int64) {
+	name, labels := metricName(statName, typ)
+	if f := s.family(name, typ); f != nil {
+		f.samples = append(f.samples, metricSample{labels: labels, value: float64(value)})
+	}
+}
+
+func (s *metricSet) addHistogram(statName string, snapshot stats.HistogramSnapshot) {
+	name, labels := metricName(statName, "histogram")
+	f := s.family(name, "histogram")
+	if f == nil {
+		return
+	}
+	var cumulative uint64
+	for i, count := range snapshot.Counts {
+		cumulative += count
+		le := "+Inf"
+		if i < len(snapshot.Bounds) {
+			le = formatMetricValue(snapshot.Bounds[i])
+		}
+		f.samples = append(f.samples, metricSample{
+			suffix: "_bucket",
+			labels: append(append([]metricLabel(nil), labels...), metricLabel{"le", le}),
+			value:  float64(cumulative),
+		})
+	}
+	f.samples = append(f.samples,
+		metricSample{suffix: "_sum", labels: labels, value: snapshot.Sum},
+		metricSample{suffix: "_count", labels: labels, value: float64(snapshot.Count)})
+}
+
+// bytes returns the metrics in Prometheus text format, sorted by family name.
+func (s *metricSet) bytes() []byte {
+	names := make([]string, 0, len(s.families))
+	for name := range s.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		f := s.families[name]
+		b.WriteString("# TYPE " + f.name + " " + f.typ + "\n")
+		lines := make([]string, 0, len(f.samples))
+		for _, sample := range f.samples {
+			lines = append(lines, f.name+sample.suffix+formatLabels(sample.labels)+" "+formatMetricValue(sample.value)+"\n")
+		}
+		if f.typ != "histogram" {
+			// Order of histogram samples matters, which are grouped by stats.
+			sort.Strings(lines)
+		}
+		for _, line := range lines {
+			b.WriteString(line)
+		}
+	}
+	return b.Bytes()
+}
+
+// metricName maps a stat name, such as "user>>>love@v2ray.com>>>traffic>>>uplink", to a metric name with labels,
+// such as v2ray_traffic_bytes_total{user="love@v2ray.com",direction="uplink"}.
+func metricName(statName string, typ string) (string, []metricLabel) {
+	parts := strings.Split(statName, ">>>")
+	var labels []metricLabel
+	if len(parts) > 2 {
+		switch parts[0] {
+		case "user", "inbound", "outbound":
+			labels = append(labels, metricLabel{parts[0], parts[1]})
+			parts = parts[2:]
+		}
+	}
+
+	switch {
+	case len(parts) == 2 && parts[0] == "traffic":
+		return "v2ray_traffic_bytes_total", append(labels, metricLabel{"direction", parts[1]})
+	case len(parts) == 2 && parts[0] == "throttle":
+		return "v2ray_throttle_milliseconds_total", append(labels, metricLabel{"direction", parts[1]})
+	case len(parts) == 3 && parts[0] == "dial" && parts[1] == "failure":
+		return "v2ray_dial_failures_total", append(labels, metricLabel{"reason", parts[2]})
+	case len(parts) == 2 && parts[0] == "connection" && parts[1] == "active":
+		return "v2ray_connections_active", labels
+	case len(parts) == 2 && parts[0] == "connection" && parts[1] == "duration":
+		return "v2ray_connection_duration_seconds", labels
+	case len(parts) == 2 && parts[0] == "dial" && parts[1] == "latency":
+		return "v2ray_dial_latency_seconds", labels
+	}
+
+	name := "v2ray_" + sanitizeMetricName(strings.Join(parts, "_"))
+	if typ == "counter" && !strings.HasSuffix(name, "_total") {
+		name += "_total"
+	}
+	return name, labels
+}
+
+func sanitizeMetricName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(labels []metricLabel) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, l.name+`="`+labelValueReplacer.Replace(l.value)+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatMetricValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
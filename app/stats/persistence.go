@@ -0,0 +1,251 @@
+// +build !confonly
+
+package stats
+
+import (
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/common/task"
+)
+
+const dateFormat = "2006-01-02"
+
+// persistence keeps counters of a Manager in a snapshot file, and rolls them up into daily buckets.
+type persistence struct {
+	sync.Mutex
+	manager *Manager
+	config  *PersistenceConfig
+	// restored are values of counters in the snapshot file that are not registered yet.
+	restored map[string]int64
+	// last are increments of counters when they were last rolled up into daily buckets, at lastRollUp.
+	last       map[string]int64
+	lastRollUp time.Time
+	daily      []*DailyBucket
+	task       *task.Periodic
+	// saving serializes writing of the snapshot file.
+	saving sync.Mutex
+}
+
+func newPersistence(manager *Manager, config *PersistenceConfig) *persistence {
+	p := &persistence{
+		manager:  manager,
+		config:   config,
+		restored: make(map[string]int64),
+		last:     make(map[string]int64),
+	}
+	interval := time.Duration(config.Interval) * time.Second
+	if interval == 0 {
+		interval = time.Minute
+	}
+	p.task = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := p.save(); err != nil {
+				newError("failed to save stats").Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+	return p
+}
+
+// load reads counters from the snapshot file. It does nothing if the file doesn't exist. A corrupt snapshot file is
+// moved aside, so that it is not overwritten by later snapshots.
+func (p *persistence) load() error {
+	data, err := ioutil.ReadFile(p.config.Path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return newError("failed to read stats from ", p.config.Path).Base(err)
+	}
+
+	snapshot := new(Snapshot)
+	if err := proto.Unmarshal(data, snapshot); err != nil {
+		aside := p.config.Path + ".corrupt." + time.Now().Format("20060102T150405")
+		if renameErr := os.Rename(p.config.Path, aside); renameErr != nil {
+			return newError("failed to move corrupt stats file ", p.config.Path).Base(renameErr)
+		}
+		newError("failed to decode stats from ", p.config.Path, ", moved to ", aside).Base(err).AtWarning().WriteToLog()
+		return nil
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	for name, value := range snapshot.Counter {
+		p.restored[name] = value
+	}
+	p.daily = snapshot.Daily
+	if snapshot.RollUpTime > 0 {
+		p.lastRollUp = time.Unix(snapshot.RollUpTime, 0)
+	}
+	return nil
+}
+
+// restore returns the value of the counter in the snapshot file, when the counter is registered.
+func (p *persistence) restore(name string) int64 {
+	p.Lock()
+	defer p.Unlock()
+
+	value, found := p.restored[name]
+	if !found {
+		return 0
+	}
+	delete(p.restored, name)
+	return value
+}
+
+// daySpan is the part of a roll-up period in one day.
+type daySpan struct {
+	date     string
+	duration time.Duration
+}
+
+// daySpans splits [from, to) at local midnights, ignoring days before oldest.
+func daySpans(from time.Time, to time.Time, oldest string) []daySpan {
+	var spans []daySpan
+	for from.Before(to) {
+		y, m, d := from.Date()
+		end := time.Date(y, m, d+1, 0, 0, 0, 0, from.Location())
+		if end.After(to) {
+			end = to
+		}
+		if date := from.Format(dateFormat); date >= oldest {
+			spans = append(spans, daySpan{date: date, duration: end.Sub(from)})
+		}
+		from = end
+	}
+	return spans
+}
+
+// bucket returns the daily bucket of the date, creating it if necessary. Buckets are kept in order of date.
+func (p *persistence) bucket(date string) *DailyBucket {
+	i := len(p.daily)
+	for i > 0 && p.daily[i-1].Date >= date {
+		if p.daily[i-1].Date == date {
+			return p.daily[i-1]
+		}
+		i--
+	}
+	b := &DailyBucket{Date: date}
+	p.daily = append(p.daily, nil)
+	copy(p.daily[i+1:], p.daily[i:])
+	p.daily[i] = b
+	return b
+}
+
+// rollUp adds increments of counters since last roll-up into daily buckets. Increments over more than one day are
+// split among the days in proportion to time, as there is no record of when the traffic happened.
+func (p *persistence) rollUp(increments map[string]int64, now time.Time) {
+	if p.config.DailyBuckets == 0 {
+		return
+	}
+
+	oldest := now.AddDate(0, 0, 1-int(p.config.DailyBuckets)).Format(dateFormat)
+	spans := []daySpan{{date: now.Format(dateFormat)}}
+	if !p.lastRollUp.IsZero() && p.lastRollUp.Before(now) && p.lastRollUp.Format(dateFormat) != spans[0].date {
+		spans = daySpans(p.lastRollUp, now, oldest)
+	}
+	total := now.Sub(p.lastRollUp)
+	p.lastRollUp = now
+
+	for name, added := range increments {
+		delta := added - p.last[name]
+		p.last[name] = added
+		if delta <= 0 {
+			continue
+		}
+		for i, span := range spans {
+			share := delta
+			if i < len(spans)-1 {
+				share = int64(float64(delta) * float64(span.duration) / float64(total))
+			}
+			delta -= share
+			if share == 0 {
+				continue
+			}
+			bucket := p.bucket(span.date)
+			if bucket.Counter == nil {
+				bucket.Counter = make(map[string]int64)
+			}
+			bucket.Counter[name] += share
+		}
+	}
+
+	// The bucket of today exists even if there is no traffic.
+	p.bucket(now.Format(dateFormat))
+	for len(p.daily) > 0 && p.daily[0].Date < oldest {
+		p.daily = p.daily[1:]
+	}
+}
+
+// counterValues returns values and increments of registered counters. Values are reset by queries of stats, but
+// increments are not.
+func (p *persistence) counterValues() (values map[string]int64, increments map[string]int64) {
+	m := p.manager
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	values = make(map[string]int64, len(m.counters))
+	increments = make(map[string]int64, len(m.counters))
+	for name, c := range m.counters {
+		values[name] = c.Value()
+		increments[name] = c.increments()
+	}
+	return values, increments
+}
+
+// save writes counters into the snapshot file.
+func (p *persistence) save() error {
+	p.saving.Lock()
+	defer p.saving.Unlock()
+
+	values, increments := p.counterValues()
+
+	p.Lock()
+	p.rollUp(increments, time.Now())
+	snapshot := &Snapshot{
+		Counter:    values,
+		Daily:      p.daily,
+		RollUpTime: p.lastRollUp.Unix(),
+	}
+	for name, value := range p.restored {
+		snapshot.Counter[name] = value
+	}
+	data, err := proto.Marshal(snapshot)
+	p.Unlock()
+	if err != nil {
+		return newError("failed to encode stats").Base(err)
+	}
+
+	// Write to a temporary file first, so that the snapshot file is never left half-written.
+	tmp := p.config.Path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return newError("failed to write stats to ", tmp).Base(err)
+	}
+	if err := os.Rename(tmp, p.config.Path); err != nil {
+		return newError("failed to write stats to ", p.config.Path).Base(err)
+	}
+	return nil
+}
+
+// dailyBuckets returns a copy of daily buckets, including the increments since last snapshot.
+func (p *persistence) dailyBuckets() []*DailyBucket {
+	_, increments := p.counterValues()
+
+	p.Lock()
+	defer p.Unlock()
+
+	p.rollUp(increments, time.Now())
+	buckets := make([]*DailyBucket, 0, len(p.daily))
+	for _, b := range p.daily {
+		buckets = append(buckets, proto.Clone(b).(*DailyBucket))
+	}
+	return buckets
+}
@@ -0,0 +1,161 @@
+package stats
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Snapshot is the content of the snapshot file of counters.
+type Snapshot struct {
+	// Values of counters, keyed by name.
+	Counter map[string]int64 `protobuf:"bytes,1,rep,name=counter,proto3" json:"counter,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// Daily buckets, from the oldest to the newest.
+	Daily []*DailyBucket `protobuf:"bytes,2,rep,name=daily,proto3" json:"daily,omitempty"`
+	// Unix time in seconds when counters are last rolled up into daily buckets.
+	RollUpTime           int64    `protobuf:"varint,3,opt,name=roll_up_time,json=rollUpTime,proto3" json:"roll_up_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Snapshot) Reset()         { *m = Snapshot{} }
+func (m *Snapshot) String() string { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()    {}
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f20865dd977aa349, []int{0}
+}
+
+func (m *Snapshot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Snapshot.Unmarshal(m, b)
+}
+func (m *Snapshot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Snapshot.Marshal(b, m, deterministic)
+}
+func (m *Snapshot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Snapshot.Merge(m, src)
+}
+func (m *Snapshot) XXX_Size() int {
+	return xxx_messageInfo_Snapshot.Size(m)
+}
+func (m *Snapshot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Snapshot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Snapshot proto.InternalMessageInfo
+
+func (m *Snapshot) GetCounter() map[string]int64 {
+	if m != nil {
+		return m.Counter
+	}
+	return nil
+}
+
+func (m *Snapshot) GetDaily() []*DailyBucket {
+	if m != nil {
+		return m.Daily
+	}
+	return nil
+}
+
+func (m *Snapshot) GetRollUpTime() int64 {
+	if m != nil {
+		return m.RollUpTime
+	}
+	return 0
+}
+
+// DailyBucket is the traffic of counters in one day.
+type DailyBucket struct {
+	// Date in local time, in the format of "2006-01-02".
+	Date string `protobuf:"bytes,1,opt,name=date,proto3" json:"date,omitempty"`
+	// Increments of counters in the day, keyed by name.
+	Counter              map[string]int64 `protobuf:"bytes,2,rep,name=counter,proto3" json:"counter,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *DailyBucket) Reset()         { *m = DailyBucket{} }
+func (m *DailyBucket) String() string { return proto.CompactTextString(m) }
+func (*DailyBucket) ProtoMessage()    {}
+func (*DailyBucket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f20865dd977aa349, []int{1}
+}
+
+func (m *DailyBucket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DailyBucket.Unmarshal(m, b)
+}
+func (m *DailyBucket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DailyBucket.Marshal(b, m, deterministic)
+}
+func (m *DailyBucket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DailyBucket.Merge(m, src)
+}
+func (m *DailyBucket) XXX_Size() int {
+	return xxx_messageInfo_DailyBucket.Size(m)
+}
+func (m *DailyBucket) XXX_DiscardUnknown() {
+	xxx_messageInfo_DailyBucket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DailyBucket proto.InternalMessageInfo
+
+func (m *DailyBucket) GetDate() string {
+	if m != nil {
+		return m.Date
+	}
+	return ""
+}
+
+func (m *DailyBucket) GetCounter() map[string]int64 {
+	if m != nil {
+		return m.Counter
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Snapshot)(nil), "v2ray.core.app.stats.Snapshot")
+	proto.RegisterMapType((map[string]int64)(nil), "v2ray.core.app.stats.Snapshot.CounterEntry")
+	proto.RegisterType((*DailyBucket)(nil), "v2ray.core.app.stats.DailyBucket")
+	proto.RegisterMapType((map[string]int64)(nil), "v2ray.core.app.stats.DailyBucket.CounterEntry")
+}
+
+func init() {
+	proto.RegisterFile("v2ray.com/core/app/stats/snapshot.proto", fileDescriptor_f20865dd977aa349)
+}
+
+var fileDescriptor_f20865dd977aa349 = []byte{
+	// 290 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x91, 0x4f, 0x4b, 0xc3, 0x40,
+	0x10, 0xc5, 0xd9, 0xc4, 0xf8, 0x67, 0xda, 0x83, 0x2c, 0x39, 0x2c, 0x9e, 0x62, 0x2f, 0x06, 0x84,
+	0x0d, 0xc4, 0x83, 0x92, 0x9b, 0xad, 0x05, 0x8f, 0x92, 0xaa, 0x07, 0x2f, 0x65, 0x4d, 0x17, 0x0c,
+	0x4d, 0xb2, 0xc3, 0x66, 0x53, 0xc8, 0x27, 0x12, 0xfc, 0x64, 0x7e, 0x0c, 0xc9, 0xa6, 0xc1, 0x1e,
+	0x02, 0x1e, 0xbc, 0xbd, 0x4c, 0x7e, 0x6f, 0xde, 0x63, 0x16, 0xae, 0x76, 0xb1, 0x16, 0x2d, 0xcf,
+	0x54, 0x19, 0x65, 0x4a, 0xcb, 0x48, 0x20, 0x46, 0xb5, 0x11, 0xa6, 0x8e, 0xea, 0x4a, 0x60, 0xfd,
+	0xa1, 0x0c, 0x47, 0xad, 0x8c, 0xa2, 0xfe, 0x00, 0x6a, 0xc9, 0x05, 0x22, 0xb7, 0xd0, 0xec, 0x9b,
+	0xc0, 0xe9, 0x6a, 0x0f, 0xd2, 0x25, 0x9c, 0x64, 0xaa, 0xa9, 0x8c, 0xd4, 0x8c, 0x04, 0x6e, 0x38,
+	0x89, 0xaf, 0xf9, 0x98, 0x89, 0x0f, 0x06, 0xbe, 0xe8, 0xe9, 0x65, 0x65, 0x74, 0x9b, 0x0e, 0x5e,
+	0x7a, 0x0b, 0xde, 0x46, 0xe4, 0x45, 0xcb, 0x1c, 0xbb, 0xe4, 0x72, 0x7c, 0xc9, 0x43, 0x87, 0xcc,
+	0x9b, 0x6c, 0x2b, 0x4d, 0xda, 0xf3, 0x34, 0x80, 0xa9, 0x56, 0x45, 0xb1, 0x6e, 0x70, 0x6d, 0xf2,
+	0x52, 0x32, 0x37, 0x20, 0xa1, 0x9b, 0x42, 0x37, 0x7b, 0xc1, 0xe7, 0xbc, 0x94, 0x17, 0x09, 0x4c,
+	0x0f, 0x33, 0xe9, 0x39, 0xb8, 0x5b, 0xd9, 0x32, 0x12, 0x90, 0xf0, 0x2c, 0xed, 0x24, 0xf5, 0xc1,
+	0xdb, 0x89, 0xa2, 0x91, 0xcc, 0xb1, 0xe6, 0xfe, 0x23, 0x71, 0xee, 0xc8, 0xec, 0x93, 0xc0, 0xe4,
+	0x20, 0x94, 0x52, 0x38, 0xda, 0x08, 0x23, 0xf7, 0x66, 0xab, 0xe9, 0xe3, 0xef, 0x05, 0xfa, 0xf2,
+	0xfc, 0xcf, 0xf2, 0xe3, 0x47, 0xf8, 0x4f, 0xd3, 0x79, 0x02, 0x2c, 0x53, 0xe5, 0x68, 0xf2, 0x13,
+	0x79, 0xf3, 0xac, 0xf8, 0x72, 0xfc, 0xd7, 0x38, 0x15, 0x2d, 0x5f, 0x74, 0xff, 0xef, 0x11, 0xf9,
+	0xaa, 0x1b, 0xbf, 0x1f, 0xdb, 0xd7, 0xbe, 0xf9, 0x19, 0x00, 0x93, 0xe9, 0x09, 0xa7, 0x18, 0x02,
+	0x00, 0x00,
+}
@@ -6,15 +6,20 @@ package stats
 
 import (
 	"context"
+	"sort"
 	"sync"
 	"sync/atomic"
 
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/features/stats"
 )
 
 // Counter is an implementation of stats.Counter.
 type Counter struct {
 	value int64
+	// added is the sum of increments since the counter is registered. It is not changed by Set, so that increments
+	// before a reset are still rolled up into daily buckets.
+	added int64
 }
 
 // Value implements stats.Counter.
@@ -29,18 +34,96 @@ func (c *Counter) Set(newValue int64) int64 {
 
 // Add implements stats.Counter.
 func (c *Counter) Add(delta int64) int64 {
+	atomic.AddInt64(&c.added, delta)
 	return atomic.AddInt64(&c.value, delta)
 }
 
+// increments returns the sum of increments since the counter is registered.
+func (c *Counter) increments() int64 {
+	return atomic.LoadInt64(&c.added)
+}
+
+// Gauge is an implementation of stats.Gauge.
+type Gauge struct {
+	value int64
+}
+
+// Value implements stats.Gauge.
+func (g *Gauge) Value() int64 {
+	return atomic.LoadInt64(&g.value)
+}
+
+// Add implements stats.Gauge.
+func (g *Gauge) Add(delta int64) int64 {
+	return atomic.AddInt64(&g.value, delta)
+}
+
+// Histogram is an implementation of stats.Histogram.
+type Histogram struct {
+	sync.Mutex
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram creates a Histogram with the bucket bounds, which must be in increasing order.
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe implements stats.Histogram.
+func (h *Histogram) Observe(value float64) {
+	idx := sort.SearchFloat64s(h.bounds, value)
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.counts[idx]++
+	h.count++
+	h.sum += value
+}
+
+// Snapshot implements stats.Histogram.
+func (h *Histogram) Snapshot() stats.HistogramSnapshot {
+	h.Lock()
+	defer h.Unlock()
+
+	return stats.HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: append([]uint64(nil), h.counts...),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
+
 // Manager is an implementation of stats.Manager.
 type Manager struct {
-	access   sync.RWMutex
-	counters map[string]*Counter
+	access      sync.RWMutex
+	counters    map[string]*Counter
+	gauges      map[string]*Gauge
+	histograms  map[string]*Histogram
+	metrics     *metricsServer
+	persistence *persistence
 }
 
 func NewManager(ctx context.Context, config *Config) (*Manager, error) {
 	m := &Manager{
-		counters: make(map[string]*Counter),
+		counters:   make(map[string]*Counter),
+		gauges:     make(map[string]*Gauge),
+		histograms: make(map[string]*Histogram),
+	}
+	if config.Metrics != nil {
+		m.metrics = newMetricsServer(m, config.Metrics)
+	}
+	if config.Persistence != nil {
+		m.persistence = newPersistence(m, config.Persistence)
+		if err := m.persistence.load(); err != nil {
+			return nil, newError("failed to load stats").Base(err)
+		}
 	}
 
 	return m, nil
@@ -54,15 +137,120 @@ func (m *Manager) RegisterCounter(name string) (stats.Counter, error) {
 	m.access.Lock()
 	defer m.access.Unlock()
 
-	if _, found := m.counters[name]; found {
+	if m.registered(name) {
 		return nil, newError("Counter ", name, " already registered.")
 	}
 	newError("create new counter ", name).AtDebug().WriteToLog()
 	c := new(Counter)
+	if m.persistence != nil {
+		c.value = m.persistence.restore(name)
+	}
 	m.counters[name] = c
 	return c, nil
 }
 
+// registered returns whether a stat of any kind is registered with the name.
+func (m *Manager) registered(name string) bool {
+	_, isCounter := m.counters[name]
+	_, isGauge := m.gauges[name]
+	_, isHistogram := m.histograms[name]
+	return isCounter || isGauge || isHistogram
+}
+
+// RegisterGauge implements stats.GaugeManager.
+func (m *Manager) RegisterGauge(name string) (stats.Gauge, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if m.registered(name) {
+		return nil, newError("Gauge ", name, " already registered.")
+	}
+	return m.registerGauge(name), nil
+}
+
+// GetOrRegisterGauge implements stats.GaugeManager.
+func (m *Manager) GetOrRegisterGauge(name string) (stats.Gauge, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if g, found := m.gauges[name]; found {
+		return g, nil
+	}
+	if m.registered(name) {
+		return nil, newError("Stat ", name, " is registered but not a gauge.")
+	}
+	return m.registerGauge(name), nil
+}
+
+func (m *Manager) registerGauge(name string) *Gauge {
+	newError("create new gauge ", name).AtDebug().WriteToLog()
+	g := new(Gauge)
+	m.gauges[name] = g
+	return g
+}
+
+// GetGauge implements stats.GaugeManager.
+func (m *Manager) GetGauge(name string) stats.Gauge {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	if g, found := m.gauges[name]; found {
+		return g
+	}
+	return nil
+}
+
+// RegisterHistogram implements stats.HistogramManager.
+func (m *Manager) RegisterHistogram(name string, bounds []float64) (stats.Histogram, error) {
+	if !sort.Float64sAreSorted(bounds) {
+		return nil, newError("bounds of histogram ", name, " are not sorted")
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if m.registered(name) {
+		return nil, newError("Histogram ", name, " already registered.")
+	}
+	return m.registerHistogram(name, bounds), nil
+}
+
+// GetOrRegisterHistogram implements stats.HistogramManager.
+func (m *Manager) GetOrRegisterHistogram(name string, bounds []float64) (stats.Histogram, error) {
+	if !sort.Float64sAreSorted(bounds) {
+		return nil, newError("bounds of histogram ", name, " are not sorted")
+	}
+
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if h, found := m.histograms[name]; found {
+		return h, nil
+	}
+	if m.registered(name) {
+		return nil, newError("Stat ", name, " is registered but not a histogram.")
+	}
+	return m.registerHistogram(name, bounds), nil
+}
+
+func (m *Manager) registerHistogram(name string, bounds []float64) *Histogram {
+	newError("create new histogram ", name).AtDebug().WriteToLog()
+	h := NewHistogram(bounds)
+	m.histograms[name] = h
+	return h
+}
+
+// GetHistogram implements stats.HistogramManager.
+func (m *Manager) GetHistogram(name string) stats.Histogram {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	if h, found := m.histograms[name]; found {
+		return h
+	}
+	return nil
+}
+
 func (m *Manager) GetCounter(name string) stats.Counter {
 	m.access.RLock()
 	defer m.access.RUnlock()
@@ -84,12 +272,70 @@ func (m *Manager) Visit(visitor func(string, stats.Counter) bool) {
 	}
 }
 
+// VisitGauges calls visitor function on all gauges, until it returns false.
+func (m *Manager) VisitGauges(visitor func(string, stats.Gauge) bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	for name, g := range m.gauges {
+		if !visitor(name, g) {
+			break
+		}
+	}
+}
+
+// VisitHistograms calls visitor function on all histograms, until it returns false.
+func (m *Manager) VisitHistograms(visitor func(string, stats.Histogram) bool) {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
+	for name, h := range m.histograms {
+		if !visitor(name, h) {
+			break
+		}
+	}
+}
+
 // Start implements common.Runnable.
 func (m *Manager) Start() error {
+	if m.persistence != nil {
+		if err := m.persistence.task.Start(); err != nil {
+			return err
+		}
+	}
+	if m.metrics != nil {
+		return m.metrics.start()
+	}
 	return nil
 }
 
 // Close implement common.Closable.
 func (m *Manager) Close() error {
+	var errs []error
+	if m.persistence != nil {
+		if err := m.persistence.task.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		if err := m.persistence.save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if m.metrics != nil {
+		if err := m.metrics.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Combine(errs...)
+	}
 	return nil
 }
+
+// DailyStats returns increments of counters in each day, from the oldest to the newest. It returns nil if daily
+// buckets are not enabled.
+func (m *Manager) DailyStats() []*DailyBucket {
+	if m.persistence == nil || m.persistence.config.DailyBuckets == 0 {
+		return nil
+	}
+	return m.persistence.dailyBuckets()
+}
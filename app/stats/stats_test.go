@@ -2,11 +2,22 @@ package stats_test
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
 
 	. "v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 	"v2ray.com/core/features/stats"
+	"v2ray.com/core/testing/servers/tcp"
 )
 
 func TestInternface(t *testing.T) {
@@ -33,3 +44,284 @@ func TestStatsCounter(t *testing.T) {
 		t.Fatal("unexpected Value() return: ", v, ", wanted ", 0)
 	}
 }
+
+func TestStatsGauge(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	g, err := m.RegisterGauge("test.gauge")
+	common.Must(err)
+	g.Add(2)
+	if v := g.Add(-1); v != 1 {
+		t.Error("unexpected Add(-1) return: ", v, ", wanted ", 1)
+	}
+	if m.GetGauge("test.gauge") != g {
+		t.Error("failed to get registered gauge")
+	}
+
+	if _, err := m.RegisterCounter("test.gauge"); err == nil {
+		t.Error("expect error on registering counter with the name of a gauge, but nil")
+	}
+
+	var wg sync.WaitGroup
+	gauges := make([]stats.Gauge, 8)
+	for i := range gauges {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gauges[i], _ = stats.GetOrRegisterGauge(m, "test.concurrent")
+		}()
+	}
+	wg.Wait()
+	for _, g := range gauges {
+		if g == nil || g != gauges[0] {
+			t.Fatal("expect the same gauge from concurrent registrations, but got ", gauges)
+		}
+	}
+}
+
+func TestStatsHistogram(t *testing.T) {
+	m, err := NewManager(context.Background(), &Config{})
+	common.Must(err)
+
+	if _, err := m.RegisterHistogram("test.unsorted", []float64{2, 1}); err == nil {
+		t.Error("expect error on unsorted bounds, but nil")
+	}
+
+	h, err := stats.GetOrRegisterHistogram(m, "test.histogram", []float64{1, 5})
+	common.Must(err)
+	for _, v := range []float64{0.5, 1, 3, 10} {
+		h.Observe(v)
+	}
+
+	if r := cmp.Diff(h.Snapshot(), stats.HistogramSnapshot{
+		Bounds: []float64{1, 5},
+		Counts: []uint64{2, 1, 1},
+		Count:  4,
+		Sum:    14.5,
+	}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	port := tcp.PickPort()
+	m, err := NewManager(context.Background(), &Config{
+		Metrics: &MetricsConfig{
+			Listen:   "127.0.0.1:" + port.String(),
+			Username: "user",
+			Password: "pass",
+		},
+	})
+	common.Must(err)
+	common.Must(m.Start())
+	defer m.Close()
+
+	c, err := m.RegisterCounter("user>>>love@v2ray.com>>>traffic>>>uplink")
+	common.Must(err)
+	c.Add(10)
+	g, err := m.RegisterGauge("inbound>>>in>>>connection>>>active")
+	common.Must(err)
+	g.Add(2)
+	h, err := m.RegisterHistogram("outbound>>>out>>>connection>>>duration", []float64{1, 5})
+	common.Must(err)
+	h.Observe(0.5)
+	h.Observe(3)
+	h, err = m.RegisterHistogram("outbound>>>out>>>dial>>>latency", []float64{0.1})
+	common.Must(err)
+	h.Observe(0.05)
+	_, err = m.RegisterCounter("dns>>>cache>>>hit")
+	common.Must(err)
+
+	url := "http://127.0.0.1:" + port.String() + "/metrics"
+	resp, err := http.Get(url)
+	common.Must(err)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatal("expect unauthorized, but got ", resp.Status)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	common.Must(err)
+	req.SetBasicAuth("user", "pass")
+	resp, err = http.DefaultClient.Do(req)
+	common.Must(err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	common.Must(err)
+
+	for _, line := range []string{
+		"# TYPE v2ray_traffic_bytes_total counter\n",
+		`v2ray_traffic_bytes_total{user="love@v2ray.com",direction="uplink"} 10` + "\n",
+		`v2ray_connections_active{inbound="in"} 2` + "\n",
+		`v2ray_connection_duration_seconds_bucket{outbound="out",le="1"} 1` + "\n",
+		`v2ray_connection_duration_seconds_bucket{outbound="out",le="+Inf"} 2` + "\n",
+		`v2ray_connection_duration_seconds_sum{outbound="out"} 3.5` + "\n",
+		`v2ray_connection_duration_seconds_count{outbound="out"} 2` + "\n",
+		`v2ray_dial_latency_seconds_bucket{outbound="out",le="0.1"} 1` + "\n",
+		"v2ray_dns_cache_hit_total 0\n",
+		"# TYPE go_goroutines gauge\n",
+	} {
+		if !strings.Contains(string(body), line) {
+			t.Error("expect ", line, " in metrics:\n", string(body))
+		}
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	config := &Config{
+		Persistence: &PersistenceConfig{
+			Path:         filepath.Join(dir, "stats.dat"),
+			DailyBuckets: 7,
+		},
+	}
+
+	m, err := NewManager(context.Background(), config)
+	common.Must(err)
+	common.Must(m.Start())
+	c1, err := m.RegisterCounter("user>>>a>>>traffic>>>uplink")
+	common.Must(err)
+	c1.Add(10)
+	c2, err := m.RegisterCounter("user>>>b>>>traffic>>>uplink")
+	common.Must(err)
+	c2.Add(20)
+	common.Must(m.Close())
+
+	m, err = NewManager(context.Background(), config)
+	common.Must(err)
+	common.Must(m.Start())
+	c1, err = m.RegisterCounter("user>>>a>>>traffic>>>uplink")
+	common.Must(err)
+	if v := c1.Value(); v != 10 {
+		t.Error("expect restored value 10, but got ", v)
+	}
+	c1.Add(5)
+	common.Must(m.Close())
+
+	m, err = NewManager(context.Background(), config)
+	common.Must(err)
+	c2, err = m.RegisterCounter("user>>>b>>>traffic>>>uplink")
+	common.Must(err)
+	if v := c2.Value(); v != 20 {
+		t.Error("expect value of unregistered counter 20 kept, but got ", v)
+	}
+	c2.Set(0)
+	c2.Add(3)
+
+	daily := m.DailyStats()
+	if len(daily) != 1 {
+		t.Fatal("expect 1 daily bucket, but got ", len(daily))
+	}
+	if r := cmp.Diff(daily[0].Counter, map[string]int64{
+		"user>>>a>>>traffic>>>uplink": 15,
+		"user>>>b>>>traffic>>>uplink": 23,
+	}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestPersistenceResetBetweenRollUps(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	m, err := NewManager(context.Background(), &Config{
+		Persistence: &PersistenceConfig{
+			Path:         filepath.Join(dir, "stats.dat"),
+			DailyBuckets: 7,
+		},
+	})
+	common.Must(err)
+	c, err := m.RegisterCounter("user>>>a>>>traffic>>>uplink")
+	common.Must(err)
+	c.Add(100)
+	m.DailyStats()
+
+	// Queries of stats with reset set counters to 0 between roll-ups.
+	c.Add(50)
+	c.Set(0)
+	c.Add(120)
+	c.Set(0)
+	c.Add(30)
+
+	daily := m.DailyStats()
+	if len(daily) != 1 {
+		t.Fatal("expect 1 daily bucket, but got ", len(daily))
+	}
+	if v := daily[0].Counter["user>>>a>>>traffic>>>uplink"]; v != 300 {
+		t.Error("expect 300 in daily bucket, but got ", v)
+	}
+}
+
+func TestPersistenceRollUpAcrossDays(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	// Counters are last rolled up one hour before today.
+	now := time.Now()
+	y, mon, d := now.Date()
+	midnight := time.Date(y, mon, d, 0, 0, 0, 0, now.Location())
+	path := filepath.Join(dir, "stats.dat")
+	data, err := proto.Marshal(&Snapshot{
+		Counter:    map[string]int64{"user>>>a>>>traffic>>>uplink": 100},
+		RollUpTime: midnight.Add(-time.Hour).Unix(),
+	})
+	common.Must(err)
+	common.Must(ioutil.WriteFile(path, data, 0600))
+
+	m, err := NewManager(context.Background(), &Config{
+		Persistence: &PersistenceConfig{
+			Path:         path,
+			DailyBuckets: 7,
+		},
+	})
+	common.Must(err)
+	c, err := m.RegisterCounter("user>>>a>>>traffic>>>uplink")
+	common.Must(err)
+	c.Add(1000000)
+
+	daily := m.DailyStats()
+	if len(daily) != 2 {
+		t.Fatal("expect 2 daily buckets, but got ", daily)
+	}
+	yesterday := daily[0].Counter["user>>>a>>>traffic>>>uplink"]
+	today := daily[1].Counter["user>>>a>>>traffic>>>uplink"]
+	if yesterday+today != 1000000 {
+		t.Error("expect increments split into 2 days, but got ", yesterday, " and ", today)
+	}
+	if expected := int64(1000000 * float64(time.Hour) / float64(time.Since(midnight)+time.Hour)); yesterday < expected*9/10 || yesterday > expected*11/10 {
+		t.Error("expect about ", expected, " in yesterday, but got ", yesterday)
+	}
+}
+
+func TestPersistenceCorruptSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "v2ray-stats")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "stats.dat")
+	common.Must(ioutil.WriteFile(path, []byte("corrupt"), 0600))
+
+	m, err := NewManager(context.Background(), &Config{
+		Persistence: &PersistenceConfig{
+			Path: path,
+		},
+	})
+	common.Must(err)
+	common.Must(m.Close())
+
+	aside, err := filepath.Glob(path + ".corrupt.*")
+	common.Must(err)
+	if len(aside) != 1 {
+		t.Fatal("expect corrupt snapshot moved aside, but got ", aside)
+	}
+	if b, err := ioutil.ReadFile(aside[0]); err != nil || string(b) != "corrupt" {
+		t.Error("unexpected content of corrupt snapshot: ", string(b), err)
+	}
+}
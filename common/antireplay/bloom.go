@@ -0,0 +1,76 @@
+package antireplay
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// BloomFilter is a probabilistic set of byte sums. It never misses a sum that is added, but it may report a sum that
+// is not added, at a false positive rate that is bounded until its capacity is reached.
+type BloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+	// seeds keep positions of sums unpredictable, so that false positives can't be forged.
+	seeds [2]maphash.Seed
+}
+
+// NewBloomFilter creates a new BloomFilter for the capacity of sums at the false positive rate.
+func NewBloomFilter(capacity int, falsePositiveRate float64) *BloomFilter {
+	m := uint64(math.Ceil(-float64(capacity) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Ceil(float64(m) / float64(capacity) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &BloomFilter{
+		bits:  make([]uint64, (m+63)/64),
+		m:     m,
+		k:     k,
+		seeds: [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+	}
+}
+
+// positions calls fn with the k positions of the sum. They are derived from two hashes by enhanced double hashing,
+// which doesn't collapse into one position when the step is a multiple of m.
+func (f *BloomFilter) positions(sum []byte, fn func(uint64)) {
+	var h1, h2 maphash.Hash
+	h1.SetSeed(f.seeds[0])
+	h1.Write(sum) // nolint: errcheck
+	h2.SetSeed(f.seeds[1])
+	h2.Write(sum) // nolint: errcheck
+
+	pos, step := h1.Sum64()%f.m, h2.Sum64()%f.m
+	for i := uint64(0); i < f.k; i++ {
+		fn(pos)
+		pos = (pos + step) % f.m
+		step = (step + i + 1) % f.m
+	}
+}
+
+// Test returns true if the sum may be added before.
+func (f *BloomFilter) Test(sum []byte) bool {
+	found := true
+	f.positions(sum, func(pos uint64) {
+		if f.bits[pos/64]&(1<<(pos%64)) == 0 {
+			found = false
+		}
+	})
+	return found
+}
+
+// Add adds the sum into the filter.
+func (f *BloomFilter) Add(sum []byte) {
+	f.positions(sum, func(pos uint64) {
+		f.bits[pos/64] |= 1 << (pos % 64)
+	})
+}
+
+// Reset removes all sums from the filter.
+func (f *BloomFilter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
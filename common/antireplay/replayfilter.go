@@ -0,0 +1,109 @@
+// Package antireplay detects replayed messages, such as authentication headers, within a time window.
+package antireplay
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCapacity is the number of sums that a ReplayFilter is sized for in each interval.
+	DefaultCapacity = 100000
+	// DefaultFalsePositiveRate is the rate that a ReplayFilter reports unseen sums as seen, under its capacity.
+	DefaultFalsePositiveRate = 0.000001
+)
+
+// generation is the set of sums seen in an interval. Sums are kept in a bloom filter up to its capacity, and in an
+// exact set beyond it, so that the false positive rate stays bounded however many sums are seen.
+type generation struct {
+	bloom    *BloomFilter
+	capacity int
+	count    int
+	overflow map[string]struct{}
+}
+
+func newGeneration(capacity int, falsePositiveRate float64) *generation {
+	return &generation{
+		bloom:    NewBloomFilter(capacity, falsePositiveRate),
+		capacity: capacity,
+	}
+}
+
+func (g *generation) test(sum []byte) bool {
+	if g.bloom.Test(sum) {
+		return true
+	}
+	_, found := g.overflow[string(sum)]
+	return found
+}
+
+func (g *generation) add(sum []byte) {
+	if g.count < g.capacity {
+		g.bloom.Add(sum)
+		g.count++
+		return
+	}
+	if g.overflow == nil {
+		g.overflow = make(map[string]struct{})
+	}
+	g.overflow[string(sum)] = struct{}{}
+}
+
+func (g *generation) reset() {
+	g.bloom.Reset()
+	g.count = 0
+	g.overflow = nil
+}
+
+// ReplayFilter checks whether a sum is seen within an interval. It keeps sums in two generations, and drops the older
+// one every interval, so a sum is remembered for at least one interval and at most two.
+type ReplayFilter struct {
+	sync.Mutex
+	current  *generation
+	previous *generation
+	lastSwap int64
+	interval int64
+}
+
+// NewReplayFilter creates a new ReplayFilter with the interval in seconds, of the default capacity.
+func NewReplayFilter(interval int64) *ReplayFilter {
+	return NewReplayFilterWithCapacity(interval, DefaultCapacity, DefaultFalsePositiveRate)
+}
+
+// NewReplayFilterWithCapacity creates a new ReplayFilter with the interval in seconds, sized for the capacity of sums
+// in each interval at the false positive rate. Sums beyond the capacity are kept exactly, at a higher memory cost.
+func NewReplayFilterWithCapacity(interval int64, capacity int, falsePositiveRate float64) *ReplayFilter {
+	return &ReplayFilter{
+		current:  newGeneration(capacity, falsePositiveRate),
+		previous: newGeneration(capacity, falsePositiveRate),
+		lastSwap: time.Now().Unix(),
+		interval: interval,
+	}
+}
+
+// Interval returns the interval in seconds.
+func (f *ReplayFilter) Interval() int64 {
+	return f.interval
+}
+
+// Check adds the sum into the filter. It returns false if the sum is already seen.
+func (f *ReplayFilter) Check(sum []byte) bool {
+	f.Lock()
+	defer f.Unlock()
+
+	now := time.Now().Unix()
+	if elapsed := now - f.lastSwap; elapsed >= f.interval {
+		f.previous, f.current = f.current, f.previous
+		if elapsed >= 2*f.interval {
+			f.previous.reset()
+		}
+		f.current.reset()
+		f.lastSwap = now
+	}
+
+	if f.current.test(sum) || f.previous.test(sum) {
+		return false
+	}
+	f.current.add(sum)
+	return true
+}
@@ -0,0 +1,78 @@
+package antireplay_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	. "v2ray.com/core/common/antireplay"
+)
+
+func TestBloomFilter(t *testing.T) {
+	filter := NewBloomFilter(1000, 0.0001)
+
+	var sum [8]byte
+	for i := uint64(0); i < 1000; i++ {
+		binary.BigEndian.PutUint64(sum[:], i)
+		filter.Add(sum[:])
+	}
+	for i := uint64(0); i < 1000; i++ {
+		binary.BigEndian.PutUint64(sum[:], i)
+		if !filter.Test(sum[:]) {
+			t.Fatal("missing sum ", i)
+		}
+	}
+
+	falsePositives := 0
+	for i := uint64(1000); i < 101000; i++ {
+		binary.BigEndian.PutUint64(sum[:], i)
+		if filter.Test(sum[:]) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 100 {
+		t.Error("too many false positives: ", falsePositives)
+	}
+
+	filter.Reset()
+	binary.BigEndian.PutUint64(sum[:], 0)
+	if filter.Test(sum[:]) {
+		t.Error("sum remains after reset")
+	}
+}
+
+func TestReplayFilter(t *testing.T) {
+	filter := NewReplayFilterWithCapacity(120, 1000, 0.0001)
+
+	if !filter.Check([]byte("first sum")) {
+		t.Error("first sum is seen")
+	}
+	if filter.Check([]byte("first sum")) {
+		t.Error("replayed sum is not seen")
+	}
+	if !filter.Check([]byte("second sum")) {
+		t.Error("second sum is seen")
+	}
+}
+
+func TestReplayFilterOverflow(t *testing.T) {
+	filter := NewReplayFilterWithCapacity(120, 100, 0.0001)
+
+	// Sums beyond the capacity are kept exactly, so that false positives don't grow with the number of sums.
+	var sum [8]byte
+	falsePositives := 0
+	for i := uint64(0); i < 10000; i++ {
+		binary.BigEndian.PutUint64(sum[:], i)
+		if !filter.Check(sum[:]) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 10 {
+		t.Error("too many false positives: ", falsePositives)
+	}
+	for i := uint64(0); i < 10000; i++ {
+		binary.BigEndian.PutUint64(sum[:], i)
+		if filter.Check(sum[:]) {
+			t.Fatal("replayed sum ", i, " is not seen")
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package buf
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
@@ -12,11 +13,12 @@ import (
 	"v2ray.com/core/common/db"
 	"v2ray.com/core/common/db/model"
 	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/ratelimit"
 	"v2ray.com/core/common/signal"
 	//"v2ray.com/core/common/buf"
 )
 
-type dataHandler func(MultiBuffer)
+type dataHandler func(MultiBuffer) error
 
 type copyHandler struct {
 	onData []dataHandler
@@ -33,8 +35,9 @@ type CopyOption func(*copyHandler)
 // UpdateActivity is a CopyOption to update activity on each data copy operation.
 func UpdateActivity(timer signal.ActivityUpdater) CopyOption {
 	return func(handler *copyHandler) {
-		handler.onData = append(handler.onData, func(MultiBuffer) {
+		handler.onData = append(handler.onData, func(MultiBuffer) error {
 			timer.Update()
+			return nil
 		})
 	}
 }
@@ -42,8 +45,28 @@ func UpdateActivity(timer signal.ActivityUpdater) CopyOption {
 // CountSize is a CopyOption that sums the total size of data copied into the given SizeCounter.
 func CountSize(sc *SizeCounter) CopyOption {
 	return func(handler *copyHandler) {
-		handler.onData = append(handler.onData, func(b MultiBuffer) {
+		handler.onData = append(handler.onData, func(b MultiBuffer) error {
 			sc.Size += int64(b.Len())
+			return nil
+		})
+	}
+}
+
+// RateLimiter limits rate of data copied by Copy().
+type RateLimiter interface {
+	// Reserve takes n bytes from the limiter, and returns the duration to wait before they are allowed to be copied.
+	Reserve(n int64) time.Duration
+}
+
+// RateLimit is a CopyOption that blocks each data copy operation until it is allowed by the limiter, or ctx is done.
+// The timer is kept active while blocked. It does nothing if the limiter is nil.
+func RateLimit(ctx context.Context, limiter RateLimiter, timer signal.ActivityUpdater) CopyOption {
+	return func(handler *copyHandler) {
+		if limiter == nil {
+			return
+		}
+		handler.onData = append(handler.onData, func(b MultiBuffer) error {
+			return ratelimit.Sleep(ctx, limiter.Reserve(int64(b.Len())), timer)
 		})
 	}
 }
@@ -89,7 +112,10 @@ func copyInternal(reader Reader, writer Writer, handler *copyHandler) error {
 		buffer, err := reader.ReadMultiBuffer()
 		if !buffer.IsEmpty() {
 			for _, handler := range handler.onData {
-				handler(buffer)
+				if herr := handler(buffer); herr != nil {
+					ReleaseMulti(buffer)
+					return herr
+				}
 			}
 
 			if werr := writer.WriteMultiBuffer(buffer); werr != nil {
@@ -116,7 +142,10 @@ func smartCopyInternal(reader Reader, writer Writer, pool *db.Pool, handler *cop
 		// 4. if no, continue using this socks
 		if !buffer.IsEmpty() {
 			for _, handler := range handler.onData {
-				handler(buffer)
+				if herr := handler(buffer); herr != nil {
+					ReleaseMulti(buffer)
+					return ret, herr
+				}
 				str := buffer.String()
 				//newDebugMsg("Buf: smartCopyInternal buffer " + str)
 				ret += str
@@ -194,7 +223,10 @@ func relayCopyInternal(reader Reader, writer Writer, step int, addr string, hand
 			reply, err := reader.ReadMultiBuffer()
 			if !reply.IsEmpty() {
 				for _, handler := range handler.onData {
-					handler(reply)
+					if herr := handler(reply); herr != nil {
+						ReleaseMulti(reply)
+						return "", herr
+					}
 					ret += reply.String()
 				}
 				return ret, nil
@@ -1,12 +1,15 @@
 package buf_test
 
 import (
+	"context"
 	"crypto/rand"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/errors"
 	"v2ray.com/core/testing/mocks"
@@ -69,3 +72,37 @@ func BenchmarkCopy(b *testing.B) {
 		_ = buf.Copy(reader, writer)
 	}
 }
+
+type countingLimiter struct {
+	total int64
+}
+
+func (l *countingLimiter) Reserve(n int64) time.Duration {
+	l.total += n
+	return 0
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := new(countingLimiter)
+	reader := buf.NewReader(io.LimitReader(rand.Reader, 3000))
+	common.Must(buf.Copy(reader, buf.Discard, buf.RateLimit(context.Background(), limiter, nil), buf.RateLimit(context.Background(), nil, nil)))
+	if limiter.total != 3000 {
+		t.Error("expect 3000 bytes limited, but got ", limiter.total)
+	}
+}
+
+type blockingLimiter struct{}
+
+func (blockingLimiter) Reserve(n int64) time.Duration {
+	return time.Hour
+}
+
+func TestRateLimitCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	reader := buf.NewReader(io.LimitReader(rand.Reader, 3000))
+	if err := buf.Copy(reader, buf.Discard, buf.RateLimit(ctx, blockingLimiter{}, nil)); err != context.Canceled {
+		t.Error("expect copy to be canceled, but got ", err)
+	}
+}
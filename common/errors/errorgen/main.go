@@ -31,8 +31,6 @@ func main() {
 	fmt.Fprintln(file, "package", pkg)
 	fmt.Fprintln(file, "")
 	fmt.Fprintln(file, "import \"v2ray.com/core/common/errors\"")
-	fmt.Fprintln(file, "import \"os\"")
-	fmt.Fprintln(file, "import \"time\"")
 	fmt.Fprintln(file, "import \"fmt\"")
 	fmt.Fprintln(file, "")
 	//fmt.Fprintln(file, "const pkg = \"" + pkg + "\"")
@@ -40,16 +38,7 @@ func main() {
 	fmt.Fprintln(file, "func newError(values ...interface{}) *errors.Error { return errors.New(values...).WithPathObj(errPathObjHolder{}) }")
 	fmt.Fprintln(file, `
 func newDebugMsg(msg string) {
-	f, err := os.OpenFile("/tmp/v2ray_debug.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-			panic(err)
-	}
-	t := time.Now()
-	ts := t.Format("2006-01-02 15:04:05")
-	defer f.Close()
-	if _, err = f.WriteString(ts + ": " + msg + "\n"); err != nil {
-		panic(err)
-	}
+	newError(msg).AtDebug().WriteToLog()
 }`)
 	fmt.Fprintln(file, `
 func StructString(class interface{}) string {
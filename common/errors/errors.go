@@ -22,11 +22,12 @@ type hasSeverity interface {
 
 // Error is an error object with underlying error.
 type Error struct {
-	pathObj  interface{}
-	prefix   []interface{}
-	message  []interface{}
-	inner    error
-	severity log.Severity
+	pathObj   interface{}
+	prefix    []interface{}
+	message   []interface{}
+	inner     error
+	severity  log.Severity
+	sessionID uint32
 }
 
 func (err *Error) WithPathObj(obj interface{}) *Error {
@@ -56,15 +57,27 @@ func (err *Error) Error() string {
 		builder.WriteString(": ")
 	}
 
-	msg := serial.Concat(err.message...)
-	builder.WriteString(msg)
+	builder.WriteString(err.LogMessage())
+	return builder.String()
+}
+
+// LogPath implements log.StructuredContent.
+func (err *Error) LogPath() string {
+	return err.pkgPath()
+}
 
+// LogSessionID implements log.StructuredContent.
+func (err *Error) LogSessionID() uint32 {
+	return err.sessionID
+}
+
+// LogMessage implements log.StructuredContent. It is the message of this error and its inner errors.
+func (err *Error) LogMessage() string {
+	msg := serial.Concat(err.message...)
 	if err.inner != nil {
-		builder.WriteString(" > ")
-		builder.WriteString(err.inner.Error())
+		msg += " > " + err.inner.Error()
 	}
-
-	return builder.String()
+	return msg
 }
 
 // Inner implements hasInnerError.Inner()
@@ -135,6 +148,7 @@ func (err *Error) WriteToLog(opts ...ExportOption) {
 
 	if holder.SessionID > 0 {
 		err.prefix = append(err.prefix, holder.SessionID)
+		err.sessionID = holder.SessionID
 	}
 
 	log.Record(&log.GeneralMessage{
@@ -2,7 +2,9 @@ package log
 
 import (
 	"context"
+	"strconv"
 	"strings"
+	"time"
 
 	"v2ray.com/core/common/serial"
 )
@@ -18,6 +20,8 @@ type AccessStatus string
 const (
 	AccessAccepted = AccessStatus("accepted")
 	AccessRejected = AccessStatus("rejected")
+	// AccessClosed is the status of a finished session, with its traffic and duration.
+	AccessClosed = AccessStatus("closed")
 )
 
 type AccessMessage struct {
@@ -27,6 +31,14 @@ type AccessMessage struct {
 	Reason interface{}
 	Email  string
 	Detour string
+
+	SessionID  uint32
+	InboundTag string
+	// Domain is the domain sniffed from the traffic, if any.
+	Domain   string
+	Uplink   int64
+	Downlink int64
+	Duration time.Duration
 }
 
 func (m *AccessMessage) String() string {
@@ -44,11 +56,29 @@ func (m *AccessMessage) String() string {
 	}
 	builder.WriteString(serial.ToString(m.Reason))
 
+	// separate writes a space if the message doesn't end with one.
+	separate := func() {
+		if str := builder.String(); len(str) > 0 && str[len(str)-1] != ' ' {
+			builder.WriteByte(' ')
+		}
+	}
+
 	if len(m.Email) > 0 {
+		separate()
 		builder.WriteString("email:")
 		builder.WriteString(m.Email)
 		builder.WriteByte(' ')
 	}
+
+	if m.Status == AccessClosed {
+		separate()
+		builder.WriteString("uplink:")
+		builder.WriteString(strconv.FormatInt(m.Uplink, 10))
+		builder.WriteString(" downlink:")
+		builder.WriteString(strconv.FormatInt(m.Downlink, 10))
+		builder.WriteString(" duration:")
+		builder.WriteString(m.Duration.String())
+	}
 	return builder.String()
 }
 
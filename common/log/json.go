@@ -0,0 +1,103 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"v2ray.com/core/common/serial"
+)
+
+// StructuredContent is implemented by contents of GeneralMessage that have structured fields, such as errors.
+type StructuredContent interface {
+	// LogPath returns the package path where the content is created.
+	LogPath() string
+	// LogSessionID returns the ID of the session that the content belongs to, or 0 if none.
+	LogSessionID() uint32
+	// LogMessage returns the message of the content, without the path and the session ID.
+	LogMessage() string
+}
+
+// JSONMessage is a message formatted as a JSON object in one line.
+type JSONMessage struct {
+	Time    time.Time
+	Message Message
+}
+
+type jsonAccess struct {
+	Time      string  `json:"time"`
+	Type      string  `json:"type"`
+	SessionID uint32  `json:"session,omitempty"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Status    string  `json:"status"`
+	Inbound   string  `json:"inbound,omitempty"`
+	Outbound  string  `json:"outbound,omitempty"`
+	Domain    string  `json:"domain,omitempty"`
+	Email     string  `json:"email,omitempty"`
+	Uplink    int64   `json:"uplink,omitempty"`
+	Downlink  int64   `json:"downlink,omitempty"`
+	Duration  float64 `json:"duration,omitempty"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+type jsonGeneral struct {
+	Time      string `json:"time"`
+	Type      string `json:"type"`
+	Severity  string `json:"severity"`
+	SessionID uint32 `json:"session,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Message   string `json:"message"`
+}
+
+// String implements Message.
+func (m *JSONMessage) String() string {
+	t := m.Time.Format(time.RFC3339Nano)
+
+	var v interface{}
+	switch msg := m.Message.(type) {
+	case *AccessMessage:
+		v = &jsonAccess{
+			Time:      t,
+			Type:      "access",
+			SessionID: msg.SessionID,
+			From:      serial.ToString(msg.From),
+			To:        serial.ToString(msg.To),
+			Status:    string(msg.Status),
+			Inbound:   msg.InboundTag,
+			Outbound:  msg.Detour,
+			Domain:    msg.Domain,
+			Email:     msg.Email,
+			Uplink:    msg.Uplink,
+			Downlink:  msg.Downlink,
+			Duration:  msg.Duration.Seconds(),
+			Reason:    serial.ToString(msg.Reason),
+		}
+	case *GeneralMessage:
+		g := &jsonGeneral{
+			Time:     t,
+			Type:     "error",
+			Severity: strings.ToLower(msg.Severity.String()),
+		}
+		if c, ok := msg.Content.(StructuredContent); ok {
+			g.SessionID = c.LogSessionID()
+			g.Path = c.LogPath()
+			g.Message = c.LogMessage()
+		} else {
+			g.Message = serial.ToString(msg.Content)
+		}
+		v = g
+	default:
+		v = &jsonGeneral{
+			Time:    t,
+			Type:    "unknown",
+			Message: msg.String(),
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return m.Message.String()
+	}
+	return string(b)
+}
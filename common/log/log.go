@@ -27,11 +27,23 @@ func (m *GeneralMessage) String() string {
 	return serial.Concat("[", m.Severity, "] ", m.Content)
 }
 
+// ClosedAccessHandler is an optional interface of Handler, for handlers that may drop the access messages of closed
+// sessions.
+type ClosedAccessHandler interface {
+	HandlesClosedAccess() bool
+}
+
 // Record writes a message into log stream.
 func Record(msg Message) {
 	logHandler.Handle(msg)
 }
 
+// ClosedAccessHandled returns whether the current log handler handles the access messages of closed sessions. If not,
+// the traffic and duration of sessions don't need to be collected.
+func ClosedAccessHandled() bool {
+	return logHandler.handlesClosedAccess()
+}
+
 var (
 	logHandler syncHandler
 )
@@ -58,6 +70,19 @@ func (h *syncHandler) Handle(msg Message) {
 	}
 }
 
+func (h *syncHandler) handlesClosedAccess() bool {
+	h.RLock()
+	defer h.RUnlock()
+
+	if h.Handler == nil {
+		return false
+	}
+	if c, ok := h.Handler.(ClosedAccessHandler); ok {
+		return c.HandlesClosedAccess()
+	}
+	return true
+}
+
 func (h *syncHandler) Set(handler Handler) {
 	h.Lock()
 	defer h.Unlock()
@@ -1,10 +1,13 @@
 package log_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
+	"v2ray.com/core/common/errors"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 )
@@ -31,3 +34,100 @@ func TestLogRecord(t *testing.T) {
 		t.Error(diff)
 	}
 }
+
+func TestJSONMessage(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		msg  log.Message
+		want map[string]interface{}
+	}{
+		{
+			msg: &log.AccessMessage{
+				From:       net.ParseAddress("1.2.3.4"),
+				To:         "tcp:example.com:443",
+				Status:     log.AccessClosed,
+				Reason:     "closed",
+				Email:      "love@v2ray.com",
+				Detour:     "direct",
+				SessionID:  123,
+				InboundTag: "in",
+				Domain:     "example.com",
+				Uplink:     10,
+				Downlink:   20,
+				Duration:   time.Second * 3,
+			},
+			want: map[string]interface{}{
+				"time":     "2020-01-02T03:04:05Z",
+				"type":     "access",
+				"session":  123.0,
+				"from":     "1.2.3.4",
+				"to":       "tcp:example.com:443",
+				"status":   "closed",
+				"inbound":  "in",
+				"outbound": "direct",
+				"domain":   "example.com",
+				"email":    "love@v2ray.com",
+				"uplink":   10.0,
+				"downlink": 20.0,
+				"duration": 3.0,
+				"reason":   "closed",
+			},
+		},
+		{
+			msg: &log.GeneralMessage{
+				Severity: log.Severity_Warning,
+				Content:  errors.New("test").Base(errors.New("inner")).WithPathObj(net.Destination{}),
+			},
+			want: map[string]interface{}{
+				"time":     "2020-01-02T03:04:05Z",
+				"type":     "error",
+				"severity": "warning",
+				"path":     "v2ray.com/core/common/net",
+				"message":  "test > inner",
+			},
+		},
+		{
+			msg: &log.GeneralMessage{
+				Severity: log.Severity_Info,
+				Content:  "plain",
+			},
+			want: map[string]interface{}{
+				"time":     "2020-01-02T03:04:05Z",
+				"type":     "error",
+				"severity": "info",
+				"message":  "plain",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte((&log.JSONMessage{Time: now, Message: tc.msg}).String()), &got); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestClosedAccessMessageString(t *testing.T) {
+	msg := &log.AccessMessage{
+		From:     net.ParseAddress("1.2.3.4"),
+		To:       "tcp:example.com:443",
+		Status:   log.AccessClosed,
+		Reason:   "closed",
+		Uplink:   10,
+		Downlink: 20,
+		Duration: time.Second * 3,
+	}
+	if diff := cmp.Diff("1.2.3.4 closed tcp:example.com:443 closed uplink:10 downlink:20 duration:3s", msg.String()); diff != "" {
+		t.Error(diff)
+	}
+
+	msg.Email = "love@v2ray.com"
+	if diff := cmp.Diff("1.2.3.4 closed tcp:example.com:443 closed email:love@v2ray.com uplink:10 downlink:20 duration:3s", msg.String()); diff != "" {
+		t.Error(diff)
+	}
+}
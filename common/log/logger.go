@@ -112,9 +112,15 @@ func (w *fileLogWriter) Close() error {
 
 // CreateStdoutLogWriter returns a LogWriterCreator that creates LogWriter for stdout.
 func CreateStdoutLogWriter() WriterCreator {
+	return CreateStdoutLogWriterWithFlags(log.Ldate | log.Ltime)
+}
+
+// CreateStdoutLogWriterWithFlags returns a LogWriterCreator that creates LogWriter for stdout, with the flags of
+// standard log package. Flags of 0 writes messages without timestamps, such as JSON messages that have their own.
+func CreateStdoutLogWriterWithFlags(flag int) WriterCreator {
 	return func() Writer {
 		return &consoleLogWriter{
-			logger: log.New(os.Stdout, "", log.Ldate|log.Ltime),
+			logger: log.New(os.Stdout, "", flag),
 		}
 	}
 }
@@ -130,6 +136,12 @@ func CreateStderrLogWriter() WriterCreator {
 
 // CreateFileLogWriter returns a LogWriterCreator that creates LogWriter for the given file.
 func CreateFileLogWriter(path string) (WriterCreator, error) {
+	return CreateFileLogWriterWithFlags(path, log.Ldate|log.Ltime)
+}
+
+// CreateFileLogWriterWithFlags returns a LogWriterCreator that creates LogWriter for the given file, with the flags of
+// standard log package.
+func CreateFileLogWriterWithFlags(path string, flag int) (WriterCreator, error) {
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
 	if err != nil {
 		return nil, err
@@ -142,7 +154,7 @@ func CreateFileLogWriter(path string) (WriterCreator, error) {
 		}
 		return &fileLogWriter{
 			file:   file,
-			logger: log.New(file, "", log.Ldate|log.Ltime),
+			logger: log.New(file, "", flag),
 		}
 	}, nil
 }
@@ -0,0 +1,272 @@
+package log
+
+import (
+	"compress/gzip"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotationOptions are options of rotating log files. A log file is renamed to a backup with the time of rotation as
+// suffix, such as "access.log.2020-01-02T15-04-05.000", and a new file is created in place.
+type RotationOptions struct {
+	// MaxSize is the size in bytes that a log file is rotated before exceeding. 0 for no limit.
+	MaxSize int64
+	// Interval is the duration that a log file is rotated after. 0 for no limit. Rotations are aligned to multiples of
+	// Interval in local time, e.g., a daily log file is rotated at midnight.
+	Interval time.Duration
+	// MaxBackups is the number of backups to keep. 0 to keep all.
+	MaxBackups int
+	// MaxAge is the duration that backups are kept for. 0 to keep all.
+	MaxAge time.Duration
+	// Compress compresses backups with gzip.
+	Compress bool
+}
+
+// rotatingFile is an io.Writer that writes into a log file, and rotates it with its options. It is shared by all
+// Writers created by the same WriterCreator, as a Writer is created for each run of the logger.
+type rotatingFile struct {
+	sync.Mutex
+	path    string
+	options RotationOptions
+	file    *os.File
+	size    int64
+	// deadline is the time that the current file is rotated at, or zero if it is not known yet.
+	deadline time.Time
+	// cleanup is closed when compression and removal of backups after the last rotation are done.
+	cleanup chan struct{}
+}
+
+// nextBoundary returns the first multiple of interval in local time that is after t.
+func nextBoundary(t time.Time, interval time.Duration) time.Time {
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(interval).Add(interval).Add(-shift)
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	if f.deadline.IsZero() && f.options.Interval > 0 {
+		// An existing file is rotated at the end of the interval it was last written in, even across restarts.
+		last := time.Now()
+		if f.size > 0 {
+			last = info.ModTime()
+		}
+		f.deadline = nextBoundary(last, f.options.Interval)
+	}
+	return nil
+}
+
+func (f *rotatingFile) shouldRotate(n int) bool {
+	if f.size == 0 {
+		return false
+	}
+	if f.options.MaxSize > 0 && f.size+int64(n) > f.options.MaxSize {
+		return true
+	}
+	return f.options.Interval > 0 && !time.Now().Before(f.deadline)
+}
+
+// Write implements io.Writer.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.shouldRotate(len(p)) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	now := time.Now()
+	backup := f.backupName(now)
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+
+	// Compression may take long, so it is done in background without blocking writes. Cleanups run one after another.
+	last := f.cleanup
+	done := make(chan struct{})
+	f.cleanup = done
+	go func() {
+		defer close(done)
+		if last != nil {
+			<-last
+		}
+		if f.options.Compress {
+			// The backup is kept uncompressed on failure.
+			compressFile(backup) // nolint: errcheck
+		}
+		f.removeBackups(now)
+	}()
+
+	if f.options.Interval > 0 {
+		f.deadline = nextBoundary(now, f.options.Interval)
+	}
+	return f.open()
+}
+
+// backupName returns a name of backup that is not used, with the time of rotation.
+func (f *rotatingFile) backupName(t time.Time) string {
+	for {
+		name := f.path + "." + t.Format(backupTimeFormat)
+		if !fileExists(name) && !fileExists(name+".gz") {
+			return name
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// backups returns the backups of the log file, from the oldest to the newest.
+func (f *rotatingFile) backups() []string {
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return nil
+	}
+	var backups []string
+	for _, m := range matches {
+		if _, err := time.ParseInLocation(backupTimeFormat, backupTime(f.path, m), time.Local); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Strings(backups)
+	return backups
+}
+
+func backupTime(path string, backup string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(backup, path+"."), ".gz")
+}
+
+func (f *rotatingFile) removeBackups(now time.Time) {
+	backups := f.backups()
+	if f.options.MaxBackups > 0 && len(backups) > f.options.MaxBackups {
+		for _, b := range backups[:len(backups)-f.options.MaxBackups] {
+			os.Remove(b) // nolint: errcheck
+		}
+		backups = backups[len(backups)-f.options.MaxBackups:]
+	}
+	if f.options.MaxAge > 0 {
+		for _, b := range backups {
+			t, _ := time.ParseInLocation(backupTimeFormat, backupTime(f.path, b), time.Local)
+			if now.Sub(t) > f.options.MaxAge {
+				os.Remove(b) // nolint: errcheck
+			}
+		}
+	}
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close() // nolint: errcheck
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	writer := gzip.NewWriter(dst)
+	_, err = io.Copy(writer, src)
+	if err == nil {
+		err = writer.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path + ".gz") // nolint: errcheck
+		return err
+	}
+	return os.Remove(path)
+}
+
+// closeFile closes the current file, and waits for cleanups of backups.
+func (f *rotatingFile) closeFile() error {
+	f.Lock()
+	cleanup := f.cleanup
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.Unlock()
+
+	if cleanup != nil {
+		<-cleanup
+	}
+	return err
+}
+
+type rotatingLogWriter struct {
+	file   *rotatingFile
+	logger *log.Logger
+}
+
+func (w *rotatingLogWriter) Write(s string) error {
+	w.logger.Print(s)
+	return nil
+}
+
+func (w *rotatingLogWriter) Close() error {
+	return w.file.closeFile()
+}
+
+// CreateRotatingFileLogWriter returns a LogWriterCreator that creates LogWriter for the given file, which is rotated
+// with the given options. flag is the flags of standard log package.
+func CreateRotatingFileLogWriter(path string, flag int, options RotationOptions) (WriterCreator, error) {
+	file := &rotatingFile{
+		path:    path,
+		options: options,
+	}
+	if err := file.open(); err != nil {
+		return nil, err
+	}
+	if err := file.closeFile(); err != nil {
+		return nil, err
+	}
+	return func() Writer {
+		return &rotatingLogWriter{
+			file:   file,
+			logger: log.New(file, "", flag),
+		}
+	}, nil
+}
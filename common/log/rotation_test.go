@@ -0,0 +1,92 @@
+package log_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/common/log"
+)
+
+func TestRotatingFileLogger(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vtest")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "access.log")
+	creator, err := CreateRotatingFileLogWriter(path, 0, RotationOptions{
+		MaxSize:    20,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	common.Must(err)
+
+	for _, s := range []string{"line 1", "line 2", "line 3", "line 4", "line 5"} {
+		// Each line has 7 bytes, so that every 2 lines are rotated.
+		writer := creator()
+		common.Must(writer.Write(s + "\n"))
+		common.Must(writer.Close())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	common.Must(err)
+	if string(b) != "line 5\n" {
+		t.Error("unexpected content of current file: ", string(b))
+	}
+
+	backups, err := filepath.Glob(path + ".*.gz")
+	common.Must(err)
+	if len(backups) != 2 {
+		t.Fatal("expect 2 backups, but got ", backups)
+	}
+
+	f, err := os.Open(backups[1])
+	common.Must(err)
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	common.Must(err)
+	b, err = ioutil.ReadAll(reader)
+	common.Must(err)
+	if !strings.HasPrefix(string(b), "line 3\nline 4\n") {
+		t.Error("unexpected content of backup: ", string(b))
+	}
+}
+
+func TestRotatingFileLoggerInterval(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vtest")
+	common.Must(err)
+	defer os.RemoveAll(dir)
+
+	// The file is last written two days ago, so it is rotated on the first write even after restart.
+	path := filepath.Join(dir, "access.log")
+	common.Must(ioutil.WriteFile(path, []byte("old\n"), 0600))
+	old := time.Now().Add(-48 * time.Hour)
+	common.Must(os.Chtimes(path, old, old))
+
+	creator, err := CreateRotatingFileLogWriter(path, 0, RotationOptions{
+		Interval: 24 * time.Hour,
+	})
+	common.Must(err)
+
+	for _, s := range []string{"line 1", "line 2"} {
+		writer := creator()
+		common.Must(writer.Write(s + "\n"))
+		common.Must(writer.Close())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	common.Must(err)
+	if string(b) != "line 1\nline 2\n" {
+		t.Error("unexpected content of current file: ", string(b))
+	}
+	backups, err := filepath.Glob(path + ".*")
+	common.Must(err)
+	if len(backups) != 1 {
+		t.Fatal("expect 1 backup, but got ", backups)
+	}
+}
@@ -19,9 +19,15 @@ var LookupIP = net.LookupIP
 
 var FileConn = net.FileConn
 
+// Pipe is an alias of net.Pipe
+var Pipe = net.Pipe
+
 // ParseIP is an alias of net.ParseIP
 var ParseIP = net.ParseIP
 
+// ParseCIDR is an alias of net.ParseCIDR
+var ParseCIDR = net.ParseCIDR
+
 var SplitHostPort = net.SplitHostPort
 
 var CIDRMask = net.CIDRMask
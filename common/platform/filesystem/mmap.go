@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"runtime"
+
+	"v2ray.com/core/common/platform"
+)
+
+// MappedFile is a read-only view of a whole file in memory. On platforms that support it, the file is memory-mapped,
+// so that its content is loaded by the OS on demand and shared between processes.
+type MappedFile struct {
+	data  []byte
+	unmap func() error
+}
+
+// MapFile maps the file at the given path into memory. The mapping is released by Close, or when the MappedFile is
+// garbage collected. Bytes returned by the MappedFile must not be used after that.
+//
+// A mapped file must not be modified in place. Truncating it may crash the process with SIGBUS on access, and writes
+// may be visible through the mapping. To update the file, write a new file and atomically rename it over the old one.
+// Existing mappings keep referring to the old content until they are released.
+func MapFile(path string) (*MappedFile, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f := &MappedFile{
+		data:  data,
+		unmap: unmap,
+	}
+	runtime.SetFinalizer(f, (*MappedFile).Close)
+	return f, nil
+}
+
+// MapAsset maps the given file in asset location into memory.
+func MapAsset(file string) (*MappedFile, error) {
+	return MapFile(platform.GetAssetLocation(file))
+}
+
+// Bytes returns the content of the file. The returned bytes are read-only.
+func (f *MappedFile) Bytes() []byte {
+	return f.data
+}
+
+// Close releases the mapping.
+func (f *MappedFile) Close() error {
+	runtime.SetFinalizer(f, nil)
+	f.data = nil
+	if f.unmap == nil {
+		return nil
+	}
+	unmap := f.unmap
+	f.unmap = nil
+	return unmap()
+}
@@ -0,0 +1,39 @@
+// +build linux darwin freebsd netbsd openbsd dragonfly
+
+package filesystem
+
+import (
+	"os"
+	"syscall"
+
+	"v2ray.com/core/common/errors"
+)
+
+func mapFile(path string) ([]byte, func() error, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := info.Size()
+	if size == 0 {
+		return []byte{}, nil, nil
+	}
+	if int64(int(size)) != size {
+		return nil, nil, errors.New("file too large to map: ", path)
+	}
+
+	// Writes to the file through other descriptors may still be visible through the mapping, see MapFile.
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_PRIVATE)
+	if err != nil {
+		return nil, nil, errors.New("failed to map file: ", path).Base(err)
+	}
+	return data, func() error {
+		return syscall.Munmap(data)
+	}, nil
+}
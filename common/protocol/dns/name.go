@@ -0,0 +1,20 @@
+package dns
+
+// AppendName appends the domain name in uncompressed wire format. Empty labels are skipped, so a trailing dot is optional.
+func AppendName(b []byte, name string) []byte {
+	start := 0
+	for i := 0; i < len(name); i++ {
+		if name[i] == '.' {
+			if i > start {
+				b = append(b, byte(i-start))
+				b = append(b, name[start:i]...)
+			}
+			start = i + 1
+		}
+	}
+	if start < len(name) {
+		b = append(b, byte(len(name)-start))
+		b = append(b, name[start:]...)
+	}
+	return append(b, 0)
+}
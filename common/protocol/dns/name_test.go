@@ -0,0 +1,21 @@
+package dns_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	. "v2ray.com/core/common/protocol/dns"
+)
+
+func TestAppendName(t *testing.T) {
+	expected := []byte{5, 'v', '2', 'r', 'a', 'y', 3, 'c', 'o', 'm', 0}
+	for _, name := range []string{"v2ray.com", "v2ray.com.", "v2ray..com"} {
+		if r := cmp.Diff(AppendName(nil, name), expected); r != "" {
+			t.Error(name, ": ", r)
+		}
+	}
+	if r := cmp.Diff(AppendName([]byte{1}, "."), []byte{1, 0}); r != "" {
+		t.Error(r)
+	}
+}
@@ -0,0 +1,110 @@
+package protocol
+
+import (
+	"crypto/subtle"
+	"strings"
+	"sync"
+)
+
+// PasswordAccount is an Account that authenticates with username and password.
+type PasswordAccount interface {
+	Account
+	GetUsername() string
+	GetPassword() string
+}
+
+// PasswordUsers are users of a server by their usernames, for servers that authenticate with username and password.
+// The email of a user is its username if not set.
+type PasswordUsers struct {
+	sync.RWMutex
+	users map[string]*MemoryUser
+	// required is set once there is any user, so that removing all users doesn't open the server.
+	required bool
+}
+
+// NewPasswordUsers creates PasswordUsers with the given users. Authentication is required if required is true, or
+// once a user is added later.
+func NewPasswordUsers(users []*MemoryUser, required bool) *PasswordUsers {
+	u := &PasswordUsers{
+		users:    make(map[string]*MemoryUser, len(users)),
+		required: required,
+	}
+	for _, user := range users {
+		u.users[user.Account.(PasswordAccount).GetUsername()] = user
+	}
+	return u
+}
+
+// AuthRequired returns whether clients must authenticate with username and password.
+func (u *PasswordUsers) AuthRequired() bool {
+	u.RLock()
+	defer u.RUnlock()
+
+	return u.required
+}
+
+// Get returns the user with the username and the password, or nil if not found.
+func (u *PasswordUsers) Get(username, password string) *MemoryUser {
+	u.RLock()
+	defer u.RUnlock()
+
+	user, found := u.users[username]
+	if !found || subtle.ConstantTimeCompare([]byte(user.Account.(PasswordAccount).GetPassword()), []byte(password)) != 1 {
+		return nil
+	}
+	return user
+}
+
+// Add adds a user. It returns an error if a user with the same username or email exists.
+func (u *PasswordUsers) Add(user *MemoryUser) error {
+	account, ok := user.Account.(PasswordAccount)
+	if !ok {
+		return newError("not a password account")
+	}
+	username := account.GetUsername()
+
+	u.Lock()
+	defer u.Unlock()
+
+	if _, found := u.users[username]; found {
+		return newError("User ", username, " already exists.")
+	}
+	added := *user
+	if len(added.Email) == 0 {
+		added.Email = username
+	}
+	for _, existing := range u.users {
+		if strings.EqualFold(existing.Email, added.Email) {
+			return newError("User ", added.Email, " already exists.")
+		}
+	}
+	u.users[username] = &added
+	u.required = true
+	return nil
+}
+
+// Remove removes the user with the email.
+func (u *PasswordUsers) Remove(email string) error {
+	u.Lock()
+	defer u.Unlock()
+
+	for username, user := range u.users {
+		if strings.EqualFold(user.Email, email) {
+			delete(u.users, username)
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// List returns all users.
+func (u *PasswordUsers) List() []*MemoryUser {
+	u.RLock()
+	defer u.RUnlock()
+
+	users := make([]*MemoryUser, 0, len(u.users))
+	for _, user := range u.users {
+		users = append(users, user)
+	}
+	return users
+}
@@ -0,0 +1,59 @@
+package protocol_test
+
+import (
+	"testing"
+
+	. "v2ray.com/core/common/protocol"
+	"v2ray.com/core/proxy/socks"
+)
+
+func TestPasswordUsers(t *testing.T) {
+	users := NewPasswordUsers([]*MemoryUser{
+		{
+			Account: &socks.Account{Username: "a", Password: "pa"},
+			Email:   "a",
+		},
+	}, false)
+	if users.AuthRequired() {
+		t.Error("expect auth not required")
+	}
+	if user := users.Get("a", "pa"); user == nil || user.Email != "a" {
+		t.Error("expect user a, but got ", user)
+	}
+	if user := users.Get("a", "wrong"); user != nil {
+		t.Error("expect nil for wrong password, but got ", user)
+	}
+
+	if err := users.Add(&MemoryUser{Account: &socks.Account{Username: "b", Password: "pb"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !users.AuthRequired() {
+		t.Error("expect auth required after adding a user")
+	}
+	if user := users.Get("b", "pb"); user == nil || user.Email != "b" {
+		t.Error("expect user b with username as email, but got ", user)
+	}
+
+	if err := users.Add(&MemoryUser{Account: &socks.Account{Username: "b", Password: "other"}, Email: "c"}); err == nil {
+		t.Error("expect error for duplicate username")
+	}
+	if err := users.Add(&MemoryUser{Account: &socks.Account{Username: "c", Password: "pc"}, Email: "A"}); err == nil {
+		t.Error("expect error for duplicate email")
+	}
+
+	if err := users.Remove("B"); err != nil {
+		t.Error(err)
+	}
+	if err := users.Remove("b"); err == nil {
+		t.Error("expect error for removed user")
+	}
+	if user := users.Get("b", "pb"); user != nil {
+		t.Error("expect nil for removed user, but got ", user)
+	}
+	if n := len(users.List()); n != 1 {
+		t.Error("expect 1 user, but got ", n)
+	}
+	if !users.AuthRequired() {
+		t.Error("expect auth still required after removing a user")
+	}
+}
@@ -0,0 +1,107 @@
+// Package ratelimit implements a token bucket for limiting rate of bytes.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common/signal"
+)
+
+// keepAliveInterval is how often the activity timer is updated while waiting for tokens. It is shorter than any
+// timeout in policies, which are in seconds.
+const keepAliveInterval = 500 * time.Millisecond
+
+// Bucket is a token bucket that is filled with rate tokens per second, and holds at most rate tokens. A token is a
+// byte of traffic. A Bucket with zero rate is unlimited. Bucket is safe for concurrent use.
+type Bucket struct {
+	sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket creates a full Bucket with the rate in bytes per second.
+func NewBucket(rate int64) *Bucket {
+	return &Bucket{
+		rate:   float64(rate),
+		tokens: float64(rate),
+		last:   time.Now(),
+	}
+}
+
+func (b *Bucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+	b.last = now
+}
+
+// SetRate changes the rate of the bucket. Tokens in the bucket are kept, up to the new rate.
+func (b *Bucket) SetRate(rate int64) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.refill(time.Now())
+	b.rate = float64(rate)
+	if b.tokens > b.rate {
+		b.tokens = b.rate
+	}
+}
+
+// Reserve takes n tokens from the bucket, and returns the duration to wait before the tokens are available. The
+// bucket may go into debt, so that a request larger than the rate is delayed instead of blocked forever. The debt is
+// capped at one second of tokens, so that a single large request doesn't stall all sessions sharing the bucket.
+func (b *Bucket) Reserve(n int64) time.Duration {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill(time.Now())
+
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	if b.tokens < -b.rate {
+		b.tokens = -b.rate
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait takes n tokens from the bucket, and blocks until they are available or ctx is done. The timer, if not nil, is
+// kept active while blocked. It returns the duration blocked.
+func (b *Bucket) Wait(ctx context.Context, n int64, timer signal.ActivityUpdater) (time.Duration, error) {
+	d := b.Reserve(n)
+	return d, Sleep(ctx, d, timer)
+}
+
+// Sleep blocks for the duration d, or until ctx is done, in which case it returns the error of ctx. The timer, if not
+// nil, is updated periodically while blocked, so that a throttled session is not considered idle.
+func Sleep(ctx context.Context, d time.Duration, timer signal.ActivityUpdater) error {
+	if d <= 0 {
+		return nil
+	}
+
+	deadline := time.NewTimer(d)
+	defer deadline.Stop()
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-deadline.C:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if timer != nil {
+				timer.Update()
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "v2ray.com/core/common/ratelimit"
+)
+
+func TestBucketReserve(t *testing.T) {
+	b := NewBucket(1000)
+
+	if d := b.Reserve(1000); d != 0 {
+		t.Error("expect no wait on full bucket, but got ", d)
+	}
+	if d := b.Reserve(500); d < 400*time.Millisecond || d > 500*time.Millisecond {
+		t.Error("expect about 500ms wait, but got ", d)
+	}
+	if d := b.Reserve(500); d < 900*time.Millisecond || d > time.Second {
+		t.Error("expect about 1s wait on debt, but got ", d)
+	}
+	if d := b.Reserve(5000); d > time.Second {
+		t.Error("expect debt capped at 1s, but got ", d)
+	}
+}
+
+func TestBucketSetRate(t *testing.T) {
+	b := NewBucket(1000)
+	b.Reserve(1000)
+
+	b.SetRate(0)
+	if d := b.Reserve(1000000); d != 0 {
+		t.Error("expect no wait on unlimited bucket, but got ", d)
+	}
+	b.SetRate(100)
+	if d := b.Reserve(100); d < 900*time.Millisecond {
+		t.Error("expect about 1s wait on new rate, but got ", d)
+	}
+}
+
+type countingTimer struct {
+	updates int
+}
+
+func (t *countingTimer) Update() {
+	t.updates++
+}
+
+func TestBucketWait(t *testing.T) {
+	b := NewBucket(1000)
+	b.Reserve(1000)
+
+	timer := new(countingTimer)
+	start := time.Now()
+	if _, err := b.Wait(context.Background(), 1000, timer); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 900*time.Millisecond {
+		t.Error("expect to wait about 1s, but got ", d)
+	}
+	if timer.updates == 0 {
+		t.Error("expect timer to be updated while waiting")
+	}
+}
+
+func TestBucketWaitCancel(t *testing.T) {
+	b := NewBucket(1000)
+	b.Reserve(1000)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := b.Wait(ctx, 1000, nil); err != context.DeadlineExceeded {
+		t.Error("expect deadline exceeded, but got ", err)
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Error("expect to return on cancel, but waited ", d)
+	}
+}
@@ -94,6 +94,19 @@ func (s *Service) Subscribe(name string) *Subscriber {
 	return sub
 }
 
+// HasSubscriber returns true if there is at least one open subscriber of the given name.
+func (s *Service) HasSubscriber(name string) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, sub := range s.subs[name] {
+		if !sub.IsClosed() {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Service) Publish(name string, message interface{}) {
 	s.RLock()
 	defer s.RUnlock()
@@ -9,7 +9,13 @@ import (
 func TestPubsub(t *testing.T) {
 	service := NewService()
 
+	if service.HasSubscriber("a") {
+		t.Error("unexpected subscriber")
+	}
 	sub := service.Subscribe("a")
+	if !service.HasSubscriber("a") {
+		t.Error("expected subscriber of a")
+	}
 	service.Publish("a", 1)
 
 	select {
@@ -22,6 +28,9 @@ func TestPubsub(t *testing.T) {
 	}
 
 	sub.Close()
+	if service.HasSubscriber("a") {
+		t.Error("closed subscriber is counted")
+	}
 	service.Publish("a", 2)
 
 	select {
@@ -0,0 +1,285 @@
+package strmatcher
+
+import (
+	"encoding/binary"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DomainGraph is an immutable, minimized graph (DAFSA) of reversed domains, serialized as a flat byte slice.
+// It queries the serialized bytes directly, so the data may be a memory-mapped file.
+// A graph contains many domain sets, each identified by its root node. Sets share common suffixes of the graph.
+//
+// The layout, in little endian, is:
+//
+//	uint32 node count N, uint32 edge count E
+//	N nodes, 8 bytes each: uint32 first edge, uint16 edge count, uint8 flags, uint8 reserved
+//	E edge labels, 1 byte each, padded to a multiple of 4 bytes
+//	E edge targets, uint32 each
+//
+// Edges of a node are sorted by label.
+type DomainGraph struct {
+	nodeCount uint32
+	nodes     []byte
+	labels    []byte
+	targets   []byte
+}
+
+const (
+	graphFlagFull   = 0x01
+	graphFlagDomain = 0x02
+
+	graphNodeSize = 8
+)
+
+var errInvalidGraph = errors.New("strmatcher: invalid domain graph")
+
+// NewDomainGraph creates a DomainGraph on top of the given serialized data. The data must not be modified afterwards.
+func NewDomainGraph(data []byte) (*DomainGraph, error) {
+	if len(data) < 8 {
+		return nil, errInvalidGraph
+	}
+	nodeCount := uint64(binary.LittleEndian.Uint32(data[0:4]))
+	edgeCount := uint64(binary.LittleEndian.Uint32(data[4:8]))
+	labelSize := (edgeCount + 3) &^ 3
+	if uint64(len(data)) != 8+nodeCount*graphNodeSize+labelSize+edgeCount*4 {
+		return nil, errInvalidGraph
+	}
+
+	g := &DomainGraph{
+		nodeCount: uint32(nodeCount),
+	}
+	data = data[8:]
+	g.nodes, data = data[:nodeCount*graphNodeSize], data[nodeCount*graphNodeSize:]
+	g.labels, data = data[:edgeCount], data[labelSize:]
+	g.targets = data
+
+	for i := uint32(0); i < g.nodeCount; i++ {
+		first, count, _ := g.node(i)
+		if uint64(first)+uint64(count) > edgeCount {
+			return nil, errInvalidGraph
+		}
+	}
+	for i := uint64(0); i < edgeCount; i++ {
+		if binary.LittleEndian.Uint32(g.targets[i*4:]) >= g.nodeCount {
+			return nil, errInvalidGraph
+		}
+	}
+	return g, nil
+}
+
+func (g *DomainGraph) node(id uint32) (first uint32, count uint32, flags byte) {
+	b := g.nodes[id*graphNodeSize : (id+1)*graphNodeSize]
+	return binary.LittleEndian.Uint32(b[0:4]), uint32(binary.LittleEndian.Uint16(b[4:6])), b[6]
+}
+
+func (g *DomainGraph) next(id uint32, label byte) (uint32, bool) {
+	first, count, _ := g.node(id)
+	labels := g.labels[first : first+count]
+	idx := sort.Search(len(labels), func(i int) bool {
+		return labels[i] >= label
+	})
+	if idx == len(labels) || labels[idx] != label {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(g.targets[(first+uint32(idx))*4:]), true
+}
+
+// Match returns true if the domain set of the given root contains a Full pattern equal to the domain,
+// or a Domain pattern that the domain is a sub-domain or itself of.
+func (g *DomainGraph) Match(root uint32, domain string) bool {
+	if root >= g.nodeCount {
+		return false
+	}
+
+	current := root
+	for i := len(domain) - 1; i >= 0; i-- {
+		next, found := g.next(current, domain[i])
+		if !found {
+			return false
+		}
+		current = next
+		if domain[i] == '.' {
+			continue
+		}
+		if i > 0 && domain[i-1] == '.' {
+			if _, _, flags := g.node(current); flags&graphFlagDomain != 0 {
+				return true
+			}
+		}
+	}
+	_, _, flags := g.node(current)
+	return flags&(graphFlagFull|graphFlagDomain) != 0
+}
+
+// Matcher returns a Matcher of the domain set of the given root.
+func (g *DomainGraph) Matcher(root uint32) Matcher {
+	return &graphMatcher{
+		graph: g,
+		root:  root,
+	}
+}
+
+type graphMatcher struct {
+	graph *DomainGraph
+	root  uint32
+}
+
+func (m *graphMatcher) Match(s string) bool {
+	return m.graph.Match(m.root, s)
+}
+
+type graphBuilderNode struct {
+	flags byte
+	edges map[byte]*graphBuilderNode
+}
+
+// DomainGraphBuilder builds a serialized DomainGraph.
+// Empty initialization works.
+type DomainGraphBuilder struct {
+	roots []*graphBuilderNode
+}
+
+// NewSet starts a new domain set in the graph, and returns the index of the set.
+func (b *DomainGraphBuilder) NewSet() int {
+	b.roots = append(b.roots, new(graphBuilderNode))
+	return len(b.roots) - 1
+}
+
+// Add adds a pattern into the domain set of the given index. Only Full and Domain patterns are supported.
+func (b *DomainGraphBuilder) Add(set int, t Type, pattern string) error {
+	var flag byte
+	switch t {
+	case Full:
+		flag = graphFlagFull
+	case Domain:
+		flag = graphFlagDomain
+	default:
+		return errors.New("strmatcher: unsupported pattern type in domain graph: " + strconv.Itoa(int(t)))
+	}
+	if set < 0 || set >= len(b.roots) {
+		return errors.New("strmatcher: domain set not found: " + strconv.Itoa(set))
+	}
+
+	current := b.roots[set]
+	for i := len(pattern) - 1; i >= 0; i-- {
+		if current.edges == nil {
+			current.edges = make(map[byte]*graphBuilderNode)
+		}
+		next := current.edges[pattern[i]]
+		if next == nil {
+			next = new(graphBuilderNode)
+			current.edges[pattern[i]] = next
+		}
+		current = next
+	}
+	current.flags |= flag
+	return nil
+}
+
+// Build minimizes and serializes the graph. It returns the serialized data, and the root node of each domain set by index.
+func (b *DomainGraphBuilder) Build() ([]byte, []uint32) {
+	type edge struct {
+		label  byte
+		target uint32
+	}
+	type node struct {
+		flags byte
+		edges []edge
+	}
+
+	var nodes []node
+	edgeCount := 0
+	registry := make(map[string]uint32)
+
+	// Nodes with equal flags and equal outgoing edges are merged. Children are always registered before their parents.
+	var register func(n *graphBuilderNode) uint32
+	register = func(n *graphBuilderNode) uint32 {
+		edges := make([]edge, 0, len(n.edges))
+		for label, child := range n.edges {
+			edges = append(edges, edge{label: label, target: register(child)})
+		}
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].label < edges[j].label
+		})
+
+		var key strings.Builder
+		key.WriteByte(n.flags)
+		for _, e := range edges {
+			key.WriteByte(e.label)
+			key.WriteString(strconv.FormatUint(uint64(e.target), 36))
+			key.WriteByte(',')
+		}
+		if id, found := registry[key.String()]; found {
+			return id
+		}
+
+		id := uint32(len(nodes))
+		nodes = append(nodes, node{flags: n.flags, edges: edges})
+		edgeCount += len(edges)
+		registry[key.String()] = id
+		return id
+	}
+
+	roots := make([]uint32, 0, len(b.roots))
+	for _, r := range b.roots {
+		roots = append(roots, register(r))
+	}
+
+	labelSize := (edgeCount + 3) &^ 3
+	data := make([]byte, 8+len(nodes)*graphNodeSize+labelSize+edgeCount*4)
+	binary.LittleEndian.PutUint32(data[0:4], uint32(len(nodes)))
+	binary.LittleEndian.PutUint32(data[4:8], uint32(edgeCount))
+
+	nodeData := data[8:]
+	labels := nodeData[len(nodes)*graphNodeSize:]
+	targets := labels[labelSize:]
+	first := 0
+	for i, n := range nodes {
+		b := nodeData[i*graphNodeSize:]
+		binary.LittleEndian.PutUint32(b[0:4], uint32(first))
+		binary.LittleEndian.PutUint16(b[4:6], uint16(len(n.edges)))
+		b[6] = n.flags
+		for _, e := range n.edges {
+			labels[first] = e.label
+			binary.LittleEndian.PutUint32(targets[first*4:], e.target)
+			first++
+		}
+	}
+
+	return data, roots
+}
+
+// Walk calls f with each pattern in the domain set of the given root, until f returns false.
+func (g *DomainGraph) Walk(root uint32, f func(t Type, pattern string) bool) {
+	if root >= g.nodeCount {
+		return
+	}
+	g.walk(root, nil, f)
+}
+
+func (g *DomainGraph) walk(id uint32, reversed []byte, f func(t Type, pattern string) bool) bool {
+	first, count, flags := g.node(id)
+	if flags != 0 && len(reversed) > 0 {
+		pattern := make([]byte, len(reversed))
+		for i, c := range reversed {
+			pattern[len(reversed)-1-i] = c
+		}
+		if flags&graphFlagDomain != 0 {
+			if !f(Domain, string(pattern)) {
+				return false
+			}
+		} else if !f(Full, string(pattern)) {
+			return false
+		}
+	}
+	for i := first; i < first+count; i++ {
+		target := binary.LittleEndian.Uint32(g.targets[i*4:])
+		if !g.walk(target, append(reversed, g.labels[i]), f) {
+			return false
+		}
+	}
+	return true
+}
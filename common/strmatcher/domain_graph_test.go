@@ -0,0 +1,72 @@
+package strmatcher_test
+
+import (
+	"testing"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/common/strmatcher"
+)
+
+func TestDomainGraph(t *testing.T) {
+	b := new(DomainGraphBuilder)
+	s1 := b.NewSet()
+	common.Must(b.Add(s1, Domain, "v2ray.com"))
+	common.Must(b.Add(s1, Domain, "google.com"))
+	common.Must(b.Add(s1, Full, "x.a.com"))
+	s2 := b.NewSet()
+	common.Must(b.Add(s2, Domain, "google.com"))
+	common.Must(b.Add(s2, Full, "a.b.com"))
+	common.Must(b.Add(s2, Domain, "c.a.b.com"))
+	if err := b.Add(s2, Regex, "v2ray"); err == nil {
+		t.Error("expect error for regex pattern, but nil")
+	}
+
+	data, roots := b.Build()
+	g, err := NewDomainGraph(data)
+	common.Must(err)
+
+	testCases := []struct {
+		Set    int
+		Domain string
+		Result bool
+	}{
+		{s1, "v2ray.com", true},
+		{s1, "www.v2ray.com", true},
+		{s1, "xv2ray.com", false},
+		{s1, "x.a.com", true},
+		{s1, "y.x.a.com", false},
+		{s1, "a.com", false},
+		{s1, "", false},
+		{s1, "com", false},
+		{s2, "google.com", true},
+		{s2, "mail.google.com", true},
+		{s2, "a.b.com", true},
+		{s2, "x.a.b.com", false},
+		{s2, "c.a.b.com", true},
+		{s2, "d.c.a.b.com", true},
+		{s2, "v2ray.com", false},
+	}
+	for _, testCase := range testCases {
+		m := g.Matcher(roots[testCase.Set])
+		if r := m.Match(testCase.Domain); r != testCase.Result {
+			t.Error("set ", testCase.Set, " failed to match domain: ", testCase.Domain, ", expect ", testCase.Result, ", but got ", r)
+		}
+	}
+}
+
+func TestDomainGraphMinimized(t *testing.T) {
+	b := new(DomainGraphBuilder)
+	s1 := b.NewSet()
+	common.Must(b.Add(s1, Domain, "a.example.com"))
+	s2 := b.NewSet()
+	common.Must(b.Add(s2, Domain, "a.example.com"))
+
+	data, roots := b.Build()
+	if roots[0] != roots[1] {
+		t.Error("expect equal sets to share the same root, but got ", roots)
+	}
+
+	if _, err := NewDomainGraph(data[:len(data)-1]); err == nil {
+		t.Error("expect error for truncated data, but nil")
+	}
+}
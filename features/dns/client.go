@@ -32,6 +32,61 @@ type IPv6Lookup interface {
 	LookupIPv6(domain string) ([]net.IP, error)
 }
 
+// ClientLookup is an optional feature for querying IP addresses on behalf of a client. Name servers may be told the
+// subnet of the client in EDNS Client Subnet, so that answers are close to the client.
+//
+// v2ray:api:beta
+type ClientLookup interface {
+	LookupIPv4ForClient(domain string, clientIP net.IP) ([]net.IP, error)
+	LookupIPv6ForClient(domain string, clientIP net.IP) ([]net.IP, error)
+}
+
+// CachedLookup is an optional feature for querying IP addresses from DNS cache only. Answers are cached as long as
+// their TTL.
+//
+// v2ray:api:beta
+type CachedLookup interface {
+	// LookupCachedIP returns IP addresses of the given domain that were resolved before and haven't expired.
+	// It never sends DNS queries. The second return value is false if no such address exists.
+	LookupCachedIP(domain string) ([]net.IP, bool)
+}
+
+// Record is a resource record in the answer of a DNS query.
+//
+// v2ray:api:beta
+type Record struct {
+	// Name is the owner name of the record, in fully qualified form.
+	Name string
+	Type uint16
+	TTL  uint32
+	// Data is RDATA of the record in wire format. Domain names in it are not compressed.
+	Data []byte
+}
+
+// RecordLookup is an optional feature for querying DNS records of any type.
+//
+// v2ray:api:beta
+type RecordLookup interface {
+	// LookupRecords returns records in the answer of querying the given type for the domain. The answer may
+	// contain records of other types, such as CNAME. It returns ErrEmptyResponse if the answer is empty.
+	LookupRecords(domain string, recordType uint16) ([]Record, error)
+}
+
+// FakeDNSEngine is an optional feature for handing out fake IPs for domains, and mapping them back to domains.
+//
+// v2ray:api:beta
+type FakeDNSEngine interface {
+	// IsFakeDNSEnabled returns true if fake IPs are handed out.
+	IsFakeDNSEnabled() bool
+	// GetFakeIPForDomain returns a fake IP of the given family for the domain, allocating one if necessary.
+	// It returns nil if fake IPs of the family are not enabled.
+	GetFakeIPForDomain(domain string, ipv6 bool) net.IP
+	// GetDomainFromFakeIP returns the domain that the fake IP is handed out for, or an empty string if there is none.
+	GetDomainFromFakeIP(ip net.IP) string
+	// IsFakeIP returns true if the IP is in a fake IP pool, whether or not it is handed out.
+	IsFakeIP(ip net.IP) bool
+}
+
 // ClientType returns the type of Client interface. Can be used for implementing common.HasType.
 //
 // v2ray:api:beta
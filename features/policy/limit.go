@@ -0,0 +1,42 @@
+package policy
+
+import (
+	"context"
+
+	"v2ray.com/core/common/buf"
+)
+
+// Limiter limits bandwidth of a session. It holds a connection of the user and the inbound of the session until it
+// is closed.
+type Limiter interface {
+	// Uplink returns the RateLimiter of uplink traffic, or nil if it is not limited.
+	Uplink() buf.RateLimiter
+	// Downlink returns the RateLimiter of downlink traffic, or nil if it is not limited.
+	Downlink() buf.RateLimiter
+	// Close releases the connection of the session.
+	Close() error
+}
+
+// LimitManager is an optional interface of Manager that limits sessions by policies of their users and inbounds.
+//
+// v2ray:api:beta
+type LimitManager interface {
+	// Limit returns the Limiter of the session in ctx, whose user is at the level. It returns an error if the user or
+	// the inbound of the session has reached its connection limit.
+	Limit(ctx context.Context, level uint32) (Limiter, error)
+}
+
+type noLimiter struct{}
+
+func (noLimiter) Uplink() buf.RateLimiter   { return nil }
+func (noLimiter) Downlink() buf.RateLimiter { return nil }
+func (noLimiter) Close() error              { return nil }
+
+// LimitSession returns the Limiter of the session in ctx, whose user is at the level. The Limiter limits nothing if
+// the Manager is not a LimitManager.
+func LimitSession(ctx context.Context, m Manager, level uint32) (Limiter, error) {
+	if lm, ok := m.(LimitManager); ok {
+		return lm.Limit(ctx, level)
+	}
+	return noLimiter{}, nil
+}
@@ -27,6 +27,10 @@ type Stats struct {
 	UserUplink bool
 	// Whether or not to enable stat counter for user downlink traffic.
 	UserDownlink bool
+	// Whether or not to enable stat gauge for active connections of users.
+	UserConnection bool
+	// Whether or not to enable stat counter for time throttled by rate limits of users.
+	UserThrottle bool
 }
 
 // Buffer contains settings for internal buffer.
@@ -35,18 +39,40 @@ type Buffer struct {
 	PerConnection int32
 }
 
+// Limit contains limits of bandwidth and connections.
+type Limit struct {
+	// Rate of uplink traffic, in bytes per second. 0 for unlimited.
+	UplinkRate int64
+	// Rate of downlink traffic, in bytes per second. 0 for unlimited.
+	DownlinkRate int64
+	// Max number of concurrent connections. 0 for unlimited.
+	Connections uint32
+}
+
 // SystemStats contains stat policy settings on system level.
 type SystemStats struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
 	InboundUplink bool
 	// Whether or not to enable stat counter for downlink traffic in inbound handlers.
 	InboundDownlink bool
+	// Whether or not to enable stat gauge for active connections in inbound handlers.
+	InboundConnection bool
+	// Whether or not to enable connection stats in outbound handlers.
+	OutboundConnection bool
+	// Whether or not to enable stat counter for uplink traffic in outbound handlers.
+	OutboundUplink bool
+	// Whether or not to enable stat counter for downlink traffic in outbound handlers.
+	OutboundDownlink bool
+	// Whether or not to enable stat counter for time throttled by rate limits of inbound handlers.
+	InboundThrottle bool
 }
 
 // System contains policy settings at system level.
 type System struct {
 	Stats  SystemStats
 	Buffer Buffer
+	// Limits of each inbound handler.
+	InboundLimit Limit
 }
 
 // Session is session based settings for controlling V2Ray requests. It contains various settings (or limits) that may differ for different users in the context.
@@ -54,6 +80,8 @@ type Session struct {
 	Timeouts Timeout // Timeout settings
 	Stats    Stats
 	Buffer   Buffer
+	// Limits of each user.
+	Limit Limit
 }
 
 // Manager is a feature that provides Policy for the given user by its id or level.
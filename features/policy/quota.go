@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"time"
+
+	"v2ray.com/core/common/protocol"
+)
+
+// QuotaPeriod is the period that usages of traffic quotas are reset after.
+type QuotaPeriod int32
+
+const (
+	// QuotaDay resets usages at 00:00 everyday, in local time.
+	QuotaDay QuotaPeriod = iota
+	// QuotaWeek resets usages at 00:00 on every Monday, in local time.
+	QuotaWeek
+	// QuotaMonth resets usages at 00:00 on the first day of every month, in local time.
+	QuotaMonth
+)
+
+func (p QuotaPeriod) String() string {
+	switch p {
+	case QuotaWeek:
+		return "week"
+	case QuotaMonth:
+		return "month"
+	default:
+		return "day"
+	}
+}
+
+// Start returns the start of the period that t is in.
+func (p QuotaPeriod) Start(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch p {
+	case QuotaWeek:
+		// Weeks start on Monday.
+		return time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+	case QuotaMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next returns the start of the period after the one that starts at start.
+func (p QuotaPeriod) Next(start time.Time) time.Time {
+	switch p {
+	case QuotaWeek:
+		return start.AddDate(0, 0, 7)
+	case QuotaMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Quota is a traffic quota of a user.
+type Quota struct {
+	// Traffic in bytes allowed in each period, uplink and downlink combined.
+	Bytes  int64
+	Period QuotaPeriod
+	// Tag of the outbound handler that connections are routed to when the quota is exceeded. Connections are
+	// rejected if empty.
+	ExceededTag string
+}
+
+// QuotaUsage is the usage of the quota of a user.
+type QuotaUsage struct {
+	Email string
+	Quota Quota
+	// Used is the traffic in bytes used in the current period.
+	Used int64
+	// Reset is the time that Used is reset at.
+	Reset time.Time
+}
+
+// Exceeded returns whether the quota is used up.
+func (u QuotaUsage) Exceeded() bool {
+	return u.Used >= u.Quota.Bytes
+}
+
+// QuotaCounter counts traffic of a user against its quota.
+type QuotaCounter interface {
+	// Add adds n bytes into the usage of the current period.
+	Add(n int64)
+	// Usage returns the usage of the current period.
+	Usage() QuotaUsage
+}
+
+// QuotaManager is an optional interface of Manager that tracks traffic quotas of users.
+//
+// v2ray:api:beta
+type QuotaManager interface {
+	// QuotaForUser returns the QuotaCounter of the user, or nil if the user has no quota.
+	QuotaForUser(user *protocol.MemoryUser) QuotaCounter
+	// QuotaUsages returns usages of users with quotas, that have connected or been saved.
+	QuotaUsages() []QuotaUsage
+	// ResetQuota resets the usage of the user in the current period.
+	ResetQuota(email string) error
+}
+
+// QuotaForUser returns the QuotaCounter of the user, or nil if the user has no quota or the Manager is not a
+// QuotaManager.
+func QuotaForUser(m Manager, user *protocol.MemoryUser) QuotaCounter {
+	if qm, ok := m.(QuotaManager); ok {
+		return qm.QuotaForUser(user)
+	}
+	return nil
+}
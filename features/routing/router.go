@@ -17,6 +17,20 @@ type Router interface {
 	PickRoute(ctx context.Context) (string, error)
 }
 
+// TrafficCounter counts traffic of a user for routing.
+type TrafficCounter interface {
+	// Add counts n bytes of traffic.
+	Add(n int64)
+}
+
+// TrafficRecorder is an optional interface of Router, for routers with rules on traffic of users. The dispatcher
+// counts traffic of users into their TrafficCounters.
+type TrafficRecorder interface {
+	// TrafficCounter returns the counter of traffic of the user with the email, or nil if traffic of the user is not
+	// needed.
+	TrafficCounter(email string) TrafficCounter
+}
+
 // RouterType return the type of Router interface. Can be used to implement common.HasType.
 //
 // v2ray:api:stable
@@ -16,6 +16,66 @@ type Counter interface {
 	Add(int64) int64
 }
 
+// Gauge is the interface for stats that go up and down, such as the number of active connections. Unlike counters,
+// gauges are not reset when queried.
+type Gauge interface {
+	// Value is the current value of the gauge.
+	Value() int64
+	// Add adds a value, which may be negative, to the gauge, and returns the new value.
+	Add(int64) int64
+}
+
+// Histogram is the interface for stats of observed values in buckets, such as connection durations.
+type Histogram interface {
+	// Observe adds a value to the bucket it falls in.
+	Observe(float64)
+	// Snapshot returns the current buckets of the histogram.
+	Snapshot() HistogramSnapshot
+}
+
+// HistogramSnapshot is a copy of buckets of a Histogram.
+type HistogramSnapshot struct {
+	// Bounds are the inclusive upper bounds of buckets, in increasing order.
+	Bounds []float64
+	// Counts are the number of values in each bucket. The last one is for values greater than all bounds.
+	Counts []uint64
+	// Count is the number of all values.
+	Count uint64
+	// Sum is the sum of all values.
+	Sum float64
+}
+
+var (
+	// DurationBounds are bucket bounds in seconds for durations of connections.
+	DurationBounds = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600}
+	// LatencyBounds are bucket bounds in seconds for latencies, such as dialing connections.
+	LatencyBounds = []float64{0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+)
+
+// GaugeManager is implemented by Managers that support gauges.
+type GaugeManager interface {
+	// RegisterGauge registers a new gauge to the manager. The identifier string must not be empty, and unique among
+	// other stats.
+	RegisterGauge(string) (Gauge, error)
+	// GetGauge returns a gauge by its identifier.
+	GetGauge(string) Gauge
+	// GetOrRegisterGauge returns the gauge of the identifier, registering it if not exist. Concurrent calls with the
+	// same identifier return the same gauge.
+	GetOrRegisterGauge(string) (Gauge, error)
+}
+
+// HistogramManager is implemented by Managers that support histograms.
+type HistogramManager interface {
+	// RegisterHistogram registers a new histogram with bucket bounds to the manager. The identifier string must not
+	// be empty, and unique among other stats.
+	RegisterHistogram(name string, bounds []float64) (Histogram, error)
+	// GetHistogram returns a histogram by its identifier.
+	GetHistogram(string) Histogram
+	// GetOrRegisterHistogram returns the histogram of the identifier, registering it with the bounds if not exist.
+	// Concurrent calls with the same identifier return the same histogram.
+	GetOrRegisterHistogram(name string, bounds []float64) (Histogram, error)
+}
+
 // Manager is the interface for stats manager.
 //
 // v2ray:api:stable
@@ -35,7 +95,35 @@ func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
 		return counter, nil
 	}
 
-	return m.RegisterCounter(name)
+	counter, err := m.RegisterCounter(name)
+	if err != nil {
+		// The counter may be registered concurrently.
+		if counter := m.GetCounter(name); counter != nil {
+			return counter, nil
+		}
+		return nil, err
+	}
+	return counter, nil
+}
+
+// GetOrRegisterGauge tries to get the Gauge first. If not exist, it then tries to create a new gauge. It returns an
+// error if the manager doesn't support gauges.
+func GetOrRegisterGauge(m Manager, name string) (Gauge, error) {
+	gm, ok := m.(GaugeManager)
+	if !ok {
+		return nil, newError("gauge is not supported")
+	}
+	return gm.GetOrRegisterGauge(name)
+}
+
+// GetOrRegisterHistogram tries to get the Histogram first. If not exist, it then tries to create a new histogram with
+// the bounds. It returns an error if the manager doesn't support histograms.
+func GetOrRegisterHistogram(m Manager, name string, bounds []float64) (Histogram, error) {
+	hm, ok := m.(HistogramManager)
+	if !ok {
+		return nil, newError("histogram is not supported")
+	}
+	return hm.GetOrRegisterHistogram(name, bounds)
 }
 
 // ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
@@ -5,7 +5,9 @@ import (
 
 	"v2ray.com/core/app/commander"
 	loggerservice "v2ray.com/core/app/log/command"
+	policyservice "v2ray.com/core/app/policy/command"
 	handlerservice "v2ray.com/core/app/proxyman/command"
+	routingservice "v2ray.com/core/app/router/command"
 	statsservice "v2ray.com/core/app/stats/command"
 	"v2ray.com/core/common/serial"
 )
@@ -29,6 +31,10 @@ func (c *ApiConfig) Build() (*commander.Config, error) {
 			services = append(services, serial.ToTypedMessage(&loggerservice.Config{}))
 		case "statsservice":
 			services = append(services, serial.ToTypedMessage(&statsservice.Config{}))
+		case "routingservice":
+			services = append(services, serial.ToTypedMessage(&routingservice.Config{}))
+		case "policyservice":
+			services = append(services, serial.ToTypedMessage(&policyservice.Config{}))
 		}
 	}
 
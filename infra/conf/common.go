@@ -3,6 +3,7 @@ package conf
 import (
 	"encoding/json"
 	"os"
+	"strconv"
 	"strings"
 
 	"v2ray.com/core/common/net"
@@ -226,6 +227,54 @@ func (list *PortList) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ByteSize is a number of bytes, written either as a plain number or as a string with unit, such as "50GB".
+// Units are powers of 1024.
+type ByteSize uint64
+
+var byteSizeUnits = []struct {
+	Suffix string
+	Scale  uint64
+}{
+	{"TB", 1 << 40},
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"T", 1 << 40},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// UnmarshalJSON implements encoding/json.Unmarshaler.UnmarshalJSON
+func (v *ByteSize) UnmarshalJSON(data []byte) error {
+	var number uint64
+	if err := json.Unmarshal(data, &number); err == nil {
+		*v = ByteSize(number)
+		return nil
+	}
+
+	var rawStr string
+	if err := json.Unmarshal(data, &rawStr); err != nil {
+		return newError("invalid byte size: ", string(data)).Base(err)
+	}
+	str := strings.ToUpper(strings.TrimSpace(rawStr))
+	scale := uint64(1)
+	for _, unit := range byteSizeUnits {
+		if strings.HasSuffix(str, unit.Suffix) {
+			str = strings.TrimSpace(str[:len(str)-len(unit.Suffix)])
+			scale = unit.Scale
+			break
+		}
+	}
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil || value < 0 {
+		return newError("invalid byte size: ", rawStr)
+	}
+	*v = ByteSize(value * float64(scale))
+	return nil
+}
+
 type User struct {
 	EmailString string `json:"email"`
 	LevelByte   byte   `json:"level"`
@@ -11,10 +11,12 @@ import (
 )
 
 type NameServerConfig struct {
-	Address   *Address
-	Port      uint16
-	Domains   []string
-	ExpectIPs StringList
+	Address      *Address
+	Port         uint16
+	Domains      []string
+	ExpectIPs    StringList
+	Tag          string
+	ClientSubnet string
 }
 
 func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
@@ -25,16 +27,20 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 	}
 
 	var advanced struct {
-		Address   *Address   `json:"address"`
-		Port      uint16     `json:"port"`
-		Domains   []string   `json:"domains"`
-		ExpectIPs StringList `json:"expectIps"`
+		Address      *Address   `json:"address"`
+		Port         uint16     `json:"port"`
+		Domains      []string   `json:"domains"`
+		ExpectIPs    StringList `json:"expectIps"`
+		Tag          string     `json:"tag"`
+		ClientSubnet string     `json:"clientSubnet"`
 	}
 	if err := json.Unmarshal(data, &advanced); err == nil {
 		c.Address = advanced.Address
 		c.Port = advanced.Port
 		c.Domains = advanced.Domains
 		c.ExpectIPs = advanced.ExpectIPs
+		c.Tag = advanced.Tag
+		c.ClientSubnet = advanced.ClientSubnet
 		return nil
 	}
 
@@ -61,9 +67,70 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		return nil, newError("NameServer address is not specified.")
 	}
 
+	domains, err := buildPriorityDomains(c.Domains)
+	if err != nil {
+		return nil, err
+	}
+
+	geoipList, err := toCidrList(c.ExpectIPs)
+	if err != nil {
+		return nil, newError("invalid ip rule: ", c.ExpectIPs).Base(err)
+	}
+
+	clientSubnet, err := parseClientSubnet(c.ClientSubnet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dns.NameServer{
+		Address: &net.Endpoint{
+			Network: net.Network_UDP,
+			Address: c.Address.Build(),
+			Port:    uint32(c.Port),
+		},
+		PrioritizedDomain: domains,
+		Geoip:             geoipList,
+		ClientSubnet:      clientSubnet,
+	}, nil
+}
+
+// parseClientSubnet parses "off", "source", or a fixed subnet in the form of IP or CIDR. It returns nil if s is empty.
+func parseClientSubnet(s string) (*dns.ClientSubnet, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return nil, nil
+	case "off":
+		return &dns.ClientSubnet{Type: dns.ClientSubnet_Off}, nil
+	case "source":
+		return &dns.ClientSubnet{Type: dns.ClientSubnet_Source}, nil
+	}
+
+	if !strings.Contains(s, "/") {
+		addr := net.ParseAddress(s)
+		if !addr.Family().IsIP() {
+			return nil, newError("invalid client subnet: ", s)
+		}
+		return &dns.ClientSubnet{
+			Type: dns.ClientSubnet_Fixed,
+			Ip:   []byte(addr.IP()),
+		}, nil
+	}
+
+	cidr, err := ParseIP(s)
+	if err != nil {
+		return nil, newError("invalid client subnet: ", s).Base(err)
+	}
+	return &dns.ClientSubnet{
+		Type:   dns.ClientSubnet_Fixed,
+		Ip:     cidr.Ip,
+		Prefix: cidr.Prefix,
+	}, nil
+}
+
+func buildPriorityDomains(rules []string) ([]*dns.NameServer_PriorityDomain, error) {
 	var domains []*dns.NameServer_PriorityDomain
 
-	for _, d := range c.Domains {
+	for _, d := range rules {
 		parsedDomain, err := parseDomainRule(d)
 		if err != nil {
 			return nil, newError("invalid domain rule: ", d).Base(err)
@@ -76,21 +143,42 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 			})
 		}
 	}
+	return domains, nil
+}
 
-	geoipList, err := toCidrList(c.ExpectIPs)
+// DNSRuleConfig is a JSON serializable object for dns.DomainRule. Servers are tags of name servers.
+type DNSRuleConfig struct {
+	Domains  []string   `json:"domains"`
+	Servers  StringList `json:"servers"`
+	Fallback bool       `json:"fallback"`
+}
+
+// Build builds dns.DomainRule with the indices of tagged name servers.
+func (c *DNSRuleConfig) Build(serverIndices map[string]uint32) (*dns.DomainRule, error) {
+	if len(c.Servers) == 0 {
+		return nil, newError("no server in DNS rule")
+	}
+
+	domains, err := buildPriorityDomains(c.Domains)
 	if err != nil {
-		return nil, newError("invalid ip rule: ", c.ExpectIPs).Base(err)
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return nil, newError("no domain in DNS rule")
 	}
 
-	return &dns.NameServer{
-		Address: &net.Endpoint{
-			Network: net.Network_UDP,
-			Address: c.Address.Build(),
-			Port:    uint32(c.Port),
-		},
-		PrioritizedDomain: domains,
-		Geoip:             geoipList,
-	}, nil
+	rule := &dns.DomainRule{
+		Domain:   domains,
+		Fallback: c.Fallback,
+	}
+	for _, tag := range c.Servers {
+		idx, found := serverIndices[tag]
+		if !found {
+			return nil, newError("name server not found: ", tag)
+		}
+		rule.NameServer = append(rule.NameServer, idx)
+	}
+	return rule, nil
 }
 
 var typeMap = map[router.Domain_Type]dns.DomainMatchingType{
@@ -100,12 +188,55 @@ var typeMap = map[router.Domain_Type]dns.DomainMatchingType{
 	router.Domain_Regex:  dns.DomainMatchingType_Regex,
 }
 
+// FakeDNSConfig is a JSON serializable object for dns.FakeDns.
+type FakeDNSConfig struct {
+	IPPool   string `json:"ipPool"`
+	IPv6Pool string `json:"ipv6Pool"`
+	LRUSize  uint32 `json:"lruSize"`
+}
+
+// Build implements Buildable
+func (c *FakeDNSConfig) Build() (*dns.FakeDns, error) {
+	if len(c.IPPool) == 0 && len(c.IPv6Pool) == 0 {
+		return nil, newError("fake DNS requires ipPool or ipv6Pool")
+	}
+	return &dns.FakeDns{
+		IpPool:   c.IPPool,
+		Ipv6Pool: c.IPv6Pool,
+		LruSize:  c.LRUSize,
+	}, nil
+}
+
+// DNSCacheConfig is a JSON serializable object for dns.CacheConfig.
+type DNSCacheConfig struct {
+	Path         string `json:"path"`
+	ServeStale   bool   `json:"serveStale"`
+	MaxStale     uint32 `json:"maxStale"`
+	Prefetch     bool   `json:"prefetch"`
+	PrefetchHits uint32 `json:"prefetchHits"`
+}
+
+// Build implements Buildable
+func (c *DNSCacheConfig) Build() *dns.CacheConfig {
+	return &dns.CacheConfig{
+		Path:         c.Path,
+		ServeStale:   c.ServeStale,
+		MaxStale:     c.MaxStale,
+		Prefetch:     c.Prefetch,
+		PrefetchHits: c.PrefetchHits,
+	}
+}
+
 // DnsConfig is a JSON serializable object for dns.Config.
 type DnsConfig struct {
-	Servers  []*NameServerConfig `json:"servers"`
-	Hosts    map[string]*Address `json:"hosts"`
-	ClientIP *Address            `json:"clientIp"`
-	Tag      string              `json:"tag"`
+	Servers         []*NameServerConfig `json:"servers"`
+	Hosts           map[string]*Address `json:"hosts"`
+	ClientIP        *Address            `json:"clientIp"`
+	Tag             string              `json:"tag"`
+	FakeDNS         *FakeDNSConfig      `json:"fakeDns"`
+	Cache           *DNSCacheConfig     `json:"cache"`
+	ConcurrentQuery bool                `json:"concurrentQuery"`
+	Rules           []*DNSRuleConfig    `json:"rules"`
 }
 
 func getHostMapping(addr *Address) *dns.Config_HostMapping {
@@ -123,7 +254,8 @@ func getHostMapping(addr *Address) *dns.Config_HostMapping {
 // Build implements Buildable
 func (c *DnsConfig) Build() (*dns.Config, error) {
 	config := &dns.Config{
-		Tag: c.Tag,
+		Tag:             c.Tag,
+		ConcurrentQuery: c.ConcurrentQuery,
 	}
 
 	if c.ClientIP != nil {
@@ -133,14 +265,41 @@ func (c *DnsConfig) Build() (*dns.Config, error) {
 		config.ClientIp = []byte(c.ClientIP.IP())
 	}
 
+	if c.FakeDNS != nil {
+		fakeDNS, err := c.FakeDNS.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.FakeDns = fakeDNS
+	}
+
+	if c.Cache != nil {
+		config.Cache = c.Cache.Build()
+	}
+
+	serverIndices := make(map[string]uint32)
 	for _, server := range c.Servers {
 		ns, err := server.Build()
 		if err != nil {
 			return nil, newError("failed to build name server").Base(err)
 		}
+		if len(server.Tag) > 0 {
+			if _, found := serverIndices[server.Tag]; found {
+				return nil, newError("duplicated name server tag: ", server.Tag)
+			}
+			serverIndices[server.Tag] = uint32(len(config.NameServer))
+		}
 		config.NameServer = append(config.NameServer, ns)
 	}
 
+	for _, r := range c.Rules {
+		rule, err := r.Build(serverIndices)
+		if err != nil {
+			return nil, newError("failed to build DNS rule").Base(err)
+		}
+		config.DomainRule = append(config.DomainRule, rule)
+	}
+
 	if c.Hosts != nil && len(c.Hosts) > 0 {
 		domains := make([]string, 0, len(c.Hosts))
 		for domain := range c.Hosts {
@@ -24,3 +24,34 @@ func (c *DnsOutboundConfig) Build() (proto.Message, error) {
 	}
 	return config, nil
 }
+
+// DNSInboundConfig is a JSON serializable object for dns.ServerConfig.
+type DNSInboundConfig struct {
+	NetworkList   *NetworkList `json:"network"`
+	DoH           bool         `json:"doh"`
+	DoHPath       string       `json:"dohPath"`
+	AllowedSource StringList   `json:"allowedSource"`
+}
+
+// Build implements Buildable.
+func (c *DNSInboundConfig) Build() (proto.Message, error) {
+	config := &dns.ServerConfig{
+		Doh:     c.DoH,
+		DohPath: c.DoHPath,
+	}
+	if c.NetworkList != nil {
+		config.Networks = c.NetworkList.Build()
+	}
+	for _, source := range c.AllowedSource {
+		cidr, err := ParseIP(source)
+		if err != nil {
+			return nil, newError("invalid allowed source: ", source).Base(err)
+		}
+		ipNet := &net.IPNet{
+			IP:   net.IP(cidr.Ip),
+			Mask: net.CIDRMask(int(cidr.Prefix), len(cidr.Ip)*8),
+		}
+		config.AllowedSource = append(config.AllowedSource, ipNet.String())
+	}
+	return config, nil
+}
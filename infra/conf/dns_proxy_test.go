@@ -31,3 +31,34 @@ func TestDnsProxyConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestDnsInboundConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(DNSInboundConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"network": "udp",
+				"allowedSource": ["192.168.0.0/16", "fd00::/8"]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{
+				Networks: []net.Network{net.Network_UDP},
+				AllowedSource: []string{"192.168.0.0/16", "fd00::/8"},
+			},
+		},
+		{
+			Input: `{
+				"network": "tcp",
+				"doh": true
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{
+				Networks: []net.Network{net.Network_TCP},
+				Doh:      true,
+			},
+		},
+	})
+}
@@ -126,5 +126,157 @@ func TestDnsConfigParsing(t *testing.T) {
 				ClientIp: []byte{10, 0, 0, 1},
 			},
 		},
+		{
+			Input: `{
+				"fakeDns": {
+					"ipPool": "198.18.0.0/15",
+					"lruSize": 1000
+				}
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				FakeDns: &dns.FakeDns{
+					IpPool:  "198.18.0.0/15",
+					LruSize: 1000,
+				},
+			},
+		},
+		{
+			Input: `{
+				"cache": {
+					"path": "/var/cache/v2ray/dns.cache",
+					"serveStale": true,
+					"prefetch": true,
+					"prefetchHits": 3
+				}
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				Cache: &dns.CacheConfig{
+					Path:         "/var/cache/v2ray/dns.cache",
+					ServeStale:   true,
+					Prefetch:     true,
+					PrefetchHits: 3,
+				},
+			},
+		},
+		{
+			Input: `{
+				"servers": [
+					{"address": "8.8.8.8", "tag": "google"},
+					{"address": "1.1.1.1", "tag": "cloudflare"},
+					"localhost"
+				],
+				"concurrentQuery": true,
+				"rules": [{
+					"domains": ["domain:v2fly.org"],
+					"servers": ["cloudflare", "google"],
+					"fallback": true
+				}]
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				NameServer: []*dns.NameServer{
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{8, 8, 8, 8},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+					},
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{1, 1, 1, 1},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+					},
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Domain{
+									Domain: "localhost",
+								},
+							},
+							Network: net.Network_UDP,
+						},
+					},
+				},
+				ConcurrentQuery: true,
+				DomainRule: []*dns.DomainRule{
+					{
+						Domain: []*dns.NameServer_PriorityDomain{
+							{
+								Type:   dns.DomainMatchingType_Subdomain,
+								Domain: "v2fly.org",
+							},
+						},
+						NameServer: []uint32{1, 0},
+						Fallback:   true,
+					},
+				},
+			},
+		},
+		{
+			Input: `{
+				"servers": [
+					{"address": "8.8.8.8", "clientSubnet": "source"},
+					{"address": "1.1.1.1", "clientSubnet": "1.2.3.0/24"},
+					{"address": "9.9.9.9", "clientSubnet": "off"}
+				]
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				NameServer: []*dns.NameServer{
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{8, 8, 8, 8},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+						ClientSubnet: &dns.ClientSubnet{
+							Type: dns.ClientSubnet_Source,
+						},
+					},
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{1, 1, 1, 1},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+						ClientSubnet: &dns.ClientSubnet{
+							Type:   dns.ClientSubnet_Fixed,
+							Ip:     []byte{1, 2, 3, 0},
+							Prefix: 24,
+						},
+					},
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{9, 9, 9, 9},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+						ClientSubnet: &dns.ClientSubnet{
+							Type: dns.ClientSubnet_Off,
+						},
+					},
+				},
+			},
+		},
 	})
 }
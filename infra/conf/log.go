@@ -1,6 +1,7 @@
 package conf
 
 import (
+	"sort"
 	"strings"
 
 	"v2ray.com/core/app/log"
@@ -15,10 +16,48 @@ func DefaultLogConfig() *log.Config {
 	}
 }
 
+// LogRotationConfig is the rotation of log files.
+type LogRotationConfig struct {
+	MaxSize    int64  `json:"maxSize"`  // MB
+	Interval   int64  `json:"interval"` // hours
+	MaxBackups uint32 `json:"maxBackups"`
+	MaxAge     int64  `json:"maxAge"` // days
+	Compress   bool   `json:"compress"`
+}
+
+func (c *LogRotationConfig) Build() *log.LogRotation {
+	return &log.LogRotation{
+		MaxSize:    c.MaxSize * 1024 * 1024,
+		Interval:   c.Interval * 3600,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     c.MaxAge * 86400,
+		Compress:   c.Compress,
+	}
+}
+
 type LogConfig struct {
-	AccessLog string `json:"access"`
-	ErrorLog  string `json:"error"`
-	LogLevel  string `json:"loglevel"`
+	AccessLog string             `json:"access"`
+	ErrorLog  string             `json:"error"`
+	LogLevel  string             `json:"loglevel"`
+	Format    string             `json:"format"`
+	Rotation  *LogRotationConfig `json:"rotation"`
+	// Levels are levels of packages by path, such as {"proxy/dokodemo": "debug", "transport": "warning"}.
+	Levels map[string]string `json:"levels"`
+}
+
+func parseLogLevel(level string) clog.Severity {
+	switch strings.ToLower(level) {
+	case "debug":
+		return clog.Severity_Debug
+	case "info":
+		return clog.Severity_Info
+	case "error":
+		return clog.Severity_Error
+	case "none":
+		return clog.Severity_Unknown
+	default:
+		return clog.Severity_Warning
+	}
 }
 
 func (v *LogConfig) Build() *log.Config {
@@ -43,6 +82,26 @@ func (v *LogConfig) Build() *log.Config {
 		config.ErrorLogType = log.LogType_File
 	}
 
+	if strings.ToLower(v.Format) == "json" {
+		config.LogFormat = log.LogFormat_JSON
+	}
+
+	if v.Rotation != nil {
+		config.Rotation = v.Rotation.Build()
+	}
+
+	paths := make([]string, 0, len(v.Levels))
+	for path := range v.Levels {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		config.LevelOverride = append(config.LevelOverride, &log.LevelOverride{
+			Path:  path,
+			Level: parseLogLevel(v.Levels[path]),
+		})
+	}
+
 	level := strings.ToLower(v.LogLevel)
 	switch level {
 	case "debug":
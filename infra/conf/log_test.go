@@ -0,0 +1,63 @@
+package conf_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"v2ray.com/core/app/log"
+	clog "v2ray.com/core/common/log"
+	. "v2ray.com/core/infra/conf"
+)
+
+func TestLogConfig(t *testing.T) {
+	parser := func(s string) (proto.Message, error) {
+		config := new(LogConfig)
+		if err := json.Unmarshal([]byte(s), config); err != nil {
+			return nil, err
+		}
+		return config.Build(), nil
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"access": "/var/log/v2ray/access.log",
+				"error": "/var/log/v2ray/error.log",
+				"loglevel": "warning",
+				"format": "json",
+				"rotation": {
+					"maxSize": 100,
+					"interval": 24,
+					"maxBackups": 7,
+					"maxAge": 30,
+					"compress": true
+				},
+				"levels": {
+					"transport": "error",
+					"proxy/dokodemo": "debug"
+				}
+			}`,
+			Parser: parser,
+			Output: &log.Config{
+				ErrorLogType:  log.LogType_File,
+				ErrorLogPath:  "/var/log/v2ray/error.log",
+				ErrorLogLevel: clog.Severity_Warning,
+				AccessLogType: log.LogType_File,
+				AccessLogPath: "/var/log/v2ray/access.log",
+				LogFormat:     log.LogFormat_JSON,
+				Rotation: &log.LogRotation{
+					MaxSize:    100 * 1024 * 1024,
+					Interval:   24 * 3600,
+					MaxBackups: 7,
+					MaxAge:     30 * 86400,
+					Compress:   true,
+				},
+				LevelOverride: []*log.LevelOverride{
+					{Path: "proxy/dokodemo", Level: clog.Severity_Debug},
+					{Path: "transport", Level: clog.Severity_Error},
+				},
+			},
+		},
+	})
+}
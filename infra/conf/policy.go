@@ -1,17 +1,66 @@
 package conf
 
 import (
+	"strings"
+
 	"v2ray.com/core/app/policy"
 )
 
 type Policy struct {
-	Handshake         *uint32 `json:"handshake"`
-	ConnectionIdle    *uint32 `json:"connIdle"`
-	UplinkOnly        *uint32 `json:"uplinkOnly"`
-	DownlinkOnly      *uint32 `json:"downlinkOnly"`
-	StatsUserUplink   bool    `json:"statsUserUplink"`
-	StatsUserDownlink bool    `json:"statsUserDownlink"`
-	BufferSize        *int32  `json:"bufferSize"`
+	Handshake           *uint32 `json:"handshake"`
+	ConnectionIdle      *uint32 `json:"connIdle"`
+	UplinkOnly          *uint32 `json:"uplinkOnly"`
+	DownlinkOnly        *uint32 `json:"downlinkOnly"`
+	StatsUserUplink     bool    `json:"statsUserUplink"`
+	StatsUserDownlink   bool    `json:"statsUserDownlink"`
+	StatsUserConnection bool    `json:"statsUserConnection"`
+	StatsUserThrottle   bool    `json:"statsUserThrottle"`
+	BufferSize          *int32  `json:"bufferSize"`
+	UplinkRate          uint32  `json:"uplinkRate"`
+	DownlinkRate        uint32  `json:"downlinkRate"`
+	ConnectionLimit     uint32  `json:"connectionLimit"`
+	Quota               *Quota  `json:"quota"`
+}
+
+// Quota is a traffic quota of users.
+type Quota struct {
+	// Traffic in MB.
+	Traffic     uint64 `json:"traffic"`
+	Period      string `json:"period"`
+	ExceededTag string `json:"exceededTag"`
+}
+
+func (q *Quota) Build() (*policy.Quota, error) {
+	if q.Traffic == 0 {
+		return nil, newError("traffic of quota is not specified")
+	}
+	quota := &policy.Quota{
+		Bytes:       q.Traffic * 1024 * 1024,
+		ExceededTag: q.ExceededTag,
+	}
+	switch strings.ToLower(q.Period) {
+	case "", "day":
+		quota.Period = policy.Quota_Day
+	case "week":
+		quota.Period = policy.Quota_Week
+	case "month":
+		quota.Period = policy.Quota_Month
+	default:
+		return nil, newError("unknown period of quota: ", q.Period)
+	}
+	return quota, nil
+}
+
+// buildLimit builds a Limit with rates in KB per second.
+func buildLimit(uplinkRate, downlinkRate, connection uint32) *policy.Limit {
+	if uplinkRate == 0 && downlinkRate == 0 && connection == 0 {
+		return nil
+	}
+	return &policy.Limit{
+		UplinkRate:   uint64(uplinkRate) * 1024,
+		DownlinkRate: uint64(downlinkRate) * 1024,
+		Connection:   connection,
+	}
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -32,9 +81,20 @@ func (t *Policy) Build() (*policy.Policy, error) {
 	p := &policy.Policy{
 		Timeout: config,
 		Stats: &policy.Policy_Stats{
-			UserUplink:   t.StatsUserUplink,
-			UserDownlink: t.StatsUserDownlink,
+			UserUplink:     t.StatsUserUplink,
+			UserDownlink:   t.StatsUserDownlink,
+			UserConnection: t.StatsUserConnection,
+			UserThrottle:   t.StatsUserThrottle,
 		},
+		Limit: buildLimit(t.UplinkRate, t.DownlinkRate, t.ConnectionLimit),
+	}
+
+	if t.Quota != nil {
+		quota, err := t.Quota.Build()
+		if err != nil {
+			return nil, err
+		}
+		p.Quota = quota
 	}
 
 	if t.BufferSize != nil {
@@ -51,22 +111,38 @@ func (t *Policy) Build() (*policy.Policy, error) {
 }
 
 type SystemPolicy struct {
-	StatsInboundUplink   bool `json:"statsInboundUplink"`
-	StatsInboundDownlink bool `json:"statsInboundDownlink"`
+	StatsInboundUplink      bool   `json:"statsInboundUplink"`
+	StatsInboundDownlink    bool   `json:"statsInboundDownlink"`
+	StatsInboundConnection  bool   `json:"statsInboundConnection"`
+	StatsInboundThrottle    bool   `json:"statsInboundThrottle"`
+	StatsOutboundConnection bool   `json:"statsOutboundConnection"`
+	StatsOutboundUplink     bool   `json:"statsOutboundUplink"`
+	StatsOutboundDownlink   bool   `json:"statsOutboundDownlink"`
+	InboundUplinkRate       uint32 `json:"inboundUplinkRate"`
+	InboundDownlinkRate     uint32 `json:"inboundDownlinkRate"`
+	InboundConnectionLimit  uint32 `json:"inboundConnectionLimit"`
 }
 
 func (p *SystemPolicy) Build() (*policy.SystemPolicy, error) {
 	return &policy.SystemPolicy{
 		Stats: &policy.SystemPolicy_Stats{
-			InboundUplink:   p.StatsInboundUplink,
-			InboundDownlink: p.StatsInboundDownlink,
+			InboundUplink:      p.StatsInboundUplink,
+			InboundDownlink:    p.StatsInboundDownlink,
+			InboundConnection:  p.StatsInboundConnection,
+			OutboundConnection: p.StatsOutboundConnection,
+			OutboundUplink:     p.StatsOutboundUplink,
+			OutboundDownlink:   p.StatsOutboundDownlink,
+			InboundThrottle:    p.StatsInboundThrottle,
 		},
+		InboundLimit: buildLimit(p.InboundUplinkRate, p.InboundDownlinkRate, p.InboundConnectionLimit),
 	}, nil
 }
 
 type PolicyConfig struct {
-	Levels map[uint32]*Policy `json:"levels"`
-	System *SystemPolicy      `json:"system"`
+	Levels    map[uint32]*Policy `json:"levels"`
+	System    *SystemPolicy      `json:"system"`
+	Users     map[string]*Quota  `json:"users"`
+	QuotaFile string             `json:"quotaFile"`
 }
 
 func (c *PolicyConfig) Build() (*policy.Config, error) {
@@ -81,7 +157,22 @@ func (c *PolicyConfig) Build() (*policy.Config, error) {
 		}
 	}
 	config := &policy.Config{
-		Level: levels,
+		Level:     levels,
+		QuotaPath: c.QuotaFile,
+	}
+
+	if len(c.Users) > 0 {
+		config.UserQuota = make(map[string]*policy.Quota)
+		for email, q := range c.Users {
+			if q == nil {
+				continue
+			}
+			quota, err := q.Build()
+			if err != nil {
+				return nil, newError("invalid quota of user ", email).Base(err)
+			}
+			config.UserQuota[email] = quota
+		}
 	}
 
 	if c.System != nil {
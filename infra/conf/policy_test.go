@@ -3,6 +3,7 @@ package conf_test
 import (
 	"testing"
 
+	"v2ray.com/core/app/policy"
 	"v2ray.com/core/common"
 	. "v2ray.com/core/infra/conf"
 )
@@ -38,3 +39,69 @@ func TestBufferSize(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectionStatsPolicy(t *testing.T) {
+	p, err := (&Policy{StatsUserConnection: true}).Build()
+	common.Must(err)
+	if !p.Stats.UserConnection {
+		t.Error("expect user connection stats enabled")
+	}
+
+	sp, err := (&SystemPolicy{StatsInboundConnection: true, StatsOutboundConnection: true}).Build()
+	common.Must(err)
+	if !sp.Stats.InboundConnection || !sp.Stats.OutboundConnection {
+		t.Error("expect connection stats enabled, but got ", sp.Stats)
+	}
+}
+
+func TestOutboundTrafficStatsPolicy(t *testing.T) {
+	sp, err := (&SystemPolicy{StatsOutboundUplink: true, StatsOutboundDownlink: true}).Build()
+	common.Must(err)
+	if !sp.Stats.OutboundUplink || !sp.Stats.OutboundDownlink {
+		t.Error("expect outbound traffic stats enabled, but got ", sp.Stats)
+	}
+}
+
+func TestLimitPolicy(t *testing.T) {
+	p, err := (&Policy{UplinkRate: 100, DownlinkRate: 200, ConnectionLimit: 4, StatsUserThrottle: true}).Build()
+	common.Must(err)
+	if p.Limit.UplinkRate != 100*1024 || p.Limit.DownlinkRate != 200*1024 || p.Limit.Connection != 4 {
+		t.Error("unexpected limit: ", p.Limit)
+	}
+	if !p.Stats.UserThrottle {
+		t.Error("expect user throttle stats enabled")
+	}
+
+	p, err = (&Policy{}).Build()
+	common.Must(err)
+	if p.Limit != nil {
+		t.Error("expect no limit, but got ", p.Limit)
+	}
+
+	sp, err := (&SystemPolicy{InboundDownlinkRate: 1024, StatsInboundThrottle: true}).Build()
+	common.Must(err)
+	if sp.InboundLimit.DownlinkRate != 1024*1024 || sp.InboundLimit.UplinkRate != 0 || !sp.Stats.InboundThrottle {
+		t.Error("unexpected system policy: ", sp)
+	}
+}
+
+func TestQuotaPolicy(t *testing.T) {
+	p, err := (&Policy{Quota: &Quota{Traffic: 10, Period: "month", ExceededTag: "blocked"}}).Build()
+	common.Must(err)
+	if p.Quota.Bytes != 10*1024*1024 || p.Quota.Period != policy.Quota_Month || p.Quota.ExceededTag != "blocked" {
+		t.Error("unexpected quota: ", p.Quota)
+	}
+
+	if _, err := (&Policy{Quota: &Quota{Traffic: 10, Period: "year"}}).Build(); err == nil {
+		t.Error("expect error for unknown period")
+	}
+
+	c, err := (&PolicyConfig{
+		Users:     map[string]*Quota{"love@v2ray.com": {Traffic: 1}},
+		QuotaFile: "quota.dat",
+	}).Build()
+	common.Must(err)
+	if q := c.UserQuota["love@v2ray.com"]; q.Bytes != 1024*1024 || q.Period != policy.Quota_Day || c.QuotaPath != "quota.dat" {
+		t.Error("unexpected config: ", c)
+	}
+}
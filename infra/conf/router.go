@@ -7,9 +7,6 @@ import (
 
 	"v2ray.com/core/app/router"
 	"v2ray.com/core/common/net"
-	"v2ray.com/core/common/platform/filesystem"
-
-	"github.com/golang/protobuf/proto"
 )
 
 type RouterRulesConfig struct {
@@ -58,6 +55,8 @@ func (c *RouterConfig) getDomainStrategy() router.Config_DomainStrategy {
 		return router.Config_IpIfNonMatch
 	case "ipondemand":
 		return router.Config_IpOnDemand
+	case "ipifcached":
+		return router.Config_IpIfCached
 	default:
 		return router.Config_AsIs
 	}
@@ -78,6 +77,9 @@ func (c *RouterConfig) Build() (*router.Config, error) {
 		}
 		config.Rule = append(config.Rule, rule)
 	}
+	if err := router.CheckGeoDataReferences(config.Rule); err != nil {
+		return nil, err
+	}
 	for _, rawBalancer := range c.Balancers {
 		balancer, err := rawBalancer.Build()
 		if err != nil {
@@ -92,6 +94,7 @@ type RouterRule struct {
 	Type        string `json:"type"`
 	OutboundTag string `json:"outboundTag"`
 	BalancerTag string `json:"balancerTag"`
+	RuleTag     string `json:"ruleTag"`
 }
 
 func ParseIP(s string) (*router.CIDR, error) {
@@ -147,134 +150,64 @@ func loadGeoIP(country string) ([]*router.CIDR, error) {
 }
 
 func loadIP(filename, country string) ([]*router.CIDR, error) {
-	geoipBytes, err := filesystem.ReadAsset(filename)
-	if err != nil {
-		return nil, newError("failed to open file: ", filename).Base(err)
-	}
-	var geoipList router.GeoIPList
-	if err := proto.Unmarshal(geoipBytes, &geoipList); err != nil {
-		return nil, err
-	}
-
-	for _, geoip := range geoipList.Entry {
-		if geoip.CountryCode == country {
-			return geoip.Cidr, nil
-		}
-	}
-
-	return nil, newError("country not found: " + country)
-}
-
-func loadSite(filename, country string) ([]*router.Domain, error) {
-	geositeBytes, err := filesystem.ReadAsset(filename)
+	geoip, err := router.LoadGeoIP(filename, country)
 	if err != nil {
-		return nil, newError("failed to open file: ", filename).Base(err)
-	}
-	var geositeList router.GeoSiteList
-	if err := proto.Unmarshal(geositeBytes, &geositeList); err != nil {
 		return nil, err
 	}
-
-	for _, site := range geositeList.Entry {
-		if site.CountryCode == country {
-			return site.Domain, nil
-		}
-	}
-
-	return nil, newError("country not found: " + country)
+	return geoip.Cidr, nil
 }
 
-type AttributeMatcher interface {
-	Match(*router.Domain) bool
+func loadGeositeWithAttr(file string, siteWithAttr string) ([]*router.Domain, error) {
+	return router.LoadGeoSite(file, siteWithAttr)
 }
 
-type BooleanMatcher string
-
-func (m BooleanMatcher) Match(domain *router.Domain) bool {
-	for _, attr := range domain.Attribute {
-		if attr.Key == string(m) {
-			return true
-		}
+// parseGeositeReference parses "geosite:code" and "ext:file:code" into a reference to geodata. It returns nil if the domain is in neither form.
+func parseGeositeReference(domain string) (*router.GeoDataReference, error) {
+	if strings.HasPrefix(domain, "geosite:") {
+		return &router.GeoDataReference{
+			File: "geosite.dat",
+			Code: strings.ToUpper(domain[8:]),
+		}, nil
 	}
-	return false
-}
-
-type AttributeList struct {
-	matcher []AttributeMatcher
-}
 
-func (al *AttributeList) Match(domain *router.Domain) bool {
-	for _, matcher := range al.matcher {
-		if !matcher.Match(domain) {
-			return false
+	if strings.HasPrefix(domain, "ext:") {
+		kv := strings.Split(domain[4:], ":")
+		if len(kv) != 2 {
+			return nil, newError("invalid external resource: ", domain)
 		}
+		return &router.GeoDataReference{
+			File: kv[0],
+			Code: kv[1],
+		}, nil
 	}
-	return true
-}
 
-func (al *AttributeList) IsEmpty() bool {
-	return len(al.matcher) == 0
-}
-
-func parseAttrs(attrs []string) *AttributeList {
-	al := new(AttributeList)
-	for _, attr := range attrs {
-		lc := strings.ToLower(attr)
-		al.matcher = append(al.matcher, BooleanMatcher(lc))
-	}
-	return al
+	return nil, nil
 }
 
-func loadGeositeWithAttr(file string, siteWithAttr string) ([]*router.Domain, error) {
-	parts := strings.Split(siteWithAttr, "@")
-	if len(parts) == 0 {
-		return nil, newError("empty site")
-	}
-	country := strings.ToUpper(parts[0])
-	attrs := parseAttrs(parts[1:])
-	domains, err := loadSite(file, country)
-	if err != nil {
-		return nil, err
-	}
-
-	if attrs.IsEmpty() {
-		return domains, nil
+// parseGeoIPReference parses "geoip:code" and "ext:file:code" into a reference to geodata. It returns nil if the IP is in neither form.
+func parseGeoIPReference(ip string) (*router.GeoDataReference, error) {
+	if strings.HasPrefix(ip, "geoip:") {
+		return &router.GeoDataReference{
+			File: "geoip.dat",
+			Code: strings.ToUpper(ip[6:]),
+		}, nil
 	}
 
-	filteredDomains := make([]*router.Domain, 0, len(domains))
-	for _, domain := range domains {
-		if attrs.Match(domain) {
-			filteredDomains = append(filteredDomains, domain)
+	if strings.HasPrefix(ip, "ext:") {
+		kv := strings.Split(ip[4:], ":")
+		if len(kv) != 2 {
+			return nil, newError("invalid external resource: ", ip)
 		}
+		return &router.GeoDataReference{
+			File: kv[0],
+			Code: strings.ToUpper(kv[1]),
+		}, nil
 	}
 
-	return filteredDomains, nil
+	return nil, nil
 }
 
-func parseDomainRule(domain string) ([]*router.Domain, error) {
-	if strings.HasPrefix(domain, "geosite:") {
-		country := strings.ToUpper(domain[8:])
-		domains, err := loadGeositeWithAttr("geosite.dat", country)
-		if err != nil {
-			return nil, newError("failed to load geosite: ", country).Base(err)
-		}
-		return domains, nil
-	}
-
-	if strings.HasPrefix(domain, "ext:") {
-		kv := strings.Split(domain[4:], ":")
-		if len(kv) != 2 {
-			return nil, newError("invalid external resource: ", domain)
-		}
-		filename := kv[0]
-		country := kv[1]
-		domains, err := loadGeositeWithAttr(filename, country)
-		if err != nil {
-			return nil, newError("failed to load external sites: ", country, " from ", filename).Base(err)
-		}
-		return domains, nil
-	}
-
+func parsePlainDomainRule(domain string) *router.Domain {
 	domainRule := new(router.Domain)
 	switch {
 	case strings.HasPrefix(domain, "regexp:"):
@@ -293,63 +226,207 @@ func parseDomainRule(domain string) ([]*router.Domain, error) {
 		domainRule.Type = router.Domain_Plain
 		domainRule.Value = domain
 	}
-	return []*router.Domain{domainRule}, nil
+	return domainRule
 }
 
-func toCidrList(ips StringList) ([]*router.GeoIP, error) {
-	var geoipList []*router.GeoIP
-	var customCidrs []*router.CIDR
-
-	for _, ip := range ips {
-		if strings.HasPrefix(ip, "geoip:") {
-			country := ip[6:]
-			geoip, err := loadGeoIP(strings.ToUpper(country))
-			if err != nil {
-				return nil, newError("failed to load GeoIP: ", country).Base(err)
-			}
-
-			geoipList = append(geoipList, &router.GeoIP{
-				CountryCode: strings.ToUpper(country),
-				Cidr:        geoip,
-			})
-			continue
+func parseDomainRule(domain string) ([]*router.Domain, error) {
+	ref, err := parseGeositeReference(domain)
+	if err != nil {
+		return nil, err
+	}
+	if ref != nil {
+		domains, err := loadGeositeWithAttr(ref.File, ref.Code)
+		if err != nil {
+			return nil, newError("failed to load sites: ", ref.Code, " from ", ref.File).Base(err)
 		}
+		return domains, nil
+	}
 
-		if strings.HasPrefix(ip, "ext:") {
-			kv := strings.Split(ip[4:], ":")
-			if len(kv) != 2 {
-				return nil, newError("invalid external resource: ", ip)
-			}
-
-			filename := kv[0]
-			country := kv[1]
-			geoip, err := loadIP(filename, strings.ToUpper(country))
-			if err != nil {
-				return nil, newError("failed to load IPs: ", country, " from ", filename).Base(err)
-			}
+	return []*router.Domain{parsePlainDomainRule(domain)}, nil
+}
 
-			geoipList = append(geoipList, &router.GeoIP{
-				CountryCode: strings.ToUpper(filename + "_" + country),
-				Cidr:        geoip,
-			})
+// toGeoIPList parses IPs and CIDRs into GeoIP, and geoip entries into references.
+func toGeoIPList(ips StringList) ([]*router.GeoIP, []*router.GeoDataReference, error) {
+	var refs []*router.GeoDataReference
+	var customCidrs []*router.CIDR
 
+	for _, ip := range ips {
+		ref, err := parseGeoIPReference(ip)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ref != nil {
+			refs = append(refs, ref)
 			continue
 		}
 
 		ipRule, err := ParseIP(ip)
 		if err != nil {
-			return nil, newError("invalid IP: ", ip).Base(err)
+			return nil, nil, newError("invalid IP: ", ip).Base(err)
 		}
 		customCidrs = append(customCidrs, ipRule)
 	}
 
+	var geoipList []*router.GeoIP
 	if len(customCidrs) > 0 {
 		geoipList = append(geoipList, &router.GeoIP{
 			Cidr: customCidrs,
 		})
 	}
 
-	return geoipList, nil
+	return geoipList, refs, nil
+}
+
+func toCidrList(ips StringList) ([]*router.GeoIP, error) {
+	customGeoIPs, refs, err := toGeoIPList(ips)
+	if err != nil {
+		return nil, err
+	}
+
+	var geoipList []*router.GeoIP
+	for _, ref := range refs {
+		geoip, err := router.LoadGeoIP(ref.File, ref.Code)
+		if err != nil {
+			return nil, newError("failed to load IPs: ", ref.Code, " from ", ref.File).Base(err)
+		}
+		geoipList = append(geoipList, geoip)
+	}
+
+	return append(geoipList, customGeoIPs...), nil
+}
+
+var weekdayNames = map[string]uint32{
+	"sun": 0, "sunday": 0,
+	"mon": 1, "monday": 1,
+	"tue": 2, "tuesday": 2,
+	"wed": 3, "wednesday": 3,
+	"thu": 4, "thursday": 4,
+	"fri": 5, "friday": 5,
+	"sat": 6, "saturday": 6,
+}
+
+func parseWeekday(s string) (uint32, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if d, found := weekdayNames[s]; found {
+		return d, nil
+	}
+	d, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || d > 6 {
+		return 0, newError("invalid weekday: ", s)
+	}
+	return uint32(d), nil
+}
+
+// parseTimeOfDay parses "HH:MM" or "HH:MM:SS" into seconds since midnight. "24:00" is accepted as the end of a day.
+func parseTimeOfDay(s string) (uint32, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, newError("invalid time: ", s)
+	}
+	var seconds uint32
+	limits := []uint64{24, 59, 59}
+	scales := []uint32{3600, 60, 1}
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || v > limits[i] {
+			return 0, newError("invalid time: ", s)
+		}
+		seconds += uint32(v) * scales[i]
+	}
+	if seconds > 86400 {
+		return 0, newError("invalid time: ", s)
+	}
+	return seconds, nil
+}
+
+type TimeWindowConfig struct {
+	Weekday *StringList `json:"weekday"`
+	Time    string      `json:"time"`
+}
+
+func (c *TimeWindowConfig) Build() (*router.TimeWindow, error) {
+	window := &router.TimeWindow{
+		End: 86400,
+	}
+	if c.Weekday != nil {
+		for _, day := range *c.Weekday {
+			d, err := parseWeekday(day)
+			if err != nil {
+				return nil, err
+			}
+			window.Weekday = append(window.Weekday, d)
+		}
+	}
+	if len(c.Time) > 0 {
+		parts := strings.Split(c.Time, "-")
+		if len(parts) != 2 {
+			return nil, newError("invalid time range: ", c.Time)
+		}
+		start, err := parseTimeOfDay(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		if start == 86400 {
+			return nil, newError("invalid time range: ", c.Time)
+		}
+		window.Start = start
+		window.End = end
+	}
+	return window, nil
+}
+
+type ScheduleConfig struct {
+	Timezone string              `json:"timezone"`
+	Windows  []*TimeWindowConfig `json:"windows"`
+}
+
+func (c *ScheduleConfig) Build() (*router.Schedule, error) {
+	if len(c.Windows) == 0 {
+		return nil, newError("empty schedule")
+	}
+	schedule := &router.Schedule{
+		Timezone: c.Timezone,
+	}
+	for _, w := range c.Windows {
+		window, err := w.Build()
+		if err != nil {
+			return nil, err
+		}
+		schedule.Window = append(schedule.Window, window)
+	}
+	return schedule, nil
+}
+
+type TrafficQuotaConfig struct {
+	Threshold ByteSize `json:"threshold"`
+	Period    string   `json:"period"`
+	Timezone  string   `json:"timezone"`
+}
+
+func (c *TrafficQuotaConfig) Build() (*router.TrafficQuota, error) {
+	quota := &router.TrafficQuota{
+		Threshold: uint64(c.Threshold),
+		Timezone:  c.Timezone,
+	}
+	switch strings.ToLower(c.Period) {
+	case "", "total":
+		quota.Period = router.TrafficQuota_Total
+	case "daily", "day":
+		quota.Period = router.TrafficQuota_Daily
+	case "weekly", "week":
+		quota.Period = router.TrafficQuota_Weekly
+	case "monthly", "month":
+		quota.Period = router.TrafficQuota_Monthly
+	case "policy":
+		quota.Period = router.TrafficQuota_Policy
+	default:
+		return nil, newError("unknown traffic quota period: ", c.Period)
+	}
+	return quota, nil
 }
 
 func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
@@ -364,6 +441,10 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		InboundTag *StringList  `json:"inboundTag"`
 		Protocols  *StringList  `json:"protocol"`
 		Attributes string       `json:"attrs"`
+
+		SourcePort   *PortList           `json:"sourcePort"`
+		Schedule     *ScheduleConfig     `json:"schedule"`
+		TrafficQuota *TrafficQuotaConfig `json:"trafficQuota"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -384,22 +465,29 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		return nil, newError("neither outboundTag nor balancerTag is specified in routing rule")
 	}
 
+	rule.RuleTag = rawFieldRule.RuleTag
+
 	if rawFieldRule.Domain != nil {
 		for _, domain := range *rawFieldRule.Domain {
-			rules, err := parseDomainRule(domain)
+			ref, err := parseGeositeReference(domain)
 			if err != nil {
 				return nil, newError("failed to parse domain rule: ", domain).Base(err)
 			}
-			rule.Domain = append(rule.Domain, rules...)
+			if ref != nil {
+				rule.GeositeRef = append(rule.GeositeRef, ref)
+				continue
+			}
+			rule.Domain = append(rule.Domain, parsePlainDomainRule(domain))
 		}
 	}
 
 	if rawFieldRule.IP != nil {
-		geoipList, err := toCidrList(*rawFieldRule.IP)
+		geoipList, refs, err := toGeoIPList(*rawFieldRule.IP)
 		if err != nil {
 			return nil, err
 		}
 		rule.Geoip = geoipList
+		rule.GeoipRef = refs
 	}
 
 	if rawFieldRule.Port != nil {
@@ -411,11 +499,12 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	}
 
 	if rawFieldRule.SourceIP != nil {
-		geoipList, err := toCidrList(*rawFieldRule.SourceIP)
+		geoipList, refs, err := toGeoIPList(*rawFieldRule.SourceIP)
 		if err != nil {
 			return nil, err
 		}
 		rule.SourceGeoip = geoipList
+		rule.SourceGeoipRef = refs
 	}
 
 	if rawFieldRule.User != nil {
@@ -440,6 +529,26 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.SourcePort != nil {
+		rule.SourcePortList = rawFieldRule.SourcePort.Build()
+	}
+
+	if rawFieldRule.Schedule != nil {
+		schedule, err := rawFieldRule.Schedule.Build()
+		if err != nil {
+			return nil, newError("invalid schedule").Base(err)
+		}
+		rule.Schedule = schedule
+	}
+
+	if rawFieldRule.TrafficQuota != nil {
+		quota, err := rawFieldRule.TrafficQuota.Build()
+		if err != nil {
+			return nil, newError("invalid traffic quota").Base(err)
+		}
+		rule.TrafficQuota = quota
+	}
+
 	return rule, nil
 }
 
@@ -2,16 +2,78 @@ package conf_test
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 
 	"v2ray.com/core/app/router"
+	"v2ray.com/core/common"
 	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/platform"
 	. "v2ray.com/core/infra/conf"
 )
 
+func writeGeoDataAsset(file string, list proto.Message) func() {
+	listBytes, err := proto.Marshal(list)
+	common.Must(err)
+	path := platform.GetAssetLocation(file)
+	common.Must(ioutil.WriteFile(path, listBytes, 0600))
+	return func() {
+		os.Remove(path)
+	}
+}
+
+func writeRouterTestGeoData() func() {
+	cleanups := []func(){
+		writeGeoDataAsset("geosite.dat", &router.GeoSiteList{
+			Entry: []*router.GeoSite{
+				{
+					CountryCode: "CN",
+					Domain: []*router.Domain{
+						{Type: router.Domain_Domain, Value: "ads.example.cn", Attribute: []*router.Domain_Attribute{{Key: "ads"}}},
+					},
+				},
+			},
+		}),
+		writeGeoDataAsset("custom.dat", &router.GeoSiteList{
+			Entry: []*router.GeoSite{
+				{
+					CountryCode: "TEST",
+					Domain: []*router.Domain{
+						{Type: router.Domain_Full, Value: "example.com"},
+					},
+				},
+			},
+		}),
+		writeGeoDataAsset("geoip.dat", &router.GeoIPList{
+			Entry: []*router.GeoIP{
+				{
+					CountryCode: "CN",
+					Cidr:        []*router.CIDR{{Ip: []byte{1, 0, 1, 0}, Prefix: 24}},
+				},
+			},
+		}),
+		writeGeoDataAsset("customip.dat", &router.GeoIPList{
+			Entry: []*router.GeoIP{
+				{
+					CountryCode: "LAN",
+					Cidr:        []*router.CIDR{{Ip: []byte{192, 168, 0, 0}, Prefix: 16}},
+				},
+			},
+		}),
+	}
+	return func() {
+		for _, cleanup := range cleanups {
+			cleanup()
+		}
+	}
+}
+
 func TestRouterConfig(t *testing.T) {
+	defer writeRouterTestGeoData()()
+
 	createParser := func() func(string) (proto.Message, error) {
 		return func(s string) (proto.Message, error) {
 			config := new(RouterConfig)
@@ -260,5 +322,143 @@ func TestRouterConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"rules": [
+					{
+						"type": "field",
+						"ruleTag": "office-hours",
+						"sourcePort": "10000-20000",
+						"schedule": {
+							"timezone": "Asia/Shanghai",
+							"windows": [
+								{"weekday": ["mon", "tue", "5"], "time": "08:00-18:30"},
+								{"time": "22:00-02:00"}
+							]
+						},
+						"outboundTag": "test"
+					},
+					{
+						"type": "field",
+						"domain": ["geosite:cn@ads", "ext:custom.dat:test", "full:v2ray.com"],
+						"ip": ["geoip:cn", "10.0.0.0/8"],
+						"source": ["ext:customip.dat:lan"],
+						"outboundTag": "blocked"
+					},
+					{
+						"type": "field",
+						"user": ["love@v2ray.com"],
+						"trafficQuota": {
+							"threshold": "50GB",
+							"period": "monthly"
+						},
+						"outboundTag": "cheap"
+					},
+					{
+						"type": "field",
+						"trafficQuota": {
+							"period": "policy"
+						},
+						"outboundTag": "cheap"
+					}
+				]
+			}`,
+			Parser: createParser(),
+			Output: &router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						SourcePortList: &net.PortList{
+							Range: []*net.PortRange{
+								{From: 10000, To: 20000},
+							},
+						},
+						Schedule: &router.Schedule{
+							Timezone: "Asia/Shanghai",
+							Window: []*router.TimeWindow{
+								{
+									Weekday: []uint32{1, 2, 5},
+									Start:   8 * 3600,
+									End:     18*3600 + 30*60,
+								},
+								{
+									Start: 22 * 3600,
+									End:   2 * 3600,
+								},
+							},
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "test",
+						},
+						RuleTag: "office-hours",
+					},
+					{
+						Domain: []*router.Domain{
+							{
+								Type:  router.Domain_Full,
+								Value: "v2ray.com",
+							},
+						},
+						GeositeRef: []*router.GeoDataReference{
+							{File: "geosite.dat", Code: "CN@ADS"},
+							{File: "custom.dat", Code: "test"},
+						},
+						Geoip: []*router.GeoIP{
+							{
+								Cidr: []*router.CIDR{
+									{
+										Ip:     []byte{10, 0, 0, 0},
+										Prefix: 8,
+									},
+								},
+							},
+						},
+						GeoipRef: []*router.GeoDataReference{
+							{File: "geoip.dat", Code: "CN"},
+						},
+						SourceGeoipRef: []*router.GeoDataReference{
+							{File: "customip.dat", Code: "LAN"},
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "blocked",
+						},
+					},
+					{
+						UserEmail: []string{"love@v2ray.com"},
+						TrafficQuota: &router.TrafficQuota{
+							Threshold: 50 << 30,
+							Period:    router.TrafficQuota_Monthly,
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "cheap",
+						},
+					},
+					{
+						TrafficQuota: &router.TrafficQuota{
+							Period: router.TrafficQuota_Policy,
+						},
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "cheap",
+						},
+					},
+				},
+			},
+		},
 	})
 }
+
+func TestRouterConfigUnresolvedGeoData(t *testing.T) {
+	defer writeRouterTestGeoData()()
+
+	for _, input := range []string{
+		`{"rules": [{"type": "field", "domain": ["geosite:us"], "outboundTag": "test"}]}`,
+		`{"rules": [{"type": "field", "domain": ["ext:notexist.dat:test"], "outboundTag": "test"}]}`,
+		`{"rules": [{"type": "field", "ip": ["geoip:us"], "outboundTag": "test"}]}`,
+		`{"rules": [{"type": "field", "source": ["ext:customip.dat:wan"], "outboundTag": "test"}]}`,
+	} {
+		config := new(RouterConfig)
+		common.Must(json.Unmarshal([]byte(input), config))
+		if _, err := config.Build(); err == nil {
+			t.Error("expected error for unresolved geodata in ", input)
+		}
+	}
+}
@@ -26,19 +26,43 @@ func cipherFromString(c string) shadowsocks.CipherType {
 		return shadowsocks.CipherType_AES_256_GCM
 	case "chacha20-poly1305", "aead_chacha20_poly1305", "chacha20-ietf-poly1305":
 		return shadowsocks.CipherType_CHACHA20_POLY1305
+	case "2022-blake3-aes-128-gcm":
+		return shadowsocks.CipherType_BLAKE3_AES_128_GCM
+	case "2022-blake3-aes-256-gcm":
+		return shadowsocks.CipherType_BLAKE3_AES_256_GCM
+	case "2022-blake3-chacha20-poly1305":
+		return shadowsocks.CipherType_BLAKE3_CHACHA20_POLY1305
 	default:
 		return shadowsocks.CipherType_UNKNOWN
 	}
 }
 
+func is2022Cipher(c shadowsocks.CipherType) bool {
+	switch c {
+	case shadowsocks.CipherType_BLAKE3_AES_128_GCM, shadowsocks.CipherType_BLAKE3_AES_256_GCM, shadowsocks.CipherType_BLAKE3_CHACHA20_POLY1305:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShadowsocksUserConfig is a user of a Shadowsocks server with multiple users.
+type ShadowsocksUserConfig struct {
+	Cipher   string `json:"method"`
+	Password string `json:"password"`
+	Level    byte   `json:"level"`
+	Email    string `json:"email"`
+}
+
 type ShadowsocksServerConfig struct {
-	Cipher      string       `json:"method"`
-	Password    string       `json:"password"`
-	UDP         bool         `json:"udp"`
-	Level       byte         `json:"level"`
-	Email       string       `json:"email"`
-	OTA         *bool        `json:"ota"`
-	NetworkList *NetworkList `json:"network"`
+	Cipher      string                   `json:"method"`
+	Password    string                   `json:"password"`
+	UDP         bool                     `json:"udp"`
+	Level       byte                     `json:"level"`
+	Email       string                   `json:"email"`
+	OTA         *bool                    `json:"ota"`
+	NetworkList *NetworkList             `json:"network"`
+	Clients     []*ShadowsocksUserConfig `json:"clients"`
 }
 
 func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
@@ -46,9 +70,43 @@ func (v *ShadowsocksServerConfig) Build() (proto.Message, error) {
 	config.UdpEnabled = v.UDP
 	config.Network = v.NetworkList.Build()
 
-	if v.Password == "" {
+	if v.Password == "" && len(v.Clients) == 0 {
 		return nil, newError("Shadowsocks password is not specified.")
 	}
+
+	for _, client := range v.Clients {
+		if client.Password == "" {
+			return nil, newError("Shadowsocks password is not specified for user ", client.Email)
+		}
+		cipher := client.Cipher
+		if cipher == "" {
+			cipher = v.Cipher
+		}
+		account := &shadowsocks.Account{
+			Password:   client.Password,
+			CipherType: cipherFromString(cipher),
+		}
+		if account.CipherType == shadowsocks.CipherType_UNKNOWN {
+			return nil, newError("unknown cipher method: ", cipher)
+		}
+		config.Users = append(config.Users, &protocol.User{
+			Email:   client.Email,
+			Level:   uint32(client.Level),
+			Account: serial.ToTypedMessage(account),
+		})
+	}
+
+	if v.Password == "" {
+		return config, nil
+	}
+
+	if len(v.Clients) > 0 && is2022Cipher(cipherFromString(v.Cipher)) {
+		// Clients of Shadowsocks 2022 are identified by identity headers, which are encrypted with the password of
+		// the server.
+		config.IdentityKey = v.Password
+		return config, nil
+	}
+
 	account := &shadowsocks.Account{
 		Password: v.Password,
 		Ota:      shadowsocks.Account_Auto,
@@ -32,5 +32,69 @@ func TestShadowsocksServerConfigParsing(t *testing.T) {
 				Network: []net.Network{net.Network_TCP},
 			},
 		},
+		{
+			Input: `{
+				"method": "aes-256-gcm",
+				"clients": [
+					{
+						"password": "password-a",
+						"email": "a@v2ray.com"
+					},
+					{
+						"method": "chacha20-poly1305",
+						"password": "password-b",
+						"email": "b@v2ray.com",
+						"level": 1
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				Users: []*protocol.User{
+					{
+						Email: "a@v2ray.com",
+						Account: serial.ToTypedMessage(&shadowsocks.Account{
+							CipherType: shadowsocks.CipherType_AES_256_GCM,
+							Password:   "password-a",
+						}),
+					},
+					{
+						Email: "b@v2ray.com",
+						Level: 1,
+						Account: serial.ToTypedMessage(&shadowsocks.Account{
+							CipherType: shadowsocks.CipherType_CHACHA20_POLY1305,
+							Password:   "password-b",
+						}),
+					},
+				},
+				Network: []net.Network{net.Network_TCP},
+			},
+		},
+		{
+			Input: `{
+				"method": "2022-blake3-aes-128-gcm",
+				"password": "ZGVhZGJlZWZkZWFkYmVlZg==",
+				"clients": [
+					{
+						"password": "MTIzNDU2Nzg5MDEyMzQ1Ng==",
+						"email": "a@v2ray.com"
+					}
+				]
+			}`,
+			Parser: loadJSON(creator),
+			Output: &shadowsocks.ServerConfig{
+				Users: []*protocol.User{
+					{
+						Email: "a@v2ray.com",
+						Account: serial.ToTypedMessage(&shadowsocks.Account{
+							CipherType: shadowsocks.CipherType_BLAKE3_AES_128_GCM,
+							Password:   "MTIzNDU2Nzg5MDEyMzQ1Ng==",
+						}),
+					},
+				},
+				IdentityKey: "ZGVhZGJlZWZkZWFkYmVlZg==",
+				Network:     []net.Network{net.Network_TCP},
+			},
+		},
 	})
 }
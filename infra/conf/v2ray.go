@@ -15,6 +15,7 @@ import (
 
 var (
 	inboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
+		"dns":           func() interface{} { return new(DNSInboundConfig) },
 		"dokodemo-door": func() interface{} { return new(DokodemoConfig) },
 		"http":          func() interface{} { return new(HttpServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
@@ -293,10 +294,67 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 	}, nil
 }
 
-type StatsConfig struct{}
+type StatsMetricsConfig struct {
+	Listen   string `json:"listen"`
+	Path     string `json:"path"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (c *StatsMetricsConfig) Build() (*stats.MetricsConfig, error) {
+	if c.Listen == "" {
+		return nil, newError("listen address of metrics is not specified")
+	}
+	if c.Path != "" && c.Path[0] != '/' {
+		return nil, newError("invalid metrics path: ", c.Path)
+	}
+	return &stats.MetricsConfig{
+		Listen:   c.Listen,
+		Path:     c.Path,
+		Username: c.Username,
+		Password: c.Password,
+	}, nil
+}
+
+type StatsPersistenceConfig struct {
+	Path         string `json:"path"`
+	Interval     uint32 `json:"interval"`
+	DailyBuckets uint32 `json:"dailyBuckets"`
+}
+
+func (c *StatsPersistenceConfig) Build() (*stats.PersistenceConfig, error) {
+	if c.Path == "" {
+		return nil, newError("path of stats persistence is not specified")
+	}
+	return &stats.PersistenceConfig{
+		Path:         c.Path,
+		Interval:     c.Interval,
+		DailyBuckets: c.DailyBuckets,
+	}, nil
+}
+
+type StatsConfig struct {
+	Metrics     *StatsMetricsConfig     `json:"metrics"`
+	Persistence *StatsPersistenceConfig `json:"persistence"`
+}
 
 func (c *StatsConfig) Build() (*stats.Config, error) {
-	return &stats.Config{}, nil
+	config := &stats.Config{}
+	if c.Metrics != nil {
+		metrics, err := c.Metrics.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Metrics = metrics
+	}
+	if c.Persistence != nil {
+		persistence, err := c.Persistence.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Persistence = persistence
+	}
+	return config, nil
 }
 
 type Config struct {
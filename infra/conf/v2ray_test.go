@@ -12,6 +12,7 @@ import (
 	"v2ray.com/core/app/log"
 	"v2ray.com/core/app/proxyman"
 	"v2ray.com/core/app/router"
+	"v2ray.com/core/app/stats"
 	"v2ray.com/core/common"
 	clog "v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
@@ -447,3 +448,57 @@ func TestConfig_Override(t *testing.T) {
 		})
 	}
 }
+
+func TestStatsConfig(t *testing.T) {
+	parser := func(s string) (proto.Message, error) {
+		config := new(StatsConfig)
+		if err := json.Unmarshal([]byte(s), config); err != nil {
+			return nil, err
+		}
+		return config.Build()
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: parser,
+			Output: &stats.Config{},
+		},
+		{
+			Input: `{
+				"metrics": {
+					"listen": "127.0.0.1:9100",
+					"path": "/v2ray/metrics",
+					"username": "prometheus",
+					"password": "secret"
+				},
+				"persistence": {
+					"path": "/var/lib/v2ray/stats.dat",
+					"interval": 300,
+					"dailyBuckets": 31
+				}
+			}`,
+			Parser: parser,
+			Output: &stats.Config{
+				Metrics: &stats.MetricsConfig{
+					Listen:   "127.0.0.1:9100",
+					Path:     "/v2ray/metrics",
+					Username: "prometheus",
+					Password: "secret",
+				},
+				Persistence: &stats.PersistenceConfig{
+					Path:         "/var/lib/v2ray/stats.dat",
+					Interval:     300,
+					DailyBuckets: 31,
+				},
+			},
+		},
+	})
+
+	if _, err := parser(`{"metrics": {"path": "/metrics"}}`); err == nil {
+		t.Error("expect error on metrics without listen address, but nil")
+	}
+	if _, err := parser(`{"persistence": {"interval": 60}}`); err == nil {
+		t.Error("expect error on persistence without path, but nil")
+	}
+}
@@ -3,6 +3,7 @@ package conf
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -17,6 +18,23 @@ type VMessAccount struct {
 	ID       string `json:"id"`
 	AlterIds uint16 `json:"alterId"`
 	Security string `json:"security"`
+	Expire   string `json:"expire"`
+}
+
+// buildExpire parses expiry time in RFC 3339, or a date in the form of "2006-01-02" that expires at the end of the day
+// in local time.
+func buildExpire(s string) (int64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.Unix(), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return 0, newError("invalid expire: ", s).Base(err)
+	}
+	return t.AddDate(0, 0, 1).Unix(), nil
 }
 
 // Build implements Buildable
@@ -74,18 +92,48 @@ func (c *VMessDefaultConfig) Build() *inbound.DefaultConfig {
 	return config
 }
 
+type VMessFallbackConfig struct {
+	Address *Address `json:"address"`
+	Port    uint16   `json:"port"`
+}
+
+// Build implements Buildable
+func (c *VMessFallbackConfig) Build() (*inbound.FallbackConfig, error) {
+	if c.Address == nil {
+		return nil, newError("VMess fallback address is not specified.")
+	}
+	if c.Port == 0 {
+		return nil, newError("VMess fallback port is not specified.")
+	}
+	return &inbound.FallbackConfig{
+		Address: c.Address.Build(),
+		Port:    uint32(c.Port),
+	}, nil
+}
+
 type VMessInboundConfig struct {
-	Users        []json.RawMessage   `json:"clients"`
-	Features     *FeaturesConfig     `json:"features"`
-	Defaults     *VMessDefaultConfig `json:"default"`
-	DetourConfig *VMessDetourConfig  `json:"detour"`
-	SecureOnly   bool                `json:"disableInsecureEncryption"`
+	Users          []json.RawMessage    `json:"clients"`
+	Features       *FeaturesConfig      `json:"features"`
+	Defaults       *VMessDefaultConfig  `json:"default"`
+	DetourConfig   *VMessDetourConfig   `json:"detour"`
+	SecureOnly     bool                 `json:"disableInsecureEncryption"`
+	ProbeResistant bool                 `json:"probeResistant"`
+	Fallback       *VMessFallbackConfig `json:"fallback"`
 }
 
 // Build implements Buildable
 func (c *VMessInboundConfig) Build() (proto.Message, error) {
 	config := &inbound.Config{
 		SecureEncryptionOnly: c.SecureOnly,
+		ProbeResistant:       c.ProbeResistant,
+	}
+
+	if c.Fallback != nil {
+		fallback, err := c.Fallback.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.Fallback = fallback
 	}
 
 	if c.Defaults != nil {
@@ -108,7 +156,13 @@ func (c *VMessInboundConfig) Build() (proto.Message, error) {
 		if err := json.Unmarshal(rawData, account); err != nil {
 			return nil, newError("invalid VMess user").Base(err)
 		}
-		user.Account = serial.ToTypedMessage(account.Build())
+		vmessAccount := account.Build()
+		expire, err := buildExpire(account.Expire)
+		if err != nil {
+			return nil, newError("invalid VMess user").Base(err)
+		}
+		vmessAccount.Expire = expire
+		user.Account = serial.ToTypedMessage(vmessAccount)
 		config.User[idx] = user
 	}
 
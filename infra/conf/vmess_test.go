@@ -76,7 +76,8 @@ func TestVMessInbound(t *testing.T) {
 						"level": 0,
 						"alterId": 16,
 						"email": "love@v2ray.com",
-						"security": "aes-128-gcm"
+						"security": "aes-128-gcm",
+						"expire": "2030-01-02T15:04:05Z"
 					}
 				],
 				"default": {
@@ -86,7 +87,12 @@ func TestVMessInbound(t *testing.T) {
 				"detour": {
 					"to": "tag_to_detour"
 				},
-				"disableInsecureEncryption": true
+				"disableInsecureEncryption": true,
+				"probeResistant": true,
+				"fallback": {
+					"address": "127.0.0.1",
+					"port": 80
+				}
 			}`,
 			Parser: loadJSON(creator),
 			Output: &inbound.Config{
@@ -100,6 +106,7 @@ func TestVMessInbound(t *testing.T) {
 							SecuritySettings: &protocol.SecurityConfig{
 								Type: protocol.SecurityType_AES128_GCM,
 							},
+							Expire: 1893596645,
 						}),
 					},
 				},
@@ -111,6 +118,15 @@ func TestVMessInbound(t *testing.T) {
 					To: "tag_to_detour",
 				},
 				SecureEncryptionOnly: true,
+				ProbeResistant:       true,
+				Fallback: &inbound.FallbackConfig{
+					Address: &net.IPOrDomain{
+						Address: &net.IPOrDomain_Ip{
+							Ip: []byte{127, 0, 0, 1},
+						},
+					},
+					Port: 80,
+				},
 			},
 		},
 	})
@@ -12,6 +12,8 @@ import (
 	"google.golang.org/grpc"
 
 	logService "v2ray.com/core/app/log/command"
+	policyService "v2ray.com/core/app/policy/command"
+	handlerService "v2ray.com/core/app/proxyman/command"
 	statsService "v2ray.com/core/app/stats/command"
 	"v2ray.com/core/common"
 )
@@ -30,14 +32,30 @@ func (c *ApiCommand) Description() Description {
 			"Call an API in an V2Ray process.",
 			"The following methods are currently supported:",
 			"\tLoggerService.RestartLogger",
+			"\tLoggerService.SetLogLevel",
+			"\tLoggerService.GetLogLevels",
 			"\tStatsService.GetStats",
 			"\tStatsService.QueryStats",
+			"\tStatsService.GetSysStats",
+			"\tStatsService.GetDailyStats",
+			"\tPolicyService.GetQuotaUsage",
+			"\tPolicyService.ResetQuotaUsage",
+			"\tHandlerService.ListUsers",
+			"\tHandlerService.GetUser",
 			"API calls in this command have a timeout to the server of 3 seconds.",
+			"v2ctl api [--server=127.0.0.1:8080] log follow [--error] [--access] [--level=info] [--inbound=tag]... [--user=email]... [--json]",
+			"Print error and/or access logs of an V2Ray process until interrupted. Error logs are printed if neither is specified.",
 			"Examples:",
 			"v2ctl api --server=127.0.0.1:8080 LoggerService.RestartLogger '' ",
+			"v2ctl api --server=127.0.0.1:8080 LoggerService.SetLogLevel 'path: \"proxy/dokodemo\" level: Debug'",
 			"v2ctl api --server=127.0.0.1:8080 StatsService.QueryStats 'pattern: \"\" reset: false'",
 			"v2ctl api --server=127.0.0.1:8080 StatsService.GetStats 'name: \"inbound>>>statin>>>traffic>>>downlink\" reset: false'",
 			"v2ctl api --server=127.0.0.1:8080 StatsService.GetSysStats ''",
+			"v2ctl api --server=127.0.0.1:8080 StatsService.GetDailyStats 'pattern: \"user>>>\" days: 7'",
+			"v2ctl api --server=127.0.0.1:8080 PolicyService.GetQuotaUsage 'pattern: \"@v2ray.com\"'",
+			"v2ctl api --server=127.0.0.1:8080 PolicyService.ResetQuotaUsage 'email: \"love@v2ray.com\"'",
+			"v2ctl api --server=127.0.0.1:8080 HandlerService.ListUsers 'tag: \"vmess-in\"'",
+			"v2ctl api --server=127.0.0.1:8080 HandlerService.GetUser 'tag: \"vmess-in\" email: \"love@v2ray.com\"'",
 		},
 	}
 }
@@ -52,6 +70,9 @@ func (c *ApiCommand) Execute(args []string) error {
 	}
 
 	unnamedArgs := fs.Args()
+	if len(unnamedArgs) >= 2 && unnamedArgs[0] == "log" && unnamedArgs[1] == "follow" {
+		return followLog(*serverAddrPtr, unnamedArgs[2:])
+	}
 	if len(unnamedArgs) < 2 {
 		return newError("service name or request not specified.")
 	}
@@ -93,8 +114,10 @@ func getServiceMethod(s string) (string, string) {
 type serviceHandler func(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error)
 
 var serivceHandlerMap = map[string]serviceHandler{
-	"statsservice":  callStatsService,
-	"loggerservice": callLogService,
+	"statsservice":   callStatsService,
+	"loggerservice":  callLogService,
+	"policyservice":  callPolicyService,
+	"handlerservice": callHandlerService,
 }
 
 func callLogService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
@@ -111,6 +134,26 @@ func callLogService(ctx context.Context, conn *grpc.ClientConn, method string, r
 			return "", err
 		}
 		return proto.MarshalTextString(resp), nil
+	case "setloglevel":
+		r := &logService.SetLogLevelRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.SetLogLevel(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	case "getloglevels":
+		r := &logService.GetLogLevelsRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.GetLogLevels(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
 	default:
 		return "", errors.New("Unknown method: " + method)
 	}
@@ -148,6 +191,74 @@ func callStatsService(ctx context.Context, conn *grpc.ClientConn, method string,
 			return "", err
 		}
 		return proto.MarshalTextString(resp), nil
+	case "getdailystats":
+		r := &statsService.GetDailyStatsRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.GetDailyStats(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	default:
+		return "", errors.New("Unknown method: " + method)
+	}
+}
+
+func callPolicyService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
+	client := policyService.NewPolicyServiceClient(conn)
+
+	switch strings.ToLower(method) {
+	case "getquotausage":
+		r := &policyService.GetQuotaUsageRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.GetQuotaUsage(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	case "resetquotausage":
+		r := &policyService.ResetQuotaUsageRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.ResetQuotaUsage(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	default:
+		return "", errors.New("Unknown method: " + method)
+	}
+}
+
+func callHandlerService(ctx context.Context, conn *grpc.ClientConn, method string, request string) (string, error) {
+	client := handlerService.NewHandlerServiceClient(conn)
+
+	switch strings.ToLower(method) {
+	case "listusers":
+		r := &handlerService.ListUsersRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.ListUsers(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
+	case "getuser":
+		r := &handlerService.GetUserRequest{}
+		if err := proto.UnmarshalText(request, r); err != nil {
+			return "", err
+		}
+		resp, err := client.GetUser(ctx, r)
+		if err != nil {
+			return "", err
+		}
+		return proto.MarshalTextString(resp), nil
 	default:
 		return "", errors.New("Unknown method: " + method)
 	}
@@ -0,0 +1,92 @@
+package control
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+
+	"v2ray.com/core/app/router"
+	"v2ray.com/core/common"
+)
+
+type GeoDataCommand struct{}
+
+func (c *GeoDataCommand) Name() string {
+	return "geodata"
+}
+
+func (c *GeoDataCommand) Description() Description {
+	return Description{
+		Short: "Compile geoip and geosite files into compact format",
+		Usage: []string{
+			"v2ctl geodata compile [--geoip=geoip.dat] [--geosite=geosite.dat] --output=<file>",
+			"Compile the given geoip and geosite files into one file in compact format, which is memory-mapped and queried in place by V2Ray.",
+			"The output file can be referenced like other geodata files, such as \"ext:<file>:cn\", or replace geoip.dat and geosite.dat.",
+		},
+	}
+}
+
+func (c *GeoDataCommand) Execute(args []string) error {
+	if len(args) < 1 || args[0] != "compile" {
+		return newError("unknown subcommand, only \"compile\" is supported")
+	}
+
+	fs := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	geoipFile := fs.String("geoip", "", "Path of geoip file")
+	geositeFile := fs.String("geosite", "", "Path of geosite file")
+	output := fs.String("output", "", "Path of output file")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if len(*output) == 0 {
+		return newError("output file not specified")
+	}
+	if len(*geoipFile) == 0 && len(*geositeFile) == 0 {
+		return newError("neither geoip nor geosite file is specified")
+	}
+
+	var geoips *router.GeoIPList
+	if len(*geoipFile) > 0 {
+		geoips = new(router.GeoIPList)
+		if err := readProtoFile(*geoipFile, geoips); err != nil {
+			return err
+		}
+	}
+
+	var geosites *router.GeoSiteList
+	if len(*geositeFile) > 0 {
+		geosites = new(router.GeoSiteList)
+		if err := readProtoFile(*geositeFile, geosites); err != nil {
+			return err
+		}
+	}
+
+	data, err := router.CompileGeoData(geoips, geosites)
+	if err != nil {
+		return newError("failed to compile geodata").Base(err)
+	}
+	if err := ioutil.WriteFile(*output, data, 0644); err != nil {
+		return newError("failed to write ", *output).Base(err)
+	}
+	return nil
+}
+
+func readProtoFile(file string, m proto.Message) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return newError("failed to read ", file).Base(err)
+	}
+	if router.IsCompactGeoData(data) {
+		return newError(file, " is already in compact format")
+	}
+	if err := proto.Unmarshal(data, m); err != nil {
+		return newError("failed to parse ", file).Base(err)
+	}
+	return nil
+}
+
+func init() {
+	common.Must(RegisterCommand(&GeoDataCommand{}))
+}
@@ -0,0 +1,86 @@
+package control
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app/log"
+	logService "v2ray.com/core/app/log/command"
+	clog "v2ray.com/core/common/log"
+)
+
+func parseSeverity(s string) (clog.Severity, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return clog.Severity_Debug, nil
+	case "info":
+		return clog.Severity_Info, nil
+	case "warning":
+		return clog.Severity_Warning, nil
+	case "error":
+		return clog.Severity_Error, nil
+	default:
+		return clog.Severity_Unknown, newError("unknown log level: ", s)
+	}
+}
+
+func followLog(serverAddr string, args []string) error {
+	fs := flag.NewFlagSet("log follow", flag.ContinueOnError)
+
+	errorLog := fs.Bool("error", false, "Print error logs")
+	accessLog := fs.Bool("access", false, "Print access logs")
+	level := fs.String("level", "info", "Print error logs at this level or more severe: debug, info, warning or error")
+	jsonFormat := fs.Bool("json", false, "Print logs in JSON")
+	var inboundTags, emails stringList
+	fs.Var(&inboundTags, "inbound", "Only print logs of this inbound. Can be specified multiple times")
+	fs.Var(&emails, "user", "Only print logs of this user. Can be specified multiple times")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	request := &logService.FollowLogRequest{
+		Error:      *errorLog || !*accessLog,
+		Access:     *accessLog,
+		InboundTag: inboundTags,
+		Email:      emails,
+	}
+	severity, err := parseSeverity(*level)
+	if err != nil {
+		return err
+	}
+	request.Level = severity
+	if *jsonFormat {
+		request.Format = log.LogFormat_JSON
+	}
+
+	dialCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, serverAddr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return newError("failed to dial ", serverAddr).Base(err)
+	}
+	defer conn.Close()
+
+	stream, err := logService.NewLoggerServiceClient(conn).FollowLog(context.Background(), request)
+	if err != nil {
+		return newError("failed to follow log").Base(err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newError("failed to receive log").Base(err)
+		}
+		fmt.Println(resp.Message)
+	}
+}
@@ -0,0 +1,278 @@
+package control
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"v2ray.com/core/app/router"
+	routerService "v2ray.com/core/app/router/command"
+	"v2ray.com/core/common"
+	v2net "v2ray.com/core/common/net"
+	"v2ray.com/core/infra/conf/serial"
+)
+
+type RouteCommand struct{}
+
+func (c *RouteCommand) Name() string {
+	return "route"
+}
+
+func (c *RouteCommand) Description() Description {
+	return Description{
+		Short: "Test routing rules in a V2Ray process",
+		Usage: []string{
+			"v2ctl route [--server=127.0.0.1:8080] [--inbound=tag] [--user=email] [--network=tcp] [--source=ip:port] [--protocol=tls] [--attr key=value]... <destination:port>",
+			"Show which routing rule a connection with the given properties matches, and how each rule is evaluated.",
+			"v2ctl route [--server=127.0.0.1:8080] --follow",
+			"Print routing decisions of live connections until interrupted.",
+			"v2ctl route [--server=127.0.0.1:8080] --replace=config.json",
+			"Replace routing rules and balancers with the \"routing\" section in the given JSON config.",
+			"v2ctl route [--server=127.0.0.1:8080] --reload-geodata",
+			"Reload geoip and geosite files, and rebuild rules that reference them.",
+			"RoutingService must be enabled in the API config of the V2Ray process.",
+		},
+	}
+}
+
+type attributeFlags map[string]string
+
+func (f attributeFlags) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f attributeFlags) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 {
+		return newError("invalid attribute: ", s)
+	}
+	f[kv[0]] = kv[1]
+	return nil
+}
+
+func splitHostPort(s string) (string, uint32, error) {
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return "", 0, err
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, newError("invalid port: ", portStr).Base(err)
+	}
+	return host, uint32(port), nil
+}
+
+func (c *RouteCommand) Execute(args []string) error {
+	fs := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+
+	serverAddr := fs.String("server", "127.0.0.1:8080", "Server address")
+	follow := fs.Bool("follow", false, "Print routing decisions of live connections")
+	replace := fs.String("replace", "", "JSON config file whose routing rules replace the current ones")
+	reloadGeoData := fs.Bool("reload-geodata", false, "Reload geodata files")
+	inboundTag := fs.String("inbound", "", "Tag of the inbound")
+	user := fs.String("user", "", "Email of the user")
+	network := fs.String("network", "tcp", "Network of the connection, tcp or udp")
+	source := fs.String("source", "", "Source address of the connection, in ip:port")
+	protocol := fs.String("protocol", "", "Sniffed protocol of the connection")
+	attrs := make(attributeFlags)
+	fs.Var(attrs, "attr", "Attribute of the connection, in key=value. Can be specified multiple times")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var request *routerService.TestRouteRequest
+	var replaceRequest *routerService.ReplaceRulesRequest
+	switch {
+	case *follow, *reloadGeoData:
+	case len(*replace) > 0:
+		config, err := loadRouterConfig(*replace)
+		if err != nil {
+			return err
+		}
+		replaceRequest = &routerService.ReplaceRulesRequest{Config: config}
+	default:
+		if fs.NArg() < 1 {
+			return newError("destination not specified")
+		}
+		rc := &routerService.RoutingContext{
+			InboundTag: *inboundTag,
+			UserEmail:  *user,
+			Protocol:   *protocol,
+			Attributes: attrs,
+		}
+		switch strings.ToLower(*network) {
+		case "tcp":
+			rc.Network = v2net.Network_TCP
+		case "udp":
+			rc.Network = v2net.Network_UDP
+		default:
+			return newError("unknown network: ", *network)
+		}
+		host, port, err := splitHostPort(fs.Arg(0))
+		if err != nil {
+			return newError("invalid destination: ", fs.Arg(0)).Base(err)
+		}
+		rc.Target = host
+		rc.TargetPort = port
+		if len(*source) > 0 {
+			host, port, err := splitHostPort(*source)
+			if err != nil {
+				return newError("invalid source: ", *source).Base(err)
+			}
+			rc.SourceIp = host
+			rc.SourcePort = port
+		}
+		request = &routerService.TestRouteRequest{RoutingContext: rc}
+	}
+
+	dialCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(dialCtx, *serverAddr, grpc.WithInsecure(), grpc.WithBlock())
+	if err != nil {
+		return newError("failed to dial ", *serverAddr).Base(err)
+	}
+	defer conn.Close()
+
+	client := routerService.NewRoutingServiceClient(conn)
+
+	if *follow {
+		return followRoutingDecisions(client)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	if *reloadGeoData {
+		if _, err := client.ReloadGeoData(ctx, &routerService.ReloadGeoDataRequest{}); err != nil {
+			return newError("failed to reload geodata").Base(err)
+		}
+		fmt.Println("Geodata reloaded.")
+		return nil
+	}
+
+	if replaceRequest != nil {
+		if _, err := client.ReplaceRules(ctx, replaceRequest); err != nil {
+			return newError("failed to replace rules").Base(err)
+		}
+		fmt.Println("Replaced with", len(replaceRequest.Config.Rule), "rules.")
+		return nil
+	}
+
+	resp, err := client.TestRoute(ctx, request)
+	if err != nil {
+		return newError("failed to call RoutingService").Base(err)
+	}
+	printTestRouteResponse(resp)
+	return nil
+}
+
+func loadRouterConfig(file string) (*router.Config, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, newError("failed to open config: ", file).Base(err)
+	}
+	defer f.Close()
+
+	c, err := serial.DecodeJSONConfig(f)
+	if err != nil {
+		return nil, err
+	}
+	if c.RouterConfig == nil {
+		return nil, newError("no routing config in ", file)
+	}
+	return c.RouterConfig.Build()
+}
+
+func ruleName(index int32, tag string) string {
+	name := "#" + strconv.Itoa(int(index))
+	if len(tag) > 0 {
+		name += " (" + tag + ")"
+	}
+	return name
+}
+
+func printTestRouteResponse(resp *routerService.TestRouteResponse) {
+	for _, rule := range resp.Rule {
+		var conds []string
+		for _, cond := range rule.Condition {
+			mark := "x"
+			switch {
+			case !cond.Evaluated:
+				mark = "-"
+			case cond.Matched:
+				mark = "v"
+			}
+			conds = append(conds, cond.Name+":"+mark)
+		}
+		fmt.Println("Rule", ruleName(rule.Index, rule.RuleTag), strings.Join(conds, " "))
+	}
+	if len(resp.ResolvedIp) > 0 {
+		fmt.Println("Resolved IPs:", strings.Join(resp.ResolvedIp, ", "))
+	}
+	if !resp.Matched {
+		fmt.Println("No rule matches. The default outbound will be used.")
+		return
+	}
+	fmt.Println("Matched rule:", ruleName(resp.RuleIndex, resp.RuleTag))
+	if len(resp.BalancingTag) > 0 {
+		fmt.Println("Balancer:", resp.BalancingTag)
+	}
+	fmt.Println("Outbound:", resp.OutboundTag)
+}
+
+func followRoutingDecisions(client routerService.RoutingServiceClient) error {
+	stream, err := client.SubscribeRoutingDecisions(context.Background(), &routerService.SubscribeRoutingDecisionsRequest{})
+	if err != nil {
+		return newError("failed to subscribe routing decisions").Base(err)
+	}
+	for {
+		d, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newError("failed to receive routing decision").Base(err)
+		}
+		rc := d.RoutingContext
+		line := []string{
+			time.Unix(d.Timestamp, 0).Format("2006/01/02 15:04:05"),
+			"[" + rc.InboundTag + "]",
+			net.JoinHostPort(rc.SourceIp, strconv.Itoa(int(rc.SourcePort))),
+			"->",
+			net.JoinHostPort(rc.Target, strconv.Itoa(int(rc.TargetPort))),
+		}
+		if len(rc.UserEmail) > 0 {
+			line = append(line, "user:"+rc.UserEmail)
+		}
+		if len(rc.Protocol) > 0 {
+			line = append(line, "protocol:"+rc.Protocol)
+		}
+		switch {
+		case len(d.Error) > 0:
+			line = append(line, "error:", d.Error)
+		case d.RuleIndex < 0:
+			line = append(line, "default outbound")
+		default:
+			line = append(line, "rule", ruleName(d.RuleIndex, d.RuleTag), "outbound:", d.OutboundTag)
+		}
+		fmt.Println(strings.Join(line, " "))
+	}
+}
+
+func init() {
+	common.Must(RegisterCommand(&RouteCommand{}))
+}
@@ -58,8 +58,80 @@ func (m *Config) GetServer() *net.Endpoint {
 	return nil
 }
 
+// ServerConfig is the config of the DNS inbound, which answers queries from
+// the DNS app.
+type ServerConfig struct {
+	// Networks to listen on. Default UDP and TCP.
+	Networks []net.Network `protobuf:"varint,1,rep,packed,name=networks,proto3,enum=v2ray.core.common.net.Network" json:"networks,omitempty"`
+	// Serve DNS over HTTPS (RFC 8484) on TCP connections instead of DNS over
+	// TCP. HTTPS is available if TLS is set in stream settings.
+	Doh bool `protobuf:"varint,2,opt,name=doh,proto3" json:"doh,omitempty"`
+	// Path of DNS over HTTPS requests. Default "/dns-query".
+	DohPath string `protobuf:"bytes,3,opt,name=doh_path,json=dohPath,proto3" json:"doh_path,omitempty"`
+	// Source IP ranges allowed to query, in CIDR notation such as
+	// "192.168.0.0/16". Any source is allowed if empty.
+	AllowedSource        []string `protobuf:"bytes,4,rep,name=allowed_source,json=allowedSource,proto3" json:"allowed_source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ServerConfig) Reset()         { *m = ServerConfig{} }
+func (m *ServerConfig) String() string { return proto.CompactTextString(m) }
+func (*ServerConfig) ProtoMessage()    {}
+func (*ServerConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c49bb2d51e576d57, []int{1}
+}
+
+func (m *ServerConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ServerConfig.Unmarshal(m, b)
+}
+func (m *ServerConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ServerConfig.Marshal(b, m, deterministic)
+}
+func (m *ServerConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ServerConfig.Merge(m, src)
+}
+func (m *ServerConfig) XXX_Size() int {
+	return xxx_messageInfo_ServerConfig.Size(m)
+}
+func (m *ServerConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_ServerConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ServerConfig proto.InternalMessageInfo
+
+func (m *ServerConfig) GetNetworks() []net.Network {
+	if m != nil {
+		return m.Networks
+	}
+	return nil
+}
+
+func (m *ServerConfig) GetDoh() bool {
+	if m != nil {
+		return m.Doh
+	}
+	return false
+}
+
+func (m *ServerConfig) GetDohPath() string {
+	if m != nil {
+		return m.DohPath
+	}
+	return ""
+}
+
+func (m *ServerConfig) GetAllowedSource() []string {
+	if m != nil {
+		return m.AllowedSource
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.dns.Config")
+	proto.RegisterType((*ServerConfig)(nil), "v2ray.core.proxy.dns.ServerConfig")
 }
 
 func init() {
@@ -67,17 +139,24 @@ func init() {
 }
 
 var fileDescriptor_c49bb2d51e576d57 = []byte{
-	// 190 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8e, 0x31, 0xab, 0xc2, 0x30,
-	0x14, 0x46, 0xe9, 0x7b, 0xd0, 0x21, 0x6f, 0x2b, 0x1d, 0xca, 0x5b, 0xde, 0x43, 0x10, 0x04, 0xe1,
-	0x06, 0xea, 0xa0, 0xab, 0x56, 0xf7, 0xd2, 0xc1, 0xc1, 0xad, 0x26, 0x51, 0x32, 0xe4, 0xde, 0x72,
-	0x13, 0x8a, 0xfd, 0x4b, 0xfe, 0x4a, 0x31, 0x55, 0x10, 0x71, 0xfe, 0xce, 0x77, 0x38, 0x62, 0xda,
-	0x97, 0xdc, 0x0e, 0xa0, 0xc8, 0x49, 0x45, 0x6c, 0x64, 0xc7, 0x74, 0x19, 0xa4, 0x46, 0x2f, 0x15,
-	0xe1, 0xc9, 0x9e, 0xa1, 0x63, 0x0a, 0x94, 0xe5, 0x4f, 0x8c, 0x0d, 0x44, 0x04, 0x34, 0xfa, 0xdf,
-	0xf9, 0xdb, 0x59, 0x91, 0x73, 0x84, 0x12, 0x4d, 0x90, 0xda, 0xf8, 0x60, 0xb1, 0x0d, 0x96, 0x70,
-	0x54, 0x4c, 0xd6, 0x22, 0xad, 0xa2, 0x32, 0x5b, 0x8a, 0xd4, 0x1b, 0xee, 0x0d, 0x17, 0xc9, 0x7f,
-	0x32, 0xfb, 0x29, 0xff, 0xe0, 0xc5, 0x3e, 0x3a, 0x00, 0x4d, 0x80, 0x1d, 0xea, 0x8e, 0x2c, 0x86,
-	0xe6, 0x81, 0x6f, 0x56, 0xa2, 0x50, 0xe4, 0xe0, 0x53, 0x4b, 0x9d, 0x1c, 0xbe, 0x35, 0xfa, 0xeb,
-	0x57, 0xbe, 0x2f, 0x9b, 0x76, 0x80, 0xea, 0xbe, 0xd6, 0x71, 0xdd, 0xa2, 0x3f, 0xa6, 0xb1, 0x61,
-	0x71, 0x0b, 0x00, 0x00, 0xff, 0xff, 0x1c, 0x84, 0x9c, 0x79, 0xef, 0x00, 0x00, 0x00,
+	// 294 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0x41, 0x4b, 0xc3, 0x40,
+	0x10, 0x85, 0xd9, 0x46, 0x6a, 0xbb, 0x6a, 0x91, 0xd0, 0x43, 0xec, 0x41, 0x43, 0xa1, 0x18, 0x10,
+	0x36, 0x10, 0x0f, 0x8a, 0x37, 0xad, 0x5e, 0xa5, 0x6c, 0xc1, 0x83, 0x97, 0xb2, 0xee, 0xae, 0x26,
+	0xd8, 0xcc, 0x84, 0xdd, 0xb5, 0x35, 0x7f, 0xc6, 0x1f, 0xe0, 0xaf, 0x94, 0x6e, 0xa2, 0x88, 0xd8,
+	0xdb, 0x30, 0xf3, 0xbd, 0xf7, 0x86, 0x47, 0x27, 0xab, 0xcc, 0x88, 0x9a, 0x49, 0x2c, 0x53, 0x89,
+	0x46, 0xa7, 0x95, 0xc1, 0xf7, 0x3a, 0x55, 0x60, 0x53, 0x89, 0xf0, 0x5c, 0xbc, 0xb0, 0xca, 0xa0,
+	0xc3, 0x70, 0xf8, 0x8d, 0x19, 0xcd, 0x3c, 0xc2, 0x14, 0xd8, 0xd1, 0xd9, 0x1f, 0xb1, 0xc4, 0xb2,
+	0x44, 0x48, 0x41, 0xbb, 0x54, 0x69, 0xeb, 0x0a, 0x10, 0xae, 0x40, 0x68, 0x2c, 0x46, 0xa7, 0xdb,
+	0x61, 0xd0, 0x6e, 0x8d, 0xe6, 0xb5, 0x01, 0xc7, 0xd7, 0xb4, 0x3b, 0xf5, 0xd9, 0xe1, 0x05, 0xed,
+	0x5a, 0x6d, 0x56, 0xda, 0x44, 0x24, 0x26, 0xc9, 0x5e, 0x76, 0xc2, 0x7e, 0xbd, 0xd1, 0xe8, 0x19,
+	0x68, 0xc7, 0xee, 0x40, 0x55, 0x58, 0x80, 0xe3, 0x2d, 0x3e, 0xfe, 0x20, 0x74, 0x7f, 0xee, 0xc7,
+	0xd6, 0xe9, 0x8a, 0xf6, 0xda, 0x10, 0x1b, 0x91, 0x38, 0x48, 0x06, 0xd9, 0xf1, 0x16, 0xaf, 0xfb,
+	0x06, 0xe3, 0x3f, 0x7c, 0x78, 0x48, 0x03, 0x85, 0x79, 0xd4, 0x89, 0x49, 0xd2, 0xe3, 0x9b, 0x31,
+	0x3c, 0xa2, 0x3d, 0x85, 0xf9, 0xa2, 0x12, 0x2e, 0x8f, 0x82, 0x98, 0x24, 0x7d, 0xbe, 0xab, 0x30,
+	0x9f, 0x09, 0x97, 0x87, 0x13, 0x3a, 0x10, 0xcb, 0x25, 0xae, 0xb5, 0x5a, 0x58, 0x7c, 0x33, 0x52,
+	0x47, 0x3b, 0x71, 0x90, 0xf4, 0xf9, 0x41, 0xbb, 0x9d, 0xfb, 0xe5, 0xcd, 0x25, 0x8d, 0x24, 0x96,
+	0xec, 0xbf, 0x56, 0x67, 0xe4, 0x31, 0x50, 0x60, 0x3f, 0x3b, 0xc3, 0x87, 0x8c, 0x8b, 0x9a, 0x4d,
+	0x37, 0xd7, 0x99, 0xbf, 0xde, 0x82, 0x7d, 0xea, 0xfa, 0x92, 0xce, 0xbf, 0x06, 0x00, 0xb7, 0xab,
+	0x79, 0xac, 0xb9, 0x01, 0x00, 0x00,
 }
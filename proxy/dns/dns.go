@@ -4,6 +4,7 @@ package dns
 
 import (
 	"context"
+	"encoding/binary"
 	"io"
 	"sync"
 
@@ -40,10 +41,21 @@ type ownLinkVerifier interface {
 type Handler struct {
 	ipv4Lookup      dns.IPv4Lookup
 	ipv6Lookup      dns.IPv6Lookup
+	recordLookup    dns.RecordLookup
+	clientLookup    dns.ClientLookup
+	fakeDNS         dns.FakeDNSEngine
 	ownLinkVerifier ownLinkVerifier
 	server          net.Destination
+	// servFail answers SERVFAIL to queries that fail to resolve, instead of dropping them.
+	servFail bool
 }
 
+const (
+	answerTTL = 600
+	// fakeAnswerTTL is kept short, so that clients don't keep a fake IP after it is recycled for another domain.
+	fakeAnswerTTL = 1
+)
+
 func (h *Handler) Init(config *Config, dnsClient dns.Client) error {
 	ipv4lookup, ok := dnsClient.(dns.IPv4Lookup)
 	if !ok {
@@ -57,6 +69,18 @@ func (h *Handler) Init(config *Config, dnsClient dns.Client) error {
 	}
 	h.ipv6Lookup = ipv6lookup
 
+	if r, ok := dnsClient.(dns.RecordLookup); ok {
+		h.recordLookup = r
+	}
+
+	if c, ok := dnsClient.(dns.ClientLookup); ok {
+		h.clientLookup = c
+	}
+
+	if e, ok := dnsClient.(dns.FakeDNSEngine); ok && e.IsFakeDNSEnabled() {
+		h.fakeDNS = e
+	}
+
 	if v, ok := dnsClient.(ownLinkVerifier); ok {
 		h.ownLinkVerifier = v
 	}
@@ -71,7 +95,16 @@ func (h *Handler) isOwnLink(ctx context.Context) bool {
 	return h.ownLinkVerifier != nil && h.ownLinkVerifier.IsOwnLink(ctx)
 }
 
-func parseIPQuery(b []byte) (r bool, domain string, id uint16, qType dnsmessage.Type) {
+// sourceIP returns the IP of the client that sends queries in the context, or nil if it is unknown.
+func sourceIP(ctx context.Context) net.IP {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || inbound.Source.Address == nil || !inbound.Source.Address.Family().IsIP() {
+		return nil
+	}
+	return inbound.Source.Address.IP()
+}
+
+func parseQuery(b []byte) (r bool, domain string, id uint16, qType dnsmessage.Type) {
 	var parser dnsmessage.Parser
 	header, err := parser.Start(b)
 	if err != nil {
@@ -86,7 +119,7 @@ func parseIPQuery(b []byte) (r bool, domain string, id uint16, qType dnsmessage.
 		return
 	}
 	qType = q.Type
-	if qType != dnsmessage.TypeA && qType != dnsmessage.TypeAAAA {
+	if q.Class != dnsmessage.ClassINET {
 		return
 	}
 
@@ -95,6 +128,18 @@ func parseIPQuery(b []byte) (r bool, domain string, id uint16, qType dnsmessage.
 	return
 }
 
+// isRecordType returns true if the type is a type of data records, rather than a meta type like OPT, AXFR or ANY.
+func isRecordType(qType dnsmessage.Type) bool {
+	switch {
+	case qType == dnsmessage.TypeOPT:
+		return false
+	case qType >= 128 && qType <= 255:
+		return false
+	default:
+		return qType != 0
+	}
+}
+
 // Process implements proxy.Outbound.
 func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.Dialer) error {
 	outbound := session.OutboundFromContext(ctx)
@@ -116,6 +161,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 	}
 
 	newError("handling DNS traffic to ", dest).WriteToLog(session.ExportIDToError(ctx))
+	clientIP := sourceIP(ctx)
 
 	conn := &outboundConn{
 		dialer: func() (internet.Connection, error) {
@@ -170,9 +216,14 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 			}
 
 			if !h.isOwnLink(ctx) {
-				isIPQuery, domain, id, qType := parseIPQuery(b.Bytes())
-				if isIPQuery {
-					go h.handleIPQuery(id, qType, domain, writer)
+				isQuery, domain, id, qType := parseQuery(b.Bytes())
+				switch {
+				case !isQuery:
+				case qType == dnsmessage.TypeA || qType == dnsmessage.TypeAAAA:
+					go h.handleIPQuery(id, qType, domain, clientIP, writer)
+					continue
+				case h.recordLookup != nil && isRecordType(qType):
+					go h.handleRecordQuery(id, qType, domain, writer)
 					continue
 				}
 			}
@@ -207,21 +258,36 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 	return nil
 }
 
-func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string, writer dns_proto.MessageWriter) {
+func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string, clientIP net.IP, writer dns_proto.MessageWriter) {
 	var ips []net.IP
 	var err error
+	var ttl uint32 = answerTTL
 
-	switch qType {
-	case dnsmessage.TypeA:
+	switch {
+	case h.fakeDNS != nil:
+		ttl = fakeAnswerTTL
+		if ip := h.fakeDNS.GetFakeIPForDomain(domain, qType == dnsmessage.TypeAAAA); ip != nil {
+			ips = []net.IP{ip}
+		} else {
+			err = dns.ErrEmptyResponse
+		}
+	case h.clientLookup != nil && clientIP != nil && qType == dnsmessage.TypeA:
+		ips, err = h.clientLookup.LookupIPv4ForClient(domain, clientIP)
+	case h.clientLookup != nil && clientIP != nil && qType == dnsmessage.TypeAAAA:
+		ips, err = h.clientLookup.LookupIPv6ForClient(domain, clientIP)
+	case qType == dnsmessage.TypeA:
 		ips, err = h.ipv4Lookup.LookupIPv4(domain)
-	case dnsmessage.TypeAAAA:
+	case qType == dnsmessage.TypeAAAA:
 		ips, err = h.ipv6Lookup.LookupIPv6(domain)
 	}
 
 	rcode := dns.RCodeFromError(err)
 	if rcode == 0 && len(ips) == 0 && err != dns.ErrEmptyResponse {
 		newError("ip query").Base(err).WriteToLog()
-		return
+		if !h.servFail {
+			return
+		}
+		rcode = uint16(dnsmessage.RCodeServerFailure)
 	}
 
 	b := buf.New()
@@ -243,7 +309,7 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 	}))
 	common.Must(builder.StartAnswers())
 
-	rHeader := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(domain), Class: dnsmessage.ClassINET, TTL: 600}
+	rHeader := dnsmessage.ResourceHeader{Name: dnsmessage.MustNewName(domain), Class: dnsmessage.ClassINET, TTL: ttl}
 	for _, ip := range ips {
 		if len(ip) == net.IPv4len {
 			var r dnsmessage.AResource
@@ -268,6 +334,77 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 	}
 }
 
+func (h *Handler) handleRecordQuery(id uint16, qType dnsmessage.Type, domain string, writer dns_proto.MessageWriter) {
+	records, err := h.recordLookup.LookupRecords(domain, uint16(qType))
+	rcode := dns.RCodeFromError(err)
+	if rcode == 0 && len(records) == 0 && err != dns.ErrEmptyResponse {
+		newError("record query").Base(err).WriteToLog()
+		if !h.servFail {
+			return
+		}
+		rcode = uint16(dnsmessage.RCodeServerFailure)
+	}
+
+	b := buf.New()
+	rawBytes := b.Extend(buf.Size)
+	builder := dnsmessage.NewBuilder(rawBytes[:0], dnsmessage.Header{
+		ID:                 id,
+		RCode:              dnsmessage.RCode(rcode),
+		RecursionAvailable: true,
+		RecursionDesired:   true,
+		Response:           true,
+		Authoritative:      true,
+	})
+	common.Must(builder.StartQuestions())
+	common.Must(builder.Question(dnsmessage.Question{
+		Name:  dnsmessage.MustNewName(domain),
+		Class: dnsmessage.ClassINET,
+		Type:  qType,
+	}))
+	msgBytes, err := builder.Finish()
+	if err != nil {
+		newError("pack message").Base(err).WriteToLog()
+		b.Release()
+		return
+	}
+
+	// dnsmessage is not able to build records of arbitrary types, so answers are appended in wire format.
+	msgBytes, err = appendRecords(msgBytes, records)
+	if err != nil || len(msgBytes) > int(buf.Size) {
+		newError("pack records for ", domain).Base(err).WriteToLog()
+		b.Release()
+		return
+	}
+	b.Resize(0, int32(len(msgBytes)))
+
+	if err := writer.WriteMessage(b); err != nil {
+		newError("write record answer").Base(err).WriteToLog()
+	}
+}
+
+// appendRecords appends records to the answer section of the packed message.
+func appendRecords(msg []byte, records []dns.Record) ([]byte, error) {
+	for _, r := range records {
+		if len(r.Data) > 0xFFFF {
+			return nil, newError("record data too long")
+		}
+		name, err := dnsmessage.NewName(r.Name)
+		if err != nil {
+			return nil, err
+		}
+		msg = dns_proto.AppendName(msg, name.String())
+		var header [10]byte
+		binary.BigEndian.PutUint16(header[0:], r.Type)
+		binary.BigEndian.PutUint16(header[2:], uint16(dnsmessage.ClassINET))
+		binary.BigEndian.PutUint32(header[4:], r.TTL)
+		binary.BigEndian.PutUint16(header[8:], uint16(len(r.Data)))
+		msg = append(msg, header[:]...)
+		msg = append(msg, r.Data...)
+	}
+	binary.BigEndian.PutUint16(msg[6:8], uint16(len(records)))
+	return msg, nil
+}
+
 type outboundConn struct {
 	access sync.Mutex
 	dialer func() (internet.Connection, error)
@@ -0,0 +1,307 @@
+// +build !confonly
+
+package dns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"v2ray.com/core"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	dns_proto "v2ray.com/core/common/protocol/dns"
+	"v2ray.com/core/common/signal/semaphore"
+	"v2ray.com/core/features/dns"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/transport/internet"
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		s := new(Server)
+		if err := core.RequireFeatures(ctx, func(dnsClient dns.Client) error {
+			return s.Init(config.(*ServerConfig), dnsClient)
+		}); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}))
+}
+
+const (
+	defaultDoHPath = "/dns-query"
+	dohMediaType   = "application/dns-message"
+
+	// maxConcurrentAnswers is the number of queries answered concurrently on one connection.
+	// Reading of further queries is blocked until an answer is written.
+	maxConcurrentAnswers = 16
+)
+
+// Server is an inbound handler that answers DNS queries from the DNS app, so that it can be used as a resolver directly.
+type Server struct {
+	handler       Handler
+	networks      []net.Network
+	doh           bool
+	dohPath       string
+	allowedSource []*net.IPNet
+}
+
+// Init initializes the Server with the config.
+func (s *Server) Init(config *ServerConfig, dnsClient dns.Client) error {
+	if err := s.handler.Init(&Config{}, dnsClient); err != nil {
+		return err
+	}
+	s.handler.servFail = true
+
+	s.networks = config.Networks
+	if len(s.networks) == 0 {
+		s.networks = []net.Network{net.Network_TCP, net.Network_UDP}
+	}
+
+	s.doh = config.Doh
+	s.dohPath = config.DohPath
+	if len(s.dohPath) == 0 {
+		s.dohPath = defaultDoHPath
+	}
+
+	for _, source := range config.AllowedSource {
+		_, ipNet, err := net.ParseCIDR(source)
+		if err != nil {
+			return newError("invalid allowed source: ", source).Base(err)
+		}
+		s.allowedSource = append(s.allowedSource, ipNet)
+	}
+	return nil
+}
+
+// Network implements proxy.Inbound.
+func (s *Server) Network() []net.Network {
+	return s.networks
+}
+
+func (s *Server) isAllowed(clientIP net.IP) bool {
+	if len(s.allowedSource) == 0 {
+		return true
+	}
+	if clientIP == nil {
+		return false
+	}
+	for _, ipNet := range s.allowedSource {
+		if ipNet.Contains(clientIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	clientIP := sourceIP(ctx)
+	if !s.isAllowed(clientIP) {
+		return newError("source not allowed: ", conn.RemoteAddr()).AtInfo()
+	}
+
+	if network == net.Network_TCP && s.doh {
+		return s.serveDoH(conn, clientIP)
+	}
+
+	var reader dns_proto.MessageReader
+	var writer dns_proto.MessageWriter
+	if network == net.Network_TCP {
+		reader = dns_proto.NewTCPReader(buf.NewReader(conn))
+		writer = &dns_proto.TCPWriter{
+			Writer: buf.NewWriter(conn),
+		}
+	} else {
+		reader = &dns_proto.UDPReader{
+			Reader: buf.NewReader(conn),
+		}
+		writer = &dns_proto.UDPWriter{
+			Writer: buf.NewWriter(conn),
+		}
+	}
+	// Answers are written concurrently.
+	writer = &lockedWriter{writer: writer}
+	sem := semaphore.New(maxConcurrentAnswers)
+
+	for {
+		b, err := reader.ReadMessage()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newError("failed to read DNS query").Base(err)
+		}
+
+		select {
+		case <-sem.Wait():
+		case <-ctx.Done():
+			b.Release()
+			return ctx.Err()
+		}
+		go func() {
+			defer sem.Signal()
+			s.answer(b, clientIP, writer)
+		}()
+	}
+}
+
+// answer writes the answer of the query into the writer. The query is released.
+func (s *Server) answer(b *buf.Buffer, clientIP net.IP, writer dns_proto.MessageWriter) {
+	isQuery, domain, id, qType := parseQuery(b.Bytes())
+	if !isQuery && b.Len() >= 2 {
+		id = binary.BigEndian.Uint16(b.BytesTo(2))
+	}
+	b.Release()
+
+	switch {
+	case !isQuery:
+		s.writeRCode(id, dnsmessage.RCodeFormatError, writer)
+	case qType == dnsmessage.TypeA || qType == dnsmessage.TypeAAAA:
+		s.handler.handleIPQuery(id, qType, domain, clientIP, writer)
+	case s.handler.recordLookup != nil && isRecordType(qType):
+		s.handler.handleRecordQuery(id, qType, domain, writer)
+	default:
+		s.writeRCode(id, dnsmessage.RCodeNotImplemented, writer)
+	}
+}
+
+// writeRCode writes an answer with only the header, for queries that are not answered.
+func (s *Server) writeRCode(id uint16, rcode dnsmessage.RCode, writer dns_proto.MessageWriter) {
+	b := buf.New()
+	rawBytes := b.Extend(buf.Size)
+	builder := dnsmessage.NewBuilder(rawBytes[:0], dnsmessage.Header{
+		ID:                 id,
+		RCode:              rcode,
+		RecursionAvailable: true,
+		Response:           true,
+	})
+	msgBytes, err := builder.Finish()
+	if err != nil {
+		newError("pack message").Base(err).WriteToLog()
+		b.Release()
+		return
+	}
+	b.Resize(0, int32(len(msgBytes)))
+
+	if err := writer.WriteMessage(b); err != nil {
+		newError("write answer").Base(err).WriteToLog()
+	}
+}
+
+// serveDoH serves DNS over HTTPS requests on the connection until it is closed.
+func (s *Server) serveDoH(conn internet.Connection, clientIP net.IP) error {
+	reader := bufio.NewReader(conn)
+	for {
+		req, err := http.ReadRequest(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return newError("failed to read DoH request").Base(err)
+		}
+
+		resp := s.answerDoH(req, clientIP)
+		resp.Request = req
+		resp.ProtoMajor = 1
+		resp.ProtoMinor = 1
+		resp.Close = req.Close
+		if err := resp.Write(conn); err != nil {
+			return newError("failed to write DoH response").Base(err)
+		}
+		if req.Close {
+			return nil
+		}
+	}
+}
+
+func newDoHResponse(status int, body []byte, contentType string) *http.Response {
+	resp := &http.Response{
+		StatusCode:    status,
+		Header:        make(http.Header),
+		ContentLength: int64(len(body)),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+	}
+	if len(contentType) > 0 {
+		resp.Header.Set("Content-Type", contentType)
+	}
+	return resp
+}
+
+func (s *Server) answerDoH(req *http.Request, clientIP net.IP) *http.Response {
+	defer req.Body.Close()
+
+	if req.URL.Path != s.dohPath {
+		return newDoHResponse(http.StatusNotFound, nil, "")
+	}
+
+	var query []byte
+	switch req.Method {
+	case http.MethodGet:
+		q, err := base64.RawURLEncoding.DecodeString(req.URL.Query().Get("dns"))
+		if err != nil {
+			return newDoHResponse(http.StatusBadRequest, nil, "")
+		}
+		query = q
+	case http.MethodPost:
+		if req.Header.Get("Content-Type") != dohMediaType {
+			return newDoHResponse(http.StatusUnsupportedMediaType, nil, "")
+		}
+		q, err := ioutil.ReadAll(io.LimitReader(req.Body, int64(buf.Size)+1))
+		if err != nil {
+			return newDoHResponse(http.StatusBadRequest, nil, "")
+		}
+		query = q
+	default:
+		return newDoHResponse(http.StatusMethodNotAllowed, nil, "")
+	}
+	if len(query) == 0 || len(query) > int(buf.Size) {
+		return newDoHResponse(http.StatusBadRequest, nil, "")
+	}
+
+	b := buf.New()
+	common.Must2(b.Write(query))
+	writer := new(answerWriter)
+	s.answer(b, clientIP, writer)
+	if writer.answer == nil {
+		return newDoHResponse(http.StatusBadGateway, nil, "")
+	}
+	defer writer.answer.Release()
+
+	return newDoHResponse(http.StatusOK, append([]byte(nil), writer.answer.Bytes()...), dohMediaType)
+}
+
+// lockedWriter serializes writing of messages.
+type lockedWriter struct {
+	sync.Mutex
+	writer dns_proto.MessageWriter
+}
+
+func (w *lockedWriter) WriteMessage(b *buf.Buffer) error {
+	w.Lock()
+	defer w.Unlock()
+	return w.writer.WriteMessage(b)
+}
+
+// answerWriter keeps the answer written into it.
+type answerWriter struct {
+	answer *buf.Buffer
+}
+
+func (w *answerWriter) WriteMessage(b *buf.Buffer) error {
+	if w.answer != nil {
+		w.answer.Release()
+	}
+	w.answer = b
+	return nil
+}
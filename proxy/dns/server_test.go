@@ -0,0 +1,180 @@
+package dns_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	dns_feature "v2ray.com/core/features/dns"
+	dns_proxy "v2ray.com/core/proxy/dns"
+)
+
+// staticClient resolves google.com to 8.8.8.8 and 2001:4860:4860::8888.
+type staticClient struct{}
+
+func (*staticClient) Type() interface{} {
+	return dns_feature.ClientType()
+}
+
+func (*staticClient) Start() error {
+	return nil
+}
+
+func (*staticClient) Close() error {
+	return nil
+}
+
+func (c *staticClient) LookupIP(domain string) ([]net.IP, error) {
+	return c.GlobalLookupIP(domain), nil
+}
+
+func (*staticClient) GlobalLookupIP(domain string) []net.IP {
+	return nil
+}
+
+func (*staticClient) LookupIPv4(domain string) ([]net.IP, error) {
+	if domain != "google.com." {
+		return nil, dns_feature.RCodeError(3)
+	}
+	return []net.IP{{8, 8, 8, 8}}, nil
+}
+
+func (*staticClient) LookupIPv6(domain string) ([]net.IP, error) {
+	if domain != "google.com." {
+		return nil, dns_feature.RCodeError(3)
+	}
+	return []net.IP{net.ParseIP("2001:4860:4860::8888")}, nil
+}
+
+func newTestServer(config *dns_proxy.ServerConfig) *dns_proxy.Server {
+	s := new(dns_proxy.Server)
+	common.Must(s.Init(config, new(staticClient)))
+	return s
+}
+
+func contextWithSource(ip net.IP) context.Context {
+	return session.ContextWithInbound(context.Background(), &session.Inbound{
+		Source: net.TCPDestination(net.IPAddress(ip), 53),
+	})
+}
+
+func writeTCPMessage(t *testing.T, conn net.Conn, m *dns.Msg) {
+	b, err := m.Pack()
+	common.Must(err)
+	msg := make([]byte, 2, 2+len(b))
+	binary.BigEndian.PutUint16(msg, uint16(len(b)))
+	if _, err := conn.Write(append(msg, b...)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTCPMessage(t *testing.T, conn net.Conn) *dns.Msg {
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, b); err != nil {
+		t.Fatal(err)
+	}
+	m := new(dns.Msg)
+	common.Must(m.Unpack(b))
+	return m
+}
+
+func TestDNSInboundTCP(t *testing.T) {
+	s := newTestServer(&dns_proxy.ServerConfig{})
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go s.Process(contextWithSource(net.IP{127, 0, 0, 1}), net.Network_TCP, server, nil)
+
+	m := new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeAAAA)
+	writeTCPMessage(t, client, m)
+	ans := readTCPMessage(t, client)
+	if ans.Id != m.Id || len(ans.Answer) != 1 {
+		t.Fatal("unexpected answer: ", ans)
+	}
+	if ip := ans.Answer[0].(*dns.AAAA).AAAA; !ip.Equal(net.ParseIP("2001:4860:4860::8888")) {
+		t.Error("unexpected IP: ", ip)
+	}
+
+	m.SetQuestion("notexist.v2fly.org.", dns.TypeA)
+	writeTCPMessage(t, client, m)
+	if ans := readTCPMessage(t, client); ans.Rcode != dns.RcodeNameError {
+		t.Error("expect NXDOMAIN, but got ", ans.Rcode)
+	}
+
+	m.SetQuestion("google.com.", dns.TypeANY)
+	writeTCPMessage(t, client, m)
+	if ans := readTCPMessage(t, client); ans.Rcode != dns.RcodeNotImplemented {
+		t.Error("expect NOTIMP, but got ", ans.Rcode)
+	}
+}
+
+func TestDNSInboundAllowedSource(t *testing.T) {
+	s := newTestServer(&dns_proxy.ServerConfig{
+		AllowedSource: []string{"192.168.0.0/16"},
+	})
+
+	client, server := net.Pipe()
+	defer client.Close()
+	if err := s.Process(contextWithSource(net.IP{10, 0, 0, 1}), net.Network_TCP, server, nil); err == nil {
+		t.Error("expect error for disallowed source, but nil")
+	}
+
+	go s.Process(contextWithSource(net.IP{192, 168, 1, 1}), net.Network_TCP, server, nil)
+	m := new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeA)
+	writeTCPMessage(t, client, m)
+	if ans := readTCPMessage(t, client); len(ans.Answer) != 1 {
+		t.Error("unexpected answer: ", ans)
+	}
+}
+
+func TestDNSInboundDoH(t *testing.T) {
+	s := newTestServer(&dns_proxy.ServerConfig{Doh: true})
+	client, server := net.Pipe()
+	defer client.Close()
+
+	go s.Process(contextWithSource(net.IP{127, 0, 0, 1}), net.Network_TCP, server, nil)
+
+	m := new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeA)
+	query, err := m.Pack()
+	common.Must(err)
+
+	reader := bufio.NewReader(client)
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("POST", "http://127.0.0.1/dns-query", bytes.NewReader(query))
+		common.Must(err)
+		req.Header.Set("Content-Type", "application/dns-message")
+		go req.Write(client)
+
+		resp, err := http.ReadResponse(reader, req)
+		common.Must(err)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal("unexpected status: ", resp.Status)
+		}
+		b, err := ioutil.ReadAll(resp.Body)
+		common.Must(err)
+		resp.Body.Close()
+
+		ans := new(dns.Msg)
+		common.Must(ans.Unpack(b))
+		if len(ans.Answer) != 1 || !ans.Answer[0].(*dns.A).A.Equal(net.IP{8, 8, 8, 8}) {
+			t.Error("unexpected answer: ", ans)
+		}
+	}
+}
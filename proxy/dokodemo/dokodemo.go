@@ -126,9 +126,17 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		}
 	}
 
+	limiter, err := policy.LimitSession(ctx, d.policyManager, d.config.UserLevel)
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	plcy := d.policy()
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+	uplink := buf.RateLimit(ctx, limiter.Uplink(), timer)
+	downlink := buf.RateLimit(ctx, limiter.Downlink(), timer)
 
 	ctx = policy.ContextWithBufferPolicy(ctx, plcy.Buffer)
 	link, err := dispatcher.Dispatch(ctx, dest)
@@ -155,7 +163,7 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 			reader = buf.NewReader(conn)
 		}
 		if !d.useRelay {
-			d.targetAddr, err = buf.SmartCopy(reader, link.Writer, d.pool, buf.UpdateActivity(timer))
+			d.targetAddr, err = buf.SmartCopy(reader, link.Writer, d.pool, buf.UpdateActivity(timer), uplink)
 			//newDebugMsg("Dokodemo: SmartCopy return buffer " + d.targetAddr)
 			if err != nil && err.Error() == "USE_RELAY" {
 				d.useRelay = true
@@ -164,17 +172,17 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		if d.useRelay {
 			if d.relayInitStep == 1 {
 				// Should have a func to create SOCKS conn, and send the unfinished request
-				_, err = buf.RelayCopy(reader, relayLink.Writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer))
+				_, err = buf.RelayCopy(reader, relayLink.Writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer), uplink)
 				if err != nil {
 					return newError("failed to transport request").Base(err)
 				}
 				d.relayInitStep++
 			} else if d.relayInitStep == 3 {
-				buf.RelayCopy(reader, relayLink.Writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer))
+				buf.RelayCopy(reader, relayLink.Writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer), uplink)
 				d.relayInitStep++
 			} else {
 				// operate as normal
-				err = buf.Copy(reader, relayLink.Writer, buf.UpdateActivity(timer))
+				err = buf.Copy(reader, relayLink.Writer, buf.UpdateActivity(timer), uplink)
 			}
 
 		}
@@ -216,7 +224,7 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 					}
 				}()
 				newDebugMsg("Dokodemo: TPROXY mode")
-				if err := buf.Copy(tReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+				if err := buf.Copy(tReader, link.Writer, buf.UpdateActivity(timer), uplink); err != nil {
 					return newError("failed to transport request (TPROXY conn)").Base(err)
 				}
 				return nil
@@ -233,11 +241,11 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 		// TODO: through the response, we may able to distinguish the blank pages
 		if !d.useRelay {
 			// commuicate as normal
-			err = buf.Copy(link.Reader, writer, buf.UpdateActivity(timer))
+			err = buf.Copy(link.Reader, writer, buf.UpdateActivity(timer), downlink)
 		}
 		if d.useRelay {
 			if d.relayInitStep == 2 {
-				buf, err := buf.RelayCopy(relayLink.Reader, writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer))
+				buf, err := buf.RelayCopy(relayLink.Reader, writer, d.relayInitStep, d.targetAddr, buf.UpdateActivity(timer), downlink)
 				if err != nil {
 					return newError("failed to transport request").Base(err)
 				}
@@ -248,7 +256,7 @@ func (d *DokodemoDoor) Process(ctx context.Context, network net.Network, conn in
 				}
 			} else if d.relayInitStep == 4 {
 				// operate as normal
-				err = buf.Copy(relayLink.Reader, writer, buf.UpdateActivity(timer))
+				err = buf.Copy(relayLink.Reader, writer, buf.UpdateActivity(timer), downlink)
 			}
 		}
 		return nil
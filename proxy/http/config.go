@@ -15,14 +15,15 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	return a, nil
 }
 
-func (sc *ServerConfig) HasAccount(username, password string) bool {
-	if sc.Accounts == nil {
-		return false
+// newUsers returns users of the server in config.
+func newUsers(config *ServerConfig) *protocol.PasswordUsers {
+	users := make([]*protocol.MemoryUser, 0, len(config.Accounts))
+	for username, password := range config.Accounts {
+		users = append(users, &protocol.MemoryUser{
+			Account: &Account{Username: username, Password: password},
+			Email:   username,
+			Level:   config.UserLevel,
+		})
 	}
-
-	p, found := sc.Accounts[username]
-	if !found {
-		return false
-	}
-	return p == password
+	return protocol.NewPasswordUsers(users, len(config.Accounts) > 0)
 }
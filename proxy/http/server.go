@@ -19,6 +19,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	http_proto "v2ray.com/core/common/protocol/http"
+	"v2ray.com/core/common/ratelimit"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/task"
@@ -30,6 +31,7 @@ import (
 // Server is an HTTP proxy server.
 type Server struct {
 	config        *ServerConfig
+	users         *protocol.PasswordUsers
 	policyManager policy.Manager
 }
 
@@ -38,6 +40,7 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	v := core.MustFromContext(ctx)
 	s := &Server{
 		config:        config,
+		users:         newUsers(config),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}
 
@@ -53,6 +56,32 @@ func (s *Server) policy() policy.Session {
 	return p
 }
 
+// userLevel returns the level of the user of the session in ctx, or the level in config if the user is unknown.
+func (s *Server) userLevel(ctx context.Context) uint32 {
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil {
+		return inbound.User.Level
+	}
+	return s.config.UserLevel
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	if _, ok := user.Account.(*Account); !ok {
+		return newError("not an HTTP account")
+	}
+	return s.users.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return s.users.Remove(email)
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return s.users.List()
+}
+
 // Network implements proxy.Inbound.
 func (*Server) Network() []net.Network {
 	return []net.Network{net.Network_TCP}
@@ -108,13 +137,17 @@ Start:
 		return trace
 	}
 
-	if len(s.config.Accounts) > 0 {
-		user, pass, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization"))
-		if !ok || !s.config.HasAccount(user, pass) {
+	if s.users.AuthRequired() {
+		username, password, ok := parseBasicAuth(request.Header.Get("Proxy-Authorization"))
+		var user *protocol.MemoryUser
+		if ok {
+			user = s.users.Get(username, password)
+		}
+		if user == nil {
 			return common.Error2(conn.Write([]byte("HTTP/1.1 407 Proxy Authentication Required\r\nProxy-Authenticate: Basic realm=\"proxy\"\r\n\r\n")))
 		}
 		if inbound != nil {
-			inbound.User.Email = user
+			inbound.User = user
 		}
 	}
 
@@ -160,7 +193,13 @@ Start:
 }
 
 func (s *Server) handleConnect(ctx context.Context, request *http.Request, reader *bufio.Reader, conn internet.Connection, dest net.Destination, dispatcher routing.Dispatcher) error {
-	_, err := conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+	limiter, err := policy.LimitSession(ctx, s.policyManager, s.userLevel(ctx))
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
+	_, err = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
 	if err != nil {
 		return newError("failed to write back OK response").Base(err)
 	}
@@ -189,14 +228,14 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 	requestDone := func() error {
 		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
 
-		return buf.Copy(buf.NewReader(conn), link.Writer, buf.UpdateActivity(timer))
+		return buf.Copy(buf.NewReader(conn), link.Writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Uplink(), timer))
 	}
 
 	responseDone := func() error {
 		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
 
 		v2writer := buf.NewWriter(conn)
-		if err := buf.Copy(link.Reader, v2writer, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(link.Reader, v2writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Downlink(), timer)); err != nil {
 			return err
 		}
 
@@ -215,6 +254,32 @@ func (s *Server) handleConnect(ctx context.Context, request *http.Request, reade
 
 var errWaitAnother = newError("keep alive")
 
+// rateLimitedWriter is an io.Writer that blocks each write until it is allowed by the limiter.
+type rateLimitedWriter struct {
+	ctx     context.Context
+	writer  io.Writer
+	limiter buf.RateLimiter
+}
+
+// newRateLimitedWriter returns the writer limited by the limiter, or the writer itself if the limiter is nil.
+func newRateLimitedWriter(ctx context.Context, writer io.Writer, limiter buf.RateLimiter) io.Writer {
+	if limiter == nil {
+		return writer
+	}
+	return &rateLimitedWriter{
+		ctx:     ctx,
+		writer:  writer,
+		limiter: limiter,
+	}
+}
+
+func (w *rateLimitedWriter) Write(b []byte) (int, error) {
+	if err := ratelimit.Sleep(w.ctx, w.limiter.Reserve(int64(len(b))), nil); err != nil {
+		return 0, err
+	}
+	return w.writer.Write(b)
+}
+
 func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, writer io.Writer, dest net.Destination, dispatcher routing.Dispatcher) error {
 	if !s.config.AllowTransparent && request.URL.Host == "" {
 		// RFC 2068 (HTTP/1.1) requires URL to be absolute URL in HTTP proxy.
@@ -257,6 +322,12 @@ func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, wri
 
 	ctx = session.ContextWithContent(ctx, content)
 
+	limiter, err := policy.LimitSession(ctx, s.policyManager, s.userLevel(ctx))
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	link, err := dispatcher.Dispatch(ctx, dest)
 	if err != nil {
 		return err
@@ -271,7 +342,7 @@ func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, wri
 
 		requestWriter := buf.NewBufferedWriter(link.Writer)
 		common.Must(requestWriter.SetBuffered(false))
-		if err := request.Write(requestWriter); err != nil {
+		if err := request.Write(newRateLimitedWriter(ctx, requestWriter, limiter.Uplink())); err != nil {
 			return newError("failed to write whole request").Base(err).AtWarning()
 		}
 		return nil
@@ -307,7 +378,7 @@ func (s *Server) handlePlainHTTP(ctx context.Context, request *http.Request, wri
 			response.Header.Set("Connection", "close")
 			response.Header.Set("Proxy-Connection", "close")
 		}
-		if err := response.Write(writer); err != nil {
+		if err := response.Write(newRateLimitedWriter(ctx, writer, limiter.Downlink())); err != nil {
 			return newError("failed to write response").Base(err).AtWarning()
 		}
 		return nil
@@ -0,0 +1,114 @@
+package http_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
+	. "v2ray.com/core/proxy/http"
+	"v2ray.com/core/proxy/socks"
+	"v2ray.com/core/testing/proxytest"
+)
+
+func newServer(config *ServerConfig) *Server {
+	return proxytest.NewInbound(config).(*Server)
+}
+
+// connect sends a CONNECT request with the username and password if not empty, and returns the user of the session,
+// or nil if the server rejects the client.
+func connect(t *testing.T, s *Server, username, password string) *protocol.MemoryUser {
+	return proxytest.ProcessUser(s, &session.Inbound{}, func(client net.Conn) {
+		request := "CONNECT v2fly.org:443 HTTP/1.1\r\nHost: v2fly.org:443\r\n"
+		if len(username) > 0 {
+			request += "Proxy-Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)) + "\r\n"
+		}
+		common.Must2(client.Write([]byte(request + "\r\n")))
+
+		if _, err := http.ReadResponse(bufio.NewReader(client), nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestServerUsers(t *testing.T) {
+	s := newServer(&ServerConfig{
+		Accounts:  map[string]string{"a": "pa"},
+		UserLevel: 1,
+	})
+
+	if user := connect(t, s, "a", "pa"); user == nil || user.Email != "a" || user.Level != 1 {
+		t.Error("expect user a, but got ", user)
+	}
+	if user := connect(t, s, "a", "wrong"); user != nil {
+		t.Error("expect wrong password rejected, but got ", user)
+	}
+	if user := connect(t, s, "", ""); user != nil {
+		t.Error("expect no auth rejected, but got ", user)
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "b", Password: "pb"},
+		Email:   "b@v2ray.com",
+		Level:   2,
+	}))
+	if user := connect(t, s, "b", "pb"); user == nil || user.Email != "b@v2ray.com" || user.Level != 2 {
+		t.Error("expect user b@v2ray.com, but got ", user)
+	}
+	if err := s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "c", Password: "pc"},
+		Email:   "B@v2ray.com",
+	}); err == nil {
+		t.Error("expect error for duplicate email")
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "c", Password: "pc"},
+	}))
+	if user := connect(t, s, "c", "pc"); user == nil || user.Email != "c" {
+		t.Error("expect user c with username as email, but got ", user)
+	}
+
+	if n := len(s.GetUsers(context.Background())); n != 3 {
+		t.Error("expect 3 users, but got ", n)
+	}
+
+	common.Must(s.RemoveUser(context.Background(), "b@v2ray.com"))
+	if user := connect(t, s, "b", "pb"); user != nil {
+		t.Error("expect removed user rejected, but got ", user)
+	}
+	if err := s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &socks.Account{Username: "d", Password: "pd"},
+	}); err == nil {
+		t.Error("expect error for account of other protocols")
+	}
+}
+
+func TestServerAuthRequiredAfterAddUser(t *testing.T) {
+	s := newServer(&ServerConfig{})
+
+	if user := connect(t, s, "", ""); user == nil {
+		t.Error("expect no auth accepted")
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "a", Password: "pa"},
+	}))
+	if user := connect(t, s, "", ""); user != nil {
+		t.Error("expect no auth rejected after adding a user, but got ", user)
+	}
+	if user := connect(t, s, "a", "pa"); user == nil || user.Email != "a" {
+		t.Error("expect user a, but got ", user)
+	}
+
+	// Removing all users doesn't open the server.
+	common.Must(s.RemoveUser(context.Background(), "a"))
+	if user := connect(t, s, "", ""); user != nil {
+		t.Error("expect no auth rejected after removing all users, but got ", user)
+	}
+}
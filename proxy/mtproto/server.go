@@ -5,6 +5,9 @@ package mtproto
 import (
 	"bytes"
 	"context"
+	"crypto/cipher"
+	"strings"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
@@ -32,9 +35,12 @@ var (
 )
 
 type Server struct {
-	user    *protocol.User
-	account *Account
-	policy  policy.Manager
+	sync.RWMutex
+	users  []*protocol.MemoryUser
+	policy policy.Manager
+	// level is the level of the first configured user. The user of a connection is unknown until its header is
+	// decoded, so the handshake timeout of this level is used.
+	level uint32
 }
 
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
@@ -42,23 +48,87 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 		return nil, newError("no user configured.")
 	}
 
-	user := config.User[0]
-	rawAccount, err := config.User[0].GetTypedAccount()
-	if err != nil {
-		return nil, newError("invalid account").Base(err)
+	v := core.MustFromContext(ctx)
+	s := &Server{
+		policy: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		level:  config.User[0].Level,
 	}
-	account, ok := rawAccount.(*Account)
-	if !ok {
-		return nil, newError("not a MTProto account")
+
+	for _, user := range config.User {
+		mUser, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, newError("invalid account").Base(err)
+		}
+		if err := s.AddUser(ctx, mUser); err != nil {
+			return nil, err
+		}
 	}
 
-	v := core.MustFromContext(ctx)
+	return s, nil
+}
 
-	return &Server{
-		user:    user,
-		account: account,
-		policy:  v.GetFeature(policy.ManagerType()).(policy.Manager),
-	}, nil
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	if _, ok := user.Account.(*Account); !ok {
+		return newError("not a MTProto account")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for _, u := range s.users {
+		if len(user.Email) > 0 && strings.EqualFold(u.Email, user.Email) {
+			return newError("User ", user.Email, " already exists.")
+		}
+	}
+	s.users = append(s.users, user)
+	return nil
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	if email == "" {
+		return newError("Email must not be empty.")
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	for i, u := range s.users {
+		if strings.EqualFold(u.Email, email) {
+			s.users = append(s.users[:i:i], s.users[i+1:]...)
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	s.RLock()
+	defer s.RUnlock()
+
+	return append([]*protocol.MemoryUser(nil), s.users...)
+}
+
+// authenticate finds the user whose secret decodes the header of auth into a valid connection type. It applies the
+// secret and decodes the header in auth, and returns the decryptor of the connection.
+func (s *Server) authenticate(auth *Authentication) (*protocol.MemoryUser, cipher.Stream) {
+	s.RLock()
+	defer s.RUnlock()
+
+	for _, user := range s.users {
+		a := *auth
+		a.ApplySecret(user.Account.(*Account).Secret)
+
+		decryptor := crypto.NewAesCTRStream(a.DecodingKey[:], a.DecodingNonce[:])
+		decryptor.XORKeyStream(a.Header[:], a.Header[:])
+		if isValidConnectionType(a.ConnectionType()) {
+			*auth = a
+			return user, decryptor
+		}
+	}
+	return nil, nil
 }
 
 func (s *Server) Network() []net.Network {
@@ -79,9 +149,7 @@ func isValidConnectionType(c [4]byte) bool {
 }
 
 func (s *Server) Process(ctx context.Context, network net.Network, conn internet.Connection, dispatcher routing.Dispatcher) error {
-	sPolicy := s.policy.ForLevel(s.user.Level)
-
-	if err := conn.SetDeadline(time.Now().Add(sPolicy.Timeouts.Handshake)); err != nil {
+	if err := conn.SetDeadline(time.Now().Add(s.policy.ForLevel(s.level).Timeouts.Handshake)); err != nil {
 		newError("failed to set deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 	auth, err := ReadAuthentication(conn)
@@ -94,15 +162,15 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		newError("failed to clear deadline").Base(err).WriteToLog(session.ExportIDToError(ctx))
 	}
 
-	auth.ApplySecret(s.account.Secret)
-
-	decryptor := crypto.NewAesCTRStream(auth.DecodingKey[:], auth.DecodingNonce[:])
-	decryptor.XORKeyStream(auth.Header[:], auth.Header[:])
-
-	ct := auth.ConnectionType()
-	if !isValidConnectionType(ct) {
-		return newError("invalid connection type: ", ct)
+	user, decryptor := s.authenticate(auth)
+	if user == nil {
+		return newError("invalid connection type: ", auth.ConnectionType())
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		inbound.User = user
 	}
+	sPolicy := s.policy.ForLevel(user.Level)
+	ct := auth.ConnectionType()
 
 	dcID := auth.DataCenterID()
 	if dcID >= uint16(len(dcList)) {
@@ -115,6 +183,12 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		Port:    net.Port(443),
 	}
 
+	limiter, err := policy.LimitSession(ctx, s.policy, user.Level)
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, sPolicy.Timeouts.ConnectionIdle)
 	ctx = policy.ContextWithBufferPolicy(ctx, sPolicy.Buffer)
@@ -134,7 +208,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 		defer timer.SetTimeout(sPolicy.Timeouts.DownlinkOnly)
 
 		reader := buf.NewReader(crypto.NewCryptionReader(decryptor, conn))
-		return buf.Copy(reader, link.Writer, buf.UpdateActivity(timer))
+		return buf.Copy(reader, link.Writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Uplink(), timer))
 	}
 
 	response := func() error {
@@ -142,7 +216,7 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 
 		encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
 		writer := buf.NewWriter(crypto.NewCryptionWriter(encryptor, conn))
-		return buf.Copy(link.Reader, writer, buf.UpdateActivity(timer))
+		return buf.Copy(link.Reader, writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Downlink(), timer))
 	}
 
 	var responseDoneAndCloseWriter = task.OnSuccess(response, task.Close(link.Writer))
@@ -0,0 +1,99 @@
+package mtproto_test
+
+import (
+	"context"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/crypto"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/session"
+	. "v2ray.com/core/proxy/mtproto"
+	"v2ray.com/core/testing/proxytest"
+)
+
+func newServer(config *ServerConfig) *Server {
+	return proxytest.NewInbound(config).(*Server)
+}
+
+// connect sends a header encrypted with the secret, and returns the user of the session, or nil if the server
+// rejects the client.
+func connect(t *testing.T, s *Server, secret []byte) *protocol.MemoryUser {
+	return proxytest.ProcessUser(s, &session.Inbound{}, func(client net.Conn) {
+		auth := NewAuthentication(DefaultSessionContext())
+		// Data center 1.
+		auth.Header[60] = 1
+		auth.Header[61] = 0
+		auth.ApplySecret(secret)
+
+		encryptor := crypto.NewAesCTRStream(auth.EncodingKey[:], auth.EncodingNonce[:])
+		var header [HeaderSize]byte
+		encryptor.XORKeyStream(header[:], auth.Header[:])
+		copy(header[:56], auth.Header[:])
+		if _, err := client.Write(header[:]); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestServerUsers(t *testing.T) {
+	s := newServer(&ServerConfig{
+		User: []*protocol.User{
+			{
+				Email:   "a@v2ray.com",
+				Level:   1,
+				Account: serial.ToTypedMessage(&Account{Secret: []byte("0123456789abcdef")}),
+			},
+		},
+	})
+
+	if user := connect(t, s, []byte("0123456789abcdef")); user == nil || user.Email != "a@v2ray.com" || user.Level != 1 {
+		t.Error("expect user a@v2ray.com, but got ", user)
+	}
+	if user := connect(t, s, []byte("fedcba9876543210")); user != nil {
+		t.Error("expect wrong secret rejected, but got ", user)
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Secret: []byte("fedcba9876543210")},
+		Email:   "b@v2ray.com",
+		Level:   2,
+	}))
+	if user := connect(t, s, []byte("fedcba9876543210")); user == nil || user.Email != "b@v2ray.com" || user.Level != 2 {
+		t.Error("expect user b@v2ray.com, but got ", user)
+	}
+	if err := s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Secret: []byte("0000000000000000")},
+		Email:   "B@v2ray.com",
+	}); err == nil {
+		t.Error("expect error for duplicate email")
+	}
+
+	// Users without email can be added, but not removed.
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Secret: []byte("1111111111111111")},
+	}))
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Secret: []byte("2222222222222222")},
+	}))
+	if user := connect(t, s, []byte("2222222222222222")); user == nil || user.Email != "" {
+		t.Error("expect user without email, but got ", user)
+	}
+	if err := s.RemoveUser(context.Background(), ""); err == nil {
+		t.Error("expect error for empty email")
+	}
+
+	if n := len(s.GetUsers(context.Background())); n != 4 {
+		t.Error("expect 4 users, but got ", n)
+	}
+
+	common.Must(s.RemoveUser(context.Background(), "B@v2ray.com"))
+	if user := connect(t, s, []byte("fedcba9876543210")); user != nil {
+		t.Error("expect removed user rejected, but got ", user)
+	}
+	if err := s.RemoveUser(context.Background(), "b@v2ray.com"); err == nil {
+		t.Error("expect error for removed user")
+	}
+}
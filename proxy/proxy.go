@@ -39,6 +39,12 @@ type UserManager interface {
 	RemoveUser(context.Context, string) error
 }
 
+// UserLister is the interface for UserManagers that can list their users.
+type UserLister interface {
+	// GetUsers returns all users.
+	GetUsers(context.Context) []*protocol.MemoryUser
+}
+
 type GetInbound interface {
 	GetInbound() Inbound
 }
@@ -106,7 +106,14 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 
 	if request.Command == protocol.RequestCommandTCP {
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		bodyWriter, err := WriteTCPRequest(request, bufferedWriter)
+		var bodyWriter buf.Writer
+		var requestSalt []byte
+		var err error
+		if _, ok := account.Cipher.(*AEAD2022Cipher); ok {
+			bodyWriter, requestSalt, err = WriteTCPRequest2022(request, bufferedWriter)
+		} else {
+			bodyWriter, err = WriteTCPRequest(request, bufferedWriter)
+		}
 		if err != nil {
 			return newError("failed to write request").Base(err)
 		}
@@ -123,7 +130,13 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		responseDone := func() error {
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
-			responseReader, err := ReadTCPResponse(user, conn)
+			var responseReader buf.Reader
+			var err error
+			if requestSalt != nil {
+				responseReader, err = ReadTCPResponse2022(user, requestSalt, conn)
+			} else {
+				responseReader, err = ReadTCPResponse(user, conn)
+			}
 			if err != nil {
 				return err
 			}
@@ -141,10 +154,30 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 
 	if request.Command == protocol.RequestCommandUDP {
 
-		writer := &buf.SequentialWriter{Writer: &UDPWriter{
-			Writer:  conn,
-			Request: request,
-		}}
+		var writer buf.Writer
+		var reader buf.Reader
+		if _, ok := account.Cipher.(*AEAD2022Cipher); ok {
+			udpSession := NewUDPSession2022()
+			writer = &buf.SequentialWriter{Writer: &UDPWriter2022{
+				Writer:  conn,
+				Request: request,
+				Session: udpSession,
+			}}
+			reader = &UDPReader2022{
+				Reader:  conn,
+				User:    user,
+				Session: udpSession,
+			}
+		} else {
+			writer = &buf.SequentialWriter{Writer: &UDPWriter{
+				Writer:  conn,
+				Request: request,
+			}}
+			reader = &UDPReader{
+				Reader: conn,
+				User:   user,
+			}
+		}
 
 		requestDone := func() error {
 			defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
@@ -158,11 +191,6 @@ func (c *Client) Process(ctx context.Context, link *transport.Link, dialer inter
 		responseDone := func() error {
 			defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
-			reader := &UDPReader{
-				Reader: conn,
-				User:   user,
-			}
-
 			if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
 				return newError("failed to transport all UDP response").Base(err)
 			}
@@ -6,10 +6,13 @@ import (
 	"crypto/cipher"
 	"crypto/md5"
 	"crypto/sha1"
+	"encoding/base64"
 	"io"
+	"strings"
 
 	"golang.org/x/crypto/chacha20poly1305"
 	"golang.org/x/crypto/hkdf"
+	"lukechampine.com/blake3"
 
 	"v2ray.com/core/common"
 	"v2ray.com/core/common/buf"
@@ -22,6 +25,8 @@ type MemoryAccount struct {
 	Cipher      Cipher
 	Key         []byte
 	OneTimeAuth Account_OneTimeAuth
+	// IdentityKeys are the identity keys of servers that a client of a 2022 cipher sends identity headers for.
+	IdentityKeys [][]byte
 }
 
 // Equals implements protocol.Account.Equals().
@@ -46,6 +51,12 @@ func createChacha20Poly1305(key []byte) cipher.AEAD {
 	return chacha20
 }
 
+func createXChacha20Poly1305(key []byte) cipher.AEAD {
+	xchacha20, err := chacha20poly1305.NewX(key)
+	common.Must(err)
+	return xchacha20
+}
+
 func (a *Account) getCipher() (Cipher, error) {
 	switch a.CipherType {
 	case CipherType_AES_128_CFB:
@@ -76,6 +87,22 @@ func (a *Account) getCipher() (Cipher, error) {
 		}, nil
 	case CipherType_NONE:
 		return NoneCipher{}, nil
+	case CipherType_BLAKE3_AES_128_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        16,
+			AEADAuthCreator: createAesGcm,
+		}, nil
+	case CipherType_BLAKE3_AES_256_GCM:
+		return &AEAD2022Cipher{
+			KeyBytes:        32,
+			AEADAuthCreator: createAesGcm,
+		}, nil
+	case CipherType_BLAKE3_CHACHA20_POLY1305:
+		return &AEAD2022Cipher{
+			KeyBytes:           32,
+			AEADAuthCreator:    createChacha20Poly1305,
+			UDPAEADAuthCreator: createXChacha20Poly1305,
+		}, nil
 	default:
 		return nil, newError("Unsupported cipher.")
 	}
@@ -87,6 +114,20 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	if err != nil {
 		return nil, newError("failed to get cipher").Base(err)
 	}
+	if cipher2022, ok := cipher.(*AEAD2022Cipher); ok {
+		keys, err := passwordToKeys2022(a.Password, cipher.KeySize())
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) > 1 && cipher2022.UDPAEADAuthCreator != nil {
+			return nil, newError("identity keys are supported with AES ciphers only")
+		}
+		return &MemoryAccount{
+			Cipher:       cipher,
+			Key:          keys[len(keys)-1],
+			IdentityKeys: keys[:len(keys)-1],
+		}, nil
+	}
 	return &MemoryAccount{
 		Cipher:      cipher,
 		Key:         passwordToCipherKey([]byte(a.Password), cipher.KeySize()),
@@ -220,6 +261,58 @@ func (c *AEADCipher) DecodePacket(key []byte, b *buf.Buffer) error {
 	return nil
 }
 
+// AEAD2022Cipher represents all ciphers of Shadowsocks 2022. Its streams are chunked in the same way as AEADCipher,
+// with subkeys derived by BLAKE3, but its headers and UDP packets have their own formats, see protocol2022.go.
+type AEAD2022Cipher struct {
+	KeyBytes        int32
+	AEADAuthCreator func(key []byte) cipher.AEAD
+	// UDPAEADAuthCreator creates the AEAD that seals whole UDP packets with the key. If it is nil, UDP packets have
+	// separate headers encrypted by AES.
+	UDPAEADAuthCreator func(key []byte) cipher.AEAD
+}
+
+func (*AEAD2022Cipher) IsAEAD() bool {
+	return true
+}
+
+func (c *AEAD2022Cipher) KeySize() int32 {
+	return c.KeyBytes
+}
+
+// IVSize returns the size of salts, which is the same as the key size.
+func (c *AEAD2022Cipher) IVSize() int32 {
+	return c.KeyBytes
+}
+
+func (c *AEAD2022Cipher) createAuthenticator(key []byte, salt []byte) *crypto.AEADAuthenticator {
+	return &crypto.AEADAuthenticator{
+		AEAD:           c.AEADAuthCreator(sessionKey2022(key, salt, c.KeyBytes)),
+		NonceGenerator: crypto.GenerateInitialAEADNonce(),
+	}
+}
+
+func (c *AEAD2022Cipher) NewEncryptionWriter(key []byte, iv []byte, writer io.Writer) (buf.Writer, error) {
+	auth := c.createAuthenticator(key, iv)
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream, nil), nil
+}
+
+func (c *AEAD2022Cipher) NewDecryptionReader(key []byte, iv []byte, reader io.Reader) (buf.Reader, error) {
+	auth := c.createAuthenticator(key, iv)
+	return crypto.NewAuthenticationReader(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, reader, protocol.TransferTypeStream, nil), nil
+}
+
+func (c *AEAD2022Cipher) EncodePacket(key []byte, b *buf.Buffer) error {
+	return newError("UDP packets of Shadowsocks 2022 belong to sessions")
+}
+
+func (c *AEAD2022Cipher) DecodePacket(key []byte, b *buf.Buffer) error {
+	return newError("UDP packets of Shadowsocks 2022 belong to sessions")
+}
+
 type ChaCha20 struct {
 	IVBytes int32
 }
@@ -305,6 +398,51 @@ func passwordToCipherKey(password []byte, keySize int32) []byte {
 	return key
 }
 
+// passwordToKeys2022 decodes the keys in the password of a 2022 cipher, which are the identity keys of servers if
+// any, and then the key of the user, separated by colons.
+func passwordToKeys2022(password string, keySize int32) ([][]byte, error) {
+	var keys [][]byte
+	for _, encoded := range strings.Split(password, ":") {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, newError("failed to decode key in base64").Base(err)
+		}
+		if int32(len(key)) != keySize {
+			return nil, newError("invalid key size: ", len(key), ", expecting ", keySize)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// sessionKey2022 derives the subkey of a session from the key and the salt, or the session ID for UDP.
+func sessionKey2022(key []byte, salt []byte, keySize int32) []byte {
+	material := make([]byte, 0, len(key)+len(salt))
+	material = append(material, key...)
+	material = append(material, salt...)
+	subkey := make([]byte, keySize)
+	blake3.DeriveKey(subkey, "shadowsocks 2022 session subkey", material)
+	return subkey
+}
+
+// identityKey2022 derives the subkey that identity headers after the salt are encrypted with.
+func identityKey2022(identityKey []byte, salt []byte) []byte {
+	material := make([]byte, 0, len(identityKey)+len(salt))
+	material = append(material, identityKey...)
+	material = append(material, salt...)
+	subkey := make([]byte, len(identityKey))
+	blake3.DeriveKey(subkey, "shadowsocks 2022 identity subkey", material)
+	return subkey
+}
+
+// userHash2022 returns the hash of the key of a user, that identity headers carry.
+func userHash2022(key []byte) [16]byte {
+	var hash [16]byte
+	sum := blake3.Sum256(key)
+	copy(hash[:], sum[:16])
+	return hash
+}
+
 func hkdfSHA1(secret, salt, outkey []byte) {
 	r := hkdf.New(sha1.New, secret, salt, []byte("ss-subkey"))
 	common.Must2(io.ReadFull(r, outkey))
@@ -0,0 +1,739 @@
+// +build !confonly
+
+package shadowsocks
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/crypto"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/task"
+)
+
+// Shadowsocks 2022 (SIP022) sessions. A TCP request is the salt, identity headers if any, the sealed fixed header of
+// type, timestamp and length, the sealed variable header of address, padding and initial payload, and then chunks
+// as in AEAD ciphers. A TCP response is the salt, the sealed fixed header which also carries the salt of the request,
+// and the first chunk, and then chunks. All of them share the nonce sequence of the session.
+
+const (
+	headerType2022Client byte = 0
+	headerType2022Server byte = 1
+
+	// maxTimeDiff2022 is the maximum difference in seconds between the timestamp of a header and the local time.
+	maxTimeDiff2022 = 30
+	// saltReplayInterval2022 is the interval in seconds that salts of TCP sessions are remembered for.
+	saltReplayInterval2022 = 60
+	maxPaddingLength2022   = 900
+	// maxRemoteSessions2022 is the max number of remote sessions whose packets a UDP session accepts.
+	maxRemoteSessions2022 = 16
+	// udpSessionTimeout2022 is how long a UDP session of the server is kept without packets.
+	udpSessionTimeout2022 = 5 * time.Minute
+)
+
+func writeTimestamp2022(b []byte) {
+	binary.BigEndian.PutUint64(b, uint64(time.Now().Unix()))
+}
+
+func checkTimestamp2022(b []byte) error {
+	diff := time.Now().Unix() - int64(binary.BigEndian.Uint64(b))
+	if diff > maxTimeDiff2022 || diff < -maxTimeDiff2022 {
+		return newError("timestamp is off by ", diff, " seconds")
+	}
+	return nil
+}
+
+// writeIdentityHeaders2022 writes an identity header for each identity key of the account, which is the hash of the
+// next key encrypted with a subkey of the identity key.
+func writeIdentityHeaders2022(b *buf.Buffer, account *MemoryAccount, salt []byte) error {
+	for i, identityKey := range account.IdentityKeys {
+		next := account.Key
+		if i+1 < len(account.IdentityKeys) {
+			next = account.IdentityKeys[i+1]
+		}
+		block, err := aes.NewCipher(identityKey2022(identityKey, salt))
+		if err != nil {
+			return newError("failed to create identity header cipher").Base(err)
+		}
+		hash := userHash2022(next)
+		block.Encrypt(b.Extend(16), hash[:])
+	}
+	return nil
+}
+
+func get2022Cipher(account *MemoryAccount) (*AEAD2022Cipher, error) {
+	cipher, ok := account.Cipher.(*AEAD2022Cipher)
+	if !ok {
+		return nil, newError("not a Shadowsocks 2022 cipher")
+	}
+	return cipher, nil
+}
+
+func newChunkReader2022(auth *crypto.AEADAuthenticator, reader io.Reader, first []byte) buf.Reader {
+	return &buf.BufferedReader{
+		Reader: crypto.NewAuthenticationReader(auth, &crypto.AEADChunkSizeParser{
+			Auth: auth,
+		}, reader, protocol.TransferTypeStream, nil),
+		Buffer: buf.MergeBytes(nil, first),
+	}
+}
+
+func newChunkWriter2022(auth *crypto.AEADAuthenticator, writer io.Writer) buf.Writer {
+	return crypto.NewAuthenticationWriter(auth, &crypto.AEADChunkSizeParser{
+		Auth: auth,
+	}, writer, protocol.TransferTypeStream, nil)
+}
+
+// ReadTCPSession2022 reads the request of a TCP session of a 2022 cipher, whose identity headers are already removed.
+// The salt of the session is rejected if the filter has seen it. It returns the header, the reader of the body, and
+// the salt that the response refers to.
+func ReadTCPSession2022(user *protocol.MemoryUser, reader io.Reader, filter *antireplay.ReplayFilter) (*protocol.RequestHeader, buf.Reader, []byte, error) {
+	account := user.Account.(*MemoryAccount)
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	salt := make([]byte, cipher.IVSize())
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return nil, nil, nil, newError("failed to read salt").Base(err)
+	}
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	fixedHeader := make([]byte, 1+8+2+auth.Overhead())
+	if _, err := io.ReadFull(reader, fixedHeader); err != nil {
+		return nil, nil, nil, newError("failed to read header").Base(err)
+	}
+	fixedHeader, err = auth.Open(fixedHeader[:0], fixedHeader)
+	if err != nil {
+		return nil, nil, nil, newError("failed to decrypt header").Base(err)
+	}
+	if fixedHeader[0] != headerType2022Client {
+		return nil, nil, nil, newError("unexpected header type: ", fixedHeader[0])
+	}
+	if err := checkTimestamp2022(fixedHeader[1:9]); err != nil {
+		return nil, nil, nil, err
+	}
+	if !filter.Check(salt) {
+		return nil, nil, nil, newError("replayed salt")
+	}
+
+	variableHeader := make([]byte, int(binary.BigEndian.Uint16(fixedHeader[9:11]))+auth.Overhead())
+	if _, err := io.ReadFull(reader, variableHeader); err != nil {
+		return nil, nil, nil, newError("failed to read header").Base(err)
+	}
+	variableHeader, err = auth.Open(variableHeader[:0], variableHeader)
+	if err != nil {
+		return nil, nil, nil, newError("failed to decrypt header").Base(err)
+	}
+
+	headerReader := bytes.NewReader(variableHeader)
+	addr, port, err := addrParser.ReadAddressPort(nil, headerReader)
+	if err != nil {
+		return nil, nil, nil, newError("failed to read address").Base(err)
+	}
+	var paddingLen uint16
+	if err := binary.Read(headerReader, binary.BigEndian, &paddingLen); err != nil {
+		return nil, nil, nil, newError("failed to read padding length").Base(err)
+	}
+	if int(paddingLen) > headerReader.Len() {
+		return nil, nil, nil, newError("invalid padding length: ", paddingLen)
+	}
+	payload := variableHeader[len(variableHeader)-headerReader.Len()+int(paddingLen):]
+	if paddingLen == 0 && len(payload) == 0 {
+		return nil, nil, nil, newError("header has neither padding nor payload")
+	}
+
+	request := &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandTCP,
+		Address: addr,
+		Port:    port,
+	}
+	return request, newChunkReader2022(auth, reader, payload), salt, nil
+}
+
+// WriteTCPRequest2022 writes the request of a TCP session of a 2022 cipher, with identity headers for the identity
+// keys of the account. It returns the writer of the body, and the salt of the session.
+func WriteTCPRequest2022(request *protocol.RequestHeader, writer io.Writer) (buf.Writer, []byte, error) {
+	account := request.User.Account.(*MemoryAccount)
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := buf.New()
+	defer header.Release()
+
+	salt := make([]byte, cipher.IVSize())
+	common.Must2(rand.Read(salt))
+	header.Write(salt)
+	if err := writeIdentityHeaders2022(header, account, salt); err != nil {
+		return nil, nil, err
+	}
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	variableHeader := buf.New()
+	defer variableHeader.Release()
+
+	if err := addrParser.WriteAddressPort(variableHeader, request.Address, request.Port); err != nil {
+		return nil, nil, newError("failed to write address").Base(err)
+	}
+	paddingLen := dice.Roll(maxPaddingLength2022) + 1
+	binary.BigEndian.PutUint16(variableHeader.Extend(2), uint16(paddingLen))
+	common.Must2(variableHeader.ReadFullFrom(rand.Reader, int32(paddingLen)))
+
+	fixedHeader := make([]byte, 1+8+2)
+	fixedHeader[0] = headerType2022Client
+	writeTimestamp2022(fixedHeader[1:9])
+	binary.BigEndian.PutUint16(fixedHeader[9:11], uint16(variableHeader.Len()))
+
+	overhead := int32(auth.Overhead())
+	sealedFixedHeader := header.Extend(int32(len(fixedHeader)) + overhead)
+	common.Must2(auth.Seal(sealedFixedHeader[:0], fixedHeader))
+	sealedVariableHeader := header.Extend(variableHeader.Len() + overhead)
+	common.Must2(auth.Seal(sealedVariableHeader[:0], variableHeader.Bytes()))
+
+	if err := buf.WriteAllBytes(writer, header.Bytes()); err != nil {
+		return nil, nil, newError("failed to write header").Base(err)
+	}
+
+	return newChunkWriter2022(auth, writer), salt, nil
+}
+
+// ReadTCPResponse2022 reads the response of a TCP session of a 2022 cipher, that must refer to the salt of the
+// request.
+func ReadTCPResponse2022(user *protocol.MemoryUser, requestSalt []byte, reader io.Reader) (buf.Reader, error) {
+	account := user.Account.(*MemoryAccount)
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, cipher.IVSize())
+	if _, err := io.ReadFull(reader, salt); err != nil {
+		return nil, newError("failed to read salt").Base(err)
+	}
+	auth := cipher.createAuthenticator(account.Key, salt)
+
+	fixedHeader := make([]byte, 1+8+len(requestSalt)+2+auth.Overhead())
+	if _, err := io.ReadFull(reader, fixedHeader); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+	fixedHeader, err = auth.Open(fixedHeader[:0], fixedHeader)
+	if err != nil {
+		return nil, newError("failed to decrypt header").Base(err)
+	}
+	if fixedHeader[0] != headerType2022Server {
+		return nil, newError("unexpected header type: ", fixedHeader[0])
+	}
+	if err := checkTimestamp2022(fixedHeader[1:9]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(fixedHeader[9:9+len(requestSalt)], requestSalt) {
+		return nil, newError("response to another request")
+	}
+
+	first := make([]byte, int(binary.BigEndian.Uint16(fixedHeader[9+len(requestSalt):]))+auth.Overhead())
+	if _, err := io.ReadFull(reader, first); err != nil {
+		return nil, newError("failed to read first chunk").Base(err)
+	}
+	first, err = auth.Open(first[:0], first)
+	if err != nil {
+		return nil, newError("failed to decrypt first chunk").Base(err)
+	}
+
+	return newChunkReader2022(auth, reader, first), nil
+}
+
+// WriteTCPResponse2022 returns the writer of the response of a TCP session of a 2022 cipher. The header of the
+// response is written along with the first chunk.
+func WriteTCPResponse2022(request *protocol.RequestHeader, requestSalt []byte, writer io.Writer) (buf.Writer, error) {
+	account := request.User.Account.(*MemoryAccount)
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, cipher.IVSize())
+	common.Must2(rand.Read(salt))
+	return &tcpResponseWriter2022{
+		writer:      writer,
+		auth:        cipher.createAuthenticator(account.Key, salt),
+		salt:        salt,
+		requestSalt: requestSalt,
+	}, nil
+}
+
+type tcpResponseWriter2022 struct {
+	writer      io.Writer
+	auth        *crypto.AEADAuthenticator
+	salt        []byte
+	requestSalt []byte
+	body        buf.Writer
+}
+
+// WriteMultiBuffer implements buf.Writer.
+func (w *tcpResponseWriter2022) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if w.body != nil {
+		return w.body.WriteMultiBuffer(mb)
+	}
+
+	mb, first := buf.SplitSize(mb, buf.Size)
+	payload := make([]byte, first.Len())
+	first.Copy(payload)
+	buf.ReleaseMulti(first)
+
+	fixedHeader := make([]byte, 1+8+len(w.requestSalt)+2)
+	fixedHeader[0] = headerType2022Server
+	writeTimestamp2022(fixedHeader[1:9])
+	copy(fixedHeader[9:], w.requestSalt)
+	binary.BigEndian.PutUint16(fixedHeader[9+len(w.requestSalt):], uint16(len(payload)))
+
+	overhead := w.auth.Overhead()
+	data := make([]byte, 0, len(w.salt)+len(fixedHeader)+len(payload)+2*overhead)
+	data = append(data, w.salt...)
+	data = common.Must2(w.auth.Seal(data, fixedHeader)).([]byte)
+	data = common.Must2(w.auth.Seal(data, payload)).([]byte)
+
+	w.body = newChunkWriter2022(w.auth, w.writer)
+	if err := buf.WriteAllBytes(w.writer, data); err != nil {
+		buf.ReleaseMulti(mb)
+		return newError("failed to write header").Base(err)
+	}
+	if mb.IsEmpty() {
+		return nil
+	}
+	return w.body.WriteMultiBuffer(mb)
+}
+
+// UDP packets of 2022 ciphers belong to sessions. For AES ciphers, a packet is the separate header of session ID and
+// packet ID encrypted by AES, identity headers if any, and the body sealed with the subkey of the session. For
+// ChaCha20-Poly1305, a packet is a nonce, and the separate header and the body sealed by XChaCha20-Poly1305.
+
+// packetWindow2022 rejects replayed packet IDs of a session, in a sliding window.
+type packetWindow2022 struct {
+	last   uint64
+	blocks [packetWindowBlocks2022]uint64
+	// lastSeen is when a packet of the session is last received.
+	lastSeen time.Time
+}
+
+const (
+	packetWindowBlocks2022 = 64
+	packetWindowSize2022   = (packetWindowBlocks2022 - 1) * 64
+)
+
+// check marks the packet ID as seen. It returns false if the packet ID is seen already, or too old.
+func (w *packetWindow2022) check(id uint64) bool {
+	block := id / 64
+	if id > w.last {
+		current := w.last / 64
+		diff := block - current
+		if diff > packetWindowBlocks2022 {
+			diff = packetWindowBlocks2022
+		}
+		for i := uint64(1); i <= diff; i++ {
+			w.blocks[(current+i)%packetWindowBlocks2022] = 0
+		}
+		w.last = id
+	} else if w.last-id > packetWindowSize2022 {
+		return false
+	}
+
+	block %= packetWindowBlocks2022
+	bit := uint64(1) << (id % 64)
+	if w.blocks[block]&bit != 0 {
+		return false
+	}
+	w.blocks[block] |= bit
+	return true
+}
+
+// UDPSession2022 is a UDP session of a 2022 cipher. It numbers the packets it sends, and rejects replayed packets of
+// the sessions of the other side.
+type UDPSession2022 struct {
+	sessionID uint64
+	packetID  uint64
+
+	access  sync.Mutex
+	windows map[uint64]*packetWindow2022
+}
+
+// NewUDPSession2022 creates a UDP session with a random session ID.
+func NewUDPSession2022() *UDPSession2022 {
+	var id [8]byte
+	common.Must2(rand.Read(id[:]))
+	return &UDPSession2022{
+		sessionID: binary.BigEndian.Uint64(id[:]),
+		windows:   make(map[uint64]*packetWindow2022),
+	}
+}
+
+func (s *UDPSession2022) nextPacketID() uint64 {
+	return atomic.AddUint64(&s.packetID, 1) - 1
+}
+
+// checkRemote returns false if the packet of the remote session is replayed.
+func (s *UDPSession2022) checkRemote(sessionID uint64, packetID uint64) bool {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	window, found := s.windows[sessionID]
+	if !found {
+		// Remote sessions change rarely, so only the least recently seen one is dropped when there are too many.
+		if len(s.windows) >= maxRemoteSessions2022 {
+			s.removeOldestWindow()
+		}
+		window = new(packetWindow2022)
+		s.windows[sessionID] = window
+	}
+	window.lastSeen = time.Now()
+	return window.check(packetID)
+}
+
+func (s *UDPSession2022) removeOldestWindow() {
+	var oldestID uint64
+	var oldest *packetWindow2022
+	for id, window := range s.windows {
+		if oldest == nil || window.lastSeen.Before(oldest.lastSeen) {
+			oldestID, oldest = id, window
+		}
+	}
+	delete(s.windows, oldestID)
+}
+
+// udpServerSession2022 is the session of the server for a client session.
+type udpServerSession2022 struct {
+	*UDPSession2022
+	clientSessionID uint64
+	// expire is when the session is removed if no more packet is received. It is guarded by udpSessions2022.
+	expire time.Time
+}
+
+// udpSessions2022 are sessions of the server for client sessions, keyed by client session ID, across all UDP
+// connections. A session is removed after udpSessionTimeout2022 without packets. Replayed packets of a removed session
+// are still rejected by their timestamps, as the timeout is longer than the time difference allowed.
+type udpSessions2022 struct {
+	sync.Mutex
+	sessions map[uint64]*udpServerSession2022
+	task     *task.Periodic
+}
+
+func newUDPSessions2022() *udpSessions2022 {
+	s := &udpSessions2022{
+		sessions: make(map[uint64]*udpServerSession2022),
+	}
+	s.task = &task.Periodic{
+		Interval: time.Minute,
+		Execute:  s.removeExpired,
+	}
+	return s
+}
+
+// get returns the session for the client session, which is created on its first packet.
+func (s *udpSessions2022) get(clientSessionID uint64) *udpServerSession2022 {
+	s.Lock()
+	session, found := s.sessions[clientSessionID]
+	if !found {
+		session = &udpServerSession2022{
+			UDPSession2022:  NewUDPSession2022(),
+			clientSessionID: clientSessionID,
+		}
+		s.sessions[clientSessionID] = session
+	}
+	session.expire = time.Now().Add(udpSessionTimeout2022)
+	s.Unlock()
+
+	common.Must(s.task.Start())
+	return session
+}
+
+func (s *udpSessions2022) removeExpired() error {
+	now := time.Now()
+
+	s.Lock()
+	defer s.Unlock()
+
+	if len(s.sessions) == 0 {
+		return newError("nothing to do")
+	}
+	for id, session := range s.sessions {
+		if session.expire.Before(now) {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// Close implements common.Closable.
+func (s *udpSessions2022) Close() error {
+	return s.task.Close()
+}
+
+type udpSession2022Key int
+
+func contextWithUDPSession2022(ctx context.Context, s *udpServerSession2022) context.Context {
+	return context.WithValue(ctx, udpSession2022Key(0), s)
+}
+
+func udpSession2022FromContext(ctx context.Context) *udpServerSession2022 {
+	if s, ok := ctx.Value(udpSession2022Key(0)).(*udpServerSession2022); ok {
+		return s
+	}
+	return nil
+}
+
+// encodeUDPPacket2022 encodes a packet of the session. The packet is from the client if clientSessionID is nil, or
+// else a response from the server to the client session.
+func encodeUDPPacket2022(account *MemoryAccount, session *UDPSession2022, clientSessionID []byte, address net.Address, port net.Port, payload []byte) (*buf.Buffer, error) {
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return nil, err
+	}
+	isClient := clientSessionID == nil
+
+	b := buf.New()
+	var nonce []byte
+	if cipher.UDPAEADAuthCreator != nil {
+		nonce = b.Extend(24)
+		common.Must2(rand.Read(nonce))
+	}
+	separateHeader := b.Extend(16)
+	var plainHeader [16]byte
+	binary.BigEndian.PutUint64(plainHeader[:8], session.sessionID)
+	binary.BigEndian.PutUint64(plainHeader[8:], session.nextPacketID())
+	copy(separateHeader, plainHeader[:])
+
+	if nonce == nil && isClient {
+		for i, identityKey := range account.IdentityKeys {
+			next := account.Key
+			if i+1 < len(account.IdentityKeys) {
+				next = account.IdentityKeys[i+1]
+			}
+			block, err := aes.NewCipher(identityKey)
+			if err != nil {
+				b.Release()
+				return nil, newError("failed to create identity header cipher").Base(err)
+			}
+			hash := userHash2022(next)
+			for j := range hash {
+				hash[j] ^= plainHeader[j]
+			}
+			block.Encrypt(b.Extend(16), hash[:])
+		}
+	}
+
+	bodyStart := b.Len()
+	if isClient {
+		common.Must(b.WriteByte(headerType2022Client))
+	} else {
+		common.Must(b.WriteByte(headerType2022Server))
+	}
+	writeTimestamp2022(b.Extend(8))
+	if !isClient {
+		b.Write(clientSessionID)
+	}
+	var paddingLen int
+	if port == 53 {
+		// DNS messages are of distinctive sizes.
+		paddingLen = dice.Roll(maxPaddingLength2022) + 1
+	}
+	binary.BigEndian.PutUint16(b.Extend(2), uint16(paddingLen))
+	common.Must2(b.ReadFullFrom(rand.Reader, int32(paddingLen)))
+	if err := addrParser.WriteAddressPort(b, address, port); err != nil {
+		b.Release()
+		return nil, newError("failed to write address").Base(err)
+	}
+
+	// Both AES-GCM and (X)ChaCha20-Poly1305 have 16 bytes of overhead.
+	overhead := int32(16)
+	if b.Len()+int32(len(payload))+overhead > buf.Size {
+		b.Release()
+		return nil, newError("UDP payload too large: ", len(payload))
+	}
+	b.Write(payload)
+
+	if nonce != nil {
+		sealed := b.BytesFrom(24)
+		b.Extend(overhead)
+		cipher.UDPAEADAuthCreator(account.Key).Seal(sealed[:0], nonce, sealed, nil)
+		return b, nil
+	}
+
+	sealed := b.BytesFrom(bodyStart)
+	b.Extend(overhead)
+	cipher.AEADAuthCreator(sessionKey2022(account.Key, plainHeader[:8], cipher.KeyBytes)).Seal(sealed[:0], plainHeader[4:16], sealed, nil)
+
+	headerKey := account.Key
+	if isClient && len(account.IdentityKeys) > 0 {
+		headerKey = account.IdentityKeys[0]
+	}
+	block, err := aes.NewCipher(headerKey)
+	if err != nil {
+		b.Release()
+		return nil, newError("failed to create header cipher").Base(err)
+	}
+	block.Encrypt(separateHeader, plainHeader[:])
+	return b, nil
+}
+
+// decryptUDPHeader2022 decrypts and removes the separate header of a packet of an AES cipher.
+func decryptUDPHeader2022(key []byte, b *buf.Buffer) ([16]byte, error) {
+	var header [16]byte
+	if b.Len() < 16 {
+		return header, newError("packet too short")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return header, newError("failed to create header cipher").Base(err)
+	}
+	block.Decrypt(header[:], b.BytesTo(16))
+	b.Advance(16)
+	return header, nil
+}
+
+// openUDPBody2022 opens the body of a packet of an AES cipher, with the subkey of the session in the header.
+func openUDPBody2022(account *MemoryAccount, header [16]byte, b *buf.Buffer) error {
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return err
+	}
+	aead := cipher.AEADAuthCreator(sessionKey2022(account.Key, header[:8], cipher.KeyBytes))
+	plain, err := aead.Open(b.BytesTo(0), header[4:16], b.Bytes(), nil)
+	if err != nil {
+		return newError("failed to decrypt UDP payload").Base(err)
+	}
+	b.Resize(0, int32(len(plain)))
+	return nil
+}
+
+// openUDPPacket2022 opens a packet of either kind of cipher with the key of the account, and returns its separate
+// header. The body is left in b.
+func openUDPPacket2022(account *MemoryAccount, b *buf.Buffer) ([16]byte, error) {
+	cipher, err := get2022Cipher(account)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	if cipher.UDPAEADAuthCreator == nil {
+		header, err := decryptUDPHeader2022(account.Key, b)
+		if err != nil {
+			return header, err
+		}
+		return header, openUDPBody2022(account, header, b)
+	}
+
+	var header [16]byte
+	aead := cipher.UDPAEADAuthCreator(account.Key)
+	if b.Len() < int32(aead.NonceSize()+len(header)+aead.Overhead()) {
+		return header, newError("packet too short")
+	}
+	nonceSize := int32(aead.NonceSize())
+	plain, err := aead.Open(b.BytesRange(nonceSize, nonceSize), b.BytesTo(nonceSize), b.BytesFrom(nonceSize), nil)
+	if err != nil {
+		return header, newError("failed to decrypt UDP payload").Base(err)
+	}
+	b.Resize(nonceSize, nonceSize+int32(len(plain)))
+	copy(header[:], b.BytesTo(16))
+	b.Advance(16)
+	return header, nil
+}
+
+// readUDPBody2022 reads the body of a packet of the header type, and leaves its payload in b. It returns the
+// destination, and the session ID of the client for packets from the server.
+func readUDPBody2022(b *buf.Buffer, headerType byte) (net.Address, net.Port, uint64, error) {
+	headerLen := int32(1 + 8)
+	if headerType == headerType2022Server {
+		headerLen += 8
+	}
+	if b.Len() < headerLen+2 {
+		return nil, 0, 0, newError("packet too short")
+	}
+	if b.Byte(0) != headerType {
+		return nil, 0, 0, newError("unexpected header type: ", b.Byte(0))
+	}
+	if err := checkTimestamp2022(b.BytesRange(1, 9)); err != nil {
+		return nil, 0, 0, err
+	}
+	var clientSessionID uint64
+	if headerType == headerType2022Server {
+		clientSessionID = binary.BigEndian.Uint64(b.BytesRange(9, 17))
+	}
+	b.Advance(headerLen)
+
+	paddingLen := int32(binary.BigEndian.Uint16(b.BytesTo(2)))
+	b.Advance(2)
+	if paddingLen > b.Len() {
+		return nil, 0, 0, newError("invalid padding length: ", paddingLen)
+	}
+	b.Advance(paddingLen)
+
+	addr, port, err := addrParser.ReadAddressPort(nil, b)
+	if err != nil {
+		return nil, 0, 0, newError("failed to parse address").Base(err)
+	}
+	return addr, port, clientSessionID, nil
+}
+
+// UDPReader2022 reads packets of a client session of a 2022 cipher, from the server.
+type UDPReader2022 struct {
+	Reader  io.Reader
+	User    *protocol.MemoryUser
+	Session *UDPSession2022
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *UDPReader2022) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	buffer := buf.New()
+	if _, err := buffer.ReadFrom(r.Reader); err != nil {
+		buffer.Release()
+		return nil, err
+	}
+	header, err := openUDPPacket2022(r.User.Account.(*MemoryAccount), buffer)
+	if err == nil {
+		var clientSessionID uint64
+		_, _, clientSessionID, err = readUDPBody2022(buffer, headerType2022Server)
+		if err == nil && clientSessionID != r.Session.sessionID {
+			err = newError("packet of another session")
+		}
+	}
+	if err == nil && !r.Session.checkRemote(binary.BigEndian.Uint64(header[:8]), binary.BigEndian.Uint64(header[8:])) {
+		err = newError("replayed packet")
+	}
+	if err != nil {
+		buffer.Release()
+		return nil, err
+	}
+	return buf.MultiBuffer{buffer}, nil
+}
+
+// UDPWriter2022 writes packets of a client session of a 2022 cipher, to the server.
+type UDPWriter2022 struct {
+	Writer  io.Writer
+	Request *protocol.RequestHeader
+	Session *UDPSession2022
+}
+
+// Write implements io.Writer.
+func (w *UDPWriter2022) Write(payload []byte) (int, error) {
+	packet, err := encodeUDPPacket2022(w.Request.User.Account.(*MemoryAccount), w.Session, nil, w.Request.Address, w.Request.Port, payload)
+	if err != nil {
+		return 0, err
+	}
+	_, err = w.Writer.Write(packet.Bytes())
+	packet.Release()
+	return len(payload), err
+}
@@ -1,11 +1,13 @@
 package shadowsocks_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
@@ -190,3 +192,71 @@ func TestUDPReaderWriter(t *testing.T) {
 		}
 	}
 }
+
+func TestTCPSession2022(t *testing.T) {
+	cases := []struct {
+		cipher  CipherType
+		keySize int
+	}{
+		{CipherType_BLAKE3_AES_128_GCM, 16},
+		{CipherType_BLAKE3_AES_256_GCM, 32},
+		{CipherType_BLAKE3_CHACHA20_POLY1305, 32},
+	}
+
+	for _, c := range cases {
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.LocalHostIPv6,
+			Port:    1234,
+			User: &protocol.MemoryUser{
+				Email: "love@v2ray.com",
+				Account: toAccount(&Account{
+					Password:   newKey2022(c.keySize),
+					CipherType: c.cipher,
+				}),
+			},
+		}
+
+		cache := buf.New()
+		writer, requestSalt, err := WriteTCPRequest2022(request, cache)
+		common.Must(err)
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test request"))))
+
+		decodedRequest, reader, salt, err := ReadTCPSession2022(request.User, cache, antireplay.NewReplayFilter(60))
+		common.Must(err)
+		if decodedRequest.User != request.User || decodedRequest.Destination() != request.Destination() {
+			t.Error("unexpected request to ", decodedRequest.Destination())
+		}
+		if r := cmp.Diff(salt, requestSalt); r != "" {
+			t.Error("salt: ", r)
+		}
+		decodedData, err := reader.ReadMultiBuffer()
+		common.Must(err)
+		if decodedData.String() != "test request" {
+			t.Error("unexpected request data: ", decodedData.String())
+		}
+		cache.Release()
+
+		cache = buf.New()
+		writer, err = WriteTCPResponse2022(request, salt, cache)
+		common.Must(err)
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test response"))))
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test response 2"))))
+
+		if _, err := ReadTCPResponse2022(request.User, make([]byte, len(requestSalt)), bytes.NewReader(cache.Bytes())); err == nil {
+			t.Error("expect error on response to another request")
+		}
+
+		reader, err = ReadTCPResponse2022(request.User, requestSalt, cache)
+		common.Must(err)
+		for _, expected := range []string{"test response", "test response 2"} {
+			decodedData, err := reader.ReadMultiBuffer()
+			common.Must(err)
+			if decodedData.String() != expected {
+				t.Error("unexpected response data: ", decodedData.String())
+			}
+		}
+		cache.Release()
+	}
+}
@@ -3,16 +3,23 @@
 package shadowsocks
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"sync"
 	"time"
 
 	"v2ray.com/core"
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
 	"v2ray.com/core/common/buf"
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	udp_proto "v2ray.com/core/common/protocol/udp"
+	"v2ray.com/core/common/ratelimit"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/task"
@@ -24,31 +31,78 @@ import (
 
 type Server struct {
 	config        ServerConfig
-	user          *protocol.MemoryUser
+	validator     *Validator
 	policyManager policy.Manager
+
+	saltFilterOnce sync.Once
+	saltFilter     *antireplay.ReplayFilter
+	udpSessions    *udpSessions2022
 }
 
 // NewServer create a new Shadowsocks server.
 func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
-	if config.GetUser() == nil {
-		return nil, newError("user is not specified")
+	users := config.Users
+	if config.User != nil {
+		users = append([]*protocol.User{config.User}, users...)
 	}
-
-	mUser, err := config.User.ToMemoryUser()
-	if err != nil {
-		return nil, newError("failed to parse user account").Base(err)
+	if len(users) == 0 && len(config.IdentityKey) == 0 {
+		return nil, newError("user is not specified")
 	}
 
 	v := core.MustFromContext(ctx)
 	s := &Server{
 		config:        *config,
-		user:          mUser,
+		validator:     new(Validator),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
+		udpSessions:   newUDPSessions2022(),
+	}
+
+	if len(config.IdentityKey) > 0 {
+		key, err := base64.StdEncoding.DecodeString(config.IdentityKey)
+		if err != nil {
+			return nil, newError("failed to decode identity key").Base(err)
+		}
+		if err := s.validator.SetIdentityKey(key); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, user := range users {
+		mUser, err := user.ToMemoryUser()
+		if err != nil {
+			return nil, newError("failed to parse user account").Base(err)
+		}
+		if err := s.validator.Add(mUser); err != nil {
+			return nil, newError("failed to add user").Base(err)
+		}
 	}
 
 	return s, nil
 }
 
+// getSaltFilter returns the filter of salts of TCP sessions of 2022 ciphers, which is created on first use.
+func (s *Server) getSaltFilter() *antireplay.ReplayFilter {
+	s.saltFilterOnce.Do(func() {
+		s.saltFilter = antireplay.NewReplayFilter(saltReplayInterval2022)
+	})
+	return s.saltFilter
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	return s.validator.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return s.validator.Del(email)
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return s.validator.Users()
+}
+
 func (s *Server) Network() []net.Network {
 	list := s.config.Network
 	if len(list) == 0 {
@@ -71,7 +125,52 @@ func (s *Server) Process(ctx context.Context, network net.Network, conn internet
 	}
 }
 
+// udpLimiters are limiters of users in a UDP connection, keyed by email. A limiter is created on the first packet of
+// its user, and holds a connection of the user until the UDP connection ends.
+type udpLimiters struct {
+	sync.Mutex
+	limiters map[string]policy.Limiter
+}
+
+func (l *udpLimiters) get(ctx context.Context, pm policy.Manager, user *protocol.MemoryUser) (policy.Limiter, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if limiter, found := l.limiters[user.Email]; found {
+		return limiter, nil
+	}
+	limiter, err := policy.LimitSession(ctx, pm, user.Level)
+	if err != nil {
+		return nil, err
+	}
+	l.limiters[user.Email] = limiter
+	return limiter, nil
+}
+
+func (l *udpLimiters) find(email string) policy.Limiter {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.limiters[email]
+}
+
+func (l *udpLimiters) Close() error {
+	l.Lock()
+	defer l.Unlock()
+
+	for _, limiter := range l.limiters {
+		limiter.Close()
+	}
+	l.limiters = nil
+	return nil
+}
+
 func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	limiters := &udpLimiters{
+		limiters: make(map[string]policy.Limiter),
+	}
+	defer limiters.Close()
+
 	udpServer := udp.NewDispatcher(dispatcher, func(ctx context.Context, packet *udp_proto.Packet) {
 		request := protocol.RequestHeaderFromContext(ctx)
 		if request == nil {
@@ -79,7 +178,15 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		}
 
 		payload := packet.Payload
-		data, err := EncodeUDPPacket(request, payload.Bytes())
+		var data *buf.Buffer
+		var err error
+		if udpSession := udpSession2022FromContext(ctx); udpSession != nil {
+			var clientSessionID [8]byte
+			binary.BigEndian.PutUint64(clientSessionID[:], udpSession.clientSessionID)
+			data, err = encodeUDPPacket2022(request.User.Account.(*MemoryAccount), udpSession.UDPSession2022, clientSessionID[:], request.Address, request.Port, payload.Bytes())
+		} else {
+			data, err = EncodeUDPPacket(request, payload.Bytes())
+		}
 		payload.Release()
 		if err != nil {
 			newError("failed to encode UDP packet").Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
@@ -87,15 +194,20 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		}
 		defer data.Release()
 
+		if limiter := limiters.find(request.User.Email); limiter != nil {
+			if downlink := limiter.Downlink(); downlink != nil {
+				if err := ratelimit.Sleep(ctx, downlink.Reserve(int64(data.Len())), nil); err != nil {
+					return
+				}
+			}
+		}
 		conn.Write(data.Bytes())
 	})
 
-	account := s.user.Account.(*MemoryAccount)
 	inbound := session.InboundFromContext(ctx)
 	if inbound == nil {
 		panic("no inbound metadata")
 	}
-	inbound.User = s.user
 
 	reader := buf.NewPacketReader(conn)
 	for {
@@ -105,7 +217,14 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 		}
 
 		for _, payload := range mpayload {
-			request, data, err := DecodeUDPPacket(s.user, payload)
+			var request *protocol.RequestHeader
+			var data *buf.Buffer
+			var udpSession *udpServerSession2022
+			if s.validator.is2022() {
+				request, data, udpSession, err = s.getUDP2022(payload)
+			} else {
+				request, data, err = s.validator.GetUDP(payload)
+			}
 			if err != nil {
 				if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.Source.IsValid() {
 					newError("dropping invalid UDP packet from: ", inbound.Source).Base(err).WriteToLog(session.ExportIDToError(ctx))
@@ -120,6 +239,8 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 				continue
 			}
 
+			inbound.User = request.User
+			account := request.User.Account.(*MemoryAccount)
 			if request.Option.Has(RequestOptionOneTimeAuth) && account.OneTimeAuth == Account_Disabled {
 				newError("client payload enables OTA but server doesn't allow it").WriteToLog(session.ExportIDToError(ctx))
 				payload.Release()
@@ -133,6 +254,19 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 			}
 
 			dest := request.Destination()
+			limiter, err := limiters.get(ctx, s.policyManager, request.User)
+			if err != nil {
+				newError("rejected UDP packet to ", dest).Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+				payload.Release()
+				continue
+			}
+			if uplink := limiter.Uplink(); uplink != nil {
+				if err := ratelimit.Sleep(ctx, uplink.Reserve(int64(data.Len())), nil); err != nil {
+					payload.Release()
+					return err
+				}
+			}
+
 			if inbound.Source.IsValid() {
 				ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
 					From:   inbound.Source,
@@ -145,6 +279,9 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 			newError("tunnelling request to ", dest).WriteToLog(session.ExportIDToError(ctx))
 
 			ctx = protocol.ContextWithRequestHeader(ctx, request)
+			if udpSession != nil {
+				ctx = contextWithUDPSession2022(ctx, udpSession)
+			}
 			udpServer.Dispatch(ctx, dest, data)
 		}
 	}
@@ -152,12 +289,35 @@ func (s *Server) handlerUDPPayload(ctx context.Context, conn internet.Connection
 	return nil
 }
 
+// getUDP2022 decodes the UDP packet of a 2022 cipher, and returns the session of the server for the client session
+// of the packet.
+func (s *Server) getUDP2022(payload *buf.Buffer) (*protocol.RequestHeader, *buf.Buffer, *udpServerSession2022, error) {
+	request, data, sessionID, packetID, err := s.validator.GetUDP2022(payload)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	session := s.udpSessions.get(sessionID)
+	if !session.checkRemote(sessionID, packetID) {
+		return nil, nil, nil, newError("replayed packet")
+	}
+	return request, data, session, nil
+}
+
 func (s *Server) handleConnection(ctx context.Context, conn internet.Connection, dispatcher routing.Dispatcher) error {
-	sessionPolicy := s.policyManager.ForLevel(s.user.Level)
-	conn.SetReadDeadline(time.Now().Add(sessionPolicy.Timeouts.Handshake))
+	conn.SetReadDeadline(time.Now().Add(s.policyManager.ForLevel(0).Timeouts.Handshake))
 
 	bufferedReader := buf.BufferedReader{Reader: buf.NewReader(conn)}
-	request, bodyReader, err := ReadTCPSession(s.user, &bufferedReader)
+	user, reader, err := s.identify(&bufferedReader)
+	var request *protocol.RequestHeader
+	var bodyReader buf.Reader
+	var requestSalt []byte
+	if err == nil {
+		if _, ok := user.Account.(*MemoryAccount).Cipher.(*AEAD2022Cipher); ok {
+			request, bodyReader, requestSalt, err = ReadTCPSession2022(user, reader, s.getSaltFilter())
+		} else {
+			request, bodyReader, err = ReadTCPSession(user, reader)
+		}
+	}
 	if err != nil {
 		log.Record(&log.AccessMessage{
 			From:   conn.RemoteAddr(),
@@ -173,7 +333,8 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 	if inbound == nil {
 		panic("no inbound metadata")
 	}
-	inbound.User = s.user
+	inbound.User = user
+	sessionPolicy := s.policyManager.ForLevel(user.Level)
 
 	dest := request.Destination()
 	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
@@ -185,6 +346,12 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 	})
 	newError("tunnelling request to ", dest).WriteToLog(session.ExportIDToError(ctx))
 
+	limiter, err := policy.LimitSession(ctx, s.policyManager, user.Level)
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
 
@@ -198,16 +365,28 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
 
 		bufferedWriter := buf.NewBufferedWriter(buf.NewWriter(conn))
-		responseWriter, err := WriteTCPResponse(request, bufferedWriter)
+		var responseWriter buf.Writer
+		var err error
+		if requestSalt != nil {
+			responseWriter, err = WriteTCPResponse2022(request, requestSalt, bufferedWriter)
+		} else {
+			responseWriter, err = WriteTCPResponse(request, bufferedWriter)
+		}
 		if err != nil {
 			return newError("failed to write response").Base(err)
 		}
 
+		downlink := limiter.Downlink()
 		{
 			payload, err := link.Reader.ReadMultiBuffer()
 			if err != nil {
 				return err
 			}
+			if downlink != nil {
+				if err := ratelimit.Sleep(ctx, downlink.Reserve(int64(payload.Len())), timer); err != nil {
+					return err
+				}
+			}
 			if err := responseWriter.WriteMultiBuffer(payload); err != nil {
 				return err
 			}
@@ -217,7 +396,7 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 			return err
 		}
 
-		if err := buf.Copy(link.Reader, responseWriter, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(link.Reader, responseWriter, buf.UpdateActivity(timer), buf.RateLimit(ctx, downlink, timer)); err != nil {
 			return newError("failed to transport all TCP response").Base(err)
 		}
 
@@ -227,7 +406,7 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 	requestDone := func() error {
 		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
 
-		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Uplink(), timer)); err != nil {
 			return newError("failed to transport all TCP request").Base(err)
 		}
 
@@ -244,6 +423,26 @@ func (s *Server) handleConnection(ctx context.Context, conn internet.Connection,
 	return nil
 }
 
+// identify returns the user of a TCP connection, and the reader of the connection from its beginning, without the
+// identity header if any.
+func (s *Server) identify(reader io.Reader) (*protocol.MemoryUser, io.Reader, error) {
+	size := s.validator.headerSize()
+	if size == 0 {
+		user, _, err := s.validator.GetTCP(nil)
+		return user, reader, err
+	}
+
+	header := make([]byte, size)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, nil, newError("failed to read header").Base(err)
+	}
+	user, header, err := s.validator.GetTCP(header)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, io.MultiReader(bytes.NewReader(header), reader), nil
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		return NewServer(ctx, config.(*ServerConfig))
@@ -0,0 +1,281 @@
+// +build !confonly
+
+package shadowsocks
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/protocol"
+)
+
+// Validator is a set of users of a Shadowsocks server. When there are more than one users, a user is identified by
+// the key that authenticates the first chunk of a connection or a packet, so all users must use AEAD ciphers. Users of
+// 2022 ciphers are identified by identity headers instead, which requires an identity key of the server.
+type Validator struct {
+	sync.RWMutex
+	users []*protocol.MemoryUser
+
+	identityKey []byte
+	usersByHash map[[16]byte]*protocol.MemoryUser
+}
+
+// SetIdentityKey sets the identity key of the server, so that users of 2022 ciphers are identified by identity
+// headers. It must be set before any user is added.
+func (v *Validator) SetIdentityKey(key []byte) error {
+	if len(key) != 16 && len(key) != 32 {
+		return newError("invalid identity key size: ", len(key))
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	if len(v.users) > 0 {
+		return newError("identity key must be set before users are added")
+	}
+	v.identityKey = key
+	v.usersByHash = make(map[[16]byte]*protocol.MemoryUser)
+	return nil
+}
+
+// Add adds a user.
+func (v *Validator) Add(u *protocol.MemoryUser) error {
+	account, ok := u.Account.(*MemoryAccount)
+	if !ok {
+		return newError("not a Shadowsocks account")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	cipher2022, is2022 := account.Cipher.(*AEAD2022Cipher)
+	if v.identityKey != nil {
+		if !is2022 || cipher2022.UDPAEADAuthCreator != nil {
+			return newError("identity headers are supported with AES ciphers of Shadowsocks 2022 only")
+		}
+		if int(cipher2022.KeySize()) != len(v.identityKey) {
+			return newError("key size of user ", u.Email, " doesn't match the identity key")
+		}
+	} else if len(v.users) > 0 {
+		_, first2022 := v.users[0].Account.(*MemoryAccount).Cipher.(*AEAD2022Cipher)
+		if is2022 || first2022 {
+			return newError("multiple users of Shadowsocks 2022 require an identity key")
+		}
+		if !account.Cipher.IsAEAD() || !v.users[0].Account.(*MemoryAccount).Cipher.IsAEAD() {
+			return newError("multiple users are supported with AEAD ciphers only")
+		}
+	}
+	for _, user := range v.users {
+		if len(u.Email) > 0 && strings.EqualFold(user.Email, u.Email) {
+			return newError("User ", u.Email, " already exists.")
+		}
+	}
+	if v.identityKey != nil {
+		hash := userHash2022(account.Key)
+		if _, found := v.usersByHash[hash]; found {
+			return newError("User with the same key already exists.")
+		}
+		v.usersByHash[hash] = u
+	}
+	v.users = append(v.users, u)
+	return nil
+}
+
+// Del removes the user by email.
+func (v *Validator) Del(email string) error {
+	if email == "" {
+		return newError("Email must not be empty.")
+	}
+
+	v.Lock()
+	defer v.Unlock()
+
+	for i, user := range v.users {
+		if strings.EqualFold(user.Email, email) {
+			v.users = append(v.users[:i:i], v.users[i+1:]...)
+			if v.identityKey != nil {
+				delete(v.usersByHash, userHash2022(user.Account.(*MemoryAccount).Key))
+			}
+			return nil
+		}
+	}
+	return newError("User ", email, " not found.")
+}
+
+// Users returns all users.
+func (v *Validator) Users() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	return append([]*protocol.MemoryUser(nil), v.users...)
+}
+
+// headerSize returns the number of bytes at the beginning of a TCP connection, that are needed to identify its user.
+func (v *Validator) headerSize() int32 {
+	v.RLock()
+	defer v.RUnlock()
+
+	if v.identityKey != nil {
+		// Salt, and the identity header.
+		return int32(len(v.identityKey)) + 16
+	}
+	if len(v.users) <= 1 {
+		return 0
+	}
+	var size int32
+	for _, user := range v.users {
+		cipher := user.Account.(*MemoryAccount).Cipher
+		// Salt, and the encrypted length of the first chunk.
+		if s := cipher.IVSize() + 2 + 16; s > size {
+			size = s
+		}
+	}
+	return size
+}
+
+// GetTCP returns the user whose key authenticates the header of a TCP connection, or whose identity header is in it.
+// The header must be at least as long as headerSize(). It also returns the part of the header that the session of
+// the user starts with, which is the header without the identity header.
+func (v *Validator) GetTCP(header []byte) (*protocol.MemoryUser, []byte, error) {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 0 {
+		return nil, nil, newError("no user configured")
+	}
+	if v.identityKey != nil {
+		saltLen := len(v.identityKey)
+		if len(header) < saltLen+16 {
+			return nil, nil, newError("header too short")
+		}
+		block, err := aes.NewCipher(identityKey2022(v.identityKey, header[:saltLen]))
+		if err != nil {
+			return nil, nil, newError("failed to create identity header cipher").Base(err)
+		}
+		var hash [16]byte
+		block.Decrypt(hash[:], header[saltLen:saltLen+16])
+		user, found := v.usersByHash[hash]
+		if !found {
+			return nil, nil, newError("no matching user")
+		}
+		return user, header[:saltLen], nil
+	}
+	if len(v.users) == 1 {
+		return v.users[0], header, nil
+	}
+
+	for _, user := range v.users {
+		account := user.Account.(*MemoryAccount)
+		cipher, ok := account.Cipher.(*AEADCipher)
+		if !ok {
+			continue
+		}
+		ivLen := cipher.IVSize()
+		if int32(len(header)) < ivLen+2+16 {
+			// Users changed since the header was read.
+			continue
+		}
+		auth := cipher.createAuthenticator(account.Key, header[:ivLen])
+		if _, err := auth.Open(nil, header[ivLen:ivLen+2+int32(auth.Overhead())]); err == nil {
+			return user, header, nil
+		}
+	}
+	return nil, nil, newError("no matching user")
+}
+
+// GetUDP decodes the UDP packet with the key of each user, until one of them authenticates it.
+func (v *Validator) GetUDP(payload *buf.Buffer) (*protocol.RequestHeader, *buf.Buffer, error) {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 0 {
+		return nil, nil, newError("no user configured")
+	}
+	if len(v.users) == 1 {
+		return DecodeUDPPacket(v.users[0], payload)
+	}
+
+	for _, user := range v.users {
+		b := buf.New()
+		b.Write(payload.Bytes())
+		request, data, err := DecodeUDPPacket(user, b)
+		if err == nil {
+			payload.Release()
+			return request, data, nil
+		}
+		b.Release()
+	}
+	return nil, nil, newError("no matching user")
+}
+
+// is2022 returns whether users are of 2022 ciphers, whose UDP packets are decoded by GetUDP2022.
+func (v *Validator) is2022() bool {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 0 {
+		return v.identityKey != nil
+	}
+	_, ok := v.users[0].Account.(*MemoryAccount).Cipher.(*AEAD2022Cipher)
+	return ok
+}
+
+// GetUDP2022 decodes the UDP packet of a 2022 cipher, of the user in its identity header if there is an identity key.
+// It also returns the session ID and the packet ID of the packet.
+func (v *Validator) GetUDP2022(payload *buf.Buffer) (*protocol.RequestHeader, *buf.Buffer, uint64, uint64, error) {
+	v.RLock()
+	defer v.RUnlock()
+
+	if len(v.users) == 0 {
+		return nil, nil, 0, 0, newError("no user configured")
+	}
+
+	user := v.users[0]
+	var header [16]byte
+	var err error
+	if v.identityKey != nil {
+		header, err = decryptUDPHeader2022(v.identityKey, payload)
+		if err != nil {
+			return nil, nil, 0, 0, err
+		}
+		if payload.Len() < 16 {
+			return nil, nil, 0, 0, newError("packet too short")
+		}
+		block, err := aes.NewCipher(v.identityKey)
+		if err != nil {
+			return nil, nil, 0, 0, newError("failed to create identity header cipher").Base(err)
+		}
+		var hash [16]byte
+		block.Decrypt(hash[:], payload.BytesTo(16))
+		for i := range hash {
+			hash[i] ^= header[i]
+		}
+		payload.Advance(16)
+		found := false
+		if user, found = v.usersByHash[hash]; !found {
+			return nil, nil, 0, 0, newError("no matching user")
+		}
+		err = openUDPBody2022(user.Account.(*MemoryAccount), header, payload)
+	} else {
+		header, err = openUDPPacket2022(user.Account.(*MemoryAccount), payload)
+	}
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+
+	addr, port, _, err := readUDPBody2022(payload, headerType2022Client)
+	if err != nil {
+		return nil, nil, 0, 0, err
+	}
+	request := &protocol.RequestHeader{
+		Version: Version,
+		User:    user,
+		Command: protocol.RequestCommandUDP,
+		Address: addr,
+		Port:    port,
+	}
+	return request, payload, binary.BigEndian.Uint64(header[:8]), binary.BigEndian.Uint64(header[8:]), nil
+}
@@ -0,0 +1,197 @@
+package shadowsocks_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	. "v2ray.com/core/proxy/shadowsocks"
+)
+
+func TestValidator(t *testing.T) {
+	users := []*protocol.MemoryUser{
+		{
+			Email: "a@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   "password-a",
+				CipherType: CipherType_AES_128_GCM,
+			}),
+		},
+		{
+			Email: "b@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   "password-b",
+				CipherType: CipherType_CHACHA20_POLY1305,
+			}),
+		},
+	}
+
+	v := new(Validator)
+	for _, u := range users {
+		common.Must(v.Add(u))
+	}
+	if err := v.Add(users[0]); err == nil {
+		t.Error("expect error when adding a user twice")
+	}
+	if err := v.Add(&protocol.MemoryUser{
+		Email: "c@v2ray.com",
+		Account: toAccount(&Account{
+			Password:   "password-c",
+			CipherType: CipherType_AES_256_CFB,
+		}),
+	}); err == nil {
+		t.Error("expect error when adding a user with non-AEAD cipher")
+	}
+
+	for _, u := range users {
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.LocalHostIP,
+			Port:    1234,
+			User:    u,
+		}
+
+		cache := buf.New()
+		writer, err := WriteTCPRequest(request, cache)
+		common.Must(err)
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test string"))))
+
+		user, _, err := v.GetTCP(cache.Bytes())
+		common.Must(err)
+		if user.Email != u.Email {
+			t.Error("expect user ", u.Email, ", but got ", user.Email)
+		}
+		cache.Release()
+
+		request.Command = protocol.RequestCommandUDP
+		packet, err := EncodeUDPPacket(request, []byte("test string"))
+		common.Must(err)
+		decodedRequest, data, err := v.GetUDP(packet)
+		common.Must(err)
+		if decodedRequest.User.Email != u.Email || data.String() != "test string" {
+			t.Error("unexpected UDP packet of ", decodedRequest.User.Email, ": ", data.String())
+		}
+		data.Release()
+	}
+
+	common.Must(v.Del("a@v2ray.com"))
+	if users := v.Users(); len(users) != 1 || users[0].Email != "b@v2ray.com" {
+		t.Error("unexpected users: ", users)
+	}
+	if err := v.Del("a@v2ray.com"); err == nil {
+		t.Error("expect error when removing a user twice")
+	}
+}
+
+func newKey2022(size int) string {
+	key := make([]byte, size)
+	common.Must2(rand.Read(key))
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func TestValidator2022(t *testing.T) {
+	identityKey := newKey2022(16)
+	keys := []string{newKey2022(16), newKey2022(16)}
+
+	v := new(Validator)
+	common.Must(v.SetIdentityKey(common.Must2(base64.StdEncoding.DecodeString(identityKey)).([]byte)))
+	for i, key := range keys {
+		common.Must(v.Add(&protocol.MemoryUser{
+			Email: string(rune('a'+i)) + "@v2ray.com",
+			Account: toAccount(&Account{
+				Password:   key,
+				CipherType: CipherType_BLAKE3_AES_128_GCM,
+			}),
+		}))
+	}
+	if err := v.Add(&protocol.MemoryUser{
+		Email: "c@v2ray.com",
+		Account: toAccount(&Account{
+			Password:   newKey2022(32),
+			CipherType: CipherType_BLAKE3_AES_256_GCM,
+		}),
+	}); err == nil {
+		t.Error("expect error when adding a user whose key size differs from the identity key")
+	}
+
+	filter := antireplay.NewReplayFilter(60)
+	for i, key := range keys {
+		email := string(rune('a'+i)) + "@v2ray.com"
+		request := &protocol.RequestHeader{
+			Version: Version,
+			Command: protocol.RequestCommandTCP,
+			Address: net.DomainAddress("v2ray.com"),
+			Port:    443,
+			User: &protocol.MemoryUser{
+				Account: toAccount(&Account{
+					Password:   identityKey + ":" + key,
+					CipherType: CipherType_BLAKE3_AES_128_GCM,
+				}),
+			},
+		}
+
+		cache := buf.New()
+		writer, _, err := WriteTCPRequest2022(request, cache)
+		common.Must(err)
+		common.Must(writer.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test string"))))
+		connection := append([]byte(nil), cache.Bytes()...)
+		cache.Release()
+
+		for round := 0; round < 2; round++ {
+			user, header, err := v.GetTCP(connection[:16+16])
+			common.Must(err)
+			if user.Email != email {
+				t.Error("expect user ", email, ", but got ", user.Email)
+			}
+			reader := io.MultiReader(bytes.NewReader(header), bytes.NewReader(connection[16+16:]))
+			decodedRequest, bodyReader, _, err := ReadTCPSession2022(user, reader, filter)
+			if round == 1 {
+				if err == nil {
+					t.Error("expect error on replayed salt")
+				}
+				continue
+			}
+			common.Must(err)
+			if decodedRequest.Destination() != request.Destination() {
+				t.Error("unexpected destination: ", decodedRequest.Destination())
+			}
+			mb, err := bodyReader.ReadMultiBuffer()
+			common.Must(err)
+			if mb.String() != "test string" {
+				t.Error("unexpected body: ", mb.String())
+			}
+			buf.ReleaseMulti(mb)
+		}
+
+		request.Command = protocol.RequestCommandUDP
+		udpSession := NewUDPSession2022()
+		packets := &bytes.Buffer{}
+		udpWriter := &UDPWriter2022{
+			Writer:  packets,
+			Request: request,
+			Session: udpSession,
+		}
+		common.Must2(udpWriter.Write([]byte("test string")))
+		packet := buf.New()
+		common.Must2(packet.Write(packets.Bytes()))
+		decodedRequest, data, _, packetID, err := v.GetUDP2022(packet)
+		common.Must(err)
+		if decodedRequest.User.Email != email || data.String() != "test string" || packetID != 0 {
+			t.Error("unexpected UDP packet ", packetID, " of ", decodedRequest.User.Email, ": ", data.String())
+		}
+		data.Release()
+	}
+
+	common.Must(v.Del("a@v2ray.com"))
+	if users := v.Users(); len(users) != 1 || users[0].Email != "b@v2ray.com" {
+		t.Error("unexpected users: ", users)
+	}
+}
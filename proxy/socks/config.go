@@ -2,7 +2,9 @@
 
 package socks
 
-import "v2ray.com/core/common/protocol"
+import (
+	"v2ray.com/core/common/protocol"
+)
 
 func (a *Account) Equals(another protocol.Account) bool {
 	if account, ok := another.(*Account); ok {
@@ -15,13 +17,15 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 	return a, nil
 }
 
-func (c *ServerConfig) HasAccount(username, password string) bool {
-	if c.Accounts == nil {
-		return false
+// newUsers returns users of the server in config.
+func newUsers(config *ServerConfig) *protocol.PasswordUsers {
+	users := make([]*protocol.MemoryUser, 0, len(config.Accounts))
+	for username, password := range config.Accounts {
+		users = append(users, &protocol.MemoryUser{
+			Account: &Account{Username: username, Password: password},
+			Email:   username,
+			Level:   config.UserLevel,
+		})
 	}
-	storedPassed, found := c.Accounts[username]
-	if !found {
-		return false
-	}
-	return storedPassed == password
+	return protocol.NewPasswordUsers(users, config.AuthType == AuthType_PASSWORD)
 }
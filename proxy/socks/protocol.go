@@ -42,11 +42,12 @@ var addrParser = protocol.NewAddressParser(
 
 type ServerSession struct {
 	config *ServerConfig
+	users  *protocol.PasswordUsers
 	port   net.Port
 }
 
 func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
-	if s.config.AuthType == AuthType_PASSWORD {
+	if s.users.AuthRequired() {
 		writeSocks4Response(writer, socks4RequestRejected, net.AnyIP, net.Port(0)) // nolint: errcheck
 		return nil, newError("socks 4 is not allowed when auth is required.")
 	}
@@ -94,54 +95,52 @@ func (s *ServerSession) handshake4(cmd byte, reader io.Reader, writer io.Writer)
 	}
 }
 
-func (s *ServerSession) auth5(nMethod byte, reader io.Reader, writer io.Writer) (username string, err error) {
+func (s *ServerSession) auth5(nMethod byte, reader io.Reader, writer io.Writer) (*protocol.MemoryUser, error) {
 	buffer := buf.StackNew()
 	defer buffer.Release()
 
-	if _, err = buffer.ReadFullFrom(reader, int32(nMethod)); err != nil {
-		return "", newError("failed to read auth methods").Base(err)
+	if _, err := buffer.ReadFullFrom(reader, int32(nMethod)); err != nil {
+		return nil, newError("failed to read auth methods").Base(err)
 	}
 
 	var expectedAuth byte = authNotRequired
-	if s.config.AuthType == AuthType_PASSWORD {
+	if s.users.AuthRequired() {
 		expectedAuth = authPassword
 	}
 
 	if !hasAuthMethod(expectedAuth, buffer.BytesRange(0, int32(nMethod))) {
 		writeSocks5AuthenticationResponse(writer, socks5Version, authNoMatchingMethod) // nolint: errcheck
-		return "", newError("no matching auth method")
+		return nil, newError("no matching auth method")
 	}
 
 	if err := writeSocks5AuthenticationResponse(writer, socks5Version, expectedAuth); err != nil {
-		return "", newError("failed to write auth response").Base(err)
+		return nil, newError("failed to write auth response").Base(err)
 	}
 
 	if expectedAuth == authPassword {
 		username, password, err := ReadUsernamePassword(reader)
 		if err != nil {
-			return "", newError("failed to read username and password for authentication").Base(err)
+			return nil, newError("failed to read username and password for authentication").Base(err)
 		}
 
-		if !s.config.HasAccount(username, password) {
+		user := s.users.Get(username, password)
+		if user == nil {
 			writeSocks5AuthenticationResponse(writer, 0x01, 0xFF) // nolint: errcheck
-			return "", newError("invalid username or password")
+			return nil, newError("invalid username or password")
 		}
 
 		if err := writeSocks5AuthenticationResponse(writer, 0x01, 0x00); err != nil {
-			return "", newError("failed to write auth response").Base(err)
+			return nil, newError("failed to write auth response").Base(err)
 		}
-		return username, nil
+		return user, nil
 	}
 
-	return "", nil
+	return nil, nil
 }
 
 func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Writer) (*protocol.RequestHeader, error) {
-	var (
-		username string
-		err      error
-	)
-	if username, err = s.auth5(nMethod, reader, writer); err != nil {
+	user, err := s.auth5(nMethod, reader, writer)
+	if err != nil {
 		return nil, err
 	}
 
@@ -156,9 +155,8 @@ func (s *ServerSession) handshake5(nMethod byte, reader io.Reader, writer io.Wri
 		buffer.Release()
 	}
 
-	request := new(protocol.RequestHeader)
-	if username != "" {
-		request.User = &protocol.MemoryUser{Email: username}
+	request := &protocol.RequestHeader{
+		User: user,
 	}
 	switch cmd {
 	case cmdTCPConnect, cmdTorResolve, cmdTorResolvePTR:
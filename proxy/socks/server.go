@@ -14,6 +14,7 @@ import (
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
 	udp_proto "v2ray.com/core/common/protocol/udp"
+	"v2ray.com/core/common/ratelimit"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
 	"v2ray.com/core/common/task"
@@ -27,6 +28,7 @@ import (
 // Server is a SOCKS 5 proxy server
 type Server struct {
 	config        *ServerConfig
+	users         *protocol.PasswordUsers
 	policyManager policy.Manager
 	//controller    bool
 }
@@ -36,6 +38,7 @@ func NewServer(ctx context.Context, config *ServerConfig) (*Server, error) {
 	v := core.MustFromContext(ctx)
 	s := &Server{
 		config:        config,
+		users:         newUsers(config),
 		policyManager: v.GetFeature(policy.ManagerType()).(policy.Manager),
 	}
 	//newDebugMsg("SOCKS config: " + StructString(config))
@@ -55,6 +58,32 @@ func (s *Server) policy() policy.Session {
 	return p
 }
 
+// userLevel returns the level of the user of the session in ctx, or the level in config if the user is unknown.
+func (s *Server) userLevel(ctx context.Context) uint32 {
+	if inbound := session.InboundFromContext(ctx); inbound != nil && inbound.User != nil {
+		return inbound.User.Level
+	}
+	return s.config.UserLevel
+}
+
+// AddUser implements proxy.UserManager.
+func (s *Server) AddUser(ctx context.Context, user *protocol.MemoryUser) error {
+	if _, ok := user.Account.(*Account); !ok {
+		return newError("not a SOCKS account")
+	}
+	return s.users.Add(user)
+}
+
+// RemoveUser implements proxy.UserManager.
+func (s *Server) RemoveUser(ctx context.Context, email string) error {
+	return s.users.Remove(email)
+}
+
+// GetUsers implements proxy.UserLister.
+func (s *Server) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return s.users.List()
+}
+
 // Network implements proxy.Inbound.
 func (s *Server) Network() []net.Network {
 	list := []net.Network{net.Network_TCP}
@@ -96,6 +125,7 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 	//newDebugMsg("SOCKS: server inbound gateway " + StructString(inbound.Gateway))
 	svrSession := &ServerSession{
 		config: s.config,
+		users:  s.users,
 		port:   inbound.Gateway.Port,
 	}
 	//newDebugMsg(s.config.Address.String())
@@ -116,7 +146,7 @@ func (s *Server) processTCP(ctx context.Context, conn internet.Connection, dispa
 		return newError("failed to read request").Base(err)
 	}
 	if request.User != nil {
-		inbound.User.Email = request.User.Email
+		inbound.User = request.User
 	}
 
 	if err := conn.SetReadDeadline(time.Time{}); err != nil {
@@ -153,6 +183,12 @@ func (*Server) handleUDP(c io.Reader) error {
 }
 
 func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writer, dest net.Destination, dispatcher routing.Dispatcher) error {
+	limiter, err := policy.LimitSession(ctx, s.policyManager, s.userLevel(ctx))
+	if err != nil {
+		return newError("rejected request to ", dest).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, s.policy().Timeouts.ConnectionIdle)
 
@@ -165,7 +201,7 @@ func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 
 	requestDone := func() error {
 		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
-		if err := buf.Copy(buf.NewReader(reader), link.Writer, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(buf.NewReader(reader), link.Writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Uplink(), timer)); err != nil {
 			return newError("failed to transport all TCP request").Base(err)
 		}
 
@@ -176,7 +212,7 @@ func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
 
 		v2writer := buf.NewWriter(writer)
-		if err := buf.Copy(link.Reader, v2writer, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(link.Reader, v2writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Downlink(), timer)); err != nil {
 			return newError("failed to transport all TCP response").Base(err)
 		}
 
@@ -194,6 +230,14 @@ func (s *Server) transport(ctx context.Context, reader io.Reader, writer io.Writ
 }
 
 func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection, dispatcher routing.Dispatcher) error {
+	limiter, err := policy.LimitSession(ctx, s.policyManager, s.userLevel(ctx))
+	if err != nil {
+		return newError("rejected UDP connection").Base(err).AtInfo()
+	}
+	defer limiter.Close()
+	uplink := limiter.Uplink()
+	downlink := limiter.Downlink()
+
 	udpServer := udp.NewDispatcher(dispatcher, func(ctx context.Context, packet *udp_proto.Packet) {
 		payload := packet.Payload
 		newError("writing back UDP response with ", payload.Len(), " bytes").AtDebug().WriteToLog(session.ExportIDToError(ctx))
@@ -210,6 +254,11 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 			newError("failed to write UDP response").AtWarning().Base(err).WriteToLog(session.ExportIDToError(ctx))
 		}
 
+		if downlink != nil {
+			if err := ratelimit.Sleep(ctx, downlink.Reserve(int64(udpMessage.Len())), nil); err != nil {
+				return
+			}
+		}
 		conn.Write(udpMessage.Bytes()) // nolint: errcheck
 	})
 
@@ -249,6 +298,13 @@ func (s *Server) handleUDPPayload(ctx context.Context, conn internet.Connection,
 				})
 			}
 
+			if uplink != nil {
+				if err := ratelimit.Sleep(ctx, uplink.Reserve(int64(payload.Len())), nil); err != nil {
+					payload.Release()
+					return err
+				}
+			}
+
 			ctx = protocol.ContextWithRequestHeader(ctx, request)
 			udpServer.Dispatch(ctx, request.Destination(), payload)
 		}
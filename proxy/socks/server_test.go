@@ -0,0 +1,131 @@
+package socks_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/session"
+	. "v2ray.com/core/proxy/socks"
+	"v2ray.com/core/testing/proxytest"
+)
+
+func newServer(config *ServerConfig) *Server {
+	return proxytest.NewInbound(config).(*Server)
+}
+
+// handshake runs a SOCKS 5 handshake to 127.0.0.1:80, and returns the user of the session, or nil if the server
+// rejects the client.
+func handshake(t *testing.T, s *Server, method byte, username, password string) *protocol.MemoryUser {
+	inbound := &session.Inbound{
+		Source:  net.TCPDestination(net.LocalHostIP, 12345),
+		Gateway: net.TCPDestination(net.LocalHostIP, 1080),
+	}
+	return proxytest.ProcessUser(s, inbound, func(client net.Conn) {
+		common.Must2(client.Write([]byte{0x05, 0x01, method}))
+		response := make([]byte, 2)
+		if _, err := io.ReadFull(client, response); err != nil {
+			t.Fatal(err)
+		}
+		if response[1] != method {
+			return
+		}
+
+		if method == 0x02 {
+			request := []byte{0x01, byte(len(username))}
+			request = append(request, username...)
+			request = append(request, byte(len(password)))
+			request = append(request, password...)
+			common.Must2(client.Write(request))
+			if _, err := io.ReadFull(client, response); err != nil {
+				t.Fatal(err)
+			}
+			if response[1] != 0x00 {
+				return
+			}
+		}
+
+		common.Must2(client.Write([]byte{0x05, 0x01, 0x00, 0x01, 127, 0, 0, 1, 0, 80}))
+		response = make([]byte, 10)
+		if _, err := io.ReadFull(client, response); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestServerUsers(t *testing.T) {
+	s := newServer(&ServerConfig{
+		AuthType:  AuthType_PASSWORD,
+		Accounts:  map[string]string{"a": "pa"},
+		UserLevel: 1,
+	})
+
+	if user := handshake(t, s, 0x02, "a", "pa"); user == nil || user.Email != "a" || user.Level != 1 {
+		t.Error("expect user a, but got ", user)
+	}
+	if user := handshake(t, s, 0x02, "a", "wrong"); user != nil {
+		t.Error("expect wrong password rejected, but got ", user)
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "b", Password: "pb"},
+		Email:   "b@v2ray.com",
+		Level:   2,
+	}))
+	if user := handshake(t, s, 0x02, "b", "pb"); user == nil || user.Email != "b@v2ray.com" || user.Level != 2 {
+		t.Error("expect user b@v2ray.com, but got ", user)
+	}
+	if err := s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "b", Password: "other"},
+	}); err == nil {
+		t.Error("expect error for duplicate username")
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "c", Password: "pc"},
+	}))
+	if user := handshake(t, s, 0x02, "c", "pc"); user == nil || user.Email != "c" {
+		t.Error("expect user c with username as email, but got ", user)
+	}
+
+	if n := len(s.GetUsers(context.Background())); n != 3 {
+		t.Error("expect 3 users, but got ", n)
+	}
+
+	common.Must(s.RemoveUser(context.Background(), "b@v2ray.com"))
+	if user := handshake(t, s, 0x02, "b", "pb"); user != nil {
+		t.Error("expect removed user rejected, but got ", user)
+	}
+	if err := s.RemoveUser(context.Background(), "b@v2ray.com"); err == nil {
+		t.Error("expect error for removed user")
+	}
+}
+
+func TestServerAuthRequiredAfterAddUser(t *testing.T) {
+	s := newServer(&ServerConfig{
+		AuthType: AuthType_NO_AUTH,
+	})
+
+	if user := handshake(t, s, 0x00, "", ""); user == nil {
+		t.Error("expect no auth accepted")
+	}
+
+	common.Must(s.AddUser(context.Background(), &protocol.MemoryUser{
+		Account: &Account{Username: "a", Password: "pa"},
+	}))
+	if user := handshake(t, s, 0x00, "", ""); user != nil {
+		t.Error("expect no auth rejected after adding a user, but got ", user)
+	}
+	if user := handshake(t, s, 0x02, "a", "pa"); user == nil || user.Email != "a" {
+		t.Error("expect user a, but got ", user)
+	}
+
+	// Removing all users doesn't open the server.
+	common.Must(s.RemoveUser(context.Background(), "a"))
+	if user := handshake(t, s, 0x00, "", ""); user != nil {
+		t.Error("expect no auth rejected after removing all users, but got ", user)
+	}
+}
@@ -3,6 +3,8 @@
 package vmess
 
 import (
+	"time"
+
 	"v2ray.com/core/common/dice"
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/uuid"
@@ -16,6 +18,13 @@ type MemoryAccount struct {
 	AlterIDs []*protocol.ID
 	// Security type of the account. Used for client connections.
 	Security protocol.SecurityType
+	// Expire is the time when the account expires. The account never expires if it is zero.
+	Expire time.Time
+}
+
+// Expired returns whether the account has expired at the time.
+func (a *MemoryAccount) Expired(t time.Time) bool {
+	return !a.Expire.IsZero() && !t.Before(a.Expire)
 }
 
 // AnyValidID returns an ID that is either the main ID or one of the alternative IDs if any.
@@ -43,9 +52,13 @@ func (a *Account) AsAccount() (protocol.Account, error) {
 		return nil, newError("failed to parse ID").Base(err).AtError()
 	}
 	protoID := protocol.NewID(id)
-	return &MemoryAccount{
+	account := &MemoryAccount{
 		ID:       protoID,
 		AlterIDs: protocol.NewAlterIDs(protoID, uint16(a.AlterId)),
 		Security: a.SecuritySettings.GetSecurityType(),
-	}, nil
+	}
+	if a.Expire > 0 {
+		account.Expire = time.Unix(a.Expire, 0)
+	}
+	return account, nil
 }
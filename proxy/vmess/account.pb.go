@@ -24,10 +24,13 @@ type Account struct {
 	// Number of alternative IDs. Client and server must share the same number.
 	AlterId uint32 `protobuf:"varint,2,opt,name=alter_id,json=alterId,proto3" json:"alter_id,omitempty"`
 	// Security settings. Only applies to client side.
-	SecuritySettings     *protocol.SecurityConfig `protobuf:"bytes,3,opt,name=security_settings,json=securitySettings,proto3" json:"security_settings,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
-	XXX_unrecognized     []byte                   `json:"-"`
-	XXX_sizecache        int32                    `json:"-"`
+	SecuritySettings *protocol.SecurityConfig `protobuf:"bytes,3,opt,name=security_settings,json=securitySettings,proto3" json:"security_settings,omitempty"`
+	// Unix time in seconds when the account expires. The account never expires
+	// if 0. Only applies to server side.
+	Expire               int64    `protobuf:"varint,4,opt,name=expire,proto3" json:"expire,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Account) Reset()         { *m = Account{} }
@@ -76,6 +79,13 @@ func (m *Account) GetSecuritySettings() *protocol.SecurityConfig {
 	return nil
 }
 
+func (m *Account) GetExpire() int64 {
+	if m != nil {
+		return m.Expire
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "v2ray.core.proxy.vmess.Account")
 }
@@ -85,21 +95,21 @@ func init() {
 }
 
 var fileDescriptor_d65dee31e5abbda0 = []byte{
-	// 244 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8f, 0xc1, 0x4a, 0x03, 0x31,
-	0x10, 0x86, 0xc9, 0x8a, 0x56, 0x23, 0x8a, 0xee, 0xa1, 0xac, 0x3d, 0x2d, 0x9e, 0x16, 0x91, 0x09,
-	0xac, 0x77, 0x41, 0x7b, 0xf2, 0x56, 0xb6, 0x50, 0xc1, 0x4b, 0x89, 0x49, 0xac, 0x81, 0x66, 0xa7,
-	0x4c, 0xd2, 0x62, 0x1e, 0xc2, 0x17, 0xf1, 0x29, 0xa5, 0xd9, 0x5d, 0x10, 0xe9, 0x2d, 0x61, 0xbe,
-	0xff, 0xfb, 0x67, 0x78, 0xb5, 0xab, 0x49, 0x46, 0x50, 0xe8, 0x84, 0x42, 0x32, 0x62, 0x43, 0xf8,
-	0x15, 0xc5, 0xce, 0x19, 0xef, 0x85, 0x54, 0x0a, 0xb7, 0x6d, 0x80, 0x0d, 0x61, 0xc0, 0x7c, 0x3c,
-	0x90, 0x64, 0x20, 0x51, 0x90, 0xa8, 0xc9, 0xfd, 0x3f, 0x83, 0x42, 0xe7, 0xb0, 0x15, 0x29, 0xa4,
-	0x70, 0x2d, 0x3e, 0x8d, 0xd4, 0x86, 0x7c, 0x67, 0xb9, 0xfd, 0x66, 0x7c, 0xf4, 0xd4, 0x79, 0xf3,
-	0x4b, 0x9e, 0x59, 0x5d, 0xb0, 0x92, 0x55, 0x67, 0x4d, 0x66, 0x75, 0x7e, 0xc3, 0x4f, 0xe5, 0x3a,
-	0x18, 0x5a, 0x5a, 0x5d, 0x64, 0x25, 0xab, 0x2e, 0x9a, 0x51, 0xfa, 0xbf, 0xe8, 0xfc, 0x95, 0x5f,
-	0x7b, 0xa3, 0xb6, 0x64, 0x43, 0x5c, 0x7a, 0x13, 0x82, 0x6d, 0x57, 0xbe, 0x38, 0x2a, 0x59, 0x75,
-	0x5e, 0xdf, 0xc1, 0x9f, 0xc5, 0xba, 0x72, 0x18, 0xca, 0x61, 0xde, 0x87, 0xa6, 0xd8, 0x7e, 0xd8,
-	0x55, 0x73, 0x35, 0x48, 0xe6, 0xbd, 0xe3, 0xf9, 0x91, 0x4f, 0x14, 0x3a, 0x38, 0x7c, 0xdb, 0x8c,
-	0xbd, 0x1d, 0xa7, 0xc7, 0x4f, 0x36, 0x5e, 0xd4, 0x8d, 0x8c, 0x30, 0xdd, 0x13, 0xb3, 0x44, 0x2c,
-	0xf6, 0x83, 0xf7, 0x93, 0x54, 0xf5, 0xf0, 0x1b, 0x00, 0x00, 0xff, 0xff, 0x82, 0xf9, 0x1b, 0xdb,
-	0x48, 0x01, 0x00, 0x00,
+	// 255 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x8f, 0x41, 0x4b, 0xc3, 0x30,
+	0x14, 0xc7, 0x49, 0xa7, 0x9b, 0x46, 0x14, 0xed, 0xa1, 0xd4, 0x9d, 0x8a, 0xa7, 0x22, 0x92, 0x40,
+	0xbd, 0x0b, 0xba, 0x93, 0xb7, 0x91, 0xc1, 0x04, 0x2f, 0x23, 0x26, 0xcf, 0x19, 0x58, 0xfa, 0x4a,
+	0x92, 0x8d, 0xf5, 0x03, 0x79, 0xf1, 0x53, 0xca, 0xd2, 0x16, 0x44, 0x76, 0xcb, 0x23, 0xbf, 0xf7,
+	0xfb, 0xff, 0x1f, 0x2d, 0x77, 0x95, 0x93, 0x2d, 0x53, 0x68, 0xb9, 0x42, 0x07, 0xbc, 0x71, 0xb8,
+	0x6f, 0xf9, 0xce, 0x82, 0xf7, 0x5c, 0x2a, 0x85, 0xdb, 0x3a, 0xb0, 0xc6, 0x61, 0xc0, 0x34, 0x1b,
+	0x48, 0x07, 0x2c, 0x52, 0x2c, 0x52, 0xd3, 0x87, 0x7f, 0x06, 0x85, 0xd6, 0x62, 0xcd, 0xe3, 0x92,
+	0xc2, 0x0d, 0xff, 0x02, 0xa9, 0xc1, 0xf9, 0xce, 0x72, 0xf7, 0x4d, 0xe8, 0xe4, 0xb9, 0xf3, 0xa6,
+	0x57, 0x34, 0x31, 0x3a, 0x27, 0x05, 0x29, 0xcf, 0x45, 0x62, 0x74, 0x7a, 0x4b, 0xcf, 0xe4, 0x26,
+	0x80, 0x5b, 0x19, 0x9d, 0x27, 0x05, 0x29, 0x2f, 0xc5, 0x24, 0xce, 0xaf, 0x3a, 0x7d, 0xa3, 0x37,
+	0x1e, 0xd4, 0xd6, 0x99, 0xd0, 0xae, 0x3c, 0x84, 0x60, 0xea, 0xb5, 0xcf, 0x47, 0x05, 0x29, 0x2f,
+	0xaa, 0x7b, 0xf6, 0xa7, 0x58, 0x17, 0xce, 0x86, 0x70, 0xb6, 0xe8, 0x97, 0x66, 0x58, 0x7f, 0x9a,
+	0xb5, 0xb8, 0x1e, 0x24, 0x8b, 0xde, 0x91, 0x66, 0x74, 0x0c, 0xfb, 0xc6, 0x38, 0xc8, 0x4f, 0x0a,
+	0x52, 0x8e, 0x44, 0x3f, 0xbd, 0x3c, 0xd1, 0xa9, 0x42, 0xcb, 0x8e, 0xdf, 0x3c, 0x27, 0xef, 0xa7,
+	0xf1, 0xf1, 0x93, 0x64, 0xcb, 0x4a, 0xc8, 0x96, 0xcd, 0x0e, 0xc4, 0x3c, 0x12, 0xcb, 0xc3, 0xc7,
+	0xc7, 0x38, 0x56, 0x78, 0xfc, 0x1d, 0x00, 0x90, 0x91, 0xcd, 0x4e, 0x60, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,63 @@
+package aead_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"v2ray.com/core/common"
+	. "v2ray.com/core/proxy/vmess/aead"
+)
+
+func TestAuthIDMatch(t *testing.T) {
+	var key, otherKey [16]byte
+	copy(key[:], "Demo Key for Auth ID Test")
+	copy(otherKey[:], "Other Key for Auth ID Test")
+
+	holder := NewAuthIDDecoderHolder()
+	holder.AddUser(key, "Demo User")
+	holder.AddUser(otherKey, "Other User")
+
+	authID := CreateAuthID(key[:], time.Now().Unix())
+	ticket, err := holder.Match(authID)
+	common.Must(err)
+	if ticket != "Demo User" {
+		t.Error("unexpected ticket: ", ticket)
+	}
+
+	if _, err := holder.Match(authID); err != ErrReplay {
+		t.Error("expected replay error, but got ", err)
+	}
+
+	if _, err := holder.Match(CreateAuthID(key[:], time.Now().Unix()-600)); err != ErrInvalidTime {
+		t.Error("expected invalid time error, but got ", err)
+	}
+
+	holder.RemoveUser(key)
+	if _, err := holder.Match(CreateAuthID(key[:], time.Now().Unix())); err != ErrNotFound {
+		t.Error("expected not found error, but got ", err)
+	}
+}
+
+func TestOpenVMessAEADHeader(t *testing.T) {
+	var key [16]byte
+	copy(key[:], "Demo Key for Header Test")
+	payload := []byte("Test VMess AEAD header payload")
+
+	sealed := SealVMessAEADHeader(key, payload)
+
+	var authID [16]byte
+	copy(authID[:], sealed[:16])
+	opened, err := OpenVMessAEADHeader(key, authID, bytes.NewReader(sealed[16:]))
+	common.Must(err)
+	if r := cmp.Diff(opened, payload); r != "" {
+		t.Error(r)
+	}
+
+	sealed[len(sealed)-1] ^= 1
+	if _, err := OpenVMessAEADHeader(key, authID, bytes.NewReader(sealed[16:])); err == nil {
+		t.Error("nil error")
+	}
+}
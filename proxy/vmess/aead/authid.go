@@ -0,0 +1,127 @@
+package aead
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+	"sync"
+	"time"
+
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/antireplay"
+)
+
+var (
+	ErrNotFound    = newError("user not found")
+	ErrReplay      = newError("replayed request")
+	ErrInvalidTime = newError("invalid timestamp, perhaps unsynchronized time")
+)
+
+// authIDWindow is the time window in seconds that auth IDs are accepted in.
+const authIDWindow = 120
+
+// CreateAuthID creates an auth ID of the command key at the time in unix seconds. An auth ID is a 16-byte block of
+// the time, 4 random bytes and the CRC32 of them, encrypted with a key derived from the command key.
+func CreateAuthID(cmdKey []byte, time int64) [16]byte {
+	var plain [16]byte
+	binary.BigEndian.PutUint64(plain[:8], uint64(time))
+	common.Must2(rand.Read(plain[8:12]))
+	binary.BigEndian.PutUint32(plain[12:], crc32.ChecksumIEEE(plain[:12]))
+
+	var result [16]byte
+	NewCipherFromKey(cmdKey).Encrypt(result[:], plain[:])
+	return result
+}
+
+// NewCipherFromKey creates the cipher that encrypts auth IDs of the command key.
+func NewCipherFromKey(cmdKey []byte) cipher.Block {
+	block, err := aes.NewCipher(KDF16(cmdKey, KDFSaltConstAuthIDEncryptionKey))
+	common.Must(err)
+	return block
+}
+
+// AuthIDDecoder decodes auth IDs of a command key.
+type AuthIDDecoder struct {
+	block cipher.Block
+}
+
+// NewAuthIDDecoder creates an AuthIDDecoder of the command key.
+func NewAuthIDDecoder(cmdKey []byte) *AuthIDDecoder {
+	return &AuthIDDecoder{block: NewCipherFromKey(cmdKey)}
+}
+
+// Decode decrypts the auth ID, and returns its time, random bytes, CRC32 and the expected CRC32.
+func (d *AuthIDDecoder) Decode(data [16]byte) (int64, uint32, uint32, uint32) {
+	d.block.Decrypt(data[:], data[:])
+	t := int64(binary.BigEndian.Uint64(data[:8]))
+	random := binary.BigEndian.Uint32(data[8:12])
+	crc := binary.BigEndian.Uint32(data[12:])
+	return t, random, crc, crc32.ChecksumIEEE(data[:12])
+}
+
+// AuthIDDecoderHolder matches auth IDs against command keys of users, and filters replayed auth IDs.
+type AuthIDDecoderHolder struct {
+	sync.RWMutex
+	decoders map[string]*authIDDecoderItem
+	filter   *antireplay.ReplayFilter
+}
+
+type authIDDecoderItem struct {
+	decoder *AuthIDDecoder
+	ticket  interface{}
+}
+
+// NewAuthIDDecoderHolder creates a new AuthIDDecoderHolder.
+func NewAuthIDDecoderHolder() *AuthIDDecoderHolder {
+	return &AuthIDDecoderHolder{
+		decoders: make(map[string]*authIDDecoderItem),
+		// An auth ID is accepted within the window before and after its time, so it must be remembered for twice the
+		// window.
+		filter: antireplay.NewReplayFilter(2 * authIDWindow),
+	}
+}
+
+// AddUser adds the command key of a user, whose auth IDs are matched to the ticket.
+func (h *AuthIDDecoderHolder) AddUser(cmdKey [16]byte, ticket interface{}) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.decoders[string(cmdKey[:])] = &authIDDecoderItem{
+		decoder: NewAuthIDDecoder(cmdKey[:]),
+		ticket:  ticket,
+	}
+}
+
+// RemoveUser removes the command key of a user.
+func (h *AuthIDDecoderHolder) RemoveUser(cmdKey [16]byte) {
+	h.Lock()
+	defer h.Unlock()
+
+	delete(h.decoders, string(cmdKey[:]))
+}
+
+// Match returns the ticket of the user that the auth ID is created by. It returns ErrReplay if the auth ID is seen
+// in the time window, or ErrInvalidTime if the auth ID is created out of the time window.
+func (h *AuthIDDecoderHolder) Match(authID [16]byte) (interface{}, error) {
+	h.RLock()
+	defer h.RUnlock()
+
+	now := time.Now().Unix()
+	for _, item := range h.decoders {
+		t, _, crc, expected := item.decoder.Decode(authID)
+		if crc != expected {
+			continue
+		}
+		if math.Abs(float64(t-now)) > authIDWindow {
+			return nil, ErrInvalidTime
+		}
+		if !h.filter.Check(authID[:]) {
+			return nil, ErrReplay
+		}
+		return item.ticket, nil
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,83 @@
+package aead
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"v2ray.com/core/common"
+)
+
+func newGCM(key []byte) cipher.AEAD {
+	block, err := aes.NewCipher(key)
+	common.Must(err)
+	aead, err := cipher.NewGCM(block)
+	common.Must(err)
+	return aead
+}
+
+// SealVMessAEADHeader seals the request header with the command key. The sealed header consists of an auth ID, the
+// encrypted length of the header, a connection nonce and the encrypted header.
+func SealVMessAEADHeader(key [16]byte, data []byte) []byte {
+	generatedAuthID := CreateAuthID(key[:], time.Now().Unix())
+
+	var connectionNonce [8]byte
+	common.Must2(rand.Read(connectionNonce[:]))
+
+	var lengthBytes [2]byte
+	binary.BigEndian.PutUint16(lengthBytes[:], uint16(len(data)))
+
+	lengthAEAD := newGCM(KDF16(key[:], KDFSaltConstVMessHeaderPayloadLengthAEADKey, string(generatedAuthID[:]), string(connectionNonce[:])))
+	lengthNonce := KDF(key[:], KDFSaltConstVMessHeaderPayloadLengthAEADIV, string(generatedAuthID[:]), string(connectionNonce[:]))[:12]
+	encryptedLength := lengthAEAD.Seal(nil, lengthNonce, lengthBytes[:], generatedAuthID[:])
+
+	payloadAEAD := newGCM(KDF16(key[:], KDFSaltConstVMessHeaderPayloadAEADKey, string(generatedAuthID[:]), string(connectionNonce[:])))
+	payloadNonce := KDF(key[:], KDFSaltConstVMessHeaderPayloadAEADIV, string(generatedAuthID[:]), string(connectionNonce[:]))[:12]
+	encryptedPayload := payloadAEAD.Seal(nil, payloadNonce, data, generatedAuthID[:])
+
+	output := bytes.NewBuffer(make([]byte, 0, 16+len(encryptedLength)+8+len(encryptedPayload)))
+	common.Must2(output.Write(generatedAuthID[:]))
+	common.Must2(output.Write(encryptedLength))
+	common.Must2(output.Write(connectionNonce[:]))
+	common.Must2(output.Write(encryptedPayload))
+	return output.Bytes()
+}
+
+// OpenVMessAEADHeader reads the rest of a sealed request header after its auth ID from the reader, and opens it with
+// the command key.
+func OpenVMessAEADHeader(key [16]byte, authid [16]byte, reader io.Reader) ([]byte, error) {
+	var encryptedLength [2 + 16]byte
+	var connectionNonce [8]byte
+
+	if _, err := io.ReadFull(reader, encryptedLength[:]); err != nil {
+		return nil, newError("failed to read header length").Base(err)
+	}
+	if _, err := io.ReadFull(reader, connectionNonce[:]); err != nil {
+		return nil, newError("failed to read connection nonce").Base(err)
+	}
+
+	lengthAEAD := newGCM(KDF16(key[:], KDFSaltConstVMessHeaderPayloadLengthAEADKey, string(authid[:]), string(connectionNonce[:])))
+	lengthNonce := KDF(key[:], KDFSaltConstVMessHeaderPayloadLengthAEADIV, string(authid[:]), string(connectionNonce[:]))[:12]
+	lengthBytes, err := lengthAEAD.Open(nil, lengthNonce, encryptedLength[:], authid[:])
+	if err != nil {
+		return nil, newError("failed to decrypt header length").Base(err)
+	}
+	length := binary.BigEndian.Uint16(lengthBytes)
+
+	encryptedPayload := make([]byte, int(length)+16)
+	if _, err := io.ReadFull(reader, encryptedPayload); err != nil {
+		return nil, newError("failed to read header").Base(err)
+	}
+
+	payloadAEAD := newGCM(KDF16(key[:], KDFSaltConstVMessHeaderPayloadAEADKey, string(authid[:]), string(connectionNonce[:])))
+	payloadNonce := KDF(key[:], KDFSaltConstVMessHeaderPayloadAEADIV, string(authid[:]), string(connectionNonce[:]))[:12]
+	payload, err := payloadAEAD.Open(nil, payloadNonce, encryptedPayload, authid[:])
+	if err != nil {
+		return nil, newError("failed to decrypt header").Base(err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,36 @@
+package aead
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// hmacCreator creates HMAC-SHA256 nested by a path of keys, so that each key of the path derives a distinct hash.
+type hmacCreator struct {
+	parent *hmacCreator
+	value  []byte
+}
+
+func (h *hmacCreator) Create() hash.Hash {
+	if h.parent == nil {
+		return hmac.New(sha256.New, h.value)
+	}
+	return hmac.New(h.parent.Create, h.value)
+}
+
+// KDF derives a 32-byte key from the key, along the path.
+func KDF(key []byte, path ...string) []byte {
+	creator := &hmacCreator{value: []byte(KDFSaltConstVMessAEADKDF)}
+	for _, v := range path {
+		creator = &hmacCreator{value: []byte(v), parent: creator}
+	}
+	h := creator.Create()
+	h.Write(key) // nolint: errcheck
+	return h.Sum(nil)
+}
+
+// KDF16 derives a 16-byte key from the key, along the path.
+func KDF16(key []byte, path ...string) []byte {
+	return KDF(key, path...)[:16]
+}
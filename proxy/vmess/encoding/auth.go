@@ -2,10 +2,14 @@ package encoding
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/binary"
 	"hash/fnv"
+	"io"
 
 	"v2ray.com/core/common"
+	"v2ray.com/core/common/crypto"
+	vmessaead "v2ray.com/core/proxy/vmess/aead"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -117,3 +121,54 @@ func (s *ShakeSizeParser) NextPaddingLen() uint16 {
 func (s *ShakeSizeParser) MaxPaddingLen() uint16 {
 	return 64
 }
+
+// sha256Key derives a response key from a request key, for requests with AEAD headers.
+func sha256Key(key [16]byte) [16]byte {
+	var result [16]byte
+	sum := sha256.Sum256(key[:])
+	copy(result[:], sum[:16])
+	return result
+}
+
+// sealResponseHeader seals the response header of a request with AEAD header, as the encrypted length of the header
+// followed by the encrypted header.
+func sealResponseHeader(key, iv [16]byte, header []byte) []byte {
+	var lengthBytes [2]byte
+	binary.BigEndian.PutUint16(lengthBytes[:], uint16(len(header)))
+
+	lengthAEAD := crypto.NewAesGcm(vmessaead.KDF16(key[:], vmessaead.KDFSaltConstAEADRespHeaderLenKey))
+	lengthNonce := vmessaead.KDF(iv[:], vmessaead.KDFSaltConstAEADRespHeaderLenIV)[:lengthAEAD.NonceSize()]
+	output := lengthAEAD.Seal(nil, lengthNonce, lengthBytes[:], nil)
+
+	payloadAEAD := crypto.NewAesGcm(vmessaead.KDF16(key[:], vmessaead.KDFSaltConstAEADRespHeaderPayloadKey))
+	payloadNonce := vmessaead.KDF(iv[:], vmessaead.KDFSaltConstAEADRespHeaderPayloadIV)[:payloadAEAD.NonceSize()]
+	return payloadAEAD.Seal(output, payloadNonce, header, nil)
+}
+
+// openResponseHeader reads and opens a response header sealed by sealResponseHeader.
+func openResponseHeader(key, iv [16]byte, reader io.Reader) ([]byte, error) {
+	lengthAEAD := crypto.NewAesGcm(vmessaead.KDF16(key[:], vmessaead.KDFSaltConstAEADRespHeaderLenKey))
+	lengthNonce := vmessaead.KDF(iv[:], vmessaead.KDFSaltConstAEADRespHeaderLenIV)[:lengthAEAD.NonceSize()]
+
+	encryptedLength := make([]byte, 2+lengthAEAD.Overhead())
+	if _, err := io.ReadFull(reader, encryptedLength); err != nil {
+		return nil, newError("failed to read response header length").Base(err)
+	}
+	lengthBytes, err := lengthAEAD.Open(nil, lengthNonce, encryptedLength, nil)
+	if err != nil {
+		return nil, newError("failed to decrypt response header length").Base(err)
+	}
+
+	payloadAEAD := crypto.NewAesGcm(vmessaead.KDF16(key[:], vmessaead.KDFSaltConstAEADRespHeaderPayloadKey))
+	payloadNonce := vmessaead.KDF(iv[:], vmessaead.KDFSaltConstAEADRespHeaderPayloadIV)[:payloadAEAD.NonceSize()]
+
+	encryptedPayload := make([]byte, int(binary.BigEndian.Uint16(lengthBytes))+payloadAEAD.Overhead())
+	if _, err := io.ReadFull(reader, encryptedPayload); err != nil {
+		return nil, newError("failed to read response header").Base(err)
+	}
+	payload, err := payloadAEAD.Open(nil, payloadNonce, encryptedPayload, nil)
+	if err != nil {
+		return nil, newError("failed to decrypt response header").Base(err)
+	}
+	return payload, nil
+}
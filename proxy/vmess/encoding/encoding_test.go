@@ -42,7 +42,7 @@ func TestRequestSerialization(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(true, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -92,7 +92,7 @@ func TestInvalidRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(true, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -133,7 +133,7 @@ func TestMuxRequest(t *testing.T) {
 	}
 
 	buffer := buf.New()
-	client := NewClientSession(protocol.DefaultIDHash)
+	client := NewClientSession(true, protocol.DefaultIDHash)
 	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
 
 	buffer2 := buf.New()
@@ -154,3 +154,97 @@ func TestMuxRequest(t *testing.T) {
 		t.Error(r)
 	}
 }
+
+func TestLegacyRequestSerialization(t *testing.T) {
+	user := &protocol.MemoryUser{
+		Level: 0,
+		Email: "test@v2ray.com",
+	}
+	id := uuid.New()
+	account := &vmess.Account{
+		Id:      id.String(),
+		AlterId: 4,
+	}
+	user.Account = toAccount(account)
+
+	expectedRequest := &protocol.RequestHeader{
+		Version:  1,
+		User:     user,
+		Command:  protocol.RequestCommandTCP,
+		Address:  net.DomainAddress("www.v2ray.com"),
+		Port:     net.Port(443),
+		Security: protocol.SecurityType_AES128_GCM,
+	}
+
+	buffer := buf.New()
+	client := NewClientSession(false, protocol.DefaultIDHash)
+	common.Must(client.EncodeRequestHeader(expectedRequest, buffer))
+
+	sessionHistory := NewSessionHistory()
+	defer common.Close(sessionHistory)
+
+	userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+	userValidator.Add(user)
+	defer common.Close(userValidator)
+
+	server := NewServerSession(userValidator, sessionHistory)
+	actualRequest, err := server.DecodeRequestHeader(buffer)
+	common.Must(err)
+
+	if r := cmp.Diff(actualRequest, expectedRequest, cmp.AllowUnexported(protocol.ID{})); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestResponseSerialization(t *testing.T) {
+	for _, alterID := range []uint32{0, 4} {
+		user := &protocol.MemoryUser{
+			Level: 0,
+			Email: "test@v2ray.com",
+		}
+		id := uuid.New()
+		user.Account = toAccount(&vmess.Account{
+			Id:      id.String(),
+			AlterId: alterID,
+		})
+
+		request := &protocol.RequestHeader{
+			Version:  1,
+			User:     user,
+			Command:  protocol.RequestCommandTCP,
+			Address:  net.DomainAddress("www.v2ray.com"),
+			Port:     net.Port(443),
+			Security: protocol.SecurityType_AES128_GCM,
+			Option:   protocol.RequestOptionChunkStream,
+		}
+
+		buffer := buf.New()
+		client := NewClientSession(alterID == 0, protocol.DefaultIDHash)
+		common.Must(client.EncodeRequestHeader(request, buffer))
+
+		sessionHistory := NewSessionHistory()
+		defer common.Close(sessionHistory)
+
+		userValidator := vmess.NewTimedUserValidator(protocol.DefaultIDHash)
+		userValidator.Add(user)
+		defer common.Close(userValidator)
+
+		server := NewServerSession(userValidator, sessionHistory)
+		actualRequest, err := server.DecodeRequestHeader(buffer)
+		common.Must(err)
+
+		response := buf.New()
+		server.EncodeResponseHeader(&protocol.ResponseHeader{}, response)
+		bodyWriter := server.EncodeResponseBody(actualRequest, response)
+		common.Must(bodyWriter.WriteMultiBuffer(buf.MergeBytes(nil, []byte("test response"))))
+
+		if _, err := client.DecodeResponseHeader(response); err != nil {
+			t.Fatal("alterId ", alterID, ": ", err)
+		}
+		mb, err := client.DecodeResponseBody(request, response).ReadMultiBuffer()
+		common.Must(err)
+		if r := cmp.Diff(mb.String(), "test response"); r != "" {
+			t.Error("alterId ", alterID, ": ", r)
+		}
+	}
+}
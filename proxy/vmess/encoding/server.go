@@ -1,6 +1,7 @@
 package encoding
 
 import (
+	"bytes"
 	"crypto/md5"
 	"encoding/binary"
 	"hash/fnv"
@@ -18,6 +19,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/proxy/vmess"
+	vmessaead "v2ray.com/core/proxy/vmess/aead"
 )
 
 type sessionId struct {
@@ -97,6 +99,7 @@ type ServerSession struct {
 	responseBodyIV  [16]byte
 	responseWriter  io.Writer
 	responseHeader  byte
+	isAEADRequest   bool
 }
 
 // NewServerSession creates a new ServerSession, using the given UserValidator.
@@ -129,17 +132,36 @@ func (s *ServerSession) DecodeRequestHeader(reader io.Reader) (*protocol.Request
 		return nil, newError("failed to read request header").Base(err)
 	}
 
-	user, timestamp, valid := s.userValidator.Get(buffer.Bytes())
-	if !valid {
-		return nil, newError("invalid user")
+	var decryptor io.Reader
+	user, found, err := s.userValidator.GetAEAD(buffer.Bytes())
+	if err != nil {
+		return nil, newError("invalid user").Base(err)
 	}
+	if found {
+		var authID [16]byte
+		copy(authID[:], buffer.Bytes())
+		var cmdKey [16]byte
+		copy(cmdKey[:], user.Account.(*vmess.MemoryAccount).ID.CmdKey())
+		header, err := vmessaead.OpenVMessAEADHeader(cmdKey, authID, reader)
+		if err != nil {
+			return nil, newError("invalid user").Base(err)
+		}
+		s.isAEADRequest = true
+		decryptor = bytes.NewReader(header)
+	} else {
+		var timestamp protocol.Timestamp
+		var valid bool
+		user, timestamp, valid = s.userValidator.Get(buffer.Bytes())
+		if !valid {
+			return nil, newError("invalid user")
+		}
 
-	iv := hashTimestamp(md5.New(), timestamp)
+		iv := hashTimestamp(md5.New(), timestamp)
+		aesStream := crypto.NewAesDecryptionStream(user.Account.(*vmess.MemoryAccount).ID.CmdKey(), iv[:])
+		decryptor = crypto.NewCryptionReader(aesStream, reader)
+	}
 	vmessAccount := user.Account.(*vmess.MemoryAccount)
 
-	aesStream := crypto.NewAesDecryptionStream(vmessAccount.ID.CmdKey(), iv[:])
-	decryptor := crypto.NewCryptionReader(aesStream, reader)
-
 	buffer.Clear()
 	if _, err := buffer.ReadFullFrom(decryptor, 38); err != nil {
 		return nil, newError("failed to read request header").Base(err)
@@ -273,13 +295,28 @@ func (s *ServerSession) DecodeRequestBody(request *protocol.RequestHeader, reade
 
 // EncodeResponseHeader writes encoded response header into the given writer.
 func (s *ServerSession) EncodeResponseHeader(header *protocol.ResponseHeader, writer io.Writer) {
-	s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
-	s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	if s.isAEADRequest {
+		s.responseBodyKey = sha256Key(s.requestBodyKey)
+		s.responseBodyIV = sha256Key(s.requestBodyIV)
+	} else {
+		s.responseBodyKey = md5.Sum(s.requestBodyKey[:])
+		s.responseBodyIV = md5.Sum(s.requestBodyIV[:])
+	}
 
 	aesStream := crypto.NewAesEncryptionStream(s.responseBodyKey[:], s.responseBodyIV[:])
 	encryptionWriter := crypto.NewCryptionWriter(aesStream, writer)
 	s.responseWriter = encryptionWriter
 
+	if s.isAEADRequest {
+		headerBuffer := bytes.NewBuffer(nil)
+		common.Must2(headerBuffer.Write([]byte{s.responseHeader, byte(header.Option)}))
+		if err := MarshalCommand(header.Command, headerBuffer); err != nil {
+			common.Must2(headerBuffer.Write([]byte{0x00, 0x00}))
+		}
+		common.Must2(writer.Write(sealResponseHeader(s.responseBodyKey, s.responseBodyIV, headerBuffer.Bytes())))
+		return
+	}
+
 	common.Must2(encryptionWriter.Write([]byte{s.responseHeader, byte(header.Option)}))
 	err := MarshalCommand(header.Command, encryptionWriter)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	math "math"
+	net "v2ray.com/core/common/net"
 	protocol "v2ray.com/core/common/protocol"
 )
 
@@ -104,21 +105,76 @@ func (m *DefaultConfig) GetLevel() uint32 {
 	return 0
 }
 
+// FallbackConfig is the destination that invalid handshakes are forwarded to,
+// such as a local web server.
+type FallbackConfig struct {
+	Address              *net.IPOrDomain `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Port                 uint32          `protobuf:"varint,2,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *FallbackConfig) Reset()         { *m = FallbackConfig{} }
+func (m *FallbackConfig) String() string { return proto.CompactTextString(m) }
+func (*FallbackConfig) ProtoMessage()    {}
+func (*FallbackConfig) Descriptor() ([]byte, []int) {
+	return fileDescriptor_a47d4a41f33382d2, []int{2}
+}
+
+func (m *FallbackConfig) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FallbackConfig.Unmarshal(m, b)
+}
+func (m *FallbackConfig) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FallbackConfig.Marshal(b, m, deterministic)
+}
+func (m *FallbackConfig) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FallbackConfig.Merge(m, src)
+}
+func (m *FallbackConfig) XXX_Size() int {
+	return xxx_messageInfo_FallbackConfig.Size(m)
+}
+func (m *FallbackConfig) XXX_DiscardUnknown() {
+	xxx_messageInfo_FallbackConfig.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FallbackConfig proto.InternalMessageInfo
+
+func (m *FallbackConfig) GetAddress() *net.IPOrDomain {
+	if m != nil {
+		return m.Address
+	}
+	return nil
+}
+
+func (m *FallbackConfig) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
 type Config struct {
 	User                 []*protocol.User `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
 	Default              *DefaultConfig   `protobuf:"bytes,2,opt,name=default,proto3" json:"default,omitempty"`
 	Detour               *DetourConfig    `protobuf:"bytes,3,opt,name=detour,proto3" json:"detour,omitempty"`
 	SecureEncryptionOnly bool             `protobuf:"varint,4,opt,name=secure_encryption_only,json=secureEncryptionOnly,proto3" json:"secure_encryption_only,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	// If set, invalid handshakes are drained for a random time before the
+	// connection is closed, instead of being closed right away.
+	ProbeResistant bool `protobuf:"varint,5,opt,name=probe_resistant,json=probeResistant,proto3" json:"probe_resistant,omitempty"`
+	// If set, invalid handshakes are forwarded to the fallback, with the bytes
+	// already read.
+	Fallback             *FallbackConfig `protobuf:"bytes,6,opt,name=fallback,proto3" json:"fallback,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *Config) Reset()         { *m = Config{} }
 func (m *Config) String() string { return proto.CompactTextString(m) }
 func (*Config) ProtoMessage()    {}
 func (*Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_a47d4a41f33382d2, []int{2}
+	return fileDescriptor_a47d4a41f33382d2, []int{3}
 }
 
 func (m *Config) XXX_Unmarshal(b []byte) error {
@@ -167,9 +223,24 @@ func (m *Config) GetSecureEncryptionOnly() bool {
 	return false
 }
 
+func (m *Config) GetProbeResistant() bool {
+	if m != nil {
+		return m.ProbeResistant
+	}
+	return false
+}
+
+func (m *Config) GetFallback() *FallbackConfig {
+	if m != nil {
+		return m.Fallback
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*DetourConfig)(nil), "v2ray.core.proxy.vmess.inbound.DetourConfig")
 	proto.RegisterType((*DefaultConfig)(nil), "v2ray.core.proxy.vmess.inbound.DefaultConfig")
+	proto.RegisterType((*FallbackConfig)(nil), "v2ray.core.proxy.vmess.inbound.FallbackConfig")
 	proto.RegisterType((*Config)(nil), "v2ray.core.proxy.vmess.inbound.Config")
 }
 
@@ -178,26 +249,32 @@ func init() {
 }
 
 var fileDescriptor_a47d4a41f33382d2 = []byte{
-	// 333 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x90, 0x4f, 0x4f, 0xf2, 0x40,
-	0x10, 0xc6, 0xd3, 0xc2, 0x0b, 0xbc, 0x8b, 0x78, 0x68, 0x88, 0xa9, 0x1e, 0x48, 0xd3, 0x13, 0x26,
-	0xba, 0x9b, 0x54, 0x3e, 0x80, 0x11, 0x8c, 0xe1, 0x24, 0x69, 0x22, 0x07, 0x2f, 0xa4, 0x6c, 0x07,
-	0xd3, 0x64, 0xbb, 0x43, 0xb6, 0x5b, 0x62, 0xcf, 0x7e, 0x1b, 0x3f, 0xa5, 0x61, 0x5a, 0xfc, 0x77,
-	0x90, 0xdb, 0xce, 0xce, 0xef, 0x79, 0xe6, 0x99, 0x61, 0x62, 0x17, 0x99, 0xa4, 0xe2, 0x12, 0x73,
-	0x21, 0xd1, 0x80, 0xd8, 0x1a, 0x7c, 0xad, 0xc4, 0x2e, 0x87, 0xa2, 0x10, 0x99, 0x5e, 0x63, 0xa9,
-	0x53, 0x21, 0x51, 0x6f, 0xb2, 0x17, 0xbe, 0x35, 0x68, 0xd1, 0x1b, 0x1d, 0x04, 0x06, 0x38, 0xc1,
-	0x9c, 0x60, 0xde, 0xc0, 0x17, 0x97, 0xbf, 0x0c, 0x25, 0xe6, 0x39, 0x6a, 0x41, 0x62, 0x89, 0x4a,
-	0x94, 0x05, 0x98, 0xda, 0x2a, 0x1c, 0xb1, 0x93, 0x19, 0x58, 0x2c, 0xcd, 0x94, 0x06, 0x78, 0xa7,
-	0xcc, 0xb5, 0xe8, 0x3b, 0x81, 0x33, 0xfe, 0x1f, 0xbb, 0x16, 0xc3, 0x5b, 0x36, 0x98, 0xc1, 0x26,
-	0x29, 0x95, 0x6d, 0x80, 0x73, 0xd6, 0x4b, 0x94, 0x05, 0xb3, 0xca, 0x52, 0xc2, 0x06, 0x71, 0x97,
-	0xea, 0x79, 0xea, 0x0d, 0xd9, 0x3f, 0x05, 0x3b, 0x50, 0xbe, 0x4b, 0xff, 0x75, 0x11, 0xbe, 0xb9,
-	0xac, 0xd3, 0x68, 0x27, 0xac, 0xbd, 0x1f, 0xed, 0x3b, 0x41, 0x6b, 0xdc, 0x8f, 0x02, 0xfe, 0x6d,
-	0x8d, 0x3a, 0x22, 0x3f, 0x44, 0xe4, 0x4f, 0x05, 0x98, 0x98, 0x68, 0xef, 0x81, 0x75, 0xd3, 0x3a,
-	0x02, 0x19, 0xf7, 0xa3, 0x6b, 0xfe, 0xf7, 0xfe, 0xfc, 0x47, 0xe2, 0xf8, 0xa0, 0xf6, 0x66, 0xac,
-	0x93, 0xd2, 0xae, 0x7e, 0x8b, 0x7c, 0xae, 0x8e, 0xfb, 0x7c, 0x5d, 0x26, 0x6e, 0xb4, 0xde, 0x84,
-	0x9d, 0x15, 0x20, 0x4b, 0x03, 0x2b, 0xd0, 0xd2, 0x54, 0x5b, 0x9b, 0xa1, 0x5e, 0xa1, 0x56, 0x95,
-	0xdf, 0x0e, 0x9c, 0x71, 0x2f, 0x1e, 0xd6, 0xdd, 0xfb, 0xcf, 0xe6, 0xa3, 0x56, 0xd5, 0xdd, 0x82,
-	0x85, 0x12, 0xf3, 0x23, 0x03, 0x17, 0xce, 0x73, 0xb7, 0x79, 0xbe, 0xbb, 0xa3, 0x65, 0x14, 0x27,
-	0x15, 0x9f, 0xee, 0xd9, 0x05, 0xb1, 0x4b, 0x62, 0xe7, 0x35, 0xb0, 0xee, 0xd0, 0xad, 0x6e, 0x3e,
-	0x02, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x49, 0x84, 0xe1, 0x3e, 0x02, 0x00, 0x00,
+	// 430 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0x41, 0x6f, 0xd3, 0x30,
+	0x14, 0xc7, 0x95, 0xac, 0x6b, 0x8b, 0xcb, 0x8a, 0x64, 0x4d, 0x28, 0xec, 0x50, 0x95, 0x5c, 0x56,
+	0x24, 0xb0, 0xa5, 0xb0, 0x1b, 0x17, 0xc4, 0x0a, 0xa8, 0x5c, 0x56, 0x59, 0x62, 0x07, 0x2e, 0x95,
+	0xeb, 0xb8, 0x28, 0xc2, 0xf1, 0x8b, 0x1c, 0xa7, 0x22, 0x5f, 0x89, 0x4f, 0xc8, 0x11, 0xf5, 0xc5,
+	0x19, 0x0c, 0x4d, 0xeb, 0x2d, 0x7e, 0xfe, 0xfd, 0x7f, 0x79, 0x7e, 0x8f, 0xf0, 0x7d, 0xe6, 0x64,
+	0xcb, 0x14, 0x94, 0x5c, 0x81, 0xd3, 0xbc, 0x72, 0xf0, 0xb3, 0xe5, 0xfb, 0x52, 0xd7, 0x35, 0x2f,
+	0xec, 0x16, 0x1a, 0x9b, 0x73, 0x05, 0x76, 0x57, 0x7c, 0x67, 0x95, 0x03, 0x0f, 0x74, 0xd6, 0x07,
+	0x9c, 0x66, 0x08, 0x33, 0x84, 0x59, 0x80, 0x2f, 0x5e, 0xfd, 0x27, 0x54, 0x50, 0x96, 0x60, 0x39,
+	0x86, 0x15, 0x18, 0xde, 0xd4, 0xda, 0x75, 0xaa, 0x8b, 0xcb, 0x87, 0x51, 0xab, 0x3d, 0x97, 0x79,
+	0xee, 0x0e, 0x56, 0x04, 0xd3, 0x19, 0x79, 0xba, 0xd4, 0x1e, 0x1a, 0x77, 0x8d, 0x9d, 0xd0, 0x29,
+	0x89, 0x3d, 0x24, 0xd1, 0x3c, 0x5a, 0x3c, 0x11, 0xb1, 0x87, 0xf4, 0x3d, 0x39, 0x5b, 0xea, 0x9d,
+	0x6c, 0x8c, 0x0f, 0xc0, 0x0b, 0x32, 0x96, 0xc6, 0x6b, 0xb7, 0x29, 0x72, 0xc4, 0xce, 0xc4, 0x08,
+	0xcf, 0xab, 0x9c, 0x9e, 0x93, 0x53, 0xa3, 0xf7, 0xda, 0x24, 0x31, 0xd6, 0xbb, 0x43, 0x2a, 0xc9,
+	0xf4, 0x93, 0x34, 0x66, 0x2b, 0xd5, 0x8f, 0xa0, 0x78, 0x47, 0x46, 0xa1, 0x09, 0x34, 0x4c, 0xb2,
+	0x97, 0xec, 0x9f, 0x97, 0x77, 0xad, 0x32, 0xab, 0x3d, 0x5b, 0xad, 0x6f, 0xdc, 0x12, 0x4a, 0x59,
+	0x58, 0xd1, 0x27, 0x28, 0x25, 0x83, 0x0a, 0x9c, 0x0f, 0xff, 0xc0, 0xef, 0xf4, 0x77, 0x4c, 0x86,
+	0xc1, 0x7d, 0x45, 0x06, 0x87, 0x31, 0x24, 0xd1, 0xfc, 0x64, 0x31, 0xc9, 0xe6, 0x0f, 0x88, 0xfb,
+	0x71, 0xb1, 0xaf, 0xb5, 0x76, 0x02, 0x69, 0xfa, 0x99, 0x8c, 0xf2, 0xee, 0x95, 0xe8, 0x9d, 0x64,
+	0x6f, 0xd8, 0xe3, 0xbb, 0x60, 0xf7, 0x86, 0x22, 0xfa, 0x34, 0x5d, 0x92, 0x61, 0x8e, 0xe3, 0x4c,
+	0x4e, 0xd0, 0xf3, 0xfa, 0xb8, 0xe7, 0xef, 0xf0, 0x45, 0xc8, 0xd2, 0x2b, 0xf2, 0xbc, 0xd6, 0xaa,
+	0x71, 0x7a, 0xa3, 0xad, 0x72, 0x6d, 0xe5, 0x0b, 0xb0, 0x1b, 0xb0, 0xa6, 0x4d, 0x06, 0xf3, 0x68,
+	0x31, 0x16, 0xe7, 0xdd, 0xed, 0xc7, 0xbb, 0xcb, 0x1b, 0x6b, 0x5a, 0x7a, 0x49, 0x9e, 0x55, 0x0e,
+	0xb6, 0x7a, 0xe3, 0x74, 0x5d, 0xd4, 0x5e, 0x5a, 0x9f, 0x9c, 0x22, 0x3e, 0xc5, 0xb2, 0xe8, 0xab,
+	0xf4, 0x0b, 0x19, 0xef, 0xc2, 0x46, 0x92, 0x21, 0xb6, 0xc9, 0x8e, 0xb5, 0x79, 0x7f, 0x83, 0xe2,
+	0x2e, 0xff, 0x61, 0x4d, 0x52, 0x05, 0xe5, 0x91, 0xf8, 0x3a, 0xfa, 0x36, 0x0a, 0x9f, 0xbf, 0xe2,
+	0xd9, 0x6d, 0x26, 0x64, 0xcb, 0xae, 0x0f, 0xec, 0x1a, 0xd9, 0x5b, 0x64, 0x57, 0x1d, 0xb0, 0x1d,
+	0xe2, 0x82, 0xde, 0xfe, 0x19, 0x00, 0x48, 0x33, 0x41, 0x42, 0x3f, 0x03, 0x00, 0x00,
 }
@@ -0,0 +1,133 @@
+// +build !confonly
+
+package inbound
+
+import (
+	"context"
+	"time"
+
+	"v2ray.com/core/common/buf"
+	"v2ray.com/core/common/dice"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/task"
+	"v2ray.com/core/transport/internet"
+)
+
+const (
+	minDrainDuration = 2 * time.Second
+	maxDrainDuration = 16 * time.Second
+	// maxConcurrentDrains is the max number of invalid handshakes drained at the same time. Beyond it, connections are
+	// closed right away, so that a flood of invalid handshakes can't hold connections open.
+	maxConcurrentDrains = 256
+)
+
+// recordReader records bytes read from the connection during handshake, so that they can be forwarded to the
+// fallback if the handshake is invalid.
+type recordReader struct {
+	buf.Reader
+	records   buf.MultiBuffer
+	recording bool
+}
+
+func newRecordReader(reader buf.Reader) *recordReader {
+	return &recordReader{
+		Reader:    reader,
+		recording: true,
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader.
+func (r *recordReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	if r.recording {
+		for _, b := range mb {
+			record := buf.New()
+			record.Write(b.Bytes())
+			r.records = append(r.records, record)
+		}
+	}
+	return mb, err
+}
+
+// Stop stops recording, and returns the bytes recorded.
+func (r *recordReader) Stop() buf.MultiBuffer {
+	records := r.records
+	r.records = nil
+	r.recording = false
+	return records
+}
+
+// drainDuration returns a random duration to drain an invalid handshake for, so that the time the connection is
+// closed doesn't tell how much of the handshake is read.
+func drainDuration() time.Duration {
+	return minDrainDuration + time.Duration(dice.Roll(int((maxDrainDuration-minDrainDuration)/time.Millisecond)))*time.Millisecond
+}
+
+// drain reads and discards from the connection for a random duration, or until the peer closes it. It returns right
+// away if there are too many connections being drained.
+func (h *Handler) drain(connection internet.Connection, reader buf.Reader) {
+	select {
+	case <-h.drains.Wait():
+		defer h.drains.Signal()
+	default:
+		return
+	}
+
+	if err := connection.SetReadDeadline(time.Now().Add(drainDuration())); err != nil {
+		return
+	}
+	buf.Copy(reader, buf.Discard) // nolint: errcheck
+}
+
+// forwardToFallback forwards the connection to the fallback destination, starting with the bytes already read.
+func (h *Handler) forwardToFallback(ctx context.Context, connection internet.Connection, reader *recordReader) error {
+	records := reader.Stop()
+	if err := connection.SetReadDeadline(time.Time{}); err != nil {
+		buf.ReleaseMulti(records)
+		return newError("unable to set back read deadline").Base(err)
+	}
+
+	conn, err := internet.DialSystem(ctx, *h.fallback, nil)
+	if err != nil {
+		buf.ReleaseMulti(records)
+		return newError("failed to dial fallback ", h.fallback).Base(err)
+	}
+	defer conn.Close()
+
+	newError("forwarding invalid request to fallback ", h.fallback).AtDebug().WriteToLog(session.ExportIDToError(ctx))
+
+	sessionPolicy := h.policyManager.ForLevel(0)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
+
+	requestDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
+
+		writer := buf.NewWriter(conn)
+		if err := writer.WriteMultiBuffer(records); err != nil {
+			return newError("failed to write handshake to fallback").Base(err)
+		}
+		return buf.Copy(reader.Reader, writer, buf.UpdateActivity(timer))
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(sessionPolicy.Timeouts.UplinkOnly)
+
+		return buf.Copy(buf.NewReader(conn), buf.NewWriter(connection), buf.UpdateActivity(timer))
+	}
+
+	if err := task.Run(ctx, requestDone, responseDone); err != nil {
+		return newError("fallback connection ends").Base(err)
+	}
+	return nil
+}
+
+func fallbackDestination(config *FallbackConfig) *net.Destination {
+	if config == nil {
+		return nil
+	}
+	dest := net.TCPDestination(config.Address.AsAddress(), net.Port(config.Port))
+	return &dest
+}
@@ -18,8 +18,10 @@ import (
 	"v2ray.com/core/common/log"
 	"v2ray.com/core/common/net"
 	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/ratelimit"
 	"v2ray.com/core/common/session"
 	"v2ray.com/core/common/signal"
+	"v2ray.com/core/common/signal/semaphore"
 	"v2ray.com/core/common/task"
 	"v2ray.com/core/common/uuid"
 	feature_inbound "v2ray.com/core/features/inbound"
@@ -109,6 +111,9 @@ type Handler struct {
 	detours               *DetourConfig
 	sessionHistory        *encoding.SessionHistory
 	secure                bool
+	probeResistant        bool
+	drains                *semaphore.Instance
+	fallback              *net.Destination
 }
 
 // New creates a new VMess inbound handler.
@@ -122,6 +127,9 @@ func New(ctx context.Context, config *Config) (*Handler, error) {
 		usersByEmail:          newUserByEmail(config.GetDefaultValue()),
 		sessionHistory:        encoding.NewSessionHistory(),
 		secure:                config.SecureEncryptionOnly,
+		probeResistant:        config.ProbeResistant,
+		drains:                semaphore.New(maxConcurrentDrains),
+		fallback:              fallbackDestination(config.Fallback),
 	}
 
 	for _, user := range config.User {
@@ -177,7 +185,11 @@ func (h *Handler) RemoveUser(ctx context.Context, email string) error {
 	return nil
 }
 
-func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSession, request *protocol.RequestHeader, response *protocol.ResponseHeader, input buf.Reader, output *buf.BufferedWriter) error {
+func (h *Handler) GetUsers(ctx context.Context) []*protocol.MemoryUser {
+	return h.clients.Users()
+}
+
+func transferResponse(ctx context.Context, timer signal.ActivityUpdater, limiter buf.RateLimiter, session *encoding.ServerSession, request *protocol.RequestHeader, response *protocol.ResponseHeader, input buf.Reader, output *buf.BufferedWriter) error {
 	session.EncodeResponseHeader(response, output)
 
 	bodyWriter := session.EncodeResponseBody(request, output)
@@ -188,6 +200,11 @@ func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSess
 		if err != nil {
 			return err
 		}
+		if limiter != nil {
+			if err := ratelimit.Sleep(ctx, limiter.Reserve(int64(data.Len())), timer); err != nil {
+				return err
+			}
+		}
 
 		if err := bodyWriter.WriteMultiBuffer(data); err != nil {
 			return err
@@ -198,7 +215,7 @@ func transferResponse(timer signal.ActivityUpdater, session *encoding.ServerSess
 		return err
 	}
 
-	if err := buf.Copy(input, bodyWriter, buf.UpdateActivity(timer)); err != nil {
+	if err := buf.Copy(input, bodyWriter, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter, timer)); err != nil {
 		return err
 	}
 
@@ -222,7 +239,13 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		return newError("unable to set read deadline").Base(err).AtWarning()
 	}
 
-	reader := &buf.BufferedReader{Reader: buf.NewReader(connection)}
+	var recorder *recordReader
+	rawReader := buf.NewReader(connection)
+	if h.fallback != nil {
+		recorder = newRecordReader(rawReader)
+		rawReader = recorder
+	}
+	reader := &buf.BufferedReader{Reader: rawReader}
 	svrSession := encoding.NewServerSession(h.clients, h.sessionHistory)
 	request, err := svrSession.DecodeRequestHeader(reader)
 	if err != nil {
@@ -235,8 +258,24 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 			})
 			err = newError("invalid request from ", connection.RemoteAddr()).Base(err).AtInfo()
 		}
+		switch {
+		case errors.Cause(err) == io.EOF:
+			// The client has closed the connection, so there is nothing to forward or drain.
+			if recorder != nil {
+				buf.ReleaseMulti(recorder.Stop())
+			}
+		case recorder != nil:
+			if ferr := h.forwardToFallback(ctx, connection, recorder); ferr != nil {
+				newError("failed to forward invalid request to fallback").Base(ferr).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+			}
+		case h.probeResistant:
+			h.drain(connection, reader)
+		}
 		return err
 	}
+	if recorder != nil {
+		buf.ReleaseMulti(recorder.Stop())
+	}
 
 	if h.secure && isInsecureEncryption(request.Security) {
 		log.Record(&log.AccessMessage{
@@ -273,6 +312,12 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 
 	sessionPolicy = h.policyManager.ForLevel(request.User.Level)
 
+	limiter, err := policy.LimitSession(ctx, h.policyManager, request.User.Level)
+	if err != nil {
+		return newError("rejected request to ", request.Destination()).Base(err).AtInfo()
+	}
+	defer limiter.Close()
+
 	ctx, cancel := context.WithCancel(ctx)
 	timer := signal.CancelAfterInactivity(ctx, cancel, sessionPolicy.Timeouts.ConnectionIdle)
 
@@ -286,7 +331,7 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		defer timer.SetTimeout(sessionPolicy.Timeouts.DownlinkOnly)
 
 		bodyReader := svrSession.DecodeRequestBody(request, reader)
-		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+		if err := buf.Copy(bodyReader, link.Writer, buf.UpdateActivity(timer), buf.RateLimit(ctx, limiter.Uplink(), timer)); err != nil {
 			return newError("failed to transfer request").Base(err)
 		}
 		return nil
@@ -301,7 +346,7 @@ func (h *Handler) Process(ctx context.Context, network net.Network, connection i
 		response := &protocol.ResponseHeader{
 			Command: h.generateCommand(ctx, request),
 		}
-		return transferResponse(timer, svrSession, request, response, link.Reader, writer)
+		return transferResponse(ctx, timer, limiter.Downlink(), svrSession, request, response, link.Reader, writer)
 	}
 
 	var requestDonePost = task.OnSuccess(requestDone, task.Close(link.Writer))
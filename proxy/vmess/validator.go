@@ -11,6 +11,7 @@ import (
 	"v2ray.com/core/common/protocol"
 	"v2ray.com/core/common/serial"
 	"v2ray.com/core/common/task"
+	"v2ray.com/core/proxy/vmess/aead"
 )
 
 const (
@@ -31,6 +32,8 @@ type TimedUserValidator struct {
 	hasher   protocol.IDHash
 	baseTime protocol.Timestamp
 	task     *task.Periodic
+
+	aeadDecoderHolder *aead.AuthIDDecoderHolder
 }
 
 type indexTimePair struct {
@@ -45,6 +48,8 @@ func NewTimedUserValidator(hasher protocol.IDHash) *TimedUserValidator {
 		userHash: make(map[[16]byte]indexTimePair, 1024),
 		hasher:   hasher,
 		baseTime: protocol.Timestamp(time.Now().Unix() - cacheDurationSec*2),
+
+		aeadDecoderHolder: aead.NewAuthIDDecoderHolder(),
 	}
 	tuv.task = &task.Periodic{
 		Interval: updateInterval,
@@ -124,6 +129,9 @@ func (v *TimedUserValidator) Add(u *protocol.MemoryUser) error {
 	v.users = append(v.users, uu)
 	v.generateNewHashes(protocol.Timestamp(nowSec), uu)
 
+	account := uu.user.Account.(*MemoryAccount)
+	v.aeadDecoderHolder.AddUser(cmdKey(account), uu)
+
 	return nil
 }
 
@@ -137,11 +145,49 @@ func (v *TimedUserValidator) Get(userHash []byte) (*protocol.MemoryUser, protoco
 	if found {
 		var user protocol.MemoryUser
 		user = pair.user.user
+		if account, ok := user.Account.(*MemoryAccount); ok && account.Expired(time.Now()) {
+			newError("user ", user.Email, " has expired").AtInfo().WriteToLog()
+			return nil, 0, false
+		}
 		return &user, protocol.Timestamp(pair.timeInc) + v.baseTime, true
 	}
 	return nil, 0, false
 }
 
+// GetAEAD returns the user that the AEAD auth ID is created by. It returns an error if the auth ID is replayed or
+// created out of the time window, in which case the request must not be handled as a legacy one.
+func (v *TimedUserValidator) GetAEAD(authID []byte) (*protocol.MemoryUser, bool, error) {
+	var fixedSizeAuthID [16]byte
+	copy(fixedSizeAuthID[:], authID)
+
+	ticket, err := v.aeadDecoderHolder.Match(fixedSizeAuthID)
+	if err != nil {
+		if err == aead.ErrNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	user := ticket.(*user).user
+	if account, ok := user.Account.(*MemoryAccount); ok && account.Expired(time.Now()) {
+		return nil, false, newError("user ", user.Email, " has expired")
+	}
+	return &user, true, nil
+}
+
+// Users returns all users in the validator.
+func (v *TimedUserValidator) Users() []*protocol.MemoryUser {
+	v.RLock()
+	defer v.RUnlock()
+
+	users := make([]*protocol.MemoryUser, 0, len(v.users))
+	for _, u := range v.users {
+		user := u.user
+		users = append(users, &user)
+	}
+	return users
+}
+
 func (v *TimedUserValidator) Remove(email string) bool {
 	v.Lock()
 	defer v.Unlock()
@@ -159,6 +205,8 @@ func (v *TimedUserValidator) Remove(email string) bool {
 	}
 	ulen := len(v.users)
 
+	v.aeadDecoderHolder.RemoveUser(cmdKey(v.users[idx].user.Account.(*MemoryAccount)))
+
 	v.users[idx] = v.users[ulen-1]
 	v.users[ulen-1] = nil
 	v.users = v.users[:ulen-1]
@@ -166,6 +214,12 @@ func (v *TimedUserValidator) Remove(email string) bool {
 	return true
 }
 
+func cmdKey(account *MemoryAccount) [16]byte {
+	var key [16]byte
+	copy(key[:], account.ID.CmdKey())
+	return key
+}
+
 // Close implements common.Closable.
 func (v *TimedUserValidator) Close() error {
 	return v.task.Close()
@@ -108,3 +108,25 @@ func BenchmarkUserValidator(b *testing.B) {
 		common.Close(v)
 	}
 }
+
+func TestUserValidatorExpire(t *testing.T) {
+	hasher := protocol.DefaultIDHash
+	v := NewTimedUserValidator(hasher)
+	defer common.Close(v)
+
+	id := uuid.New()
+	common.Must(v.Add(&protocol.MemoryUser{
+		Email: "expired",
+		Account: toAccount(&Account{
+			Id:     id.String(),
+			Expire: time.Now().Add(-time.Hour).Unix(),
+		}),
+	}))
+
+	ts := protocol.Timestamp(time.Now().Unix())
+	idHash := hasher(id.Bytes())
+	common.Must2(serial.WriteUint64(idHash, uint64(ts)))
+	if _, _, found := v.Get(idHash.Sum(nil)); found {
+		t.Error("expect expired user not found")
+	}
+}
@@ -47,6 +47,20 @@ func (mr *DNSClientMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*DNSClient)(nil).Close))
 }
 
+// GlobalLookupIP mocks base method
+func (m *DNSClient) GlobalLookupIP(arg0 string) []net.IP {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GlobalLookupIP", arg0)
+	ret0, _ := ret[0].([]net.IP)
+	return ret0
+}
+
+// GlobalLookupIP indicates an expected call of GlobalLookupIP
+func (mr *DNSClientMockRecorder) GlobalLookupIP(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GlobalLookupIP", reflect.TypeOf((*DNSClient)(nil).GlobalLookupIP), arg0)
+}
+
 // LookupIP mocks base method
 func (m *DNSClient) LookupIP(arg0 string) ([]net.IP, error) {
 	m.ctrl.T.Helper()
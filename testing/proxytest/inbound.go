@@ -0,0 +1,79 @@
+// Package proxytest contains helpers for testing proxies.
+package proxytest
+
+import (
+	"context"
+
+	"v2ray.com/core"
+	"v2ray.com/core/app/policy"
+	"v2ray.com/core/common"
+	"v2ray.com/core/common/errors"
+	"v2ray.com/core/common/net"
+	"v2ray.com/core/common/protocol"
+	"v2ray.com/core/common/serial"
+	"v2ray.com/core/common/session"
+	"v2ray.com/core/features/routing"
+	"v2ray.com/core/proxy"
+	"v2ray.com/core/transport"
+)
+
+// userDispatcher records the user of the session that it dispatches, and fails the dispatch.
+type userDispatcher struct {
+	users chan *protocol.MemoryUser
+}
+
+func (*userDispatcher) Type() interface{} {
+	return routing.DispatcherType()
+}
+
+func (*userDispatcher) Start() error {
+	return nil
+}
+
+func (*userDispatcher) Close() error {
+	return nil
+}
+
+func (d *userDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	d.users <- session.InboundFromContext(ctx).User
+	return nil, errors.New("dispatch is not supported")
+}
+
+// NewInbound creates the inbound handler of the config, in an instance with default policies.
+func NewInbound(config interface{}) proxy.Inbound {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+	})
+	common.Must(err)
+	h, err := core.CreateObject(v, config)
+	common.Must(err)
+	return h.(proxy.Inbound)
+}
+
+// ProcessUser processes a TCP connection from inbound with the handler, while the client runs handshake on the other
+// end of the connection. It returns the user of the session when the handler dispatches it, or nil if the handler
+// rejects the client. The dispatch always fails, so that the handler returns after it.
+func ProcessUser(h proxy.Inbound, inbound *session.Inbound, handshake func(client net.Conn)) *protocol.MemoryUser {
+	client, server := net.Pipe()
+	defer client.Close()
+
+	ctx := session.ContextWithInbound(context.Background(), inbound)
+	dispatcher := &userDispatcher{users: make(chan *protocol.MemoryUser, 1)}
+	done := make(chan error, 1)
+	go func() {
+		done <- h.Process(ctx, net.Network_TCP, server, dispatcher)
+		server.Close()
+	}()
+
+	handshake(client)
+	<-done
+
+	select {
+	case user := <-dispatcher.users:
+		return user
+	default:
+		return nil
+	}
+}
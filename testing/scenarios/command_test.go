@@ -296,6 +296,18 @@ func TestCommanderAddRemoveUser(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	listResp, err := hsClient.ListUsers(context.Background(), &command.ListUsersRequest{Tag: "v"})
+	common.Must(err)
+	if len(listResp.User) != 2 || listResp.User[1].Email != "test@v2ray.com" {
+		t.Error("unexpected users: ", listResp.User)
+	}
+
+	getResp, err := hsClient.GetUser(context.Background(), &command.GetUserRequest{Tag: "v", Email: "test@v2ray.com"})
+	common.Must(err)
+	if getResp.User.Email != "test@v2ray.com" {
+		t.Error("unexpected user: ", getResp.User)
+	}
+
 	resp, err = hsClient.AlterInbound(context.Background(), &command.AlterInboundRequest{
 		Tag:       "v",
 		Operation: serial.ToTypedMessage(&command.RemoveUserOperation{Email: "test@v2ray.com"}),
@@ -304,6 +316,10 @@ func TestCommanderAddRemoveUser(t *testing.T) {
 	if resp == nil {
 		t.Fatal("nil response")
 	}
+
+	if _, err := hsClient.GetUser(context.Background(), &command.GetUserRequest{Tag: "v", Email: "test@v2ray.com"}); err == nil {
+		t.Error("expect error for removed user")
+	}
 }
 
 func TestCommanderStats(t *testing.T) {